@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+}
+
+var aliasCmd = &cobra.Command{
+	Use:     "alias",
+	Short:   "Manage component reference aliases",
+	GroupID: "component",
+	Long: `Define short names for component references. An alias can be used anywhere
+a reference is accepted — run, inspect, pull, policy, config and so on.
+Follow it with @ and a version, constraint or digest to override the
+aliased version: with claude aliased to catalyst:local.claude:0.1.0,
+claude@0.2.0 means catalyst:local.claude:0.2.0.
+
+Aliases are stored under "aliases" in ~/.cyfr/config.json and take
+precedence over bare component names.`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> [type] <reference>",
+	Short: "Define an alias",
+	Long:  "Create or replace an alias for a component reference. The reference is stored in canonical form.",
+	Example: `  cyfr alias set claude c:local.claude:0.1.0
+  cyfr alias set sentiment catalyst:acme.sentiment:^1.2`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := ref.ValidateAlias(name); err != nil {
+			return output.Errorf("%v", err)
+		}
+		normalized, err := normalizeComponentRef(joinTypeShorthand(args[1:])[0])
+		if err != nil {
+			return err
+		}
+		r, err := parseComponentRef(normalized)
+		if err != nil {
+			return err
+		}
+		target := r.String()
+
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		if cfg.Aliases == nil {
+			cfg.Aliases = map[string]string{}
+		}
+		cfg.Aliases[name] = target
+		if err := cfg.Save(); err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+
+		if flagJSON {
+			output.JSON(map[string]any{"alias": name, "reference": target})
+		} else {
+			fmt.Printf("Alias '%s' set to %s\n", name, target)
+		}
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "Show all aliases",
+	Long:    "List every alias and the reference it stands for.",
+	Example: "  cyfr alias list",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		if flagJSON {
+			aliases := cfg.Aliases
+			if aliases == nil {
+				aliases = map[string]string{}
+			}
+			output.JSON(map[string]any{"aliases": aliases, "count": len(aliases)})
+			return nil
+		}
+		if len(cfg.Aliases) == 0 {
+			fmt.Println("No aliases defined. Add one with 'cyfr alias set'.")
+			return nil
+		}
+		names := make([]string, 0, len(cfg.Aliases))
+		for name := range cfg.Aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-15s %s\n", name, cfg.Aliases[name])
+		}
+		return nil
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Delete an alias",
+	Long:    "Remove an alias from the config.",
+	Example: "  cyfr alias remove claude",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		if _, ok := cfg.Aliases[name]; !ok {
+			return output.NewError(output.CodeNotFound, "Alias '%s' not found.", name)
+		}
+		delete(cfg.Aliases, name)
+		if err := cfg.Save(); err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"alias": name, "removed": true})
+		} else {
+			fmt.Printf("Alias '%s' removed.\n", name)
+		}
+		return nil
+	},
+}
+
+// expandAlias replaces an alias defined in the config with the reference
+// it stands for. Anything else is returned unchanged.
+func expandAlias(s string) string {
+	cfg, err := config.Load()
+	if err != nil || len(cfg.Aliases) == 0 {
+		return s
+	}
+	expanded, _ := ref.ExpandAlias(s, cfg.Aliases)
+	return expanded
+}
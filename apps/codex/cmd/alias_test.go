@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+func TestExpandAlias_FromConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultForLocal()
+	cfg.Aliases = map[string]string{"claude": "catalyst:local.claude:0.1.0"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"claude", "catalyst:local.claude:0.1.0"},
+		{"claude@0.2.0", "catalyst:local.claude:0.2.0"},
+		{"local.claude@0.2.0", "local.claude:0.2.0"},
+	}
+	for _, tt := range tests {
+		if got, _ := normalizeComponentRef(tt.input); got != tt.want {
+			t.Errorf("normalizeComponentRef(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	refMap, _ := parseReference("claude", "reagent")
+	if refMap["registry"] != "catalyst:local.claude:0.1.0" {
+		t.Errorf("parseReference(alias) = %v", refMap)
+	}
+}
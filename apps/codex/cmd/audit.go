@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -11,6 +12,10 @@ func init() {
 	auditCmd.AddCommand(auditExportCmd)
 
 	auditExportCmd.Flags().String("format", "json", "Export format: json, csv")
+	auditListCmd.Flags().Bool("follow", false, "Keep streaming new audit events after listing")
+	addPaginationFlags(auditListCmd, 100)
+	addColumnsFlag(auditListCmd)
+	addWatchFlag(auditListCmd)
 }
 
 var auditCmd = &cobra.Command{
@@ -23,22 +28,47 @@ var auditCmd = &cobra.Command{
 var auditListCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List audit events",
-	Long:    "Display recent audit events in reverse chronological order.",
+	Long:    "Display recent audit events in reverse chronological order. At most --limit events are fetched (default 100); use --all to page through everything. With --follow, keep the connection open and print new events as the server streams them, until interrupted. With --watch, list the latest events again every interval instead.",
 	Example: `  cyfr audit list
-  cyfr audit list --json`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("audit", map[string]any{
-			"action": "list",
-		})
+  cyfr audit list --limit 500
+  cyfr audit list --all --json
+  cyfr audit list --follow
+  cyfr audit list --limit 20 --watch=5s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit := pageLimit(cmd)
+		toolArgs := typed.AuditArgs{Action: typed.AuditActionList}
+		if limit > 0 {
+			toolArgs.Filters = map[string]any{"limit": limit}
+		}
+
+		follow, _ := cmd.Flags().GetBool("follow")
+		interval, _ := cmd.Flags().GetDuration("watch")
+		if follow && interval > 0 {
+			return output.NewError(output.CodeInvalidArgument, "--follow and --watch can't be used together")
+		}
+		if !follow && interval <= 0 {
+			pageOutput()
+		}
+
+		client, err := newClient()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		err = watch(cmd, func() error {
+			result, err := client.CallToolPagedCtx(cmd.Context(), "audit", toolArgs.Map(), "events", limit)
+			if err != nil {
+				return output.Errorf("Failed: %v", err)
+			}
+			if err := printList(cmd, result, "events"); err != nil {
+				return err
+			}
+			warnMoreResults(result)
+			return nil
+		})
+		if err != nil || !follow {
+			return err
 		}
+		return followNotifications(cmd.Context(), client)
 	},
 }
 
@@ -48,21 +78,27 @@ var auditExportCmd = &cobra.Command{
 	Long:  "Export all audit events in the specified format for external processing.",
 	Example: `  cyfr audit export
   cyfr audit export --format csv`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		format, _ := cmd.Flags().GetString("format")
 
-		client := newClient()
-		result, err := client.CallTool("audit", map[string]any{
-			"action": "export",
-			"format": format,
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		done := showProgress(client.Client, "Exporting")
+		result, err := client.Audit(cmd.Context(), typed.AuditArgs{
+			Action: typed.AuditActionExport,
+			Format: format,
 		})
+		done()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
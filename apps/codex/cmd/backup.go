@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	backupCmd.Flags().Bool("include-secrets", false, "Also back up .env, with the key the server's secrets are encrypted with, and secret files under data/")
+	restoreCmd.Flags().Bool("force", false, "Restore over a project that already has data, replacing it")
+}
+
+// backupManifest is the first entry of a backup archive, describing it.
+type backupManifest struct {
+	Version        int       `json:"version"`
+	Created        time.Time `json:"created"`
+	CLIVersion     string    `json:"cli_version"`
+	Project        string    `json:"project,omitempty"`
+	IncludeSecrets bool      `json:"include_secrets"`
+}
+
+// backupManifestName is the name of the manifest in a backup archive.
+const backupManifestName = "cyfr-backup.json"
+
+// backupPaths are the project's files and directories a backup holds, if
+// they exist.
+var backupPaths = []string{"cyfr.yaml", "docker-compose.yml", composeOverrideFile, ".env", "data", "components"}
+
+// isSecretPath reports whether path, relative to the project, is left out
+// of backups without --include-secrets: .env, and anything under data/
+// with secret in its name.
+func isSecretPath(path string) bool {
+	path = filepath.ToSlash(path)
+	if path == ".env" {
+		return true
+	}
+	return strings.HasPrefix(path, "data/") && strings.Contains(strings.ToLower(path), "secret")
+}
+
+var backupCmd = &cobra.Command{
+	Use:     "backup [file]",
+	Short:   "Back up the local server's data and components",
+	GroupID: "start",
+	Long: `Write the local server's state — data/ with its SQLite database and audit log, components/, cyfr.yaml and the compose files — to a gzipped tar archive, cyfr-backup-<timestamp>.tar.gz unless a file is given. A running server is stopped while its files are copied and started again afterwards, so the database is consistent.
+
+The server's secrets are encrypted with CYFR_SECRET_KEY_BASE from .env, which is only backed up with --include-secrets, along with files under data/ with secret in their name. Without it, the secrets in a restored project can't be read and have to be set again. Keep backups with secrets as safe as the secrets themselves.
+
+Use 'cyfr restore' to restore a backup into a new project, e.g. on another machine.`,
+	Example: `  cyfr backup
+  cyfr backup --include-secrets ~/backups/cyfr.tar.gz`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat("data"); err != nil {
+			return output.Errorf("No data/ to back up: run this in a CYFR project directory.")
+		}
+		file := "cyfr-backup-" + time.Now().Format("20060102-150405") + ".tar.gz"
+		if len(args) > 0 {
+			file = args[0]
+		}
+		secrets, _ := cmd.Flags().GetBool("include-secrets")
+
+		resume, err := quiesceServer(cmd)
+		if err != nil {
+			return err
+		}
+		n, size, err := writeBackup(file, secrets)
+		if resumeErr := resume(); err == nil && resumeErr != nil {
+			return resumeErr
+		}
+		if err != nil {
+			return output.Errorf("Failed to back up: %v", err)
+		}
+		fmt.Printf("Backed up %d files to %s (%s).\n", n, file, output.HumanBytes(size))
+		if !secrets {
+			fmt.Println("Secrets can't be read from this backup; use --include-secrets to keep them.")
+		}
+		return nil
+	},
+}
+
+// quiesceServer stops the project's server if it is running, for its files
+// to be copied, and returns a function starting it again.
+func quiesceServer(cmd *cobra.Command) (resume func() error, err error) {
+	if pid, ok := nativePID(); ok {
+		fmt.Println("Stopping the server while backing up...")
+		if err := stopNative(pid); err != nil {
+			return nil, output.Errorf("Failed to stop the server (PID %d): %v", pid, err)
+		}
+		return func() error {
+			fmt.Println("Starting the server again...")
+			return startNative(projectPort())
+		}, nil
+	}
+	runtime, err := projectRuntime()
+	if err != nil || !runtime.running() {
+		return func() error { return nil }, nil
+	}
+	fmt.Println("Stopping the server while backing up...")
+	stop := runtime.compose(cmd.Context(), "stop")
+	stop.Stderr = os.Stderr
+	if err := stop.Run(); err != nil {
+		return nil, output.Errorf("Failed to stop the server: %v", err)
+	}
+	return func() error {
+		fmt.Println("Starting the server again...")
+		start := runtime.compose(cmd.Context(), "start")
+		start.Stderr = os.Stderr
+		if err := start.Run(); err != nil {
+			return output.Errorf("Failed to start the server again: %v", err)
+		}
+		return nil
+	}, nil
+}
+
+// writeBackup writes the backup archive to file, which only appears once
+// it is complete, and returns the number of files in it and its size.
+func writeBackup(file string, secrets bool) (int, int64, error) {
+	f, err := os.CreateTemp(filepath.Dir(file), ".cyfr-backup-*")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(f.Name())
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	manifest, _ := json.MarshalIndent(backupManifest{
+		Version:        1,
+		Created:        time.Now().UTC(),
+		CLIVersion:     Version,
+		Project:        cyfrYAMLValue("name"),
+		IncludeSecrets: secrets,
+	}, "", "  ")
+	if err := tw.WriteHeader(&tar.Header{Name: backupManifestName, Mode: 0644, Size: int64(len(manifest)), ModTime: time.Now()}); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+
+	n := 0
+	// The archive itself may be written under data/.
+	absFile, _ := filepath.Abs(file)
+	absTemp, _ := filepath.Abs(f.Name())
+	for _, root := range backupPaths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) && path == root {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !secrets && isSecretPath(path) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if abs, _ := filepath.Abs(path); abs == absFile || abs == absTemp {
+				return nil
+			}
+			if !d.IsDir() && !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(path)
+			if d.IsDir() {
+				hdr.Name += "/"
+				return tw.WriteHeader(hdr)
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			src, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+			if _, err := io.Copy(tw, src); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			n++
+			return nil
+		})
+		if err != nil {
+			f.Close()
+			return 0, 0, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	if err := gw.Close(); err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, 0, err
+	}
+	// Backups with secrets are as sensitive as .env.
+	mode := os.FileMode(0644)
+	if secrets {
+		mode = 0600
+	}
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		return 0, 0, err
+	}
+	return n, info.Size(), os.Rename(f.Name(), file)
+}
+
+var restoreCmd = &cobra.Command{
+	Use:     "restore <file>",
+	Short:   "Restore a backup into this project",
+	GroupID: "start",
+	Long: `Restore a backup made with 'cyfr backup' into the current directory: its data/ and components/, and its cyfr.yaml, docker-compose.yml and docker-compose.override.yml unless the project has its own. A .env in the backup replaces the project's, since the restored secrets are encrypted with its key; the replaced one is kept as .env.before-restore.
+
+The server has to be stopped, and the project must not have any data yet unless --force is given, which replaces it.`,
+	Example: `  mkdir cyfr && cd cyfr
+  cyfr restore ~/backups/cyfr-backup-20260102-150405.tar.gz
+  cyfr up`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := nativePID(); ok || composeRunning() {
+			return output.Errorf("The server is running. Stop it with 'cyfr down' before restoring.")
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		if entries, _ := os.ReadDir("data"); len(entries) > 0 && !force {
+			return output.Errorf("This project already has data in data/. Restore into a new project, or pass --force to replace it.")
+		}
+		manifest, n, err := restoreBackup(args[0], force)
+		if err != nil {
+			return output.Errorf("Failed to restore %s: %v", args[0], err)
+		}
+		fmt.Printf("Restored %d files from the backup of %s", n, manifest.Created.Local().Format("2006-01-02 15:04"))
+		if manifest.Project != "" {
+			fmt.Printf(" (project %s)", manifest.Project)
+		}
+		fmt.Println(".")
+		if !manifest.IncludeSecrets {
+			fmt.Println("The backup has no secrets; set them again with 'cyfr secret set'.")
+		}
+		fmt.Println("Run 'cyfr up' to start the server.")
+		return nil
+	},
+}
+
+// restoreBackup extracts the backup archive file into the current
+// directory, with force replacing data/ first.
+func restoreBackup(file string, force bool) (backupManifest, int, error) {
+	var manifest backupManifest
+	f, err := os.Open(file)
+	if err != nil {
+		return manifest, 0, err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, 0, fmt.Errorf("not a backup: %w", err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != backupManifestName {
+		return manifest, 0, fmt.Errorf("not a backup made with 'cyfr backup'")
+	}
+	if err := json.NewDecoder(io.LimitReader(tr, 1<<20)).Decode(&manifest); err != nil {
+		return manifest, 0, fmt.Errorf("read %s: %w", backupManifestName, err)
+	}
+	if manifest.Version != 1 {
+		return manifest, 0, fmt.Errorf("backup format %d is newer than this cyfr supports; upgrade cyfr", manifest.Version)
+	}
+
+	if force {
+		if err := os.RemoveAll("data"); err != nil {
+			return manifest, 0, err
+		}
+	}
+	n := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return manifest, n, nil
+		}
+		if err != nil {
+			return manifest, n, err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if !filepath.IsLocal(name) || !restorable(name) {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(name, 0755); err != nil {
+				return manifest, n, err
+			}
+		case tar.TypeReg:
+			switch name {
+			case "cyfr.yaml", "docker-compose.yml", composeOverrideFile:
+				if _, err := os.Stat(name); err == nil {
+					continue
+				}
+			case ".env":
+				if _, err := os.Stat(name); err == nil {
+					if err := os.Rename(name, ".env.before-restore"); err != nil {
+						return manifest, n, err
+					}
+					fmt.Println("Replaced .env with the backup's; the previous one is .env.before-restore.")
+				}
+			}
+			if err := restoreFile(name, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return manifest, n, err
+			}
+			n++
+		}
+	}
+}
+
+// restorable reports whether path, from a backup archive, is one of
+// backupPaths or under one.
+func restorable(path string) bool {
+	top, _, _ := strings.Cut(filepath.ToSlash(path), "/")
+	for _, p := range backupPaths {
+		if top == p {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreFile writes the contents of r to path with mode.
+func restoreFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return f.Close()
+}
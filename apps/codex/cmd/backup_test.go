@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupRestore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	src := chdirTemp(t)
+	files := map[string]string{
+		"cyfr.yaml":                      "name: demo\nport: 4000\n",
+		".env":                           "CYFR_SECRET_KEY_BASE=abc\n",
+		"data/cyfr.db":                   "sqlite",
+		"data/secrets/key":               "secret",
+		"components/catalysts/local/x/a": "wasm",
+	}
+	for name, content := range files {
+		os.MkdirAll(filepath.Dir(name), 0755)
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain := filepath.Join(t.TempDir(), "plain.tar.gz")
+	if n, _, err := writeBackup(plain, false); err != nil || n != 3 {
+		t.Fatalf("writeBackup without secrets = %d files, %v; want 3", n, err)
+	}
+	full := filepath.Join(src, "data", "full.tar.gz")
+	if n, _, err := writeBackup(full, true); err != nil || n != 5 {
+		t.Fatalf("writeBackup with secrets = %d files, %v; want 5", n, err)
+	}
+	if info, err := os.Stat(full); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("backup with secrets has mode %v, %v; want 0600", info.Mode(), err)
+	}
+
+	// Into a new project without secrets: its own cyfr.yaml and .env stay.
+	chdirTemp(t)
+	os.WriteFile("cyfr.yaml", []byte("name: new\n"), 0644)
+	os.WriteFile(".env", []byte("CYFR_SECRET_KEY_BASE=new\n"), 0600)
+	manifest, n, err := restoreBackup(plain, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Project != "demo" || manifest.IncludeSecrets || n != 2 {
+		t.Errorf("restored %d files, manifest %+v", n, manifest)
+	}
+	for name, want := range map[string]string{"cyfr.yaml": "name: new\n", ".env": "CYFR_SECRET_KEY_BASE=new\n", "data/cyfr.db": "sqlite"} {
+		if got, _ := os.ReadFile(name); string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := os.Stat("data/secrets/key"); err == nil {
+		t.Error("secret file restored from a backup without secrets")
+	}
+
+	// With secrets and --force: .env is replaced, the old one kept.
+	if _, _, err := restoreBackup(full, true); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(".env"); string(got) != files[".env"] {
+		t.Errorf(".env = %q", got)
+	}
+	if got, _ := os.ReadFile(".env.before-restore"); string(got) != "CYFR_SECRET_KEY_BASE=new\n" {
+		t.Errorf(".env.before-restore = %q", got)
+	}
+	if got, _ := os.ReadFile("data/secrets/key"); string(got) != "secret" {
+		t.Errorf("data/secrets/key = %q", got)
+	}
+	if _, err := os.Stat("data/full.tar.gz"); err == nil {
+		t.Error("the backup archived itself")
+	}
+}
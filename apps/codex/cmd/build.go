@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	buildCmd.Flags().BoolP("watch", "w", false, "Rebuild whenever a source file changes, until interrupted")
+	rootCmd.AddCommand(buildCmd)
+}
+
+var buildCmd = &cobra.Command{
+	Use:     "build [directory]...",
+	Short:   "Build components with their language's toolchain",
+	GroupID: "component",
+	Long: `Compile components to WebAssembly with the toolchain of their language, writing each to <type>.wasm in its directory, where 'cyfr register' and 'cyfr run' find it. Without a directory, the component in the current directory is built.
+
+The language and source directory come from the build section of the component's cyfr-manifest.json, which 'cyfr new component' fills in:
+
+  "build": { "language": "rust", "source": "src" }
+
+Without one, the language is detected from the source (Cargo.toml, go.mod, package.json or Python files) and the source directory is src/ if there is one. The toolchains are cargo component (rust), tinygo (go), componentize-py (python) and jco (js); entry sets the Python module (default app) or JavaScript file (default index.js) to build. The WIT world is taken from the source's wit/, the component's, or the project's wit/<type>/. A component without a known language is built with its build.sh.
+
+With --watch, the components are rebuilt whenever their source changes.`,
+	Example: `  cyfr build
+  cyfr build components/catalysts/local/feeds/0.1.0
+  cyfr build --watch
+  cyfr build && cyfr run c:local.feeds:0.1.0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dirs := args
+		if len(dirs) == 0 {
+			dirs = []string{"."}
+		}
+		builds := make([]*componentBuild, len(dirs))
+		for i, dir := range dirs {
+			if strings.HasSuffix(dir, ".wasm") || filepath.Base(dir) == componentManifestFile {
+				dir = filepath.Dir(dir)
+			}
+			b, err := loadComponentBuild(dir)
+			if err != nil {
+				return err
+			}
+			builds[i] = b
+		}
+
+		rebuild, _ := cmd.Flags().GetBool("watch")
+		for _, b := range builds {
+			if err := b.build(cmd.Context()); err != nil {
+				if !rebuild {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+		if rebuild {
+			return watchBuilds(cmd.Context(), builds)
+		}
+		return nil
+	},
+}
+
+// componentBuildSettings is the build section of a cyfr-manifest.json.
+type componentBuildSettings struct {
+	Language string `json:"language,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Entry    string `json:"entry,omitempty"`
+}
+
+// componentBuild is how a component is built: with the toolchain of its
+// language from its source directory, into its .wasm file. Paths are
+// absolute.
+type componentBuild struct {
+	Ref      string // the component's reference, or its directory outside the components/ layout
+	Dir      string
+	Type     string
+	Language string // one of scaffold.Languages, or "" to run build.sh
+	Source   string
+	Entry    string
+	WIT      string
+}
+
+// loadComponentBuild reads how to build the component in dir from its
+// manifest and, where that says nothing, its source.
+func loadComponentBuild(dir string) (*componentBuild, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(abs, componentManifestFile))
+	if os.IsNotExist(err) {
+		return nil, output.NewError(output.CodeNotFound, "%s isn't a component directory: it has no %s", dir, componentManifestFile)
+	}
+	if err != nil {
+		return nil, output.Errorf("Failed to read the manifest: %v", err)
+	}
+	var manifest struct {
+		Type  string                 `json:"type"`
+		Build componentBuildSettings `json:"build"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, output.NewError(output.CodeInvalidArgument, "Invalid %s in %s: %v", componentManifestFile, dir, err)
+	}
+	if !slices.Contains(componentTypes, manifest.Type) {
+		return nil, output.NewError(output.CodeInvalidArgument, "%s in %s has no valid type (catalyst, reagent or formula)", componentManifestFile, dir)
+	}
+
+	b := &componentBuild{Ref: dir, Dir: abs, Type: manifest.Type, Language: manifest.Build.Language, Entry: manifest.Build.Entry}
+	if r, err := ref.FromPath(abs); err == nil {
+		b.Ref = r.String()
+	}
+	switch {
+	case manifest.Build.Source != "":
+		b.Source = filepath.Join(abs, filepath.FromSlash(manifest.Build.Source))
+	case isDir(filepath.Join(abs, "src")):
+		b.Source = filepath.Join(abs, "src")
+	default:
+		b.Source = abs
+	}
+	if b.Language == "" {
+		b.Language = detectLanguage(b.Source)
+	} else if _, ok := toolchains[b.Language]; !ok {
+		return nil, output.NewError(output.CodeInvalidArgument, "Unknown build language %q in %s (use rust, go, python or js)", b.Language, componentManifestFile)
+	}
+	if b.Entry == "" {
+		b.Entry = toolchains[b.Language].entry
+	}
+	b.WIT = findWIT(b)
+	return b, nil
+}
+
+// isDir reports whether path is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// detectLanguage returns the language of the source in dir, or "" if it
+// can't tell.
+func detectLanguage(dir string) string {
+	for _, marker := range []struct{ file, lang string }{
+		{"Cargo.toml", "rust"},
+		{"go.mod", "go"},
+		{"package.json", "js"},
+		{"pyproject.toml", "python"},
+	} {
+		if _, err := os.Stat(filepath.Join(dir, marker.file)); err == nil {
+			return marker.lang
+		}
+	}
+	if py, _ := filepath.Glob(filepath.Join(dir, "*.py")); len(py) > 0 {
+		return "python"
+	}
+	return ""
+}
+
+// findWIT returns the WIT directory of the component's world: the source's
+// wit/, the component's, or the project's wit/<type>/ above it.
+func findWIT(b *componentBuild) string {
+	candidates := []string{filepath.Join(b.Source, "wit"), filepath.Join(b.Dir, "wit")}
+	for dir := b.Dir; ; {
+		candidates = append(candidates, filepath.Join(dir, "wit", b.Type))
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	for _, c := range candidates {
+		if isDir(c) {
+			return c
+		}
+	}
+	return ""
+}
+
+// wasm returns the component's .wasm file.
+func (b *componentBuild) wasm() string {
+	return filepath.Join(b.Dir, b.Type+".wasm")
+}
+
+// A toolchain compiles a component's source in one language.
+type toolchain struct {
+	bin     string // the program that has to be installed
+	install string // how to install it
+	entry   string // the default entry point, if the toolchain takes one
+	args    func(b *componentBuild) []string
+	// built returns the file the toolchain wrote, if it can't be told to
+	// write b.wasm() itself.
+	built func(b *componentBuild) (string, error)
+}
+
+// toolchains are the toolchains by language.
+var toolchains = map[string]toolchain{
+	"rust": {
+		bin:     "cargo-component",
+		install: "cargo install cargo-component",
+		args: func(b *componentBuild) []string {
+			return []string{"cargo", "component", "build", "--release"}
+		},
+		built: newestRustOutput,
+	},
+	"go": {
+		bin:     "tinygo",
+		install: "see https://tinygo.org/getting-started/install/",
+		args: func(b *componentBuild) []string {
+			return []string{"tinygo", "build", "-target=wasip2", "--wit-package", b.WIT, "--wit-world", b.Type, "-o", b.wasm(), "."}
+		},
+	},
+	"python": {
+		bin:     "componentize-py",
+		install: "pip install componentize-py",
+		entry:   "app",
+		args: func(b *componentBuild) []string {
+			return []string{"componentize-py", "-d", b.WIT, "-w", b.Type, "componentize", b.Entry, "-o", b.wasm()}
+		},
+	},
+	"js": {
+		bin:     "jco",
+		install: "npm install -g @bytecodealliance/jco @bytecodealliance/componentize-js",
+		entry:   "index.js",
+		args: func(b *componentBuild) []string {
+			return []string{"jco", "componentize", b.Entry, "--wit", b.WIT, "--world-name", b.Type, "--out", b.wasm()}
+		},
+	},
+}
+
+// newestRustOutput returns the .wasm cargo component built last for the
+// component.
+func newestRustOutput(b *componentBuild) (string, error) {
+	var newest string
+	var newestTime time.Time
+	for _, target := range []string{"wasm32-wasip1", "wasm32-wasi", "wasm32-wasip2"} {
+		paths, _ := filepath.Glob(filepath.Join(b.Source, "target", target, "release", "*.wasm"))
+		for _, p := range paths {
+			if info, err := os.Stat(p); err == nil && info.ModTime().After(newestTime) {
+				newest, newestTime = p, info.ModTime()
+			}
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("cargo component built no .wasm under %s", filepath.Join(b.Source, "target"))
+	}
+	return newest, nil
+}
+
+// build compiles the component, with the toolchain's output going to
+// stderr so that --json stays parseable, and reports the result.
+func (b *componentBuild) build(ctx context.Context) error {
+	start := time.Now()
+	if err := b.compile(ctx); err != nil {
+		return err
+	}
+	info, err := os.Stat(b.wasm())
+	if err != nil {
+		return output.Errorf("Build of %s wrote no %s", b.Ref, filepath.Base(b.wasm()))
+	}
+	if flagJSON {
+		output.JSON(map[string]any{
+			"status":      "built",
+			"reference":   b.Ref,
+			"language":    b.Language,
+			"path":        b.wasm(),
+			"size":        info.Size(),
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
+	} else {
+		fmt.Printf("Built %s (%s, %s) in %s\n", b.Ref, output.HumanBytes(info.Size()), b.Language, time.Since(start).Round(time.Millisecond))
+	}
+	return nil
+}
+
+// compile runs the toolchain, or build.sh for a component without a known
+// language.
+func (b *componentBuild) compile(ctx context.Context) error {
+	if b.Language == "" {
+		if _, err := os.Stat(filepath.Join(b.Dir, "build.sh")); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Can't tell how to build %s: set build.language in its %s (rust, go, python or js)", b.Ref, componentManifestFile)
+		}
+		return b.run(exec.CommandContext(ctx, "sh", "build.sh"), b.Dir)
+	}
+	tc := toolchains[b.Language]
+	if _, err := exec.LookPath(tc.bin); err != nil {
+		return output.Errorf("Building %s code needs %s, which isn't installed: %s", b.Language, tc.bin, tc.install)
+	}
+	if b.WIT == "" && tc.built == nil {
+		return output.Errorf("Can't build %s: no WIT definitions for the %s world in its source, its directory or the project's wit/%s/", b.Ref, b.Type, b.Type)
+	}
+	args := tc.args(b)
+	if err := b.run(exec.CommandContext(ctx, args[0], args[1:]...), b.Source); err != nil {
+		return err
+	}
+	if tc.built == nil {
+		return nil
+	}
+	built, err := tc.built(b)
+	if err != nil {
+		return output.Errorf("Build of %s failed: %v", b.Ref, err)
+	}
+	return copyFile(built, b.wasm())
+}
+
+// run runs a build command in dir.
+func (b *componentBuild) run(c *exec.Cmd, dir string) error {
+	c.Dir = dir
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return output.Errorf("Build of %s failed: %v", b.Ref, err)
+	}
+	return nil
+}
+
+// copyFile copies the file src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// sourceSnapshot returns the modification times and sizes of the files of
+// b's source, leaving out build output and dependencies.
+func (b *componentBuild) sourceSnapshot() string {
+	var entries []string
+	filepath.WalkDir(b.Source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case "target", "node_modules", "__pycache__", ".git":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".wasm") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			entries = append(entries, fmt.Sprintf("%s %d %d", path, info.ModTime().UnixNano(), info.Size()))
+		}
+		return nil
+	})
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// watchBuilds rebuilds each component whenever its source changes, until
+// ctx ends. Failed builds are reported and wait for the next change.
+func watchBuilds(ctx context.Context, builds []*componentBuild) error {
+	snapshots := make([]string, len(builds))
+	for i, b := range builds {
+		snapshots[i] = b.sourceSnapshot()
+	}
+	fmt.Fprintln(os.Stderr, "Watching for changes. Press Ctrl-C to stop.")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(500 * time.Millisecond):
+		}
+		for i, b := range builds {
+			snapshot := b.sourceSnapshot()
+			if snapshot == snapshots[i] {
+				continue
+			}
+			snapshots[i] = snapshot
+			if err := b.build(ctx); err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+	}
+}
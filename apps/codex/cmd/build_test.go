@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeToolchain puts programs on PATH that run script with sh.
+func fakeToolchain(t *testing.T, scripts map[string]string) {
+	t.Helper()
+	bin := t.TempDir()
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// writeComponent creates a component with manifest in the components/
+// layout and returns its directory.
+func writeComponent(t *testing.T, compType, name, manifest string, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join("components", compType+"s", "local", name, "0.1.0")
+	files[componentManifestFile] = manifest
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBuildGo(t *testing.T) {
+	chdirTemp(t)
+	// A tinygo that writes its arguments to the file after -o.
+	fakeToolchain(t, map[string]string{"tinygo": `args="$*"
+while [ $# -gt 0 ]; do
+	if [ "$1" = -o ]; then echo "$args" > "$2"; fi
+	shift
+done
+`})
+	os.MkdirAll("wit/catalyst", 0755)
+	dir := writeComponent(t, "catalyst", "feeds", `{"type": "catalyst", "build": {"language": "go"}}`, map[string]string{"main.go": "package main\n"})
+
+	buildCmd.SetContext(context.Background())
+	out, err := captureStdout(func() {
+		if err := buildCmd.RunE(buildCmd, []string{dir}); err != nil {
+			t.Error(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Built catalyst:local.feeds:0.1.0") {
+		t.Errorf("output = %q", out)
+	}
+	args, err := os.ReadFile(filepath.Join(dir, "catalyst.wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wit, _ := filepath.Abs("wit/catalyst")
+	if want := "build -target=wasip2 --wit-package " + wit + " --wit-world catalyst"; !strings.Contains(string(args), want) {
+		t.Errorf("tinygo %s, want %q", args, want)
+	}
+}
+
+func TestBuildRustDetected(t *testing.T) {
+	chdirTemp(t)
+	fakeToolchain(t, map[string]string{
+		"cargo-component": "",
+		"cargo":           "mkdir -p target/wasm32-wasip1/release && echo \"$*\" > target/wasm32-wasip1/release/calc.wasm\n",
+	})
+	dir := writeComponent(t, "reagent", "calc", `{"type": "reagent"}`, map[string]string{"src/Cargo.toml": "[package]\n"})
+
+	b, err := loadComponentBuild(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Language != "rust" || filepath.Base(b.Source) != "src" {
+		t.Errorf("build = %+v", b)
+	}
+	if _, err := captureStdout(func() {
+		if err := b.build(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "reagent.wasm")); string(data) != "component build --release\n" {
+		t.Errorf("reagent.wasm = %q", data)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("PATH", t.TempDir())
+	if _, err := loadComponentBuild("."); err == nil || !strings.Contains(err.Error(), "no "+componentManifestFile) {
+		t.Errorf("no manifest: %v", err)
+	}
+	dir := writeComponent(t, "formula", "f", `{"type": "formula", "build": {"language": "cobol"}}`, map[string]string{})
+	if _, err := loadComponentBuild(dir); err == nil || !strings.Contains(err.Error(), "cobol") {
+		t.Errorf("unknown language: %v", err)
+	}
+	dir = writeComponent(t, "formula", "g", `{"type": "formula", "build": {"language": "python"}}`, map[string]string{})
+	b, err := loadComponentBuild(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.compile(context.Background()); err == nil || !strings.Contains(err.Error(), "pip install componentize-py") {
+		t.Errorf("missing toolchain: %v", err)
+	}
+}
+
+func TestSourceSnapshot(t *testing.T) {
+	chdirTemp(t)
+	dir := writeComponent(t, "reagent", "r", `{"type": "reagent"}`, map[string]string{"app.py": "x = 1\n"})
+	b, err := loadComponentBuild(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := b.sourceSnapshot()
+	os.WriteFile(filepath.Join(dir, "reagent.wasm"), []byte("built"), 0644)
+	os.MkdirAll(filepath.Join(dir, "__pycache__"), 0755)
+	os.WriteFile(filepath.Join(dir, "__pycache__", "app.pyc"), []byte("cache"), 0644)
+	if b.sourceSnapshot() != before {
+		t.Error("build output changed the snapshot")
+	}
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 22\n"), 0644)
+	if b.sourceSnapshot() == before {
+		t.Error("a source change didn't change the snapshot")
+	}
+}
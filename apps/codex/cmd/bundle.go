@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/cosign"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	componentExportCmd.Flags().StringP("file", "f", "", "Write the bundle to this file (default cyfr-components-<timestamp>.tar.gz)")
+	componentCmd.AddCommand(componentExportCmd)
+	componentImportCmd.Flags().Bool("force", false, "Replace versions already in components/")
+	componentImportCmd.Flags().Bool("no-register", false, "Only extract the components, without registering them or setting their policies")
+	componentCmd.AddCommand(componentImportCmd)
+}
+
+// bundleManifestName is the first entry of a component bundle.
+const bundleManifestName = "cyfr-bundle.json"
+
+// zstdMagic starts a zstd stream; gzip's is 1f 8b.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// A bundleManifest describes a component bundle.
+type bundleManifest struct {
+	Version    int           `json:"version"`
+	Created    time.Time     `json:"created"`
+	CLIVersion string        `json:"cli_version"`
+	Components []bundleEntry `json:"components"`
+}
+
+// A bundleEntry is a component in a bundle. Its files are under Path.
+type bundleEntry struct {
+	Reference string         `json:"reference"`
+	Path      string         `json:"path"`
+	Source    string         `json:"source"` // local, registry or oci
+	Digest    string         `json:"digest"`
+	Size      int64          `json:"size"`
+	Signed    bool           `json:"signed"`
+	Policy    map[string]any `json:"policy,omitempty"`
+}
+
+// A bundleFile is a file of a component in a bundle, named relative to
+// its entry's Path.
+type bundleFile struct {
+	Name string
+	Data []byte
+}
+
+var componentExportCmd = &cobra.Command{
+	Use:   "export [type] <reference>...",
+	Short: "Package components into a bundle for another instance",
+	Long: `Package components into a gzipped tar bundle that 'cyfr component import' installs on another instance, for hosts that can't pull from a registry. For each component, the bundle has its WASM artifact, its manifest and README, its signature bundle if it is signed, and the host policy set for it, if any.
+
+A component is read from the project's components/ directory if it is there, and from the server's registry or its OCI registry otherwise. Version constraints and "latest" are resolved first.
+
+Bundles are compressed with gzip; a --file ending in .zst is written as .tar.gz, since this build of cyfr has no zstd.`,
+	Example: `  cyfr component export f:acme.workflow:1.0.0 c:acme.fetch:1.2.0 --file bundle.tar.gz
+  cyfr component export c local.claude:0.1.0 local.openai:0.1.0`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := componentRefArgs(args)
+		if err != nil {
+			return err
+		}
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			file = "cyfr-components-" + time.Now().Format("20060102-150405") + ".tar.gz"
+		}
+		if strings.HasSuffix(file, ".zst") {
+			gz := strings.TrimSuffix(strings.TrimSuffix(file, ".zst"), ".tar") + ".tar.gz"
+			fmt.Fprintf(os.Stderr, "Warning: zstd isn't available in this build; writing a gzip bundle to %s.\n", gz)
+			file = gz
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		var entries []bundleEntry
+		var files [][]bundleFile
+		for _, raw := range refs {
+			entry, f, err := exportComponent(cmd.Context(), client, raw)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			files = append(files, f)
+		}
+		size, err := writeBundle(file, entries, files)
+		if err != nil {
+			return output.Errorf("Failed to write %s: %v", file, err)
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"file": file, "size": size, "components": entries, "count": len(entries)})
+			return nil
+		}
+		for _, e := range entries {
+			note := "unsigned"
+			if e.Signed {
+				note = "signed"
+			}
+			if e.Policy != nil {
+				note += ", with policy"
+			}
+			fmt.Printf("  %s (%s, %s)\n", e.Reference, output.HumanBytes(e.Size), note)
+		}
+		fmt.Printf("Exported %d component(s) to %s (%s).\n", len(entries), file, output.HumanBytes(size))
+		return nil
+	},
+}
+
+// exportComponent reads the files of the component raw names for a bundle.
+func exportComponent(ctx context.Context, client *mcp.Client, raw string) (bundleEntry, []bundleFile, error) {
+	var entry bundleEntry
+	resolved, err := resolveConstraint(ctx, client, raw)
+	if err != nil {
+		return entry, nil, err
+	}
+	if resolved, err = resolveLatest(ctx, client, resolved); err != nil {
+		return entry, nil, err
+	}
+	r, err := parseComponentRef(resolved)
+	if err != nil {
+		return entry, nil, err
+	}
+	r.Digest = ""
+	if r.Type == "" {
+		return entry, nil, output.NewError(output.CodeInvalidArgument, "Cannot export %s: add a type prefix, e.g. c:%s", r, r)
+	}
+	var artifact, signature []byte
+	var files []bundleFile
+	wasmName := r.Type + ".wasm"
+
+	if oci, ok := registryRef(resolved); ok {
+		entry.Source = "oci"
+		a, err := newOCIClient(oci.Registry).Pull(ctx, oci.Registry, oci.Repository(), oci.Version)
+		if err != nil {
+			return entry, nil, output.Errorf("Cannot export %s: %v", oci.Unpinned(), err)
+		}
+		artifact = a.Data
+		if r.Version == "latest" && a.Version() != "" {
+			r.Version = a.Version()
+		}
+		r.Registry, r.Namespace = "", ociNamespace(oci)
+	} else if dir := componentDir(r); isDir(dir) {
+		entry.Source = "local"
+		if artifact, err = os.ReadFile(filepath.Join(dir, wasmName)); err != nil {
+			return entry, nil, output.Errorf("Cannot export %s: %s isn't built; run 'cyfr build' first", r, wasmName)
+		}
+		if signature, err = artifactSignature(filepath.Join(dir, wasmName)); err != nil {
+			return entry, nil, output.Errorf("Cannot export %s: %v", r, err)
+		}
+		for _, name := range []string{componentManifestFile, "README.md"} {
+			if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+				files = append(files, bundleFile{name, data})
+			}
+		}
+	} else {
+		entry.Source = "registry"
+		info, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+			Action:    typed.ComponentActionInspect,
+			Reference: r.String(),
+		})
+		if err != nil {
+			return entry, nil, output.Errorf("Cannot export %s: %v%s", r, err, didYouMean(r.String(), err))
+		}
+		digest, _ := info["digest"].(string)
+		if digest == "" {
+			return entry, nil, output.Errorf("Cannot export %s: the server did not report a digest for it.", r)
+		}
+		if artifact, err = downloadBlob(ctx, client, withAlgorithm(digest)); err != nil {
+			return entry, nil, output.Errorf("Cannot export %s: %v", r, err)
+		}
+		if !sameDigest(artifactDigest(artifact), digest) {
+			return entry, nil, output.NewError(output.CodeUnverified, "Cannot export %s: the downloaded artifact doesn't match its digest %s", r, withAlgorithm(digest))
+		}
+		if s, ok := info["signature"].(string); ok && s != "" {
+			signature = []byte(s)
+		}
+		if m, ok := info["manifest"].(map[string]any); ok {
+			data, _ := json.MarshalIndent(m, "", "  ")
+			files = append(files, bundleFile{componentManifestFile, append(data, '\n')})
+		}
+	}
+
+	entry.Reference = r.String()
+	entry.Path = path.Join("components", r.Type+"s", r.Namespace, r.Name, r.Version)
+	entry.Digest, entry.Size = artifactDigest(artifact), int64(len(artifact))
+	entry.Signed = signature != nil
+	// The artifact goes first: a signature bundle older than its artifact
+	// is taken to be stale.
+	head := []bundleFile{{wasmName, artifact}}
+	if signature != nil {
+		head = append(head, bundleFile{wasmName + cosign.BundleSuffix, signature})
+	}
+	files = append(head, files...)
+	if result, err := typed.New(client).Policy(ctx, typed.PolicyArgs{
+		Action:       typed.PolicyActionGet,
+		ComponentRef: entry.Reference,
+	}); err == nil {
+		entry.Policy, _ = result["policy"].(map[string]any)
+	}
+	return entry, files, nil
+}
+
+// writeBundle writes the bundle to file, which only appears once it is
+// complete, and returns its size.
+func writeBundle(file string, entries []bundleEntry, files [][]bundleFile) (int64, error) {
+	f, err := os.CreateTemp(filepath.Dir(file), ".cyfr-bundle-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+	write := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: now}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	manifest, _ := json.MarshalIndent(bundleManifest{
+		Version:    1,
+		Created:    now.UTC(),
+		CLIVersion: Version,
+		Components: entries,
+	}, "", "  ")
+	err = write(bundleManifestName, manifest)
+	for i, entry := range entries {
+		for _, bf := range files[i] {
+			if err == nil {
+				err = write(path.Join(entry.Path, bf.Name), bf.Data)
+			}
+		}
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gw.Close()
+	}
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	return info.Size(), os.Rename(f.Name(), file)
+}
+
+var componentImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Install the components of a bundle",
+	Long: `Install the components of a bundle made by 'cyfr component export' into the project's components/ directory, register them with the server of the current context, and set the host policies the bundle has for them. Each artifact is checked against the digest the bundle records first.
+
+Versions already in components/ are skipped unless --force is given. --no-register only extracts the components.`,
+	Example: `  cyfr component import bundle.tar.gz
+  cyfr component import bundle.tar.gz --no-register`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		noRegister, _ := cmd.Flags().GetBool("no-register")
+		manifest, skipped, err := extractBundle(args[0], force)
+		if err != nil {
+			return err
+		}
+		var client *mcp.Client
+		if !noRegister {
+			if client, err = newClient(); err != nil {
+				return err
+			}
+		}
+		results := []any{}
+		failed := 0
+		for _, e := range manifest.Components {
+			result := map[string]any{"reference": e.Reference, "path": e.Path, "status": "imported"}
+			results = append(results, result)
+			if skipped[e.Reference] {
+				result["status"] = "skipped"
+				continue
+			}
+			if noRegister {
+				continue
+			}
+			if err := installComponent(cmd.Context(), client, e); err != nil {
+				result["status"], result["error"] = "failed", err.Error()
+				failed++
+			} else if e.Policy != nil {
+				result["policy"] = true
+			}
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"components": results, "count": len(results)})
+		} else {
+			for _, item := range results {
+				r := item.(map[string]any)
+				line := fmt.Sprintf("  %s: %s", r["reference"], r["status"])
+				switch {
+				case r["error"] != nil:
+					line += fmt.Sprintf(" (%s)", r["error"])
+				case r["status"] == "skipped":
+					line += " (already in components/; --force replaces it)"
+				case r["policy"] == true:
+					line += " (with policy)"
+				}
+				fmt.Println(line)
+			}
+		}
+		if failed > 0 {
+			return output.Errorf("Cannot install %d component(s) from %s", failed, args[0])
+		}
+		return nil
+	},
+}
+
+// extractBundle checks the bundle file and writes its components into
+// components/. Components whose version directory exists are left as they
+// are unless force is set, and returned as skipped.
+func extractBundle(file string, force bool) (bundleManifest, map[string]bool, error) {
+	var manifest bundleManifest
+	f, err := os.Open(file)
+	if err != nil {
+		return manifest, nil, output.Errorf("Failed to read %s: %v", file, err)
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	if magic, _ := br.Peek(len(zstdMagic)); bytes.Equal(magic, zstdMagic) {
+		return manifest, nil, output.NewError(output.CodeUnsupported, "%s is compressed with zstd, which this build of cyfr can't read; export it again as .tar.gz", file)
+	}
+	gr, err := gzip.NewReader(br)
+	if err != nil {
+		return manifest, nil, output.Errorf("%s is not a component bundle: %v", file, err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != bundleManifestName {
+		return manifest, nil, output.Errorf("%s is not a bundle made with 'cyfr component export'", file)
+	}
+	if err := json.NewDecoder(io.LimitReader(tr, 1<<20)).Decode(&manifest); err != nil {
+		return manifest, nil, output.Errorf("Failed to read %s: %v", bundleManifestName, err)
+	}
+	if manifest.Version != 1 {
+		return manifest, nil, output.Errorf("Bundle format %d is newer than this cyfr supports; upgrade cyfr", manifest.Version)
+	}
+
+	// Files are read into memory first, so that nothing is written unless
+	// every artifact matches its digest.
+	byPath := map[string]*bundleEntry{}
+	refs := make([]ref.ComponentRef, len(manifest.Components))
+	for i := range manifest.Components {
+		e := &manifest.Components[i]
+		r, err := ref.Parse(e.Reference)
+		if err != nil {
+			return manifest, nil, output.Errorf("%s: %v", file, err)
+		}
+		// An entry may only write into its own version directory, which a
+		// path such as components/.. would escape.
+		want := path.Join("components", r.Type+"s", r.Namespace, r.Name, r.Version)
+		e.Path = path.Clean(e.Path)
+		if r.Type == "" || (e.Path != want && !strings.HasPrefix(e.Path, want+"/")) {
+			return manifest, nil, output.Errorf("%s: invalid path %q for %s", file, e.Path, e.Reference)
+		}
+		refs[i] = r
+		byPath[e.Path] = e
+	}
+	contents := map[string][]bundleFile{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, output.Errorf("Failed to read %s: %v", file, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dir, name := path.Split(hdr.Name)
+		dir = strings.TrimSuffix(dir, "/")
+		if byPath[dir] == nil || name == "" {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, nil, output.Errorf("Failed to read %s: %v", file, err)
+		}
+		contents[dir] = append(contents[dir], bundleFile{name, data})
+	}
+	for i, e := range manifest.Components {
+		r := refs[i]
+		var artifact []byte
+		for _, bf := range contents[e.Path] {
+			if bf.Name == r.Type+".wasm" {
+				artifact = bf.Data
+			}
+		}
+		if artifact == nil {
+			return manifest, nil, output.Errorf("%s has no artifact for %s", file, e.Reference)
+		}
+		if !sameDigest(artifactDigest(artifact), e.Digest) {
+			return manifest, nil, output.NewError(output.CodeUnverified, "The artifact of %s in %s doesn't match its digest %s", e.Reference, file, withAlgorithm(e.Digest))
+		}
+	}
+
+	skipped := map[string]bool{}
+	for _, e := range manifest.Components {
+		dir := filepath.FromSlash(e.Path)
+		if isDir(dir) {
+			if !force {
+				skipped[e.Reference] = true
+				continue
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				return manifest, nil, output.Errorf("Failed to replace %s: %v", dir, err)
+			}
+		}
+		for _, bf := range contents[e.Path] {
+			if err := restoreFile(filepath.Join(dir, bf.Name), bytes.NewReader(bf.Data), 0644); err != nil {
+				return manifest, nil, output.Errorf("Failed to write %s: %v", dir, err)
+			}
+		}
+	}
+	return manifest, skipped, nil
+}
+
+// installComponent registers an extracted component with the server and
+// sets the policy the bundle has for it.
+func installComponent(ctx context.Context, client *mcp.Client, e bundleEntry) error {
+	if _, err := registerComponent(ctx, filepath.FromSlash(e.Path)); err != nil {
+		return err
+	}
+	if e.Policy == nil {
+		return nil
+	}
+	if _, err := typed.New(client).Policy(ctx, typed.PolicyArgs{
+		Action:       typed.PolicyActionSet,
+		ComponentRef: e.Reference,
+		Policy:       e.Policy,
+	}); err != nil {
+		return fmt.Errorf("set policy: %v", err)
+	}
+	return nil
+}
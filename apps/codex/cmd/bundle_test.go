@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestExportImportBundle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Errors = map[string]string{"policy.get": "Policy not found"}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	chdirTemp(t)
+	writeComponent(t, "catalyst", "fetch", `{"type": "catalyst"}`, map[string]string{"catalyst.wasm": "\x00asm fetch", "README.md": "# fetch\n"})
+	var entries []bundleEntry
+	var files [][]bundleFile
+	// A local component, and one from the registry.
+	for _, raw := range []string{"c:local.fetch:0.1.0", "r:local.hello:0.1.0"} {
+		e, bf, err := exportComponent(context.Background(), client, raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, files = append(entries, e), append(files, bf)
+	}
+	if entries[0].Source != "local" || entries[1].Source != "registry" || entries[0].Policy != nil {
+		t.Fatalf("entries = %+v", entries)
+	}
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if _, err := writeBundle(bundle, entries, files); err != nil {
+		t.Fatal(err)
+	}
+
+	chdirTemp(t)
+	manifest, skipped, err := extractBundle(bundle, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Components) != 2 || len(skipped) != 0 {
+		t.Fatalf("manifest = %+v, skipped %v", manifest, skipped)
+	}
+	for name, want := range map[string]string{
+		"components/catalysts/local/fetch/0.1.0/catalyst.wasm": "\x00asm fetch",
+		"components/catalysts/local/fetch/0.1.0/README.md":     "# fetch\n",
+		"components/reagents/local/hello/0.1.0/reagent.wasm":   "\x00asm\x01\x00\x00\x00",
+	} {
+		if got, _ := os.ReadFile(name); string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// Existing versions are skipped unless forced.
+	os.WriteFile("components/catalysts/local/fetch/0.1.0/README.md", []byte("edited"), 0644)
+	if _, skipped, _ = extractBundle(bundle, false); !skipped["catalyst:local.fetch:0.1.0"] {
+		t.Errorf("skipped = %v", skipped)
+	}
+	if got, _ := os.ReadFile("components/catalysts/local/fetch/0.1.0/README.md"); string(got) != "edited" {
+		t.Error("an existing version was replaced without --force")
+	}
+	if _, skipped, _ = extractBundle(bundle, true); len(skipped) != 0 {
+		t.Errorf("--force skipped %v", skipped)
+	}
+
+	// An artifact that doesn't match its digest fails the whole import.
+	entries[1].Digest = artifactDigest([]byte("other"))
+	if _, err := writeBundle(bundle, entries, files); err != nil {
+		t.Fatal(err)
+	}
+	chdirTemp(t)
+	if _, _, err := extractBundle(bundle, false); err == nil || !strings.Contains(err.Error(), "doesn't match its digest") {
+		t.Errorf("tampered bundle: err = %v", err)
+	}
+	if isDir("components") {
+		t.Error("a tampered bundle was partly extracted")
+	}
+
+	// A path outside the entry's version directory is refused, even with
+	// --force, before anything is removed or written.
+	entries[1].Digest = artifactDigest([]byte("\x00asm\x01\x00\x00\x00"))
+	for _, p := range []string{"components/..", "components/.", "components/reagents/local/hello/0.1.0/../../other/0.1.0"} {
+		entries[1].Path = p
+		if _, err := writeBundle(bundle, entries, files); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile("keep", []byte("project"), 0644)
+		if _, _, err := extractBundle(bundle, true); err == nil || !strings.Contains(err.Error(), "invalid path") {
+			t.Errorf("path %q: err = %v", p, err)
+		}
+		if _, err := os.Stat("keep"); err != nil {
+			t.Errorf("path %q: the project directory was touched", p)
+		}
+	}
+
+	os.WriteFile("bundle.tar.zst", append(zstdMagic, 0, 0), 0644)
+	if _, _, err := extractBundle("bundle.tar.zst", false); err == nil || !strings.Contains(err.Error(), "zstd") {
+		t.Errorf("zstd bundle: err = %v", err)
+	}
+}
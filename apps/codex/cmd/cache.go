@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/cache"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cachePruneCmd.Flags().String("older-than", "", "Remove artifacts not used for this long, e.g. 30d or 12h")
+	cachePruneCmd.Flags().String("max-size", "", "Then remove the least recently used artifacts until the cache fits this size, e.g. 5GB")
+	cachePruneCmd.Flags().Bool("all", false, "Remove every cached artifact")
+	cachePruneCmd.Flags().Bool("dry-run", false, "List what would be removed without removing it")
+	cacheCmd.AddCommand(cacheInfoCmd, cacheListCmd, cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:     "cache",
+	Short:   "Inspect and clean the local artifact cache",
+	GroupID: "component",
+	Long:    "Inspect and garbage-collect the component artifacts 'cyfr pull' keeps in ~/.cyfr/cache/artifacts, so they are at hand offline. Each artifact is stored once by digest, with the references it was pulled as and when it was last used; pulling it again marks it used.",
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:     "info",
+	Short:   "Show the cache's location and size",
+	Example: "  cyfr cache info",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, entries, err := cacheEntries()
+		if err != nil {
+			return err
+		}
+		var total int64
+		for _, e := range entries {
+			total += e.Size
+		}
+		info := map[string]any{
+			"path":       c.Dir,
+			"artifacts":  len(entries),
+			"components": len(cachedComponents(entries)),
+			"size":       total,
+		}
+		if len(entries) > 0 {
+			// entries is most recently used first.
+			info["last_used"] = entries[0].LastUsed.UTC().Format(time.RFC3339)
+			info["least_recently_used"] = entries[len(entries)-1].LastUsed.UTC().Format(time.RFC3339)
+		}
+		if flagJSON {
+			output.JSON(info)
+			return nil
+		}
+		info["size"] = output.HumanBytes(total)
+		output.KeyValue(info)
+		return nil
+	},
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "Show cached components with their disk usage",
+	Long:    "List the components with artifacts in the cache, most recently used first, with the versions cached, how much disk they take and when they were last used. --json lists the artifacts too.",
+	Example: "  cyfr cache list",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, entries, err := cacheEntries()
+		if err != nil {
+			return err
+		}
+		components := cachedComponents(entries)
+		if flagJSON {
+			output.JSON(map[string]any{"components": components, "artifacts": entries, "count": len(components)})
+			return nil
+		}
+		if len(components) == 0 {
+			fmt.Println("The cache is empty. 'cyfr pull' fills it.")
+			return nil
+		}
+		rows := make([]map[string]string, len(components))
+		for i, c := range components {
+			rows[i] = map[string]string{
+				"COMPONENT": c.Component,
+				"VERSIONS":  strings.Join(c.Versions, ", "),
+				"ARTIFACTS": fmt.Sprint(c.Artifacts),
+				"SIZE":      output.HumanBytes(c.Size),
+				"LAST USED": c.LastUsed.Local().Format("2006-01-02 15:04"),
+			}
+		}
+		output.Table([]string{"COMPONENT", "VERSIONS", "ARTIFACTS", "SIZE", "LAST USED"}, rows)
+		return nil
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unused artifacts from the cache",
+	Long:  "Remove the artifacts not used for --older-than, then, if the cache is still larger than --max-size, the least recently used ones until it fits. --all empties the cache. Sizes take B, KB, MB, GB or TB (powers of 1024); ages take a Go duration or a number of days, e.g. 30d.",
+	Example: `  cyfr cache prune --older-than 30d
+  cyfr cache prune --max-size 5GB --dry-run
+  cyfr cache prune --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThanFlag, _ := cmd.Flags().GetString("older-than")
+		maxSizeFlag, _ := cmd.Flags().GetString("max-size")
+		all, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if !all && olderThanFlag == "" && maxSizeFlag == "" {
+			return output.NewError(output.CodeInvalidArgument, "Give --older-than, --max-size or --all")
+		}
+		var olderThan time.Duration
+		var maxSize int64
+		var err error
+		if olderThanFlag != "" {
+			if olderThan, err = parseAge(olderThanFlag); err != nil {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --older-than: %v", err)
+			}
+		}
+		if maxSizeFlag != "" {
+			if maxSize, err = parseByteSize(maxSizeFlag); err != nil {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --max-size: %v", err)
+			}
+		}
+
+		c, entries, err := cacheEntries()
+		if err != nil {
+			return err
+		}
+		prune := entries
+		if !all {
+			if prune, err = c.PruneCandidates(olderThan, maxSize); err != nil {
+				return output.Errorf("Failed to read the cache: %v", err)
+			}
+		}
+		var freed int64
+		digests := make([]string, len(prune))
+		for i, e := range prune {
+			freed += e.Size
+			digests[i] = e.Digest
+		}
+		if !dryRun {
+			if err := c.Remove(digests); err != nil {
+				return output.Errorf("Failed to prune the cache: %v", err)
+			}
+		}
+		if flagJSON {
+			if prune == nil {
+				prune = []cache.Entry{}
+			}
+			output.JSON(map[string]any{"removed": prune, "count": len(prune), "freed": freed, "dry_run": dryRun})
+			return nil
+		}
+		verb := "Removed"
+		if dryRun {
+			verb = "Would remove"
+		}
+		for _, e := range prune {
+			fmt.Printf("%s %s (%s)\n", verb, strings.Join(e.References, ", "), output.HumanBytes(e.Size))
+		}
+		fmt.Printf("%s %d artifact(s), freeing %s.\n", verb, len(prune), output.HumanBytes(freed))
+		return nil
+	},
+}
+
+// cacheEntries opens the artifact cache and reads its artifacts.
+func cacheEntries() (*cache.Cache, []cache.Entry, error) {
+	c, err := cache.Open()
+	if err != nil {
+		return nil, nil, output.NewError(output.CodeConfig, "Cannot find the cache: %v", err)
+	}
+	entries, err := c.Entries()
+	if err != nil {
+		return nil, nil, output.Errorf("Failed to read the cache: %v", err)
+	}
+	return c, entries, nil
+}
+
+// A cachedComponent is the disk usage of a component's cached artifacts.
+type cachedComponent struct {
+	Component string    `json:"component"`
+	Versions  []string  `json:"versions"`
+	Artifacts int       `json:"artifacts"`
+	Size      int64     `json:"size"`
+	LastUsed  time.Time `json:"last_used"`
+}
+
+// cachedComponents groups cached artifacts by the components they were
+// pulled as, most recently used first.
+func cachedComponents(entries []cache.Entry) []cachedComponent {
+	byName := map[string]*cachedComponent{}
+	for _, e := range entries {
+		counted := map[string]bool{}
+		for _, reference := range e.References {
+			r, err := ref.Parse(reference)
+			if err != nil {
+				continue
+			}
+			name := componentName(r)
+			c := byName[name]
+			if c == nil {
+				c = &cachedComponent{Component: name}
+				byName[name] = c
+			}
+			if !counted[name] {
+				counted[name] = true
+				c.Artifacts++
+				c.Size += e.Size
+			}
+			if version := r.Version; version != "" && !slices.Contains(c.Versions, version) {
+				c.Versions = append(c.Versions, version)
+			}
+			if e.LastUsed.After(c.LastUsed) {
+				c.LastUsed = e.LastUsed
+			}
+		}
+	}
+	components := make([]cachedComponent, 0, len(byName))
+	for _, c := range byName {
+		ref.Sort(c.Versions)
+		components = append(components, *c)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if !components[i].LastUsed.Equal(components[j].LastUsed) {
+			return components[i].LastUsed.After(components[j].LastUsed)
+		}
+		return components[i].Component < components[j].Component
+	})
+	return components
+}
+
+// cacheArtifact keeps the artifact with the given digest, pulled from the
+// server's registry as reference, in the local cache, downloading it
+// unless it is there already. It is best effort: the pull has succeeded,
+// so a failure is only a warning.
+func cacheArtifact(ctx context.Context, client *mcp.Client, reference, digest string) {
+	if digest == "" {
+		return
+	}
+	c, err := cache.Open()
+	if err != nil {
+		return
+	}
+	data, ok := c.Get(digest)
+	if !ok {
+		if data, err = downloadBlob(ctx, client, withAlgorithm(digest)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: not cached: %v\n", err)
+			return
+		}
+		if !sameDigest(artifactDigest(data), digest) {
+			fmt.Fprintf(os.Stderr, "Warning: not cached: the artifact downloaded doesn't match digest %s\n", digest)
+			return
+		}
+	}
+	if r, err := ref.Parse(reference); err == nil {
+		reference = r.String()
+	}
+	if _, err := c.Put(reference, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not cached: %v\n", err)
+	}
+}
+
+// parseAge parses a duration such as 12h, or a number of days such as 30d.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%q is not a number of days", s)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration, e.g. 30d or 12h", s)
+	}
+	return d, nil
+}
+
+// parseByteSize parses a size such as 5GB or 512MiB, in powers of 1024 as
+// HumanBytes prints them, or a plain number of bytes.
+func parseByteSize(s string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	upper = strings.TrimSuffix(strings.TrimSuffix(upper, "IB"), "B")
+	multiplier := int64(1)
+	if upper != "" {
+		if i := strings.IndexByte("KMGT", upper[len(upper)-1]); i >= 0 {
+			multiplier = int64(1) << (10 * (i + 1))
+			upper = upper[:len(upper)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not a size, e.g. 5GB or 500MB", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
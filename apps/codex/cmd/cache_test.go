@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/cache"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestPull_FillsCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := httptest.NewServer(mockserver.New(mockserver.DefaultFixtures()))
+	defer srv.Close()
+	chdirTemp(t)
+
+	if _, err := pullComponent(context.Background(), mcp.NewClient(srv.URL), "r:local.hello:0.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	c, err := cache.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := c.Entries()
+	if len(entries) != 1 || entries[0].Digest != "sha256:93a44bbb96c751218e4c00d479e4c14358122a389acca16205b1e4d0dc5f9476" || entries[0].References[0] != "reagent:local.hello:0.1.0" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestCachedComponents(t *testing.T) {
+	used := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []cache.Entry{
+		{Digest: "sha256:a", References: []string{"reagent:local.hello:0.2.0", "reagent:local.hello:latest"}, Size: 10, LastUsed: used},
+		{Digest: "sha256:b", References: []string{"reagent:local.hello:0.1.0"}, Size: 5, LastUsed: used.Add(-time.Hour)},
+		{Digest: "sha256:c", References: []string{"catalyst:acme.fetch:1.0.0"}, Size: 7, LastUsed: used.Add(-2 * time.Hour)},
+	}
+	got := cachedComponents(entries)
+	if len(got) != 2 {
+		t.Fatalf("components = %+v", got)
+	}
+	hello := got[0]
+	if hello.Component != "reagent:local.hello" || hello.Artifacts != 2 || hello.Size != 15 || !hello.LastUsed.Equal(used) {
+		t.Errorf("hello = %+v", hello)
+	}
+	if want := []string{"0.1.0", "0.2.0", "latest"}; len(hello.Versions) != 3 || hello.Versions[0] != want[0] || hello.Versions[1] != want[1] {
+		t.Errorf("versions = %v", hello.Versions)
+	}
+}
+
+func TestParseAgeAndSize(t *testing.T) {
+	for s, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "1.5d": 36 * time.Hour, "12h": 12 * time.Hour} {
+		if got, err := parseAge(s); err != nil || got != want {
+			t.Errorf("parseAge(%q) = %v, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "d", "-1d", "soon", "0s"} {
+		if _, err := parseAge(s); err == nil {
+			t.Errorf("parseAge(%q) succeeded", s)
+		}
+	}
+	for s, want := range map[string]int64{"5GB": 5 << 30, "512MiB": 512 << 20, "1.5kb": 1536, "100": 100, "100B": 100} {
+		if got, err := parseByteSize(s); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "GB", "-5GB", "5XB"} {
+		if _, err := parseByteSize(s); err == nil {
+			t.Errorf("parseByteSize(%q) succeeded", s)
+		}
+	}
+}
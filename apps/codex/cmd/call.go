@@ -3,11 +3,13 @@ package cmd
 import (
 	"encoding/json"
 
+	"github.com/cyfr/codex/internal/mcp"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	addContentFlags(callCmd)
 	rootCmd.AddCommand(callCmd)
 }
 
@@ -15,29 +17,41 @@ var callCmd = &cobra.Command{
 	Use:     "call <tool> [json-args]",
 	Short:   "Invoke any MCP tool directly",
 	GroupID: "advanced",
-	Long:    "Directly invoke any registered MCP tool by name, passing an optional JSON object as arguments. Useful for debugging, scripting, and accessing tools that don't have a dedicated CLI command.",
+	Long:    "Directly invoke any registered MCP tool by name, passing an optional JSON object as arguments. Useful for debugging, scripting, and accessing tools that don't have a dedicated CLI command.\n\nThe result is printed as JSON. Images, audio and embedded resources in it are included base64-encoded under \"_content\" unless --output-dir is given, in which case they are saved to files there.",
 	Example: `  cyfr call system '{"action":"status"}'
   cyfr call component '{"action":"search","query":"sentiment"}'
-  cyfr call secret '{"action":"list"}'`,
+  cyfr call secret '{"action":"list"}'
+  cyfr call execution '{"action":"run","reference":{"registry":"cyfr.chart:1.0.0"}}' --output-dir ./out`,
 	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		toolName := args[0]
 
 		var toolArgs map[string]any
 		if len(args) > 1 {
 			if err := json.Unmarshal([]byte(args[1]), &toolArgs); err != nil {
-				output.Errorf("Invalid JSON: %v", err)
+				return output.NewError(output.CodeInvalidArgument, "Invalid JSON: %v", err)
 			}
 		} else {
 			toolArgs = map[string]any{}
 		}
 
-		client := newClient()
-		result, err := client.CallTool(toolName, toolArgs)
+		client, err := newClient()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return err
+		}
+		result, err := client.CallToolCtx(cmd.Context(), toolName, toolArgs)
+		if err != nil {
+			return toolError(err)
 		}
 
+		opts := contentOptionsFromFlags(cmd, toolName)
+		if flagJSON || !cmd.Flags().Changed("output-dir") {
+			prepareContentJSON(cmd.Context(), client, result, opts)
+			output.JSON(result)
+			return nil
+		}
+		extra := mcp.ExtraContent(result)
 		output.JSON(result)
+		return printExtraContent(cmd.Context(), client, extra, opts)
 	},
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+// serverState converts an initialize result to its saved form.
+func serverState(r *mcp.InitializeResult) *config.ServerState {
+	if r == nil {
+		return nil
+	}
+	s := &config.ServerState{
+		ProtocolVersion: r.ProtocolVersion,
+		Capabilities:    r.Capabilities,
+	}
+	if r.ServerInfo != nil {
+		s.Name = r.ServerInfo.Name
+		s.Version = r.ServerInfo.Version
+	}
+	return s
+}
+
+// initializeResult converts a saved server state back to the initialize
+// result the client uses for feature detection.
+func initializeResult(s *config.ServerState) *mcp.InitializeResult {
+	if s == nil {
+		return nil
+	}
+	r := &mcp.InitializeResult{
+		ProtocolVersion: s.ProtocolVersion,
+		Capabilities:    s.Capabilities,
+	}
+	if s.Name != "" || s.Version != "" {
+		r.ServerInfo = &mcp.ServerInfo{Name: s.Name, Version: s.Version}
+	}
+	return r
+}
+
+// warnProtocolMismatch warns when the server negotiated a different MCP
+// protocol version than the one this CLI was built for.
+func warnProtocolMismatch(r *mcp.InitializeResult) {
+	if r == nil || r.ProtocolVersion == "" || r.ProtocolVersion == mcp.ProtocolVersion {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: server speaks MCP %s but cyfr was built for %s; some commands may not work.\n",
+		r.ProtocolVersion, mcp.ProtocolVersion)
+}
+
+// serverSummary describes the negotiated server for JSON output, or nil if
+// nothing has been negotiated yet.
+func serverSummary(client *mcp.Client) map[string]any {
+	s := serverState(client.Server)
+	if s == nil {
+		return nil
+	}
+	summary := map[string]any{
+		"protocol_version":        s.ProtocolVersion,
+		"client_protocol_version": mcp.ProtocolVersion,
+		"capabilities":            s.Capabilities,
+	}
+	if s.Name != "" {
+		summary["name"] = s.Name
+		summary["version"] = s.Version
+	}
+	return summary
+}
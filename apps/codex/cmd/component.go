@@ -1,17 +1,42 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	addMultiContextFlags(searchCmd)
+	addPaginationFlags(searchCmd, 20)
+	searchCmd.Flags().String("type", "", "Only find components of this type: catalyst, reagent or formula (or c, r, f)")
+	searchCmd.Flags().String("namespace", "", "Only find components in this namespace, e.g. acme")
+	searchCmd.Flags().String("sort", "", "Order the components by downloads, updated (most recent first) or name")
+	searchCmd.Flags().Int("page", 1, "Show this page of --limit results")
+	searchCmd.Flags().String("registry", "", "Search this configured OCI registry rather than the server's")
+	addColumnsFlag(searchCmd)
 	rootCmd.AddCommand(searchCmd)
+	inspectCmd.Flags().Bool("versions", false, "List the component's published versions with their publish dates and digests, highest first")
+	inspectCmd.Flags().Bool("manifest", false, "Show the component's manifest as published and the WIT interfaces it imports and exports")
+	inspectCmd.Flags().Bool("local", false, "Parse the component's artifact from components/ or the cache instead of asking the server")
 	rootCmd.AddCommand(inspectCmd)
+	pullCmd.Flags().Bool("with-deps", false, "Pull the components a formula depends on too, skipping those already in components/")
+	pullCmd.Flags().String("registry", "", "Pull from this configured OCI registry rather than where the namespace is routed")
 	rootCmd.AddCommand(pullCmd)
 	rootCmd.AddCommand(resolveCmd)
+	publishCmd.Flags().Bool("skip-validation", false, "Publish a local component without checking it as 'cyfr validate' does")
+	publishCmd.Flags().String("bump", "", "Publish the next major, minor or patch version after the highest published one, copied from the local component")
+	publishCmd.Flags().Bool("dry-run", false, "Show what would be published, without publishing it")
+	publishCmd.Flags().String("registry", "", "Publish to this configured OCI registry rather than where the namespace is routed")
+	publishCmd.Flags().String("artifact", "", "WASM file to push when publishing to an OCI registry (default: the component's file under components/)")
 	rootCmd.AddCommand(publishCmd)
 }
 
@@ -19,82 +44,316 @@ var searchCmd = &cobra.Command{
 	Use:     "search <query>",
 	Short:   "Search for components",
 	GroupID: "component",
-	Long:    "Search the component registry by keyword and return matching references.",
+	Long:    "Search the component registry by keyword and show the matching components in a table, with the latest release of each and its description. --json prints every matching version as the registry returns it.\n\n--type and --namespace narrow the search and --sort orders the components by downloads, by when they were last updated, or by name. At most --limit matches are returned (default 20), and --page 2 shows the next --limit; use --all for every match. Use --contexts or --all-contexts to compare registries across servers.\n\nA --namespace routed to an OCI registry with 'cyfr registry add', or --registry, searches that registry instead. OCI registries can't be searched by keyword, so the query is taken as the name of a component and its published versions are listed.",
 	Example: `  cyfr search sentiment
-  cyfr search "http client" --json`,
+  cyfr search "http client" --json
+  cyfr search sentiment --type catalyst --namespace acme
+  cyfr search sentiment --sort downloads
+  cyfr search sentiment --limit 50 --page 2
+  cyfr search sentiment --contexts local,staging
+  cyfr search sentiment --registry acme --namespace acme`,
 	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("component", map[string]any{
-			"action": "search",
-			"query":  args[0],
-		})
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit := pageLimit(cmd)
+		toolArgs := typed.ComponentArgs{
+			Action: typed.ComponentActionSearch,
+			Query:  args[0],
+		}
+		typ, _ := cmd.Flags().GetString("type")
+		if typ != "" {
+			typ = ref.ExpandType(typ)
+			if !ref.IsTypePrefix(typ) {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --type %q (use catalyst, reagent, or formula)", typ)
+			}
+			toolArgs.Type = typ
+		}
+		namespace, _ := cmd.Flags().GetString("namespace")
+		if namespace != "" {
+			toolArgs.Namespace = namespace
+		}
+		sortBy, _ := cmd.Flags().GetString("sort")
+		if err := validSearchSort(sortBy); err != nil {
+			return err
+		}
+		if sortBy != "" {
+			toolArgs.Sort = sortBy
+		}
+		page, _ := cmd.Flags().GetInt("page")
+		if page < 1 {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --page %d (pages count from 1)", page)
+		}
+		if page > 1 && limit == 0 {
+			return output.NewError(output.CodeInvalidArgument, "--page needs --limit, not --all")
+		}
+
+		registry, _ := cmd.Flags().GetString("registry")
+		reg, err := searchRegistry(registry, namespace)
+		if err != nil {
+			return err
+		}
+
+		names, err := selectedContexts(cmd)
 		if err != nil {
-			output.Errorf("Search failed: %v", err)
+			return err
+		}
+		if names != nil {
+			if reg != nil {
+				return output.NewError(output.CodeInvalidArgument, "--contexts and --all-contexts search the servers' registries, not an OCI registry")
+			}
+			if page > 1 {
+				return output.NewError(output.CodeInvalidArgument, "--page can't be used with --contexts or --all-contexts")
+			}
+			if limit > 0 {
+				toolArgs.Limit = limit
+			}
+			results := callAcrossContexts(cmd.Context(), names, "component", toolArgs.Map())
+			printContextResults(results)
+			return contextErrors(results)
 		}
+
+		var result map[string]any
+		if reg != nil {
+			if result, err = searchOCI(cmd.Context(), reg, namespace, args[0], typ); err != nil {
+				return err
+			}
+		} else {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			// Pages are the results after the first (page-1)*limit.
+			result, err = client.CallToolPagedCtx(cmd.Context(), "component", toolArgs.Map(), "components", limit*page)
+			if err != nil {
+				return output.Errorf("Search failed: %v", err)
+			}
+			rememberSearchResults(result)
+		}
+		searchPage(result, namespace, page, limit)
 		if flagJSON {
 			output.JSON(result)
+		} else if err := printList(cmd, map[string]any{"components": searchSummary(result, sortBy)}, "components"); err != nil {
+			return err
+		}
+		if page > 1 {
+			warnMorePages(result, page)
 		} else {
-			output.KeyValue(result)
+			warnMoreResults(result)
 		}
+		return nil
 	},
 }
 
 var inspectCmd = &cobra.Command{
-	Use:     "inspect [type] <reference>",
+	Use:     "inspect [type] <reference>...",
 	Short:   "Show component details",
 	GroupID: "component",
-	Long:    "Display metadata, version history, and capability declarations for a component.",
+	Long:    "Display metadata, version history, and capability declarations for one or more components, given as separate arguments or comma-separated; duplicates are inspected once. A version constraint such as ^1.2 or \">=1.0.0 <2.0.0\" is resolved to the highest matching published version. A reference pinned with @sha256:<digest> fails unless the registry's artifact has that digest. With --versions, the component's published versions are listed instead, highest first by semver precedence, marking the release \"latest\" resolves to and those that are yanked, with when each was published and its digest. With --manifest, the component's manifest is shown as published, in full, followed by the WIT interfaces its artifact imports and exports.\n\nGiven .wasm files or the sha256:<digest> of artifacts in the cache, or references with --local, the component binaries are parsed on this machine without the server: their imports and exports, the WIT worlds they were built for, their custom sections and how their size breaks down by section, with the manifest next to a file in a component directory. Use it to check a component before publishing it.",
 	Example: `  cyfr inspect c:local.claude:0.1.0
+  cyfr inspect c:local.claude@^0.1
+  cyfr inspect c:local.claude --versions
+  cyfr inspect c:local.claude:0.1.0 --manifest
+  cyfr inspect c:acme.sentiment@sha256:93a44bbb...
   cyfr inspect c local.claude:0.1.0
-  cyfr inspect local.sentiment:1.0.0`,
-	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
-		args = joinTypeShorthand(args)
-		normalized := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("component", map[string]any{
-			"action":    "inspect",
-			"reference": normalized,
-		})
+  cyfr inspect c:local.claude:0.1.0,r:acme.parser:2.0.0
+  cyfr inspect local.sentiment:1.0.0
+  cyfr inspect components/catalysts/local/claude/0.1.0/catalyst.wasm
+  cyfr inspect c:acme.sentiment:1.2.0 --local`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versions, _ := cmd.Flags().GetBool("versions")
+		manifest, _ := cmd.Flags().GetBool("manifest")
+		local, _ := cmd.Flags().GetBool("local")
+		if versions && manifest {
+			return output.Error("--versions and --manifest can't be used together.")
+		}
+		artifacts, err := localArtifactArgs(args)
 		if err != nil {
-			output.Errorf("Inspect failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		if len(artifacts) == 0 && local {
+			refs, err := componentRefArgs(args)
+			if err != nil {
+				return err
+			}
+			for _, raw := range refs {
+				r, _ := parseComponentRef(raw)
+				artifact, err := localArtifactFor(r)
+				if err != nil {
+					return err
+				}
+				artifacts = append(artifacts, artifact)
+			}
+		}
+		if len(artifacts) > 0 {
+			if versions || manifest {
+				return output.Error("--versions and --manifest need the registry; inspect the reference without --local.")
+			}
+			results := make([]map[string]any, len(artifacts))
+			for i, artifact := range artifacts {
+				if results[i], err = inspectArtifact(artifact); err != nil {
+					return err
+				}
+			}
+			printRefResults(artifacts, results, printArtifact)
+			return nil
+		}
+
+		refs, err := componentRefArgs(args)
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
 		}
+		if versions {
+			if len(refs) > 1 {
+				return output.Error("--versions takes a single reference.")
+			}
+			return listVersions(cmd.Context(), client, refs[0])
+		}
+		results := make([]map[string]any, len(refs))
+		for i, raw := range refs {
+			if results[i], err = inspectComponent(cmd.Context(), client, raw); err != nil {
+				return err
+			}
+			if manifest {
+				results[i] = publishedManifest(cmd.Context(), client, results[i])
+			}
+		}
+		if manifest {
+			printRefResults(refs, results, printManifest)
+			return nil
+		}
+		printRefResults(refs, results, refKeyValue(refs))
+		return nil
 	},
 }
 
+// inspectComponent looks up one component in the registry.
+func inspectComponent(ctx context.Context, client *mcp.Client, raw string) (map[string]any, error) {
+	resolved, err := resolveConstraint(ctx, client, raw)
+	if err != nil {
+		return nil, err
+	}
+	normalized, pinned, err := unpinReference(resolved)
+	if err != nil {
+		return nil, err
+	}
+	result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionInspect,
+		Reference: normalized,
+	})
+	if err != nil {
+		return nil, output.Errorf("Inspect failed: %v%s", err, didYouMean(normalized, err))
+	}
+	if pinned != "" {
+		digest, _ := result["digest"].(string)
+		if err := checkDigest(normalized, pinned, digest); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 var pullCmd = &cobra.Command{
-	Use:     "pull [type] <reference>",
+	Use:     "pull [type] <reference>...",
 	Short:   "Fetch component to cache",
 	GroupID: "component",
-	Long:    "Download a component WASM artifact to the local cache so it is available for offline execution.",
+	Long: `Download component WASM artifacts to the local cache so they are available for offline execution. Several components may be given, as separate arguments or comma-separated; duplicates are pulled once. A version constraint such as ~1.4.0 is resolved to the highest matching published version, and an unversioned reference to the highest release. For a reference pinned with @sha256:<digest>, the artifact is downloaded and its hash checked against the pin. If the context's verify_signatures is enforce or warn, the artifact's digest and signature are checked first, as 'cyfr verify' does, and the pull is pinned to the digest that was checked.
+
+A reference starting with a registry host, such as ghcr.io/acme/sentiment:1.0.0, is pulled directly from that OCI registry into components/{type}s/{namespace}/{name}/{version}/. Credentials, if the registry needs them, are read from CYFR_REGISTRY_USERNAME and CYFR_REGISTRY_PASSWORD.
+
+With --with-deps, the components a formula depends on are pulled too, and theirs in turn, resolved as 'cyfr deps' resolves them, so that it can later run offline. Components already in components/ with their WASM file built are reported as cached and not pulled again.`,
 	Example: `  cyfr pull c:local.claude:0.1.0
-  cyfr pull cyfr.sentiment:1.0.0`,
-	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
-		args = joinTypeShorthand(args)
-		normalized := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("component", map[string]any{
-			"action":    "pull",
-			"reference": normalized,
-		})
+  cyfr pull f:acme.workflow:1.0.0 --with-deps
+  cyfr pull c:cyfr.sentiment:~1.4.0
+  cyfr pull c:acme.sentiment@sha256:93a44bbb...
+  cyfr pull cyfr.sentiment:1.0.0
+  cyfr pull c local.claude:0.1.0 local.openai:0.2.0
+  cyfr pull c:local.claude:0.1.0,r:acme.parser:2.0.0
+  cyfr pull c:ghcr.io/acme/sentiment:1.0.0`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := componentRefArgs(args)
 		if err != nil {
-			output.Errorf("Pull failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		registry, _ := cmd.Flags().GetString("registry")
+		for i := range refs {
+			if refs[i], err = routeRegistry(refs[i], registry); err != nil {
+				return err
+			}
+		}
+		if withDeps, _ := cmd.Flags().GetBool("with-deps"); withDeps {
+			return printPulled(pullWithDeps(cmd.Context(), client, refs))
 		}
+		results := make([]map[string]any, len(refs))
+		for i, raw := range refs {
+			if results[i], err = pullComponent(cmd.Context(), client, raw); err != nil {
+				return err
+			}
+		}
+		printRefResults(refs, results, refKeyValue(refs))
+		return nil
 	},
 }
 
+// pullComponent downloads one component, from its OCI registry or through
+// the server.
+func pullComponent(ctx context.Context, client *mcp.Client, raw string) (map[string]any, error) {
+	resolved, err := resolveConstraint(ctx, client, raw)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err = resolveLatest(ctx, client, resolved); err != nil {
+		return nil, err
+	}
+	checked, err := checkBeforeUse(ctx, client, resolved)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := registryRef(resolved); ok {
+		if r.Digest == "" {
+			r.Digest = checked
+		}
+		return pullOCI(ctx, r)
+	}
+	normalized, pinned, err := unpinReference(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if pinned == "" {
+		pinned = checked
+	}
+	done := showProgress(client, "Pulling")
+	result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionPull,
+		Reference: normalized,
+	})
+	done()
+	if err != nil {
+		return nil, output.Errorf("Pull failed: %v%s", err, didYouMean(normalized, err))
+	}
+	if pinned != "" {
+		digest, _ := result["digest"].(string)
+		if err := checkDigest(normalized, pinned, digest); err != nil {
+			return nil, err
+		}
+		if err := verifyArtifact(ctx, client, pinned); err != nil {
+			return nil, output.Errorf("Pull failed: %v", err)
+		}
+		result["verified"] = true
+	}
+	digest, _ := result["digest"].(string)
+	cacheArtifact(ctx, client, normalized, digest)
+	return result, nil
+}
+
 var resolveCmd = &cobra.Command{
 	Use:     "resolve [type] <reference>",
 	Short:   "Resolve component location",
@@ -103,22 +362,32 @@ var resolveCmd = &cobra.Command{
 	Example: `  cyfr resolve c:local.claude:0.1.0
   cyfr resolve cyfr.sentiment:1.0.0`,
 	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		normalized := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("component", map[string]any{
-			"action":    "resolve",
-			"reference": normalized,
+		normalized, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
+		if _, err := parseComponentRef(normalized); err != nil {
+			return err
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Component(cmd.Context(), typed.ComponentArgs{
+			Action:    typed.ComponentActionResolve,
+			Reference: normalized,
 		})
 		if err != nil {
-			output.Errorf("Resolve failed: %v", err)
+			return output.Errorf("Resolve failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -126,35 +395,166 @@ var publishCmd = &cobra.Command{
 	Use:     "publish [type] <reference>",
 	Short:   "Sign and publish component",
 	GroupID: "component",
-	Long:    "Sign a local component and publish it to the registry, making it available for execution.",
+	Long:    "Sign a local component and publish it to the registry, making it available for execution. A signature bundle made by 'cyfr sign' is attached; publishing an unsigned component warns. The component may be given by reference or by its path, e.g. components/reagents/local/sentiment/1.0.0/. A reference starting with a registry host, such as c:ghcr.io/acme/sentiment:1.0.0, pushes the component's WASM file to that OCI registry instead, using credentials from CYFR_REGISTRY_USERNAME and CYFR_REGISTRY_PASSWORD. A component in the local components/ directory is checked as 'cyfr validate' does first.\n\nWith --bump major, minor or patch, the next version is worked out from the versions already published (and those in components/), e.g. 1.3.0 for --bump minor when 1.2.4 is the highest. The component's version directory is copied to the new version's, its manifest and README are updated to it, and the new version is published. Without a version in the reference, the highest local version is copied.\n\nWith --dry-run, nothing is published, copied or changed. Instead the artifact's size and digest are shown, whether the version is already published, the metadata that differ from the latest published version, and the host policy the version would run under, which is the default for its type unless one is set.",
 	Example: `  cyfr publish r:local.sentiment:1.0.0
-  cyfr publish local.sentiment:1.0.0`,
+  cyfr publish local.sentiment:1.0.0
+  cyfr publish components/reagents/local/sentiment/1.0.0/reagent.wasm
+  cyfr publish c:ghcr.io/acme/sentiment:1.0.0 --artifact build/catalyst.wasm
+  cyfr publish r:local.sentiment --bump patch
+  cyfr publish r:local.sentiment --bump minor --dry-run`,
 	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		normalized := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("component", map[string]any{
-			"action":    "publish",
-			"reference": normalized,
-		})
+		normalized, err := normalizeComponentRef(args[0])
 		if err != nil {
-			output.Errorf("Publish failed: %v", err)
+			return err
+		}
+		registry, _ := cmd.Flags().GetString("registry")
+		if normalized, err = routeRegistry(normalized, registry); err != nil {
+			return err
+		}
+		part, _ := cmd.Flags().GetString("bump")
+		if part != "" && !slices.Contains(bumpParts, part) {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --bump %q (use major, minor, or patch)", part)
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if r, ok := registryRef(normalized); ok {
+			if part != "" {
+				return output.Error("--bump only works for components in the local components/ directory.")
+			}
+			artifact, _ := cmd.Flags().GetString("artifact")
+			if dryRun {
+				dir := filepath.Join("components", r.Type+"s", ociNamespace(r), r.Name, r.Version)
+				return printPublishPlan(planPublish(cmd.Context(), nil, r, dir, artifact))
+			}
+			result, err := publishOCI(cmd.Context(), r, artifact)
+			if err != nil {
+				return err
+			}
+			if flagJSON {
+				output.JSON(result)
+			} else {
+				output.KeyValue(result)
+			}
+			return nil
+		}
+		r, err := parseComponentRef(normalized)
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		dir := componentDir(r)
+		switch {
+		case part != "" && dryRun:
+			var from ref.ComponentRef
+			if from, r, err = bumpVersion(cmd.Context(), client, r, part); err != nil {
+				return err
+			}
+			dir = componentDir(from)
+		case part != "":
+			if r, err = bumpComponent(cmd.Context(), client, r, part); err != nil {
+				return err
+			}
+			normalized, dir = r.String(), componentDir(r)
+		}
+		if r.Type != "" && isDir(dir) {
+			if err := checkBeforeRegister(cmd, dir); err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			return printPublishPlan(planPublish(cmd.Context(), client, r, dir, ""))
+		}
+		toolArgs := typed.ComponentArgs{
+			Action:    typed.ComponentActionPublish,
+			Reference: normalized,
+		}
+		if artifact := filepath.Join(dir, r.Type+".wasm"); r.Type != "" && isDir(dir) {
+			signature, err := artifactSignature(artifact)
+			if err != nil {
+				return output.Errorf("Cannot publish %s: %v", r, err)
+			}
+			warnUnsigned(artifact, signature)
+			if signature != nil {
+				toolArgs.Signature = string(signature)
+			}
+		}
+		done := showProgress(client, "Publishing")
+		result, err := typed.New(client).Component(cmd.Context(), toolArgs)
+		done()
+		if err != nil {
+			return output.Errorf("Publish failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
 // normalizeComponentRef applies minimal CLI-level normalization to a
-// component reference. Full parsing and validation is done server-side
-// by Sanctum.ComponentRef.
-func normalizeComponentRef(s string) string {
+// component reference: aliases from the config are expanded, a path to a
+// component in the local layout becomes its reference, and "@" as the
+// version separator becomes ":", while a trailing "@sha256:..." digest pin
+// is kept. A reference without a type takes the project's default type.
+// Full parsing and validation is done server-side by
+// Sanctum.ComponentRef.
+func normalizeComponentRef(s string) (string, error) {
+	s, err := expandComponentRef(s)
+	if err != nil {
+		return "", err
+	}
+	return withDefaultType(s, ""), nil
+}
+
+// expandComponentRef is normalizeComponentRef without the project's
+// default type, for callers that apply a type of their own.
+func expandComponentRef(s string) (string, error) {
+	if expanded := expandAlias(s); expanded != s {
+		s = expanded
+	} else if r, ok, err := pathComponentRef(s); err != nil || ok {
+		return r.String(), err
+	}
+	s, digest := ref.SplitDigest(s)
 	if strings.Contains(s, "@") {
 		s = strings.Replace(s, "@", ":", 1)
 	}
-	return s
+	if digest != "" {
+		s += "@" + digest
+	}
+	return s, nil
+}
+
+// isComponentPath reports whether a reference argument is a filesystem
+// path rather than a reference: a .wasm file, a path starting with ./, ../
+// or /, or an existing directory.
+func isComponentPath(s string) bool {
+	if strings.HasSuffix(s, ".wasm") || strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || strings.HasPrefix(s, "/") {
+		return true
+	}
+	if !strings.Contains(s, "/") {
+		return false
+	}
+	info, err := os.Stat(s)
+	return err == nil && info.IsDir()
+}
+
+// pathComponentRef derives the reference of a component given by its path
+// (components/catalysts/local/claude/0.1.0/ or its catalyst.wasm). It
+// returns false if s isn't a path, and an error if it is one outside the
+// component layout.
+func pathComponentRef(s string) (ref.ComponentRef, bool, error) {
+	if !isComponentPath(s) {
+		return ref.ComponentRef{}, false, nil
+	}
+	r, err := ref.FromPath(s)
+	if err != nil {
+		return r, false, output.Errorf("%v", err)
+	}
+	return r, true, nil
 }
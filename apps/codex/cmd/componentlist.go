@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	componentListCmd.Flags().String("type", "", "Only list components of this type: catalyst, reagent or formula (or c, r, f)")
+	componentListCmd.Flags().String("namespace", "", "Only list components in this namespace, e.g. local")
+	componentListCmd.Flags().Bool("offline", false, "Don't ask the server which components are registered")
+	addColumnsFlag(componentListCmd)
+	componentCmd.AddCommand(componentListCmd)
+	rootCmd.AddCommand(componentCmd)
+}
+
+var componentCmd = &cobra.Command{
+	Use:     "component",
+	Short:   "Work with local and published components",
+	GroupID: "component",
+	Long:    "Work with the components in the project's components/ directory, and with the versions published to the registry.",
+}
+
+var componentListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the components in components/",
+	Long: `List the components in the project's components/<type>s/<namespace>/<name>/<version>/ directories with their reference, type, version, the size of their .wasm file ("-" if it isn't built) and whether the server of the current context has them registered ("unknown" if it can't be asked). --offline doesn't ask it.
+
+--type and --namespace narrow the list; --quiet prints only the references.`,
+	Example: `  cyfr component list
+  cyfr component ls --type catalyst
+  cyfr component list --namespace local --offline
+  cyfr component list --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		typ, _ := cmd.Flags().GetString("type")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		offline, _ := cmd.Flags().GetBool("offline")
+		if typ != "" {
+			typ = ref.ExpandType(typ)
+			if !ref.IsTypePrefix(typ) {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --type %q (use catalyst, reagent, or formula)", typ)
+			}
+		}
+
+		components := installedComponents(typ, namespace)
+		registered := map[string]string{}
+		if !offline && len(components) > 0 {
+			registered = registrationStatus(cmd.Context(), components)
+		}
+
+		items := make([]any, len(components))
+		for i, c := range components {
+			item := map[string]any{
+				"reference":  c.Ref.String(),
+				"type":       c.Ref.Type,
+				"namespace":  c.Ref.Namespace,
+				"name":       c.Ref.Name,
+				"version":    c.Ref.Version,
+				"path":       c.Dir,
+				"size":       c.Size,
+				"registered": registered[c.Ref.String()],
+			}
+			if offline {
+				delete(item, "registered")
+			}
+			if !flagJSON {
+				item["size"] = "-"
+				if c.Size >= 0 {
+					item["size"] = output.HumanBytes(c.Size)
+				}
+			}
+			items[i] = item
+		}
+		return printList(cmd, map[string]any{"installed": items}, "installed")
+	},
+}
+
+// An installedComponent is a component directory under components/.
+type installedComponent struct {
+	Ref  ref.ComponentRef
+	Dir  string
+	Size int64 // the size of its .wasm file, or -1 if it isn't built
+}
+
+// installedComponents returns the components under components/ of type
+// typ and in namespace, either of which may be "" for all, ordered by
+// type, namespace, name and version.
+func installedComponents(typ, namespace string) []installedComponent {
+	dirs, _ := filepath.Glob(filepath.Join("components", "*", "*", "*", "*"))
+	var components []installedComponent
+	for _, dir := range dirs {
+		if !isDir(dir) {
+			continue
+		}
+		r, err := ref.FromPath(dir)
+		if err != nil || typ != "" && r.Type != typ || namespace != "" && r.Namespace != namespace {
+			continue
+		}
+		c := installedComponent{Ref: r, Dir: dir, Size: -1}
+		if info, err := os.Stat(filepath.Join(dir, r.Type+".wasm")); err == nil {
+			c.Size = info.Size()
+		}
+		components = append(components, c)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		a, b := components[i].Ref, components[j].Ref
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return ref.Compare(a.Version, b.Version) < 0
+	})
+	return components
+}
+
+// registrationStatus asks the server of the current context which of the
+// components it has, returning "yes", "no" or, if it can't tell,
+// "unknown" by reference.
+func registrationStatus(ctx context.Context, components []installedComponent) map[string]string {
+	status := map[string]string{}
+	for _, c := range components {
+		status[c.Ref.String()] = "unknown"
+	}
+	client, err := newClient()
+	if err != nil {
+		return status
+	}
+	ctx, cancel := mcp.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	for _, c := range components {
+		registered, err := componentRegistered(ctx, client, c.Ref)
+		if err != nil {
+			// The server is unreachable or refuses: the rest won't do
+			// better.
+			return status
+		}
+		status[c.Ref.String()] = "no"
+		if registered {
+			status[c.Ref.String()] = "yes"
+		}
+	}
+	return status
+}
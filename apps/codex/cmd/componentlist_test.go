@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstalledComponents(t *testing.T) {
+	chdirTemp(t)
+	for _, dir := range []string{
+		"components/reagents/local/calc/0.10.0",
+		"components/reagents/local/calc/0.9.0",
+		"components/catalysts/acme/feeds/1.0.0",
+		"components/catalysts/local/web/0.1.0",
+		"components/notes/local/x/1.0.0", // not a type directory
+	} {
+		os.MkdirAll(dir, 0755)
+	}
+	os.WriteFile("components/catalysts/local/web/0.1.0/catalyst.wasm", []byte("1234"), 0644)
+
+	var refs []string
+	for _, c := range installedComponents("", "") {
+		refs = append(refs, c.Ref.String())
+	}
+	want := []string{"catalyst:acme.feeds:1.0.0", "catalyst:local.web:0.1.0", "reagent:local.calc:0.9.0", "reagent:local.calc:0.10.0"}
+	if len(refs) != len(want) {
+		t.Fatalf("components = %v, want %v", refs, want)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("components = %v, want %v", refs, want)
+			break
+		}
+	}
+
+	local := installedComponents("catalyst", "local")
+	if len(local) != 1 || local[0].Size != 4 || local[0].Dir != filepath.Join("components", "catalysts", "local", "web", "0.1.0") {
+		t.Errorf("local catalysts = %+v", local)
+	}
+	if c := installedComponents("reagent", ""); len(c) != 2 || c[0].Size != -1 {
+		t.Errorf("reagents = %+v", c)
+	}
+}
+
+func TestComponentListQuiet(t *testing.T) {
+	chdirTemp(t)
+	os.MkdirAll("components/formulas/local/f/1.0.0", 0755)
+	os.MkdirAll("components/reagents/local/r/1.0.0", 0755)
+	flagQuiet = true
+	componentListCmd.Flags().Set("type", "f")
+	componentListCmd.Flags().Set("offline", "true")
+	t.Cleanup(func() {
+		flagQuiet = false
+		componentListCmd.Flags().Set("type", "")
+		componentListCmd.Flags().Set("offline", "false")
+	})
+	componentListCmd.SetContext(context.Background())
+
+	var runErr error
+	out, err := captureStdout(func() { runErr = componentListCmd.RunE(componentListCmd, nil) })
+	if err != nil || runErr != nil {
+		t.Fatal(err, runErr)
+	}
+	if out != "formula:local.f:1.0.0\n" {
+		t.Errorf("output = %q", out)
+	}
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+// defaultUpdateInterval is how often component watch asks the registry
+// for new versions unless --interval says otherwise.
+const defaultUpdateInterval = 5 * time.Minute
+
+func init() {
+	componentWatchCmd.Flags().Duration("interval", defaultUpdateInterval, "How often to ask the registry for new versions")
+	componentWatchCmd.Flags().Bool("events", false, "Also check whenever the server sends a notification naming the component")
+	componentWatchCmd.Flags().String("webhook", "", "POST each new version as JSON to this URL")
+	componentWatchCmd.Flags().Bool("desktop", false, "Show a desktop notification for each new version")
+	componentCmd.AddCommand(componentWatchCmd)
+}
+
+var componentWatchCmd = &cobra.Command{
+	Use:   "watch [type] <component>",
+	Short: "Report new versions of a component as they are published",
+	Long: `Watch a component in the registry and report each version published after the watch starts, until interrupted. The registry is asked every --interval; with --events, the server's notification stream is followed too, and a notification naming the component triggers a check at once. Yanked versions aren't reported.
+
+A new version is printed to stdout, as a line of JSON with --json. --webhook also POSTs it as JSON to a URL, such as a Slack or CI trigger, and --desktop shows it as a desktop notification (notify-send on Linux, osascript on macOS). A failed check or delivery is reported on stderr and the watch goes on.`,
+	Example: `  cyfr component watch c:acme.sentiment
+  cyfr component watch c:acme.sentiment --interval 1m --desktop
+  cyfr component watch c:acme.sentiment --events --webhook https://hooks.example.com/cyfr`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		events, _ := cmd.Flags().GetBool("events")
+		webhook, _ := cmd.Flags().GetString("webhook")
+		desktop, _ := cmd.Flags().GetBool("desktop")
+		if interval <= 0 {
+			return output.NewError(output.CodeInvalidArgument, "--interval must be positive")
+		}
+		if webhook != "" && !strings.HasPrefix(webhook, "https://") && !strings.HasPrefix(webhook, "http://") {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --webhook %q: give an http(s) URL", webhook)
+		}
+		if desktop {
+			if err := checkDesktopNotifier(); err != nil {
+				return err
+			}
+		}
+
+		raw := joinTypeShorthand(args)[0]
+		normalized, err := normalizeComponentRef(raw)
+		if err != nil {
+			return err
+		}
+		r, err := parseComponentRef(normalized)
+		if err != nil {
+			return err
+		}
+		if r.Version != "latest" || r.Digest != "" {
+			return output.NewError(output.CodeInvalidArgument, "Cannot watch %s: give the component without a version, e.g. %s", raw, componentName(r))
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		return watchComponent(cmd.Context(), client, r, interval, events, updateNotifier{webhook: webhook, desktop: desktop})
+	},
+}
+
+// A publishedUpdate is a new version component watch reports.
+type publishedUpdate struct {
+	Event       string `json:"event"` // always component.published
+	Component   string `json:"component"`
+	Reference   string `json:"reference"`
+	Version     string `json:"version"`
+	PublishedAt string `json:"published_at,omitempty"`
+}
+
+// watchComponent reports the versions of r's component published after
+// it starts, checking every interval and, with events, on each server
+// notification naming the component, until ctx is cancelled.
+func watchComponent(ctx context.Context, client *mcp.Client, r ref.ComponentRef, interval time.Duration, events bool, notify updateNotifier) error {
+	known := map[string]bool{}
+	current, err := newVersions(ctx, client, r, known)
+	if err != nil {
+		if isInterrupted(err) {
+			return nil
+		}
+		return output.Errorf("Cannot list versions of %s: %v", componentName(r), err)
+	}
+	var versions []string
+	for _, u := range current {
+		versions = append(versions, u.Version)
+	}
+	latest, ok := ref.Latest(versions)
+	if !ok {
+		latest = "none yet"
+	}
+	fmt.Fprintf(os.Stderr, "Watching %s for new versions (latest: %s), checking every %s. Press Ctrl-C to stop.\n", componentName(r), latest, interval)
+
+	var notifications <-chan mcp.Notification
+	if events {
+		sub, err := client.SubscribeCtx(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot follow server events, polling only: %v\n", err)
+		} else {
+			defer sub.Close()
+			notifications = sub.C
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	name := r.Namespace + "." + r.Name
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case n, ok := <-notifications:
+			if !ok {
+				fmt.Fprintln(os.Stderr, "Warning: the server's event stream ended, polling only")
+				notifications = nil
+				continue
+			}
+			if b, _ := json.Marshal(n.Params); !strings.Contains(string(b), name) {
+				continue
+			}
+		}
+		updates, err := newVersions(ctx, client, r, known)
+		if err != nil {
+			if isInterrupted(err) {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Warning: cannot check %s: %v\n", componentName(r), err)
+			continue
+		}
+		for _, u := range updates {
+			notify.send(ctx, u)
+		}
+	}
+}
+
+// newVersions returns the versions of r's component published in the
+// registry that aren't in known, lowest first, and adds them to it.
+func newVersions(ctx context.Context, client *mcp.Client, r ref.ComponentRef, known map[string]bool) ([]publishedUpdate, error) {
+	var history []versionEntry
+	if r.Registry != "" {
+		tags, err := ociTags(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			history = append(history, versionEntry{Version: tag})
+		}
+	} else {
+		var err error
+		if history, err = versionHistory(ctx, client, r); err != nil {
+			return nil, err
+		}
+	}
+	var updates []publishedUpdate
+	for _, e := range history {
+		if e.Yanked || known[e.Version] {
+			continue
+		}
+		known[e.Version] = true
+		u := publishedUpdate{Event: "component.published", Component: componentName(r), Version: e.Version, PublishedAt: e.PublishedAt}
+		u.Reference = u.Component + ":" + e.Version
+		updates = append(updates, u)
+	}
+	sort.Slice(updates, func(i, j int) bool { return ref.Compare(updates[i].Version, updates[j].Version) < 0 })
+	return updates, nil
+}
+
+// An updateNotifier delivers the new versions component watch finds.
+type updateNotifier struct {
+	webhook string
+	desktop bool
+}
+
+// send reports u on stdout and to the webhook and desktop, if set. A
+// failed delivery is a warning on stderr.
+func (n updateNotifier) send(ctx context.Context, u publishedUpdate) {
+	message := fmt.Sprintf("New version of %s: %s", u.Component, u.Version)
+	if flagJSON {
+		b, _ := json.Marshal(u)
+		fmt.Println(string(b))
+	} else {
+		fmt.Println(message)
+	}
+	if n.webhook != "" {
+		if err := postWebhook(ctx, n.webhook, u); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: webhook: %v\n", err)
+		}
+	}
+	if n.desktop {
+		if err := desktopNotification(message); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: desktop notification: %v\n", err)
+		}
+	}
+}
+
+// postWebhook POSTs u as JSON to url.
+func postWebhook(ctx context.Context, url string, u publishedUpdate) error {
+	body, _ := json.Marshal(u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// desktopNotifier returns the command that shows desktop notifications on
+// this OS, or "" if there is none.
+func desktopNotifier() string {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		return "notify-send"
+	case "darwin":
+		return "osascript"
+	}
+	return ""
+}
+
+// checkDesktopNotifier fails if desktop notifications can't be shown.
+func checkDesktopNotifier() error {
+	bin := desktopNotifier()
+	if bin == "" {
+		return output.NewError(output.CodeUnsupported, "--desktop isn't supported on %s", runtime.GOOS)
+	}
+	if _, err := exec.LookPath(bin); err != nil {
+		return output.NewError(output.CodeUnsupported, "--desktop needs %s, which isn't installed", bin)
+	}
+	return nil
+}
+
+// desktopNotification shows message as a desktop notification.
+func desktopNotification(message string) error {
+	var c *exec.Cmd
+	switch bin := desktopNotifier(); bin {
+	case "osascript":
+		c = exec.Command(bin, "-e", fmt.Sprintf("display notification %q with title \"cyfr\"", message))
+	default:
+		c = exec.Command(bin, "cyfr", message)
+	}
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
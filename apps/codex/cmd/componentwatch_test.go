@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+func TestNewVersions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	hello := func(version string, yanked bool) any {
+		return map[string]any{"name": "hello", "publisher": "local", "type": "reagent", "version": version, "yanked": yanked}
+	}
+	f.Responses["component.search"] = map[string]any{"components": []any{hello("0.1.0", false)}}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+	r, _ := ref.Parse("r:local.hello")
+
+	known := map[string]bool{}
+	if updates, err := newVersions(context.Background(), client, r, known); err != nil || len(updates) != 1 {
+		t.Fatalf("first check = %v, %v", updates, err)
+	}
+	if updates, _ := newVersions(context.Background(), client, r, known); len(updates) != 0 {
+		t.Errorf("unchanged registry reported %v", updates)
+	}
+
+	f.Responses["component.search"] = map[string]any{"components": []any{
+		hello("0.1.0", false), hello("0.10.0", false), hello("0.2.0", false), hello("0.3.0", true),
+	}}
+	updates, err := newVersions(context.Background(), client, r, known)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, u := range updates {
+		got = append(got, u.Reference)
+	}
+	if strings.Join(got, ",") != "reagent:local.hello:0.2.0,reagent:local.hello:0.10.0" {
+		t.Errorf("updates = %v", got)
+	}
+}
+
+func TestUpdateNotifier_Webhook(t *testing.T) {
+	var received publishedUpdate
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		json.NewDecoder(req.Body).Decode(&received)
+	}))
+	defer hook.Close()
+
+	flagJSON = false
+	u := publishedUpdate{Event: "component.published", Component: "catalyst:acme.sentiment", Reference: "catalyst:acme.sentiment:1.5.0", Version: "1.5.0"}
+	out, _ := captureStdout(func() { updateNotifier{webhook: hook.URL}.send(context.Background(), u) })
+	if out != "New version of catalyst:acme.sentiment: 1.5.0\n" {
+		t.Errorf("output = %q", out)
+	}
+	if received != u {
+		t.Errorf("webhook received %+v", received)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -28,27 +29,34 @@ var configSetCmd = &cobra.Command{
 	Example: `  cyfr config set c:local.claude:0.1.0 model claude-sonnet-4-5-20250929
   cyfr config set c local.claude:0.1.0 timeout 30`,
 	Args: cobra.RangeArgs(3, 4),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		componentRef := normalizeComponentRef(args[0])
+		componentRef, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
 		key := args[1]
 		value := args[2]
 
-		client := newClient()
-		result, err := client.CallTool("config", map[string]any{
-			"action":        "set",
-			"component_ref": componentRef,
-			"key":           key,
-			"value":         value,
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Config(cmd.Context(), typed.ConfigArgs{
+			Action:       typed.ConfigActionSet,
+			ComponentRef: componentRef,
+			Key:          key,
+			Value:        value,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Printf("Config '%s' set for %s.\n", key, componentRef)
 		}
+		return nil
 	},
 }
 
@@ -59,16 +67,22 @@ var configShowCmd = &cobra.Command{
 	Example: `  cyfr config show c:local.claude:0.1.0
   cyfr config show acme.sentiment:1.0.0`,
 	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		componentRef := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("config", map[string]any{
-			"action":        "get_all",
-			"component_ref": componentRef,
+		componentRef, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Config(cmd.Context(), typed.ConfigArgs{
+			Action:       typed.ConfigActionGetAll,
+			ComponentRef: componentRef,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
@@ -80,5 +94,6 @@ var configShowCmd = &cobra.Command{
 				output.KeyValue(result)
 			}
 		}
+		return nil
 	},
 }
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// addContentFlags registers the flags controlling how non-text content in a
+// tool result (images, audio, resources) is handled.
+func addContentFlags(cmd *cobra.Command) {
+	cmd.Flags().String("output-dir", ".", "Directory to save images, audio and binary resources returned by the tool")
+	cmd.Flags().Bool("fetch-resources", false, "Fetch resources the tool links to instead of just printing their URIs")
+}
+
+// contentOptions controls how extra content blocks are handled.
+type contentOptions struct {
+	// Dir is where binary content is saved.
+	Dir string
+	// FetchResources reads linked resources and saves or embeds them.
+	FetchResources bool
+	// Prefix names saved files that have no name of their own.
+	Prefix string
+}
+
+// contentOptionsFromFlags reads the flags added by addContentFlags.
+func contentOptionsFromFlags(cmd *cobra.Command, prefix string) contentOptions {
+	dir, _ := cmd.Flags().GetString("output-dir")
+	fetch, _ := cmd.Flags().GetBool("fetch-resources")
+	return contentOptions{Dir: dir, FetchResources: fetch, Prefix: prefix}
+}
+
+// prepareContentJSON readies a result's extra content for --json output:
+// blocks stay inline with their base64 data, and with --fetch-resources
+// each resource link is replaced by the embedded resource it points to.
+func prepareContentJSON(ctx context.Context, client *mcp.Client, result map[string]any, opts contentOptions) {
+	blocks, _ := result[mcp.ExtraContentKey].([]mcp.ContentBlock)
+	if !opts.FetchResources || len(blocks) == 0 {
+		return
+	}
+	var out []mcp.ContentBlock
+	for _, b := range blocks {
+		if b.Type != "resource_link" {
+			out = append(out, b)
+			continue
+		}
+		contents, err := client.ReadResourceCtx(ctx, b.URI)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s: %v\n", b.URI, err)
+			out = append(out, b)
+			continue
+		}
+		for i := range contents {
+			out = append(out, mcp.ContentBlock{Type: "resource", Resource: &contents[i]})
+		}
+	}
+	result[mcp.ExtraContentKey] = out
+}
+
+// printExtraContent prints or saves the extra content blocks of a result
+// for human-readable output: text is printed, images, audio and binary
+// resources are written to files in opts.Dir, and resource links are
+// listed (or fetched with opts.FetchResources).
+func printExtraContent(ctx context.Context, client *mcp.Client, blocks []mcp.ContentBlock, opts contentOptions) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	fmt.Println("")
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			fmt.Println(b.Text)
+		case "image", "audio":
+			data, err := base64.StdEncoding.DecodeString(b.Data)
+			if err != nil {
+				return output.Errorf("Failed to decode %s content: %v", b.Type, err)
+			}
+			if err := saveContent(opts, b.Type, "", b.MimeType, data); err != nil {
+				return err
+			}
+		case "resource":
+			if err := printResourceContents(opts, b.Resource); err != nil {
+				return err
+			}
+		case "resource_link":
+			if !opts.FetchResources {
+				fmt.Printf("Resource: %s\n", resourceLinkLabel(b))
+				continue
+			}
+			contents, err := client.ReadResourceCtx(ctx, b.URI)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to fetch %s: %v\n", b.URI, err)
+				continue
+			}
+			for i := range contents {
+				if err := printResourceContents(opts, &contents[i]); err != nil {
+					return err
+				}
+			}
+		default:
+			fmt.Printf("(%s content not shown; use --json to see it)\n", b.Type)
+		}
+	}
+	return nil
+}
+
+// printResourceContents prints a text resource or saves a binary one.
+func printResourceContents(opts contentOptions, c *mcp.ResourceContents) error {
+	if c == nil {
+		return nil
+	}
+	if c.Blob == "" {
+		fmt.Printf("Resource %s:\n%s\n", c.URI, formatResourceText(*c))
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(c.Blob)
+	if err != nil {
+		return output.Errorf("Failed to decode resource %s: %v", c.URI, err)
+	}
+	return saveContent(opts, "resource", resourceFileName(c.URI), c.MimeType, data)
+}
+
+// saveContent writes binary content to a new file in opts.Dir and reports
+// where it went. Existing files are never overwritten.
+func saveContent(opts contentOptions, kind, name, mimeType string, data []byte) error {
+	dir := opts.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return output.Errorf("Failed to create %s: %v", dir, err)
+	}
+	p, err := createUnique(dir, name, opts.Prefix, extensionFor(mimeType), data)
+	if err != nil {
+		return output.Errorf("Failed to save %s: %v", kind, err)
+	}
+	label := kind
+	if mimeType != "" {
+		label += " (" + mimeType + ")"
+	}
+	fmt.Printf("Saved %s, %s, to %s\n", label, output.HumanBytes(int64(len(data))), p)
+	return nil
+}
+
+// createUnique writes data to dir/name, or to dir/prefix-N+ext for the
+// first free N when name is empty or already taken.
+func createUnique(dir, name, prefix, ext string, data []byte) (string, error) {
+	if prefix == "" {
+		prefix = "content"
+	}
+	candidates := func(n int) string {
+		if name != "" && n == 0 {
+			return name
+		}
+		if name != "" {
+			base := strings.TrimSuffix(name, filepath.Ext(name))
+			return fmt.Sprintf("%s-%d%s", base, n, filepath.Ext(name))
+		}
+		return fmt.Sprintf("%s-%d%s", prefix, n+1, ext)
+	}
+	for n := 0; n < 1000; n++ {
+		p := filepath.Join(dir, candidates(n))
+		f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return "", err
+		}
+		return p, f.Close()
+	}
+	return "", fmt.Errorf("no free file name in %s", dir)
+}
+
+// extensionFor returns a file extension for a MIME type, or ".bin".
+func extensionFor(mimeType string) string {
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/wav", "audio/x-wav":
+		return ".wav"
+	case "application/wasm":
+		return ".wasm"
+	}
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// resourceFileName derives a file name from the last path segment of a
+// resource URI, or "" if it has none.
+func resourceFileName(uri string) string {
+	_, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		rest = uri
+	}
+	base := path.Base(rest)
+	if base == "." || base == "/" || strings.ContainsAny(base, `\:`) {
+		return ""
+	}
+	return base
+}
+
+// resourceLinkLabel describes a resource link on one line.
+func resourceLinkLabel(b mcp.ContentBlock) string {
+	label := b.URI
+	if b.Name != "" {
+		label += "  " + b.Name
+	}
+	if b.MimeType != "" {
+		label += "  (" + b.MimeType + ")"
+	}
+	return label
+}
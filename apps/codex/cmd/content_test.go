@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateUnique(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name, file, prefix, ext string
+		want                    string
+	}{
+		{"prefix", "", "output", ".png", "output-1.png"},
+		{"prefix taken", "", "output", ".png", "output-2.png"},
+		{"named", "report.csv", "output", ".csv", "report.csv"},
+		{"named taken", "report.csv", "output", ".csv", "report-1.csv"},
+		{"default prefix", "", "", ".bin", "content-1.bin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := createUnique(dir, tt.file, tt.prefix, tt.ext, []byte(tt.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filepath.Base(p); got != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+			data, err := os.ReadFile(p)
+			if err != nil || string(data) != tt.name {
+				t.Errorf("contents = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestResourceFileName(t *testing.T) {
+	tests := []struct {
+		uri, want string
+	}{
+		{"arca://artifacts/chart.png", "chart.png"},
+		{"opus://executions/exec_1/logs", "logs"},
+		{"arca://report.csv", "report.csv"},
+		{"arca://", ""},
+		{"file:///tmp/", "tmp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			if got := resourceFileName(tt.uri); got != tt.want {
+				t.Errorf("resourceFileName(%q) = %q, want %q", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtensionFor(t *testing.T) {
+	tests := map[string]string{
+		"image/png":              ".png",
+		"image/jpeg":             ".jpg",
+		"audio/wav":              ".wav",
+		"application/wasm":       ".wasm",
+		"application/x-whatever": ".bin",
+		"":                       ".bin",
+	}
+	for mimeType, want := range tests {
+		if got := extensionFor(mimeType); got != want {
+			t.Errorf("extensionFor(%q) = %q, want %q", mimeType, got, want)
+		}
+	}
+}
@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/cosign"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -13,38 +21,60 @@ func init() {
 	contextCmd.AddCommand(contextListCmd)
 	contextCmd.AddCommand(contextSetCmd)
 	contextCmd.AddCommand(contextAddCmd)
+	contextCmd.AddCommand(contextRemoveCmd)
+	contextCmd.AddCommand(contextRenameCmd)
+	contextCmd.AddCommand(contextShowCmd)
+
+	contextRemoveCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+
+	contextAddCmd.Flags().Duration("connect-timeout", 0, "Timeout for connecting to the server (default 10s)")
+	contextAddCmd.Flags().Duration("request-timeout", 0, "Timeout for a whole request, including long executions (default 10m)")
+	contextAddCmd.Flags().Int("max-idle-conns", 0, "Keep-alive connections to keep open (default 4)")
+	contextAddCmd.Flags().String("ca-cert", "", "PEM bundle of CA certificates to trust for this server")
+	contextAddCmd.Flags().String("client-cert", "", "PEM client certificate for mutual TLS")
+	contextAddCmd.Flags().String("client-key", "", "PEM private key for --client-cert")
+	contextAddCmd.Flags().Bool("insecure-skip-verify", false, "Don't verify the server certificate (testing only)")
+	contextAddCmd.Flags().String("transport", "", "How to reach the server: http (default) or websocket")
+	contextAddCmd.Flags().String("verify-signatures", "", "Check component signatures before pull and run: enforce, warn or off (default off)")
+	contextAddCmd.Flags().StringArray("trusted-key", nil, "PEM public key whose signatures are trusted (repeatable)")
+	contextAddCmd.Flags().StringArray("default", nil, "Default for a flag with this context, as name=value (repeatable), e.g. json=true or default_type=catalyst")
 }
 
 var contextCmd = &cobra.Command{
 	Use:     "context",
 	Short:   "Manage server connections (local only)",
 	GroupID: "advanced",
-	Long:    "Add, list, and switch between CYFR server connections. Use contexts to manage multiple instances (e.g. local, staging, production) from a single CLI installation.",
+	Long:    "Add, list, and switch between CYFR server connections. Use contexts to manage multiple instances (e.g. local, staging, production) from a single CLI installation.\n\nSession IDs, API keys and OAuth tokens are kept in ~/.cyfr/config.json. Set \"credential_store\": \"keyring\" in that file to keep them in the OS keyring (macOS Keychain, Secret Service, or Windows Credential Manager) instead; existing credentials are moved on the next command, and back again if it is set to \"file\".",
 }
 
 var contextListCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "Show all contexts",
-	Long:    "Show all configured server contexts. The active context is marked with an asterisk (*).",
+	Long:    "Show all configured server contexts. The active context, after --context, CYFR_CONTEXT and the project config are applied, is marked with an asterisk (*).",
 	Example: "  cyfr context list",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load()
 		if err != nil {
-			output.Errorf("Failed to load config: %v", err)
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
 		}
 
 		if flagJSON {
-			output.JSON(cfg)
-			return
+			output.JSON(redactedConfig(cfg))
+			return nil
 		}
 
+		active := cfg.CurrentContext
+		if name := contextOverride(); name != "" {
+			active = name
+		}
 		for name, ctx := range cfg.Contexts {
 			marker := "  "
-			if name == cfg.CurrentContext {
+			if name == active {
 				marker = "* "
 			}
 			fmt.Printf("%s%-15s %s\n", marker, name, ctx.URL)
 		}
+		return nil
 	},
 }
 
@@ -54,49 +84,413 @@ var contextSetCmd = &cobra.Command{
 	Long:    "Set the named context as the active server connection for all subsequent commands.",
 	Example: "  cyfr context set production",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 
 		cfg, err := config.Load()
 		if err != nil {
-			output.Errorf("Failed to load config: %v", err)
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
 		}
 
 		if _, ok := cfg.Contexts[name]; !ok {
-			output.Errorf("Context '%s' not found. Use 'cyfr context add' first.", name)
+			return output.NewError(output.CodeNotFound, "Context '%s' not found. Use 'cyfr context add' first.", name)
 		}
 
 		cfg.CurrentContext = name
 		if err := cfg.Save(); err != nil {
-			output.Errorf("Failed to save config: %v", err)
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
 		}
 
 		fmt.Printf("Switched to context '%s' (%s)\n", name, cfg.Contexts[name].URL)
+		return nil
 	},
 }
 
 var contextAddCmd = &cobra.Command{
 	Use:   "add <name> <url>",
 	Short: "Add a new server connection",
-	Long:  "Register a new CYFR server connection by name and URL.",
+	Long: `Register a new CYFR server connection by name and URL.
+
+Timeouts and keep-alive can be tuned per context; a --timeout flag on any
+command overrides the request timeout for that invocation. Proxies are
+taken from HTTPS_PROXY, HTTP_PROXY, and NO_PROXY.
+
+For servers behind an internal CA or requiring mutual TLS, pass --ca-cert
+and/or --client-cert with --client-key. Certificate paths are stored as
+absolute paths and checked when the context is added.
+
+With --transport websocket, commands talk to the server over one
+persistent WebSocket connection instead of an HTTP request per call, which
+cuts latency for interactive use.
+
+With --verify-signatures enforce, pull and run refuse components whose
+digest or signature doesn't check out against the --trusted-key keys; warn
+only warns. See 'cyfr verify'.
+
+With --default, a context sets defaults for flags not given on the command
+line, e.g. --default json=true --default timeout=60s for production, and
+default_type for component references without a type. A project's
+.cyfr/config.json takes precedence over them.`,
 	Example: `  cyfr context add local http://localhost:4000
   cyfr context add cloud https://cyfr.example.com
-  cyfr context add enterprise https://cyfr.corp.internal:4000`,
+  cyfr context add enterprise https://cyfr.corp.internal:4000
+  cyfr context add batch https://cyfr.example.com --request-timeout 1h
+  cyfr context add corp https://cyfr.corp.internal --ca-cert corp-ca.pem \
+    --client-cert me.pem --client-key me-key.pem
+  cyfr context add live http://localhost:4000 --transport websocket
+  cyfr context add prod https://cyfr.example.com --default json=true --default timeout=60s
+  cyfr context add corp https://cyfr.corp.internal --verify-signatures enforce --trusted-key corp.pub`,
 	Args: cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		url := args[1]
 
 		cfg, err := config.Load()
 		if err != nil {
-			output.Errorf("Failed to load config: %v", err)
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+
+		connectTimeout, _ := cmd.Flags().GetDuration("connect-timeout")
+		requestTimeout, _ := cmd.Flags().GetDuration("request-timeout")
+		maxIdleConns, _ := cmd.Flags().GetInt("max-idle-conns")
+
+		ctx := &config.Context{URL: url, MaxIdleConns: maxIdleConns}
+		if connectTimeout > 0 {
+			ctx.ConnectTimeout = connectTimeout.String()
+		}
+		if requestTimeout > 0 {
+			ctx.RequestTimeout = requestTimeout.String()
+		}
+		for flag, path := range map[string]*string{"ca-cert": &ctx.CACert, "client-cert": &ctx.ClientCert, "client-key": &ctx.ClientKey} {
+			if *path, err = absFlagPath(cmd, flag); err != nil {
+				return err
+			}
+		}
+		ctx.InsecureSkipVerify, _ = cmd.Flags().GetBool("insecure-skip-verify")
+		if _, err := mcp.LoadTLSConfig(ctx.CACert, ctx.ClientCert, ctx.ClientKey, ctx.InsecureSkipVerify); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Invalid TLS settings: %v", err)
+		}
+		ctx.Transport, _ = cmd.Flags().GetString("transport")
+		if !validTransport(ctx.Transport) {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --transport %q (use http or websocket)", ctx.Transport)
+		}
+		if ctx.InsecureSkipVerify {
+			fmt.Fprintln(os.Stderr, "Warning: server certificates will not be verified for this context.")
+		}
+		ctx.VerifySignatures, _ = cmd.Flags().GetString("verify-signatures")
+		switch ctx.VerifySignatures {
+		case "", verifyEnforce, verifyWarn, verifyOff:
+		default:
+			return output.NewError(output.CodeInvalidArgument, "Invalid --verify-signatures %q (use enforce, warn or off)", ctx.VerifySignatures)
+		}
+		keys, _ := cmd.Flags().GetStringArray("trusted-key")
+		for _, key := range keys {
+			abs, err := filepath.Abs(key)
+			if err != nil {
+				return output.Errorf("Invalid --trusted-key %s: %v", key, err)
+			}
+			data, err := os.ReadFile(abs)
+			if err == nil {
+				_, err = cosign.ParsePublicKey(data)
+			}
+			if err != nil {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --trusted-key %s: %v", key, err)
+			}
+			ctx.TrustedKeys = append(ctx.TrustedKeys, abs)
+		}
+		if ctx.VerifySignatures == verifyEnforce && len(ctx.TrustedKeys) == 0 {
+			fmt.Fprintln(os.Stderr, "Warning: with no --trusted-key, every component will be refused.")
+		}
+		defaults, _ := cmd.Flags().GetStringArray("default")
+		if ctx.Defaults, err = parseContextDefaults(defaults); err != nil {
+			return err
 		}
 
-		cfg.Contexts[name] = &config.Context{URL: url}
+		cfg.Contexts[name] = ctx
 		if err := cfg.Save(); err != nil {
-			output.Errorf("Failed to save config: %v", err)
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
 		}
 
 		fmt.Printf("Added context '%s' (%s)\n", name, url)
+		return nil
+	},
+}
+
+var contextRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a server connection",
+	Long: `Remove a context after asking for confirmation (skip it with --yes). A
+session the context holds is logged out on the server first, and its stored
+credentials are deleted. Removing the active context switches to "local",
+or to no context if that's the one being removed.`,
+	Example: `  cyfr context remove staging
+  cyfr context remove staging --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		ctx, ok := cfg.Contexts[name]
+		if !ok {
+			return output.NewError(output.CodeNotFound, "Context '%s' not found.", name)
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes && !confirm(fmt.Sprintf("Remove context '%s' (%s)?", name, ctx.URL)) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+
+		// Logging out is best effort: the server may be gone for good,
+		// which is often why the context is being removed.
+		if ctx.SessionID != "" {
+			if client, err := clientForContext(cfg, name); err == nil {
+				client.OnTokenRefresh = nil
+				_, _ = typed.New(client).Session(cmd.Context(), typed.SessionArgs{
+					Action: typed.SessionActionLogout,
+				})
+			}
+		}
+
+		delete(cfg.Contexts, name)
+		switched := ""
+		if cfg.CurrentContext == name {
+			cfg.CurrentContext = ""
+			if _, ok := cfg.Contexts["local"]; ok {
+				cfg.CurrentContext = "local"
+			}
+			switched = cfg.CurrentContext
+		}
+		if err := cfg.Save(); err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+
+		fmt.Printf("Removed context '%s'\n", name)
+		if cfg.CurrentContext == "" {
+			fmt.Println("No active context. Use 'cyfr context set' to choose one.")
+		} else if switched != "" {
+			fmt.Printf("Switched to context '%s' (%s)\n", switched, cfg.Contexts[switched].URL)
+		}
+		return nil
+	},
+}
+
+var contextRenameCmd = &cobra.Command{
+	Use:     "rename <old> <new>",
+	Short:   "Rename a server connection",
+	Long:    "Rename a context, keeping its URL, settings and credentials. If it is the active context, it stays active under the new name.",
+	Example: "  cyfr context rename cloud production",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldName, newName := args[0], args[1]
+
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		ctx, ok := cfg.Contexts[oldName]
+		if !ok {
+			return output.NewError(output.CodeNotFound, "Context '%s' not found.", oldName)
+		}
+		if _, exists := cfg.Contexts[newName]; exists {
+			return output.Errorf("Context '%s' already exists.", newName)
+		}
+
+		delete(cfg.Contexts, oldName)
+		cfg.Contexts[newName] = ctx
+		if cfg.CurrentContext == oldName {
+			cfg.CurrentContext = newName
+		}
+		if err := cfg.Save(); err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+
+		fmt.Printf("Renamed context '%s' to '%s'\n", oldName, newName)
+		return nil
+	},
+}
+
+var contextShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show one server connection",
+	Long: `Show the full details of a context, by default the active one: its URL,
+connection settings, where its credentials are kept, and how it
+authenticates. Secrets are masked.`,
+	Example: `  cyfr context show
+  cyfr context show staging --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		active := cfg.CurrentContext
+		if name := contextOverride(); name != "" {
+			active = name
+		}
+		name := active
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if _, ok := cfg.Contexts[name]; !ok {
+			return output.NewError(output.CodeNotFound, "Context '%s' not found.", name)
+		}
+
+		ctx := redactedConfig(cfg).Contexts[name]
+		if flagJSON {
+			output.JSON(map[string]any{
+				"name":    name,
+				"current": name == active,
+				"auth":    authState(cfg.Contexts[name]),
+				"context": ctx,
+			})
+			return nil
+		}
+
+		details := map[string]any{
+			"name":    name,
+			"url":     ctx.URL,
+			"current": name == active,
+			"auth":    authState(cfg.Contexts[name]),
+		}
+		store := cfg.CredentialStore
+		if ctx.Credentials != "" {
+			store = ctx.Credentials
+		}
+		if store == "" {
+			store = config.CredentialStoreFile
+		}
+		details["credential_store"] = store
+		optional := map[string]string{
+			"transport":         ctx.Transport,
+			"verify_signatures": ctx.VerifySignatures,
+			"connect_timeout":   ctx.ConnectTimeout,
+			"request_timeout":   ctx.RequestTimeout,
+			"ca_cert":           ctx.CACert,
+			"client_cert":       ctx.ClientCert,
+			"client_key":        ctx.ClientKey,
+		}
+		for k, v := range optional {
+			if v != "" {
+				details[k] = v
+			}
+		}
+		if ctx.MaxIdleConns > 0 {
+			details["max_idle_conns"] = ctx.MaxIdleConns
+		}
+		if len(ctx.TrustedKeys) > 0 {
+			details["trusted_keys"] = ctx.TrustedKeys
+		}
+		if len(ctx.Defaults) > 0 {
+			details["defaults"] = ctx.Defaults
+		}
+		if ctx.InsecureSkipVerify {
+			details["insecure_skip_verify"] = true
+		}
+		if ctx.Server != nil {
+			if server := strings.TrimSpace(ctx.Server.Name + " " + ctx.Server.Version); server != "" {
+				details["server"] = server
+			}
+			details["protocol_version"] = ctx.Server.ProtocolVersion
+		}
+		output.KeyValue(details)
+		return nil
 	},
 }
+
+// authState describes how a context authenticates: "api key", "oauth"
+// (with its expiry), "session", or "none".
+func authState(ctx *config.Context) string {
+	switch {
+	case ctx.APIKey != "":
+		return "api key"
+	case ctx.OAuth != nil && ctx.OAuth.Expiry != "":
+		if expiry, err := time.Parse(time.RFC3339, ctx.OAuth.Expiry); err == nil && time.Now().After(expiry) {
+			if ctx.OAuth.RefreshToken != "" {
+				return "oauth (expired, refreshable)"
+			}
+			return "oauth (expired)"
+		}
+		return "oauth (expires " + ctx.OAuth.Expiry + ")"
+	case ctx.OAuth != nil:
+		return "oauth"
+	case ctx.SessionID != "":
+		return "session"
+	}
+	return "none"
+}
+
+// confirm asks a yes/no question on stderr and reports whether the answer
+// read from stdin was yes.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+// parseContextDefaults parses --default name=value pairs into a context's
+// defaults, keeping booleans as JSON booleans.
+func parseContextDefaults(pairs []string) (map[string]any, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	defaults := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "--")
+		if !ok || name == "" {
+			return nil, output.NewError(output.CodeInvalidArgument, "Invalid --default %q (use name=value)", pair)
+		}
+		switch value {
+		case "true", "false":
+			defaults[name] = value == "true"
+		default:
+			defaults[name] = value
+		}
+	}
+	return defaults, nil
+}
+
+// validTransport reports whether t is a supported context transport.
+func validTransport(t string) bool {
+	return t == "" || t == transportHTTP || t == transportWebSocket
+}
+
+// absFlagPath returns a path flag's value as an absolute path, so the
+// stored context works from any directory. Empty stays empty.
+func absFlagPath(cmd *cobra.Command, name string) (string, error) {
+	v, _ := cmd.Flags().GetString(name)
+	if v == "" {
+		return "", nil
+	}
+	abs, err := filepath.Abs(v)
+	if err != nil {
+		return "", output.NewError(output.CodeInvalidArgument, "Invalid --%s path: %v", name, err)
+	}
+	return abs, nil
+}
+
+// redactedConfig returns a copy of cfg safe to print, with stored API keys
+// and OAuth tokens masked.
+func redactedConfig(cfg *config.Config) *config.Config {
+	out := &config.Config{CurrentContext: cfg.CurrentContext, CredentialStore: cfg.CredentialStore, Contexts: map[string]*config.Context{}}
+	for name, ctx := range cfg.Contexts {
+		c := *ctx
+		if c.APIKey != "" {
+			c.APIKey = "********"
+		}
+		if c.OAuth != nil {
+			token := *c.OAuth
+			token.AccessToken = "********"
+			if token.RefreshToken != "" {
+				token.RefreshToken = "********"
+			}
+			c.OAuth = &token
+		}
+		out.Contexts[name] = &c
+	}
+	return out
+}
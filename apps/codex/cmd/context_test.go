@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+func TestContextRenameAndRemove(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{CurrentContext: "cloud", Contexts: map[string]*config.Context{
+		"local": {URL: "http://localhost:4000"},
+		"cloud": {URL: "https://cyfr.example.com", APIKey: "cyfr_sk_cloud"},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := contextRenameCmd.RunE(contextRenameCmd, []string{"cloud", "production"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod := cfg.Contexts["production"]
+	if cfg.Contexts["cloud"] != nil || prod == nil || prod.URL != "https://cyfr.example.com" || prod.APIKey != "cyfr_sk_cloud" {
+		t.Errorf("context not renamed with its settings: %+v", cfg.Contexts)
+	}
+	if cfg.CurrentContext != "production" {
+		t.Errorf("active context = %q, want production", cfg.CurrentContext)
+	}
+
+	contextRemoveCmd.Flags().Set("yes", "true")
+	t.Cleanup(func() { contextRemoveCmd.Flags().Set("yes", "false") })
+	if err := contextRemoveCmd.RunE(contextRemoveCmd, []string{"production"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Contexts["production"] != nil {
+		t.Errorf("context not removed")
+	}
+	if cfg.CurrentContext != "local" {
+		t.Errorf("active context = %q, want local", cfg.CurrentContext)
+	}
+}
+
+func TestAuthState(t *testing.T) {
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		ctx  config.Context
+		want string
+	}{
+		{config.Context{}, "none"},
+		{config.Context{SessionID: "sess_1"}, "session"},
+		{config.Context{APIKey: "cyfr_sk_1", SessionID: "sess_1"}, "api key"},
+		{config.Context{OAuth: &config.OAuthToken{AccessToken: "a"}}, "oauth"},
+		{config.Context{OAuth: &config.OAuthToken{AccessToken: "a", Expiry: past}}, "oauth (expired)"},
+		{config.Context{OAuth: &config.OAuthToken{AccessToken: "a", RefreshToken: "r", Expiry: past}}, "oauth (expired, refreshable)"},
+	}
+	for _, tt := range tests {
+		if got := authState(&tt.ctx); got != tt.want {
+			t.Errorf("authState(%+v) = %q, want %q", tt.ctx, got, tt.want)
+		}
+	}
+}
+
+func TestContextDefaults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	projectCache.dir = ""
+	cfg := config.DefaultForLocal()
+	cfg.Current().Defaults, _ = parseContextDefaults([]string{"json=true", "timeout=60s", "default_type=catalyst", "follow=true"})
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	resetFlags(rootCmd)
+	t.Cleanup(func() { resetFlags(rootCmd) })
+
+	if err := keyListCmd.ParseFlags([]string{"--timeout", "5s"}); err != nil {
+		t.Fatal(err)
+	}
+	applyDefaults(keyListCmd)
+	if !flagJSON {
+		t.Error("json default not applied")
+	}
+	if flagTimeout != 5*time.Second {
+		t.Errorf("explicit --timeout overridden by default: %s", flagTimeout)
+	}
+	if got, _ := normalizeComponentRef("acme.sentiment:1.0.0"); got != "catalyst:acme.sentiment:1.0.0" {
+		t.Errorf("default_type not applied: %q", got)
+	}
+
+	resetFlags(rootCmd)
+	applyDefaults(keyListCmd)
+	if flagTimeout != 60*time.Second {
+		t.Errorf("timeout default not applied: %s", flagTimeout)
+	}
+}
+
+func TestMock_ContextPing(t *testing.T) {
+	out := runCLI(t, "context", "ping", "--json")
+	var result struct {
+		Contexts []map[string]any `json:"contexts"`
+		Count    float64          `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Count != 1 || len(result.Contexts) != 1 {
+		t.Fatalf("expected the local context, got:\n%s", out)
+	}
+	local := result.Contexts[0]
+	if local["context"] != "local" || local["reachable"] != true || local["auth"] != "not logged in" || local["server"] == nil {
+		t.Errorf("unexpected ping result: %v", local)
+	}
+
+	out = runCLI(t, "context", "ping")
+	if !strings.Contains(out, "REACHABLE") || !strings.Contains(out, "yes") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
+
+func TestContextExportImport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := chdirTemp(t)
+	cfg := &config.Config{CurrentContext: "local", Contexts: map[string]*config.Context{
+		"local":   {URL: "http://localhost:4000"},
+		"staging": {URL: "https://staging.example.com", APIKey: "cyfr_sk_staging", RequestTimeout: "1m"},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	resetFlags(rootCmd)
+	t.Cleanup(func() { resetFlags(rootCmd) })
+
+	file := filepath.Join(dir, "team.json")
+	contextExportCmd.Flags().Set("file", file)
+	if err := contextExportCmd.RunE(contextExportCmd, []string{"staging"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "cyfr_sk_staging") || !strings.Contains(string(data), "staging.example.com") {
+		t.Errorf("unexpected export:\n%s", data)
+	}
+
+	// Importing into a config where staging points elsewhere and has its
+	// own key: --overwrite replaces the settings, and the key goes with
+	// the old URL.
+	cfg.Contexts["staging"] = &config.Context{URL: "https://old.example.com", APIKey: "cyfr_sk_old"}
+	delete(cfg.Contexts, "local")
+	cfg.Contexts["local"] = &config.Context{URL: "http://localhost:4000"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	contextImportCmd.Flags().Set("overwrite", "true")
+	if err := contextImportCmd.RunE(contextImportCmd, []string{file}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	staging := loaded.Contexts["staging"]
+	if staging.URL != "https://staging.example.com" || staging.RequestTimeout != "1m" || staging.APIKey != "" {
+		t.Errorf("unexpected imported context: %+v", staging)
+	}
+	if loaded.CurrentContext != "local" {
+		t.Errorf("import changed the active context to %q", loaded.CurrentContext)
+	}
+}
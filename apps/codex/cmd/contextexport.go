@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	contextCmd.AddCommand(contextExportCmd)
+	contextCmd.AddCommand(contextImportCmd)
+
+	contextExportCmd.Flags().String("file", "", "Write to this file instead of stdout")
+	contextExportCmd.Flags().Bool("include-secrets", false, "Include session IDs, API keys and OAuth tokens")
+	contextImportCmd.Flags().Bool("overwrite", false, "Replace existing contexts with different settings without asking")
+	contextImportCmd.Flags().Bool("skip-existing", false, "Keep existing contexts with different settings without asking")
+}
+
+// contextExport is the file format of context export and import.
+type contextExport struct {
+	Contexts map[string]*config.Context `json:"contexts"`
+}
+
+var contextExportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export contexts to share with a team",
+	Long: `Write the named contexts, or all of them, as JSON for 'cyfr context
+import'. Session IDs, API keys and OAuth tokens are left out unless
+--include-secrets is given, so the file is safe to commit or share.
+Certificate paths are exported as they are and must exist on the importing
+machine.`,
+	Example: `  cyfr context export --file team-contexts.json
+  cyfr context export staging production > contexts.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		names := args
+		if len(names) == 0 {
+			for name := range cfg.Contexts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+
+		includeSecrets, _ := cmd.Flags().GetBool("include-secrets")
+		export := contextExport{Contexts: make(map[string]*config.Context, len(names))}
+		for _, name := range names {
+			ctx, ok := cfg.Contexts[name]
+			if !ok {
+				return output.NewError(output.CodeNotFound, "Context '%s' not found.", name)
+			}
+			if includeSecrets {
+				c := *ctx
+				c.Server, c.Credentials = nil, ""
+				export.Contexts[name] = &c
+			} else {
+				export.Contexts[name] = shareableContext(ctx)
+			}
+		}
+		if includeSecrets {
+			fmt.Fprintln(os.Stderr, "Warning: the export includes credentials; don't share it.")
+		}
+
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return output.Errorf("Failed to encode contexts: %v", err)
+		}
+		data = append(data, '\n')
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			os.Stdout.Write(data)
+			return nil
+		}
+		perm := os.FileMode(0644)
+		if includeSecrets {
+			perm = 0600
+		}
+		if err := os.WriteFile(file, data, perm); err != nil {
+			return output.Errorf("Failed to write %s: %v", file, err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d context(s) to %s\n", len(names), file)
+		return nil
+	},
+}
+
+var contextImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import contexts from an export",
+	Long: `Merge contexts written by 'cyfr context export' into your config ('-'
+reads from stdin). New contexts are added and identical ones left alone.
+For a context that exists with different settings you are asked whether to
+replace it, unless --overwrite or --skip-existing is given. A replaced
+context keeps its credentials if the import has none and the URL is
+unchanged. The active context is never changed.`,
+	Example: `  cyfr context import team-contexts.json
+  cyfr context import team-contexts.json --skip-existing
+  curl -s https://intranet.example.com/cyfr-contexts.json | cyfr context import - --overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+		if overwrite && skipExisting {
+			return output.Error("Use either --overwrite or --skip-existing, not both")
+		}
+
+		var data []byte
+		var err error
+		if args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return output.Errorf("Failed to read contexts: %v", err)
+		}
+		var imported contextExport
+		if err := json.Unmarshal(data, &imported); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Invalid context export: %v", err)
+		}
+		if len(imported.Contexts) == 0 {
+			return output.NewError(output.CodeInvalidArgument, "Invalid context export: no contexts found.")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		names := make([]string, 0, len(imported.Contexts))
+		for name := range imported.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// Decide before taking the config lock, which other cyfr processes
+		// would wait on while a question is open.
+		var added, replaced, unchanged, skipped []string
+		take := make(map[string]*config.Context)
+		for _, name := range names {
+			ctx := imported.Contexts[name]
+			if ctx == nil || ctx.URL == "" {
+				return output.NewError(output.CodeInvalidArgument, "Invalid context export: context '%s' has no URL.", name)
+			}
+			ctx.Server, ctx.Credentials = nil, ""
+			existing, ok := cfg.Contexts[name]
+			switch {
+			case !ok:
+				added = append(added, name)
+				take[name] = ctx
+			case sameSettings(existing, ctx) && !hasCredentials(ctx):
+				unchanged = append(unchanged, name)
+			case skipExisting:
+				skipped = append(skipped, name)
+			case overwrite || confirm(fmt.Sprintf("Context '%s' exists (%s); replace it with the imported one (%s)?", name, existing.URL, ctx.URL)):
+				replaced = append(replaced, name)
+				take[name] = ctx
+			default:
+				skipped = append(skipped, name)
+			}
+		}
+
+		if len(take) > 0 {
+			_, err := config.Update(func(cfg *config.Config) error {
+				for name, ctx := range take {
+					if existing := cfg.Contexts[name]; existing != nil && !hasCredentials(ctx) && existing.URL == ctx.URL {
+						ctx.SessionID, ctx.APIKey, ctx.OAuth = existing.SessionID, existing.APIKey, existing.OAuth
+						ctx.Server = existing.Server
+					}
+					cfg.Contexts[name] = ctx
+				}
+				return nil
+			})
+			if err != nil {
+				return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+			}
+		}
+
+		result := map[string]any{
+			"added":     nonNil(added),
+			"replaced":  nonNil(replaced),
+			"unchanged": nonNil(unchanged),
+			"skipped":   nonNil(skipped),
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Imported %d context(s): %d added, %d replaced, %d unchanged, %d skipped\n",
+			len(names), len(added), len(replaced), len(unchanged), len(skipped))
+		return nil
+	},
+}
+
+// shareableContext returns a copy of ctx without credentials or anything
+// else tied to this machine's sessions.
+func shareableContext(ctx *config.Context) *config.Context {
+	c := *ctx
+	c.SessionID, c.APIKey, c.OAuth = "", "", nil
+	c.Server, c.Credentials = nil, ""
+	return &c
+}
+
+// sameSettings reports whether two contexts have the same settings,
+// ignoring credentials, as they would be exported.
+func sameSettings(a, b *config.Context) bool {
+	ja, errA := json.Marshal(shareableContext(a))
+	jb, errB := json.Marshal(shareableContext(b))
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// hasCredentials reports whether ctx holds a session, API key or token.
+func hasCredentials(ctx *config.Context) bool {
+	return ctx.SessionID != "" || ctx.APIKey != "" || ctx.OAuth != nil
+}
+
+// nonNil returns s, or an empty slice for nil, so it encodes as [].
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
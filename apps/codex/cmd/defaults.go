@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+// defaultTypeKey is the context default holding the component type for
+// references without one. It isn't a flag.
+const defaultTypeKey = "default_type"
+
+// activeContext returns the context commands talk to, after --context,
+// CYFR_CONTEXT and the project config, or nil if it isn't configured.
+func activeContext() *config.Context {
+	return loadConfigOrDefault().Contexts[activeContextName()]
+}
+
+// activeContextName returns the name of the context commands talk to, as
+// activeContext resolves it.
+func activeContextName() string {
+	if override := contextOverride(); override != "" {
+		return override
+	}
+	return loadConfigOrDefault().CurrentContext
+}
+
+// applyDefaults sets the flags of cmd not given on the command line from
+// the project config, the active context's defaults and the CLI settings,
+// in that order of precedence.
+func applyDefaults(cmd *cobra.Command) {
+	applySettings(cmd)
+
+	// Setting a flag's value directly leaves it unmarked as changed, so
+	// Changed keeps reporting only flags given on the command line.
+	if ctx := activeContext(); ctx != nil {
+		for name, v := range ctx.Defaults {
+			if name == defaultTypeKey || cmd.Flags().Changed(name) {
+				continue
+			}
+			f := cmd.Flags().Lookup(name)
+			if f == nil {
+				// Defaults apply across commands; most have only some flags.
+				continue
+			}
+			if err := f.Value.Set(defaultValue(v)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: ignoring invalid default %s=%v in context: %v\n", name, v, err)
+			}
+		}
+	}
+	if !cmd.Flags().Changed("json") {
+		applyProjectOutput()
+	}
+}
+
+// applyOutputFlags applies --output and --query over the output format
+// from defaults. A template or query implies JSON output, which they then
+// reduce for scripts. With JSON output, errors are JSON too. csv and tsv
+// change how tables are printed; other output is unaffected. dot is for
+// the commands annotated with dotOutput, which print a Graphviz graph.
+func applyOutputFlags(cmd *cobra.Command) error {
+	format, text, _ := strings.Cut(flagOutput, "=")
+	output.TableFormat = ""
+	switch format {
+	case "text":
+		flagJSON = false
+	case "csv", "tsv":
+		flagJSON = false
+		output.TableFormat = format
+	case "json", "go-template":
+		flagJSON = true
+	case "dot":
+		flagJSON = false
+	}
+	if flagQuery != "" {
+		flagJSON = true
+	}
+	output.JSONErrors = flagJSON
+	output.Silent = flagJSON || flagQuiet
+
+	template := ""
+	switch format {
+	case "", "text", "json", "csv", "tsv":
+	case "go-template":
+		if text == "" {
+			return output.NewError(output.CodeInvalidArgument, "--output go-template needs a template, e.g. go-template='{{.status}}'")
+		}
+		template = text
+	case "dot":
+		if cmd.Annotations[dotOutput] == "" {
+			return output.NewError(output.CodeInvalidArgument, "--output dot is only for graphs, such as 'cyfr deps'")
+		}
+	default:
+		return output.NewError(output.CodeInvalidArgument, "Invalid --output %q (use text, json, csv, tsv or go-template=<template>)", flagOutput)
+	}
+	if err := output.SetTemplate(template); err != nil {
+		return output.NewError(output.CodeInvalidArgument, "Invalid --output: %v", err)
+	}
+	if _, err := output.Select(nil, flagQuery); flagQuery != "" && err != nil {
+		return output.NewError(output.CodeInvalidArgument, "Invalid --query: %v", err)
+	}
+	output.Query = flagQuery
+	return nil
+}
+
+// defaultValue formats a default from the config as a flag value: lists
+// as comma-separated values, everything else as printed.
+func defaultValue(v any) string {
+	if list, ok := v.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// contextDefaultType returns the active context's default component type,
+// or "" if it has none.
+func contextDefaultType() string {
+	ctx := activeContext()
+	if ctx == nil {
+		return ""
+	}
+	v, ok := ctx.Defaults[defaultTypeKey]
+	if !ok {
+		return ""
+	}
+	typ, _ := v.(string)
+	if !ref.IsTypePrefix(typ) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid %s %v in context\n", defaultTypeKey, v)
+		return ""
+	}
+	return typ
+}
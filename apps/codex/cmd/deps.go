@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(depsCmd)
+}
+
+// dotOutput is the annotation of commands that accept --output dot.
+const dotOutput = "dot-output"
+
+var depsCmd = &cobra.Command{
+	Use:         "deps [type] <reference|path>",
+	Short:       "Show a formula's dependency tree",
+	GroupID:     "component",
+	Annotations: map[string]string{dotOutput: "true"},
+	Long: `Resolve the components a formula invokes, and the components those invoke in turn, and print them as a tree. --output dot prints the graph for Graphviz instead, and --json the tree as JSON.
+
+A component's dependencies are the references in the "dependencies" list of its cyfr-manifest.json when it is in the project's components/ directory, and what the server's registry resolves them to otherwise. Version constraints and "latest" resolve to the highest version local or published, skipping yanked ones.
+
+Dependencies are flagged, and the command fails, if they can't be resolved, if they are yanked, if they depend on themselves, or if their host policy keeps them from working: a catalyst needs allowed_domains to reach any host, and only formulas can invoke other components.`,
+	Example: `  cyfr deps f:local.list-models:0.1.0
+  cyfr deps components/formulas/local/list-models/0.1.0/
+  cyfr deps f:local.list-models --output dot | dot -Tsvg > deps.svg`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		args = joinTypeShorthand(args)
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		d := newDepResolver(cmd.Context(), client)
+		root := d.walk(args[0], nil)
+		switch {
+		case flagOutput == "dot":
+			printDepGraph(root)
+		case flagJSON:
+			output.JSON(root)
+		default:
+			printDepTree(root)
+		}
+		if n := root.problemCount(); n > 0 {
+			return output.Errorf("%d component(s) in the dependency tree of %s have problems", n, root.Reference)
+		}
+		return nil
+	},
+}
+
+// A depNode is a component in a dependency tree.
+type depNode struct {
+	Reference string `json:"reference"`
+	// Requested is the reference as declared, if it named a version
+	// constraint or "latest" that Reference resolves.
+	Requested    string     `json:"requested,omitempty"`
+	Source       string     `json:"source,omitempty"` // local, registry or oci
+	Problems     []string   `json:"problems,omitempty"`
+	Dependencies []*depNode `json:"dependencies,omitempty"`
+}
+
+func (n *depNode) problem(format string, args ...any) {
+	n.Problems = append(n.Problems, fmt.Sprintf(format, args...))
+}
+
+// problemCount counts the components with problems in the tree under n.
+func (n *depNode) problemCount() int {
+	count := 0
+	if len(n.Problems) > 0 {
+		count++
+	}
+	for _, c := range n.Dependencies {
+		count += c.problemCount()
+	}
+	return count
+}
+
+// A depResolver builds dependency trees, asking the server about each
+// component and policy once.
+type depResolver struct {
+	ctx    context.Context
+	client *mcp.Client
+
+	// published and yanked are the registry's versions of each component,
+	// by reference without version.
+	published, yanked map[string][]string
+	// domains are the allowed_domains of each catalyst's policy; nil if
+	// the policy couldn't be read.
+	domains map[string][]any
+}
+
+func newDepResolver(ctx context.Context, client *mcp.Client) *depResolver {
+	return &depResolver{
+		ctx:       ctx,
+		client:    client,
+		published: map[string][]string{},
+		yanked:    map[string][]string{},
+		domains:   map[string][]any{},
+	}
+}
+
+// walk resolves raw and, recursively, its dependencies. ancestors are the
+// references of the components that led to raw, to catch cycles.
+func (d *depResolver) walk(raw string, ancestors []string) *depNode {
+	n := &depNode{Reference: raw}
+	normalized, err := normalizeComponentRef(raw)
+	if err != nil {
+		n.problem("unresolved: %v", err)
+		return n
+	}
+	r, err := parseComponentRef(normalized)
+	if err != nil {
+		n.problem("unresolved: %v", err)
+		return n
+	}
+	if r.Registry != "" {
+		// OCI artifacts don't carry a manifest to read dependencies from.
+		n.Reference, n.Source = r.String(), "oci"
+		return n
+	}
+	if r.Type == "" {
+		n.problem("unresolved: no type; add a type prefix, e.g. c:%s", r)
+		return n
+	}
+	if r.Digest == "" && ref.IsTag(r.Version) {
+		tags, err := componentTags(d.ctx, d.client, r)
+		if err != nil {
+			n.problem("unresolved: %v", err)
+			return n
+		}
+		version, ok := tags[r.Version]
+		if !ok {
+			n.problem("unresolved: no tag %s", r.Version)
+			return n
+		}
+		n.Requested = r.String()
+		r.Version = version
+	}
+	if r.Digest == "" && (r.Version == "latest" || r.HasConstraint()) {
+		published, yanked := d.versions(r)
+		candidates := slices.Clone(published)
+		for _, v := range localVersions(r) {
+			if !slices.Contains(yanked, v) {
+				candidates = append(candidates, v)
+			}
+		}
+		version, err := ref.ResolveVersion(r.Version, candidates)
+		if err != nil {
+			n.problem("unresolved: %v", err)
+			return n
+		}
+		n.Requested = r.String()
+		r.Version = version
+	}
+	n.Reference = r.String()
+	if slices.Contains(ancestors, n.Reference) {
+		n.problem("cycle: it depends on itself")
+		return n
+	}
+
+	var deps []string
+	if dir := componentDir(r); isDir(dir) {
+		n.Source = "local"
+		if deps, err = manifestDependencies(dir); err != nil {
+			n.problem("%v", err)
+		}
+	} else {
+		result, err := typed.New(d.client).Component(d.ctx, typed.ComponentArgs{
+			Action:    typed.ComponentActionResolve,
+			Reference: n.Reference,
+		})
+		if err != nil {
+			n.problem("unresolved: %v", err)
+			return n
+		}
+		n.Source = "registry"
+		deps = resolvedDependencies(result)
+	}
+	if _, yanked := d.versions(r); slices.Contains(yanked, r.Version) {
+		n.problem("yanked")
+	}
+	if r.Type == "catalyst" {
+		if domains := d.allowedDomains(n.Reference); domains != nil && len(domains) == 0 {
+			n.problem("policy: no allowed_domains, so it can't reach any host")
+		}
+	}
+	if len(deps) > 0 && r.Type != "formula" {
+		n.problem("policy: only formulas can invoke other components, but this %s lists %d", r.Type, len(deps))
+		return n
+	}
+
+	ancestors = append(slices.Clip(ancestors), n.Reference)
+	for _, dep := range deps {
+		n.Dependencies = append(n.Dependencies, d.walk(dep, ancestors))
+	}
+	return n
+}
+
+// versions returns the versions of r's component published in the
+// registry, and those that are yanked. They are empty if it can't be
+// searched.
+func (d *depResolver) versions(r ref.ComponentRef) (published, yanked []string) {
+	r.Version, r.Digest = "", ""
+	key := r.String()
+	if _, ok := d.published[key]; !ok {
+		d.published[key], d.yanked[key], _ = publishedVersions(d.ctx, d.client, r)
+	}
+	return d.published[key], d.yanked[key]
+}
+
+// allowedDomains returns the allowed_domains of reference's effective
+// policy, or nil if it can't be read.
+func (d *depResolver) allowedDomains(reference string) []any {
+	if domains, ok := d.domains[reference]; ok {
+		return domains
+	}
+	var domains []any
+	result, err := typed.New(d.client).Policy(d.ctx, typed.PolicyArgs{
+		Action:       typed.PolicyActionGet,
+		ComponentRef: reference,
+	})
+	if err == nil {
+		policy, _ := result["policy"].(map[string]any)
+		if list, ok := policy["allowed_domains"].([]any); ok {
+			domains = list
+		}
+	}
+	d.domains[reference] = domains
+	return domains
+}
+
+// manifestDependencies reads the "dependencies" of the manifest in dir.
+// A component without a manifest has none.
+func manifestDependencies(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, componentManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var m struct {
+		Dependencies []string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", componentManifestFile, err)
+	}
+	return m.Dependencies, nil
+}
+
+// resolvedDependencies picks the dependency references out of a component
+// resolve result, which lists them as references or as objects with one.
+func resolvedDependencies(result map[string]any) []string {
+	items, _ := result["dependencies"].([]any)
+	var deps []string
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			deps = append(deps, v)
+		case map[string]any:
+			if s, ok := v["reference"].(string); ok {
+				deps = append(deps, s)
+			}
+		}
+	}
+	return deps
+}
+
+// label describes n on a line of its tree.
+func (n *depNode) label() string {
+	var notes []string
+	if n.Source != "" {
+		notes = append(notes, n.Source)
+	}
+	if n.Requested != "" {
+		r, _ := ref.Parse(n.Requested)
+		notes = append(notes, "from "+r.Version)
+	}
+	s := n.Reference
+	if len(notes) > 0 {
+		s += " (" + strings.Join(notes, ", ") + ")"
+	}
+	if len(n.Problems) > 0 {
+		s += "  ! " + strings.Join(n.Problems, "; ")
+	}
+	return s
+}
+
+// printDepTree prints the tree under root for people.
+func printDepTree(root *depNode) {
+	fmt.Println(root.label())
+	printDepChildren(root, "")
+}
+
+func printDepChildren(n *depNode, indent string) {
+	for i, c := range n.Dependencies {
+		branch, next := "├── ", "│   "
+		if i == len(n.Dependencies)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Println(indent + branch + c.label())
+		printDepChildren(c, indent+next)
+	}
+}
+
+// printDepGraph prints the tree under root as a Graphviz digraph, with
+// components that have problems in red.
+func printDepGraph(root *depNode) {
+	fmt.Println("digraph dependencies {")
+	fmt.Println("  node [shape=box];")
+	// A component that several others depend on is in the tree more than
+	// once, but in the graph once.
+	seen := map[string]bool{}
+	var visit func(n *depNode)
+	visit = func(n *depNode) {
+		if seen[n.Reference] {
+			return
+		}
+		seen[n.Reference] = true
+		if len(n.Problems) > 0 {
+			fmt.Printf("  %q [color=red, xlabel=%q];\n", n.Reference, strings.Join(n.Problems, "; "))
+		}
+		for _, c := range n.Dependencies {
+			fmt.Printf("  %q -> %q;\n", n.Reference, c.Reference)
+			visit(c)
+		}
+	}
+	visit(root)
+	fmt.Println("}")
+}
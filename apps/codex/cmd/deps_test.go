@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestDepResolverWalk(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.search"] = map[string]any{"components": []any{
+		map[string]any{"name": "calc", "publisher": "local", "version": "1.0.0", "component_type": "reagent"},
+		map[string]any{"name": "calc", "publisher": "local", "version": "1.1.0", "component_type": "reagent", "yanked": true},
+	}}
+	f.Responses["policy.get"] = map[string]any{"policy": map[string]any{"allowed_domains": []any{"api.example.com"}}}
+	f.Errors = map[string]string{"component.resolve": "Component not found: reagent:local.missing:1.0.0"}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+
+	chdirTemp(t)
+	writeComponent(t, "formula", "top", `{"id": "formula:local.top", "type": "formula", "version": "0.1.0", "dependencies": [
+		"c:local.fetch:0.1.0", "r:local.calc:^1.0", "r:local.calc:1.1.0", "r:local.missing:1.0.0", "f:local.top:0.1.0"]}`, map[string]string{})
+	writeComponent(t, "catalyst", "fetch", `{"id": "catalyst:local.fetch", "type": "catalyst", "version": "0.1.0"}`, map[string]string{})
+	for _, v := range []string{"1.0.0", "1.1.0"} {
+		writeVersion(t, "reagent", "calc", v, `{"type": "reagent"}`)
+	}
+
+	d := newDepResolver(context.Background(), mcp.NewClient(srv.URL))
+	root := d.walk("f:local.top:0.1.0", nil)
+	if root.Reference != "formula:local.top:0.1.0" || root.Source != "local" || len(root.Problems) != 0 {
+		t.Fatalf("root = %+v", root)
+	}
+	var got []string
+	for _, c := range root.Dependencies {
+		got = append(got, c.label())
+	}
+	for i, want := range []string{
+		"catalyst:local.fetch:0.1.0 (local)",
+		"reagent:local.calc:1.0.0 (local, from ^1.0)",
+		"reagent:local.calc:1.1.0 (local)  ! yanked",
+		"reagent:local.missing:1.0.0  ! unresolved: Component not found",
+		"formula:local.top:0.1.0  ! cycle: it depends on itself",
+	} {
+		if i >= len(got) || !strings.HasPrefix(got[i], want) {
+			t.Errorf("dependency %d: got %q, want %q", i, got, want)
+		}
+	}
+	if n := root.problemCount(); n != 3 {
+		t.Errorf("problemCount = %d, want 3", n)
+	}
+
+	// A catalyst that can't reach any host, and a reagent with dependencies.
+	f.Responses["policy.get"] = map[string]any{"policy": map[string]any{"allowed_domains": []any{}}}
+	writeVersion(t, "reagent", "calc", "2.0.0", `{"type": "reagent", "dependencies": ["c:local.fetch:0.1.0"]}`)
+	d = newDepResolver(context.Background(), mcp.NewClient(srv.URL))
+	if n := d.walk("c:local.fetch:0.1.0", nil); !strings.Contains(n.label(), "no allowed_domains") {
+		t.Errorf("catalyst = %q", n.label())
+	}
+	if n := d.walk("r:local.calc:2.0.0", nil); !strings.Contains(n.label(), "only formulas can invoke") || len(n.Dependencies) != 0 {
+		t.Errorf("reagent = %q", n.label())
+	}
+}
+
+func TestPrintDepGraph(t *testing.T) {
+	shared := &depNode{Reference: "c:local.fetch:0.1.0", Problems: []string{"yanked"}}
+	root := &depNode{Reference: "f:local.top:0.1.0", Dependencies: []*depNode{
+		{Reference: "f:local.a:0.1.0", Dependencies: []*depNode{shared}},
+		{Reference: "f:local.b:0.1.0", Dependencies: []*depNode{shared}},
+	}}
+	out, err := captureStdout(func() { printDepGraph(root) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"f:local.top:0.1.0" -> "f:local.a:0.1.0";`,
+		`"f:local.a:0.1.0" -> "c:local.fetch:0.1.0";`,
+		`"f:local.b:0.1.0" -> "c:local.fetch:0.1.0";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("graph doesn't have %s:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, `[color=red, xlabel="yanked"]`) != 1 {
+		t.Errorf("the shared component should be declared once:\n%s", out)
+	}
+}
+
+// writeVersion writes version of a local component with just a manifest.
+func writeVersion(t *testing.T, compType, name, version, manifest string) {
+	t.Helper()
+	dir := filepath.Join("components", compType+"s", "local", name, version)
+	os.MkdirAll(dir, 0755)
+	if err := os.WriteFile(filepath.Join(dir, componentManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/cyfr/codex/internal/wasm"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:     "diff <reference|path> <reference|path|version>",
+	Short:   "Compare two versions of a component",
+	GroupID: "component",
+	Long: `Show what changed between two versions of a component, as a unified diff: the fields of its manifest, the size and digest of its artifact, and the WIT interfaces the artifact imports and exports. When both versions are in the project's components/ directory, their READMEs are compared too. Review it before granting a new version secrets or widening its policy.
+
+Each version is read from components/ if it is there, and from the server's registry otherwise; an OCI reference pulls the artifact, which has no manifest. The second argument may be just a version or constraint of the first's component. --json lists the changed fields instead.`,
+	Example: `  cyfr diff c:acme.sentiment:1.0.0 c:acme.sentiment:1.1.0
+  cyfr diff c:acme.sentiment:1.0.0 1.1.0
+  cyfr diff c:acme.sentiment:1.0.0 latest
+  cyfr diff c:local.my-tool:0.1.0 components/catalysts/local/my-tool/0.2.0/`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := diffRefs(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		a, err := loadSnapshot(cmd.Context(), client, from)
+		if err != nil {
+			return err
+		}
+		b, err := loadSnapshot(cmd.Context(), client, to)
+		if err != nil {
+			return err
+		}
+
+		changes := a.changes(b)
+		readmeChanged := a.Source == "local" && b.Source == "local" && a.Readme != b.Readme
+		if flagJSON {
+			result := map[string]any{"from": a, "to": b, "changes": changes}
+			if a.Source == "local" && b.Source == "local" {
+				result["readme_changed"] = readmeChanged
+			}
+			output.JSON(result)
+			return nil
+		}
+		changed := output.Diff(a.label(), b.label(), a.text(), b.text())
+		if a.Source == "local" && b.Source == "local" {
+			changed = output.Diff("a/README.md", "b/README.md", a.Readme, b.Readme) || changed
+		}
+		if !changed {
+			fmt.Printf("No differences between %s and %s.\n", a.Reference, b.Reference)
+		}
+		return nil
+	},
+}
+
+// diffRefs normalizes the arguments of diff. A bare version or constraint
+// as the second argument is taken as a version of the first's component.
+func diffRefs(first, second string) (from, to string, err error) {
+	if from, err = normalizeComponentRef(first); err != nil {
+		return "", "", err
+	}
+	_, versionErr := ref.ParseVersion(second)
+	if versionErr != nil && !ref.IsConstraint(second) && second != "latest" {
+		to, err = normalizeComponentRef(second)
+		return from, to, err
+	}
+	r, err := parseComponentRef(from)
+	if err != nil {
+		return "", "", err
+	}
+	r.Version, r.Digest = second, ""
+	return from, r.String(), nil
+}
+
+// A componentSnapshot is what diff compares of a version of a component.
+type componentSnapshot struct {
+	Reference string `json:"reference"`
+	Source    string `json:"source"` // local, registry or oci
+	// Metadata are the fields of the manifest but its version, with
+	// values other than strings in JSON.
+	Metadata    map[string]string `json:"metadata"`
+	Size        int64             `json:"size,omitempty"`
+	Digest      string            `json:"digest,omitempty"`
+	ArtifactErr string            `json:"artifact_error,omitempty"`
+	Imports     []string          `json:"imports,omitempty"`
+	Exports     []string          `json:"exports,omitempty"`
+	Readme      string            `json:"-"`
+}
+
+// loadSnapshot reads the version of a component normalized names, after
+// resolving a constraint or "latest" in it.
+func loadSnapshot(ctx context.Context, client *mcp.Client, normalized string) (*componentSnapshot, error) {
+	resolved, err := resolveConstraint(ctx, client, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err = resolveLatest(ctx, client, resolved); err != nil {
+		return nil, err
+	}
+	r, err := parseComponentRef(resolved)
+	if err != nil {
+		return nil, err
+	}
+	s := &componentSnapshot{Reference: r.String(), Metadata: map[string]string{}}
+
+	var data []byte
+	if oci, ok := registryRef(resolved); ok {
+		s.Source = "oci"
+		artifact, err := newOCIClient(oci.Registry).Pull(ctx, oci.Registry, oci.Repository(), oci.Version)
+		if err != nil {
+			return nil, output.Errorf("Cannot pull %s: %v", oci.Unpinned(), err)
+		}
+		data = artifact.Data
+	} else if dir := componentDir(r); r.Type != "" && isDir(dir) {
+		s.Source = "local"
+		if manifest, err := os.ReadFile(filepath.Join(dir, componentManifestFile)); err == nil {
+			var m map[string]any
+			if err := json.Unmarshal(manifest, &m); err != nil {
+				return nil, output.Errorf("Cannot read %s: %v", filepath.Join(dir, componentManifestFile), err)
+			}
+			s.setMetadata(m)
+		}
+		if data, err = os.ReadFile(filepath.Join(dir, r.Type+".wasm")); err != nil {
+			s.ArtifactErr = fmt.Sprintf("%s.wasm isn't built", r.Type)
+		}
+		readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+		s.Readme = string(readme)
+	} else {
+		s.Source = "registry"
+		info, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+			Action:    typed.ComponentActionInspect,
+			Reference: s.Reference,
+		})
+		if err != nil {
+			return nil, output.Errorf("Cannot inspect %s: %v%s", s.Reference, err, didYouMean(s.Reference, err))
+		}
+		if m, ok := info["manifest"].(map[string]any); ok {
+			s.setMetadata(m)
+		} else {
+			for _, field := range []string{"type", "description", "license"} {
+				if v, ok := info[field]; ok {
+					s.setMetadata(map[string]any{field: v})
+				}
+			}
+		}
+		if digest, _ := info["digest"].(string); digest == "" {
+			s.ArtifactErr = "the registry reports no digest"
+		} else if data, err = downloadBlob(ctx, client, withAlgorithm(digest)); err != nil {
+			s.ArtifactErr = err.Error()
+		}
+	}
+
+	if data != nil {
+		s.Size, s.Digest = int64(len(data)), artifactDigest(data)
+		if c, err := wasm.Parse(data); err != nil {
+			s.ArtifactErr = fmt.Sprintf("can't read its interfaces: %v", err)
+		} else {
+			s.Imports, s.Exports = slices.Clone(c.Imports), slices.Clone(c.Exports)
+			sort.Strings(s.Imports)
+			sort.Strings(s.Exports)
+		}
+	}
+	return s, nil
+}
+
+// setMetadata adds the fields of manifest m to the snapshot's metadata.
+func (s *componentSnapshot) setMetadata(m map[string]any) {
+	for k, v := range m {
+		if k == "version" {
+			continue
+		}
+		if str, ok := v.(string); ok {
+			s.Metadata[k] = str
+			continue
+		}
+		// Objects are indented, so that a change in a schema or an
+		// example is a change of a few lines.
+		encoded, _ := json.Marshal(v)
+		if strings.Contains(string(encoded), "{") {
+			encoded, _ = json.MarshalIndent(v, "", "  ")
+		}
+		s.Metadata[k] = string(encoded)
+	}
+}
+
+// fields returns what is compared of the snapshot, as field names and
+// values in the order they are shown.
+func (s *componentSnapshot) fields() [][2]string {
+	keys := make([]string, 0, len(s.Metadata))
+	for k := range s.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var fields [][2]string
+	for _, k := range keys {
+		fields = append(fields, [2]string{k, s.Metadata[k]})
+	}
+	if s.ArtifactErr != "" {
+		fields = append(fields, [2]string{"artifact", s.ArtifactErr})
+	}
+	if s.Digest != "" {
+		fields = append(fields, [2]string{"size", fmt.Sprint(s.Size)}, [2]string{"digest", s.Digest})
+	}
+	fields = append(fields, [2]string{"imports", strings.Join(s.Imports, "\n")}, [2]string{"exports", strings.Join(s.Exports, "\n")})
+	return fields
+}
+
+// text renders the snapshot for a line diff: a line per field, and one
+// per interface.
+func (s *componentSnapshot) text() string {
+	var b strings.Builder
+	for _, f := range s.fields() {
+		switch f[0] {
+		case "imports", "exports":
+			for _, name := range strings.Fields(f[1]) {
+				fmt.Fprintf(&b, "%s %s\n", strings.TrimSuffix(f[0], "s"), name)
+			}
+		default:
+			fmt.Fprintf(&b, "%s: %s\n", f[0], f[1])
+		}
+	}
+	return b.String()
+}
+
+// changes lists the fields whose values differ from s to other. Fields
+// on one side only are compared with "".
+func (s *componentSnapshot) changes(other *componentSnapshot) []metadataChange {
+	from, to := map[string]string{}, map[string]string{}
+	var names []string
+	for _, f := range s.fields() {
+		from[f[0]] = f[1]
+		names = append(names, f[0])
+	}
+	for _, f := range other.fields() {
+		to[f[0]] = f[1]
+		if _, ok := from[f[0]]; !ok {
+			names = append(names, f[0])
+		}
+	}
+	changes := []metadataChange{}
+	for _, name := range names {
+		if from[name] != to[name] {
+			changes = append(changes, metadataChange{Field: name, From: from[name], To: to[name]})
+		}
+	}
+	return changes
+}
+
+// label names the snapshot in the diff header.
+func (s *componentSnapshot) label() string {
+	return fmt.Sprintf("%s (%s)", s.Reference, s.Source)
+}
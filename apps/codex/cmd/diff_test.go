@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestDiffRefs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, tc := range []struct{ first, second, want string }{
+		{"c:acme.sentiment:1.0.0", "1.1.0", "catalyst:acme.sentiment:1.1.0"},
+		{"c:acme.sentiment:1.0.0", "^1.0", "catalyst:acme.sentiment:^1.0"},
+		{"c:acme.sentiment:1.0.0", "latest", "catalyst:acme.sentiment:latest"},
+		{"c:acme.sentiment:1.0.0", "c:acme.other:2.0.0", "c:acme.other:2.0.0"},
+	} {
+		if _, got, err := diffRefs(tc.first, tc.second); err != nil || got != tc.want {
+			t.Errorf("diffRefs(%q, %q) = %q, %v, want %q", tc.first, tc.second, got, err, tc.want)
+		}
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := httptest.NewServer(mockserver.New(mockserver.DefaultFixtures()))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	chdirTemp(t)
+	write := func(version, manifest, readme string, imports []string) {
+		dir := filepath.Join("components", "catalysts", "local", "feeds", version)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, componentManifestFile), []byte(manifest), 0644)
+		os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0644)
+		os.WriteFile(filepath.Join(dir, "catalyst.wasm"), componentBinary(imports, []string{"cyfr:catalyst/run@0.1.0"}), 0644)
+	}
+	write("0.1.0", `{"type": "catalyst", "version": "0.1.0", "description": "Feeds", "secrets": ["A"]}`, "# feeds\n", []string{"cyfr:http/fetch@0.1.0"})
+	write("0.2.0", `{"type": "catalyst", "version": "0.2.0", "description": "Feeds", "secrets": ["A", "B"]}`, "# feeds\n\nNow with B.\n",
+		[]string{"cyfr:http/fetch@0.1.0", "cyfr:secrets/read@0.1.0"})
+
+	a, err := loadSnapshot(context.Background(), client, "catalyst:local.feeds:0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadSnapshot(context.Background(), client, "catalyst:local.feeds:0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Source != "local" || a.ArtifactErr != "" || len(a.Exports) != 1 {
+		t.Fatalf("snapshot = %+v", a)
+	}
+
+	var fields []string
+	for _, c := range a.changes(b) {
+		fields = append(fields, c.Field)
+	}
+	if got := strings.Join(fields, ","); got != "secrets,size,digest,imports" {
+		t.Errorf("changed fields = %s", got)
+	}
+	out, err := captureStdout(func() { output.Diff(a.label(), b.label(), a.text(), b.text()) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"--- catalyst:local.feeds:0.1.0 (local)",
+		`-secrets: ["A"]`,
+		`+secrets: ["A","B"]`,
+		" import cyfr:http/fetch@0.1.0",
+		"+import cyfr:secrets/read@0.1.0",
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("diff doesn't have %q:\n%s", want, out)
+		}
+	}
+	if a.Readme == b.Readme {
+		t.Error("the READMEs should differ")
+	}
+
+	// The registry's version, whose artifact isn't a component.
+	r, err := loadSnapshot(context.Background(), client, "reagent:local.hello:0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Source != "registry" || r.Metadata["description"] != "Says hello" || !strings.Contains(r.ArtifactErr, "interfaces") {
+		t.Errorf("registry snapshot = %+v", r)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// unpinReference splits a digest pin ("@sha256:...") off a registry
+// reference, returning the reference to send to the server and the pinned
+// digest, or "" if the reference isn't pinned. A malformed digest is an
+// error.
+func unpinReference(raw string) (string, string, error) {
+	base, digest := ref.SplitDigest(raw)
+	if digest != "" && !ref.ValidDigest(digest) {
+		return "", "", output.NewError(output.CodeInvalidArgument, "Invalid digest %q: expected sha256: followed by 64 lowercase hex digits.", digest)
+	}
+	return base, digest, nil
+}
+
+// checkDigest returns an error if the digest the server reported for a
+// pinned reference isn't the pinned one.
+func checkDigest(reference, pinned, reported string) error {
+	if reported == "" {
+		return output.Errorf("Cannot verify %s: the server did not report a digest for it.", reference)
+	}
+	if !sameDigest(reported, pinned) {
+		return output.Errorf("Digest mismatch for %s: pinned %s, got %s.", reference, pinned, withAlgorithm(reported))
+	}
+	return nil
+}
+
+// verifyPinned looks up a pinned reference in the registry and returns an
+// error unless its artifact has the pinned digest.
+func verifyPinned(ctx context.Context, client *mcp.Client, reference, pinned string) error {
+	result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionInspect,
+		Reference: reference,
+	})
+	if err != nil {
+		return output.Errorf("Cannot verify %s: %v", reference, err)
+	}
+	reported, _ := result["digest"].(string)
+	return checkDigest(reference, pinned, reported)
+}
+
+// verifyArtifact downloads the artifact with the given digest and checks
+// that its contents hash to it, so a pinned pull is verified end to end
+// rather than by trusting the registry's metadata.
+func verifyArtifact(ctx context.Context, client *mcp.Client, digest string) error {
+	data, err := downloadBlob(ctx, client, digest)
+	if err != nil {
+		return err
+	}
+	if got := artifactDigest(data); !sameDigest(got, digest) {
+		return fmt.Errorf("downloaded artifact has digest %s, want %s", got, digest)
+	}
+	return nil
+}
+
+// downloadBlob downloads the artifact with the given digest from the
+// server's registry.
+func downloadBlob(ctx context.Context, client *mcp.Client, digest string) ([]byte, error) {
+	result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action: typed.ComponentActionGetBlob,
+		Digest: digest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("download artifact: %w", err)
+	}
+	encoded, _ := result["bytes"].(string)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("download artifact: invalid base64: %w", err)
+	}
+	return data, nil
+}
+
+// artifactDigest returns the sha256 digest of data in "sha256:<hex>" form.
+func artifactDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return ref.DigestAlgorithm + hex.EncodeToString(sum[:])
+}
+
+// sameDigest compares two digests, with or without the "sha256:" prefix —
+// the registry stores them both ways.
+func sameDigest(a, b string) bool {
+	return strings.EqualFold(withAlgorithm(a), withAlgorithm(b))
+}
+
+func withAlgorithm(d string) string {
+	if strings.HasPrefix(d, ref.DigestAlgorithm) {
+		return d
+	}
+	return ref.DigestAlgorithm + d
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// mockDigest is the digest of the artifact in the built-in mock fixtures.
+const mockDigest = "sha256:93a44bbb96c751218e4c00d479e4c14358122a389acca16205b1e4d0dc5f9476"
+
+func TestNormalizeComponentRef_KeepsDigest(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"local.claude@0.1.0", "local.claude:0.1.0"},
+		{"c:acme.sentiment@" + mockDigest, "c:acme.sentiment@" + mockDigest},
+		{"c:acme.sentiment@1.0.0@" + mockDigest, "c:acme.sentiment:1.0.0@" + mockDigest},
+	}
+	for _, tt := range tests {
+		if got, _ := normalizeComponentRef(tt.input); got != tt.want {
+			t.Errorf("normalizeComponentRef(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestUnpinReference(t *testing.T) {
+	base, digest, _ := unpinReference("c:acme.sentiment:1.0.0@" + mockDigest)
+	if base != "c:acme.sentiment:1.0.0" || digest != mockDigest {
+		t.Errorf("got %q, %q", base, digest)
+	}
+	base, digest, _ = unpinReference("c:acme.sentiment:1.0.0")
+	if base != "c:acme.sentiment:1.0.0" || digest != "" {
+		t.Errorf("unpinned: got %q, %q", base, digest)
+	}
+}
+
+func TestSameDigest(t *testing.T) {
+	hex := strings.TrimPrefix(mockDigest, "sha256:")
+	if !sameDigest(mockDigest, hex) || !sameDigest(hex, mockDigest) || !sameDigest(mockDigest, strings.ToUpper(hex)) {
+		t.Error("digests with and without the algorithm prefix should match")
+	}
+	if sameDigest(mockDigest, "sha256:"+strings.Repeat("0", 64)) {
+		t.Error("different digests should not match")
+	}
+}
+
+func TestArtifactDigest(t *testing.T) {
+	if got := artifactDigest([]byte("\x00asm\x01\x00\x00\x00")); got != mockDigest {
+		t.Errorf("artifactDigest = %s, want %s", got, mockDigest)
+	}
+}
+
+func TestMock_PullPinnedVerifiesArtifact(t *testing.T) {
+	out := runCLI(t, "pull", "r:local.hello@"+mockDigest, "--json")
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result["verified"] != true {
+		t.Errorf("expected verified pull, got %v", result)
+	}
+
+	client, _ := newClient()
+	if err := verifyArtifact(context.Background(), client, "sha256:"+strings.Repeat("0", 64)); err == nil {
+		t.Error("expected a mismatch for the wrong digest")
+	}
+}
+
+func TestMock_InspectPinnedMismatch(t *testing.T) {
+	if os.Getenv("TEST_SUBPROCESS") == "1" {
+		runCLI(t, "inspect", "r:local.hello@sha256:"+strings.Repeat("0", 64))
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMock_InspectPinnedMismatch$")
+	cmd.Env = append(os.Environ(), "TEST_SUBPROCESS=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("expected subprocess to exit with error")
+	}
+	if !strings.Contains(string(out), "Digest mismatch") {
+		t.Errorf("expected 'Digest mismatch' in output, got: %s", out)
+	}
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	contextCmd.AddCommand(contextDiscoverCmd)
+	contextDiscoverCmd.Flags().BoolP("yes", "y", false, "Add every new server found without asking")
+}
+
+// serverPort is the port the CYFR server listens on inside its container.
+const serverPort = 4000
+
+var contextDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find local CYFR servers running in Docker",
+	Long: `Look through running Docker containers for CYFR servers, work out which
+host port each one is published on, and check it answers /api/health.
+Servers not yet configured are offered as new contexts, named after their
+container (--yes adds them all without asking). With --json, the servers
+found are printed and nothing is added unless --yes is given.`,
+	Example: `  cyfr context discover
+  cyfr context discover --yes
+  cyfr context discover --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := statusContext(cmd)
+		defer cancel()
+
+		containers, err := dockerContainers(ctx)
+		if err != nil {
+			return output.Errorf("Failed to list Docker containers: %v", err)
+		}
+		cfg := loadConfigOrDefault()
+		found := discoverServers(ctx, cfg, containers)
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		if flagJSON {
+			if yes {
+				if _, err := addDiscovered(found, func(discoveredServer) bool { return true }); err != nil {
+					return err
+				}
+			}
+			out := make([]map[string]any, len(found))
+			for i, s := range found {
+				out[i] = s.JSON()
+			}
+			output.JSON(map[string]any{"servers": out, "count": len(out)})
+			return nil
+		}
+
+		if len(found) == 0 {
+			fmt.Println("No CYFR servers found in running Docker containers.")
+			return nil
+		}
+		rows := make([]map[string]string, len(found))
+		for i, s := range found {
+			rows[i] = s.Row()
+		}
+		output.Table([]string{"CONTAINER", "IMAGE", "URL", "HEALTHY", "CONTEXT"}, rows)
+
+		added, err := addDiscovered(found, func(s discoveredServer) bool {
+			return yes || confirm(fmt.Sprintf("Add context '%s' (%s)?", s.Name, s.URL))
+		})
+		if err != nil {
+			return err
+		}
+		for _, s := range added {
+			fmt.Printf("Added context '%s' (%s)\n", s.Name, s.URL)
+		}
+		return nil
+	},
+}
+
+// dockerContainer is a running container as listed by docker ps.
+type dockerContainer struct {
+	ID    string `json:"ID"`
+	Image string `json:"Image"`
+	Names string `json:"Names"`
+	Ports string `json:"Ports"`
+}
+
+// dockerContainers lists the running containers.
+func dockerContainers(ctx context.Context) ([]dockerContainer, error) {
+	out, err := exec.CommandContext(ctx, "docker", "ps", "--format", "{{json .}}").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	var containers []dockerContainer
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var c dockerContainer
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, fmt.Errorf("unexpected docker ps output: %w", err)
+		}
+		containers = append(containers, c)
+	}
+	return containers, scanner.Err()
+}
+
+// publishedPort is a container port published on the host.
+type publishedPort struct {
+	Host      int
+	Container int
+}
+
+// parsePorts parses the Ports column of docker ps, e.g.
+// "0.0.0.0:4001->4000/tcp, :::4001->4000/tcp", into the TCP ports
+// published on the host, without duplicates across address families.
+func parsePorts(s string) []publishedPort {
+	var ports []publishedPort
+	seen := make(map[publishedPort]bool)
+	for _, part := range strings.Split(s, ",") {
+		host, container, ok := strings.Cut(strings.TrimSpace(part), "->")
+		if !ok || !strings.HasSuffix(container, "/tcp") {
+			continue
+		}
+		hostPort, err1 := strconv.Atoi(host[strings.LastIndex(host, ":")+1:])
+		containerPort, err2 := strconv.Atoi(strings.TrimSuffix(container, "/tcp"))
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		p := publishedPort{Host: hostPort, Container: containerPort}
+		if !seen[p] {
+			seen[p] = true
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
+
+// isCYFRImage reports whether a container image looks like a CYFR server.
+func isCYFRImage(image string) bool {
+	name, _, _ := strings.Cut(image[strings.LastIndex(image, "/")+1:], ":")
+	return name == "cyfr" || strings.HasPrefix(name, "cyfr-") || strings.Contains(image, "cyfrworks/")
+}
+
+// discoveredServer is a CYFR server found in a container.
+type discoveredServer struct {
+	Container string
+	Image     string
+	Name      string // context name to add it as
+	URL       string
+	Healthy   bool
+	Context   string // existing context with this URL, if any
+}
+
+func (s discoveredServer) JSON() map[string]any {
+	out := map[string]any{
+		"container": s.Container,
+		"image":     s.Image,
+		"url":       s.URL,
+		"healthy":   s.Healthy,
+		"name":      s.Name,
+	}
+	if s.Context != "" {
+		out["context"] = s.Context
+	}
+	return out
+}
+
+func (s discoveredServer) Row() map[string]string {
+	healthy := "no"
+	if s.Healthy {
+		healthy = "yes"
+	}
+	existing := s.Context
+	if existing == "" {
+		existing = "-"
+	}
+	return map[string]string{
+		"CONTAINER": s.Container,
+		"IMAGE":     s.Image,
+		"URL":       s.URL,
+		"HEALTHY":   healthy,
+		"CONTEXT":   existing,
+	}
+}
+
+// discoverServers finds the CYFR servers among containers: the host port
+// of the server's port, or any published port answering /api/health.
+func discoverServers(ctx context.Context, cfg *config.Config, containers []dockerContainer) []discoveredServer {
+	var found []discoveredServer
+	for _, c := range containers {
+		if !isCYFRImage(c.Image) {
+			continue
+		}
+		ports := parsePorts(c.Ports)
+		if len(ports) == 0 {
+			continue
+		}
+		// The server's own port first; other published ports only if it
+		// isn't published.
+		sort.SliceStable(ports, func(i, j int) bool {
+			return ports[i].Container == serverPort && ports[j].Container != serverPort
+		})
+		name := strings.Split(c.Names, ",")[0]
+		s := discoveredServer{Container: name, Image: c.Image, Name: name}
+		for _, p := range ports {
+			url := fmt.Sprintf("http://localhost:%d", p.Host)
+			healthy := checkHealth(ctx, url)
+			if healthy || s.URL == "" {
+				s.URL, s.Healthy = url, healthy
+			}
+			if healthy || p.Container == serverPort {
+				break
+			}
+		}
+		s.Context = contextWithURL(cfg, s.URL)
+		found = append(found, s)
+	}
+	return found
+}
+
+// checkHealth reports whether a server answers /api/health at url.
+func checkHealth(ctx context.Context, url string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return mcp.NewClient(url).Health(ctx) == nil
+}
+
+// contextWithURL returns the name of a context with url, or "".
+func contextWithURL(cfg *config.Config, url string) string {
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.TrimSuffix(cfg.Contexts[name].URL, "/") == url {
+			return name
+		}
+	}
+	return ""
+}
+
+// addDiscovered adds the healthy servers without a context that ok
+// accepts, recording the context each was added as in found, and returns
+// those added. A name already taken gets a numeric suffix.
+func addDiscovered(found []discoveredServer, ok func(discoveredServer) bool) ([]discoveredServer, error) {
+	var add []int
+	for i, s := range found {
+		if s.Healthy && s.Context == "" && ok(s) {
+			add = append(add, i)
+		}
+	}
+	if len(add) == 0 {
+		return nil, nil
+	}
+	_, err := config.Update(func(cfg *config.Config) error {
+		for _, i := range add {
+			name := found[i].Name
+			for n := 2; cfg.Contexts[name] != nil; n++ {
+				name = fmt.Sprintf("%s-%d", found[i].Name, n)
+			}
+			cfg.Contexts[name] = &config.Context{URL: found[i].URL}
+			found[i].Name, found[i].Context = name, name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+	}
+	added := make([]discoveredServer, len(add))
+	for j, i := range add {
+		added[j] = found[i]
+	}
+	return added, nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePorts(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []publishedPort
+	}{
+		{"0.0.0.0:4001->4000/tcp, :::4001->4000/tcp", []publishedPort{{4001, 4000}}},
+		{"127.0.0.1:4000->4000/tcp, 0.0.0.0:9090->9090/tcp", []publishedPort{{4000, 4000}, {9090, 9090}}},
+		{"4000/tcp", nil},
+		{"0.0.0.0:5353->5353/udp", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := parsePorts(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePorts(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsCYFRImage(t *testing.T) {
+	for image, want := range map[string]bool{
+		"ghcr.io/cyfrworks/cyfr:latest": true,
+		"cyfr:dev":                      true,
+		"cyfr-server":                   true,
+		"postgres:16":                   false,
+		"ghcr.io/acme/cyfrish:1":        false,
+	} {
+		if got := isCYFRImage(image); got != want {
+			t.Errorf("isCYFRImage(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
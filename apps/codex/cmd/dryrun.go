@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// dryRunExecution resolves everything a run would use — the component
+// artifact, the effective host policy, and the granted secrets — and prints
+// it alongside the exact execution tool call, without executing anything.
+//
+// Each lookup is independent: a failure is reported in its section rather
+// than aborting, since a failing lookup is often the answer to "why was my
+// run denied".
+func dryRunExecution(ctx context.Context, client *mcp.Client, refMap map[string]any, input map[string]any, opts runOptions) {
+	toolArgs := buildRunArgs(refMap, input, opts)
+	report := map[string]any{
+		"dry_run": true,
+		"tool_call": map[string]any{
+			"name":      "execution",
+			"arguments": toolArgs.Map(),
+		},
+	}
+
+	registryRef, isRegistry := refMap["registry"].(string)
+	if localPath, ok := refMap["local"].(string); ok {
+		report["artifact"] = map[string]any{"local": localPath}
+	}
+	if ociRef, ok := refMap["oci"].(string); ok {
+		report["reference"] = ociRef
+		report["artifact"] = map[string]any{"oci": ociRef}
+	}
+
+	if isRegistry {
+		report["reference"] = registryRef
+
+		if result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+			Action:    typed.ComponentActionResolve,
+			Reference: registryRef,
+		}); err != nil {
+			report["artifact"] = map[string]any{"error": err.Error()}
+		} else if component, ok := result["component"].(map[string]any); ok {
+			report["artifact"] = component
+		} else {
+			report["artifact"] = result
+		}
+
+		if result, err := typed.New(client).Policy(ctx, typed.PolicyArgs{
+			Action:       typed.PolicyActionGetEffective,
+			ComponentRef: registryRef,
+		}); err != nil {
+			report["policy"] = map[string]any{"error": err.Error()}
+		} else {
+			report["policy"] = result
+		}
+
+		if granted, unresolved, err := grantedSecrets(ctx, client, registryRef); err != nil {
+			report["granted_secrets"] = map[string]any{"error": err.Error()}
+		} else {
+			report["granted_secrets"] = granted
+			if len(unresolved) > 0 {
+				report["unresolved_secrets"] = unresolved
+			}
+		}
+	} else {
+		report["reference"] = referenceString(refMap)
+		report["note"] = "Local file references are not registered, so no policy or secret grants are resolved."
+	}
+
+	if flagJSON {
+		output.JSON(report)
+		return
+	}
+
+	fmt.Println("Dry run — nothing was executed.")
+	fmt.Println("")
+	fmt.Printf("Reference: %s\n", report["reference"])
+	printDryRunSection("Artifact", report["artifact"])
+	if _, ok := report["policy"]; ok {
+		printDryRunSection("Effective policy", report["policy"])
+	}
+	if names, ok := report["granted_secrets"].([]string); ok {
+		fmt.Println("")
+		fmt.Println("Granted secrets:")
+		if len(names) == 0 {
+			fmt.Println("  (none)")
+		}
+		for _, n := range names {
+			fmt.Printf("  %s\n", n)
+		}
+	} else if v, ok := report["granted_secrets"]; ok {
+		printDryRunSection("Granted secrets", v)
+	}
+	if unresolved, ok := report["unresolved_secrets"].(map[string]string); ok {
+		fmt.Println("")
+		fmt.Println("Secrets whose grant could not be checked:")
+		names := make([]string, 0, len(unresolved))
+		for n := range unresolved {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Printf("  %s: %s\n", n, unresolved[n])
+		}
+	}
+	if note, ok := report["note"].(string); ok {
+		fmt.Println("")
+		fmt.Println(note)
+	}
+
+	fmt.Println("")
+	fmt.Println("Tool call:")
+	callJSON, _ := json.MarshalIndent(report["tool_call"], "  ", "  ")
+	fmt.Printf("  %s\n", callJSON)
+}
+
+func printDryRunSection(title string, v any) {
+	fmt.Println("")
+	fmt.Printf("%s:\n", title)
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		fmt.Println("  (none)")
+		return
+	}
+	output.KeyValue(m)
+}
+
+// grantedSecrets returns the sorted names of the secrets ref is granted,
+// checking each of the server's secrets with can_access so no secret value
+// leaves the server. Secrets whose check fails are returned with the error
+// rather than dropped.
+func grantedSecrets(ctx context.Context, client *mcp.Client, ref string) ([]string, map[string]string, error) {
+	names, err := secretNames(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	granted := []string{}
+	unresolved := map[string]string{}
+	for name := range names {
+		result, err := typed.New(client).Secret(ctx, typed.SecretArgs{
+			Action:       typed.SecretActionCanAccess,
+			Name:         name,
+			ComponentRef: ref,
+		})
+		if err != nil {
+			unresolved[name] = err.Error()
+			continue
+		}
+		if allowed, _ := result["allowed"].(bool); allowed {
+			granted = append(granted, name)
+		}
+	}
+	sort.Strings(granted)
+	return granted, unresolved, nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestGrantedSecrets(t *testing.T) {
+	tests := []struct {
+		name           string
+		canAccess      any
+		canAccessError string
+		wantGranted    []string
+		wantUnresolved map[string]string
+	}{
+		{
+			name:           "granted",
+			canAccess:      map[string]any{"allowed": true},
+			wantGranted:    []string{"API_KEY", "DB_URL"},
+			wantUnresolved: map[string]string{},
+		},
+		{
+			name:           "not granted",
+			canAccess:      map[string]any{"allowed": false},
+			wantGranted:    []string{},
+			wantUnresolved: map[string]string{},
+		},
+		{
+			name:           "check fails",
+			canAccessError: "Failed to check secret access: :unavailable",
+			wantGranted:    []string{},
+			wantUnresolved: map[string]string{
+				"API_KEY": "Failed to check secret access: :unavailable",
+				"DB_URL":  "Failed to check secret access: :unavailable",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := mockserver.DefaultFixtures()
+			f.Responses["secret.list"] = map[string]any{"secrets": []any{"DB_URL", "API_KEY"}}
+			f.Responses["secret.can_access"] = tt.canAccess
+			if tt.canAccessError != "" {
+				f.Errors = map[string]string{"secret.can_access": tt.canAccessError}
+			}
+			srv := httptest.NewServer(mockserver.New(f))
+			defer srv.Close()
+
+			granted, unresolved, err := grantedSecrets(context.Background(), mcp.NewClient(srv.URL), "catalyst:local.claude:0.1.0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(granted, tt.wantGranted) {
+				t.Errorf("granted = %v, want %v", granted, tt.wantGranted)
+			}
+			if !reflect.DeepEqual(unresolved, tt.wantUnresolved) {
+				t.Errorf("unresolved = %v, want %v", unresolved, tt.wantUnresolved)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// An environment is an extra instance of the project's server, defined
+// under environments in cyfr.yaml, that runs beside the default one with
+// its own port, data and compose project:
+//
+//	environments:
+//	  test:
+//	    port: 4100
+//	    data: ./data-test
+type environment struct {
+	Name string
+	Port int
+	Data string // the data directory, ./data-<name> by default
+}
+
+// environmentNamePattern matches the names environments can have, which go
+// into compose project and file names.
+var environmentNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// parseEnvironments returns the environments defined in the cyfr.yaml data.
+// Only the environments block is read: a key at the start of a line, one
+// indented key per environment, and its settings indented further.
+func parseEnvironments(data string) (map[string]*environment, error) {
+	envs := map[string]*environment{}
+	var env *environment
+	envIndent := -1
+	in := false
+	for i, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == 0 {
+			in = trimmed == "environments:"
+			env = nil
+			continue
+		}
+		if !in {
+			continue
+		}
+		key, value, _ := strings.Cut(trimmed, ":")
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"'`)
+		if envIndent < 0 || indent <= envIndent {
+			if value != "" {
+				return nil, fmt.Errorf("cyfr.yaml line %d: expected an environment name followed by its settings", i+1)
+			}
+			if !environmentNamePattern.MatchString(key) {
+				return nil, fmt.Errorf("cyfr.yaml line %d: invalid environment name %q (use lowercase letters, digits, - and _)", i+1, key)
+			}
+			envIndent = indent
+			env = &environment{Name: key, Data: "./data-" + key}
+			envs[key] = env
+			continue
+		}
+		switch key {
+		case "port":
+			port, err := strconv.Atoi(value)
+			if err != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("cyfr.yaml line %d: invalid port %q for environment %s", i+1, value, env.Name)
+			}
+			env.Port = port
+		case "data":
+			env.Data = value
+		default:
+			return nil, fmt.Errorf("cyfr.yaml line %d: unknown setting %q for environment %s (use port or data)", i+1, key, env.Name)
+		}
+	}
+	for _, env := range envs {
+		if env.Port == 0 {
+			return nil, fmt.Errorf("cyfr.yaml: environment %s needs a port", env.Name)
+		}
+	}
+	return envs, nil
+}
+
+// lookupEnvironment returns the environment called name of the project in
+// the working directory.
+func lookupEnvironment(name string) (*environment, error) {
+	data, err := os.ReadFile("cyfr.yaml")
+	if err != nil {
+		return nil, output.Errorf("Environments are defined in cyfr.yaml, which can't be read: %v", err)
+	}
+	envs, err := parseEnvironments(string(data))
+	if err != nil {
+		return nil, output.NewError(output.CodeConfig, "%v", err)
+	}
+	env, ok := envs[name]
+	if !ok {
+		names := make([]string, 0, len(envs))
+		for n := range envs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, output.NewError(output.CodeNotFound, "cyfr.yaml defines no environments; add %s under environments", name)
+		}
+		return nil, output.NewError(output.CodeNotFound, "No environment %q in cyfr.yaml (defined: %s)", name, strings.Join(names, ", "))
+	}
+	return env, nil
+}
+
+// composeFile returns where the docker-compose.yml of env is generated.
+func (e *environment) composeFile() string {
+	return filepath.Join(".cyfr", "compose."+e.Name+".yml")
+}
+
+// composeProject returns the compose project name of env, which keeps its
+// containers and volumes apart from the default instance's.
+func (e *environment) composeProject() string {
+	dir, _ := os.Getwd()
+	base := strings.ToLower(cyfrYAMLValue("name"))
+	if base == "" {
+		base = strings.ToLower(filepath.Base(dir))
+	}
+	base = regexp.MustCompile(`[^a-z0-9_-]+`).ReplaceAllString(base, "-")
+	return strings.Trim(base, "-_") + "-" + e.Name
+}
+
+// composeDataPattern matches the entry of a docker-compose.yml volumes list
+// that mounts ./data, capturing what comes before the path.
+var composeDataPattern = regexp.MustCompile(`(?m)^(\s*-\s*"?)\./data:`)
+
+// writeCompose generates the docker-compose.yml of env from the project's,
+// publishing its port and mounting its data directory instead.
+func (e *environment) writeCompose() error {
+	data, err := os.ReadFile("docker-compose.yml")
+	if err != nil {
+		return err
+	}
+	compose := string(data)
+	if _, ok := composePort(compose); !ok {
+		return fmt.Errorf("docker-compose.yml doesn't publish port %d", serverPort)
+	}
+	compose = setComposePort(compose, e.Port)
+	compose = composeDataPattern.ReplaceAllString(compose, "${1}"+strings.ReplaceAll(e.Data, "$", "$$")+":")
+	header := fmt.Sprintf("# Generated by cyfr from docker-compose.yml for the %s environment; edit that and cyfr.yaml instead.\n", e.Name)
+	if err := os.MkdirAll(filepath.Dir(e.composeFile()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(e.composeFile(), []byte(header+compose), 0644)
+}
+
+// runtimeForEnv returns the project's container runtime, working on the
+// environment named by cmd's --env flag if it has one, which it returns
+// too.
+func runtimeForEnv(cmd *cobra.Command) (containerRuntime, *environment, error) {
+	runtime, err := projectRuntime()
+	if err != nil {
+		return runtime, nil, err
+	}
+	name, _ := cmd.Flags().GetString("env")
+	if name == "" {
+		return runtime, nil, nil
+	}
+	env, err := lookupEnvironment(name)
+	if err != nil {
+		return runtime, nil, err
+	}
+	if err := env.writeCompose(); err != nil {
+		return runtime, nil, output.Errorf("Failed to set up the %s environment: %v", name, err)
+	}
+	// Compose only merges the override file by itself without -f.
+	files := []string{"-f", env.composeFile()}
+	if _, err := os.Stat(composeOverrideFile); err == nil {
+		files = append(files, "-f", composeOverrideFile)
+	}
+	runtime.composeArgs = append(files, "-p", env.composeProject(), "--project-directory", ".")
+	return runtime, env, nil
+}
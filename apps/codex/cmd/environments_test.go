@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvironments(t *testing.T) {
+	envs, err := parseEnvironments(`name: demo
+port: 4000
+environments:
+  # For integration tests.
+  test:
+    port: 4100
+  staging:
+    port: "4200"
+    data: ./staging
+registry: ghcr.io/acme
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 2 {
+		t.Fatalf("environments = %v", envs)
+	}
+	if e := envs["test"]; e.Port != 4100 || e.Data != "./data-test" {
+		t.Errorf("test = %+v", *e)
+	}
+	if e := envs["staging"]; e.Port != 4200 || e.Data != "./staging" {
+		t.Errorf("staging = %+v", *e)
+	}
+
+	for _, bad := range []string{
+		"environments:\n  test:\n    data: ./x\n",
+		"environments:\n  test:\n    port: 99999\n",
+		"environments:\n  test:\n    image: x\n",
+		"environments:\n  Test Env:\n    port: 4100\n",
+	} {
+		if _, err := parseEnvironments(bad); err == nil {
+			t.Errorf("expected an error for:\n%s", bad)
+		}
+	}
+}
+
+func TestEnvironmentCompose(t *testing.T) {
+	chdirTemp(t)
+	opts := defaultInitOptions()
+	opts.Name = "My Project"
+	os.WriteFile("docker-compose.yml", []byte(opts.compose()), 0644)
+	os.WriteFile("cyfr.yaml", []byte(opts.cyfrYAML()+"environments:\n  test:\n    port: 4100\n"), 0644)
+
+	env, err := lookupEnvironment("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lookupEnvironment("prod"); err == nil || !strings.Contains(err.Error(), "defined: test") {
+		t.Errorf("lookupEnvironment(prod) error = %v", err)
+	}
+	if got := env.composeProject(); got != "my-project-test" {
+		t.Errorf("compose project = %q", got)
+	}
+	if err := env.writeCompose(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(env.composeFile())
+	compose := string(data)
+	for _, want := range []string{`"4100:4000"`, "- ./data-test:/app/data", "- ./components:/app/components"} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("compose file doesn't contain %q:\n%s", want, compose)
+		}
+	}
+}
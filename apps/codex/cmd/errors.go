@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func init() {
+	output.ErrorCode = errorCode
+}
+
+// errorCode returns the JSON error code for errors from the MCP client
+// and the network, or "" if it has none. Tool errors only carry a
+// message, so one saying something wasn't found is taken at its word.
+func errorCode(err error) string {
+	var unsupported *mcp.UnsupportedError
+	var argErr *mcp.ArgumentError
+	var rpcErr *mcp.JSONRPCError
+	var netErr net.Error
+	switch {
+	case isInterrupted(err):
+		return output.CodeInterrupted
+	case errors.Is(err, context.DeadlineExceeded):
+		return output.CodeTimeout
+	case errors.Is(err, mcp.ErrSessionExpired):
+		return output.CodeSessionExpired
+	case errors.Is(err, mcp.ErrSessionRequired):
+		return output.CodeNotLoggedIn
+	case errors.Is(err, mcp.ErrTokenExpired):
+		return output.CodeTokenExpired
+	case errors.As(err, &unsupported), errors.Is(err, mcp.ErrStreamingUnsupported):
+		return output.CodeUnsupported
+	case errors.As(err, &argErr):
+		return output.CodeInvalidArgument
+	case errors.As(err, &rpcErr):
+		switch rpcErr.Code {
+		case mcp.CodeMethodNotFound:
+			return output.CodeUnsupported
+		case mcp.CodeInvalidParams:
+			return output.CodeInvalidArgument
+		}
+		return output.CodeServerError
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return output.CodeTimeout
+		}
+		return output.CodeUnreachable
+	case strings.Contains(strings.ToLower(err.Error()), "not found"):
+		return output.CodeNotFound
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("call tool: %w", mcp.ErrSessionExpired), output.CodeSessionExpired},
+		{mcp.ErrSessionRequired, output.CodeNotLoggedIn},
+		{mcp.ErrTokenExpired, output.CodeTokenExpired},
+		{&mcp.UnsupportedError{}, output.CodeUnsupported},
+		{&mcp.ArgumentError{Tool: "key"}, output.CodeInvalidArgument},
+		{&mcp.JSONRPCError{Code: mcp.CodeMethodNotFound}, output.CodeUnsupported},
+		{&mcp.JSONRPCError{Code: mcp.CodeInvalidParams}, output.CodeInvalidArgument},
+		{&mcp.JSONRPCError{Code: -32000}, output.CodeServerError},
+		{fmt.Errorf("http request: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), output.CodeUnreachable},
+		{fmt.Errorf("request timed out after 1s: %w", context.DeadlineExceeded), output.CodeTimeout},
+		{context.Canceled, output.CodeInterrupted},
+		{errors.New("component r:local.nope:1.0.0 not found"), output.CodeNotFound},
+		{errors.New("boom"), ""},
+	}
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestToolError(t *testing.T) {
+	err := toolError(fmt.Errorf("call tool: %w", mcp.ErrSessionExpired))
+	if err.Code != output.CodeSessionExpired || err.Message != "Session expired. Run 'cyfr login' to re-authenticate." {
+		t.Errorf("got %q: %q", err.Code, err.Message)
+	}
+	err = toolError(errors.New("boom"))
+	if output.Code(err) != output.CodeError || err.Message != "Failed: boom" {
+		t.Errorf("got %q: %q", output.Code(err), err.Message)
+	}
+}
+
+func TestJSONErrorOutput(t *testing.T) {
+	if os.Getenv("TEST_SUBPROCESS") == "1" {
+		resetFlags(rootCmd)
+		rootCmd.SetArgs([]string{"context", "set", "nope", "--json"})
+		Execute()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestJSONErrorOutput$")
+	cmd.Env = append(os.Environ(), "TEST_SUBPROCESS=1", "HOME="+t.TempDir(), "XDG_CONFIG_HOME=", "CYFR_CONFIG_PATH=")
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit status 1, got %v", err)
+	}
+	var result struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("stdout is not a JSON error: %v\n%s", err, out)
+	}
+	if result.Error.Code != output.CodeNotFound || result.Error.Message == "" {
+		t.Errorf("unexpected error: %+v", result.Error)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// printNotification writes a server notification to stderr as a single
+// line, keeping stdout free for the command's result.
+func printNotification(n mcp.Notification) {
+	fmt.Fprintln(os.Stderr, formatNotification(n))
+}
+
+// formatNotification renders a notification for display. Progress and log
+// message notifications get a compact form; anything else is shown as JSON.
+func formatNotification(n mcp.Notification) string {
+	switch n.Method {
+	case "notifications/progress":
+		line := fmt.Sprintf("[progress] %v", n.Params["progress"])
+		if total, ok := n.Params["total"]; ok {
+			line += fmt.Sprintf("/%v", total)
+		}
+		if msg, ok := n.Params["message"].(string); ok && msg != "" {
+			line += " " + msg
+		}
+		return line
+
+	case "notifications/message":
+		level, _ := n.Params["level"].(string)
+		if level == "" {
+			level = "info"
+		}
+		data := n.Params["data"]
+		if s, ok := data.(string); ok {
+			return fmt.Sprintf("[%s] %s", level, s)
+		}
+		b, _ := json.Marshal(data)
+		return fmt.Sprintf("[%s] %s", level, b)
+	}
+
+	b, _ := json.Marshal(n.Params)
+	return fmt.Sprintf("[%s] %s", n.Method, b)
+}
+
+// showProgress draws progress notifications for the client's tool calls as
+// a progress bar on stderr, with a spinner showing the elapsed time until
+// the first one arrives. Call the returned function once the call returns
+// to clear the bar before printing results.
+func showProgress(client *mcp.Client, label string) func() {
+	spinner := output.NewSpinner(label)
+	bar := output.NewProgress(label)
+	client.OnProgress = func(p mcp.Progress) {
+		spinner.Stop()
+		bar.Update(p.Progress, p.Total, p.Message)
+	}
+	return func() {
+		spinner.Stop()
+		bar.Done()
+	}
+}
+
+// followNotifications opens the server's notification stream and prints
+// every notification until the stream ends or ctx is cancelled (Ctrl-C is
+// the normal way to stop following). In --json mode each notification is
+// written to stdout as one JSON object per line.
+func followNotifications(ctx context.Context, client *mcp.Client) error {
+	sub, err := client.SubscribeCtx(ctx)
+	if err != nil {
+		return output.Errorf("Failed to follow: %v", err)
+	}
+	defer sub.Close()
+
+	for n := range sub.C {
+		if flagJSON {
+			b, _ := json.Marshal(n)
+			fmt.Println(string(b))
+		} else {
+			fmt.Println(formatNotification(n))
+		}
+	}
+	if err := sub.Err(); err != nil && !isInterrupted(err) {
+		return output.Errorf("Stream ended: %v", err)
+	}
+	return nil
+}
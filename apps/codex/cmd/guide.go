@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -27,19 +28,23 @@ var guideListCmd = &cobra.Command{
 	Long:  "List all available CYFR documentation guides.",
 	Example: `  cyfr guide list
   cyfr guide list --json`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("guide", map[string]any{
-			"action": "list",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Guide(cmd.Context(), typed.GuideArgs{
+			Action: typed.GuideActionList,
 		})
 		if err != nil {
-			handleToolError(err)
+			return toolError(err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -50,20 +55,25 @@ var guideGetCmd = &cobra.Command{
 	Example: `  cyfr guide get component-guide
   cyfr guide get integration-guide --json`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("guide", map[string]any{
-			"action": "get",
-			"name":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Guide(cmd.Context(), typed.GuideArgs{
+			Action: typed.GuideActionGet,
+			Name:   args[0],
 		})
 		if err != nil {
-			handleToolError(err)
+			return toolError(err)
 		}
+		pageOutput()
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Println(result["content"])
 		}
+		return nil
 	},
 }
 
@@ -74,19 +84,23 @@ var guideReadmeCmd = &cobra.Command{
 	Example: `  cyfr guide readme c:local.claude:0.1.0
   cyfr guide readme local.sentiment:1.0.0 --json`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("guide", map[string]any{
-			"action":    "readme",
-			"reference": args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Guide(cmd.Context(), typed.GuideArgs{
+			Action:    typed.GuideActionReadme,
+			Reference: args[0],
 		})
 		if err != nil {
-			handleToolError(err)
+			return toolError(err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Println(result["content"])
 		}
+		return nil
 	},
 }
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cyfr/codex/internal/history"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyRerunCmd)
+	rootCmd.AddCommand(rerunCmd)
+
+	historyListCmd.Flags().Int("limit", 20, "Number of most recent runs to show (0 for all)")
+	rerunCmd.Flags().Bool("last", false, "Re-run the most recent run")
+}
+
+var historyCmd = &cobra.Command{
+	Use:     "history",
+	Short:   "Browse and replay past runs",
+	GroupID: "exec",
+	Long:    "Every 'cyfr run' is recorded in a local journal (~/.cyfr/history.db) with its reference, input, execution ID, status, and duration. Use history to list past runs, inspect one, or re-run it with exactly the same reference and input.",
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded runs",
+	Long:  "List recorded runs, most recent last.",
+	Example: `  cyfr history list
+  cyfr history list --limit 0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		path, err := history.DefaultPath()
+		if err != nil {
+			return output.Errorf("Failed to locate history: %v", err)
+		}
+		entries, err := history.Load(path)
+		if err != nil {
+			return output.Errorf("Failed to read history: %v", err)
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		if flagJSON {
+			if entries == nil {
+				entries = []history.Entry{}
+			}
+			output.JSON(entries)
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No runs recorded yet.")
+			return nil
+		}
+
+		headers := []string{"#", "TIME", "REFERENCE", "INPUT", "STATUS", "DURATION", "EXECUTION_ID"}
+		rows := make([]map[string]string, len(entries))
+		for i, e := range entries {
+			rows[i] = map[string]string{
+				"#":            strconv.Itoa(e.ID),
+				"TIME":         e.Time.Local().Format("2006-01-02 15:04:05"),
+				"REFERENCE":    referenceString(e.Reference),
+				"INPUT":        e.InputHash,
+				"STATUS":       e.Status,
+				"DURATION":     fmt.Sprintf("%dms", e.DurationMS),
+				"EXECUTION_ID": e.ExecutionID,
+			}
+		}
+		output.Table(headers, rows)
+		return nil
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:     "show <n>",
+	Short:   "Show a recorded run",
+	Long:    "Show the full record of a past run, including its input.",
+	Example: "  cyfr history show 12",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entry, err := loadHistoryEntry(args[0])
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(entry)
+			return nil
+		}
+		output.KeyValue(map[string]any{
+			"id":           entry.ID,
+			"time":         entry.Time.Local().Format("2006-01-02 15:04:05"),
+			"url":          entry.URL,
+			"reference":    entry.Reference,
+			"input":        entry.Input,
+			"input_hash":   entry.InputHash,
+			"execution_id": entry.ExecutionID,
+			"status":       entry.Status,
+			"duration_ms":  entry.DurationMS,
+			"error":        entry.Error,
+		})
+		return nil
+	},
+}
+
+var historyRerunCmd = &cobra.Command{
+	Use:     "rerun <n>",
+	Short:   "Re-run a recorded run",
+	Long:    "Execute the same reference with the same input as a past run. The run uses the current context, not the one it was originally recorded against.",
+	Example: "  cyfr history rerun 12",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entry, err := loadHistoryEntry(args[0])
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		return executeRun(cmd.Context(), client, entry.Reference, entry.Input, runOptions{Progress: true})
+	},
+}
+
+var rerunCmd = &cobra.Command{
+	Use:     "rerun [n]",
+	Short:   "Re-run a previous execution",
+	GroupID: "exec",
+	Long:    "Shortcut for 'cyfr history rerun'. Pass a history number, or --last to repeat the most recent run.",
+	Example: `  cyfr rerun --last
+  cyfr rerun 12`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		last, _ := cmd.Flags().GetBool("last")
+
+		var entry *history.Entry
+		switch {
+		case last && len(args) == 0:
+			path, err := history.DefaultPath()
+			if err != nil {
+				return output.Errorf("Failed to locate history: %v", err)
+			}
+			entry, err = history.Last(path)
+			if err != nil {
+				return output.Errorf("Nothing to re-run: %v", err)
+			}
+		case !last && len(args) == 1:
+			var err error
+			if entry, err = loadHistoryEntry(args[0]); err != nil {
+				return err
+			}
+		default:
+			return output.NewError(output.CodeInvalidArgument, "Usage: cyfr rerun <n> or cyfr rerun --last")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		return executeRun(cmd.Context(), client, entry.Reference, entry.Input, runOptions{Progress: true})
+	},
+}
+
+// loadHistoryEntry parses a history number argument and loads the entry,
+// returning an error if it cannot be found.
+func loadHistoryEntry(arg string) (*history.Entry, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return nil, output.NewError(output.CodeInvalidArgument, "Invalid history number: %s", arg)
+	}
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, output.Errorf("Failed to locate history: %v", err)
+	}
+	entry, err := history.Get(path, id)
+	if err != nil {
+		return nil, output.Errorf("%v", err)
+	}
+	return entry, nil
+}
+
+// referenceString renders a tool reference map ({"registry": ...} or
+// {"local": ...}) as the string the user originally typed.
+func referenceString(refMap map[string]any) string {
+	for _, key := range []string{"registry", "local", "arca", "oci"} {
+		if s, ok := refMap[key].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultGitHubClientID is the OAuth app new projects sign in with through
+// GitHub unless they bring their own.
+const defaultGitHubClientID = "Ov23lib66tiIwXkgUpwm"
+
+// authProviders are the sign-in providers a project can be set up with.
+var authProviders = []string{"github", "google", "none"}
+
+// initOptions are the choices cyfr init generates a project from.
+type initOptions struct {
+	Name     string
+	Port     int
+	Examples bool
+	Registry string // OCI registry for components, or "" for the server's own
+	Runtime  string // one of containerRuntimes, or "" to use the first installed
+	Auth     string // one of authProviders
+	ClientID string // OAuth client ID of the auth provider
+}
+
+// defaultInitOptions returns the options of a project set up without
+// questions.
+func defaultInitOptions() initOptions {
+	return initOptions{
+		Name:     "my-cyfr-project",
+		Port:     serverPort,
+		Examples: true,
+		Auth:     "github",
+		ClientID: defaultGitHubClientID,
+	}
+}
+
+// check returns an error if the options can't make a working project.
+// Auth is only checked withAuth, when a .env is written for it.
+func (o initOptions) check(withAuth bool) error {
+	if err := checkProjectName(o.Name); err != nil {
+		return err
+	}
+	if o.Port < 1 || o.Port > 65535 {
+		return fmt.Errorf("invalid port %d (use 1-65535)", o.Port)
+	}
+	if err := checkRuntimeName(o.Runtime); err != nil {
+		return err
+	}
+	if !withAuth {
+		return nil
+	}
+	if !slices.Contains(authProviders, o.Auth) {
+		return fmt.Errorf("invalid auth provider %q (use %s)", o.Auth, strings.Join(authProviders, ", "))
+	}
+	if o.Auth != "none" && o.ClientID == "" {
+		return fmt.Errorf("%s sign-in needs an OAuth client ID", o.Auth)
+	}
+	return nil
+}
+
+// checkProjectName returns an error unless name can go into cyfr.yaml as
+// it is.
+func checkProjectName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("the project name can't be empty")
+	}
+	if strings.ContainsAny(name, ":#\"'") {
+		return fmt.Errorf("the project name can't contain : # or quotes")
+	}
+	return nil
+}
+
+// composeOverrideFile is where a project's compose customizations go. Compose
+// merges it over docker-compose.yml, and cyfr creates it once and never
+// changes it.
+const composeOverrideFile = "docker-compose.override.yml"
+
+// composeHeader starts the docker-compose.yml cyfr init generates.
+const composeHeader = "# Generated by cyfr init, which may rewrite it. Put your changes in " + composeOverrideFile + ".\n"
+
+// compose returns the docker-compose.yml of the project.
+func (o initOptions) compose() string {
+	return composeHeader + fmt.Sprintf(`services:
+  cyfr:
+    image: ghcr.io/cyfrworks/cyfr:latest
+    ports:
+      - "%d:%d"
+    volumes:
+      - ./data:/app/data
+      - ./components:/app/components
+    env_file:
+      - .env
+`, o.Port, serverPort)
+}
+
+// composeOverride returns the docker-compose.override.yml a project starts
+// with, which only has examples.
+func composeOverride() string {
+	return `# Your changes to docker-compose.yml. Compose merges this file over it, and
+# cyfr init and cyfr upgrade never touch it. For example:
+#
+# services:
+#   cyfr:
+#     volumes:
+#       - ./certs:/app/certs:ro
+#     environment:
+#       CYFR_LOG_LEVEL: debug
+#     deploy:
+#       resources:
+#         limits:
+#           memory: 1g
+services: {}
+`
+}
+
+// isGeneratedCompose returns true if compose is a docker-compose.yml as
+// cyfr init generates it, publishing any port. Comments are ignored, so
+// files from before the header was added count too.
+func isGeneratedCompose(compose string) bool {
+	port, ok := composePort(compose)
+	if !ok {
+		return false
+	}
+	opts := initOptions{Port: port}
+	return stripComments(compose) == stripComments(opts.compose())
+}
+
+// stripComments returns s without its comment and blank lines.
+func stripComments(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			lines = append(lines, strings.TrimRight(line, " \r"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cyfrYAML returns the cyfr.yaml of the project.
+func (o initOptions) cyfrYAML() string {
+	s := fmt.Sprintf(`name: %s
+port: %d
+host: localhost
+database_path: ./data/cyfr.db
+`, o.Name, o.Port)
+	if o.Registry != "" {
+		s += fmt.Sprintf("registry: %s\n", o.Registry)
+	}
+	if o.Runtime != "" {
+		s += fmt.Sprintf("runtime: %s\n", o.Runtime)
+	}
+	return s
+}
+
+// env returns the .env of the project, with secretKey as its secret key
+// base.
+func (o initOptions) env(secretKey string) string {
+	s := fmt.Sprintf(`CYFR_SECRET_KEY_BASE=%s
+CYFR_PORT=%d
+CYFR_HOST=0.0.0.0
+CYFR_DATABASE_PATH=/app/data/cyfr.db
+`, secretKey, serverPort)
+	switch o.Auth {
+	case "github":
+		s += "CYFR_GITHUB_CLIENT_ID=" + o.ClientID + "\n"
+	case "google":
+		s += "CYFR_GOOGLE_CLIENT_ID=" + o.ClientID + "\n"
+	}
+	return s
+}
+
+// mergeDotEnv returns the existing .env with the variables of generated it
+// doesn't set added at the end, and the names of those. Everything the user
+// set is kept as it is.
+func mergeDotEnv(existing, generated string) (string, []string) {
+	set := map[string]bool{}
+	for _, kv := range parseDotEnv([]byte(existing)) {
+		key, _, _ := strings.Cut(kv, "=")
+		set[key] = true
+	}
+	var added, lines []string
+	for _, line := range strings.Split(generated, "\n") {
+		key, _, ok := strings.Cut(line, "=")
+		if !ok || set[key] {
+			continue
+		}
+		added = append(added, key)
+		lines = append(lines, line)
+	}
+	if len(added) == 0 {
+		return existing, nil
+	}
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
+	}
+	return existing + "# Added by cyfr init\n" + strings.Join(lines, "\n") + "\n", added
+}
+
+// initWizard asks for the options of a new project on a terminal.
+type initWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints question with its default and returns the answer, or def if
+// the answer is empty.
+func (w initWizard) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	line, err := w.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// askUntil asks question until valid accepts the answer, printing why it
+// doesn't.
+func (w initWizard) askUntil(question, def string, valid func(string) error) (string, error) {
+	for {
+		answer, err := w.ask(question, def)
+		if err != nil {
+			return "", err
+		}
+		if err := valid(answer); err != nil {
+			fmt.Fprintf(w.out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// askYesNo asks a yes/no question and returns the answer, or def if the
+// answer is empty.
+func (w initWizard) askYesNo(question string, def bool) (bool, error) {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+	for {
+		fmt.Fprintf(w.out, "%s [%s] ", question, choices)
+		line, err := w.in.ReadString('\n')
+		if err != nil && line == "" {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(w.out, "  answer y or n")
+	}
+}
+
+// run asks for each option, starting from o. Auth is only asked for when
+// withAuth is set, as it goes into a .env that is only written once.
+func (w initWizard) run(o initOptions, withAuth bool) (initOptions, error) {
+	var err error
+	if o.Name, err = w.askUntil("Project name", o.Name, checkProjectName); err != nil {
+		return o, err
+	}
+
+	port, err := w.askUntil("Port for the server", strconv.Itoa(o.Port), func(s string) error {
+		if n, err := strconv.Atoi(s); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("enter a port from 1 to 65535")
+		}
+		return nil
+	})
+	if err != nil {
+		return o, err
+	}
+	o.Port, _ = strconv.Atoi(port)
+
+	if o.Examples, err = w.askYesNo("Download the example components?", o.Examples); err != nil {
+		return o, err
+	}
+
+	registry := o.Registry
+	if registry == "" {
+		registry = "builtin"
+	}
+	if registry, err = w.ask("Component registry: builtin, or an OCI registry such as ghcr.io/acme", registry); err != nil {
+		return o, err
+	}
+	o.Registry = registry
+	if registry == "builtin" {
+		o.Registry = ""
+	}
+
+	if !withAuth {
+		return o, nil
+	}
+	if o.Auth, err = w.askUntil("Sign-in provider ("+strings.Join(authProviders, ", ")+")", o.Auth, func(s string) error {
+		if !slices.Contains(authProviders, s) {
+			return fmt.Errorf("choose one of %s", strings.Join(authProviders, ", "))
+		}
+		return nil
+	}); err != nil {
+		return o, err
+	}
+	switch o.Auth {
+	case "github":
+		if o.ClientID == "" {
+			o.ClientID = defaultGitHubClientID
+		}
+	case "google":
+		if o.ClientID == defaultGitHubClientID {
+			o.ClientID = ""
+		}
+	default:
+		o.ClientID = ""
+		return o, nil
+	}
+	o.ClientID, err = w.askUntil("OAuth client ID", o.ClientID, func(s string) error {
+		if s == "" {
+			return fmt.Errorf("a client ID is needed for %s sign-in", o.Auth)
+		}
+		return nil
+	})
+	return o, err
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestInitWizard(t *testing.T) {
+	answers := strings.Join([]string{
+		"demo",   // name
+		"99999",  // port, out of range
+		"4002",   // port
+		"maybe",  // examples, not yes or no
+		"n",      // examples
+		"",       // registry: builtin
+		"google", // auth
+		"",       // client ID, required for google
+		"gid",    // client ID
+	}, "\n") + "\n"
+	var out strings.Builder
+	w := initWizard{in: bufio.NewReader(strings.NewReader(answers)), out: &out}
+	opts, err := w.run(defaultInitOptions(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := initOptions{Name: "demo", Port: 4002, Examples: false, Auth: "google", ClientID: "gid"}
+	if opts != want {
+		t.Errorf("options = %+v, want %+v", opts, want)
+	}
+	for _, msg := range []string{"enter a port from 1 to 65535", "answer y or n", "a client ID is needed for google sign-in"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("wizard didn't print %q:\n%s", msg, out.String())
+		}
+	}
+	if err := opts.check(true); err != nil {
+		t.Errorf("check: %v", err)
+	}
+	if env := opts.env("key"); !strings.Contains(env, "CYFR_GOOGLE_CLIENT_ID=gid\n") || strings.Contains(env, "GITHUB") {
+		t.Errorf("env =\n%s", env)
+	}
+	if compose := opts.compose(); !strings.Contains(compose, `"4002:4000"`) {
+		t.Errorf("compose doesn't publish port 4002:\n%s", compose)
+	}
+
+	// Without auth, the wizard stops after the registry; accepting every
+	// default keeps the defaults.
+	w = initWizard{in: bufio.NewReader(strings.NewReader("\n\n\nghcr.io/acme\n")), out: io.Discard}
+	opts, err = w.run(defaultInitOptions(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = defaultInitOptions()
+	want.Registry = "ghcr.io/acme"
+	if opts != want {
+		t.Errorf("options = %+v, want %+v", opts, want)
+	}
+	if !strings.Contains(opts.cyfrYAML(), "registry: ghcr.io/acme\n") {
+		t.Errorf("cyfr.yaml =\n%s", opts.cyfrYAML())
+	}
+
+	// Input ending early cancels the wizard.
+	w = initWizard{in: bufio.NewReader(strings.NewReader("demo\n")), out: io.Discard}
+	if _, err := w.run(defaultInitOptions(), true); err == nil {
+		t.Error("expected an error when input ends")
+	}
+}
+
+func TestIsGeneratedCompose(t *testing.T) {
+	opts := defaultInitOptions()
+	opts.Port = 4100
+	generated := opts.compose()
+	if !isGeneratedCompose(generated) {
+		t.Error("generated compose file counts as edited")
+	}
+	if !isGeneratedCompose(strings.TrimPrefix(generated, composeHeader)) {
+		t.Error("compose file without the header counts as edited")
+	}
+	if isGeneratedCompose(generated + "    mem_limit: 1g\n") {
+		t.Error("edited compose file counts as generated")
+	}
+}
+
+func TestMergeDotEnv(t *testing.T) {
+	merged, added := mergeDotEnv("# mine\nCYFR_PORT=5000\nexport OTHER=1", "CYFR_PORT=4000\nCYFR_HOST=0.0.0.0\n")
+	if want := "# mine\nCYFR_PORT=5000\nexport OTHER=1\n# Added by cyfr init\nCYFR_HOST=0.0.0.0\n"; merged != want {
+		t.Errorf("merged = %q, want %q", merged, want)
+	}
+	if len(added) != 1 || added[0] != "CYFR_HOST" {
+		t.Errorf("added = %v", added)
+	}
+	if merged, added := mergeDotEnv("A=1\n", "A=2\n"); merged != "A=1\n" || added != nil {
+		t.Errorf("mergeDotEnv = %q, %v", merged, added)
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cyfr/codex/internal/cache"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/cyfr/codex/internal/wasm"
+)
+
+// localArtifactArgs returns the inspect arguments if they all name local
+// artifacts: .wasm files, or digests of artifacts in the cache. It returns
+// nil if none does, and an error if only some do.
+func localArtifactArgs(args []string) ([]string, error) {
+	var local, other []string
+	for _, arg := range args {
+		for _, item := range ref.SplitList(arg) {
+			if isLocalArtifact(item) {
+				local = append(local, item)
+			} else {
+				other = append(other, item)
+			}
+		}
+	}
+	if len(local) > 0 && len(other) > 0 {
+		return nil, output.NewError(output.CodeInvalidArgument, "Cannot inspect %s with %s: inspect local artifacts and references separately.", local[0], other[0])
+	}
+	return local, nil
+}
+
+// isLocalArtifact reports whether arg is a .wasm file or an artifact
+// digest, sha256:<hex>.
+func isLocalArtifact(arg string) bool {
+	if hex, ok := strings.CutPrefix(arg, ref.DigestAlgorithm); ok {
+		return len(hex) == 64
+	}
+	if !strings.HasSuffix(arg, ".wasm") {
+		return false
+	}
+	info, err := os.Stat(arg)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// readLocalArtifact reads a .wasm file or a cached artifact by digest.
+func readLocalArtifact(arg string) (data []byte, source, path string, err error) {
+	if !strings.HasPrefix(arg, ref.DigestAlgorithm) {
+		data, err = os.ReadFile(arg)
+		if err != nil {
+			return nil, "", "", output.Errorf("Cannot read %s: %v", arg, err)
+		}
+		return data, "file", arg, nil
+	}
+	c, err := cache.Open()
+	if err != nil {
+		return nil, "", "", output.NewError(output.CodeConfig, "Cannot find the cache: %v", err)
+	}
+	data, ok := c.Get(arg)
+	if !ok {
+		return nil, "", "", output.NewError(output.CodeNotFound, "Artifact %s is not in the cache. 'cyfr cache list --json' shows the digests there.", arg)
+	}
+	return data, "cache", c.Path(strings.ToLower(arg)), nil
+}
+
+// localArtifactFor finds the artifact of the component r names on this
+// machine for inspect --local: its .wasm file in components/, or else the
+// one it was last pulled as into the cache.
+func localArtifactFor(r ref.ComponentRef) (string, error) {
+	if path := filepath.Join(componentDir(r), r.Type+".wasm"); r.Type != "" && isLocalArtifact(path) {
+		return path, nil
+	}
+	if c, err := cache.Open(); err == nil {
+		if e, ok := c.Lookup(r.String()); ok {
+			return e.Digest, nil
+		}
+	}
+	return "", output.NewError(output.CodeNotFound, "%s has no artifact in components/ or the cache. Build or pull it first, or inspect it without --local.", r)
+}
+
+// inspectArtifact parses a component binary without the server: its
+// interfaces, the WIT worlds it was built for, its custom sections and how
+// its size breaks down by section kind. A .wasm file in a component
+// directory is shown with the manifest next to it.
+func inspectArtifact(arg string) (map[string]any, error) {
+	data, source, path, err := readLocalArtifact(arg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := wasm.Parse(data)
+	if errors.Is(err, wasm.ErrCoreModule) {
+		return nil, output.NewError(output.CodeInvalidArgument, "%s is %v; wrap it with 'wasm-tools component new' first", arg, err)
+	}
+	if err != nil {
+		return nil, output.NewError(output.CodeInvalidArgument, "%s is not a valid component: %v", arg, err)
+	}
+
+	byKind := map[string]*sectionShare{}
+	var breakdown []*sectionShare
+	for _, s := range c.Sections {
+		k := byKind[s.Kind]
+		if k == nil {
+			k = &sectionShare{Kind: s.Kind}
+			byKind[s.Kind] = k
+			breakdown = append(breakdown, k)
+		}
+		k.Count++
+		k.Size += s.Size
+	}
+	for _, k := range breakdown {
+		k.Percent = float64(k.Size) * 100 / float64(len(data))
+	}
+	sort.SliceStable(breakdown, func(i, j int) bool { return breakdown[i].Size > breakdown[j].Size })
+
+	result := map[string]any{
+		"source":          source,
+		"path":            path,
+		"digest":          artifactDigest(data),
+		"size":            len(data),
+		"imports":         nonNil(c.Imports),
+		"exports":         nonNil(c.Exports),
+		"worlds":          nonNil(c.Worlds),
+		"custom_sections": c.CustomSections,
+		"sections":        breakdown,
+	}
+	if c.CustomSections == nil {
+		result["custom_sections"] = []wasm.Section{}
+	}
+	if source == "file" {
+		if manifest, err := os.ReadFile(filepath.Join(filepath.Dir(path), componentManifestFile)); err == nil {
+			var m map[string]any
+			if json.Unmarshal(manifest, &m) == nil {
+				result["manifest"] = m
+			}
+		}
+	}
+	return result, nil
+}
+
+// A sectionShare is what the sections of one kind take of a binary.
+type sectionShare struct {
+	Kind    string  `json:"kind"`
+	Count   int     `json:"count"`
+	Size    int     `json:"size"`
+	Percent float64 `json:"percent"`
+}
+
+// printArtifact prints an inspectArtifact result.
+func printArtifact(label string, m map[string]any) {
+	fmt.Printf("# %s\n", label)
+	summary := map[string]any{
+		"source": m["source"],
+		"path":   m["path"],
+		"digest": m["digest"],
+		"size":   output.HumanBytes(int64(m["size"].(int))),
+	}
+	if worlds := m["worlds"].([]string); len(worlds) > 0 {
+		summary["worlds"] = strings.Join(worlds, ", ")
+	}
+	if manifest, ok := m["manifest"].(map[string]any); ok {
+		for _, field := range []string{"name", "version", "type"} {
+			if v, ok := manifest[field]; ok {
+				summary["manifest."+field] = v
+			}
+		}
+	}
+	output.KeyValue(summary)
+
+	for _, kind := range []string{"imports", "exports"} {
+		names := m[kind].([]string)
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", kind)
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+
+	if custom := m["custom_sections"].([]wasm.Section); len(custom) > 0 {
+		fmt.Println("\ncustom sections:")
+		rows := make([]map[string]string, len(custom))
+		for i, s := range custom {
+			rows[i] = map[string]string{"NAME": s.Name, "SIZE": output.HumanBytes(int64(s.Size))}
+		}
+		output.Table([]string{"NAME", "SIZE"}, rows)
+	}
+
+	fmt.Println("\nsize by section:")
+	sections := m["sections"].([]*sectionShare)
+	rows := make([]map[string]string, len(sections))
+	for i, s := range sections {
+		rows[i] = map[string]string{
+			"KIND":  s.Kind,
+			"COUNT": fmt.Sprint(s.Count),
+			"SIZE":  output.HumanBytes(int64(s.Size)),
+			"SHARE": fmt.Sprintf("%.1f%%", s.Percent),
+		}
+	}
+	output.Table([]string{"KIND", "COUNT", "SIZE", "SHARE"}, rows)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/cache"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+func TestInspectArtifact_File(t *testing.T) {
+	chdirTemp(t)
+	data := componentBinary([]string{"cyfr:http/fetch@0.1.0"}, []string{"cyfr:catalyst/run@0.1.0"})
+	dir := writeComponent(t, "catalyst", "demo", `{"name": "demo", "version": "0.1.0"}`, map[string]string{"catalyst.wasm": string(data)})
+	path := filepath.Join(dir, "catalyst.wasm")
+
+	artifacts, err := localArtifactArgs([]string{path})
+	if err != nil || len(artifacts) != 1 {
+		t.Fatalf("localArtifactArgs = %v, %v", artifacts, err)
+	}
+	result, err := inspectArtifact(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["digest"] != artifactDigest(data) || result["size"] != len(data) || result["source"] != "file" {
+		t.Errorf("result = %v", result)
+	}
+	if got := result["exports"]; !reflect.DeepEqual(got, []string{"cyfr:catalyst/run@0.1.0"}) {
+		t.Errorf("exports = %v", got)
+	}
+	if m, _ := result["manifest"].(map[string]any); m["name"] != "demo" {
+		t.Errorf("manifest = %v", result["manifest"])
+	}
+	var total int
+	for _, s := range result["sections"].([]*sectionShare) {
+		total += s.Size
+	}
+	if total != len(data)-8 {
+		t.Errorf("sections take %d of %d bytes after the header", total, len(data))
+	}
+
+	flagJSON = false
+	out, _ := captureStdout(func() { printArtifact(path, result) })
+	for _, want := range []string{"exports:\n  cyfr:catalyst/run@0.1.0", "size by section:", "manifest.name:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInspectArtifact_Cached(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	c, _ := cache.Open()
+	e, err := c.Put("catalyst:acme.sentiment:1.2.0", componentBinary(nil, []string{"cyfr:catalyst/run@0.1.0"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := ref.Parse("c:acme.sentiment:1.2.0")
+	artifact, err := localArtifactFor(r)
+	if err != nil || artifact != e.Digest {
+		t.Fatalf("localArtifactFor = %q, %v", artifact, err)
+	}
+	result, err := inspectArtifact(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["source"] != "cache" || result["digest"] != e.Digest {
+		t.Errorf("result = %v", result)
+	}
+
+	r, _ = ref.Parse("c:acme.sentiment:1.3.0")
+	if _, err := localArtifactFor(r); output.Code(err) != output.CodeNotFound {
+		t.Errorf("uncached: err = %v", err)
+	}
+}
+
+func TestInspectArtifact_Errors(t *testing.T) {
+	chdirTemp(t)
+	os.WriteFile("core.wasm", []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}, 0644)
+	if _, err := inspectArtifact("core.wasm"); err == nil || !strings.Contains(err.Error(), "core WebAssembly module") {
+		t.Errorf("core module: err = %v", err)
+	}
+	if _, err := localArtifactArgs([]string{"core.wasm", "c:local.tool:0.1.0"}); output.Code(err) != output.CodeInvalidArgument {
+		t.Errorf("mixed: err = %v", err)
+	}
+	if artifacts, _ := localArtifactArgs([]string{"c:local.tool:0.1.0", "missing.wasm"}); len(artifacts) != 0 {
+		t.Errorf("references taken as artifacts: %v", artifacts)
+	}
+}
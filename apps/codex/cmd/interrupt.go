@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cyfr/codex/internal/output"
+)
+
+// exitCodeInterrupted is the conventional exit status for a process ended
+// by SIGINT (128 + 2).
+const exitCodeInterrupted = 130
+
+// signalContext returns a context that is cancelled on the first SIGINT or
+// SIGTERM. After that first signal the default handling is restored, so a
+// second Ctrl-C terminates immediately even if cleanup is stuck.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// isInterrupted reports whether err is the result of the command context
+// being cancelled.
+func isInterrupted(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// errInterrupted returns the error reported for an interrupted command,
+// which exits with status 130.
+func errInterrupted() *output.CodedError {
+	return &output.CodedError{Code: output.CodeInterrupted, Message: "Interrupted.", Status: exitCodeInterrupted}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +16,9 @@ func init() {
 	keyCmd.AddCommand(keyRevokeCmd)
 	keyCmd.AddCommand(keyRotateCmd)
 
+	addPaginationFlags(keyListCmd, 100)
+	addColumnsFlag(keyListCmd)
+
 	keyCreateCmd.Flags().String("name", "", "Key name (required)")
 	keyCreateCmd.Flags().String("type", "public", "Key type: public, secret, admin")
 	keyCreateCmd.Flags().StringSlice("scope", nil, "Permission scopes")
@@ -37,38 +41,34 @@ var keyCreateCmd = &cobra.Command{
 	Example: `  cyfr key create --name my-service --type secret
   cyfr key create --name ci-runner --type public --scope execute,read
   cyfr key create --name prod --type admin --rate-limit 100/1m --ip-allowlist 10.0.0.0/8`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		name, _ := cmd.Flags().GetString("name")
 		keyType, _ := cmd.Flags().GetString("type")
 		scope, _ := cmd.Flags().GetStringSlice("scope")
 		rateLimit, _ := cmd.Flags().GetString("rate-limit")
 		ipAllowlist, _ := cmd.Flags().GetStringSlice("ip-allowlist")
 
-		toolArgs := map[string]any{
-			"action": "create",
-			"name":   name,
-			"type":   keyType,
-		}
-		if len(scope) > 0 {
-			toolArgs["scope"] = scope
-		}
-		if rateLimit != "" {
-			toolArgs["rate_limit"] = rateLimit
-		}
-		if len(ipAllowlist) > 0 {
-			toolArgs["ip_allowlist"] = ipAllowlist
+		client, err := newTypedClient()
+		if err != nil {
+			return err
 		}
-
-		client := newClient()
-		result, err := client.CallTool("key", toolArgs)
+		result, err := client.Key(cmd.Context(), typed.KeyArgs{
+			Action:      typed.KeyActionCreate,
+			Name:        name,
+			Type:        keyType,
+			Scope:       scope,
+			RateLimit:   rateLimit,
+			IPAllowlist: ipAllowlist,
+		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -78,41 +78,49 @@ var keyGetCmd = &cobra.Command{
 	Long:    "Show metadata for an API key including type, scopes, and rate limits.",
 	Example: "  cyfr key get my-service",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("key", map[string]any{
-			"action": "get",
-			"name":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Key(cmd.Context(), typed.KeyArgs{
+			Action: typed.KeyActionGet,
+			Name:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
 var keyListCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List all API keys",
-	Long:    "List all API keys with their names, types, and creation dates.",
-	Example: "  cyfr key list",
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("key", map[string]any{
-			"action": "list",
-		})
+	Use:   "list",
+	Short: "List all API keys",
+	Long:  "List API keys with their names, types, and creation dates. At most --limit keys are fetched (default 100); use --all to page through everything.",
+	Example: `  cyfr key list
+  cyfr key list --all`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		result, err := client.CallToolPagedCtx(cmd.Context(), "key", typed.KeyArgs{
+			Action: typed.KeyActionList,
+		}.Map(), "keys", pageLimit(cmd))
+		if err != nil {
+			return output.Errorf("Failed: %v", err)
 		}
+		if err := printList(cmd, result, "keys"); err != nil {
+			return err
+		}
+		warnMoreResults(result)
+		return nil
 	},
 }
 
@@ -122,14 +130,17 @@ var keyRevokeCmd = &cobra.Command{
 	Long:    "Permanently revoke an API key. Existing sessions using this key will be invalidated.",
 	Example: "  cyfr key revoke my-service",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("key", map[string]any{
-			"action": "revoke",
-			"name":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Key(cmd.Context(), typed.KeyArgs{
+			Action: typed.KeyActionRevoke,
+			Name:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
@@ -137,6 +148,7 @@ var keyRevokeCmd = &cobra.Command{
 			fmt.Printf("Key '%s' revoked.\n", args[0])
 		}
 		_ = result
+		return nil
 	},
 }
 
@@ -146,19 +158,23 @@ var keyRotateCmd = &cobra.Command{
 	Long:    "Generate a new key value for an existing key name. The old value stops working immediately.",
 	Example: "  cyfr key rotate my-service",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("key", map[string]any{
-			"action": "rotate",
-			"name":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Key(cmd.Context(), typed.KeyArgs{
+			Action: typed.KeyActionRotate,
+			Name:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
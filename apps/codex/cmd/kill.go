@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	killCmd.Flags().Bool("all", false, "Cancel all running executions")
+	killCmd.Flags().String("component", "", "Only cancel executions of this component (with --all)")
+	killCmd.Flags().Duration("older-than", 0, "Only cancel executions started longer ago than this (with --all)")
+	killCmd.Flags().Int("limit", 100, "Maximum running executions to consider (with --all)")
+	rootCmd.AddCommand(killCmd)
+}
+
+var killCmd = &cobra.Command{
+	Use:     "kill [execution_id...]",
+	Short:   "Cancel one or more executions",
+	GroupID: "exec",
+	Long: `Cancel running executions by ID, or every running execution with --all.
+Combine --all with --component and --older-than to narrow the selection.
+
+A result row is printed for every execution. The command exits non-zero if
+any cancellation failed.`,
+	Example: `  cyfr kill exec_abc123
+  cyfr kill exec_abc123 exec_def456
+  cyfr kill --all
+  cyfr kill --all --component c:local.claude:0.1.0
+  cyfr kill --all --older-than 1h`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		component, _ := cmd.Flags().GetString("component")
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		if all && len(args) > 0 {
+			return output.Error("Pass execution IDs or --all, not both")
+		}
+		if !all && len(args) == 0 {
+			return output.NewError(output.CodeInvalidArgument, "Usage: cyfr kill <execution_id...> or cyfr kill --all")
+		}
+		if !all && (component != "" || olderThan > 0) {
+			return output.Error("--component and --older-than require --all")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		ids := args
+		if all {
+			result, err := typed.New(client).Execution(cmd.Context(), typed.ExecutionArgs{
+				Action: typed.ExecutionActionList,
+				Status: "running",
+				Limit:  limit,
+			})
+			if err != nil {
+				return toolError(err)
+			}
+			if component, err = normalizeComponentRef(component); err != nil {
+				return err
+			}
+			ids = selectExecutions(result, component, olderThan, time.Now())
+		}
+
+		if len(ids) == 0 {
+			if flagJSON {
+				output.JSON(map[string]any{"results": []any{}, "cancelled": 0, "failed": 0})
+			} else {
+				fmt.Println("No matching executions.")
+			}
+			return nil
+		}
+
+		results, failed := cancelExecutions(cmd.Context(), client, ids)
+
+		if flagJSON {
+			output.JSON(map[string]any{
+				"results":   results,
+				"cancelled": len(results) - failed,
+				"failed":    failed,
+			})
+		} else {
+			rows := make([]map[string]string, len(results))
+			for i, r := range results {
+				rows[i] = map[string]string{
+					"EXECUTION_ID": r["execution_id"].(string),
+					"RESULT":       r["result"].(string),
+					"ERROR":        r["error"].(string),
+				}
+			}
+			output.Table([]string{"EXECUTION_ID", "RESULT", "ERROR"}, rows)
+		}
+
+		if failed > 0 {
+			return output.ExitError(1)
+		}
+		return nil
+	},
+}
+
+// cancelExecutions cancels each execution in turn and returns one result
+// entry per ID along with the number of failures.
+func cancelExecutions(ctx context.Context, client *mcp.Client, ids []string) ([]map[string]any, int) {
+	results := make([]map[string]any, 0, len(ids))
+	failed := 0
+	for _, id := range ids {
+		entry := map[string]any{"execution_id": id, "result": "cancelled", "error": ""}
+		if _, err := typed.New(client).Execution(ctx, typed.ExecutionArgs{
+			Action:      typed.ExecutionActionCancel,
+			ExecutionID: id,
+		}); err != nil {
+			entry["result"] = "failed"
+			entry["error"] = err.Error()
+			failed++
+		}
+		results = append(results, entry)
+	}
+	return results, failed
+}
+
+// selectExecutions picks execution IDs out of an execution list result,
+// keeping only those that match the component filter and were started
+// before now-olderThan. An empty filter or zero duration matches everything.
+func selectExecutions(result map[string]any, component string, olderThan time.Duration, now time.Time) []string {
+	executions, _ := result["executions"].([]any)
+
+	var ids []string
+	for _, e := range executions {
+		exec, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := exec["execution_id"].(string)
+		if id == "" {
+			continue
+		}
+		if component != "" && !referenceMatches(exec["reference"], component) {
+			continue
+		}
+		if olderThan > 0 {
+			startedAt, _ := exec["started_at"].(string)
+			started, err := time.Parse(time.RFC3339, startedAt)
+			if err != nil || now.Sub(started) < olderThan {
+				continue
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// referenceMatches reports whether an execution's reference (as returned by
+// the server, usually a {"registry": ...} or {"local": ...} map) refers to
+// the given component. A filter without a version matches every version.
+func referenceMatches(reference any, component string) bool {
+	var candidates []string
+	switch r := reference.(type) {
+	case string:
+		candidates = append(candidates, r)
+	case map[string]any:
+		for _, v := range r {
+			if s, ok := v.(string); ok {
+				candidates = append(candidates, s)
+			}
+		}
+	}
+
+	want := ref.ExpandTypePrefix(component)
+	for _, c := range candidates {
+		got := ref.ExpandTypePrefix(c)
+		if got == want || strings.HasPrefix(got, want+":") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectExecutions(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	result := map[string]any{
+		"executions": []any{
+			map[string]any{
+				"execution_id": "exec_old",
+				"reference":    map[string]any{"registry": "catalyst:local.claude:0.1.0"},
+				"started_at":   "2026-01-01T09:00:00Z",
+			},
+			map[string]any{
+				"execution_id": "exec_new",
+				"reference":    map[string]any{"registry": "catalyst:local.claude:0.2.0"},
+				"started_at":   "2026-01-01T11:55:00Z",
+			},
+			map[string]any{
+				"execution_id": "exec_other",
+				"reference":    map[string]any{"registry": "reagent:local.parser:1.0.0"},
+				"started_at":   "2026-01-01T08:00:00Z",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		component string
+		olderThan time.Duration
+		want      []string
+	}{
+		{"no filters", "", 0, []string{"exec_old", "exec_new", "exec_other"}},
+		{"component without version", "c:local.claude", 0, []string{"exec_old", "exec_new"}},
+		{"component with version", "catalyst:local.claude:0.2.0", 0, []string{"exec_new"}},
+		{"older than", "", time.Hour, []string{"exec_old", "exec_other"}},
+		{"component and older than", "c:local.claude", time.Hour, []string{"exec_old"}},
+		{"no match", "f:acme.flow", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectExecutions(result, tt.component, tt.olderThan, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("id[%d]: got %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
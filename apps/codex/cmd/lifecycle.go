@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bufio"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cyfr/codex/internal/config"
@@ -26,75 +29,205 @@ func generateSecretKey() (string, error) {
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+	defaults := defaultInitOptions()
+	initCmd.Flags().BoolP("yes", "y", false, "Don't ask questions; use the defaults and the flags given")
+	initCmd.Flags().String("name", defaults.Name, "Project name")
+	initCmd.Flags().Int("port", defaults.Port, "Host port the server is published on")
+	initCmd.Flags().Bool("no-examples", false, "Don't download the example components")
+	initCmd.Flags().String("registry", "", "OCI registry for components, e.g. ghcr.io/acme (default: the server's own)")
+	initCmd.Flags().String("auth", defaults.Auth, "Sign-in provider: "+strings.Join(authProviders, ", "))
+	initCmd.Flags().String("client-id", "", "OAuth client ID of the sign-in provider (default: CYFR's GitHub app)")
+	initCmd.Flags().String("runtime", "", "Container runtime to run the server with: "+strings.Join(containerRuntimes, ", ")+" (default: the first installed)")
+	initCmd.Flags().String("scaffold-file", "", "Extract the scaffold from this cyfr-scaffold.tar.gz instead of downloading it, verified against the checksums.txt next to it")
+	initCmd.Flags().String("template", "", "Also create a starter component in this language: "+strings.Join(scaffold.Languages, ", "))
 	rootCmd.AddCommand(upCmd)
 	rootCmd.AddCommand(downCmd)
+	rootCmd.AddCommand(restartCmd)
+
+	for _, c := range []*cobra.Command{upCmd, restartCmd} {
+		c.Flags().Bool("logs", false, "Follow the server's logs after starting it, until interrupted (the server keeps running)")
+		c.Flags().Int("port", 0, "Publish the server on this host port, updating docker-compose.yml, cyfr.yaml and the local context")
+		c.Flags().Bool("native", false, "Run the server binary for this platform as a background process instead of a container")
+	}
+	for _, c := range []*cobra.Command{upCmd, restartCmd, downCmd} {
+		c.Flags().String("env", "", "Work on this environment of cyfr.yaml, an instance beside the default one")
+	}
+	downCmd.Flags().Bool("volumes", false, "Also delete the server's data: its volumes and data/ (asks first)")
+	downCmd.Flags().Bool("purge", false, "Like --volumes, and also delete components/ (asks first)")
+	downCmd.Flags().BoolP("yes", "y", false, "Delete without asking")
+}
+
+// initOptionsFromFlags returns the defaults of cyfr init with its flags
+// applied.
+func initOptionsFromFlags(cmd *cobra.Command) initOptions {
+	opts := defaultInitOptions()
+	opts.Name, _ = cmd.Flags().GetString("name")
+	opts.Port, _ = cmd.Flags().GetInt("port")
+	noExamples, _ := cmd.Flags().GetBool("no-examples")
+	opts.Examples = !noExamples
+	opts.Registry, _ = cmd.Flags().GetString("registry")
+	opts.Runtime, _ = cmd.Flags().GetString("runtime")
+	opts.Auth, _ = cmd.Flags().GetString("auth")
+	if id, _ := cmd.Flags().GetString("client-id"); id != "" {
+		opts.ClientID = id
+	} else if opts.Auth != "github" {
+		opts.ClientID = ""
+	}
+	return opts
+}
+
+// scaffoldError returns err, pointing at --insecure-scaffold if it is about
+// a download that didn't match the release's checksums.
+func scaffoldError(err error) error {
+	if errors.Is(err, scaffold.ErrUnverified) {
+		return fmt.Errorf("%w (--insecure-scaffold skips the check)", err)
+	}
+	return err
 }
 
+// starterDir is where init --template creates its starter component.
+var starterDir = filepath.Join("components", "catalysts", "local", "hello", "0.1.0")
+
 var initCmd = &cobra.Command{
 	Use:     "init",
 	Short:   "Scaffold a CYFR project in the current directory",
 	GroupID: "start",
-	Long:    `Create a docker-compose.yml, cyfr.yaml, and data/components directories in the current directory so you can start a local CYFR server with "cyfr up".`,
+	Long: `Create a docker-compose.yml, cyfr.yaml, and data/components directories in the current directory so you can start a local CYFR server with "cyfr up".
+
+Run on a terminal without flags, init asks for the project name, port, whether to download the example components, the component registry, and how users sign in. Otherwise, or with --yes, it uses the defaults and the flags given.
+
+Running init again regenerates docker-compose.yml and cyfr.yaml. Put compose customizations such as extra volumes or resource limits in docker-compose.override.yml, which init creates once and nothing in cyfr changes afterwards; a docker-compose.yml with edits of its own is saved as docker-compose.yml.before-init first. An existing .env is kept, with any variables it lacks added.
+
+The server runs with Docker Compose, or the compose command of Podman, nerdctl or Colima. Without --runtime the first of docker, podman and nerdctl found on PATH is used each time; --runtime records the project's in cyfr.yaml.
+
+With --template, a starter catalyst named hello is created from the template for a language, with WIT bindings and a build script. Use 'cyfr new component' to add more.
+
+Scaffold files and templates come from the GitHub releases, or from the mirror set with 'cyfr settings set scaffold_url <url>' or scaffold_url in cyfr.yaml; downloads are checked against the checksums.txt of the GitHub releases or of the scaffold_url setting, never of cyfr.yaml's. Without network access, --scaffold-file extracts a cyfr-scaffold.tar.gz copied from a release, along with its checksums.txt.`,
 	Example: `  cyfr init
+  cyfr init --yes --port 4001 --no-examples
+  cyfr init --template go
+  cyfr init --runtime podman
+  cyfr init --yes --scaffold-file ./cyfr-scaffold.tar.gz
   cyfr up`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Pull Docker image (non-fatal)
-		fmt.Println("Pulling CYFR server image...")
-		pull := exec.Command("docker", "pull", "ghcr.io/cyfrworks/cyfr:latest")
-		pull.Stdout = os.Stdout
-		pull.Stderr = os.Stderr
-		if err := pull.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to pull image: %v (continuing anyway)\n", err)
-		}
-
-		// Download scaffold files (non-fatal)
-		if err := scaffold.Download(Version); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to download scaffold files: %v (continuing anyway)\n", err)
-		}
-
-		// Generate docker-compose.yml
-		composeContent := `services:
-  cyfr:
-    image: ghcr.io/cyfrworks/cyfr:latest
-    ports:
-      - "4000:4000"
-    volumes:
-      - ./data:/app/data
-      - ./components:/app/components
-    env_file:
-      - .env
-`
-		if err := os.WriteFile("docker-compose.yml", []byte(composeContent), 0644); err != nil {
-			output.Errorf("Failed to write docker-compose.yml: %v", err)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		template, _ := cmd.Flags().GetString("template")
+		if template != "" {
+			if err := checkLanguage(template); err != nil {
+				return err
+			}
+		}
+
+		_, err := os.Stat(".env")
+		envExists := err == nil
+		opts := initOptionsFromFlags(cmd)
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes && cmd.Flags().NFlag() == 0 && !flagJSON &&
+			output.IsTerminal(os.Stdin) && output.IsTerminal(os.Stdout) {
+			wizard := initWizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+			if opts, err = wizard.run(opts, !envExists); err != nil {
+				return output.Errorf("Setup cancelled: %v", err)
+			}
+			fmt.Println("")
+		}
+		if err := opts.check(!envExists); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Invalid project settings: %v", err)
+		}
+
+		// Pull the server image (non-fatal)
+		if runtime, err := findRuntime(opts.Runtime); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: can't pull the server image: %v (continuing anyway)\n", err)
+		} else {
+			fmt.Println("Pulling CYFR server image...")
+			pull := runtime.command(cmd.Context(), "pull", serverImage)
+			pull.Stdout = os.Stdout
+			pull.Stderr = os.Stderr
+			if err := pull.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to pull image: %v (continuing anyway)\n", err)
+			}
+		}
+
+		if file, _ := cmd.Flags().GetString("scaffold-file"); file != "" {
+			// A scaffold given explicitly has to work.
+			if err := scaffold.ExtractFile(file, opts.Examples); err != nil {
+				return output.Errorf("Failed to extract scaffold files from %s: %v", file, scaffoldError(err))
+			}
+		} else {
+			// Download scaffold files (non-fatal)
+			spinner := output.NewSpinner("Downloading scaffold files")
+			if opts.Examples {
+				err = scaffold.Download(Version)
+			} else {
+				err = scaffold.DownloadWithoutExamples(Version)
+			}
+			spinner.Stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to download scaffold files: %v (continuing anyway)\n", scaffoldError(err))
+			} else if err := recordScaffoldVersion(Version); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to record the scaffold version: %v\n", err)
+			}
+		}
+		if template != "" {
+			if err := createFromTemplate(template, starterDir, "hello", "catalyst"); err != nil {
+				return err
+			}
+		}
+
+		// Generate docker-compose.yml, keeping a copy of one with the user's
+		// edits, which belong in the override file.
+		composeBackup := ""
+		if old, err := os.ReadFile("docker-compose.yml"); err == nil && !isGeneratedCompose(string(old)) {
+			composeBackup = "docker-compose.yml.before-init"
+			if err := os.WriteFile(composeBackup, old, 0644); err != nil {
+				return output.Errorf("Failed to back up docker-compose.yml: %v", err)
+			}
+		}
+		if err := os.WriteFile("docker-compose.yml", []byte(opts.compose()), 0644); err != nil {
+			return output.Errorf("Failed to write docker-compose.yml: %v", err)
+		}
+		overrideCreated := false
+		if f, err := os.OpenFile(composeOverrideFile, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644); err == nil {
+			_, err = f.WriteString(composeOverride())
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return output.Errorf("Failed to write %s: %v", composeOverrideFile, err)
+			}
+			overrideCreated = true
+		} else if !os.IsExist(err) {
+			return output.Errorf("Failed to write %s: %v", composeOverrideFile, err)
 		}
 
 		// Generate cyfr.yaml with richer config
-		cyfrConfig := `name: my-cyfr-project
-port: 4000
-host: localhost
-database_path: ./data/cyfr.db
-`
-		if err := os.WriteFile("cyfr.yaml", []byte(cyfrConfig), 0644); err != nil {
-			output.Errorf("Failed to write cyfr.yaml: %v", err)
+		if err := os.WriteFile("cyfr.yaml", []byte(opts.cyfrYAML()), 0644); err != nil {
+			return output.Errorf("Failed to write cyfr.yaml: %v", err)
 		}
 
-		// Generate .env if it doesn't already exist (idempotent)
+		// Generate .env, or add the variables an existing one lacks. Auth
+		// is only set up for a new one.
+		secretKey, err := generateSecretKey()
+		if err != nil {
+			return output.Errorf("Failed to generate secret key: %v", err)
+		}
 		envCreated := false
-		if _, err := os.Stat(".env"); os.IsNotExist(err) {
-			secretKey, err := generateSecretKey()
+		var envAdded []string
+		if !envExists {
+			if err := os.WriteFile(".env", []byte(opts.env(secretKey)), 0600); err != nil {
+				return output.Errorf("Failed to write .env: %v", err)
+			}
+			envCreated = true
+		} else {
+			existing, err := os.ReadFile(".env")
 			if err != nil {
-				output.Errorf("Failed to generate secret key: %v", err)
+				return output.Errorf("Failed to read .env: %v", err)
 			}
-			envContent := fmt.Sprintf(`CYFR_SECRET_KEY_BASE=%s
-CYFR_PORT=4000
-CYFR_HOST=0.0.0.0
-CYFR_DATABASE_PATH=/app/data/cyfr.db
-CYFR_GITHUB_CLIENT_ID=Ov23lib66tiIwXkgUpwm
-`, secretKey)
-			if err := os.WriteFile(".env", []byte(envContent), 0600); err != nil {
-				output.Errorf("Failed to write .env: %v", err)
+			base := opts
+			base.Auth = "none"
+			var merged string
+			if merged, envAdded = mergeDotEnv(string(existing), base.env(secretKey)); envAdded != nil {
+				if err := os.WriteFile(".env", []byte(merged), 0600); err != nil {
+					return output.Errorf("Failed to write .env: %v", err)
+				}
 			}
-			envCreated = true
 		}
 
 		// Create directories
@@ -118,17 +251,28 @@ CYFR_GITHUB_CLIENT_ID=Ov23lib66tiIwXkgUpwm
 				Contexts:       map[string]*config.Context{},
 			}
 		}
-		cfg.Contexts["local"] = &config.Context{URL: "http://localhost:4000"}
+		cfg.Contexts["local"] = &config.Context{URL: fmt.Sprintf("http://localhost:%d", opts.Port)}
 		cfg.CurrentContext = "local"
 		_ = cfg.Save()
 
 		fmt.Println("CYFR project initialized.")
 		fmt.Println("  docker-compose.yml created")
+		if composeBackup != "" {
+			fmt.Printf("  the edited docker-compose.yml was saved as %s; move your changes into %s\n", composeBackup, composeOverrideFile)
+		}
+		if overrideCreated {
+			fmt.Printf("  %s created (for your compose changes)\n", composeOverrideFile)
+		} else {
+			fmt.Printf("  %s already exists (kept)\n", composeOverrideFile)
+		}
 		fmt.Println("  cyfr.yaml created")
-		if envCreated {
+		switch {
+		case envCreated:
 			fmt.Println("  .env created (contains secret key — do not commit)")
-		} else {
-			fmt.Println("  .env already exists (skipped)")
+		case envAdded != nil:
+			fmt.Printf("  .env already exists (kept, added %s)\n", strings.Join(envAdded, ", "))
+		default:
+			fmt.Println("  .env already exists (kept)")
 		}
 		fmt.Println("  data/ directory created")
 		fmt.Println("  components/catalysts/local/ created")
@@ -138,10 +282,16 @@ CYFR_GITHUB_CLIENT_ID=Ov23lib66tiIwXkgUpwm
 			fmt.Println("  component-guide.md downloaded")
 			fmt.Println("  integration-guide.md downloaded")
 			fmt.Println("  wit/ interface definitions downloaded")
-			fmt.Println("  components/ examples downloaded (claude, gemini, openai, list-models)")
+			if opts.Examples {
+				fmt.Println("  components/ examples downloaded (claude, gemini, openai, list-models)")
+			}
+		}
+		if template != "" {
+			fmt.Printf("  %s/ created from the %s template\n", starterDir, template)
 		}
 		fmt.Println("")
 		fmt.Println("Next: run 'cyfr up' to start the server.")
+		return nil
 	},
 }
 
@@ -149,61 +299,294 @@ var upCmd = &cobra.Command{
 	Use:     "up",
 	Short:   "Start the CYFR server container",
 	GroupID: "start",
-	Long:    "Start the CYFR server using Docker Compose in detached mode. Requires a docker-compose.yml in the current directory (created by cyfr init).",
-	Example: "  cyfr up",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := exec.Command("docker", "compose", "up", "-d")
+	Long: `Start the CYFR server using Docker Compose in detached mode. Requires a docker-compose.yml in the current directory (created by cyfr init). The project's cyfr.yaml can name another container runtime to use instead: runtime: podman, nerdctl or colima.
+
+With --env, an environment defined in cyfr.yaml is started instead, as a separate compose project beside the default instance, e.g. for integration tests. Each has its own port and data directory (./data-<name> unless set); its compose file is generated from docker-compose.yml into .cyfr/:
+
+  environments:
+    test:
+      port: 4100
+      data: ./data-test
+
+Pass the same --env to 'cyfr down', 'cyfr restart' and 'cyfr logs --server'.
+
+If another program already uses the server's port, a free one is offered instead; --port picks one. Moving to another port rewrites docker-compose.yml and cyfr.yaml and points the contexts for the old port at the new one.
+
+With --logs, the server's output is followed from its start instead of waiting for it to become healthy. Ctrl-C stops following; the server keeps running, and 'cyfr logs --server' shows the logs again.
+
+With --native, no container runtime is needed: the server binary for this platform is downloaded to .cyfr/bin/ and run as a background process, with its PID in .cyfr/server.pid and its output in .cyfr/server.log. 'cyfr down' stops it.`,
+	Example: `  cyfr up
+  cyfr up --logs
+  cyfr up --port 4100
+  cyfr up --native
+  cyfr up --env test`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return startServer(cmd)
+	},
+}
+
+// startServer starts the project's server with its runtime's compose, or
+// with --native as a background process, and waits for it to become
+// healthy, or with --logs follows its logs.
+func startServer(cmd *cobra.Command) error {
+	native, _ := cmd.Flags().GetBool("native")
+	envName, _ := cmd.Flags().GetString("env")
+	if envName != "" && (native || cmd.Flags().Changed("port")) {
+		return output.NewError(output.CodeInvalidArgument, "--env doesn't work with --native or --port; set the environment's port in cyfr.yaml")
+	}
+	var runtime containerRuntime
+	var env *environment
+	if pid, ok := nativePID(); ok && envName == "" {
+		if !native {
+			return output.Errorf("The server is running natively (PID %d). Stop it with 'cyfr down' first, or use --native.", pid)
+		}
+		return output.Errorf("The server is already running natively (PID %d).", pid)
+	}
+	if !native {
+		var err error
+		if runtime, env, err = runtimeForEnv(cmd); err != nil {
+			return err
+		}
+	}
+	var port int
+	if env != nil {
+		port = env.Port
+		if portInUse(port) && !runtime.running() {
+			return output.Errorf("Port %d of the %s environment is already in use by another program. Change its port in cyfr.yaml.", port, env.Name)
+		}
+	} else {
+		var err error
+		if port, err = checkUpPort(cmd); err != nil {
+			return err
+		}
+	}
+
+	if native {
+		if port == 0 {
+			port = projectPort()
+		}
+		if err := startNative(port); err != nil {
+			return err
+		}
+	} else {
+		c := runtime.compose(cmd.Context(), "up", "-d")
 		c.Stdout = os.Stdout
 		c.Stderr = os.Stderr
 		if err := c.Run(); err != nil {
-			output.Errorf("Failed to start: %v", err)
+			return output.Errorf("Failed to start: %v", err)
 		}
-		fmt.Println("CYFR server started.")
+	}
+	fmt.Println("CYFR server started.")
 
-		// Health check wait
-		cfg, err := config.Load()
-		if err != nil {
-			cfg = config.DefaultForLocal()
-		}
-		healthURL := cfg.CurrentURL() + "/api/health"
-
-		fmt.Printf("Waiting for server at %s ...\n", cfg.CurrentURL())
-		client := &http.Client{Timeout: 2 * time.Second}
-		deadline := time.Now().Add(30 * time.Second)
-		healthy := false
-		for time.Now().Before(deadline) {
-			resp, err := client.Get(healthURL)
-			if err == nil {
-				resp.Body.Close()
-				if resp.StatusCode == http.StatusOK {
-					healthy = true
-					break
-				}
-			}
-			time.Sleep(1 * time.Second)
+	if logs, _ := cmd.Flags().GetBool("logs"); logs {
+		fmt.Println("Following server logs (Ctrl-C to stop; the server keeps running)...")
+		if native {
+			return nativeLogs(cmd.Context(), "", true)
 		}
+		return serverLogs(cmd.Context(), runtime, "", "", true)
+	}
 
-		if healthy {
-			fmt.Println("Server is ready.")
+	// Health check wait
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultForLocal()
+	}
+	serverURL := cfg.CurrentURL()
+	if port != 0 {
+		serverURL = fmt.Sprintf("http://localhost:%d", port)
+	}
+	healthURL := serverURL + "/api/health"
+
+	fmt.Printf("Waiting for server at %s ...\n", serverURL)
+	spinner := output.NewSpinner("Waiting for server")
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.Now().Add(30 * time.Second)
+	healthy := false
+	for time.Now().Before(deadline) {
+		if _, ok := nativePID(); native && !ok {
+			spinner.Stop()
+			return output.Errorf("The server exited. See %s.", nativeLogFile)
+		}
+		resp, err := client.Get(healthURL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				healthy = true
+				break
+			}
+			spinner.Step(fmt.Sprintf("(health check: HTTP %d)", resp.StatusCode))
 		} else {
-			fmt.Fprintf(os.Stderr, "Warning: server did not become healthy within 30s. Check 'docker compose logs'.\n")
+			spinner.Step("(not accepting connections yet)")
 		}
-	},
+		time.Sleep(1 * time.Second)
+	}
+	spinner.Stop()
+
+	logsHint := "cyfr logs --server"
+	if env != nil {
+		logsHint += " --env " + env.Name
+	}
+	if healthy {
+		fmt.Println("Server is ready.")
+		if env != nil {
+			fmt.Printf("Reach the %s environment with --url %s.\n", env.Name, serverURL)
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: server did not become healthy within 30s. Check '%s'.\n", logsHint)
+	}
+	return nil
+}
+
+// checkUpPort makes sure the project's server can be published on a free
+// port before it is started: the one from --port, or if its own is taken
+// by another program, a free one the user accepts. The project is moved to
+// the new port. It returns the port, or 0 if docker-compose.yml doesn't
+// say.
+func checkUpPort(cmd *cobra.Command) (int, error) {
+	want, _ := cmd.Flags().GetInt("port")
+	data, err := os.ReadFile("docker-compose.yml")
+	if err != nil {
+		// compose reports it.
+		return 0, nil
+	}
+	compose := string(data)
+	port, ok := composePort(compose)
+	if !ok {
+		if want != 0 {
+			return 0, output.Errorf("Can't change the port: docker-compose.yml doesn't publish port %d.", serverPort)
+		}
+		return 0, nil
+	}
+	switch {
+	case want == port || (want == 0 && (!portInUse(port) || composeRunning())):
+		// Free, or held by the project's own running server.
+		return port, nil
+	case want == 0:
+		if want, err = offerPort(port); err != nil {
+			return 0, err
+		}
+	case portInUse(want):
+		return 0, output.Errorf("Port %d is already in use by another program.", want)
+	}
+
+	if err := moveProjectPort(compose, port, want); err != nil {
+		return 0, output.Errorf("Failed to change the port: %v", err)
+	}
+	fmt.Printf("Publishing the server on port %d instead of %d.\n", want, port)
+	return want, nil
+}
+
+// offerPort offers a free port in place of port, which another program
+// uses, and returns it if the user accepts.
+func offerPort(port int) (int, error) {
+	free := freePort(port)
+	if free == 0 {
+		return 0, output.Errorf("Port %d is already in use by another program. Free it, or pick a port with --port.", port)
+	}
+	if output.IsTerminal(os.Stdin) && !flagJSON {
+		w := initWizard{in: bufio.NewReader(os.Stdin), out: os.Stderr}
+		ok, err := w.askYesNo(fmt.Sprintf("Port %d is already in use by another program. Use port %d instead?", port, free), true)
+		if err == nil && ok {
+			return free, nil
+		}
+	}
+	return 0, output.Errorf("Port %d is already in use by another program. Run 'cyfr up --port %d' to use a free port.", port, free)
 }
 
 var downCmd = &cobra.Command{
 	Use:     "down",
 	Short:   "Stop the CYFR server container",
 	GroupID: "start",
-	Long:    "Stop the CYFR server and remove its containers via Docker Compose.",
-	Example: "  cyfr down",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := exec.Command("docker", "compose", "down")
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		if err := c.Run(); err != nil {
-			output.Errorf("Failed to stop: %v", err)
+	Long: `Stop the CYFR server and remove its containers via Docker Compose, or the project's container runtime. A server started with 'cyfr up --native' is stopped instead.
+
+For a clean slate, --volumes also deletes the server's data: its volumes and the data/ directory with its database, or the environment's data directory with --env. --purge deletes components/ as well, including pulled components and your own. Both ask first unless --yes is given.`,
+	Example: `  cyfr down
+  cyfr down --volumes
+  cyfr down --purge --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		volumes, _ := cmd.Flags().GetBool("volumes")
+		purge, _ := cmd.Flags().GetBool("purge")
+		dataDir := "data"
+		if name, _ := cmd.Flags().GetString("env"); name != "" {
+			env, err := lookupEnvironment(name)
+			if err != nil {
+				return err
+			}
+			dataDir = filepath.Clean(env.Data)
+		}
+		var remove []string
+		if volumes || purge {
+			remove = append(remove, dataDir)
+		}
+		if purge {
+			remove = append(remove, "components")
+		}
+		if len(remove) > 0 {
+			question := fmt.Sprintf("Delete the server's volumes and %s/? This can't be undone.", strings.Join(remove, "/ and "))
+			if yes, _ := cmd.Flags().GetBool("yes"); !yes && !confirm(question) {
+				fmt.Println("Aborted.")
+				return nil
+			}
+		}
+
+		if _, err := stopServer(cmd, len(remove) > 0); err != nil {
+			return err
 		}
 		fmt.Println("CYFR server stopped.")
+
+		for _, dir := range remove {
+			if err := os.RemoveAll(dir); err != nil {
+				return output.Errorf("Failed to delete %s/: %v", dir, err)
+			}
+			fmt.Printf("Deleted %s/\n", dir)
+		}
+		return nil
+	},
+}
+
+var restartCmd = &cobra.Command{
+	Use:     "restart",
+	Short:   "Restart the CYFR server container",
+	GroupID: "start",
+	Long:    "Stop the CYFR server and start it again, as 'cyfr down' and 'cyfr up' do, then wait for it to become healthy. A server running natively is started natively again. Its data is kept.",
+	Example: `  cyfr restart
+  cyfr restart --logs`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		native, err := stopServer(cmd, false)
+		if err != nil {
+			return err
+		}
+		if native {
+			cmd.Flags().Set("native", "true")
+		}
+		return startServer(cmd)
 	},
 }
+
+// stopServer stops the project's server: its native process if it runs
+// one, which it reports, or else its containers, with their volumes if
+// volumes is set.
+func stopServer(cmd *cobra.Command, volumes bool) (native bool, err error) {
+	envName, _ := cmd.Flags().GetString("env")
+	if pid, ok := nativePID(); ok && envName == "" {
+		if err := stopNative(pid); err != nil {
+			return true, output.Errorf("Failed to stop the server (PID %d): %v", pid, err)
+		}
+		return true, nil
+	}
+	runtime, _, err := runtimeForEnv(cmd)
+	if err != nil {
+		return false, err
+	}
+	args := []string{"down"}
+	if volumes {
+		args = append(args, "--volumes")
+	}
+	c := runtime.compose(cmd.Context(), args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return false, output.Errorf("Failed to stop: %v", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownPurge(t *testing.T) {
+	// A docker that prints its arguments.
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	chdirTemp(t)
+	for _, dir := range []string{"data", "components/catalysts", "wit"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	downCmd.SetContext(context.Background())
+	downCmd.Flags().Set("purge", "true")
+	downCmd.Flags().Set("yes", "true")
+	t.Cleanup(func() {
+		downCmd.Flags().Set("purge", "false")
+		downCmd.Flags().Set("yes", "false")
+	})
+	var err error
+	out, _ := captureStdout(func() {
+		err = downCmd.RunE(downCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "compose down --volumes\n") {
+		t.Errorf("didn't run docker compose down --volumes:\n%s", out)
+	}
+	for _, dir := range []string{"data", "components"} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s/ wasn't deleted", dir)
+		}
+	}
+	if _, err := os.Stat("wit"); err != nil {
+		t.Errorf("wit/ was deleted: %v", err)
+	}
+}
+
+func TestInitKeepsUserFiles(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	edited := defaultInitOptions().compose() + "    mem_limit: 1g\n"
+	os.WriteFile("docker-compose.yml", []byte(edited), 0644)
+	os.WriteFile(composeOverrideFile, []byte("services: {cyfr: {mem_limit: 2g}}\n"), 0644)
+	os.WriteFile(".env", []byte("CYFR_SECRET_KEY_BASE=mine\nMY_VAR=1\n"), 0600)
+
+	initCmd.SetContext(context.Background())
+	initCmd.Flags().Set("yes", "true")
+	t.Cleanup(func() { initCmd.Flags().Set("yes", "false") })
+	var runErr error
+	out, err := captureStdout(func() { runErr = initCmd.RunE(initCmd, nil) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+
+	if data, _ := os.ReadFile(composeOverrideFile); string(data) != "services: {cyfr: {mem_limit: 2g}}\n" {
+		t.Errorf("override = %q", data)
+	}
+	if data, _ := os.ReadFile("docker-compose.yml.before-init"); string(data) != edited {
+		t.Errorf("backup = %q", data)
+	}
+	if data, _ := os.ReadFile("docker-compose.yml"); !isGeneratedCompose(string(data)) {
+		t.Errorf("docker-compose.yml = %q", data)
+	}
+	env, _ := os.ReadFile(".env")
+	for _, want := range []string{"CYFR_SECRET_KEY_BASE=mine\n", "MY_VAR=1\n", "CYFR_PORT=4000\n"} {
+		if !strings.Contains(string(env), want) {
+			t.Errorf(".env doesn't contain %q:\n%s", want, env)
+		}
+	}
+	if strings.Count(string(env), "CYFR_SECRET_KEY_BASE") != 1 || strings.Contains(string(env), "GITHUB") {
+		t.Errorf(".env = %s", env)
+	}
+	if !strings.Contains(out, "move your changes into "+composeOverrideFile) {
+		t.Errorf("output doesn't mention the backup:\n%s", out)
+	}
+}
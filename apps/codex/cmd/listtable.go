@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// listColumns are the default table columns of list results, by the key
+// holding the list. Columns an item doesn't have are left out.
+var listColumns = map[string][]string{
+	"components": {"name", "type", "latest", "description"},
+	"executions": {"execution_id", "status", "reference", "started_at", "duration_ms"},
+	"keys":       {"name", "type", "prefix", "scope", "created_at"},
+	"secrets":    {"name", "created_at", "updated_at"},
+	"policies":   {"component_ref", "updated_at"},
+	"events":     {"timestamp", "event_type", "user_id", "component_ref", "execution_id"},
+	"installed":  {"reference", "type", "version", "size", "registered"},
+	"namespaces": {"namespace", "role", "owner", "components", "created_at"},
+	"members":    {"member", "role", "added_at"},
+	"versions":   {"version", "created_at", "created_by", "current"},
+}
+
+// listIDFields are the fields identifying the items of list results, by
+// the key holding the list, printed alone with --quiet.
+var listIDFields = map[string]string{
+	"components": "name",
+	"executions": "execution_id",
+	"keys":       "name",
+	"secrets":    "name",
+	"policies":   "component_ref",
+	"events":     "id",
+	"installed":  "reference",
+	"namespaces": "namespace",
+	"members":    "member",
+	"versions":   "version",
+}
+
+// addColumnsFlag registers --columns on a command that prints a list.
+func addColumnsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("columns", nil, "Fields to show as table columns, e.g. name,created_at (nested fields as reference.registry)")
+}
+
+// printList prints a list result: only the items' identifiers with
+// --quiet, as JSON with --json, otherwise the list under key as a table of
+// the --columns fields or the default ones. Results without such a list
+// are printed as key: value pairs.
+func printList(cmd *cobra.Command, result map[string]any, key string) error {
+	if flagQuiet {
+		items, _ := result[key].([]any)
+		printIDs(items, listIDFields[key])
+		return nil
+	}
+	if flagJSON {
+		output.JSON(result)
+		return nil
+	}
+	items, ok := result[key].([]any)
+	if !ok {
+		output.KeyValue(result)
+		return nil
+	}
+	if len(items) == 0 && output.TableFormat == "" {
+		fmt.Printf("No %s.\n", key)
+		return nil
+	}
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	for _, c := range columns {
+		if _, err := output.Select(nil, "."+c); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --columns: %v", err)
+		}
+	}
+	if len(columns) == 0 {
+		columns = defaultListColumns(items, listColumns[key])
+	}
+	output.ListTable(items, columns)
+	return nil
+}
+
+// printIDs prints the field of each item, one per line, for --quiet.
+// Items that aren't objects are printed as they are; items without the
+// field are left out.
+func printIDs(items []any, field string) {
+	for _, item := range items {
+		if _, ok := item.(map[string]any); ok {
+			item, _ = output.Select(item, "."+field)
+		}
+		if item != nil && item != "" {
+			fmt.Println(output.Cell(item))
+		}
+	}
+}
+
+// defaultListColumns returns the defaults present in items, or every field
+// that fits a cell if none are. Lists of plain values get a single column,
+// and empty lists all the defaults.
+func defaultListColumns(items []any, defaults []string) []string {
+	if len(items) == 0 && len(defaults) > 0 {
+		return defaults
+	}
+	present := output.ListColumns(items)
+	if len(present) == 0 {
+		if len(defaults) > 0 {
+			return defaults[:1]
+		}
+		return []string{"value"}
+	}
+	var columns []string
+	for _, c := range defaults {
+		if slices.Contains(present, c) {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		return present
+	}
+	return columns
+}
@@ -1,16 +1,22 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	loginCmd.Flags().String("provider", "github", "OAuth provider (github, google)")
+	loginCmd.Flags().String("with-key", "", "Authenticate with an API key instead of the device flow ('-' reads it from stdin)")
+	loginCmd.Flags().String("with-token", "", "Authenticate with an OAuth token response JSON file ('-' reads it from stdin)")
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(whoamiCmd)
@@ -20,25 +26,43 @@ var loginCmd = &cobra.Command{
 	Use:     "login",
 	Short:   "Authenticate via Device Flow",
 	GroupID: "start",
-	Long:    "Start an OAuth 2.0 Device Authorization Flow. The CLI prints a one-time code and a URL; open the URL in a browser, enter the code, and the CLI will receive a session token automatically.",
+	Long: `Start an OAuth 2.0 Device Authorization Flow. The CLI prints a one-time code and a URL; open the URL in a browser, enter the code, and the CLI will receive a session token automatically.
+
+For automation, pass --with-key with an API key created by 'cyfr key create'. The key is verified and stored in the current context, and every request is authenticated with it — no browser or session needed.
+
+To use an OAuth access token issued by your identity provider, pass --with-token with its token response JSON (access_token, and optionally refresh_token, expires_in, token_endpoint and client_id). Requests carry the access token, which is refreshed at token_endpoint when it expires. Servers whose device flow issues OAuth tokens are handled the same way.`,
 	Example: `  cyfr login
-  cyfr login --provider google`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
+  cyfr login --provider google
+  cyfr login --with-key cyfr_sk_...
+  echo "$CYFR_API_KEY" | cyfr login --with-key -
+  cyfr login --with-token token.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if key, _ := cmd.Flags().GetString("with-key"); key != "" {
+			return loginWithKey(cmd, key)
+		}
+		if path, _ := cmd.Flags().GetString("with-token"); path != "" {
+			return loginWithToken(cmd, path)
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
 		provider, _ := cmd.Flags().GetString("provider")
 
 		// Initialize MCP session
-		if err := client.Initialize(); err != nil {
-			output.Errorf("Failed to connect: %v", err)
+		if err := client.InitializeCtx(cmd.Context()); err != nil {
+			return output.Errorf("Failed to connect: %v", err)
 		}
+		warnProtocolMismatch(client.Server)
 
 		// Start device flow
-		result, err := client.CallTool("session", map[string]any{
-			"action":   "device-init",
-			"provider": provider,
+		result, err := typed.New(client).Session(cmd.Context(), typed.SessionArgs{
+			Action:   typed.SessionActionDeviceInit,
+			Provider: provider,
 		})
 		if err != nil {
-			output.Errorf("Failed to start login: %v", err)
+			return output.Errorf("Failed to start login: %v", err)
 		}
 
 		// Show user code and verification URL
@@ -55,12 +79,16 @@ var loginCmd = &cobra.Command{
 
 		// Poll for completion
 		for {
-			time.Sleep(time.Duration(interval) * time.Second)
+			select {
+			case <-time.After(time.Duration(interval) * time.Second):
+			case <-cmd.Context().Done():
+				return errInterrupted()
+			}
 
-			pollResult, err := client.CallTool("session", map[string]any{
-				"action":      "device-poll",
-				"device_code": deviceCode,
-				"provider":    provider,
+			pollResult, err := typed.New(client).Session(cmd.Context(), typed.SessionArgs{
+				Action:     typed.SessionActionDevicePoll,
+				DeviceCode: deviceCode,
+				Provider:   provider,
 			})
 			if err != nil {
 				// Network errors etc — keep trying
@@ -73,12 +101,20 @@ var loginCmd = &cobra.Command{
 				// Save session ID from the auth response
 				sessionID, _ := pollResult["session_id"].(string)
 				cfg, _ := config.Load()
-				if cfg.Current() != nil {
-					if sessionID != "" {
-						cfg.Current().SessionID = sessionID
-					} else if client.SessionID != "" {
-						cfg.Current().SessionID = client.SessionID
+				if current := cfg.Contexts[activeContextName()]; current != nil {
+					if token := tokenFromResult(pollResult); token != nil {
+						current.OAuth = savedOAuthToken(*token)
+						current.APIKey = ""
+						current.SessionID = ""
+					} else {
+						current.OAuth = nil
+						if sessionID != "" {
+							current.SessionID = sessionID
+						} else if client.SessionID != "" {
+							current.SessionID = client.SessionID
+						}
 					}
+					current.Server = serverState(client.Server)
 					_ = cfg.Save()
 				}
 
@@ -95,13 +131,13 @@ var loginCmd = &cobra.Command{
 				if flagJSON {
 					output.JSON(pollResult)
 				}
-				return
+				return nil
 
 			case "expired":
-				output.Error("Device code expired. Run 'cyfr login' again.")
+				return output.Error("Device code expired. Run 'cyfr login' again.")
 
 			case "denied":
-				output.Error("Authorization denied.")
+				return output.Error("Authorization denied.")
 
 			default:
 				// "pending" or unknown — keep polling
@@ -111,25 +147,87 @@ var loginCmd = &cobra.Command{
 	},
 }
 
+// loginWithKey verifies an API key against the server and stores it in the
+// current context.
+func loginWithKey(cmd *cobra.Command, key string) error {
+	if key == "-" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return output.Errorf("Failed to read API key from stdin: %v", err)
+		}
+		key = line
+	}
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(key, "cyfr_") {
+		return output.NewError(output.CodeInvalidArgument, "Invalid API key: keys start with cyfr_ (e.g. cyfr_sk_...). Create one with 'cyfr key create'.")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	client.SessionID = ""
+	client.APIKey = key
+	result, err := typed.New(client).Session(cmd.Context(), typed.SessionArgs{
+		Action: typed.SessionActionWhoami,
+	})
+	if err != nil {
+		return toolError(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+	}
+	if name := contextOverride(); name != "" {
+		cfg.CurrentContext = name
+	}
+	if cfg.Current() == nil {
+		return output.NewError(output.CodeNotFound, "Context '%s' not found. Use 'cyfr context add' first.", cfg.CurrentContext)
+	}
+	cfg.Current().APIKey = key
+	cfg.Current().SessionID = ""
+	cfg.Current().OAuth = nil
+	if err := cfg.Save(); err != nil {
+		return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+	}
+
+	if flagJSON {
+		output.JSON(result)
+		return nil
+	}
+	if userID, _ := result["user_id"].(string); userID != "" {
+		fmt.Printf("Logged in with API key as %s\n", userID)
+	} else {
+		fmt.Println("Logged in with API key.")
+	}
+	return nil
+}
+
 var logoutCmd = &cobra.Command{
 	Use:     "logout",
 	Short:   "End current session",
 	GroupID: "start",
 	Long:    "Invalidate the current session on the server and remove the cached session token from local config.",
 	Example: "  cyfr logout",
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
 
 		// Clear saved session locally first — even if the server call fails
 		// (e.g. session already expired), the user still wants local cleanup.
 		cfg, _ := config.Load()
 		if cfg.Current() != nil {
 			cfg.Current().SessionID = ""
+			cfg.Current().APIKey = ""
+			cfg.Current().OAuth = nil
 			_ = cfg.Save()
 		}
 
-		result, err := client.CallTool("session", map[string]any{
-			"action": "logout",
+		result, err := client.Session(cmd.Context(), typed.SessionArgs{
+			Action: typed.SessionActionLogout,
 		})
 		if err != nil {
 			// Session was already gone on the server — that's fine
@@ -138,7 +236,7 @@ var logoutCmd = &cobra.Command{
 			} else {
 				fmt.Println("Logged out successfully.")
 			}
-			return
+			return nil
 		}
 
 		if flagJSON {
@@ -146,6 +244,7 @@ var logoutCmd = &cobra.Command{
 		} else {
 			fmt.Println("Logged out successfully.")
 		}
+		return nil
 	},
 }
 
@@ -156,14 +255,17 @@ var whoamiCmd = &cobra.Command{
 	Long:    "Display the user, email, and provider associated with the current session.",
 	Example: `  cyfr whoami
   cyfr whoami --json`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
 
-		result, err := client.CallTool("session", map[string]any{
-			"action": "whoami",
+		result, err := client.Session(cmd.Context(), typed.SessionArgs{
+			Action: typed.SessionActionWhoami,
 		})
 		if err != nil {
-			handleToolError(err)
+			return toolError(err)
 		}
 
 		if flagJSON {
@@ -171,5 +273,6 @@ var whoamiCmd = &cobra.Command{
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
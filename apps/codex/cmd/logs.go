@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().Bool("server", false, "Show the logs of the local server container instead of an execution's")
+	logsCmd.Flags().String("since", "", "With --server, only show logs since a duration ago or a timestamp, e.g. 5m or 2026-01-02T15:04:05")
+	logsCmd.Flags().BoolP("follow", "f", false, "With --server, keep printing new log lines until interrupted")
+	logsCmd.Flags().String("tail", "", "With --server, only show this many lines from the end of the logs")
+	logsCmd.Flags().String("env", "", "With --server, show the logs of this environment of cyfr.yaml")
+}
+
+var logsCmd = &cobra.Command{
+	Use:     "logs [execution_id]",
+	Short:   "Show execution or server logs",
+	GroupID: "start",
+	Long: `Show the logs of an execution, like 'cyfr run --logs', or with --server the
+logs of the local CYFR server started by 'cyfr up', from the compose project
+in the current directory, or from .cyfr/server.log for 'cyfr up --native'.`,
+	Example: `  cyfr logs exec_abc123
+  cyfr logs --server
+  cyfr logs --server --since 5m -f`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, _ := cmd.Flags().GetBool("server")
+		if !server {
+			if len(args) == 0 {
+				return output.NewError(output.CodeInvalidArgument, "Usage: cyfr logs <execution_id>, or cyfr logs --server")
+			}
+			for _, name := range []string{"since", "follow", "tail", "env"} {
+				if cmd.Flags().Changed(name) {
+					return output.NewError(output.CodeInvalidArgument, "--%s only applies with --server", name)
+				}
+			}
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			return executionLogs(cmd.Context(), client, args[0])
+		}
+
+		if len(args) > 0 {
+			return output.NewError(output.CodeInvalidArgument, "--server takes no execution ID")
+		}
+		since, _ := cmd.Flags().GetString("since")
+		follow, _ := cmd.Flags().GetBool("follow")
+		tail, _ := cmd.Flags().GetString("tail")
+		// The log of a native server, unless containers have run since.
+		envName, _ := cmd.Flags().GetString("env")
+		if _, err := os.Stat(nativeLogFile); err == nil && envName == "" {
+			if _, ok := nativePID(); ok || !composeRunning() {
+				if since != "" {
+					return output.NewError(output.CodeInvalidArgument, "--since isn't supported for a server run with --native")
+				}
+				if !follow {
+					pageOutput()
+				}
+				return nativeLogs(cmd.Context(), tail, follow)
+			}
+		}
+		runtime, _, err := runtimeForEnv(cmd)
+		if err != nil {
+			return err
+		}
+		if !follow {
+			pageOutput()
+		}
+		return serverLogs(cmd.Context(), runtime, since, tail, follow)
+	},
+}
+
+// executionLogs prints the logs of an execution.
+func executionLogs(ctx context.Context, client *mcp.Client, id string) error {
+	result, err := typed.New(client).Execution(ctx, typed.ExecutionArgs{
+		Action:      typed.ExecutionActionLogs,
+		ExecutionID: id,
+	})
+	if err != nil {
+		return toolError(err)
+	}
+	pageOutput()
+	if flagJSON {
+		output.JSON(result)
+	} else {
+		output.KeyValue(result)
+	}
+	return nil
+}
+
+// serverLogs prints the logs of the local server with runtime's compose
+// logs.
+// Following them ends when ctx is, e.g. on Ctrl-C, which leaves the server
+// running.
+func serverLogs(ctx context.Context, runtime containerRuntime, since, tail string, follow bool) error {
+	args := []string{"logs"}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	if tail != "" {
+		args = append(args, "--tail", tail)
+	}
+	if follow {
+		args = append(args, "--follow")
+	}
+	c := runtime.compose(ctx, args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if follow && ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "")
+			return nil
+		}
+		return output.Errorf("Failed to show server logs: %v", err)
+	}
+	return nil
+}
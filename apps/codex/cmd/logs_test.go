@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestServerLogs(t *testing.T) {
+	// A docker that prints its arguments.
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var err error
+	out, _ := captureStdout(func() {
+		err = serverLogs(context.Background(), runtimeNamed("docker"), "5m", "100", true)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "compose logs --since 5m --tail 100 --follow"; strings.TrimSpace(out) != want {
+		t.Errorf("ran docker %q, want %q", strings.TrimSpace(out), want)
+	}
+}
+
+func TestExecutionLogs_NotFound(t *testing.T) {
+	f := mockserver.DefaultFixtures()
+	f.Errors = map[string]string{"execution.logs": "Execution exec_nope not found"}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+
+	err := executionLogs(context.Background(), mcp.NewClient(srv.URL), "exec_nope")
+	if output.Code(err) != output.CodeNotFound {
+		t.Errorf("err = %v (code %s), want %s", err, output.Code(err), output.CodeNotFound)
+	}
+}
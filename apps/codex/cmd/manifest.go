@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/wasm"
+)
+
+// publishedManifest builds what inspect --manifest shows of an inspected
+// component: its manifest as published, and the WIT interfaces its
+// artifact imports and exports. If the artifact can't be read, the
+// exports the registry reports are used and the reason is kept.
+func publishedManifest(ctx context.Context, client *mcp.Client, result map[string]any) map[string]any {
+	m := map[string]any{}
+	for _, field := range []string{"name", "publisher", "version", "type", "digest", "manifest"} {
+		if v, ok := result[field]; ok {
+			m[field] = v
+		}
+	}
+
+	var artifactErr error
+	if digest, _ := result["digest"].(string); digest == "" {
+		artifactErr = fmt.Errorf("the registry reports no digest")
+	} else if data, err := downloadBlob(ctx, client, withAlgorithm(digest)); err != nil {
+		artifactErr = err
+	} else if c, err := wasm.Parse(data); err != nil {
+		artifactErr = fmt.Errorf("can't read its interfaces: %v", err)
+	} else {
+		imports, exports := slices.Clone(c.Imports), slices.Clone(c.Exports)
+		sort.Strings(imports)
+		sort.Strings(exports)
+		m["imports"], m["exports"] = imports, exports
+		return m
+	}
+	m["artifact_error"] = artifactErr.Error()
+	if exports, ok := result["exports"]; ok {
+		m["exports"] = exports
+	}
+	return m
+}
+
+// printManifest prints a publishedManifest result: the manifest as
+// indented JSON, then the interfaces, a line each.
+func printManifest(ref string, m map[string]any) {
+	fmt.Printf("# %s\n", ref)
+	if manifest, ok := m["manifest"]; ok {
+		encoded, _ := json.MarshalIndent(manifest, "", "  ")
+		fmt.Println(string(encoded))
+	} else {
+		fmt.Println("# the registry has no manifest for this version")
+	}
+	if reason, ok := m["artifact_error"].(string); ok {
+		fmt.Printf("\n# interfaces from the registry: %s\n", reason)
+	}
+	for _, kind := range []string{"imports", "exports"} {
+		var names []string
+		switch v := m[kind].(type) {
+		case []string:
+			names = v
+		case []any:
+			for _, name := range v {
+				names = append(names, fmt.Sprint(name))
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", kind)
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestPublishedManifest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	data := componentBinary([]string{"cyfr:http/fetch@0.1.0"}, []string{"cyfr:catalyst/run@0.1.0"})
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.get_blob"] = map[string]any{"bytes": base64.StdEncoding.EncodeToString(data)}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	inspected := map[string]any{
+		"name": "feeds", "publisher": "local", "version": "0.1.0", "type": "catalyst", "digest": artifactDigest(data),
+		"manifest": map[string]any{"type": "catalyst", "secrets": []any{"A"}},
+	}
+	m := publishedManifest(context.Background(), client, inspected)
+	if !reflect.DeepEqual(m["imports"], []string{"cyfr:http/fetch@0.1.0"}) || !reflect.DeepEqual(m["exports"], []string{"cyfr:catalyst/run@0.1.0"}) {
+		t.Errorf("interfaces = %v, %v", m["imports"], m["exports"])
+	}
+	out, err := captureStdout(func() { printManifest("c:local.feeds:0.1.0", m) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# c:local.feeds:0.1.0\n", `"secrets": [`, "imports:\n  cyfr:http/fetch@0.1.0\n", "exports:\n  cyfr:catalyst/run@0.1.0\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't have %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInspectManifest_Mock(t *testing.T) {
+	// The fixtures' artifact is a core module and their inspect result has
+	// no manifest, so both are said.
+	out := runCLI(t, "inspect", "r:local.hello:0.1.0", "--manifest")
+	for _, want := range []string{"no manifest", "interfaces from the registry"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't have %q:\n%s", want, out)
+		}
+	}
+}
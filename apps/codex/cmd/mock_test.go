@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// runCLI runs the CLI against the built-in mock server and returns what it
+// printed to stdout. Commands that fail exit the process, so only
+// successful invocations can be tested this way.
+func runCLI(t *testing.T, args ...string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(config.EnvConfigPath, "")
+	resetFlags(rootCmd)
+	output.Meta = nil
+	callLog.calls = nil
+	projectCache.dir = ""
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	rootCmd.SetArgs(append([]string{"--mock"}, args...))
+	execErr := rootCmd.Execute()
+	w.Close()
+	out := <-done
+	if execErr != nil {
+		t.Fatalf("cyfr %s: %v", strings.Join(args, " "), execErr)
+	}
+	return out
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// since cobra keeps flag values between Execute calls.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func TestMock_Status(t *testing.T) {
+	out := runCLI(t, "status")
+	if !strings.Contains(out, "status:") || !strings.Contains(out, "ok") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestMock_KeyListJSON(t *testing.T) {
+	out := runCLI(t, "key", "list", "--json")
+	var result struct {
+		Keys  []map[string]any `json:"keys"`
+		Count float64          `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(result.Keys) != 1 || result.Keys[0]["name"] != "ci" || result.Count != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestMock_KeyCreateSendsTypedArgs(t *testing.T) {
+	runCLI(t, "key", "create", "--name", "ci", "--type", "secret", "--scope", "execute,read")
+	calls := mockServer.Calls()
+	last := calls[len(calls)-1]
+	if last.Tool != "key" || last.Args["action"] != "create" || last.Args["name"] != "ci" {
+		t.Fatalf("unexpected call: %+v", last)
+	}
+	scope, _ := last.Args["scope"].([]any)
+	if len(scope) != 2 || scope[0] != "execute" || scope[1] != "read" {
+		t.Errorf("scope = %v", last.Args["scope"])
+	}
+	if _, ok := last.Args["rate_limit"]; ok {
+		t.Error("unset rate_limit should not be sent")
+	}
+}
+
+func TestMock_GuideGet(t *testing.T) {
+	out := runCLI(t, "guide", "get", "component-guide")
+	if !strings.Contains(out, "# Component Guide") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestMock_ResourcesRead(t *testing.T) {
+	out := runCLI(t, "resources", "read", "opus://executions/exec_mock_1")
+	if !strings.Contains(out, `"status": "completed"`) {
+		t.Errorf("expected pretty-printed JSON resource, got:\n%s", out)
+	}
+}
+
+func TestMock_OutputFilters(t *testing.T) {
+	t.Cleanup(func() {
+		output.Query = ""
+		output.SetTemplate("")
+	})
+
+	if out := runCLI(t, "status", "-o", "go-template={{.status}} {{.version}}"); out != "ok 0.1.0\n" {
+		t.Errorf("go-template output = %q", out)
+	}
+	if out := runCLI(t, "status", "--query", ".services.opus"); out != "ok\n" {
+		t.Errorf("query output = %q", out)
+	}
+	if out := runCLI(t, "status", "--output", "json"); !strings.HasPrefix(out, "{") {
+		t.Errorf("--output json printed:\n%s", out)
+	}
+	if out := runCLI(t, "status"); !strings.Contains(out, "status:") {
+		t.Errorf("filters should not carry over to the next command:\n%s", out)
+	}
+}
+
+func TestMock_ListTables(t *testing.T) {
+	out := runCLI(t, "key", "list")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !reflect.DeepEqual(strings.Fields(lines[0]), []string{"NAME", "TYPE", "PREFIX", "CREATED_AT"}) {
+		t.Fatalf("key list printed:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[2], "ci ") {
+		t.Errorf("key list row: %q", lines[2])
+	}
+
+	out = runCLI(t, "run", "--list", "--columns", "execution_id,reference")
+	if !strings.Contains(out, "exec_mock_1") || !strings.Contains(out, "local.hello:0.1.0") || strings.Contains(out, "STATUS") {
+		t.Errorf("run --list --columns printed:\n%s", out)
+	}
+
+	if out := runCLI(t, "secret", "list"); !strings.Contains(out, "NAME") || !strings.Contains(out, "OPENAI_API_KEY") {
+		t.Errorf("secret list printed:\n%s", out)
+	}
+	if out := runCLI(t, "policy", "list"); out != "No policies.\n" {
+		t.Errorf("policy list printed %q", out)
+	}
+}
+
+func TestMock_Quiet(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"ps", "--all", "-q"}, "exec_mock_1\n"},
+		{[]string{"key", "list", "--quiet"}, "ci\n"},
+		{[]string{"secret", "list", "-q"}, "OPENAI_API_KEY\n"},
+		{[]string{"run", "-q", "r:local.hello:0.1.0"}, "exec_mock_1\n"},
+		{[]string{"run", "--list", "-q", "--json"}, "exec_mock_1\n"},
+	}
+	for _, tt := range tests {
+		if out := runCLI(t, tt.args...); out != tt.want {
+			t.Errorf("cyfr %s printed %q, want %q", strings.Join(tt.args, " "), out, tt.want)
+		}
+	}
+}
+
+func TestMock_DelimitedOutput(t *testing.T) {
+	t.Cleanup(func() { output.TableFormat = "" })
+
+	if out := runCLI(t, "search", "hello", "-o", "csv"); out != "NAME,TYPE,LATEST,DESCRIPTION\nlocal.hello,reagent,0.1.0,Says hello\n" {
+		t.Errorf("search -o csv printed %q", out)
+	}
+	if out := runCLI(t, "ps", "--all", "--output", "tsv"); !strings.HasPrefix(out, "EXECUTION_ID\tSTATUS\t") || !strings.Contains(out, "\nexec_mock_1\tcompleted\t") {
+		t.Errorf("ps --output tsv printed %q", out)
+	}
+	if out := runCLI(t, "policy", "list", "-o", "csv"); out != "COMPONENT_REF,UPDATED_AT\n" {
+		t.Errorf("an empty list should print only the header, got %q", out)
+	}
+	if out := runCLI(t, "key", "list"); strings.Contains(out, ",") {
+		t.Errorf("--output csv carried over to the next command:\n%s", out)
+	}
+}
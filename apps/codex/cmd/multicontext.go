@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// contextResult is the outcome of running a read-only tool call against one
+// context as part of a multi-context command.
+type contextResult struct {
+	Context string
+	Result  map[string]any
+	Err     error
+}
+
+// addMultiContextFlags registers --contexts and --all-contexts on a
+// read-only command.
+func addMultiContextFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("contexts", nil, "Run against these contexts (comma-separated) and compare results")
+	cmd.Flags().Bool("all-contexts", false, "Run against every configured context and compare results")
+}
+
+// selectedContexts returns the context names requested via --contexts or
+// --all-contexts, or nil if neither flag was given.
+func selectedContexts(cmd *cobra.Command) ([]string, error) {
+	all, _ := cmd.Flags().GetBool("all-contexts")
+	names, _ := cmd.Flags().GetStringSlice("contexts")
+	if all && len(names) > 0 {
+		return nil, output.Error("Use either --contexts or --all-contexts, not both")
+	}
+	if !all && len(names) == 0 {
+		return nil, nil
+	}
+	if flagURL != "" {
+		return nil, output.Error("--url cannot be combined with --contexts or --all-contexts")
+	}
+
+	cfg := loadConfigOrDefault()
+	if all {
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	for _, name := range names {
+		if _, ok := cfg.Contexts[name]; !ok {
+			return nil, output.NewError(output.CodeNotFound, "Context '%s' not found. Use 'cyfr context list' to see configured contexts.", name)
+		}
+	}
+	return names, nil
+}
+
+// callAcrossContexts invokes the same tool call against each named context
+// concurrently and returns the results in the order of names.
+func callAcrossContexts(ctx context.Context, names []string, tool string, args map[string]any) []contextResult {
+	cfg := loadConfigOrDefault()
+	results := make([]contextResult, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			client, err := clientForContext(cfg, name)
+			if err != nil {
+				results[i] = contextResult{Context: name, Err: err}
+				return
+			}
+			result, err := client.CallToolCtx(ctx, tool, args)
+			results[i] = contextResult{Context: name, Result: result, Err: err}
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// printContextResults prints per-context results for commands without a
+// tabular view: JSON keyed by context name, or one prefixed section each.
+func printContextResults(results []contextResult) {
+	if flagJSON {
+		output.JSON(contextResultsJSON(results))
+		return
+	}
+	for i, r := range results {
+		if i > 0 {
+			fmt.Println("")
+		}
+		fmt.Printf("[%s]\n", r.Context)
+		if r.Err != nil {
+			fmt.Printf("%-20s %v\n", "error:", r.Err)
+			continue
+		}
+		output.KeyValue(r.Result)
+	}
+}
+
+// contextResultsJSON converts per-context results into a map keyed by
+// context name, with failures reported as {"error": "..."}.
+func contextResultsJSON(results []contextResult) map[string]any {
+	out := make(map[string]any, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			out[r.Context] = map[string]any{"error": r.Err.Error()}
+		} else {
+			out[r.Context] = r.Result
+		}
+	}
+	return out
+}
+
+// contextErrors returns an error exiting non-zero if any context failed,
+// for commands that have printed the successful results. With --watch it
+// returns nil: the next refresh may succeed.
+func contextErrors(results []contextResult) error {
+	if watching {
+		return nil
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			return output.ExitError(1)
+		}
+	}
+	return nil
+}
+
+// warnContextErrors prints failed contexts to stderr. Used by tabular
+// commands, where errors can't be shown inline. With --watch they are
+// shown below the table instead, which each refresh redraws.
+func warnContextErrors(results []contextResult) {
+	for _, r := range results {
+		if r.Err != nil && watching {
+			fmt.Println(output.Highlight(fmt.Sprintf("Warning: context '%s': %v", r.Context, r.Err)))
+		} else if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: context '%s': %v\n", r.Context, r.Err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+// namespaceRoles are the roles a namespace member can have, from most to
+// least privileged.
+var namespaceRoles = []string{"owner", "maintainer", "publisher"}
+
+func init() {
+	namespaceCreateCmd.Flags().String("description", "", "What the namespace is for")
+	addPaginationFlags(namespaceListCmd, 100)
+	addColumnsFlag(namespaceListCmd)
+	namespaceTransferCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+	namespaceMembersAddCmd.Flags().String("role", "publisher", "Role: "+strings.Join(namespaceRoles, ", "))
+	addColumnsFlag(namespaceMembersListCmd)
+
+	namespaceMembersCmd.AddCommand(namespaceMembersListCmd, namespaceMembersAddCmd, namespaceMembersRemoveCmd)
+	namespaceCmd.AddCommand(namespaceCreateCmd, namespaceListCmd, namespaceTransferCmd, namespaceMembersCmd)
+	rootCmd.AddCommand(namespaceCmd)
+}
+
+var namespaceCmd = &cobra.Command{
+	Use:     "namespace",
+	Aliases: []string{"ns"},
+	Short:   "Manage registry namespaces and their members",
+	GroupID: "governance",
+	Long: `Claim namespaces in the server's registry, such as acme in catalyst:acme.sentiment, and manage who can publish under them. Only members of a namespace can publish components to it.
+
+Members have one of these roles:
+  owner       manages members, transfers the namespace, and publishes
+  maintainer  manages members below owner, yanks and tags versions, and publishes
+  publisher   publishes new versions`,
+}
+
+var namespaceCreateCmd = &cobra.Command{
+	Use:   "create <namespace>",
+	Short: "Claim a namespace",
+	Long:  "Claim a namespace in the registry, making you its owner. Namespaces are lowercase letters, digits and hyphens; \"local\" is reserved for unpublished components.",
+	Example: `  cyfr namespace create acme
+  cyfr namespace create acme-labs --description "Acme's experimental components"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := ref.ValidateNamespace(name); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "%v", err)
+		}
+		description, _ := cmd.Flags().GetString("description")
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callNamespace(cmd.Context(), client, typed.NamespaceArgs{
+			Action:      typed.NamespaceActionCreate,
+			Namespace:   name,
+			Description: description,
+		})
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Created namespace %s. Publish to it as <type>:%s.<name>.\n", name, name)
+		return nil
+	},
+}
+
+var namespaceListCmd = &cobra.Command{
+	Use:     "list [namespace]",
+	Aliases: []string{"ls"},
+	Short:   "List your namespaces, or what's published under one",
+	Long:    "List the namespaces you're a member of, with your role in each. Given a namespace, list the components published under it instead. At most --limit results are fetched (default 100); use --all to page through everything.",
+	Example: `  cyfr namespace list
+  cyfr namespace list acme`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, key := typed.NamespaceArgs{Action: typed.NamespaceActionList}, "namespaces"
+		if len(args) == 1 {
+			if err := ref.ValidateNamespace(args[0]); err != nil {
+				return output.NewError(output.CodeInvalidArgument, "%v", err)
+			}
+			params, key = typed.NamespaceArgs{Action: typed.NamespaceActionComponents, Namespace: args[0]}, "components"
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.CallToolPagedCtx(cmd.Context(), "namespace", params.Map(), key, pageLimit(cmd))
+		if err != nil {
+			return namespaceError(params, err)
+		}
+		if err := printList(cmd, result, key); err != nil {
+			return err
+		}
+		warnMoreResults(result)
+		return nil
+	},
+}
+
+var namespaceTransferCmd = &cobra.Command{
+	Use:   "transfer <namespace> <new-owner>",
+	Short: "Hand a namespace over to another owner",
+	Long:  "Make another user or organization the owner of a namespace. You stay a member as maintainer, unless the new owner removes you; the components published under it are unchanged.",
+	Example: `  cyfr namespace transfer acme acme-org
+  cyfr namespace transfer acme alice@example.com --yes`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, owner := args[0], args[1]
+		if err := ref.ValidateNamespace(name); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "%v", err)
+		}
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes && !confirm(fmt.Sprintf("Transfer namespace '%s' to %s?", name, owner)) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callNamespace(cmd.Context(), client, typed.NamespaceArgs{
+			Action:    typed.NamespaceActionTransfer,
+			Namespace: name,
+			Owner:     owner,
+		})
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Transferred namespace %s to %s.\n", name, owner)
+		return nil
+	},
+}
+
+var namespaceMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage who can publish under a namespace",
+	Long:  "List, add and remove the members of a namespace. Adding a member again changes their role.",
+}
+
+var namespaceMembersListCmd = &cobra.Command{
+	Use:     "list <namespace>",
+	Aliases: []string{"ls"},
+	Short:   "List the members of a namespace",
+	Example: "  cyfr namespace members list acme",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ref.ValidateNamespace(args[0]); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "%v", err)
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callNamespace(cmd.Context(), client, typed.NamespaceArgs{
+			Action:    typed.NamespaceActionMembers,
+			Namespace: args[0],
+		})
+		if err != nil {
+			return err
+		}
+		return printList(cmd, result, "members")
+	},
+}
+
+var namespaceMembersAddCmd = &cobra.Command{
+	Use:   "add <namespace> <member>",
+	Short: "Add a member to a namespace, or change their role",
+	Example: `  cyfr namespace members add acme alice@example.com
+  cyfr namespace members add acme bob@example.com --role maintainer`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, member := args[0], args[1]
+		if err := ref.ValidateNamespace(name); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "%v", err)
+		}
+		role, _ := cmd.Flags().GetString("role")
+		if !slices.Contains(namespaceRoles, role) {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --role %q: use %s", role, strings.Join(namespaceRoles, ", "))
+		}
+		if role == "owner" {
+			return output.NewError(output.CodeInvalidArgument, "A namespace has one owner; use 'cyfr namespace transfer' to change it")
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callNamespace(cmd.Context(), client, typed.NamespaceArgs{
+			Action:    typed.NamespaceActionAddMember,
+			Namespace: name,
+			Member:    member,
+			Role:      role,
+		})
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("%s is now a %s of %s.\n", member, role, name)
+		return nil
+	},
+}
+
+var namespaceMembersRemoveCmd = &cobra.Command{
+	Use:     "remove <namespace> <member>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a member from a namespace",
+	Long:    "Remove a member from a namespace. They can no longer publish under it; versions they published are kept. The owner can't be removed.",
+	Example: "  cyfr namespace members remove acme bob@example.com",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, member := args[0], args[1]
+		if err := ref.ValidateNamespace(name); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "%v", err)
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callNamespace(cmd.Context(), client, typed.NamespaceArgs{
+			Action:    typed.NamespaceActionRemoveMember,
+			Namespace: name,
+			Member:    member,
+		})
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Removed %s from %s.\n", member, name)
+		return nil
+	},
+}
+
+// callNamespace calls an action of the server's namespace tool.
+func callNamespace(ctx context.Context, client *typed.Client, params typed.NamespaceArgs) (map[string]any, error) {
+	result, err := client.Namespace(ctx, params)
+	if err != nil {
+		return nil, namespaceError(params, err)
+	}
+	return result, nil
+}
+
+// namespaceError describes a failed call of the namespace tool.
+func namespaceError(params typed.NamespaceArgs, err error) error {
+	name := params.Namespace
+	switch params.Action {
+	case typed.NamespaceActionList:
+		return output.Errorf("Cannot list namespaces: %v", err)
+	case typed.NamespaceActionComponents:
+		return output.Errorf("Cannot list components in %s: %v", name, err)
+	case typed.NamespaceActionMembers:
+		return output.Errorf("Cannot list members of %s: %v", name, err)
+	case typed.NamespaceActionAddMember:
+		return output.Errorf("Cannot add %s to %s: %v", params.Member, name, err)
+	case typed.NamespaceActionRemoveMember:
+		return output.Errorf("Cannot remove %s from %s: %v", params.Member, name, err)
+	}
+	return output.Errorf("Cannot %s namespace %s: %v", params.Action, name, err)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestNamespace_Mock(t *testing.T) {
+	if out := runCLI(t, "namespace", "create", "acme"); !strings.Contains(out, "Created namespace acme") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "namespace", "list"); !strings.Contains(out, "acme") || !strings.Contains(out, "owner") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "namespace", "list", "acme"); !strings.Contains(out, "acme.sentiment") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "namespace", "members", "list", "acme"); !strings.Contains(out, "mock@example.com") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "namespace", "members", "add", "acme", "alice@example.com"); !strings.Contains(out, "alice@example.com is now a publisher of acme") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestNamespaceArgs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, args := range [][]string{
+		{"namespace", "create", "Acme"},
+		{"namespace", "create", "local"},
+		{"namespace", "members", "add", "acme", "alice@example.com", "--role", "admin"},
+		{"namespace", "members", "add", "acme", "alice@example.com", "--role", "owner"},
+	} {
+		resetFlags(rootCmd)
+		rootCmd.SetArgs(append([]string{"--mock"}, args...))
+		err := rootCmd.Execute()
+		if output.Code(err) != output.CodeInvalidArgument {
+			t.Errorf("%v: err = %v, want %s", args, err, output.CodeInvalidArgument)
+		}
+	}
+}
+
+func TestCallNamespace(t *testing.T) {
+	srv := mockserver.New(mockserver.DefaultFixtures())
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := typed.New(mcp.NewClient(ts.URL))
+
+	args := typed.NamespaceArgs{Action: typed.NamespaceActionTransfer, Namespace: "acme", Owner: "acme-org"}
+	if _, err := callNamespace(context.Background(), client, args); err != nil {
+		t.Fatal(err)
+	}
+	calls := srv.Calls()
+	if len(calls) == 0 {
+		t.Fatal("no calls")
+	}
+	last := calls[len(calls)-1]
+	if last.Tool != "namespace" || last.Args["action"] != "transfer" || last.Args["owner"] != "acme-org" {
+		t.Errorf("call = %+v", last)
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/scaffold"
+)
+
+// The files of a server run natively, under the project's .cyfr/.
+var (
+	nativePIDFile = filepath.Join(".cyfr", "server.pid")
+	nativeLogFile = filepath.Join(".cyfr", "server.log")
+)
+
+// nativeBinary returns where the server binary of this CLI's version is
+// kept in the project.
+func nativeBinary() string {
+	version := Version
+	if version == "dev" || version == "" {
+		version = "latest"
+	}
+	name := "cyfr-server-" + version
+	if strings.HasSuffix(scaffold.ServerAsset(), ".exe") {
+		name += ".exe"
+	}
+	return filepath.Join(".cyfr", "bin", name)
+}
+
+// nativePID returns the process ID of the project's native server if it is
+// running. A pidfile left behind by a server that has exited is removed.
+func nativePID() (int, bool) {
+	data, err := os.ReadFile(nativePIDFile)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !processAlive(pid) {
+		os.Remove(nativePIDFile)
+		return 0, false
+	}
+	return pid, true
+}
+
+// startNative starts the server binary in the background, publishing it on
+// port, with its output appended to its log file. The binary is downloaded
+// first if the project doesn't have it yet.
+func startNative(port int) error {
+	bin := nativeBinary()
+	if _, err := os.Stat(bin); err != nil {
+		spinner := output.NewSpinner("Downloading the CYFR server for " + strings.TrimPrefix(scaffold.ServerAsset(), "cyfr-server-"))
+		err := scaffold.DownloadServer(Version, bin)
+		spinner.Stop()
+		if err != nil {
+			return output.Errorf("Failed to download the server: %v", scaffoldError(err))
+		}
+	}
+	absBin, err := filepath.Abs(bin)
+	if err != nil {
+		return output.Errorf("Failed to start: %v", err)
+	}
+	env, err := nativeEnv(port)
+	if err != nil {
+		return output.Errorf("Failed to start: %v", err)
+	}
+
+	logFile, err := os.OpenFile(nativeLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return output.Errorf("Failed to start: %v", err)
+	}
+	defer logFile.Close()
+	fmt.Fprintf(logFile, "--- cyfr up --native at %s\n", time.Now().Format(time.RFC3339))
+
+	c := exec.Command(absBin)
+	c.Env = env
+	c.Stdout = logFile
+	c.Stderr = logFile
+	detach(c)
+	if err := c.Start(); err != nil {
+		return output.Errorf("Failed to start: %v", err)
+	}
+	if err := os.WriteFile(nativePIDFile, []byte(strconv.Itoa(c.Process.Pid)+"\n"), 0644); err != nil {
+		c.Process.Kill()
+		return output.Errorf("Failed to start: %v", err)
+	}
+	return c.Process.Release()
+}
+
+// nativeEnv returns the environment of the native server: the project's
+// .env over the CLI's own, with the paths and port the docker-compose.yml
+// maps into the container set for this host instead.
+func nativeEnv(port int) ([]string, error) {
+	env := os.Environ()
+	if data, err := os.ReadFile(".env"); err == nil {
+		env = append(env, parseDotEnv(data)...)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	db, err := filepath.Abs(filepath.Join("data", "cyfr.db"))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(db), 0755); err != nil {
+		return nil, err
+	}
+	// Later entries win.
+	return append(env, fmt.Sprintf("CYFR_PORT=%d", port), "CYFR_DATABASE_PATH="+db), nil
+}
+
+// parseDotEnv returns the KEY=value entries of a .env file, without
+// comments, blank lines or quotes around values.
+func parseDotEnv(data []byte) []string {
+	var env []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env = append(env, key+"="+value)
+	}
+	return env
+}
+
+// stopNative stops the native server with pid, killing it if it hasn't
+// exited after ten seconds, and removes its pidfile.
+func stopNative(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := terminate(p); err != nil {
+		return err
+	}
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); {
+		if !processAlive(pid) {
+			return os.Remove(nativePIDFile)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := p.Kill(); err != nil {
+		return err
+	}
+	return os.Remove(nativePIDFile)
+}
+
+// nativeLogs prints the native server's log file, or its last tail lines.
+// Following it polls for new lines until ctx ends.
+func nativeLogs(ctx context.Context, tail string, follow bool) error {
+	f, err := os.Open(nativeLogFile)
+	if err != nil {
+		return output.Errorf("Failed to show server logs: %v", err)
+	}
+	defer f.Close()
+	if tail != "" && tail != "all" {
+		n, err := strconv.Atoi(tail)
+		if err != nil || n < 0 {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --tail %q: use a number of lines", tail)
+		}
+		if err := seekLastLines(f, n); err != nil {
+			return output.Errorf("Failed to show server logs: %v", err)
+		}
+	}
+	for {
+		if _, err := io.Copy(os.Stdout, f); err != nil {
+			return output.Errorf("Failed to show server logs: %v", err)
+		}
+		if !follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr, "")
+			return nil
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// seekLastLines moves f to the start of its last n lines.
+func seekLastLines(f *os.File, n int) error {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	start := len(data)
+	if start > 0 && data[start-1] == '\n' {
+		start--
+	}
+	for ; n > 0 && start > 0; n-- {
+		start = bytes.LastIndexByte(data[:start], '\n')
+	}
+	if n > 0 || start < 0 {
+		start = -1
+	}
+	_, err = f.Seek(int64(start+1), io.SeekStart)
+	return err
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	data := "# comment\nCYFR_PORT=4000\n\nexport CYFR_HOST = 0.0.0.0\nCYFR_SECRET_KEY_BASE=\"a=b\"\nnot an entry\n"
+	want := []string{"CYFR_PORT=4000", "CYFR_HOST=0.0.0.0", "CYFR_SECRET_KEY_BASE=a=b"}
+	if got := parseDotEnv([]byte(data)); !slices.Equal(got, want) {
+		t.Errorf("parseDotEnv = %q, want %q", got, want)
+	}
+}
+
+func TestNativeLogs(t *testing.T) {
+	chdirTemp(t)
+	if err := os.MkdirAll(".cyfr", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(nativeLogFile, []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for tail, want := range map[string]string{"": "one\ntwo\nthree\n", "2": "two\nthree\n", "9": "one\ntwo\nthree\n", "0": ""} {
+		var err error
+		out, _ := captureStdout(func() {
+			err = nativeLogs(context.Background(), tail, false)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != want {
+			t.Errorf("--tail %q printed %q, want %q", tail, out, want)
+		}
+	}
+
+	// A pidfile of a process that no longer exists is stale.
+	if err := os.WriteFile(nativePIDFile, []byte("999999999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if pid, ok := nativePID(); ok {
+		t.Errorf("nativePID = %d for a process that doesn't exist", pid)
+	}
+	if _, err := os.Stat(nativePIDFile); !os.IsNotExist(err) {
+		t.Error("stale pidfile wasn't removed")
+	}
+}
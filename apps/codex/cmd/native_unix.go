@@ -0,0 +1,37 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach makes c run in its own session, so that it outlives the CLI and
+// doesn't get the terminal's Ctrl-C.
+func detach(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether a process with pid exists.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	return err == nil && p.Signal(syscall.Signal(0)) == nil
+}
+
+// terminate asks p to exit.
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
+
+// setEcho turns the echo of what is typed on the terminal f on or off.
+func setEcho(f *os.File, on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	c := exec.Command("stty", mode)
+	c.Stdin = f
+	return c.Run()
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach makes c run in its own process group, so that it outlives the CLI
+// and doesn't get the console's Ctrl-C.
+func detach(c *exec.Cmd) {
+	c.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// processAlive reports whether a process with pid exists. FindProcess only
+// succeeds on Windows if it does.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
+
+// terminate makes p exit. Windows has no signal to ask it to.
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableEchoInput is the console mode flag that echoes what is typed.
+const enableEchoInput = 0x0004
+
+// setEcho turns the echo of what is typed on the console f on or off.
+func setEcho(f *os.File, on bool) error {
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode); err != nil {
+		return err
+	}
+	if on {
+		mode |= enableEchoInput
+	} else {
+		mode &^= enableEchoInput
+	}
+	if ok, _, err := setConsoleMode.Call(f.Fd(), uintptr(mode)); ok == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/cyfr/codex/internal/scaffold"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+	newCmd.AddCommand(newComponentCmd)
+
+	newComponentCmd.Flags().String("lang", "", "Language of the starter code: "+strings.Join(scaffold.Languages, ", ")+" (required)")
+	newComponentCmd.Flags().String("type", "catalyst", "Component type: catalyst, reagent, or formula")
+	newComponentCmd.Flags().String("version", "0.1.0", "Version directory to create")
+	newComponentCmd.Flags().String("description", "", "Description for the manifest and README (default \"A <type> named <name>.\")")
+	newComponentCmd.Flags().Bool("register", false, "Build the component with 'cyfr build' and register it")
+	_ = newComponentCmd.MarkFlagRequired("lang")
+}
+
+var newCmd = &cobra.Command{
+	Use:     "new",
+	Short:   "Create project files from templates",
+	GroupID: "component",
+	Long:    "Create new project files, such as a component, from the templates published with each CYFR release.",
+}
+
+var newComponentCmd = &cobra.Command{
+	Use:   "component <name>",
+	Short: "Create a component from a language template",
+	Long: `Create a component in the local namespace of the components/ layout,
+components/<type>s/local/<name>/<version>/, from the starter code for a
+language: its source, the WIT bindings for the component type, and a build
+script that compiles it to the component's .wasm file. A cyfr-manifest.json
+and a README.md to fill in are added, and the project's WIT world for the
+type is copied in if the template has none.
+
+Build it with 'cyfr build', then register it with 'cyfr register';
+--register does both right away.`,
+	Example: `  cyfr new component sentiment --lang go
+  cyfr new component parser --lang python --type reagent
+  cyfr new component report --lang rust --type f --version 1.0.0
+  cyfr new component fetcher --lang go --description "Fetches feeds" --register`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		lang, _ := cmd.Flags().GetString("lang")
+		compType, _ := cmd.Flags().GetString("type")
+		version, _ := cmd.Flags().GetString("version")
+		description, _ := cmd.Flags().GetString("description")
+		register, _ := cmd.Flags().GetBool("register")
+
+		compType = ref.ExpandType(compType)
+		if !ref.IsTypePrefix(compType) {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --type %q (use catalyst, reagent, or formula)", compType)
+		}
+		if err := checkLanguage(lang); err != nil {
+			return err
+		}
+		if _, err := ref.Parse(fmt.Sprintf("%s:local.%s:%s", compType, name, version)); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Invalid component: %v", err)
+		}
+
+		dir := filepath.Join("components", compType+"s", "local", name, version)
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			return output.Errorf("%s already exists and isn't empty.", dir)
+		}
+		if err := createFromTemplate(lang, dir, name, compType); err != nil {
+			return err
+		}
+		if description == "" {
+			description = fmt.Sprintf("A %s named %s.", compType, name)
+		}
+		if err := writeComponentFiles(dir, name, compType, version, lang, description); err != nil {
+			return output.Errorf("Failed to create %s: %v", dir, err)
+		}
+
+		reference := fmt.Sprintf("%s:local.%s:%s", compType, name, version)
+		result := map[string]any{"status": "created", "reference": reference, "path": dir, "lang": lang}
+		if !flagJSON {
+			fmt.Printf("Created %s from the %s template in %s/\n", reference, lang, dir)
+		}
+		if !register {
+			if flagJSON {
+				output.JSON(result)
+			} else {
+				fmt.Printf("Next: run 'cyfr build %s', then 'cyfr register %s/'.\n", dir, dir)
+			}
+			return nil
+		}
+
+		build, err := loadComponentBuild(dir)
+		if err != nil {
+			return err
+		}
+		if err := build.compile(cmd.Context()); err != nil {
+			return output.Errorf("%v. Fix it and run 'cyfr build %s && cyfr register %s/'.", err, dir, dir)
+		}
+		if err := checkBeforeRegister(cmd, dir); err != nil {
+			return err
+		}
+		if _, err := registerComponent(cmd.Context(), dir); err != nil {
+			return err
+		}
+		result["status"] = "registered"
+		if flagJSON {
+			output.JSON(result)
+		} else {
+			fmt.Printf("Built and registered %s.\n", reference)
+		}
+		return nil
+	},
+}
+
+// checkLanguage returns an error unless lang has a component template.
+func checkLanguage(lang string) error {
+	if !slices.Contains(scaffold.Languages, lang) {
+		return output.NewError(output.CodeInvalidArgument, "Unknown language %q (use %s)", lang, strings.Join(scaffold.Languages, ", "))
+	}
+	return nil
+}
+
+// createFromTemplate downloads the template for lang into dir, for a
+// component with the given name and type.
+func createFromTemplate(lang, dir, name, compType string) error {
+	spinner := output.NewSpinner("Downloading the " + lang + " template")
+	err := scaffold.Template(Version, lang, dir, name, compType)
+	spinner.Stop()
+	if err != nil {
+		return output.Errorf("Failed to download the %s template: %v", lang, scaffoldError(err))
+	}
+	return nil
+}
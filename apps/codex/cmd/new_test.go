@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteComponentFiles(t *testing.T) {
+	chdirTemp(t)
+	os.MkdirAll("wit/catalyst/deps/cyfr-http", 0755)
+	os.WriteFile("wit/catalyst/world.wit", []byte("package cyfr:catalyst@0.2.0;\n\nworld catalyst {}\n"), 0644)
+	os.WriteFile("wit/catalyst/deps/cyfr-http/interfaces.wit", []byte("package cyfr:http@0.1.0;\n"), 0644)
+	dir := filepath.Join("components", "catalysts", "local", "feeds", "0.1.0")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("from the template\n"), 0644)
+
+	if err := writeComponentFiles(dir, "feeds", "catalyst", "0.1.0", "go", "Fetches feeds"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, componentManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest map[string]any
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("manifest isn't JSON: %v\n%s", err, data)
+	}
+	if manifest["id"] != "catalyst:local.feeds" || manifest["version"] != "0.1.0" || manifest["description"] != "Fetches feeds" || manifest["wasi"] == nil ||
+		manifest["build"].(map[string]any)["language"] != "go" {
+		t.Errorf("manifest = %s", data)
+	}
+	if readme, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(readme) != "from the template\n" {
+		t.Errorf("README from the template replaced: %q", readme)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wit", "deps", "cyfr-http", "interfaces.wit")); err != nil {
+		t.Errorf("WIT world not copied: %v", err)
+	}
+
+	// A reagent gets a README naming its world, and no wasi.
+	dir = filepath.Join("components", "reagents", "local", "calc", "1.0.0")
+	os.MkdirAll(dir, 0755)
+	if err := writeComponentFiles(dir, "calc", "reagent", "1.0.0", "rust", "Calculates"); err != nil {
+		t.Fatal(err)
+	}
+	readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	for _, want := range []string{"`reagent:local.calc:1.0.0`", "`cyfr:reagent` world", "cyfr register components/reagents/local/calc/1.0.0/"} {
+		if !strings.Contains(string(readme), want) {
+			t.Errorf("README doesn't contain %q:\n%s", want, readme)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, componentManifestFile)); strings.Contains(string(data), "wasi") {
+		t.Errorf("reagent manifest has wasi:\n%s", data)
+	}
+}
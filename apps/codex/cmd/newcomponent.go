@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// componentManifestFile is the manifest every component directory has
+// beside its .wasm file.
+const componentManifestFile = "cyfr-manifest.json"
+
+// componentManifest is the cyfr-manifest.json of a new component, with the
+// fields the component guide requires and empty schemas to fill in.
+type componentManifest struct {
+	ID          string                  `json:"id"`
+	Type        string                  `json:"type"`
+	Version     string                  `json:"version"`
+	Description string                  `json:"description"`
+	WASI        map[string]bool         `json:"wasi,omitempty"`
+	Schema      map[string]any          `json:"schema"`
+	Build       *componentBuildSettings `json:"build,omitempty"`
+}
+
+// newComponentManifest returns the manifest of a new component written in
+// lang. Catalysts have to declare their WASI capabilities, which start out
+// off.
+func newComponentManifest(name, compType, version, lang, description string) componentManifest {
+	m := componentManifest{
+		ID:          fmt.Sprintf("%s:local.%s", compType, name),
+		Type:        compType,
+		Version:     version,
+		Description: description,
+		Schema: map[string]any{
+			"input":  map[string]any{"type": "object"},
+			"output": map[string]any{"type": "object"},
+		},
+		Build: &componentBuildSettings{Language: lang},
+	}
+	if compType == "catalyst" {
+		m.WASI = map[string]bool{"http": false, "secrets": false}
+	}
+	return m
+}
+
+// witPackagePattern matches the package declaration of a WIT file.
+var witPackagePattern = regexp.MustCompile(`(?m)^package\s+([^;\s]+)\s*;`)
+
+// witPackage returns the WIT package of the project's world for compType,
+// e.g. "cyfr:catalyst@0.1.0", read from wit/<type>/world.wit.
+func witPackage(compType string) string {
+	data, err := os.ReadFile(filepath.Join("wit", compType, "world.wit"))
+	if m := witPackagePattern.FindSubmatch(data); err == nil && m != nil {
+		return string(m[1])
+	}
+	return "cyfr:" + compType
+}
+
+// componentReadme returns the README.md stub of a new component.
+func componentReadme(name, compType, version, description string) string {
+	dir := filepath.ToSlash(filepath.Join("components", compType+"s", "local", name, version))
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n", name, description)
+	fmt.Fprintf(&b, "Reference: `%s:local.%s:%s`\n\n", compType, name, version)
+	fmt.Fprintf(&b, "Implements the `%s` world (wit/%s/world.wit).\n\n", witPackage(compType), compType)
+	b.WriteString("## Input\n\nDescribe the input here and in the schema in cyfr-manifest.json.\n\n")
+	b.WriteString("## Output\n\nDescribe the output here and in the schema in cyfr-manifest.json.\n\n")
+	fmt.Fprintf(&b, "## Build and register\n\n```sh\ncyfr build %s\ncyfr register %s/\n```\n", dir, dir)
+	return b.String()
+}
+
+// writeComponentFiles adds what a language template doesn't provide to the
+// new component in dir: its manifest, with lang to build it with, a README and, if the template has no
+// WIT files, a copy of the project's world for the component type. Files
+// the template created are kept.
+func writeComponentFiles(dir, name, compType, version, lang, description string) error {
+	manifest, err := json.MarshalIndent(newComponentManifest(name, compType, version, lang, description), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeNewFile(filepath.Join(dir, componentManifestFile), append(manifest, '\n')); err != nil {
+		return err
+	}
+	if err := writeNewFile(filepath.Join(dir, "README.md"), []byte(componentReadme(name, compType, version, description))); err != nil {
+		return err
+	}
+	if hasWIT(dir) {
+		return nil
+	}
+	src := filepath.Join("wit", compType)
+	if _, err := os.Stat(src); err != nil {
+		return nil // not a project with WIT definitions
+	}
+	return copyDir(src, filepath.Join(dir, "wit"))
+}
+
+// writeNewFile writes data to path unless the file already exists.
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// hasWIT reports whether there is a .wit file anywhere under dir.
+func hasWIT(dir string) bool {
+	found := false
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".wit") {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// copyDir copies the files under src to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
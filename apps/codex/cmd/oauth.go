@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// oauthToken converts a saved token to the client's form. An unreadable
+// expiry is treated as already passed, so the token is refreshed.
+func oauthToken(t *config.OAuthToken) *mcp.OAuthToken {
+	out := &mcp.OAuthToken{
+		AccessToken:   t.AccessToken,
+		RefreshToken:  t.RefreshToken,
+		TokenEndpoint: t.TokenEndpoint,
+		ClientID:      t.ClientID,
+	}
+	if t.Expiry != "" {
+		expiry, err := time.Parse(time.RFC3339, t.Expiry)
+		if err != nil {
+			expiry = time.Unix(0, 0)
+		}
+		out.Expiry = expiry
+	}
+	return out
+}
+
+// savedOAuthToken converts a client token to its saved form.
+func savedOAuthToken(t mcp.OAuthToken) *config.OAuthToken {
+	out := &config.OAuthToken{
+		AccessToken:   t.AccessToken,
+		RefreshToken:  t.RefreshToken,
+		TokenEndpoint: t.TokenEndpoint,
+		ClientID:      t.ClientID,
+	}
+	if !t.Expiry.IsZero() {
+		out.Expiry = t.Expiry.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+// saveOAuthToken returns an OnTokenRefresh callback that stores refreshed
+// tokens in the named context, so the next command starts with them.
+func saveOAuthToken(contextName string) func(mcp.OAuthToken) {
+	return func(t mcp.OAuthToken) {
+		_, err := config.Update(func(cfg *config.Config) error {
+			if cfg.Contexts[contextName] != nil {
+				cfg.Contexts[contextName].OAuth = savedOAuthToken(t)
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save refreshed access token: %v\n", err)
+		}
+	}
+}
+
+// tokenFromResult reads an OAuth token from a token response, as returned
+// by a token endpoint or by a device-flow poll on servers that issue
+// tokens, with the endpoint to refresh it at. It returns nil if there is no
+// access token.
+func tokenFromResult(result map[string]any) *mcp.OAuthToken {
+	access, _ := result["access_token"].(string)
+	if access == "" {
+		return nil
+	}
+	t := &mcp.OAuthToken{AccessToken: access}
+	t.RefreshToken, _ = result["refresh_token"].(string)
+	t.TokenEndpoint, _ = result["token_endpoint"].(string)
+	t.ClientID, _ = result["client_id"].(string)
+	if secs, _ := result["expires_in"].(float64); secs > 0 {
+		t.Expiry = time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return t
+}
+
+// loginWithToken verifies an OAuth token read from a token response file
+// (or stdin for "-") and stores it in the current context.
+func loginWithToken(cmd *cobra.Command, path string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return output.Errorf("Failed to read token: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return output.NewError(output.CodeInvalidArgument, "Invalid token file: %v", err)
+	}
+	token := tokenFromResult(result)
+	if token == nil {
+		return output.NewError(output.CodeInvalidArgument, "Invalid token file: no access_token found.")
+	}
+	if token.RefreshToken != "" && token.TokenEndpoint == "" {
+		fmt.Fprintln(os.Stderr, "Warning: no token_endpoint given; the token can't be refreshed when it expires.")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	client.SessionID = ""
+	client.APIKey = ""
+	client.OAuth = token
+	client.OnTokenRefresh = nil
+	who, err := typed.New(client).Session(cmd.Context(), typed.SessionArgs{
+		Action: typed.SessionActionWhoami,
+	})
+	if err != nil {
+		return toolError(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+	}
+	if name := contextOverride(); name != "" {
+		cfg.CurrentContext = name
+	}
+	if cfg.Current() == nil {
+		return output.NewError(output.CodeNotFound, "Context '%s' not found. Use 'cyfr context add' first.", cfg.CurrentContext)
+	}
+	// Save the client's token: verifying may have refreshed it.
+	cfg.Current().OAuth = savedOAuthToken(*client.OAuth)
+	cfg.Current().APIKey = ""
+	cfg.Current().SessionID = ""
+	if err := cfg.Save(); err != nil {
+		return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+	}
+
+	if flagJSON {
+		output.JSON(who)
+		return nil
+	}
+	if userID, _ := who["user_id"].(string); userID != "" {
+		fmt.Printf("Logged in with OAuth token as %s\n", userID)
+	} else {
+		fmt.Println("Logged in with OAuth token.")
+	}
+	return nil
+}
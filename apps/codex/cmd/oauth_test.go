@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+func TestOAuthToken_RoundTrip(t *testing.T) {
+	saved := &config.OAuthToken{
+		AccessToken:   "a",
+		RefreshToken:  "r",
+		Expiry:        "2026-01-01T12:00:00Z",
+		TokenEndpoint: "https://idp.example.com/token",
+		ClientID:      "cyfr-cli",
+	}
+	if got := savedOAuthToken(*oauthToken(saved)); *got != *saved {
+		t.Errorf("round trip = %+v, want %+v", got, saved)
+	}
+
+	// An unreadable expiry forces a refresh rather than trusting the token.
+	bad := oauthToken(&config.OAuthToken{AccessToken: "a", Expiry: "tomorrow"})
+	if !bad.Expiry.Before(time.Now()) {
+		t.Errorf("expected an unreadable expiry to be in the past, got %v", bad.Expiry)
+	}
+}
+
+func TestTokenFromResult(t *testing.T) {
+	if tokenFromResult(map[string]any{"session_id": "s"}) != nil {
+		t.Error("expected nil without an access_token")
+	}
+	tok := tokenFromResult(map[string]any{
+		"access_token":   "a",
+		"refresh_token":  "r",
+		"expires_in":     float64(3600),
+		"token_endpoint": "https://idp.example.com/token",
+	})
+	if tok == nil || tok.AccessToken != "a" || tok.RefreshToken != "r" || tok.TokenEndpoint == "" {
+		t.Fatalf("unexpected token: %+v", tok)
+	}
+	if d := time.Until(tok.Expiry); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("expiry %v not about an hour away", tok.Expiry)
+	}
+}
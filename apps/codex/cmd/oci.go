@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyfr/codex/internal/cache"
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/oci"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// registryRef parses a normalized reference and reports whether it names
+// a component in an OCI registry (ghcr.io/acme/sentiment:1.0.0) rather
+// than in the server's registry.
+func registryRef(raw string) (ref.ComponentRef, bool) {
+	r, err := ref.Parse(raw)
+	return r, err == nil && r.Registry != ""
+}
+
+// newOCIClient creates a client for the OCI registry at host, with
+// credentials from CYFR_REGISTRY_USERNAME and CYFR_REGISTRY_PASSWORD if
+// set, and otherwise those saved by 'cyfr registry login' for it.
+func newOCIClient(host string) *oci.Client {
+	c := oci.NewClient()
+	c.Username = os.Getenv("CYFR_REGISTRY_USERNAME")
+	c.Password = os.Getenv("CYFR_REGISTRY_PASSWORD")
+	if c.Username != "" || c.Password != "" {
+		return c
+	}
+	cfg := loadConfigOrDefault()
+	if name, reg := cfg.RegistryForHost(host); reg != nil {
+		c.Username = reg.Username
+		if password, err := cfg.RegistryPassword(name); err == nil {
+			c.Password = password
+		}
+	}
+	return c
+}
+
+// routeRegistry returns the OCI reference normalized is routed to: that in
+// the registry named registry if one is given, or else in the registry
+// its namespace is routed to. References already naming a registry, and
+// those in namespaces no registry has, are returned unchanged.
+func routeRegistry(normalized, registry string) (string, error) {
+	r, err := ref.Parse(normalized)
+	if err != nil || r.Registry != "" {
+		return normalized, nil
+	}
+	cfg := loadConfigOrDefault()
+	var reg *config.Registry
+	if registry != "" {
+		if reg = cfg.Registries[registry]; reg == nil {
+			return "", output.NewError(output.CodeNotFound, "Registry '%s' not found. Add it with 'cyfr registry add'.", registry)
+		}
+	} else if _, reg = cfg.RegistryFor(r.Namespace); reg == nil {
+		return normalized, nil
+	}
+	r.Registry = reg.Host()
+	if prefix := reg.Prefix(); prefix != "" {
+		r.Namespace = prefix + "/" + r.Namespace
+	}
+	return r.String(), nil
+}
+
+// ociTags lists the tags of r's repository, for resolving constraints.
+func ociTags(ctx context.Context, r ref.ComponentRef) ([]string, error) {
+	return newOCIClient(r.Registry).Tags(ctx, r.Registry, r.Repository())
+}
+
+// pullOCI downloads a component from an OCI registry into the local
+// component layout, checking it against r's digest pin if it has one.
+func pullOCI(ctx context.Context, r ref.ComponentRef) (map[string]any, error) {
+	spinner := output.NewSpinner("Pulling")
+	spinner.Step(r.Unpinned())
+	artifact, err := newOCIClient(r.Registry).Pull(ctx, r.Registry, r.Repository(), r.Version)
+	spinner.Stop()
+	if err != nil {
+		if isInterrupted(err) {
+			return nil, errInterrupted()
+		}
+		return nil, output.Errorf("Pull failed: %s: %v", r.Unpinned(), err)
+	}
+	if r.Digest != "" {
+		if err := checkDigest(r.Unpinned(), r.Digest, artifact.Digest); err != nil {
+			return nil, err
+		}
+	}
+
+	typ := r.Type
+	if typ == "" {
+		typ = artifact.Type()
+	}
+	if !ref.IsTypePrefix(typ) {
+		return nil, output.Errorf("Cannot tell the component type of %s: the artifact doesn't record it. Add a type prefix, e.g. c:%s", r.Unpinned(), r.Unpinned())
+	}
+	typ = ref.ExpandType(typ)
+	version := r.Version
+	if version == "latest" && artifact.Version() != "" {
+		version = artifact.Version()
+	}
+
+	path := filepath.Join("components", typ+"s", ociNamespace(r), r.Name, version, typ+".wasm")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, output.Errorf("Pull failed: %v", err)
+	}
+	if err := os.WriteFile(path, artifact.Data, 0644); err != nil {
+		return nil, output.Errorf("Pull failed: %v", err)
+	}
+	if c, err := cache.Open(); err == nil {
+		if _, err := c.Put(r.Unpinned(), artifact.Data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: not cached: %v\n", err)
+		}
+	}
+
+	result := map[string]any{
+		"status":    "pulled",
+		"reference": r.Unpinned(),
+		"path":      path,
+		"digest":    artifact.Digest,
+		"size":      len(artifact.Data),
+	}
+	if r.Digest != "" {
+		result["verified"] = true
+	}
+	return result, nil
+}
+
+// ociNamespace picks the local namespace directory for a component pulled
+// from an OCI registry: the last segment of its repository path that isn't
+// a type directory, or "local".
+func ociNamespace(r ref.ComponentRef) string {
+	segments := strings.Split(r.Namespace, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if s := segments[i]; s != "" && !ref.IsTypeDir(s) {
+			return s
+		}
+	}
+	return "local"
+}
+
+// publishOCI pushes a local WASM artifact to an OCI registry as r, with
+// its signature bundle if 'cyfr sign' signed it.
+func publishOCI(ctx context.Context, r ref.ComponentRef, artifactPath string) (map[string]any, error) {
+	if r.Type == "" {
+		return nil, output.Errorf("Cannot publish %s: add a type prefix, e.g. c:%s", r, r)
+	}
+	if r.Digest != "" {
+		return nil, output.Errorf("Cannot publish %s: a digest pin can't be published; remove @%s.", r, r.Digest)
+	}
+	if r.HasConstraint() {
+		return nil, output.Errorf("Cannot publish %s: publish needs an exact version, not a constraint.", r)
+	}
+	if artifactPath == "" {
+		artifactPath = filepath.Join("components", r.Type+"s", ociNamespace(r), r.Name, r.Version, r.Type+".wasm")
+	}
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return nil, output.Errorf("Cannot read artifact: %v (use --artifact to point at the .wasm file)", err)
+	}
+	signature, err := artifactSignature(artifactPath)
+	if err != nil {
+		return nil, output.Errorf("Cannot publish %s: %v", r, err)
+	}
+	warnUnsigned(artifactPath, signature)
+
+	spinner := output.NewSpinner("Publishing")
+	spinner.Step(r.String())
+	manifest, err := newOCIClient(r.Registry).Push(ctx, r.Registry, r.Repository(), r.Version, data, r.Type, signature)
+	spinner.Stop()
+	if err != nil {
+		if isInterrupted(err) {
+			return nil, errInterrupted()
+		}
+		return nil, output.Errorf("Publish failed: %s: %v", r, err)
+	}
+	return map[string]any{
+		"status":          "published",
+		"reference":       r.String(),
+		"artifact":        artifactPath,
+		"digest":          oci.Digest(data),
+		"manifest_digest": manifest,
+		"signed":          signature != nil,
+	}, nil
+}
+
+// verifyOCIPinned downloads a pinned component from its OCI registry and
+// returns an error unless the artifact has the pinned digest.
+func verifyOCIPinned(ctx context.Context, reference, pinned string) error {
+	r, _ := registryRef(reference)
+	artifact, err := newOCIClient(r.Registry).Pull(ctx, r.Registry, r.Repository(), r.Version)
+	if err != nil {
+		return output.Errorf("Cannot verify %s: %v", reference, err)
+	}
+	return checkDigest(reference, pinned, artifact.Digest)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/oci"
+)
+
+// ociRegistry serves the mock artifact as acme/sentiment tags 1.0.0 and
+// 1.2.0 from an OCI registry on the loopback interface, and returns its
+// host.
+func ociRegistry(t *testing.T) string {
+	t.Helper()
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	manifest, _ := json.Marshal(oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.ManifestMediaType,
+		ArtifactType:  oci.ArtifactType,
+		Config:        oci.Descriptor{MediaType: oci.EmptyConfigMediaType, Digest: oci.Digest([]byte("{}")), Size: 2},
+		Layers:        []oci.Descriptor{{MediaType: oci.LayerMediaType, Digest: oci.Digest(wasm), Size: int64(len(wasm))}},
+		Annotations:   map[string]string{oci.TypeAnnotation: "catalyst"},
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/acme/sentiment/tags/list":
+			w.Write([]byte(`{"name":"acme/sentiment","tags":["1.0.0","1.2.0","latest"]}`))
+		case "/v2/acme/sentiment/manifests/1.0.0", "/v2/acme/sentiment/manifests/1.2.0":
+			w.Write(manifest)
+		case "/v2/acme/sentiment/blobs/" + oci.Digest(wasm):
+			w.Write(wasm)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestPull_OCIRegistry(t *testing.T) {
+	host := ociRegistry(t)
+	dir := chdirTemp(t)
+
+	out := runCLI(t, "--json", "pull", host+"/acme/sentiment:1.0.0@"+mockDigest)
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	want := filepath.Join("components", "catalysts", "acme", "sentiment", "1.0.0", "catalyst.wasm")
+	if result["path"] != want || result["verified"] != true || result["digest"] != mockDigest {
+		t.Errorf("result = %v, want path %s, verified, digest %s", result, want, mockDigest)
+	}
+	if _, err := os.Stat(filepath.Join(dir, want)); err != nil {
+		t.Errorf("artifact not written: %v", err)
+	}
+}
+
+func TestResolveConstraint_OCITags(t *testing.T) {
+	host := ociRegistry(t)
+	client, _ := newClient()
+	got, _ := resolveConstraint(context.Background(), client, "c:"+host+"/acme/sentiment:^1.0")
+	if want := "catalyst:" + host + "/acme/sentiment:1.2.0"; got != want {
+		t.Errorf("resolveConstraint = %q, want %q", got, want)
+	}
+}
+
+func TestParseReference_OCI(t *testing.T) {
+	tests := []struct {
+		raw, compType, want string
+	}{
+		{"ghcr.io/acme/sentiment:1.0.0", "", "ghcr.io/acme/sentiment:1.0.0"},
+		{"ghcr.io/acme/sentiment@1.0.0", "reagent", "reagent:ghcr.io/acme/sentiment:1.0.0"},
+		{"c:localhost:5000/acme/sentiment:1.0.0", "", "c:localhost:5000/acme/sentiment:1.0.0"},
+	}
+	for _, tt := range tests {
+		got, _ := parseReference(tt.raw, tt.compType)
+		if got["oci"] != tt.want || got["registry"] != nil {
+			t.Errorf("parseReference(%q, %q) = %v, want oci %q", tt.raw, tt.compType, got, tt.want)
+		}
+	}
+}
+
+func TestOCINamespace(t *testing.T) {
+	host := "registry.corp"
+	tests := map[string]string{
+		host + "/catalysts/foo:2.1.0":      "local",
+		host + "/acme/sentiment:1.0.0":     "acme",
+		host + "/acme/catalysts/foo:1.0.0": "acme",
+		host + "/foo:1.0.0":                "local",
+	}
+	for raw, want := range tests {
+		r, ok := registryRef(raw)
+		if !ok {
+			t.Fatalf("registryRef(%q) not a registry ref", raw)
+		}
+		if got := ociNamespace(r); got != want {
+			t.Errorf("ociNamespace(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestPull_OCILatest(t *testing.T) {
+	host := ociRegistry(t)
+	chdirTemp(t)
+
+	// The registry's "latest" tag has no manifest: the highest release
+	// tag must be pulled instead.
+	out := runCLI(t, "--json", "pull", "c:"+host+"/acme/sentiment")
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	if want := filepath.Join("components", "catalysts", "acme", "sentiment", "1.2.0", "catalyst.wasm"); result["path"] != want {
+		t.Errorf("path = %v, want %s", result["path"], want)
+	}
+}
+
+func TestInspectVersions_OCI(t *testing.T) {
+	host := ociRegistry(t)
+
+	out := runCLI(t, "--json", "inspect", "c:"+host+"/acme/sentiment", "--versions")
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	versions, _ := result["versions"].([]any)
+	if len(versions) != 3 || versions[0] != "latest" || versions[1] != "1.2.0" || versions[2] != "1.0.0" {
+		t.Errorf("versions = %v, want [latest 1.2.0 1.0.0]", versions)
+	}
+	if result["latest"] != "1.2.0" || result["reference"] != "catalyst:"+host+"/acme/sentiment" {
+		t.Errorf("result = %v", result)
+	}
+
+	out = runCLI(t, "inspect", "c:"+host+"/acme/sentiment", "--versions")
+	if !strings.Contains(out, "1.2.0  (latest)\n") {
+		t.Errorf("text output doesn't mark latest:\n%s", out)
+	}
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	componentOutdatedCmd.Flags().Bool("all", false, "List components that are up to date too")
+	componentCmd.AddCommand(componentOutdatedCmd)
+}
+
+var componentOutdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List components with newer published versions",
+	Long: `Compare the components in the project's components/ directory, and the exact versions local formulas pin in their dependencies, with the versions published to the registry, and list those for which a newer release is out. A component pulled in several versions is compared by its highest. Yanked versions and prereleases are never offered as newer, as "latest" doesn't resolve to them.
+
+--all lists the components that are up to date too, and those never published, with "-" as their latest version. Components whose versions can't be listed are shown with the reason, and make the command fail.`,
+	Example: `  cyfr component outdated
+  cyfr component outdated --all --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		return printOutdated(outdatedComponents(cmd.Context(), client), all)
+	},
+}
+
+// An outdatedEntry compares a version of a component the project uses
+// with the latest release in the registry.
+type outdatedEntry struct {
+	Component string `json:"component"`
+	Current   string `json:"current"`
+	Latest    string `json:"latest,omitempty"` // "" if it has no published release
+	// Where is components/ for a pulled component, or the formula that
+	// pins the version.
+	Where    string `json:"where"`
+	Outdated bool   `json:"outdated"`
+	Error    string `json:"error,omitempty"`
+}
+
+// outdatedComponents compares the highest version of each component in
+// components/, and each exact version a local formula's dependencies pin,
+// with the component's latest release, asking the registry about each
+// component once.
+func outdatedComponents(ctx context.Context, client *mcp.Client) []outdatedEntry {
+	var used []outdatedEntry
+	pulled := map[string]int{}
+	for _, c := range installedComponents("", "") {
+		name := componentName(c.Ref)
+		if i, ok := pulled[name]; ok {
+			if ref.Compare(c.Ref.Version, used[i].Current) > 0 {
+				used[i].Current = c.Ref.Version
+			}
+		} else {
+			pulled[name] = len(used)
+			used = append(used, outdatedEntry{Component: name, Current: c.Ref.Version, Where: "components/"})
+		}
+	}
+	for _, c := range installedComponents("formula", "") {
+		deps, _ := manifestDependencies(c.Dir)
+		for _, dep := range deps {
+			normalized, err := normalizeComponentRef(dep)
+			if err != nil {
+				continue
+			}
+			r, err := ref.Parse(normalized)
+			if err != nil || r.Digest != "" {
+				continue
+			}
+			if _, err := ref.ParseVersion(r.Version); err != nil {
+				continue
+			}
+			used = append(used, outdatedEntry{Component: componentName(r), Current: r.Version, Where: c.Ref.String()})
+		}
+	}
+
+	type lookup struct {
+		latest string
+		err    error
+	}
+	latest := map[string]lookup{}
+	for i := range used {
+		e := &used[i]
+		l, ok := latest[e.Component]
+		if !ok {
+			r, _ := ref.Parse(e.Component)
+			versions, err := availableVersions(ctx, client, r)
+			l.err = err
+			if err == nil {
+				l.latest, _ = ref.Latest(versions)
+			}
+			latest[e.Component] = l
+		}
+		switch {
+		case l.err != nil:
+			e.Error = l.err.Error()
+		case l.latest == "":
+			// Never published, such as a component built in the project.
+		default:
+			e.Latest = l.latest
+			e.Outdated = ref.Compare(l.latest, e.Current) > 0
+		}
+	}
+	sort.SliceStable(used, func(i, j int) bool { return used[i].Component < used[j].Component })
+	return used
+}
+
+// printOutdated lists the outdated components, or all of them with all,
+// and fails if any couldn't be compared.
+func printOutdated(entries []outdatedEntry, all bool) error {
+	var shown []outdatedEntry
+	outdated, failed := 0, 0
+	for _, e := range entries {
+		if e.Outdated {
+			outdated++
+		}
+		if e.Error != "" {
+			failed++
+		}
+		if all || e.Outdated || e.Error != "" {
+			shown = append(shown, e)
+		}
+	}
+	if flagJSON {
+		if shown == nil {
+			shown = []outdatedEntry{}
+		}
+		output.JSON(map[string]any{"components": shown, "outdated": outdated, "failed": failed})
+	} else if len(shown) == 0 {
+		fmt.Printf("All %d component(s) are up to date.\n", len(entries))
+	} else {
+		rows := make([]map[string]string, len(shown))
+		for i, e := range shown {
+			latest := e.Latest
+			if latest == "" {
+				latest = "-"
+			}
+			if e.Error != "" {
+				latest = "? (" + e.Error + ")"
+			}
+			rows[i] = map[string]string{"COMPONENT": e.Component, "CURRENT": e.Current, "LATEST": latest, "WHERE": e.Where}
+		}
+		output.Table([]string{"COMPONENT", "CURRENT", "LATEST", "WHERE"}, rows)
+		fmt.Printf("\n%d of %d component(s) outdated.\n", outdated, len(entries))
+	}
+	if failed > 0 {
+		return output.Errorf("Cannot check %d component(s) for updates", failed)
+	}
+	return nil
+}
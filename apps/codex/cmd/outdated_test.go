@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestOutdatedComponents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.search"] = map[string]any{"components": []any{
+		map[string]any{"name": "hello", "publisher": "local", "type": "reagent", "version": "0.1.0"},
+		map[string]any{"name": "hello", "publisher": "local", "type": "reagent", "version": "0.3.0"},
+		map[string]any{"name": "hello", "publisher": "local", "type": "reagent", "version": "0.4.0", "yanked": true},
+		map[string]any{"name": "hello", "publisher": "local", "type": "reagent", "version": "0.5.0-rc.1"},
+	}}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+
+	chdirTemp(t)
+	writeVersion(t, "reagent", "hello", "0.1.0", `{"type": "reagent"}`)
+	writeVersion(t, "reagent", "hello", "0.3.0", `{"type": "reagent"}`)
+	writeComponent(t, "formula", "top", `{"type": "formula", "dependencies": ["r:local.hello:0.1.0", "r:local.hello:^0.1"]}`, map[string]string{})
+
+	entries := outdatedComponents(context.Background(), mcp.NewClient(srv.URL))
+	var got []string
+	for _, e := range entries {
+		got = append(got, strings.Join([]string{e.Component, e.Current, e.Latest, e.Where}, " "))
+	}
+	want := []string{
+		"formula:local.top 0.1.0  components/",
+		"reagent:local.hello 0.3.0 0.3.0 components/",
+		"reagent:local.hello 0.1.0 0.3.0 formula:local.top:0.1.0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	flagJSON = false
+	var err error
+	out, _ := captureStdout(func() { err = printOutdated(entries, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "components/") || !strings.Contains(out, "1 of 3 component(s) outdated") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestOutdated_Failed(t *testing.T) {
+	entries := []outdatedEntry{{Component: "reagent:local.hello", Current: "0.1.0", Where: "components/", Error: "connection refused"}}
+	flagJSON = false
+	var err error
+	out, _ := captureStdout(func() { err = printOutdated(entries, false) })
+	if err == nil || !strings.Contains(out, "connection refused") {
+		t.Errorf("err = %v, output:\n%s", err, out)
+	}
+}
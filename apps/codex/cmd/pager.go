@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/cyfr/codex/internal/output"
+
+// pageOutput sends the rest of the command's output through the pager
+// setting ($PAGER, or less) when stdout is a terminal, unless --no-pager
+// was given. With less, output that fits on one screen is printed as is.
+// The pager is closed when the command finishes.
+func pageOutput() {
+	if flagNoPager {
+		return
+	}
+	output.StartPager(settingValue(loadConfigOrDefault(), "pager"))
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// addPaginationFlags registers --limit and --all on a list command.
+func addPaginationFlags(cmd *cobra.Command, defaultLimit int) {
+	cmd.Flags().Int("limit", defaultLimit, "Maximum number of results to fetch")
+	cmd.Flags().Bool("all", false, "Fetch every result, following pagination to the end")
+}
+
+// pageLimit returns the requested result limit, or 0 for --all.
+func pageLimit(cmd *cobra.Command) int {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return 0
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	return limit
+}
+
+// warnMoreResults tells the user on stderr when a paged result stopped at
+// the limit with more results left on the server, so a truncated list is
+// never mistaken for a complete one.
+func warnMoreResults(result map[string]any) {
+	next, _ := result["next_cursor"].(string)
+	if next == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Showing the first %v results; more are available. Use --limit or --all to see more.\n", result["count"])
+}
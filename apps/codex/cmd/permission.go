@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -28,20 +29,24 @@ var permGetCmd = &cobra.Command{
 	Long:    "Show the permissions currently assigned to a subject.",
 	Example: "  cyfr permission get user@example.com",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("permission", map[string]any{
-			"action":  "get",
-			"subject": args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Permission(cmd.Context(), typed.PermissionArgs{
+			Action:  typed.PermissionActionGet,
+			Subject: args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -52,21 +57,24 @@ var permSetCmd = &cobra.Command{
 	Example: `  cyfr permission set user@example.com read,write
   cyfr permission set pk_mykey execute`,
 	Args: cobra.MinimumNArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse comma-separated or space-separated permissions
 		var perms []string
 		for _, a := range args[1:] {
 			perms = append(perms, strings.Split(a, ",")...)
 		}
 
-		client := newClient()
-		result, err := client.CallTool("permission", map[string]any{
-			"action":      "set",
-			"subject":     args[0],
-			"permissions": perms,
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Permission(cmd.Context(), typed.PermissionArgs{
+			Action:      typed.PermissionActionSet,
+			Subject:     args[0],
+			Permissions: perms,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
@@ -74,6 +82,7 @@ var permSetCmd = &cobra.Command{
 			fmt.Printf("Permissions updated for '%s'.\n", args[0])
 		}
 		_ = result
+		return nil
 	},
 }
 
@@ -82,18 +91,22 @@ var permListCmd = &cobra.Command{
 	Short:   "List all permission entries",
 	Long:    "List every subject and its assigned permissions.",
 	Example: "  cyfr permission list",
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("permission", map[string]any{
-			"action": "list",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Permission(cmd.Context(), typed.PermissionArgs{
+			Action: typed.PermissionActionList,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	contextCmd.AddCommand(contextPingCmd)
+}
+
+var contextPingCmd = &cobra.Command{
+	Use:   "ping [name...]",
+	Short: "Test connectivity of contexts",
+	Long: `Check every configured context, or the named ones, at once: its
+/api/health endpoint, an MCP initialize handshake, and whether its stored
+credentials are still accepted. Prints reachability, health-check latency,
+server version and auth status per context, and exits non-zero if any
+context is unreachable.`,
+	Example: `  cyfr context ping
+  cyfr context ping staging production
+  cyfr context ping --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := statusContext(cmd)
+		defer cancel()
+
+		cfg := loadConfigOrDefault()
+		names := args
+		if len(names) == 0 {
+			for name := range cfg.Contexts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+		for _, name := range names {
+			if _, ok := cfg.Contexts[name]; !ok {
+				return output.NewError(output.CodeNotFound, "Context '%s' not found. Use 'cyfr context list' to see configured contexts.", name)
+			}
+		}
+
+		results := pingContexts(ctx, cfg, names)
+		if flagJSON {
+			out := make([]map[string]any, len(results))
+			for i, r := range results {
+				out[i] = r.JSON()
+			}
+			output.JSON(map[string]any{"contexts": out, "count": len(out)})
+		} else {
+			rows := make([]map[string]string, len(results))
+			for i, r := range results {
+				rows[i] = r.Row()
+			}
+			output.Table([]string{"CONTEXT", "URL", "REACHABLE", "LATENCY", "SERVER", "AUTH"}, rows)
+		}
+
+		for _, r := range results {
+			if !r.Reachable {
+				return output.ExitError(1)
+			}
+		}
+		return nil
+	},
+}
+
+// pingResult is what context ping found out about one context.
+type pingResult struct {
+	Context   string
+	URL       string
+	Reachable bool
+	Latency   time.Duration
+	Server    *mcp.InitializeResult
+	Auth      string
+	Err       error
+}
+
+// JSON returns the result for --json output.
+func (r pingResult) JSON() map[string]any {
+	out := map[string]any{
+		"context":   r.Context,
+		"url":       r.URL,
+		"reachable": r.Reachable,
+		"auth":      r.Auth,
+	}
+	if r.Reachable {
+		out["latency_ms"] = r.Latency.Milliseconds()
+	}
+	if r.Server != nil {
+		out["server"] = serverState(r.Server)
+	}
+	if r.Err != nil {
+		out["error"] = r.Err.Error()
+	}
+	return out
+}
+
+// Row returns the result as a table row.
+func (r pingResult) Row() map[string]string {
+	row := map[string]string{
+		"CONTEXT":   r.Context,
+		"URL":       r.URL,
+		"REACHABLE": "no",
+		"LATENCY":   "-",
+		"SERVER":    "-",
+		"AUTH":      r.Auth,
+	}
+	if r.Reachable {
+		row["REACHABLE"] = "yes"
+		row["LATENCY"] = formatLatency(r.Latency)
+	} else if r.Err != nil {
+		row["REACHABLE"] = "no (" + r.Err.Error() + ")"
+	}
+	if r.Server != nil && r.Server.ServerInfo != nil {
+		row["SERVER"] = strings.TrimSpace(r.Server.ServerInfo.Name + " " + r.Server.ServerInfo.Version)
+	}
+	return row
+}
+
+// pingContexts pings the named contexts concurrently and returns the
+// results in the order of names.
+func pingContexts(ctx context.Context, cfg *config.Config, names []string) []pingResult {
+	results := make([]pingResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = pingContext(ctx, cfg, name)
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// pingContext checks one context's health endpoint, MCP handshake and
+// credentials. The handshake uses a client of its own, since initializing
+// replaces the session the credentials check needs.
+func pingContext(ctx context.Context, cfg *config.Config, name string) pingResult {
+	r := pingResult{Context: name, URL: cfg.Contexts[name].URL, Auth: "-"}
+
+	client, err := clientForContext(cfg, name)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	client.OnRateLimit = nil
+	start := time.Now()
+	if err := client.Health(ctx); err != nil {
+		r.Err = err
+		return r
+	}
+	r.Reachable = true
+	r.Latency = time.Since(start)
+
+	if err := client.InitializeCtx(ctx); err != nil {
+		r.Err = err
+	} else {
+		r.Server = client.Server
+	}
+	r.Auth = pingAuth(ctx, cfg, name)
+	return r
+}
+
+// pingAuth checks whether a context's stored credentials are accepted,
+// describing how it authenticates and whether that still works.
+func pingAuth(ctx context.Context, cfg *config.Config, name string) string {
+	state := authState(cfg.Contexts[name])
+	if state == "none" {
+		return "not logged in"
+	}
+	client, err := clientForContext(cfg, name)
+	if err != nil {
+		return fmt.Sprintf("%s: unverified (%v)", state, err)
+	}
+	client.OnRateLimit = nil
+	who, err := typed.New(client).Session(ctx, typed.SessionArgs{Action: typed.SessionActionWhoami})
+	switch {
+	case errors.Is(err, mcp.ErrSessionExpired):
+		return state + ": expired"
+	case errors.Is(err, mcp.ErrTokenExpired):
+		return state + ": expired"
+	case errors.Is(err, mcp.ErrSessionRequired):
+		return state + ": rejected"
+	case err != nil:
+		return fmt.Sprintf("%s: unverified (%v)", state, err)
+	}
+	for _, key := range []string{"email", "user_id"} {
+		if user, _ := who[key].(string); user != "" {
+			return state + ": " + user
+		}
+	}
+	return state + ": ok"
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +15,7 @@ func init() {
 	policyCmd.AddCommand(policyShowCmd)
 	policyCmd.AddCommand(policyResetCmd)
 	policyCmd.AddCommand(policyListCmd)
+	addColumnsFlag(policyListCmd)
 }
 
 var policyCmd = &cobra.Command{
@@ -30,51 +32,67 @@ var policySetCmd = &cobra.Command{
 	Example: `  cyfr policy set c:local.claude:0.1.0 allowed_domains '["api.anthropic.com"]'
   cyfr policy set acme.sentiment:1.0.0 rate_limit 100`,
 	Args: cobra.RangeArgs(3, 4),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		componentRef := normalizeComponentRef(args[0])
+		componentRef, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
 		field := args[1]
 		value := args[2]
 
-		client := newClient()
-		result, err := client.CallTool("policy", map[string]any{
-			"action":        "update_field",
-			"component_ref": componentRef,
-			"field":         field,
-			"value":         value,
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Policy(cmd.Context(), typed.PolicyArgs{
+			Action:       typed.PolicyActionUpdateField,
+			ComponentRef: componentRef,
+			Field:        field,
+			Value:        value,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Printf("Policy field '%s' updated for %s.\n", field, componentRef)
 		}
+		return nil
 	},
 }
 
 var policyShowCmd = &cobra.Command{
-	Use:     "show [type] <component_ref>",
-	Short:   "Show policy for a component",
-	Long:    "Display the full policy document for a component in a human-readable format.",
+	Use:   "show [type] <component_ref>...",
+	Short: "Show policy for a component",
+	Long:  "Display the full policy document for one or more components in a human-readable format. Components may be given as separate arguments or comma-separated; duplicates are shown once.",
 	Example: `  cyfr policy show c:local.claude:0.1.0
-  cyfr policy show acme.sentiment:1.0.0`,
-	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
-		args = joinTypeShorthand(args)
-		componentRef := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("policy", map[string]any{
-			"action":        "get",
-			"component_ref": componentRef,
-		})
+  cyfr policy show acme.sentiment:1.0.0
+  cyfr policy show c:local.claude:0.1.0,c:local.openai:0.2.0`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := componentRefArgs(args)
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		results := make([]map[string]any, len(refs))
+		for i, componentRef := range refs {
+			result, err := client.Policy(cmd.Context(), typed.PolicyArgs{
+				Action:       typed.PolicyActionGet,
+				ComponentRef: componentRef,
+			})
+			if err != nil {
+				return output.Errorf("Failed: %v", err)
+			}
+			results[i] = result
+		}
+		pageOutput()
+		printRefResults(refs, results, func(componentRef string, result map[string]any) {
 			// Pretty-print the policy
 			if policy, ok := result["policy"]; ok {
 				policyJSON, _ := json.MarshalIndent(policy, "", "  ")
@@ -82,7 +100,8 @@ var policyShowCmd = &cobra.Command{
 			} else {
 				output.KeyValue(result)
 			}
-		}
+		})
+		return nil
 	},
 }
 
@@ -93,16 +112,22 @@ var policyResetCmd = &cobra.Command{
 	Example: `  cyfr policy reset c:local.claude:0.1.0
   cyfr policy reset acme.sentiment:1.0.0`,
 	Args: cobra.RangeArgs(1, 2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		componentRef := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("policy", map[string]any{
-			"action":        "delete",
-			"component_ref": componentRef,
+		componentRef, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Policy(cmd.Context(), typed.PolicyArgs{
+			Action:       typed.PolicyActionDelete,
+			ComponentRef: componentRef,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
@@ -110,6 +135,7 @@ var policyResetCmd = &cobra.Command{
 			fmt.Printf("Policy reset for %s.\n", componentRef)
 		}
 		_ = result
+		return nil
 	},
 }
 
@@ -118,18 +144,17 @@ var policyListCmd = &cobra.Command{
 	Short:   "List all policies",
 	Long:    "List all components that have custom policies applied.",
 	Example: "  cyfr policy list",
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("policy", map[string]any{
-			"action": "list",
-		})
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		result, err := client.Policy(cmd.Context(), typed.PolicyArgs{
+			Action: typed.PolicyActionList,
+		})
+		if err != nil {
+			return output.Errorf("Failed: %v", err)
 		}
+		return printList(cmd, result, "policies")
 	},
 }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// projectCache remembers the project config found from a working
+// directory, so it is read (and warned about) once per command.
+var projectCache struct {
+	mu  sync.Mutex
+	dir string
+	p   *config.Project
+}
+
+// cyfrYAMLValue returns the value of a top-level key in the cyfr.yaml of
+// the working directory, or "" if it has none or there is no cyfr.yaml.
+func cyfrYAMLValue(key string) string {
+	data, err := os.ReadFile("cyfr.yaml")
+	if err != nil {
+		return ""
+	}
+	m := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `:[ \t]*(.*?)[ \t]*\r?$`).FindSubmatch(data)
+	if m == nil {
+		return ""
+	}
+	value := string(m[1])
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return value
+}
+
+// projectConfig returns the config of the project the working directory
+// is in, or nil if it isn't in one. A config that can't be read is warned
+// about and ignored.
+func projectConfig() *config.Project {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	projectCache.mu.Lock()
+	defer projectCache.mu.Unlock()
+	if projectCache.dir == dir {
+		return projectCache.p
+	}
+	p, err := config.FindProject(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring project config: %v\n", err)
+		p = nil
+	}
+	projectCache.dir, projectCache.p = dir, p
+	return p
+}
+
+// defaultComponentType returns the default component type for references
+// without one: the project's, or else the active context's, or "" if
+// neither has one.
+func defaultComponentType() string {
+	p := projectConfig()
+	if p == nil || p.DefaultType == "" {
+		return contextDefaultType()
+	}
+	if !ref.IsTypePrefix(p.DefaultType) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid default_type %q in %s\n", p.DefaultType, p.Path)
+		return contextDefaultType()
+	}
+	return p.DefaultType
+}
+
+// withDefaultType prefixes a reference that has no type with typ, or with
+// the default type of the project or context if typ is empty.
+func withDefaultType(s, typ string) string {
+	if s == "" || hasTypePrefix(s) {
+		return s
+	}
+	if typ == "" {
+		typ = defaultComponentType()
+	}
+	if typ == "" {
+		return s
+	}
+	return typ + ":" + s
+}
+
+// applyProjectOutput sets --json for commands run in a project with an
+// output format; the caller checks --json wasn't given explicitly.
+func applyProjectOutput() {
+	p := projectConfig()
+	if p == nil {
+		return
+	}
+	switch p.Output {
+	case "":
+	case "text":
+		flagJSON = false
+	case "json":
+		flagJSON = true
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid output %q in %s\n", p.Output, p.Path)
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+// writeProjectConfig writes a project config in dir.
+func writeProjectConfig(t *testing.T, dir, data string) {
+	t.Helper()
+	path := filepath.Join(dir, config.ProjectFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	projectCache.dir = ""
+}
+
+func TestNewClient_ProjectConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{CurrentContext: "local", Contexts: map[string]*config.Context{
+		"local":   {URL: "http://localhost:4000"},
+		"staging": {URL: "https://staging.example.com", APIKey: "cyfr_sk_staging"},
+		"prod":    {URL: "https://prod.example.com"},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	dir := chdirTemp(t)
+	writeProjectConfig(t, dir, `{"context": "staging"}`)
+	sub := filepath.Join(dir, "src")
+	if err := os.Mkdir(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(sub); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { flagContext, flagURL = "", "" })
+	t.Setenv(envContext, "")
+	t.Setenv(envURL, "")
+
+	client, _ := newClient()
+	if client.BaseURL != "https://staging.example.com" || client.APIKey != "cyfr_sk_staging" {
+		t.Errorf("project context not used: %s, key %q", client.BaseURL, client.APIKey)
+	}
+
+	t.Setenv(envContext, "prod")
+	if client, _ := newClient(); client.BaseURL != "https://prod.example.com" {
+		t.Errorf("CYFR_CONTEXT should win over the project config, got %s", client.BaseURL)
+	}
+	t.Setenv(envContext, "")
+
+	writeProjectConfig(t, dir, `{"context": "staging", "url": "https://project.example.com"}`)
+	if client, _ := newClient(); client.BaseURL != "https://project.example.com" || client.APIKey != "cyfr_sk_staging" {
+		t.Errorf("project url not used: %s, key %q", client.BaseURL, client.APIKey)
+	}
+	flagURL = "https://flag.example.com"
+	if client, _ := newClient(); client.BaseURL != "https://flag.example.com" {
+		t.Errorf("--url should win over the project config, got %s", client.BaseURL)
+	}
+}
+
+func TestProjectConfig_DefaultType(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	writeProjectConfig(t, chdirTemp(t), `{"default_type": "catalyst"}`)
+
+	if got, _ := normalizeComponentRef("acme.sentiment@1.0.0"); got != "catalyst:acme.sentiment:1.0.0" {
+		t.Errorf("normalizeComponentRef = %q", got)
+	}
+	if got, _ := normalizeComponentRef("r:acme.parser:1.0.0"); got != "r:acme.parser:1.0.0" {
+		t.Errorf("typed reference changed: %q", got)
+	}
+	got, _ := componentRefArgs([]string{"r", "acme.parser:1.0.0"})
+	if len(got) != 1 || got[0] != "reagent:acme.parser:1.0.0" {
+		t.Errorf("leading type should win over the default type, got %v", got)
+	}
+	if ref, _ := parseReference("acme.parser:1.0.0", "reagent"); ref["registry"] != "reagent:acme.parser:1.0.0" {
+		t.Errorf("--type should win over the default type, got %v", ref)
+	}
+	if ref, _ := parseReference("acme.sentiment:1.0.0", ""); ref["registry"] != "catalyst:acme.sentiment:1.0.0" {
+		t.Errorf("default type not applied, got %v", ref)
+	}
+}
+
+func TestProjectConfig_OutputJSON(t *testing.T) {
+	writeProjectConfig(t, chdirTemp(t), `{"output": "json"}`)
+
+	out := runCLI(t, "status")
+	if !strings.HasPrefix(strings.TrimSpace(out), "{") {
+		t.Errorf("expected JSON output, got:\n%s", out)
+	}
+	out = runCLI(t, "status", "--json=false")
+	if strings.HasPrefix(strings.TrimSpace(out), "{") {
+		t.Errorf("--json=false should win over the project config, got:\n%s", out)
+	}
+}
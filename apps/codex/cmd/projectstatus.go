@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+// componentTypes are the types of component a project's components
+// directory holds, in the order they are reported.
+var componentTypes = []string{"catalyst", "reagent", "formula"}
+
+// projectStatus is the overview 'cyfr status --project' prints of the
+// project in the working directory.
+type projectStatus struct {
+	Name       string
+	Dir        string
+	Runtime    string // docker, podman, ..., native, or "" if none is found
+	Running    bool
+	Healthy    bool
+	URL        string
+	Scaffold   string // the recorded scaffold version, or ""
+	Components map[string]int
+	RuntimeErr error
+	Checked    bool               // whether the server was asked for Pending
+	Pending    []ref.ComponentRef // local components the server doesn't know
+	PendingErr error              // why Pending couldn't be checked
+}
+
+// collectProjectStatus gathers the status of the project in the working
+// directory, asking its server, if it is up, which local components it
+// doesn't have yet.
+func collectProjectStatus(ctx context.Context) (*projectStatus, error) {
+	if _, err := os.Stat("cyfr.yaml"); err != nil {
+		return nil, output.NewError(output.CodeNotFound, "Not in a cyfr project directory (no cyfr.yaml found); 'cyfr init' creates one")
+	}
+	s := &projectStatus{
+		Name:       cyfrYAMLValue("name"),
+		Scaffold:   projectScaffoldVersion(),
+		Components: map[string]int{},
+		URL:        fmt.Sprintf("http://localhost:%d", projectPort()),
+	}
+	s.Dir, _ = os.Getwd()
+
+	if _, ok := nativePID(); ok {
+		s.Runtime, s.Running = "native", true
+	} else if runtime, err := projectRuntime(); err != nil {
+		s.RuntimeErr = err
+	} else {
+		s.Runtime, s.Running = runtime.Name, runtime.running()
+	}
+	if s.Running {
+		s.Healthy = checkHealth(ctx, s.URL)
+	}
+
+	refs := localComponentRefs()
+	for _, r := range refs {
+		s.Components[r.Type]++
+	}
+	if s.Healthy && len(refs) > 0 {
+		s.Pending, s.PendingErr = unregisteredComponents(ctx, s.URL, refs)
+		s.Checked = true
+	}
+	return s, nil
+}
+
+// unregisteredComponents returns the refs the server at url can't find,
+// using the credentials of a context for it if there is one.
+func unregisteredComponents(ctx context.Context, url string, refs []ref.ComponentRef) ([]ref.ComponentRef, error) {
+	client := mcp.NewClient(url)
+	cfg := loadConfigOrDefault()
+	if name := contextWithURL(cfg, url); name != "" {
+		if c, err := clientForContext(cfg, name); err == nil {
+			client = c
+		}
+	}
+	ctx, cancel := mcp.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var pending []ref.ComponentRef
+	for _, r := range refs {
+		registered, err := componentRegistered(ctx, client, r)
+		if err != nil {
+			return nil, err
+		}
+		if !registered {
+			pending = append(pending, r)
+		}
+	}
+	return pending, nil
+}
+
+// componentRegistered reports whether the server of client has the
+// component r.
+func componentRegistered(ctx context.Context, client *mcp.Client, r ref.ComponentRef) (bool, error) {
+	_, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionInspect,
+		Reference: r.String(),
+	})
+	switch {
+	case err == nil:
+		return true, nil
+	case strings.Contains(strings.ToLower(err.Error()), "not found"):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// state describes the server, e.g. "running (docker), healthy".
+func (s *projectStatus) state() string {
+	switch {
+	case s.RuntimeErr != nil:
+		return fmt.Sprintf("unknown (%v)", s.RuntimeErr)
+	case !s.Running:
+		return fmt.Sprintf("stopped (%s); 'cyfr up' starts it", s.Runtime)
+	case s.Healthy:
+		return fmt.Sprintf("running (%s), healthy at %s", s.Runtime, s.URL)
+	default:
+		return fmt.Sprintf("running (%s), not answering at %s; see 'cyfr logs --server'", s.Runtime, s.URL)
+	}
+}
+
+// scaffoldState describes the scaffold version against the CLI's.
+func (s *projectStatus) scaffoldState() string {
+	cli := strings.TrimPrefix(Version, "v")
+	switch {
+	case s.Scaffold == "":
+		return "unknown; 'cyfr upgrade' records it"
+	case cli == "dev" || cli == "":
+		return "v" + s.Scaffold
+	case s.Scaffold == cli:
+		return "v" + s.Scaffold + ", same as the CLI"
+	default:
+		return fmt.Sprintf("v%s, CLI is v%s; 'cyfr upgrade' updates it", s.Scaffold, cli)
+	}
+}
+
+// componentCounts describes the local components, e.g. "2 catalysts,
+// 0 reagents, 1 formula".
+func (s *projectStatus) componentCounts() string {
+	parts := make([]string, len(componentTypes))
+	for i, typ := range componentTypes {
+		n := s.Components[typ]
+		if n == 1 {
+			parts[i] = fmt.Sprintf("1 %s", typ)
+		} else {
+			parts[i] = fmt.Sprintf("%d %ss", n, typ)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pendingState describes the local components not registered yet.
+func (s *projectStatus) pendingState() string {
+	switch {
+	case s.PendingErr != nil:
+		return fmt.Sprintf("unknown (%v)", s.PendingErr)
+	case !s.Checked && s.Healthy:
+		return "none"
+	case !s.Checked:
+		return "unknown (the server isn't up)"
+	case len(s.Pending) == 0:
+		return "none"
+	}
+	refs := make([]string, len(s.Pending))
+	for i, r := range s.Pending {
+		refs[i] = r.String()
+	}
+	return fmt.Sprintf("%d not registered: %s; 'cyfr register <directory>' registers one", len(refs), strings.Join(refs, ", "))
+}
+
+// JSON returns the status for --json.
+func (s *projectStatus) JSON() map[string]any {
+	server := map[string]any{
+		"runtime": s.Runtime,
+		"running": s.Running,
+		"healthy": s.Healthy,
+		"url":     s.URL,
+	}
+	if s.RuntimeErr != nil {
+		server["error"] = s.RuntimeErr.Error()
+	}
+	components := map[string]any{}
+	for _, typ := range componentTypes {
+		components[typ] = s.Components[typ]
+	}
+	result := map[string]any{
+		"project":    s.Name,
+		"directory":  s.Dir,
+		"server":     server,
+		"scaffold":   s.Scaffold,
+		"cli":        strings.TrimPrefix(Version, "v"),
+		"components": components,
+	}
+	if s.Checked && s.PendingErr == nil {
+		pending := make([]string, len(s.Pending))
+		for i, r := range s.Pending {
+			pending[i] = r.String()
+		}
+		result["pending"] = pending
+	} else if s.PendingErr != nil {
+		result["pending_error"] = s.PendingErr.Error()
+	}
+	return result
+}
+
+// print prints the status as one screen of text.
+func (s *projectStatus) print() {
+	fmt.Printf("Project:    %s (%s)\n", s.Name, s.Dir)
+	fmt.Printf("Server:     %s\n", s.state())
+	fmt.Printf("Scaffold:   %s\n", s.scaffoldState())
+	fmt.Printf("Components: %s\n", s.componentCounts())
+	fmt.Printf("Pending:    %s\n", s.pendingState())
+}
+
+// runProjectStatus is 'cyfr status --project'.
+func runProjectStatus(cmd *cobra.Command) error {
+	return watch(cmd, func() error {
+		s, err := collectProjectStatus(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(s.JSON())
+		} else {
+			s.print()
+		}
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestProjectStatusNotAProject(t *testing.T) {
+	chdirTemp(t)
+	_, err := collectProjectStatus(context.Background())
+	if code := output.Code(err); code != output.CodeNotFound {
+		t.Errorf("error = %v (code %q), want not found", err, code)
+	}
+}
+
+func TestProjectStatusOffline(t *testing.T) {
+	// A docker whose compose project has no containers running.
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	chdirTemp(t)
+	opts := defaultInitOptions()
+	os.WriteFile("cyfr.yaml", []byte(opts.cyfrYAML()), 0644)
+	for _, dir := range []string{
+		"components/catalysts/local/a/0.1.0",
+		"components/catalysts/local/b/0.1.0",
+		"components/formulas/local/c/1.0.0",
+	} {
+		os.MkdirAll(dir, 0755)
+		typ := strings.TrimSuffix(strings.Split(dir, "/")[1], "s")
+		os.WriteFile(filepath.Join(dir, typ+".wasm"), nil, 0644)
+	}
+	if err := recordScaffoldVersion("v1.2.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := collectProjectStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != opts.Name || s.Runtime != "docker" || s.Running || s.Checked {
+		t.Errorf("status = %+v", s)
+	}
+	if got := s.componentCounts(); got != "2 catalysts, 0 reagents, 1 formula" {
+		t.Errorf("components = %q", got)
+	}
+	if s.Scaffold != "1.2.0" {
+		t.Errorf("scaffold = %q", s.Scaffold)
+	}
+	if got := s.pendingState(); !strings.Contains(got, "server isn't up") {
+		t.Errorf("pending = %q", got)
+	}
+}
+
+func TestUnregisteredComponents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Errors = map[string]string{"component.inspect": "Component not found"}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+
+	chdirTemp(t)
+	os.MkdirAll("components/reagents/local/r/0.1.0", 0755)
+	os.WriteFile("components/reagents/local/r/0.1.0/reagent.wasm", nil, 0644)
+	refs := localComponentRefs()
+	pending, err := unregisteredComponents(context.Background(), srv.URL, refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].String() != refs[0].String() {
+		t.Errorf("pending = %v", pending)
+	}
+
+	f.Errors = map[string]string{"component.inspect": "permission denied"}
+	if _, err := unregisteredComponents(context.Background(), srv.URL, refs); err == nil {
+		t.Error("expected an error other than not found to be returned")
+	}
+}
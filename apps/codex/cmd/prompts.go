@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(promptsCmd)
+	promptsCmd.AddCommand(promptsListCmd)
+	promptsCmd.AddCommand(promptsGetCmd)
+
+	promptsGetCmd.Flags().StringToString("arg", nil, "Prompt argument as key=value (repeatable)")
+}
+
+var promptsCmd = &cobra.Command{
+	Use:     "prompts",
+	Short:   "Browse server prompt templates",
+	GroupID: "advanced",
+	Long:    "List the reusable prompt templates a CYFR server offers to agents, and render one with arguments to see exactly what an agent would receive.",
+}
+
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompt templates",
+	Long:  "List the server's prompt templates and the arguments each accepts. Required arguments are marked with an asterisk (*).",
+	Example: `  cyfr prompts list
+  cyfr prompts list --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		prompts, err := client.ListPromptsCtx(cmd.Context())
+		if err != nil {
+			return toolError(err)
+		}
+
+		if flagJSON {
+			if prompts == nil {
+				prompts = []mcp.Prompt{}
+			}
+			output.JSON(map[string]any{"prompts": prompts})
+			return nil
+		}
+
+		if len(prompts) == 0 {
+			fmt.Println("No prompts available.")
+			return nil
+		}
+		headers := []string{"NAME", "ARGUMENTS", "DESCRIPTION"}
+		rows := make([]map[string]string, len(prompts))
+		for i, p := range prompts {
+			rows[i] = map[string]string{
+				"NAME":        p.Name,
+				"ARGUMENTS":   promptArgumentList(p.Arguments),
+				"DESCRIPTION": p.Description,
+			}
+		}
+		output.Table(headers, rows)
+		return nil
+	},
+}
+
+var promptsGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Render a prompt template",
+	Long:  "Render a prompt template with the given arguments and print the resulting messages.",
+	Example: `  cyfr prompts get build-catalyst
+  cyfr prompts get build-catalyst --arg language=go --arg name=weather`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		promptArgs, _ := cmd.Flags().GetStringToString("arg")
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.GetPromptCtx(cmd.Context(), args[0], promptArgs)
+		if err != nil {
+			return toolError(err)
+		}
+
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+
+		if result.Description != "" {
+			fmt.Println(result.Description)
+			fmt.Println("")
+		}
+		for i, m := range result.Messages {
+			if i > 0 {
+				fmt.Println("")
+			}
+			fmt.Printf("[%s]\n", m.Role)
+			if m.Content.Type == "text" {
+				fmt.Println(m.Content.Text)
+			} else {
+				fmt.Printf("(%s content)\n", m.Content.Type)
+			}
+		}
+		return nil
+	},
+}
+
+// promptArgumentList renders prompt arguments as "a*, b", marking required
+// ones with an asterisk.
+func promptArgumentList(args []mcp.PromptArgument) string {
+	names := make([]string, len(args))
+	for i, a := range args {
+		names[i] = a.Name
+		if a.Required {
+			names[i] += "*"
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	psCmd.Flags().BoolP("all", "a", false, "Show all executions, not just running ones")
+	psCmd.Flags().Int("limit", 20, "Maximum executions to show")
+	addMultiContextFlags(psCmd)
+	addWatchFlag(psCmd)
+	rootCmd.AddCommand(psCmd)
+}
+
+var psCmd = &cobra.Command{
+	Use:     "ps",
+	Short:   "List executions",
+	GroupID: "exec",
+	Long:    "List running executions as a table. Use --all to include completed, failed, and cancelled executions. With --contexts or --all-contexts, executions from several servers are listed side by side with a CONTEXT column. With --watch, the list is refreshed every interval (2s by default) until interrupted, and on a terminal new lines are highlighted.",
+	Example: `  cyfr ps
+  cyfr ps --all --limit 50
+  cyfr ps --all-contexts
+  cyfr ps --contexts local,staging
+  cyfr ps --watch
+  cyfr ps --watch=5s`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		toolArgs := typed.ExecutionArgs{
+			Action: typed.ExecutionActionList,
+			Limit:  limit,
+		}
+		if !all {
+			toolArgs.Status = "running"
+		}
+
+		headers := []string{"EXECUTION_ID", "STATUS", "REFERENCE", "STARTED", "DURATION"}
+
+		names, err := selectedContexts(cmd)
+		if err != nil {
+			return err
+		}
+		if names != nil {
+			return watch(cmd, func() error {
+				results := callAcrossContexts(cmd.Context(), names, "execution", toolArgs.Map())
+				if flagQuiet {
+					for _, r := range results {
+						executions, _ := r.Result["executions"].([]any)
+						printIDs(executions, "execution_id")
+					}
+					warnContextErrors(results)
+					return contextErrors(results)
+				}
+				if flagJSON {
+					output.JSON(contextResultsJSON(results))
+					return contextErrors(results)
+				}
+				var rows []map[string]string
+				for _, r := range results {
+					if r.Err != nil {
+						continue
+					}
+					for _, row := range executionRows(r.Result) {
+						row["CONTEXT"] = r.Context
+						rows = append(rows, row)
+					}
+				}
+				output.Table(append([]string{"CONTEXT"}, headers...), rows)
+				warnContextErrors(results)
+				return contextErrors(results)
+			})
+		}
+
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		return watch(cmd, func() error {
+			result, err := client.Execution(cmd.Context(), toolArgs)
+			if err != nil {
+				return toolError(err)
+			}
+			if flagQuiet {
+				executions, _ := result["executions"].([]any)
+				printIDs(executions, "execution_id")
+				return nil
+			}
+			if flagJSON {
+				output.JSON(result)
+				return nil
+			}
+			output.Table(headers, executionRows(result))
+			return nil
+		})
+	},
+}
+
+// executionRows converts an execution list result into table rows.
+func executionRows(result map[string]any) []map[string]string {
+	executions, _ := result["executions"].([]any)
+	rows := make([]map[string]string, 0, len(executions))
+	for _, e := range executions {
+		exec, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		refMap, _ := exec["reference"].(map[string]any)
+		duration := ""
+		if ms, ok := exec["duration_ms"].(float64); ok {
+			duration = fmt.Sprintf("%.0fms", ms)
+		}
+		rows = append(rows, map[string]string{
+			"EXECUTION_ID": stringField(exec, "execution_id"),
+			"STATUS":       stringField(exec, "status"),
+			"REFERENCE":    referenceString(refMap),
+			"STARTED":      stringField(exec, "started_at"),
+			"DURATION":     duration,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// bumpParts are the values of publish's --bump.
+var bumpParts = []string{"major", "minor", "patch"}
+
+// componentDir returns the directory of r in the project's components/
+// layout.
+func componentDir(r ref.ComponentRef) string {
+	return filepath.Join("components", r.Type+"s", r.Namespace, r.Name, r.Version)
+}
+
+// localVersions lists the versions of r's component under components/.
+func localVersions(r ref.ComponentRef) []string {
+	dirs, _ := filepath.Glob(filepath.Join("components", r.Type+"s", r.Namespace, r.Name, "*"))
+	var versions []string
+	for _, dir := range dirs {
+		if isDir(dir) {
+			versions = append(versions, filepath.Base(dir))
+		}
+	}
+	return versions
+}
+
+// bumpVersion works out the version bumpComponent would create for r:
+// the version after the highest release published or local, with part
+// incremented. It returns the local version it would be copied from, which
+// is r's or, if r has no exact version, its highest local one, and the new
+// version.
+func bumpVersion(ctx context.Context, client *mcp.Client, r ref.ComponentRef, part string) (from, to ref.ComponentRef, err error) {
+	if r.Type == "" {
+		return r, r, output.NewError(output.CodeInvalidArgument, "Cannot bump %s: add a type prefix, e.g. c:%s", r, r)
+	}
+	local := localVersions(r)
+	if r.Version == "latest" {
+		latest, ok := ref.Latest(local)
+		if !ok {
+			return r, r, output.NewError(output.CodeNotFound, "Cannot bump %s: no released version in %s", r, filepath.Dir(componentDir(r)))
+		}
+		r.Version = latest
+	} else if r.HasConstraint() {
+		return r, r, output.NewError(output.CodeInvalidArgument, "Cannot bump %s: give the exact version to start from, or none for the highest", r)
+	}
+	if src := componentDir(r); !isDir(src) {
+		return r, r, output.NewError(output.CodeNotFound, "Cannot bump %s: %s doesn't exist", r, src)
+	}
+
+	// Yanked versions can't be published again, so they count too.
+	published, yanked, err := publishedVersions(ctx, client, r)
+	if err != nil {
+		return r, r, output.Errorf("Cannot bump %s: listing its published versions failed: %v", r, err)
+	}
+	base := r.Version
+	for _, v := range slices.Concat(published, yanked, local) {
+		if parsed, err := ref.ParseVersion(v); err == nil && parsed.Pre == "" && ref.Compare(v, base) > 0 {
+			base = v
+		}
+	}
+	next, err := ref.BumpVersion(base, part)
+	if err != nil {
+		return r, r, output.NewError(output.CodeInvalidArgument, "Cannot bump %s: %v", r, err)
+	}
+	to = r
+	to.Version = next
+	if dst := componentDir(to); isDir(dst) {
+		return r, r, output.Errorf("Cannot bump %s: %s already exists; publish %s instead.", r, dst, to)
+	}
+	return r, to, nil
+}
+
+// bumpComponent prepares the next version of the local component r for
+// publishing, as bumpVersion works it out: the version directory it starts
+// from is copied to the new version's, whose manifest and README are
+// updated to it. The new version's reference is returned.
+func bumpComponent(ctx context.Context, client *mcp.Client, r ref.ComponentRef, part string) (ref.ComponentRef, error) {
+	from, to, err := bumpVersion(ctx, client, r, part)
+	if err != nil {
+		return r, err
+	}
+	dst := componentDir(to)
+	if err := copyDir(componentDir(from), dst); err != nil {
+		os.RemoveAll(dst)
+		return r, output.Errorf("Cannot bump %s: %v", from, err)
+	}
+	if err := rewriteVersion(dst, from, to); err != nil {
+		os.RemoveAll(dst)
+		return r, output.Errorf("Cannot bump %s: %v", from, err)
+	}
+	fmt.Fprintf(os.Stderr, "Bumped %s to %s in %s\n", from, to.Version, dst)
+	return to, nil
+}
+
+// manifestVersionPattern matches the version field of a manifest.
+var manifestVersionPattern = regexp.MustCompile(`("version"\s*:\s*")([^"]*)(")`)
+
+// rewriteVersion updates the copy of from in dir to the version of to: the
+// version in its manifest, and its reference and path in its README. The
+// files are otherwise left as they are.
+func rewriteVersion(dir string, from, to ref.ComponentRef) error {
+	path := filepath.Join(dir, componentManifestFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	replaced := false
+	data = manifestVersionPattern.ReplaceAllFunc(data, func(m []byte) []byte {
+		if replaced {
+			return m
+		}
+		replaced = true
+		return manifestVersionPattern.ReplaceAll(m, []byte("${1}"+to.Version+"${3}"))
+	})
+	if !replaced {
+		return fmt.Errorf("%s has no version", path)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	path = filepath.Join(dir, "README.md")
+	readme, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	updated := strings.NewReplacer(
+		from.String(), to.String(),
+		filepath.ToSlash(componentDir(from)), filepath.ToSlash(componentDir(to)),
+	).Replace(string(readme))
+	return os.WriteFile(path, []byte(updated), 0644)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+func TestBumpComponent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.search"] = map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "local", "version": "1.4.0", "component_type": "reagent"},
+		map[string]any{"name": "sentiment", "publisher": "local", "version": "2.0.0-beta.1", "component_type": "reagent"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "3.0.0", "component_type": "reagent"},
+	}}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	chdirTemp(t)
+	for _, v := range []string{"1.1.0", "1.2.0"} {
+		dir := filepath.Join("components", "reagents", "local", "sentiment", v)
+		os.MkdirAll(dir, 0755)
+		manifest := `{"id": "reagent:local.sentiment", "type": "reagent", "version": "` + v + `", "schema": {"version": "x"}}`
+		os.WriteFile(filepath.Join(dir, componentManifestFile), []byte(manifest), 0644)
+		os.WriteFile(filepath.Join(dir, "README.md"), []byte(componentReadme("sentiment", "reagent", v, "Scores text.")), 0644)
+		os.WriteFile(filepath.Join(dir, "reagent.wasm"), []byte(v), 0644)
+	}
+
+	bumped, err := bumpComponent(context.Background(), client, ref.MustParse("r:local.sentiment"), "minor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bumped.String() != "reagent:local.sentiment:1.5.0" {
+		t.Fatalf("bumped to %s, want reagent:local.sentiment:1.5.0", bumped)
+	}
+	dir := componentDir(bumped)
+	if data, _ := os.ReadFile(filepath.Join(dir, "reagent.wasm")); string(data) != "1.2.0" {
+		t.Errorf("copied reagent.wasm = %q, want the one of 1.2.0", data)
+	}
+	manifest, _ := os.ReadFile(filepath.Join(dir, componentManifestFile))
+	if !strings.Contains(string(manifest), `"version": "1.5.0"`) || !strings.Contains(string(manifest), `{"version": "x"}`) {
+		t.Errorf("manifest = %s", manifest)
+	}
+	readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	if strings.Contains(string(readme), "1.2.0") || !strings.Contains(string(readme), "reagent:local.sentiment:1.5.0") {
+		t.Errorf("README not updated:\n%s", readme)
+	}
+
+	// Versions bumped but not yet published count too.
+	if bumped, err := bumpComponent(context.Background(), client, ref.MustParse("r:local.sentiment:1.1.0"), "minor"); err != nil || bumped.Version != "1.6.0" {
+		t.Errorf("second bump = %s, %v, want 1.6.0", bumped, err)
+	}
+	if _, err := bumpComponent(context.Background(), client, ref.MustParse("r:local.missing"), "patch"); err == nil {
+		t.Error("bumping a missing component succeeded")
+	}
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/cyfr/codex/internal/cosign"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/oci"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// A publishPlan is what 'cyfr publish --dry-run' reports: what publishing
+// a component would upload, and how it compares with what is published.
+type publishPlan struct {
+	Reference   string `json:"reference"`
+	Artifact    string `json:"artifact"`
+	Size        int64  `json:"size,omitempty"`
+	Digest      string `json:"digest,omitempty"`
+	ArtifactErr string `json:"artifact_error,omitempty"`
+
+	// Signature is the signature bundle 'cyfr sign' stored beside the
+	// artifact, or "" if it isn't signed; SignatureErr is why it can't be
+	// attached.
+	Signature    string `json:"signature,omitempty"`
+	SignatureErr string `json:"signature_error,omitempty"`
+
+	Exists bool   `json:"exists"`           // the version is already published
+	Latest string `json:"latest,omitempty"` // the highest published release
+
+	// Changes are the metadata that differ from Latest's, for the server's
+	// registry.
+	Changes []metadataChange `json:"changes"`
+
+	// Policy is the host policy the version would run under: the defaults
+	// for its type unless one is set. Only for the server's registry.
+	Policy    map[string]any `json:"policy,omitempty"`
+	PolicyErr string         `json:"policy_error,omitempty"`
+}
+
+// A metadataChange is a field whose published value would change.
+type metadataChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// planPublish works out the publishPlan of r, whose files are in the local
+// directory dir, without changing anything. artifactPath overrides the
+// .wasm file in dir. client is only used for the server's registry.
+func planPublish(ctx context.Context, client *mcp.Client, r ref.ComponentRef, dir, artifactPath string) (*publishPlan, error) {
+	if r.Type == "" {
+		return nil, output.NewError(output.CodeInvalidArgument, "Cannot publish %s: add a type prefix, e.g. c:%s", r, r)
+	}
+	if r.Version == "latest" || r.HasConstraint() || r.Digest != "" {
+		return nil, output.NewError(output.CodeInvalidArgument, "Cannot publish %s: publish needs an exact version.", r)
+	}
+	if artifactPath == "" {
+		artifactPath = filepath.Join(dir, r.Type+".wasm")
+	}
+	plan := &publishPlan{Reference: r.String(), Artifact: artifactPath, Changes: []metadataChange{}}
+	if data, err := os.ReadFile(artifactPath); err != nil {
+		plan.ArtifactErr = err.Error()
+	} else {
+		plan.Size, plan.Digest = int64(len(data)), oci.Digest(data)
+	}
+	if signature, err := artifactSignature(artifactPath); err != nil {
+		plan.SignatureErr = err.Error()
+	} else if signature != nil {
+		plan.Signature = artifactPath + cosign.BundleSuffix
+	}
+
+	var versions, yanked []string
+	var err error
+	if r.Registry != "" {
+		versions, err = ociTags(ctx, r)
+	} else {
+		versions, yanked, err = publishedVersions(ctx, client, r)
+	}
+	if err != nil {
+		return nil, output.Errorf("Cannot list the published versions of %s: %v", r, toolError(err))
+	}
+	plan.Exists = slices.Contains(versions, r.Version) || slices.Contains(yanked, r.Version)
+	plan.Latest, _ = ref.Latest(versions)
+	if r.Registry != "" {
+		return plan, nil
+	}
+
+	if plan.Latest != "" {
+		latest := r
+		latest.Version = plan.Latest
+		published, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+			Action:    typed.ComponentActionInspect,
+			Reference: latest.String(),
+		})
+		if err != nil {
+			return nil, output.Errorf("Cannot inspect %s: %v", latest, err)
+		}
+		plan.Changes = metadataChanges(published, plan.localMetadata(dir))
+	}
+
+	result, err := typed.New(client).Policy(ctx, typed.PolicyArgs{
+		Action:       typed.PolicyActionGet,
+		ComponentRef: r.String(),
+	})
+	if err != nil {
+		plan.PolicyErr = err.Error()
+	} else {
+		plan.Policy, _ = result["policy"].(map[string]any)
+	}
+	return plan, nil
+}
+
+// localMetadata returns the metadata of the component in dir as the
+// registry would record it: its manifest's type and description, and its
+// artifact's digest and size.
+func (p *publishPlan) localMetadata(dir string) map[string]string {
+	metadata := map[string]string{}
+	var m struct {
+		Type        string `json:"type"`
+		Description string `json:"description"`
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, componentManifestFile)); err == nil && json.Unmarshal(data, &m) == nil {
+		metadata["type"], metadata["description"] = m.Type, m.Description
+	}
+	if p.Digest != "" {
+		metadata["digest"], metadata["size"] = p.Digest, fmt.Sprint(p.Size)
+	}
+	return metadata
+}
+
+// metadataFields are the fields metadataChanges compares, in the order
+// they are reported.
+var metadataFields = []string{"type", "description", "digest", "size"}
+
+// metadataChanges compares the inspect result of a published version with
+// local metadata. Fields missing on either side aren't compared.
+func metadataChanges(published map[string]any, local map[string]string) []metadataChange {
+	changes := []metadataChange{}
+	for _, field := range metadataFields {
+		v := published[field]
+		if field == "type" && v == nil {
+			v = published["component_type"]
+		}
+		if f, ok := v.(float64); ok {
+			v = int64(f)
+		}
+		from, to := "", local[field]
+		if v != nil {
+			from = fmt.Sprint(v)
+		}
+		if from != "" && to != "" && from != to {
+			changes = append(changes, metadataChange{Field: field, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// printPublishPlan prints the plan planPublish returned, or returns its
+// error.
+func printPublishPlan(plan *publishPlan, err error) error {
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		output.JSON(plan)
+	} else {
+		plan.print()
+	}
+	return nil
+}
+
+// print prints the plan for people.
+func (p *publishPlan) print() {
+	fmt.Println("Dry run: nothing was published.")
+	fmt.Println()
+	fmt.Printf("%-12s %s\n", "Reference:", p.Reference)
+	if p.ArtifactErr != "" {
+		fmt.Printf("%-12s %s\n", "Artifact:", p.ArtifactErr)
+	} else {
+		fmt.Printf("%-12s %s (%s)\n", "Artifact:", p.Artifact, output.HumanBytes(p.Size))
+		fmt.Printf("%-12s %s\n", "Digest:", p.Digest)
+	}
+	switch {
+	case p.SignatureErr != "":
+		fmt.Printf("%-12s %s\n", "Signature:", p.SignatureErr)
+	case p.Signature != "":
+		fmt.Printf("%-12s %s\n", "Signature:", p.Signature)
+	default:
+		fmt.Printf("%-12s none; 'cyfr sign' signs it\n", "Signature:")
+	}
+	r, _ := ref.Parse(p.Reference)
+	switch {
+	case p.Exists:
+		fmt.Printf("%-12s %s is already published; publishing it would fail\n", "Version:", r.Version)
+	case p.Latest != "":
+		fmt.Printf("%-12s %s is new; the latest published is %s\n", "Version:", r.Version, p.Latest)
+	default:
+		fmt.Printf("%-12s %s is new; no release is published yet\n", "Version:", r.Version)
+	}
+
+	if p.Latest != "" && r.Registry == "" {
+		fmt.Println()
+		if len(p.Changes) == 0 {
+			fmt.Printf("No metadata changes from %s.\n", p.Latest)
+		} else {
+			fmt.Printf("Changes from %s:\n", p.Latest)
+			for _, c := range p.Changes {
+				fmt.Printf("  %-12s %s -> %s\n", c.Field, c.From, c.To)
+			}
+		}
+	}
+
+	if p.PolicyErr != "" {
+		fmt.Printf("\nPolicy: unknown (%s)\n", p.PolicyErr)
+	} else if p.Policy != nil {
+		fmt.Println("\nPolicy (the defaults for its type unless one is set):")
+		keys := make([]string, 0, len(p.Policy))
+		for k := range p.Policy {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, _ := json.Marshal(p.Policy[k])
+			fmt.Printf("  %-18s %s\n", k, value)
+		}
+	}
+}
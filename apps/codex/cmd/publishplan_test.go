@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/oci"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+func TestMetadataChanges(t *testing.T) {
+	published := map[string]any{"component_type": "reagent", "description": "Scores text", "digest": "sha256:aaa", "size": float64(8)}
+	local := map[string]string{"type": "reagent", "description": "Scores text in any language", "digest": "sha256:bbb", "size": "8"}
+	want := []metadataChange{
+		{Field: "description", From: "Scores text", To: "Scores text in any language"},
+		{Field: "digest", From: "sha256:aaa", To: "sha256:bbb"},
+	}
+	if got := metadataChanges(published, local); !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %+v, want %+v", got, want)
+	}
+	// Fields the registry doesn't report aren't compared.
+	if got := metadataChanges(map[string]any{"type": "reagent"}, local); len(got) != 0 {
+		t.Errorf("changes = %+v, want none", got)
+	}
+}
+
+func TestPlanPublish(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.search"] = map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "local", "version": "1.0.0", "component_type": "reagent"},
+		map[string]any{"name": "sentiment", "publisher": "local", "version": "1.1.0", "component_type": "reagent"},
+	}}
+	f.Responses["component.inspect"] = map[string]any{"name": "sentiment", "version": "1.1.0", "type": "reagent", "description": "Scores text"}
+	f.Responses["policy.get"] = map[string]any{"policy": map[string]any{"allowed_domains": []any{}, "timeout": "1m"}}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	chdirTemp(t)
+	dir := filepath.Join("components", "reagents", "local", "sentiment", "1.2.0")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, componentManifestFile), []byte(`{"type": "reagent", "version": "1.2.0", "description": "Scores text in any language"}`), 0644)
+	os.WriteFile(filepath.Join(dir, "reagent.wasm"), []byte("\x00asm"), 0644)
+
+	r := ref.MustParse("r:local.sentiment:1.2.0")
+	plan, err := planPublish(context.Background(), client, r, dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Exists || plan.Latest != "1.1.0" || plan.Size != 4 || plan.Digest != oci.Digest([]byte("\x00asm")) {
+		t.Errorf("plan = %+v", plan)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Field != "description" {
+		t.Errorf("changes = %+v, want the description", plan.Changes)
+	}
+	if plan.Policy["timeout"] != "1m" {
+		t.Errorf("policy = %v", plan.Policy)
+	}
+
+	r.Version = "1.1.0"
+	if plan, err := planPublish(context.Background(), client, r, dir, ""); err != nil || !plan.Exists {
+		t.Errorf("plan of a published version = %+v, %v; want it to exist", plan, err)
+	}
+	r.Version = "latest"
+	if _, err := planPublish(context.Background(), client, r, dir, ""); err == nil {
+		t.Error("planning latest succeeded, want an exact version required")
+	}
+}
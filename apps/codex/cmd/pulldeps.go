@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// A pulledComponent is one component pull --with-deps went through.
+type pulledComponent struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"` // fetched, cached or failed
+	Source    string `json:"source,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// pullWithDeps pulls the components refs name and every component in
+// their dependency trees, as deps resolves them, each once. A component
+// already in the project's components/ directory with its artifact built
+// is left as it is, and reported as cached.
+func pullWithDeps(ctx context.Context, client *mcp.Client, refs []string) []pulledComponent {
+	d := newDepResolver(ctx, client)
+	var pulled []pulledComponent
+	seen := map[string]bool{}
+	var visit func(n *depNode)
+	visit = func(n *depNode) {
+		if seen[n.Reference] {
+			return
+		}
+		seen[n.Reference] = true
+		pulled = append(pulled, pullDep(ctx, client, n))
+		for _, c := range n.Dependencies {
+			visit(c)
+		}
+	}
+	for _, raw := range refs {
+		visit(d.walk(raw, nil))
+	}
+	return pulled
+}
+
+// pullDep pulls the component of one node of a dependency tree unless it
+// is cached.
+func pullDep(ctx context.Context, client *mcp.Client, n *depNode) pulledComponent {
+	p := pulledComponent{Reference: n.Reference, Source: n.Source}
+	for _, problem := range n.Problems {
+		if strings.HasPrefix(problem, "unresolved: ") {
+			p.Status, p.Error = "failed", strings.TrimPrefix(problem, "unresolved: ")
+			return p
+		}
+	}
+	if n.Source == "local" {
+		r, err := parseComponentRef(n.Reference)
+		if err == nil {
+			if _, err := os.Stat(filepath.Join(componentDir(r), r.Type+".wasm")); err == nil {
+				p.Status = "cached"
+				return p
+			}
+		}
+	}
+	result, err := pullComponent(ctx, client, n.Reference)
+	if err != nil {
+		p.Status, p.Error = "failed", err.Error()
+		return p
+	}
+	p.Status = "fetched"
+	if source, ok := result["source"].(string); ok {
+		p.Source = source
+	}
+	return p
+}
+
+// printPulled reports what pull --with-deps fetched, and fails if any
+// component couldn't be pulled.
+func printPulled(pulled []pulledComponent) error {
+	counts := map[string]int{}
+	for _, p := range pulled {
+		counts[p.Status]++
+	}
+	if flagJSON {
+		output.JSON(map[string]any{"components": pulled, "fetched": counts["fetched"], "cached": counts["cached"], "failed": counts["failed"]})
+	} else {
+		rows := make([]map[string]string, len(pulled))
+		for i, p := range pulled {
+			rows[i] = map[string]string{"REFERENCE": p.Reference, "STATUS": p.Status, "SOURCE": p.Source, "ERROR": p.Error}
+		}
+		headers := []string{"REFERENCE", "STATUS", "SOURCE"}
+		if counts["failed"] > 0 {
+			headers = append(headers, "ERROR")
+		}
+		output.Table(headers, rows)
+		fmt.Printf("\nFetched %d component(s); %d already cached.\n", counts["fetched"], counts["cached"])
+	}
+	if counts["failed"] > 0 {
+		return output.Errorf("Cannot pull %d component(s)", counts["failed"])
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+)
+
+func TestPullWithDeps(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := httptest.NewServer(mockserver.New(mockserver.DefaultFixtures()))
+	defer srv.Close()
+
+	chdirTemp(t)
+	writeComponent(t, "formula", "top", `{"type": "formula", "dependencies": ["c:local.fetch:0.1.0", "r:local.hello:0.1.0", "local.untyped:1.0.0"]}`,
+		map[string]string{"formula.wasm": "\x00asm"})
+	writeComponent(t, "formula", "other", `{"type": "formula", "dependencies": ["c:local.fetch:0.1.0"]}`, map[string]string{})
+	// Not built yet, so it has to be pulled.
+	writeComponent(t, "catalyst", "fetch", `{"type": "catalyst"}`, map[string]string{})
+
+	pulled := pullWithDeps(context.Background(), mcp.NewClient(srv.URL), []string{"f:local.top:0.1.0", "f:local.other:0.1.0"})
+	var got []string
+	for _, p := range pulled {
+		got = append(got, p.Reference+" "+p.Status)
+	}
+	want := []string{
+		"formula:local.top:0.1.0 cached",
+		"catalyst:local.fetch:0.1.0 fetched",
+		"reagent:local.hello:0.1.0 fetched",
+		"local.untyped:1.0.0 failed",
+		"formula:local.other:0.1.0 fetched",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pulled:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(pulled[3].Error, "no type") {
+		t.Errorf("error = %q", pulled[3].Error)
+	}
+
+	var err error
+	out, _ := captureStdout(func() { err = printPulled(pulled) })
+	if err == nil || !strings.Contains(err.Error(), "Cannot pull 1 component(s)") {
+		t.Errorf("err = %v", err)
+	}
+	if !strings.Contains(out, "Fetched 3 component(s); 1 already cached.") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
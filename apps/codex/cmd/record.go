@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// mockBuiltin is the --mock value selecting the built-in fixtures.
+const mockBuiltin = "builtin"
+
+var (
+	recorderOnce sync.Once
+	recorder     *mcp.Recorder
+	replayerOnce sync.Once
+	replayer     *mcp.Replayer
+	replayerErr  error
+	mockOnce     sync.Once
+	mockServer   *mockserver.Server
+	mockErr      error
+)
+
+// trafficWrapper returns the transport wrapper selected by --mock, --record
+// or --replay, or nil for plain network access. Every client in the process
+// shares one mock server, recorder or replayer, so multi-context commands
+// record to (and replay from) a single file. --record may be combined with
+// --mock to capture a mock session.
+func trafficWrapper() (func(http.RoundTripper) http.RoundTripper, error) {
+	if flagRecord != "" && flagReplay != "" {
+		return nil, output.Error("--record and --replay cannot be used together.")
+	}
+	if flagMock != "" && flagReplay != "" {
+		return nil, output.Error("--mock and --replay cannot be used together.")
+	}
+
+	var base func(http.RoundTripper) http.RoundTripper
+	switch {
+	case flagMock != "":
+		mockOnce.Do(func() {
+			fixtures := mockserver.DefaultFixtures()
+			if flagMock != mockBuiltin {
+				fixtures, mockErr = mockserver.LoadFixtures(flagMock)
+				if mockErr != nil {
+					return
+				}
+			}
+			mockServer = mockserver.New(fixtures)
+		})
+		if mockErr != nil {
+			return nil, output.Errorf("Failed to load mock fixtures: %v", mockErr)
+		}
+		base = func(http.RoundTripper) http.RoundTripper { return mockServer.Transport() }
+	case flagReplay != "":
+		replayerOnce.Do(func() {
+			replayer, replayerErr = mcp.LoadReplayer(flagReplay)
+		})
+		if replayerErr != nil {
+			return nil, output.Errorf("Failed to load recording: %v", replayerErr)
+		}
+		base = replayer.Wrap
+	}
+
+	if flagRecord == "" {
+		return base, nil
+	}
+	recorderOnce.Do(func() { recorder = mcp.NewRecorder(flagRecord) })
+	if base == nil {
+		return recorder.Wrap, nil
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return recorder.Wrap(base(next))
+	}, nil
+}
+
+// offline reports whether requests are answered locally (--mock or
+// --replay) rather than by a live server.
+func offline() bool {
+	return flagMock != "" || flagReplay != ""
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// componentRefArgs expands the reference arguments of a command that
+// accepts several components. A leading type applies to every reference
+// without one ("cyfr pull c acme.a:1.0 acme.b:2.0"), taking precedence over
+// the project's default type; each argument may be
+// a comma-separated list, and aliases are expanded. The references are
+// validated and returned deduplicated and sorted, in canonical form.
+func componentRefArgs(args []string) ([]string, error) {
+	var typ string
+	if len(args) >= 2 && ref.IsTypePrefix(args[0]) {
+		typ, args = args[0], args[1:]
+	}
+	var refs []ref.ComponentRef
+	for _, arg := range args {
+		for _, item := range ref.SplitList(arg) {
+			expanded, err := expandComponentRef(item)
+			if err != nil {
+				return nil, err
+			}
+			r, err := parseComponentRef(withDefaultType(expanded, typ))
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, r)
+		}
+	}
+	if len(refs) == 0 {
+		return nil, output.Error("No component reference given.")
+	}
+	deduped := ref.Dedupe(refs)
+	out := make([]string, len(deduped))
+	for i, r := range deduped {
+		out[i] = r.String()
+	}
+	return out, nil
+}
+
+// hasTypePrefix reports whether a reference starts with a type prefix.
+func hasTypePrefix(s string) bool {
+	prefix, _, ok := strings.Cut(s, ":")
+	return ok && !strings.Contains(prefix, ".") && ref.IsTypePrefix(prefix)
+}
+
+// printRefResults prints the results of a command run for each of refs:
+// with --json, a single result as usual and several as
+// {"results": [...], "count": n}; otherwise each with print, separated by
+// blank lines.
+func printRefResults(refs []string, results []map[string]any, print func(ref string, result map[string]any)) {
+	if flagJSON {
+		if len(results) == 1 {
+			output.JSON(results[0])
+		} else {
+			output.JSON(map[string]any{"results": results, "count": len(results)})
+		}
+		return
+	}
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println("")
+		}
+		print(refs[i], result)
+	}
+}
+
+// refKeyValue returns a printRefResults printer that shows results as
+// key/value pairs, under a header naming their reference if there are
+// several.
+func refKeyValue(refs []string) func(string, map[string]any) {
+	return func(ref string, result map[string]any) {
+		if len(refs) > 1 {
+			fmt.Printf("[%s]\n", ref)
+		}
+		output.KeyValue(result)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+func TestComponentRefArgs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultForLocal()
+	cfg.Aliases = map[string]string{"claude": "catalyst:local.claude:0.1.0"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"c:local.claude:0.1.0"}, []string{"catalyst:local.claude:0.1.0"}},
+		{[]string{"r:acme.parser@2.0.0,claude", "catalyst:local.claude:0.1.0"}, []string{"catalyst:local.claude:0.1.0", "reagent:acme.parser:2.0.0"}},
+		{[]string{"c", "local.b:1.0.0", "r:local.a:1.0.0,local.a:1.0.0"}, []string{"catalyst:local.a:1.0.0", "catalyst:local.b:1.0.0", "reagent:local.a:1.0.0"}},
+		{[]string{"c:acme.sentiment@>=1.0.0, <2.0.0"}, []string{"catalyst:acme.sentiment:>=1.0.0, <2.0.0"}},
+	}
+	for _, tt := range tests {
+		if got, err := componentRefArgs(tt.args); err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("componentRefArgs(%q) = %q, %v, want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestInspect_MultipleRefs(t *testing.T) {
+	// Duplicates collapse to one reference, printed as a single result.
+	out := runCLI(t, "--json", "inspect", "r:local.hello:0.1.0,reagent:local.hello:0.1.0")
+	var single map[string]any
+	if err := json.Unmarshal([]byte(out), &single); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	if _, ok := single["results"]; ok || single["name"] != "hello" {
+		t.Errorf("deduplicated inspect = %v, want a single result", single)
+	}
+
+	out = runCLI(t, "--json", "inspect", "r", "local.hello:0.1.0", "local.hello:0.2.0")
+	var multi struct {
+		Results []map[string]any `json:"results"`
+		Count   int              `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &multi); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	if multi.Count != 2 || len(multi.Results) != 2 {
+		t.Errorf("inspect of two refs = %s", out)
+	}
+
+	out = runCLI(t, "inspect", "r:local.hello:0.1.0,r:local.hello:0.2.0")
+	if !strings.Contains(out, "[reagent:local.hello:0.1.0]\n") || !strings.Contains(out, "\n[reagent:local.hello:0.2.0]\n") {
+		t.Errorf("text output lacks reference headers:\n%s", out)
+	}
+}
@@ -1,35 +1,149 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	registerCmd.Flags().Bool("skip-validation", false, "Register without checking the component as 'cyfr validate' does")
+	registerCmd.Flags().BoolP("watch", "w", false, "Register again whenever the component's .wasm or metadata changes, until interrupted")
 	rootCmd.AddCommand(registerCmd)
 }
 
 var registerCmd = &cobra.Command{
-	Use:     "register <directory>",
+	Use:     "register <directory|wasm>",
 	Short:   "Register a local component",
 	GroupID: "component",
-	Long:    "Register a local component directory with the Compendium registry, making it available for registry references in formulas.",
+	Long:    "Register a local component directory with the Compendium registry, making it available for registry references in formulas. A path to the component's .wasm file registers its directory. For components in the components/ layout, the reference they are registered under is printed. The component is checked as 'cyfr validate' does first; --skip-validation registers it anyway.\n\nWith --watch, the component is registered again whenever its .wasm or its metadata (cyfr-manifest.json and the other JSON files in its directory) changes, and each new registration is printed, a line of JSON each with --json. Run it next to 'cyfr build --watch' so that every rebuild is registered. A failed registration is reported and waits for the next change.",
 	Example: `  cyfr register components/catalysts/local/my-tool/0.1.0/
-  cyfr register ./my-component/0.1.0/ --json`,
+  cyfr register components/catalysts/local/my-tool/0.1.0/catalyst.wasm
+  cyfr register ./my-component/0.1.0/ --json
+  cyfr register components/catalysts/local/my-tool/0.1.0/ --watch`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("component", map[string]any{
-			"action":    "register",
-			"directory": args[0],
-		})
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		if strings.HasSuffix(dir, ".wasm") {
+			dir = filepath.Dir(dir)
+		}
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			return watchRegister(cmd, dir)
+		}
+		if err := checkBeforeRegister(cmd, dir); err != nil {
+			return err
+		}
+		result, err := registerComponent(cmd.Context(), dir)
 		if err != nil {
-			output.Errorf("Register failed: %v", err)
+			return err
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
+
+// watchRegister registers the component directory dir, and again whenever
+// its registrationSnapshot changes, until the command is interrupted. A
+// change is registered once the files have stopped changing, so a build
+// still writing the .wasm isn't registered half-written.
+func watchRegister(cmd *cobra.Command, dir string) error {
+	ctx := cmd.Context()
+	register := func() {
+		if err := checkBeforeRegister(cmd, dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return
+		}
+		result, err := registerComponent(ctx, dir)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			return
+		}
+		if flagJSON {
+			line, _ := json.Marshal(result)
+			fmt.Println(string(line))
+			return
+		}
+		fmt.Printf("Registered at %s\n", time.Now().Format(time.TimeOnly))
+		output.KeyValue(result)
+		fmt.Println()
+	}
+
+	registered := registrationSnapshot(dir)
+	register()
+	fmt.Fprintf(os.Stderr, "Watching %s for changes. Press Ctrl-C to stop.\n", dir)
+	pending := registered
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(500 * time.Millisecond):
+		}
+		snapshot := registrationSnapshot(dir)
+		if snapshot == registered {
+			pending = snapshot
+			continue
+		}
+		if snapshot != pending {
+			// Still changing; wait for it to settle.
+			pending = snapshot
+			continue
+		}
+		registered = snapshot
+		register()
+	}
+}
+
+// registrationSnapshot returns the modification times and sizes of what
+// registering dir reads: its .wasm and JSON files.
+func registrationSnapshot(dir string) string {
+	files, _ := os.ReadDir(dir)
+	var entries []string
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, ".wasm") && !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if info, err := f.Info(); err == nil {
+			entries = append(entries, fmt.Sprintf("%s %d %d", name, info.ModTime().UnixNano(), info.Size()))
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// registerComponent registers the component directory dir with the
+// server, returning its result with the reference the component was
+// registered under.
+func registerComponent(ctx context.Context, dir string) (map[string]any, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionRegister,
+		Directory: dir,
+	})
+	if err != nil {
+		return nil, output.Errorf("Register failed: %v", err)
+	}
+	if r, err := ref.FromPath(dir); err == nil && result != nil && result["reference"] == nil {
+		result["reference"] = r.String()
+	}
+	return result, nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistrationSnapshot(t *testing.T) {
+	chdirTemp(t)
+	dir := writeComponent(t, "reagent", "r", `{"type": "reagent"}`, map[string]string{"app.py": "x = 1\n"})
+	before := registrationSnapshot(dir)
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("x = 22\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "src"), 0755)
+	os.WriteFile(filepath.Join(dir, "src", "lib.json"), []byte("{}"), 0644)
+	if registrationSnapshot(dir) != before {
+		t.Error("a source change changed the snapshot")
+	}
+	os.WriteFile(filepath.Join(dir, "reagent.wasm"), []byte("built"), 0644)
+	afterBuild := registrationSnapshot(dir)
+	if afterBuild == before {
+		t.Error("a new .wasm didn't change the snapshot")
+	}
+	os.WriteFile(filepath.Join(dir, componentManifestFile), []byte(`{"type": "reagent", "description": "changed"}`), 0644)
+	if registrationSnapshot(dir) == afterBuild {
+		t.Error("a manifest change didn't change the snapshot")
+	}
+}
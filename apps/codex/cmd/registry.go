@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	registryAddCmd.Flags().StringSlice("namespace", nil, "Route this component namespace to the registry (repeatable)")
+	registryAddCmd.Flags().Int("priority", 0, "Prefer the registry over others routing the same namespace with a lower priority")
+	registryLoginCmd.Flags().StringP("username", "u", "", "Username for the registry")
+	registryLoginCmd.Flags().Bool("password-stdin", false, "Read the password from stdin without prompting")
+	registryCmd.AddCommand(registryListCmd, registryAddCmd, registryRemoveCmd, registryLoginCmd)
+	rootCmd.AddCommand(registryCmd)
+}
+
+var registryCmd = &cobra.Command{
+	Use:     "registry",
+	Short:   "Manage OCI registries for components",
+	GroupID: "component",
+	Long: `Configure the OCI registries, such as an organization's private one, that components are pulled from, published to and searched in alongside the server's registry.
+
+A registry serves the component namespaces routed to it: with acme routed to ghcr.io/acme-corp, 'cyfr pull c:acme.sentiment:1.0.0' pulls ghcr.io/acme-corp/acme/sentiment:1.0.0. References in other namespaces use the server's registry. When several registries route a namespace, the one with the highest priority is used. pull, publish and search take --registry to use a registry by name instead.
+
+Registries are stored under "registries" in ~/.cyfr/config.json. Passwords are kept there too, or in the OS keyring if "credential_store" is "keyring" when they are set. CYFR_REGISTRY_USERNAME and CYFR_REGISTRY_PASSWORD, if set, override them.`,
+}
+
+var registryListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "Show the configured registries",
+	Long:    "List the configured registries with their URL, the namespaces routed to them, their priority and the user logged in to each.",
+	Example: "  cyfr registry list",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		names := make([]string, 0, len(cfg.Registries))
+		for name := range cfg.Registries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if flagJSON {
+			items := make([]any, len(names))
+			for i, name := range names {
+				r := cfg.Registries[name]
+				items[i] = map[string]any{"name": name, "url": r.URL, "namespaces": r.Namespaces, "priority": r.Priority, "username": r.Username}
+			}
+			output.JSON(map[string]any{"registries": items, "count": len(items)})
+			return nil
+		}
+		if len(names) == 0 {
+			fmt.Println("No registries configured. Add one with 'cyfr registry add'.")
+			return nil
+		}
+		rows := make([]map[string]string, len(names))
+		for i, name := range names {
+			r := cfg.Registries[name]
+			rows[i] = map[string]string{
+				"NAME":       name,
+				"URL":        r.URL,
+				"NAMESPACES": strings.Join(r.Namespaces, ","),
+				"PRIORITY":   fmt.Sprint(r.Priority),
+				"USERNAME":   r.Username,
+			}
+		}
+		output.Table([]string{"NAME", "URL", "NAMESPACES", "PRIORITY", "USERNAME"}, rows)
+		return nil
+	},
+}
+
+var registryAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add a registry",
+	Long:  "Add an OCI registry by name and URL: its host, such as ghcr.io or registry.internal:5000, optionally followed by the path its repositories are under. Adding a registry with the name of an existing one replaces it, keeping its credentials.",
+	Example: `  cyfr registry add acme ghcr.io/acme-corp --namespace acme
+  cyfr registry add mirror registry.internal:5000 --namespace acme --namespace tools --priority 10`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		url, err := registryURL(args[1])
+		if err != nil {
+			return err
+		}
+		namespaces, _ := cmd.Flags().GetStringSlice("namespace")
+		for _, ns := range namespaces {
+			if ns == "" || strings.ContainsAny(ns, "./: ") {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --namespace %q", ns)
+			}
+		}
+		priority, _ := cmd.Flags().GetInt("priority")
+
+		_, err = config.Update(func(cfg *config.Config) error {
+			if cfg.Registries == nil {
+				cfg.Registries = map[string]*config.Registry{}
+			}
+			r := &config.Registry{URL: url, Namespaces: namespaces, Priority: priority}
+			if old := cfg.Registries[name]; old != nil {
+				r.Username, r.Password, r.Credentials = old.Username, old.Password, old.Credentials
+			}
+			cfg.Registries[name] = r
+			return nil
+		})
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"name": name, "url": url, "namespaces": namespaces, "priority": priority})
+			return nil
+		}
+		fmt.Printf("Added registry '%s' (%s)", name, url)
+		if len(namespaces) > 0 {
+			fmt.Printf(" for %s", strings.Join(namespaces, ", "))
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var registryRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a registry",
+	Long:    "Remove a registry and its stored credentials. Its namespaces go back to the server's registry, or to another registry routing them.",
+	Example: "  cyfr registry remove acme",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		_, err := config.Update(func(cfg *config.Config) error {
+			if cfg.Registries[name] == nil {
+				return output.NewError(output.CodeNotFound, "Registry '%s' not found.", name)
+			}
+			return cfg.RemoveRegistry(name)
+		})
+		if err != nil {
+			return registryConfigError(err)
+		}
+		fmt.Printf("Removed registry '%s'\n", name)
+		return nil
+	},
+}
+
+var registryLoginCmd = &cobra.Command{
+	Use:   "login <name>",
+	Short: "Save credentials for a registry",
+	Long:  "Save the username and password, or token, that pull, publish and search use with a registry. The password is prompted for without echoing it, or read from stdin with --password-stdin, which scripts must use.",
+	Example: `  cyfr registry login acme --username bot
+  echo "$GHCR_TOKEN" | cyfr registry login acme -u bot --password-stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		cfg, err := config.Load()
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to load config: %v", err)
+		}
+		if cfg.Registries[name] == nil {
+			return output.NewError(output.CodeNotFound, "Registry '%s' not found. Add it with 'cyfr registry add'.", name)
+		}
+
+		fromStdin, _ := cmd.Flags().GetBool("password-stdin")
+		if !fromStdin && !output.IsTerminal(os.Stdin) {
+			return output.NewError(output.CodeInvalidArgument, "Cannot prompt for the password: stdin isn't a terminal. Pipe it in with --password-stdin.")
+		}
+		in := bufio.NewReader(os.Stdin)
+		username, _ := cmd.Flags().GetString("username")
+		if username == "" {
+			fmt.Fprint(os.Stderr, "Username: ")
+			line, _ := in.ReadString('\n')
+			username = strings.TrimSpace(line)
+		}
+		var password string
+		if fromStdin {
+			line, err := in.ReadString('\n')
+			if err != nil && line == "" {
+				return output.Errorf("Failed to read the password: %v", err)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		} else if password, err = readHidden(cmd.Context(), "Password: "); err != nil {
+			return err
+		}
+		if username == "" || password == "" {
+			return output.NewError(output.CodeInvalidArgument, "A username and a password are needed.")
+		}
+
+		_, err = config.Update(func(cfg *config.Config) error {
+			if cfg.Registries[name] == nil {
+				return output.NewError(output.CodeNotFound, "Registry '%s' not found.", name)
+			}
+			return cfg.SetRegistryCredentials(name, username, password)
+		})
+		if err != nil {
+			return registryConfigError(err)
+		}
+		fmt.Printf("Saved credentials for registry '%s' as %s\n", name, username)
+		return nil
+	},
+}
+
+// registryURL checks the URL of a registry being added and returns it
+// without a scheme or trailing slash.
+func registryURL(url string) (string, error) {
+	url = strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	url = strings.TrimRight(url, "/")
+	// The URL must read as an OCI reference's registry.
+	if r, err := ref.Parse(url + "/ns/name:0.1.0"); err != nil || r.Registry == "" {
+		return "", output.NewError(output.CodeInvalidArgument, "Invalid registry URL %q: give its host, e.g. ghcr.io/acme or registry.internal:5000", url)
+	}
+	return url, nil
+}
+
+// registryConfigError reports an error from updating the config, keeping
+// the code of one already reported.
+func registryConfigError(err error) error {
+	var coded *output.CodedError
+	if errors.As(err, &coded) {
+		return err
+	}
+	return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// addRegistries writes registries to a config in a new HOME.
+func addRegistries(t *testing.T, registries map[string]*config.Registry) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(config.EnvConfigPath, "")
+	if _, err := config.Update(func(cfg *config.Config) error {
+		cfg.Registries = registries
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRouteRegistry(t *testing.T) {
+	addRegistries(t, map[string]*config.Registry{
+		"acme":   {URL: "ghcr.io/acme-corp", Namespaces: []string{"acme"}},
+		"mirror": {URL: "registry.internal:5000"},
+	})
+	for _, tc := range []struct{ ref, registry, want string }{
+		{"catalyst:acme.sentiment:1.0.0", "", "catalyst:ghcr.io/acme-corp/acme/sentiment:1.0.0"},
+		{"catalyst:cyfr.sentiment:1.0.0", "", "catalyst:cyfr.sentiment:1.0.0"},
+		{"catalyst:cyfr.sentiment:1.0.0", "mirror", "catalyst:registry.internal:5000/cyfr/sentiment:1.0.0"},
+		{"catalyst:quay.io/x/sentiment:1.0.0", "acme", "catalyst:quay.io/x/sentiment:1.0.0"},
+	} {
+		if got, err := routeRegistry(tc.ref, tc.registry); err != nil || got != tc.want {
+			t.Errorf("routeRegistry(%q, %q) = %q, %v, want %q", tc.ref, tc.registry, got, err, tc.want)
+		}
+	}
+	_, err := routeRegistry("catalyst:acme.sentiment:1.0.0", "nope")
+	if coded, ok := err.(*output.CodedError); !ok || coded.Code != output.CodeNotFound {
+		t.Errorf("unknown registry: err = %v", err)
+	}
+}
+
+func TestSearchOCI(t *testing.T) {
+	host := ociRegistry(t)
+	addRegistries(t, map[string]*config.Registry{"acme": {URL: host, Namespaces: []string{"acme"}}})
+
+	reg, err := searchRegistry("", "acme")
+	if err != nil || reg == nil {
+		t.Fatalf("--namespace acme isn't routed: %v", err)
+	}
+	result, err := searchOCI(context.Background(), reg, "", "sentiment", "catalyst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := searchSummary(result, "")
+	if len(rows) != 1 || rows[0].(map[string]any)["latest"] != "1.2.0" || rows[0].(map[string]any)["name"] != "acme.sentiment" {
+		t.Errorf("summary = %v", rows)
+	}
+	if _, err := searchOCI(context.Background(), reg, "", "http client", ""); err == nil || !strings.Contains(err.Error(), "keyword") {
+		t.Errorf("keyword search: err = %v", err)
+	}
+	if reg, _ := searchRegistry("", "cyfr"); reg != nil {
+		t.Error("an unrouted namespace should search the server")
+	}
+}
+
+func TestRegistryLogin_Stdin(t *testing.T) {
+	addRegistries(t, map[string]*config.Registry{"acme": {URL: "ghcr.io/acme"}})
+	resetFlags(registryLoginCmd)
+	t.Cleanup(func() { resetFlags(registryLoginCmd) })
+	stdin := func(data string) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteString(data)
+		w.Close()
+		old := os.Stdin
+		os.Stdin = r
+		t.Cleanup(func() { os.Stdin = old; r.Close() })
+	}
+	registryLoginCmd.Flags().Set("username", "bot")
+
+	// Without --password-stdin, a pipe is not prompted on.
+	stdin("s3cret\n")
+	err := registryLoginCmd.RunE(registryLoginCmd, []string{"acme"})
+	if output.Code(err) != output.CodeInvalidArgument || !strings.Contains(err.Error(), "--password-stdin") {
+		t.Errorf("no terminal: err = %v", err)
+	}
+
+	stdin("s3cret\n")
+	registryLoginCmd.Flags().Set("password-stdin", "true")
+	if err := registryLoginCmd.RunE(registryLoginCmd, []string{"acme"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ := config.Load()
+	if password, _ := cfg.RegistryPassword("acme"); cfg.Registries["acme"].Username != "bot" || password != "s3cret" {
+		t.Errorf("saved %q / %q", cfg.Registries["acme"].Username, password)
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(resourcesCmd)
+	resourcesCmd.AddCommand(resourcesListCmd)
+	resourcesCmd.AddCommand(resourcesReadCmd)
+
+	resourcesReadCmd.Flags().String("file", "", "Write the contents to this file instead of stdout")
+}
+
+var resourcesCmd = &cobra.Command{
+	Use:     "resources",
+	Short:   "Browse server resources",
+	GroupID: "advanced",
+	Long:    "Browse the MCP resources a CYFR server exposes — guides, component manifests, execution records and logs — the same way 'cyfr call' reaches its tools.",
+}
+
+var resourcesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available resources",
+	Long:  "List the resources the server exposes. URIs containing {placeholders} are templates; fill them in before reading.",
+	Example: `  cyfr resources list
+  cyfr resources list --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		resources, err := client.ListResourcesCtx(cmd.Context())
+		if err != nil {
+			return toolError(err)
+		}
+
+		if flagJSON {
+			if resources == nil {
+				resources = []mcp.Resource{}
+			}
+			output.JSON(map[string]any{"resources": resources})
+			return nil
+		}
+
+		if len(resources) == 0 {
+			fmt.Println("No resources available.")
+			return nil
+		}
+		headers := []string{"URI", "NAME", "MIME_TYPE", "DESCRIPTION"}
+		rows := make([]map[string]string, len(resources))
+		for i, r := range resources {
+			rows[i] = map[string]string{
+				"URI":         r.URI,
+				"NAME":        r.Name,
+				"MIME_TYPE":   r.MimeType,
+				"DESCRIPTION": r.Description,
+			}
+		}
+		output.Table(headers, rows)
+		return nil
+	},
+}
+
+var resourcesReadCmd = &cobra.Command{
+	Use:   "read <uri>",
+	Short: "Read a resource",
+	Long:  "Fetch a resource by URI and print its contents. JSON contents are pretty-printed; binary contents must be written to a file with --file.",
+	Example: `  cyfr resources read opus://executions/exec_abc123
+  cyfr resources read opus://executions/exec_abc123/logs
+  cyfr resources read opus://executions/exec_abc123 --file record.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outPath, _ := cmd.Flags().GetString("file")
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		contents, err := client.ReadResourceCtx(cmd.Context(), args[0])
+		if err != nil {
+			return toolError(err)
+		}
+
+		if flagJSON {
+			if contents == nil {
+				contents = []mcp.ResourceContents{}
+			}
+			output.JSON(map[string]any{"contents": contents})
+			return nil
+		}
+		if len(contents) == 0 {
+			return output.Errorf("Resource %s has no contents", args[0])
+		}
+
+		if outPath != "" {
+			data, err := resourceBytes(contents)
+			if err != nil {
+				return output.Errorf("Failed to decode resource: %v", err)
+			}
+			if err := os.WriteFile(outPath, data, 0644); err != nil {
+				return output.Errorf("Failed to write %s: %v", outPath, err)
+			}
+			fmt.Printf("Wrote %s to %s\n", output.HumanBytes(int64(len(data))), outPath)
+			return nil
+		}
+
+		for _, c := range contents {
+			if c.Blob != "" {
+				return output.Errorf("%s is binary (%s); use --file to save it", c.URI, c.MimeType)
+			}
+			fmt.Println(formatResourceText(c))
+		}
+		return nil
+	},
+}
+
+// resourceBytes concatenates resource contents as raw bytes, decoding
+// base64 blobs.
+func resourceBytes(contents []mcp.ResourceContents) ([]byte, error) {
+	var data []byte
+	for _, c := range contents {
+		if c.Blob != "" {
+			b, err := base64.StdEncoding.DecodeString(c.Blob)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, b...)
+			continue
+		}
+		data = append(data, c.Text...)
+	}
+	return data, nil
+}
+
+// formatResourceText pretty-prints JSON text contents and returns anything
+// else unchanged.
+func formatResourceText(c mcp.ResourceContents) string {
+	if !strings.Contains(c.MimeType, "json") {
+		return c.Text
+	}
+	var v any
+	if err := json.Unmarshal([]byte(c.Text), &v); err != nil {
+		return c.Text
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return c.Text
+	}
+	return string(b)
+}
@@ -2,17 +2,39 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cyfr/codex/internal/config"
 	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/scaffold"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagJSON    bool
-	flagURL     string
-	flagContext string
+	flagJSON       bool
+	flagURL        string
+	flagContext    string
+	flagRetries    int
+	flagTimeout    time.Duration
+	flagVerbose    bool
+	flagNoValidate bool
+	flagRecord     string
+	flagReplay     string
+	flagMock       string
+	flagTiming     bool
+	flagOutput     string
+	flagQuery      string
+	flagNoColor    bool
+	flagQuiet      bool
+	flagNoPager    bool
+	flagOutputFile string
 )
 
 var rootCmd = &cobra.Command{
@@ -20,13 +42,97 @@ var rootCmd = &cobra.Command{
 	Short: "CYFR CLI — sandboxed WASM runtime for AI agents",
 	Long: `cyfr is the command-line interface for CYFR — a sandboxed runtime
 where AI agents execute tools via MCP. Use cyfr to manage components,
-secrets, policies, and executions from the terminal or scripts.`,
+secrets, policies, and executions from the terminal or scripts.
+
+Connection settings come from the current context in ~/.cyfr/config.json,
+or $XDG_CONFIG_HOME/cyfr/config.json when XDG_CONFIG_HOME is set (an
+existing ~/.cyfr is moved there once). CYFR_CONFIG_PATH names another
+config file to use.
+
+For CI and other environments where writing that file is awkward, they can
+be overridden with environment variables:
+
+  CYFR_CONTEXT     context to use instead of the current one
+  CYFR_URL         server URL
+  CYFR_API_KEY     API key to authenticate with
+  CYFR_SESSION_ID  session to use
+
+A project can pin its own settings in a .cyfr/config.json, found in the
+working directory or any of its parents:
+
+  {"context": "staging", "url": "https://cyfr.staging.example.com",
+   "default_type": "catalyst", "output": "json"}
+
+default_type applies to component references without a type, and output
+"json" makes --json the default.
+
+Flags win over environment variables, which win over the project config,
+which wins over the context: --context over CYFR_CONTEXT over "context",
+and --url over CYFR_URL over "url". A context can also set defaults for
+other flags; see 'cyfr context add --help'.
+
+With --json, a failed command prints its error on stdout as
+{"error": {"code": "...", "message": "..."}} and exits with status 1.
+The codes are stable:
+
+  INVALID_ARGUMENT  bad flags, arguments or input
+  NOT_FOUND         a context, component or other object doesn't exist
+  NOT_LOGGED_IN     the server needs a session; run 'cyfr login'
+  SESSION_EXPIRED   the session has expired; run 'cyfr login'
+  TOKEN_EXPIRED     the OAuth token expired and couldn't be refreshed
+  UNSUPPORTED       the server doesn't offer the tool or feature
+  UNREACHABLE       the server couldn't be reached
+  TIMEOUT           the request took longer than --timeout
+  SERVER_ERROR      the server failed to handle the request
+  CONFIG_ERROR      the CLI config couldn't be read or written
+  UNVERIFIED        a component's digest or signature couldn't be verified
+  PERMISSION_DENIED the user lacks a permission the command needs
+  INTERRUPTED       the command was interrupted (exit status 130)
+  ERROR             anything else`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		applyDefaults(cmd)
+		if err := applyOutputFlags(cmd); err != nil {
+			return err
+		}
+		if flagOutputFile != "" {
+			if err := output.StartOutputFile(flagOutputFile); err != nil {
+				return output.Errorf("Cannot write output to %s: %v", flagOutputFile, err)
+			}
+		}
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		output.StopPager()
+		if flagTiming && !flagJSON {
+			printTimingSummary(os.Stderr, timingCalls())
+		}
+	},
+	// Execute reports errors, so they are reported the same way whether
+	// they come from cobra or a command.
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "Output as JSON")
-	rootCmd.PersistentFlags().StringVar(&flagURL, "url", "", "Override server URL")
-	rootCmd.PersistentFlags().StringVar(&flagContext, "context", "", "Use specific context")
+	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "", "Output format: text, json, csv or tsv (for tables), or go-template=<template>, e.g. go-template='{{.status}}'")
+	rootCmd.PersistentFlags().BoolVarP(&flagQuiet, "quiet", "q", false, "Print only identifiers, one per line, e.g. execution IDs or key names (wins over --json)")
+	rootCmd.PersistentFlags().StringVar(&flagOutputFile, "output-file", "", "Write the command's output to this file, replacing it only once the command succeeds")
+	rootCmd.PersistentFlags().BoolVar(&flagNoPager, "no-pager", false, "Don't send long output (guides, policies, audit events, logs) through the pager")
+	rootCmd.PersistentFlags().BoolVar(&flagNoColor, "no-color", false, "Turn off colored output (also NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&flagQuery, "query", "", "Print only part of the JSON output, e.g. .status or $.items[*].name (implies --json)")
+	rootCmd.PersistentFlags().StringVar(&flagURL, "url", "", "Override server URL (or $CYFR_URL)")
+	rootCmd.PersistentFlags().StringVar(&flagContext, "context", "", "Use specific context (or $CYFR_CONTEXT)")
+	rootCmd.PersistentFlags().IntVar(&flagRetries, "retries", -1, "Retries for transient HTTP failures (default 2, or $CYFR_HTTP_RETRIES)")
+	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Log each MCP request and response to stderr (also CYFR_DEBUG=1)")
+	rootCmd.PersistentFlags().BoolVar(&flagNoValidate, "no-validate", false, "Skip checking tool arguments against the server's schemas before sending")
+	rootCmd.PersistentFlags().BoolVar(&scaffold.Insecure, "insecure-scaffold", false, "Use downloaded scaffold files, templates and server binaries even if they don't match the release's checksums")
+	rootCmd.PersistentFlags().StringVar(&flagRecord, "record", "", "Record all MCP traffic to this file (secrets in requests are redacted)")
+	rootCmd.PersistentFlags().StringVar(&flagReplay, "replay", "", "Answer requests from a file written by --record instead of the server")
+	rootCmd.PersistentFlags().StringVar(&flagMock, "mock", "", "Answer requests from a built-in mock server, or --mock=fixtures.json for custom responses")
+	rootCmd.PersistentFlags().Lookup("mock").NoOptDefVal = mockBuiltin
+	rootCmd.PersistentFlags().BoolVar(&flagTiming, "timing", false, "Print request latency, sizes and retries after the command (with --json, under \"_meta\")")
+	rootCmd.PersistentFlags().DurationVar(&flagTimeout, "timeout", 0, "Request timeout, e.g. 30s or 15m (default 10m, or the context's request_timeout)")
 
 	rootCmd.AddGroup(
 		&cobra.Group{ID: "start", Title: "Getting Started:"},
@@ -41,13 +147,136 @@ func init() {
 	// Cobra already includes a "Use ... --help" footer in the default template
 }
 
-// Execute runs the root command.
-func Execute() error {
-	return rootCmd.Execute()
+// Execute runs the root command. Ctrl-C cancels the command's context,
+// which aborts any in-flight tool call. Commands return their errors, and
+// Execute is where a failed command is reported and exits.
+func Execute() {
+	ctx, stop := signalContext()
+	defer stop()
+	defer output.StopPager()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	if fileErr := finishOutputFile(err == nil); err == nil {
+		err = fileErr
+	}
+	if err != nil {
+		output.Fail(usageError(cmd, err))
+	}
+}
+
+// finishOutputFile ends --output-file, keeping the output if the command
+// succeeded, and confirms on stderr where it went.
+func finishOutputFile(keep bool) error {
+	size, err := output.FinishOutputFile(keep)
+	if err != nil {
+		return output.Errorf("Failed to write %s: %v", flagOutputFile, err)
+	}
+	if keep && flagOutputFile != "" && !flagQuiet {
+		fmt.Fprintf(os.Stderr, "Wrote %s to %s\n", output.HumanBytes(size), flagOutputFile)
+	}
+	return nil
+}
+
+// usageError returns err as reported by Execute. Errors of cobra's own,
+// such as an unknown flag, have no code; they are usage errors, and in
+// text mode are followed by the command's usage, or for an unknown
+// command a pointer to its help, as cobra prints them.
+func usageError(cmd *cobra.Command, err error) error {
+	var coded *output.CodedError
+	if errors.As(err, &coded) {
+		return err
+	}
+	coded = output.NewError(output.CodeInvalidArgument, "%v", err)
+	if !output.JSONErrors {
+		if strings.HasPrefix(coded.Message, "unknown command") {
+			coded.Message += fmt.Sprintf("\nRun '%s --help' for usage.", cmd.CommandPath())
+		} else {
+			coded.Message += "\n" + cmd.UsageString()
+		}
+	}
+	return coded
+}
+
+// Environment variables overriding connection settings.
+const (
+	envURL       = "CYFR_URL"
+	envContext   = "CYFR_CONTEXT"
+	envSessionID = "CYFR_SESSION_ID"
+	envAPIKey    = "CYFR_API_KEY"
+)
+
+// newClient creates an MCP client from config, with the overrides from
+// flags, environment variables and the project config applied, in that
+// order of precedence, over the context.
+func newClient() (*mcp.Client, error) {
+	cfg := loadConfigOrDefault()
+
+	if name := contextOverride(); name != "" {
+		cfg.CurrentContext = name
+	}
+
+	client, err := clientForContext(cfg, cfg.CurrentContext)
+	if err != nil {
+		return nil, err
+	}
+	if url := urlOverride(); url != "" {
+		client.BaseURL = url
+	}
+	if id := os.Getenv(envSessionID); id != "" {
+		client.SessionID = id
+		client.APIKey = ""
+		client.OAuth = nil
+	}
+	if key := os.Getenv(envAPIKey); key != "" {
+		client.APIKey = key
+		client.OAuth = nil
+	}
+	return client, nil
+}
+
+// contextOverride returns the context named by --context, CYFR_CONTEXT or
+// the project config, in that order of precedence, or "" to use the
+// current one.
+func contextOverride() string {
+	if flagContext != "" {
+		return flagContext
+	}
+	if name := os.Getenv(envContext); name != "" {
+		return name
+	}
+	if p := projectConfig(); p != nil {
+		return p.Context
+	}
+	return ""
+}
+
+// urlOverride returns the server URL given by --url, CYFR_URL or the
+// project config, in that order of precedence, or "" to use the context's.
+func urlOverride() string {
+	if flagURL != "" {
+		return flagURL
+	}
+	if url := os.Getenv(envURL); url != "" {
+		return url
+	}
+	if p := projectConfig(); p != nil {
+		return p.URL
+	}
+	return ""
+}
+
+// newTypedClient creates a client for the current context that calls tools
+// through the generated typed bindings.
+func newTypedClient() (*typed.Client, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	return typed.New(client), nil
 }
 
-// newClient creates an MCP client from config.
-func newClient() *mcp.Client {
+// loadConfigOrDefault loads the CLI config, falling back to the default local
+// context when it cannot be read.
+func loadConfigOrDefault() *config.Config {
 	cfg, err := config.Load()
 	if err != nil {
 		cfg = &config.Config{
@@ -57,41 +286,190 @@ func newClient() *mcp.Client {
 			},
 		}
 	}
+	return cfg
+}
 
-	// Override context if flag is set
-	if flagContext != "" {
-		cfg.CurrentContext = flagContext
+// clientForContext creates an MCP client for the named context in cfg.
+func clientForContext(cfg *config.Config, name string) (*mcp.Client, error) {
+	url := "http://localhost:4000"
+	ctx := cfg.Contexts[name]
+	if ctx != nil {
+		url = ctx.URL
 	}
 
-	url := cfg.CurrentURL()
-	if flagURL != "" {
-		url = flagURL
+	opts, err := httpOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrap, err := trafficWrapper()
+	if err != nil {
+		return nil, err
 	}
 
 	client := mcp.NewClient(url)
+	client.SetHTTPOptions(opts)
+	if wrap != nil {
+		client.WrapTransport(wrap)
+	} else if ctx != nil && useWebSocket(ctx) {
+		client.UseWebSocket()
+	}
 
 	// Use cached session ID
-	ctx := cfg.Current()
 	if ctx != nil && ctx.SessionID != "" {
 		client.SessionID = ctx.SessionID
 	}
+	if ctx != nil && ctx.APIKey != "" {
+		client.APIKey = ctx.APIKey
+	}
+	if ctx != nil && ctx.OAuth != nil {
+		client.OAuth = oauthToken(ctx.OAuth)
+		client.OnTokenRefresh = saveOAuthToken(name)
+	}
+	if ctx != nil {
+		client.Server = initializeResult(ctx.Server)
+	}
+
+	if n, ok := retryCount(); ok {
+		client.Retry.MaxRetries = n
+	}
+	if debugEnabled() {
+		client.Debug = os.Stderr
+	}
+	client.OnRateLimit = rateLimitNotice()
+	if flagTiming {
+		client.OnCall = recordCall
+		output.Meta = timingMeta
+	}
+	// Offline sessions have no live server to fetch schemas from.
+	if !flagNoValidate && !offline() {
+		enableArgumentValidation(client)
+	}
+
+	return client, nil
+}
+
+// Context transports.
+const (
+	transportHTTP      = "http"
+	transportWebSocket = "websocket"
+)
+
+// useWebSocket reports whether a context asks for the WebSocket transport,
+// warning about and ignoring values it doesn't know. Recording, replay and
+// mock sessions always use HTTP, where traffic can be intercepted.
+func useWebSocket(ctx *config.Context) bool {
+	if !validTransport(ctx.Transport) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid transport %q in config\n", ctx.Transport)
+		return false
+	}
+	return ctx.Transport == transportWebSocket
+}
+
+// rateLimitNotice returns an OnRateLimit callback that tells the user, once,
+// why the command is slowing down.
+func rateLimitNotice() func(time.Duration) {
+	var once sync.Once
+	return func(time.Duration) {
+		once.Do(func() {
+			fmt.Fprintln(os.Stderr, "Note: the server is rate limiting requests; pacing them, this may take longer.")
+		})
+	}
+}
+
+// httpOptions builds the client transport settings from a context's
+// configuration and the --timeout flag, which takes precedence. TLS
+// settings that can't be loaded are an error rather than silently dropped.
+func httpOptions(ctx *config.Context) (mcp.HTTPOptions, error) {
+	opts := mcp.DefaultHTTPOptions()
+	if ctx != nil {
+		if d, ok := parseConfigDuration("connect_timeout", ctx.ConnectTimeout); ok {
+			opts.ConnectTimeout = d
+		}
+		if d, ok := parseConfigDuration("request_timeout", ctx.RequestTimeout); ok {
+			opts.RequestTimeout = d
+		}
+		if ctx.MaxIdleConns > 0 {
+			opts.MaxIdleConns = ctx.MaxIdleConns
+		}
+		tlsConfig, err := mcp.LoadTLSConfig(ctx.CACert, ctx.ClientCert, ctx.ClientKey, ctx.InsecureSkipVerify)
+		if err != nil {
+			return opts, output.NewError(output.CodeConfig, "Invalid TLS settings for context: %v", err)
+		}
+		opts.TLS = tlsConfig
+	}
+	if flagTimeout > 0 {
+		opts.RequestTimeout = flagTimeout
+	}
+	return opts, nil
+}
+
+// parseConfigDuration parses a duration setting from the config file,
+// warning about and ignoring invalid values.
+func parseConfigDuration(key, v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid %s %q in config\n", key, v)
+		return 0, false
+	}
+	return d, true
+}
 
-	return client
+// debugEnabled reports whether wire-level logging was requested with
+// --verbose or CYFR_DEBUG.
+func debugEnabled() bool {
+	if flagVerbose {
+		return true
+	}
+	v := os.Getenv("CYFR_DEBUG")
+	return v != "" && v != "0" && v != "false"
+}
+
+// retryCount returns the retry count from --retries or CYFR_HTTP_RETRIES,
+// in that order of precedence, and false if neither is set.
+func retryCount() (int, bool) {
+	if flagRetries >= 0 {
+		return flagRetries, true
+	}
+	if v := os.Getenv("CYFR_HTTP_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid CYFR_HTTP_RETRIES=%q\n", v)
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
 }
 
-// handleToolError checks for session expiry and prints a helpful message,
-// otherwise falls back to a generic error.
-func handleToolError(err error) {
-	if errors.Is(err, mcp.ErrSessionExpired) {
-		output.Error("Session expired. Run 'cyfr login' to re-authenticate.")
+// toolError returns the error to report for a failed tool call, with its
+// error code and a message saying what to do about it where possible, such
+// as when the session has expired.
+func toolError(err error) *output.CodedError {
+	if isInterrupted(err) {
+		return errInterrupted()
 	}
-	if errors.Is(err, mcp.ErrSessionRequired) {
-		output.Error("Not logged in. Run 'cyfr login' to authenticate.")
+	var unsupported *mcp.UnsupportedError
+	var argErr *mcp.ArgumentError
+	switch {
+	case errors.As(err, &unsupported):
+		return output.NewError(output.CodeUnsupported, "Not available: %v.", unsupported)
+	case errors.As(err, &argErr):
+		return output.NewError(output.CodeInvalidArgument, "Invalid arguments for %s: %s (use --no-validate to send anyway)", argErr.Tool, strings.Join(argErr.Problems, "; "))
+	case errors.Is(err, mcp.ErrTokenExpired):
+		return output.NewError(output.CodeTokenExpired, "Access token expired and could not be refreshed. Run 'cyfr login' to re-authenticate.")
+	case errors.Is(err, mcp.ErrSessionExpired):
+		return output.NewError(output.CodeSessionExpired, "Session expired. Run 'cyfr login' to re-authenticate.")
+	case errors.Is(err, mcp.ErrSessionRequired):
+		return output.NewError(output.CodeNotLoggedIn, "Not logged in. Run 'cyfr login' to authenticate.")
 	}
-	output.Errorf("Failed: %v", err)
+	return &output.CodedError{Code: errorCode(err), Message: fmt.Sprintf("Failed: %v", err), Err: err}
 }
 
-// saveSessionID persists the session ID from the client to config.
+// saveSessionID persists the session ID from the client to the context
+// the client was made for.
 func saveSessionID(client *mcp.Client) {
 	if client.SessionID == "" {
 		return
@@ -100,5 +478,5 @@ func saveSessionID(client *mcp.Client) {
 	if err != nil {
 		return
 	}
-	_ = cfg.SetSessionID(client.SessionID)
+	_ = cfg.SetSessionID(activeContextName(), client.SessionID)
 }
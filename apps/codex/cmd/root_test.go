@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestHTTPOptions(t *testing.T) {
+	defaults := mcp.DefaultHTTPOptions()
+	tests := []struct {
+		name    string
+		ctx     *config.Context
+		timeout time.Duration
+		want    mcp.HTTPOptions
+	}{
+		{
+			name: "no context",
+			want: defaults,
+		},
+		{
+			name: "context settings",
+			ctx:  &config.Context{ConnectTimeout: "3s", RequestTimeout: "1h", MaxIdleConns: 8},
+			want: mcp.HTTPOptions{ConnectTimeout: 3 * time.Second, RequestTimeout: time.Hour, MaxIdleConns: 8},
+		},
+		{
+			name:    "flag overrides context",
+			ctx:     &config.Context{RequestTimeout: "1h"},
+			timeout: 30 * time.Second,
+			want:    mcp.HTTPOptions{ConnectTimeout: defaults.ConnectTimeout, RequestTimeout: 30 * time.Second, MaxIdleConns: defaults.MaxIdleConns},
+		},
+		{
+			name: "invalid duration ignored",
+			ctx:  &config.Context{RequestTimeout: "soon"},
+			want: defaults,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagTimeout = tt.timeout
+			defer func() { flagTimeout = 0 }()
+
+			if got, _ := httpOptions(tt.ctx); got != tt.want {
+				t.Errorf("httpOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClient_EnvOverrides(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{CurrentContext: "local", Contexts: map[string]*config.Context{
+		"local":   {URL: "http://localhost:4000", SessionID: "sess_local"},
+		"staging": {URL: "https://staging.example.com", APIKey: "cyfr_sk_staging"},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { flagContext, flagURL = "", "" })
+
+	tests := []struct {
+		name              string
+		flagContext       string
+		flagURL           string
+		env               map[string]string
+		url, session, key string
+	}{
+		{name: "config", url: "http://localhost:4000", session: "sess_local"},
+		{
+			name: "env context",
+			env:  map[string]string{envContext: "staging"},
+			url:  "https://staging.example.com", key: "cyfr_sk_staging",
+		},
+		{
+			name:        "flag context over env",
+			flagContext: "local",
+			env:         map[string]string{envContext: "staging"},
+			url:         "http://localhost:4000", session: "sess_local",
+		},
+		{
+			name: "env url",
+			env:  map[string]string{envURL: "https://ci.example.com"},
+			url:  "https://ci.example.com", session: "sess_local",
+		},
+		{
+			name:    "flag url over env",
+			flagURL: "https://flag.example.com",
+			env:     map[string]string{envURL: "https://ci.example.com"},
+			url:     "https://flag.example.com", session: "sess_local",
+		},
+		{
+			name: "env api key over context session",
+			env:  map[string]string{envAPIKey: "cyfr_sk_ci"},
+			url:  "http://localhost:4000", session: "sess_local", key: "cyfr_sk_ci",
+		},
+		{
+			name: "env session over context api key",
+			env:  map[string]string{envContext: "staging", envSessionID: "sess_ci"},
+			url:  "https://staging.example.com", session: "sess_ci",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{envURL, envContext, envSessionID, envAPIKey} {
+				t.Setenv(name, tt.env[name])
+			}
+			flagContext, flagURL = tt.flagContext, tt.flagURL
+			client, _ := newClient()
+			if client.BaseURL != tt.url || client.SessionID != tt.session || client.APIKey != tt.key {
+				t.Errorf("client = %s, session %q, key %q; want %s, %q, %q",
+					client.BaseURL, client.SessionID, client.APIKey, tt.url, tt.session, tt.key)
+			}
+		})
+	}
+}
+
+func TestSaveSessionID_ContextOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{CurrentContext: "local", Contexts: map[string]*config.Context{
+		"local":   {URL: "http://localhost:4000", SessionID: "sess_local"},
+		"staging": {URL: "https://staging.example.com"},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envContext, "staging")
+
+	client := mcp.NewClient("https://staging.example.com")
+	client.SessionID = "sess_staging"
+	saveSessionID(client)
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Contexts["staging"].SessionID; got != "sess_staging" {
+		t.Errorf("staging session = %q, want sess_staging", got)
+	}
+	if got := loaded.Contexts["local"].SessionID; got != "sess_local" {
+		t.Errorf("local session = %q, want it untouched", got)
+	}
+}
+
+func TestUsageError(t *testing.T) {
+	coded := output.NewError(output.CodeNotFound, "Context 'nope' not found.")
+	if err := usageError(runCmd, coded); err != error(coded) {
+		t.Errorf("usageError changed a coded error: %v", err)
+	}
+
+	err := usageError(runCmd, errors.New("unknown flag: --bogus"))
+	if output.Code(err) != output.CodeInvalidArgument {
+		t.Errorf("code = %q, want %q", output.Code(err), output.CodeInvalidArgument)
+	}
+	if !strings.HasPrefix(err.Error(), "unknown flag: --bogus\nUsage:") {
+		t.Errorf("flag error not followed by usage: %q", err)
+	}
+
+	err = usageError(rootCmd, errors.New(`unknown command "logs" for "cyfr"`))
+	if want := `unknown command "logs" for "cyfr"` + "\nRun 'cyfr --help' for usage."; err.Error() != want {
+		t.Errorf("usageError = %q, want %q", err, want)
+	}
+}
+
+// TestNoShadowedPersistentFlags fails when a command defines a flag whose
+// name or shorthand is already a persistent flag of an ancestor: cobra
+// lets the local flag win, so the global one silently stops working there.
+func TestNoShadowedPersistentFlags(t *testing.T) {
+	var walk func(cmd *cobra.Command, inherited []*pflag.Flag)
+	walk = func(cmd *cobra.Command, inherited []*pflag.Flag) {
+		check := func(f *pflag.Flag) {
+			for _, p := range inherited {
+				if f == p {
+					continue
+				}
+				if f.Name == p.Name {
+					t.Errorf("%s: flag --%s shadows the persistent --%s", cmd.CommandPath(), f.Name, p.Name)
+				}
+				if f.Shorthand != "" && f.Shorthand == p.Shorthand {
+					t.Errorf("%s: flag --%s takes -%s from the persistent --%s", cmd.CommandPath(), f.Name, f.Shorthand, p.Name)
+				}
+			}
+		}
+		cmd.Flags().VisitAll(check)
+		cmd.PersistentFlags().VisitAll(check)
+
+		next := append([]*pflag.Flag(nil), inherited...)
+		cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) { next = append(next, f) })
+		for _, sub := range cmd.Commands() {
+			walk(sub, next)
+		}
+	}
+	walk(rootCmd, nil)
+}
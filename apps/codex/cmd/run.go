@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/cyfr/codex/internal/history"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/cyfr/codex/internal/ref"
 
@@ -32,62 +37,76 @@ func joinTypeShorthand(args []string) []string {
 // is handled server-side by Sanctum.ComponentRef.
 //
 // Normalizations performed:
-//   - Local .wasm files → {"local": relative_path}
-//   - "@" version separator → ":" (input convenience)
-//   - --type flag injection when ref has no type prefix
+//   - Local .wasm files → {"local": relative_path}; a component's version
+//     directory in the components/ layout runs its {type}.wasm
+//   - "@" version separator → ":" (input convenience); a trailing
+//     "@sha256:..." digest pin is kept for the caller to verify
+//   - --type flag (or the project's default type) injection when ref has
+//     no type prefix
+//   - References starting with a registry host (ghcr.io/acme/foo:1.0.0)
+//     → {"oci": raw_string}
 //   - Everything else passes through as {"registry": raw_string}
-func parseReference(rawRef string, compType string) map[string]any {
-	// Local file references (ends in .wasm or starts with ./ or /)
-	if strings.HasSuffix(rawRef, ".wasm") || strings.HasPrefix(rawRef, "./") || strings.HasPrefix(rawRef, "/") {
+func parseReference(rawRef string, compType string) (map[string]any, error) {
+	// Local file references (ends in .wasm, starts with ./ ../ or /, or is
+	// a directory)
+	if isComponentPath(rawRef) {
 		absPath, err := filepath.Abs(rawRef)
 		if err != nil {
-			output.Errorf("Failed to resolve path: %v", err)
-			return nil
+			return nil, output.Errorf("Failed to resolve path: %v", err)
 		}
-		if _, err := os.Stat(absPath); err != nil {
-			output.Errorf("Component not found at %s", absPath)
-			return nil
+		info, err := os.Stat(absPath)
+		if err != nil {
+			return nil, output.NewError(output.CodeNotFound, "Component not found at %s", absPath)
+		}
+		if info.IsDir() {
+			// A component's version directory runs its {type}.wasm.
+			r, _, err := pathComponentRef(rawRef)
+			if err != nil {
+				return nil, err
+			}
+			absPath = filepath.Join(absPath, r.Type+".wasm")
+			if _, err := os.Stat(absPath); err != nil {
+				return nil, output.NewError(output.CodeNotFound, "Component not found at %s", absPath)
+			}
 		}
 		cwd, err := os.Getwd()
 		if err != nil {
-			output.Errorf("Failed to determine working directory: %v", err)
-			return nil
+			return nil, output.Errorf("Failed to determine working directory: %v", err)
 		}
 		relPath, err := filepath.Rel(cwd, absPath)
 		if err != nil || strings.HasPrefix(relPath, "..") {
-			output.Errorf("Local path %s is outside the project directory. Local components must be within the project tree.", absPath)
-			return nil
+			return nil, output.Errorf("Local path %s is outside the project directory. Local components must be within the project tree.", absPath)
 		}
-		return map[string]any{"local": relPath}
+		return map[string]any{"local": relPath}, nil
 	}
 
 	// Registry references with @ version separator → normalize to colon
-	if strings.Contains(rawRef, "@") {
-		rawRef = strings.Replace(rawRef, "@", ":", 1)
-	}
-
-	// If the ref already has a type prefix, pass through as-is
-	if colonIdx := strings.Index(rawRef, ":"); colonIdx >= 0 {
-		firstPart := rawRef[:colonIdx]
-		if !strings.Contains(firstPart, ".") && ref.IsTypePrefix(firstPart) {
-			return map[string]any{"registry": rawRef}
-		}
+	rawRef, err := expandComponentRef(rawRef)
+	if err != nil {
+		return nil, err
 	}
 
-	// If --type flag given and ref has no type prefix, prepend it
-	if compType != "" {
-		rawRef = compType + ":" + rawRef
+	key := "registry"
+	if _, ok := registryRef(rawRef); ok {
+		key = "oci"
 	}
 
-	return map[string]any{"registry": rawRef}
+	// A ref without a type prefix takes --type, or else the project's
+	// default type
+	return map[string]any{key: withDefaultType(rawRef, compType)}, nil
 }
 
 func init() {
 	runCmd.Flags().Bool("list", false, "List running executions")
+	addColumnsFlag(runCmd)
 	runCmd.Flags().String("logs", "", "View execution logs")
 	runCmd.Flags().String("cancel", "", "Cancel a running execution")
 	runCmd.Flags().String("input", "", "JSON input for execution")
 	runCmd.Flags().String("type", "", "Component type: catalyst, reagent, or formula")
+	runCmd.Flags().Bool("profile", false, "Request and print execution timing and resource metrics")
+	runCmd.Flags().Bool("follow", false, "Stream progress and log notifications to stderr while the run executes")
+	runCmd.Flags().Bool("dry-run", false, "Show what would run (artifact, policy, granted secrets, tool call) without executing")
+	addContentFlags(runCmd)
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -98,72 +117,97 @@ var runCmd = &cobra.Command{
 	Long: `Execute a component by reference. The type can be specified as a prefix
 (catalyst:, c:, reagent:, r:, formula:, f:) or as a separate first argument.
 
+The version may be a constraint such as ^1.2, ~1.4.0 or ">=1.0.0 <2.0.0"
+(quoted); it is resolved to the highest matching published version. A
+reference pinned with @sha256:<digest> only runs if the registry's artifact
+has that digest, and the digest the server reports executing is checked too.
+A reference starting with a registry host, such as ghcr.io/acme/sentiment:1.0.0,
+is run from that OCI registry. A local .wasm file, or a component's version
+directory such as components/catalysts/local/openai/0.1.0/, runs that
+artifact directly.
+
+If the context's verify_signatures is enforce, the component's digest and
+signature are checked against its trusted keys first, as 'cyfr verify'
+does, and a component that fails is refused; with warn, it runs with a
+warning. Either way the run is then pinned to the digest that was checked.
+
 Pass --input to supply a JSON object as execution input. Use --list to see
-running executions, --logs to stream output, and --cancel to abort.`,
+running executions, --logs to stream output, and --cancel to abort.
+
+With --profile, the server reports queue time, WASM instantiation time,
+CPU time, peak memory, and outbound HTTP calls for the run. The metrics are
+printed after the result and included under "profile" in --json output.
+
+With --follow, progress and log notifications sent by the server while the
+component runs are printed to stderr as they arrive. Without it, progress
+reported by the server is drawn as a progress bar on stderr.
+
+With --dry-run, nothing is executed. Instead the reference is resolved and
+the artifact, effective policy, granted secret names, and the exact
+execution tool call are printed — useful for working out why a run was
+denied.
+
+Images and audio returned by a component are saved to files in --output-dir
+(base64 inline with --json). Resources it links to are listed; add
+--fetch-resources to download them too.
+
+Every run is recorded in ~/.cyfr/history.db; see 'cyfr history'.`,
 	Example: `  cyfr run c:local.openai
   cyfr run c:local.openai:0.1.0
   cyfr run c local.openai
   cyfr run catalyst:local.openai
   cyfr run local.openai --type catalyst
   cyfr run cyfr.sentiment:1.0.0
+  cyfr run c:acme.sentiment:^1.2
+  cyfr run c:acme.sentiment@sha256:93a44bbb...
   cyfr run ./path/to/catalyst.wasm
+  cyfr run components/catalysts/local/openai/0.1.0/
   cyfr run c:local.openai --input '{"text":"hello"}'
+  cyfr run c:local.openai --profile
+  cyfr run c:local.openai --follow
+  cyfr run c:local.openai --dry-run
+  cyfr run c:local.chart --output-dir ./charts
   cyfr run --list
   cyfr run --logs exec_abc123
   cyfr run --cancel exec_abc123`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
 
 		if listFlag, _ := cmd.Flags().GetBool("list"); listFlag {
-			result, err := client.CallTool("execution", map[string]any{
-				"action": "list",
+			result, err := typed.New(client).Execution(cmd.Context(), typed.ExecutionArgs{
+				Action: typed.ExecutionActionList,
 			})
 			if err != nil {
-				output.Error(err.Error())
+				return toolError(err)
 			}
-			if flagJSON {
-				output.JSON(result)
-			} else {
-				output.KeyValue(result)
-			}
-			return
+			return printList(cmd, result, "executions")
 		}
 
 		if logsID, _ := cmd.Flags().GetString("logs"); logsID != "" {
-			result, err := client.CallTool("execution", map[string]any{
-				"action":       "logs",
-				"execution_id": logsID,
-			})
-			if err != nil {
-				output.Error(err.Error())
-			}
-			if flagJSON {
-				output.JSON(result)
-			} else {
-				output.KeyValue(result)
-			}
-			return
+			return executionLogs(cmd.Context(), client, logsID)
 		}
 
 		if cancelID, _ := cmd.Flags().GetString("cancel"); cancelID != "" {
-			result, err := client.CallTool("execution", map[string]any{
-				"action":       "cancel",
-				"execution_id": cancelID,
+			result, err := typed.New(client).Execution(cmd.Context(), typed.ExecutionArgs{
+				Action:      typed.ExecutionActionCancel,
+				ExecutionID: cancelID,
 			})
 			if err != nil {
-				output.Error(err.Error())
+				return toolError(err)
 			}
 			if flagJSON {
 				output.JSON(result)
 			} else {
 				fmt.Println("Execution cancelled.")
 			}
-			_ = result
-			return
+			return nil
 		}
 
 		if len(args) < 1 {
-			output.Error("Usage: cyfr run <reference>")
+			return output.NewError(output.CodeInvalidArgument, "Usage: cyfr run <reference>")
 		}
 
 		// CLI shorthand: "cyfr run c local.claude:0.1.0" → join as "c:local.claude:0.1.0"
@@ -175,29 +219,336 @@ running executions, --logs to stream output, and --cancel to abort.`,
 		// embedded in the reference string — the server extracts it
 		// from the reference via Sanctum.ComponentRef.parse/1.
 		rawRef := args[0]
-		refMap := parseReference(rawRef, compType)
-		toolArgs := map[string]any{
-			"action":    "run",
-			"reference": refMap,
+		refMap, err := parseReference(rawRef, compType)
+		if err != nil {
+			return err
+		}
+		var pinned string
+		for _, key := range []string{"registry", "oci"} {
+			reference, ok := refMap[key].(string)
+			if !ok {
+				continue
+			}
+			resolved, err := resolveConstraint(cmd.Context(), client, reference)
+			if err != nil {
+				return err
+			}
+			if refMap[key], pinned, err = unpinReference(resolved); err != nil {
+				return err
+			}
 		}
 
+		var input map[string]any
 		if inputStr, _ := cmd.Flags().GetString("input"); inputStr != "" {
-			var input map[string]any
 			if err := json.Unmarshal([]byte(inputStr), &input); err != nil {
-				output.Errorf("Invalid JSON input: %v", err)
+				return output.NewError(output.CodeInvalidArgument, "Invalid JSON input: %v", err)
 			}
-			toolArgs["input"] = input
 		}
 
-		result, err2 := client.CallTool("execution", toolArgs)
-		if err2 != nil {
-			output.Error(err2.Error())
+		profile, _ := cmd.Flags().GetBool("profile")
+		opts := runOptions{Profile: profile, Digest: pinned, Content: contentOptionsFromFlags(cmd, "output")}
+		if ociRef, ok := refMap["oci"].(string); ok && pinned != "" {
+			err = verifyOCIPinned(cmd.Context(), ociRef, pinned)
+		} else if pinned != "" {
+			err = verifyPinned(cmd.Context(), client, refMap["registry"].(string), pinned)
 		}
-
-		if flagJSON {
-			output.JSON(result)
+		if err != nil {
+			return err
+		}
+		for _, key := range []string{"local", "registry", "oci"} {
+			if reference, ok := refMap[key].(string); ok {
+				checked, err := checkBeforeUse(cmd.Context(), client, reference)
+				if err != nil {
+					return err
+				}
+				if opts.Digest == "" {
+					opts.Digest = checked
+				}
+			}
+		}
+		if follow, _ := cmd.Flags().GetBool("follow"); follow {
+			client.OnNotification = printNotification
 		} else {
-			output.KeyValue(result)
+			opts.Progress = true
+		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			dryRunExecution(cmd.Context(), client, refMap, input, opts)
+			return nil
 		}
+		return executeRun(cmd.Context(), client, refMap, input, opts)
 	},
 }
+
+// runOptions controls how executeRun performs and reports a run.
+type runOptions struct {
+	// Profile requests execution metrics and prints them after the result.
+	Profile bool
+	// Progress shows server progress notifications as a progress bar.
+	Progress bool
+	// Digest is the artifact digest the reference is pinned to, if any;
+	// the run fails if the server reports executing anything else.
+	Digest string
+	// Content controls how images, audio and resources in the result are
+	// saved or printed.
+	Content contentOptions
+}
+
+// trackExecutionID wraps the client's notification and progress handlers
+// to remember the first execution ID either of them carries, which may
+// arrive before the result, so an interrupted run can be cancelled. Call it
+// after the handlers are installed; the returned function reports the ID.
+func trackExecutionID(client *mcp.Client) func() string {
+	var executionID string
+	capture := func(params map[string]any) {
+		if id, ok := params["execution_id"].(string); ok && executionID == "" {
+			executionID = id
+		}
+	}
+	notify := client.OnNotification
+	client.OnNotification = func(n mcp.Notification) {
+		capture(n.Params)
+		if notify != nil {
+			notify(n)
+		}
+	}
+	if progress := client.OnProgress; progress != nil {
+		client.OnProgress = func(p mcp.Progress) {
+			capture(p.Params)
+			progress(p)
+		}
+	}
+	return func() string { return executionID }
+}
+
+// executeRun runs a component, records the invocation in the local history
+// journal, and prints the result.
+func executeRun(ctx context.Context, client *mcp.Client, refMap map[string]any, input map[string]any, opts runOptions) error {
+	toolArgs := buildRunArgs(refMap, input, opts)
+
+	done := func() {}
+	if opts.Progress && !flagQuiet {
+		done = showProgress(client, "Running")
+	}
+	executionID := trackExecutionID(client)
+	start := time.Now()
+	result, err := typed.New(client).Execution(ctx, toolArgs)
+	elapsed := time.Since(start)
+	done()
+	recordHistory(client, refMap, input, result, err, elapsed)
+	if isInterrupted(err) {
+		cancelInterruptedRun(client, refMap, executionID(), start)
+		return errInterrupted()
+	}
+	if err != nil {
+		registryRef, _ := refMap["registry"].(string)
+		coded := toolError(err)
+		coded.Message += didYouMean(registryRef, err)
+		return coded
+	}
+	if executed, _ := result["component_digest"].(string); opts.Digest != "" && executed != "" {
+		reference, ok := refMap["registry"]
+		if !ok {
+			reference = refMap["oci"]
+		}
+		if err := checkDigest(fmt.Sprint(reference), opts.Digest, executed); err != nil {
+			return err
+		}
+	}
+
+	if opts.Profile {
+		profile, _ := result["profile"].(map[string]any)
+		if profile == nil {
+			profile = map[string]any{}
+		}
+		profile["round_trip_ms"] = elapsed.Milliseconds()
+		result["profile"] = profile
+	}
+
+	if flagQuiet {
+		if id, _ := result["execution_id"].(string); id != "" {
+			fmt.Println(id)
+		}
+		return nil
+	}
+	if flagJSON {
+		prepareContentJSON(ctx, client, result, opts.Content)
+		output.JSON(result)
+		return nil
+	}
+
+	extra := mcp.ExtraContent(result)
+	if !opts.Profile {
+		output.KeyValue(result)
+		return printExtraContent(ctx, client, extra, opts.Content)
+	}
+	profile := result["profile"].(map[string]any)
+	delete(result, "profile")
+	output.KeyValue(result)
+	if err := printExtraContent(ctx, client, extra, opts.Content); err != nil {
+		return err
+	}
+	fmt.Println("")
+	fmt.Println("Profile:")
+	printProfile(profile)
+	return nil
+}
+
+// buildRunArgs builds the execution tool arguments for a run.
+func buildRunArgs(refMap map[string]any, input map[string]any, opts runOptions) typed.ExecutionArgs {
+	return typed.ExecutionArgs{
+		Action:    typed.ExecutionActionRun,
+		Reference: refMap,
+		Input:     input,
+		Profile:   opts.Profile,
+	}
+}
+
+// profileFields lists the well-known profile metrics in display order.
+var profileFields = []struct {
+	key, label string
+	bytes      bool
+}{
+	{"queue_ms", "Queue time", false},
+	{"instantiation_ms", "Instantiation", false},
+	{"cpu_ms", "CPU time", false},
+	{"duration_ms", "Execution time", false},
+	{"round_trip_ms", "Round trip", false},
+	{"memory_peak_bytes", "Memory peak", true},
+	{"http_calls", "HTTP calls", false},
+}
+
+// printProfile prints execution metrics as an aligned section. Known metrics
+// come first with units; anything else the server reports follows as-is.
+func printProfile(profile map[string]any) {
+	seen := map[string]bool{}
+	for _, f := range profileFields {
+		v, ok := profile[f.key]
+		if !ok {
+			continue
+		}
+		seen[f.key] = true
+		switch {
+		case f.bytes:
+			if n, ok := v.(float64); ok {
+				v = output.HumanBytes(int64(n))
+			}
+		case strings.HasSuffix(f.key, "_ms"):
+			v = fmt.Sprintf("%vms", v)
+		}
+		if calls, ok := v.([]any); ok {
+			fmt.Printf("  %-18s %d\n", f.label+":", len(calls))
+			for _, c := range calls {
+				fmt.Printf("  %-18s %v\n", "", c)
+			}
+			continue
+		}
+		fmt.Printf("  %-18s %v\n", f.label+":", v)
+	}
+
+	rest := map[string]any{}
+	for k, v := range profile {
+		if !seen[k] {
+			rest[k] = v
+		}
+	}
+	if len(rest) > 0 {
+		output.KeyValue(rest)
+	}
+}
+
+// recordHistory appends a run to the history journal. Failures are ignored:
+// the journal is a convenience and must never break a run.
+func recordHistory(client *mcp.Client, refMap, input, result map[string]any, runErr error, elapsed time.Duration) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return
+	}
+	entry := &history.Entry{
+		Time:       time.Now().UTC(),
+		URL:        client.BaseURL,
+		Reference:  refMap,
+		Input:      input,
+		DurationMS: elapsed.Milliseconds(),
+	}
+	if isInterrupted(runErr) {
+		entry.Status = "interrupted"
+	} else if runErr != nil {
+		entry.Status = "error"
+		entry.Error = runErr.Error()
+	} else {
+		entry.ExecutionID, _ = result["execution_id"].(string)
+		entry.Status, _ = result["status"].(string)
+	}
+	_ = history.Append(path, entry)
+}
+
+// interruptCancelTimeout bounds the cleanup done after Ctrl-C.
+const interruptCancelTimeout = 5 * time.Second
+
+// cancelInterruptedRun asks the server to cancel a run abandoned by Ctrl-C,
+// so it doesn't keep executing with nobody waiting for the result. The
+// execution ID is used if a notification revealed it; otherwise the running
+// executions are searched for the one this run started.
+func cancelInterruptedRun(client *mcp.Client, refMap map[string]any, executionID string, start time.Time) {
+	ctx, cancel := mcp.WithTimeout(context.Background(), interruptCancelTimeout)
+	defer cancel()
+
+	if executionID == "" {
+		result, err := typed.New(client).Execution(ctx, typed.ExecutionArgs{
+			Action: typed.ExecutionActionList,
+			Status: "running",
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not look up the interrupted execution: %v\n", err)
+			return
+		}
+		executionID = interruptedExecutionID(result, refMap, start)
+		if executionID == "" {
+			return
+		}
+	}
+
+	if _, err := typed.New(client).Execution(ctx, typed.ExecutionArgs{
+		Action:      typed.ExecutionActionCancel,
+		ExecutionID: executionID,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not cancel execution %s: %v\n", executionID, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Cancelled execution %s.\n", executionID)
+}
+
+// interruptClockSkew is how far a server's started_at may precede the
+// client's own start time and still count as the same run.
+const interruptClockSkew = 30 * time.Second
+
+// interruptedExecutionID finds the running execution started by an
+// interrupted run: same reference, started no earlier than start (allowing
+// for clock skew). It returns "" unless exactly one execution matches, so a
+// concurrent run of the same component is never cancelled by mistake.
+func interruptedExecutionID(result map[string]any, refMap map[string]any, start time.Time) string {
+	want := referenceString(refMap)
+	executions, _ := result["executions"].([]any)
+
+	var match string
+	for _, e := range executions {
+		exec, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		reference, _ := exec["reference"].(map[string]any)
+		if referenceString(reference) != want {
+			continue
+		}
+		startedAt, _ := exec["started_at"].(string)
+		t, err := time.Parse(time.RFC3339, startedAt)
+		if err != nil || t.Before(start.Add(-interruptClockSkew)) {
+			continue
+		}
+		if match != "" {
+			return ""
+		}
+		match, _ = exec["execution_id"].(string)
+	}
+	return match
+}
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
 )
 
 func TestParseReference_LocalRef_ReturnsRegistry(t *testing.T) {
@@ -21,7 +29,7 @@ func TestParseReference_LocalRef_ReturnsRegistry(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := parseReference(tt.input, tt.compType)
+			result, _ := parseReference(tt.input, tt.compType)
 			if result == nil {
 				t.Fatal("expected non-nil result")
 			}
@@ -51,7 +59,7 @@ func TestParseReference_DirectWasm_ReturnsRelativePath(t *testing.T) {
 	t.Cleanup(func() { os.Chdir(origDir) })
 	os.Chdir(tmp)
 
-	result := parseReference("./components/catalysts/local/claude/0.1.0/catalyst.wasm", "catalyst")
+	result, _ := parseReference("./components/catalysts/local/claude/0.1.0/catalyst.wasm", "catalyst")
 	if result == nil {
 		t.Fatal("expected non-nil result")
 	}
@@ -68,6 +76,31 @@ func TestParseReference_DirectWasm_ReturnsRelativePath(t *testing.T) {
 	}
 }
 
+func TestParseReference_ComponentDirectory(t *testing.T) {
+	dir := chdirTemp(t)
+	wasmDir := filepath.Join(dir, "components", "catalysts", "local", "claude", "0.1.0")
+	if err := os.MkdirAll(wasmDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wasmDir, "catalyst.wasm"), []byte("fake"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join("components", "catalysts", "local", "claude", "0.1.0", "catalyst.wasm")
+	for _, path := range []string{"components/catalysts/local/claude/0.1.0", "./components/catalysts/local/claude/0.1.0/"} {
+		if got, _ := parseReference(path, ""); got["local"] != want {
+			t.Errorf("parseReference(%q) = %v, want local %s", path, got, want)
+		}
+	}
+
+	// Elsewhere a path stands for the component's reference.
+	for _, path := range []string{"components/catalysts/local/claude/0.1.0/", "components/catalysts/local/claude/0.1.0/catalyst.wasm"} {
+		if got, _ := normalizeComponentRef(path); got != "catalyst:local.claude:0.1.0" {
+			t.Errorf("normalizeComponentRef(%q) = %q", path, got)
+		}
+	}
+}
+
 func TestParseReference_RegistryRefWithTypeInjected(t *testing.T) {
 	tests := []struct {
 		input string
@@ -78,7 +111,7 @@ func TestParseReference_RegistryRefWithTypeInjected(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := parseReference(tt.input, "catalyst")
+			result, _ := parseReference(tt.input, "catalyst")
 			if result == nil {
 				t.Fatal("expected non-nil result")
 			}
@@ -93,21 +126,9 @@ func TestParseReference_RegistryRefWithTypeInjected(t *testing.T) {
 	}
 }
 
-// The following tests verify error paths. Since output.Errorf calls os.Exit(1),
-// we test these by re-invoking the test binary as a subprocess.
-
 func TestParseReference_DirectWasm_OutsideProject(t *testing.T) {
-	if os.Getenv("TEST_SUBPROCESS") == "1" {
-		// Subprocess: create a wasm file outside the working directory.
-		outsideDir := os.Getenv("TEST_OUTSIDE_DIR")
-		cwd := os.Getenv("TEST_CWD")
-		os.Chdir(cwd)
-		parseReference(filepath.Join(outsideDir, "outside.wasm"), "catalyst")
-		return
-	}
-
-	// Create two separate temp dirs: "project" (cwd) and "outside".
-	projectDir := t.TempDir()
+	// Two separate temp dirs: "project" (cwd) and "outside".
+	chdirTemp(t)
 	outsideDir := t.TempDir()
 
 	wasmFile := filepath.Join(outsideDir, "outside.wasm")
@@ -115,35 +136,22 @@ func TestParseReference_DirectWasm_OutsideProject(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cmd := exec.Command(os.Args[0], "-test.run=^TestParseReference_DirectWasm_OutsideProject$")
-	cmd.Env = append(os.Environ(),
-		"TEST_SUBPROCESS=1",
-		"TEST_OUTSIDE_DIR="+outsideDir,
-		"TEST_CWD="+projectDir,
-	)
-	out, err := cmd.CombinedOutput()
+	_, err := parseReference(wasmFile, "catalyst")
 	if err == nil {
-		t.Fatal("expected subprocess to exit with error")
+		t.Fatal("expected an error for a file outside the project")
 	}
-	if !strings.Contains(string(out), "outside the project directory") {
-		t.Errorf("expected 'outside the project directory' in output, got: %s", out)
+	if !strings.Contains(err.Error(), "outside the project directory") {
+		t.Errorf("expected 'outside the project directory' in error, got: %v", err)
 	}
 }
 
 func TestParseReference_DirectWasm_Nonexistent(t *testing.T) {
-	if os.Getenv("TEST_SUBPROCESS") == "1" {
-		parseReference("./nonexistent.wasm", "catalyst")
-		return
-	}
-
-	cmd := exec.Command(os.Args[0], "-test.run=^TestParseReference_DirectWasm_Nonexistent$")
-	cmd.Env = append(os.Environ(), "TEST_SUBPROCESS=1")
-	out, err := cmd.CombinedOutput()
+	_, err := parseReference("./nonexistent.wasm", "catalyst")
 	if err == nil {
-		t.Fatal("expected subprocess to exit with error")
+		t.Fatal("expected an error for a missing file")
 	}
-	if !strings.Contains(string(out), "Component not found") {
-		t.Errorf("expected 'Component not found' in output, got: %s", out)
+	if !strings.Contains(err.Error(), "Component not found") {
+		t.Errorf("expected 'Component not found' in error, got: %v", err)
 	}
 }
 
@@ -219,7 +227,7 @@ func TestParseReference_TypeInjection(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseReference(tt.input, tt.compType)
+			result, _ := parseReference(tt.input, tt.compType)
 			if result == nil {
 				t.Fatal("expected non-nil result")
 			}
@@ -233,3 +241,103 @@ func TestParseReference_TypeInjection(t *testing.T) {
 		})
 	}
 }
+
+func TestInterruptedExecutionID(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	refMap := map[string]any{"registry": "catalyst:local.claude:0.1.0"}
+	execution := func(id, registry, startedAt string) map[string]any {
+		return map[string]any{
+			"execution_id": id,
+			"reference":    map[string]any{"registry": registry},
+			"started_at":   startedAt,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		executions []any
+		want       string
+	}{
+		{
+			name: "single match",
+			executions: []any{
+				execution("exec_1", "catalyst:local.claude:0.1.0", "2026-01-01T12:00:01Z"),
+				execution("exec_2", "reagent:local.parser:1.0.0", "2026-01-01T12:00:01Z"),
+			},
+			want: "exec_1",
+		},
+		{
+			name: "started before this run",
+			executions: []any{
+				execution("exec_1", "catalyst:local.claude:0.1.0", "2026-01-01T11:00:00Z"),
+			},
+			want: "",
+		},
+		{
+			name: "ambiguous",
+			executions: []any{
+				execution("exec_1", "catalyst:local.claude:0.1.0", "2026-01-01T12:00:01Z"),
+				execution("exec_2", "catalyst:local.claude:0.1.0", "2026-01-01T12:00:02Z"),
+			},
+			want: "",
+		},
+		{
+			name:       "none running",
+			executions: []any{},
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := map[string]any{"executions": tt.executions}
+			if got := interruptedExecutionID(result, refMap, start); got != tt.want {
+				t.Errorf("interruptedExecutionID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackExecutionID_Progress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID int `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":1,"progress":1,"execution_id":"exec_123"}}`+"\n\n")
+		resp, _ := json.Marshal(mcp.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]any{}})
+		io.WriteString(w, "data: "+string(resp)+"\n\n")
+	}))
+	defer srv.Close()
+
+	client := mcp.NewClient(srv.URL)
+	var updates int
+	client.OnProgress = func(mcp.Progress) { updates++ }
+	executionID := trackExecutionID(client)
+
+	if _, err := client.CallTool("execution", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if got := executionID(); got != "exec_123" {
+		t.Errorf("executionID() = %q, want exec_123", got)
+	}
+	if updates != 1 {
+		t.Errorf("progress handler called %d times, want 1", updates)
+	}
+}
+
+func TestExecuteRun_ErrorCode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-33301,"message":"session required"}}`)
+	}))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	err := executeRun(context.Background(), client, map[string]any{"registry": "c:local.claude:0.1.0"}, nil, runOptions{})
+	if output.Code(err) != output.CodeNotLoggedIn || !strings.Contains(err.Error(), "cyfr login") {
+		t.Errorf("err = %v (code %s), want %s", err, output.Code(err), output.CodeNotLoggedIn)
+	}
+}
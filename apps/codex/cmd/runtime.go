@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/cyfr/codex/internal/output"
+)
+
+// containerRuntimes are the container runtimes a project can run its
+// server with, in the order they are looked for when cyfr.yaml doesn't
+// name one. Colima is only used when named: it serves the docker CLI
+// through its own Docker context.
+var containerRuntimes = []string{"docker", "podman", "nerdctl", "colima"}
+
+// serverImage is the image of the CYFR server.
+const serverImage = "ghcr.io/cyfrworks/cyfr:latest"
+
+// containerRuntime is the CLI of a container runtime, with the compose
+// command the project's server is run through.
+type containerRuntime struct {
+	Name        string   // as in cyfr.yaml
+	bin         string   // the CLI
+	args        []string // global flags before any command
+	composeArgs []string // flags of compose, e.g. the file and project of an environment
+}
+
+// runtimeNamed returns the runtime called name, one of containerRuntimes.
+func runtimeNamed(name string) containerRuntime {
+	switch name {
+	case "colima":
+		return containerRuntime{Name: name, bin: "docker", args: []string{"--context", "colima"}}
+	default:
+		return containerRuntime{Name: name, bin: name}
+	}
+}
+
+// command returns a command running the runtime's CLI with args.
+func (r containerRuntime) command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, r.bin, append(append([]string{}, r.args...), args...)...)
+}
+
+// compose returns a command running the runtime's compose with args on the
+// project in the current directory.
+func (r containerRuntime) compose(ctx context.Context, args ...string) *exec.Cmd {
+	return r.command(ctx, append(append([]string{"compose"}, r.composeArgs...), args...)...)
+}
+
+// composeHint returns how users run compose themselves, for messages.
+func (r containerRuntime) composeHint(args string) string {
+	return strings.Join(append(append([]string{r.bin}, r.args...), "compose", args), " ")
+}
+
+// configuredRuntime returns the runtime the project's cyfr.yaml names, or
+// "" if it names none or there is no cyfr.yaml.
+func configuredRuntime() string {
+	return cyfrYAMLValue("runtime")
+}
+
+// checkRuntimeName returns an error unless name is one of
+// containerRuntimes, or "" to look for one.
+func checkRuntimeName(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, r := range containerRuntimes {
+		if name == r {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown container runtime %q (use %s)", name, strings.Join(containerRuntimes, ", "))
+}
+
+// findRuntime returns the runtime called name if its CLI is installed, or
+// with name "" the first installed one.
+func findRuntime(name string) (containerRuntime, error) {
+	if err := checkRuntimeName(name); err != nil {
+		return containerRuntime{}, err
+	}
+	if name != "" {
+		r := runtimeNamed(name)
+		if _, err := exec.LookPath(r.bin); err != nil {
+			return containerRuntime{}, fmt.Errorf("the project uses %s, but %s isn't installed or isn't on PATH", name, r.bin)
+		}
+		return r, nil
+	}
+	for _, name := range containerRuntimes {
+		if name == "colima" {
+			continue
+		}
+		if _, err := exec.LookPath(name); err == nil {
+			return runtimeNamed(name), nil
+		}
+	}
+	return containerRuntime{}, fmt.Errorf("no container runtime found: install Docker, Podman or nerdctl, or set runtime: in cyfr.yaml")
+}
+
+// projectRuntime returns the runtime of the project in the current
+// directory: the one its cyfr.yaml names, or else the first installed.
+func projectRuntime() (containerRuntime, error) {
+	r, err := findRuntime(configuredRuntime())
+	if err != nil {
+		return r, output.Errorf("Can't run the server: %v.", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindRuntime(t *testing.T) {
+	// Only podman and colima are installed.
+	bin := t.TempDir()
+	for _, name := range []string{"podman", "colima"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+
+	r, err := findRuntime("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "podman" {
+		t.Errorf("found %s, want podman", r.Name)
+	}
+	if _, err := findRuntime("nerdctl"); err == nil || !strings.Contains(err.Error(), "nerdctl isn't installed") {
+		t.Errorf("findRuntime(nerdctl) error = %v", err)
+	}
+	// Colima runs through the docker CLI, which isn't installed.
+	if _, err := findRuntime("colima"); err == nil || !strings.Contains(err.Error(), "docker isn't installed") {
+		t.Errorf("findRuntime(colima) error = %v", err)
+	}
+	if _, err := findRuntime("lxc"); err == nil || !strings.Contains(err.Error(), "unknown container runtime") {
+		t.Errorf("findRuntime(lxc) error = %v", err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := findRuntime(""); err == nil || !strings.Contains(err.Error(), "no container runtime found") {
+		t.Errorf("findRuntime without runtimes error = %v", err)
+	}
+}
+
+func TestConfiguredRuntime(t *testing.T) {
+	chdirTemp(t)
+	if got := configuredRuntime(); got != "" {
+		t.Errorf("without cyfr.yaml, runtime = %q", got)
+	}
+	opts := defaultInitOptions()
+	opts.Runtime = "colima"
+	if err := os.WriteFile("cyfr.yaml", []byte(opts.cyfrYAML()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := configuredRuntime(); got != "colima" {
+		t.Errorf("runtime = %q, want colima", got)
+	}
+
+	c := runtimeNamed("colima").compose(context.Background(), "up", "-d")
+	if got := strings.Join(c.Args, " "); got != "docker --context colima compose up -d" {
+		t.Errorf("colima compose runs %q", got)
+	}
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cyfr/codex/internal/cron"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleCreateCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(schedulePauseCmd)
+	scheduleCmd.AddCommand(scheduleResumeCmd)
+	scheduleCmd.AddCommand(scheduleDeleteCmd)
+
+	scheduleCreateCmd.Flags().String("cron", "", "Cron expression (required), e.g. \"0 * * * *\"")
+	scheduleCreateCmd.Flags().String("input", "", "JSON input for each execution")
+	scheduleCreateCmd.Flags().String("type", "", "Component type: catalyst, reagent, or formula")
+	scheduleCreateCmd.Flags().String("timezone", "", "IANA timezone the cron expression is evaluated in (default: UTC)")
+	scheduleCreateCmd.Flags().String("name", "", "Optional schedule name")
+	_ = scheduleCreateCmd.MarkFlagRequired("cron")
+
+	scheduleListCmd.Flags().String("timezone", "", "Render next-run times in this IANA timezone (default: schedule's own)")
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	Short:   "Manage scheduled executions",
+	GroupID: "exec",
+	Long:    "Run components on a recurring cron schedule managed by the CYFR server. Schedules use standard five-field cron syntax and can be paused, resumed, and deleted.",
+}
+
+var scheduleCreateCmd = &cobra.Command{
+	Use:   "create [type] <reference>",
+	Short: "Create a schedule",
+	Long:  "Schedule a component to run on a cron expression. The expression is validated locally before anything is sent to the server, and the next few run times are printed once the schedule is created.",
+	Example: `  cyfr schedule create c:local.claude:0.1.0 --cron "0 * * * *" --input '{"prompt":"hi"}'
+  cyfr schedule create f:acme.report:1.0.0 --cron "0 9 * * mon-fri" --timezone Europe/Berlin`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		args = joinTypeShorthand(args)
+		compType, _ := cmd.Flags().GetString("type")
+		expr, _ := cmd.Flags().GetString("cron")
+		inputStr, _ := cmd.Flags().GetString("input")
+		tz, _ := cmd.Flags().GetString("timezone")
+		name, _ := cmd.Flags().GetString("name")
+
+		sched, err := cron.Parse(expr)
+		if err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Invalid cron expression: %v", err)
+		}
+		loc, err := loadTimezone(tz)
+		if err != nil {
+			return err
+		}
+		reference, err := parseReference(args[0], compType)
+		if err != nil {
+			return err
+		}
+
+		toolArgs := typed.ScheduleArgs{
+			Action:    typed.ScheduleActionCreate,
+			Reference: reference,
+			Cron:      expr,
+			Timezone:  loc.String(),
+			Name:      name,
+		}
+		if inputStr != "" {
+			var input map[string]any
+			if err := json.Unmarshal([]byte(inputStr), &input); err != nil {
+				return output.NewError(output.CodeInvalidArgument, "Invalid JSON input: %v", err)
+			}
+			toolArgs.Input = input
+		}
+
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Schedule(cmd.Context(), toolArgs)
+		if err != nil {
+			return toolError(err)
+		}
+
+		next := sched.NextN(time.Now().In(loc), 3)
+		if flagJSON {
+			if _, ok := result["next_runs"]; !ok {
+				result["next_runs"] = formatTimes(next)
+			}
+			output.JSON(result)
+			return nil
+		}
+		output.KeyValue(result)
+		fmt.Println("")
+		fmt.Println("Next runs:")
+		for _, t := range next {
+			fmt.Printf("  %s\n", t.Format("2006-01-02 15:04 MST"))
+		}
+		return nil
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List schedules",
+	Long:  "List all schedules with their cron expression, status, and next run time.",
+	Example: `  cyfr schedule list
+  cyfr schedule list --timezone UTC`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tz, _ := cmd.Flags().GetString("timezone")
+		var displayLoc *time.Location
+		if tz != "" {
+			var err error
+			if displayLoc, err = loadTimezone(tz); err != nil {
+				return err
+			}
+		}
+
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Schedule(cmd.Context(), typed.ScheduleArgs{
+			Action: typed.ScheduleActionList,
+		})
+		if err != nil {
+			return toolError(err)
+		}
+
+		schedules, _ := result["schedules"].([]any)
+		now := time.Now()
+		for _, s := range schedules {
+			if m, ok := s.(map[string]any); ok {
+				if next := scheduleNextRun(m, displayLoc, now); next != "" {
+					m["next_run"] = next
+				}
+			}
+		}
+
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		if len(schedules) == 0 {
+			fmt.Println("No schedules.")
+			return nil
+		}
+
+		headers := []string{"ID", "NAME", "REFERENCE", "CRON", "TIMEZONE", "STATUS", "NEXT_RUN"}
+		rows := make([]map[string]string, 0, len(schedules))
+		for _, s := range schedules {
+			m, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			refMap, _ := m["reference"].(map[string]any)
+			rows = append(rows, map[string]string{
+				"ID":        fmt.Sprint(m["schedule_id"]),
+				"NAME":      stringField(m, "name"),
+				"REFERENCE": referenceString(refMap),
+				"CRON":      stringField(m, "cron"),
+				"TIMEZONE":  stringField(m, "timezone"),
+				"STATUS":    stringField(m, "status"),
+				"NEXT_RUN":  stringField(m, "next_run"),
+			})
+		}
+		output.Table(headers, rows)
+		return nil
+	},
+}
+
+var schedulePauseCmd = &cobra.Command{
+	Use:     "pause <schedule_id>",
+	Short:   "Pause a schedule",
+	Long:    "Stop a schedule from triggering new executions until it is resumed.",
+	Example: "  cyfr schedule pause sched_abc123",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scheduleAction(cmd.Context(), typed.ScheduleActionPause, args[0], "Schedule '%s' paused.\n")
+	},
+}
+
+var scheduleResumeCmd = &cobra.Command{
+	Use:     "resume <schedule_id>",
+	Short:   "Resume a paused schedule",
+	Long:    "Re-enable a paused schedule. Runs missed while paused are not back-filled.",
+	Example: "  cyfr schedule resume sched_abc123",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scheduleAction(cmd.Context(), typed.ScheduleActionResume, args[0], "Schedule '%s' resumed.\n")
+	},
+}
+
+var scheduleDeleteCmd = &cobra.Command{
+	Use:     "delete <schedule_id>",
+	Short:   "Delete a schedule",
+	Long:    "Permanently remove a schedule. Executions already started are not affected.",
+	Example: "  cyfr schedule delete sched_abc123",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return scheduleAction(cmd.Context(), typed.ScheduleActionDelete, args[0], "Schedule '%s' deleted.\n")
+	},
+}
+
+// scheduleAction performs a simple per-schedule action and prints msg on success.
+func scheduleAction(ctx context.Context, action typed.ScheduleAction, id, msg string) error {
+	client, err := newTypedClient()
+	if err != nil {
+		return err
+	}
+	result, err := client.Schedule(ctx, typed.ScheduleArgs{
+		Action:     action,
+		ScheduleID: id,
+	})
+	if err != nil {
+		return toolError(err)
+	}
+	if flagJSON {
+		output.JSON(result)
+	} else {
+		fmt.Printf(msg, id)
+	}
+	return nil
+}
+
+// scheduleNextRun returns the next run time for a schedule as reported by
+// the server, or computes it locally from the cron expression. Paused
+// schedules have no next run. When displayLoc is nil the schedule's own
+// timezone is used.
+func scheduleNextRun(s map[string]any, displayLoc *time.Location, now time.Time) string {
+	if status, _ := s["status"].(string); status == "paused" {
+		return ""
+	}
+
+	loc := time.UTC
+	if tz, _ := s["timezone"].(string); tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	if displayLoc == nil {
+		displayLoc = loc
+	}
+
+	if next, _ := s["next_run"].(string); next != "" {
+		if t, err := time.Parse(time.RFC3339, next); err == nil {
+			return t.In(displayLoc).Format("2006-01-02 15:04 MST")
+		}
+		return next
+	}
+
+	expr, _ := s["cron"].(string)
+	sched, err := cron.Parse(expr)
+	if err != nil {
+		return ""
+	}
+	t := sched.Next(now.In(loc))
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(displayLoc).Format("2006-01-02 15:04 MST")
+}
+
+// loadTimezone resolves an IANA timezone name, defaulting to UTC.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, output.NewError(output.CodeInvalidArgument, "Unknown timezone %q: %v", name, err)
+	}
+	return loc, nil
+}
+
+func formatTimes(times []time.Time) []string {
+	out := make([]string, len(times))
+	for i, t := range times {
+		out[i] = t.Format(time.RFC3339)
+	}
+	return out
+}
+
+// stringField returns m[key] as a string, or "" if it is missing or not a string.
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestMock_ScheduleCreate(t *testing.T) {
+	out := runCLI(t, "schedule", "create", "r:local.hello:0.1.0", "--cron", "0 * * * *", "--timezone", "UTC", "--input", `{"n":1}`, "--name", "hourly")
+	if !strings.Contains(out, "sched_mock_1") || !strings.Contains(out, "Next runs:") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	calls := mockServer.Calls()
+	last := calls[len(calls)-1]
+	if last.Tool != "schedule" || last.Args["action"] != "create" || last.Args["cron"] != "0 * * * *" || last.Args["timezone"] != "UTC" || last.Args["name"] != "hourly" {
+		t.Fatalf("unexpected call: %+v", last)
+	}
+	if input, _ := last.Args["input"].(map[string]any); input["n"] != float64(1) {
+		t.Errorf("input = %v", last.Args["input"])
+	}
+}
+
+func TestMock_ScheduleList(t *testing.T) {
+	out := runCLI(t, "schedule", "list")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") {
+		t.Fatalf("schedule list printed:\n%s", out)
+	}
+	// A paused schedule has no next run.
+	if row := strings.TrimSpace(lines[2]); !strings.HasPrefix(row, "sched_mock_1 ") || !strings.HasSuffix(row, " paused") {
+		t.Errorf("schedule list row: %q", lines[2])
+	}
+}
+
+func TestMock_ScheduleDelete(t *testing.T) {
+	if out := runCLI(t, "schedule", "delete", "sched_mock_1"); out != "Schedule 'sched_mock_1' deleted.\n" {
+		t.Errorf("schedule delete printed %q", out)
+	}
+	calls := mockServer.Calls()
+	if last := calls[len(calls)-1]; last.Args["action"] != "delete" || last.Args["schedule_id"] != "sched_mock_1" {
+		t.Errorf("unexpected call: %+v", last)
+	}
+}
+
+func TestScheduleCreate_InvalidArguments(t *testing.T) {
+	resetFlags(scheduleCreateCmd)
+	t.Cleanup(func() { resetFlags(scheduleCreateCmd) })
+	for _, flags := range []map[string]string{
+		{"cron": "61 * * * *"},
+		{"cron": "0 * * * *", "timezone": "Mars/Olympus_Mons"},
+		{"cron": "0 * * * *", "input": "{"},
+	} {
+		resetFlags(scheduleCreateCmd)
+		for name, value := range flags {
+			scheduleCreateCmd.Flags().Set(name, value)
+		}
+		err := scheduleCreateCmd.RunE(scheduleCreateCmd, []string{"r:local.hello:0.1.0"})
+		if output.Code(err) != output.CodeInvalidArgument {
+			t.Errorf("%v: err = %v (code %s), want %s", flags, err, output.Code(err), output.CodeInvalidArgument)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+// toolSchemaTTL is how long a server's tool schemas are cached on disk
+// before tools/list is asked again.
+const toolSchemaTTL = time.Hour
+
+// toolSchemaFile is the on-disk cache of one server's tool schemas.
+type toolSchemaFile struct {
+	URL       string     `json:"url"`
+	FetchedAt time.Time  `json:"fetched_at"`
+	Tools     []mcp.Tool `json:"tools"`
+}
+
+// toolSchemas lazily loads a server's tool input schemas, from the cache in
+// ~/.cyfr/cache when fresh and from tools/list otherwise, so arguments can
+// be validated before a call is sent. Any failure to load just disables
+// validation; the server still checks the arguments.
+type toolSchemas struct {
+	client *mcp.Client
+	once   sync.Once
+	byName map[string]any
+}
+
+// enableArgumentValidation makes client validate tool arguments against
+// the server's cached input schemas.
+func enableArgumentValidation(client *mcp.Client) {
+	s := &toolSchemas{client: client}
+	client.InputSchema = s.lookup
+}
+
+func (s *toolSchemas) lookup(ctx context.Context, tool string) any {
+	s.once.Do(func() {
+		tools := s.load(ctx)
+		s.byName = make(map[string]any, len(tools))
+		for _, t := range tools {
+			s.byName[t.Name] = t.InputSchema
+		}
+	})
+	return s.byName[tool]
+}
+
+func (s *toolSchemas) load(ctx context.Context) []mcp.Tool {
+	path, err := toolSchemaCachePath(s.client.BaseURL)
+	if err != nil {
+		return nil
+	}
+	if cached, ok := readToolSchemaCache(path, s.client.BaseURL, time.Now()); ok {
+		return cached
+	}
+
+	// A server that can't answer tools/list will fail the real call too;
+	// don't spend the retry budget twice finding that out.
+	retry := s.client.Retry
+	s.client.Retry.MaxRetries = 0
+	s.client.Retry.RateLimitRetries = 0
+	tools, err := s.client.ListToolsCtx(ctx)
+	s.client.Retry = retry
+	if err != nil {
+		return nil
+	}
+	_ = writeToolSchemaCache(path, &toolSchemaFile{
+		URL:       s.client.BaseURL,
+		FetchedAt: time.Now(),
+		Tools:     tools,
+	})
+	return tools
+}
+
+// toolSchemaCachePath returns the cache file for a server URL.
+func toolSchemaCachePath(url string) (string, error) {
+	dir, err := config.DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "cache", "tools-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// readToolSchemaCache returns the cached tools for url if the cache exists
+// and is younger than toolSchemaTTL at now.
+func readToolSchemaCache(path, url string, now time.Time) ([]mcp.Tool, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var f toolSchemaFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, false
+	}
+	if f.URL != url || now.Sub(f.FetchedAt) > toolSchemaTTL || now.Before(f.FetchedAt) {
+		return nil, false
+	}
+	return f.Tools, true
+}
+
+func writeToolSchemaCache(path string, f *toolSchemaFile) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+func TestReadToolSchemaCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.json")
+	fetched := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	err := writeToolSchemaCache(path, &toolSchemaFile{
+		URL:       "http://localhost:4000",
+		FetchedAt: fetched,
+		Tools:     []mcp.Tool{{Name: "execution"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		now  time.Time
+		want bool
+	}{
+		{"fresh", "http://localhost:4000", fetched.Add(time.Minute), true},
+		{"expired", "http://localhost:4000", fetched.Add(toolSchemaTTL + time.Second), false},
+		{"clock went backwards", "http://localhost:4000", fetched.Add(-time.Minute), false},
+		{"other server", "https://cyfr.example.com", fetched.Add(time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools, ok := readToolSchemaCache(path, tt.url, tt.now)
+			if ok != tt.want {
+				t.Fatalf("ok = %v, want %v", ok, tt.want)
+			}
+			if ok && (len(tools) != 1 || tools[0].Name != "execution") {
+				t.Errorf("tools = %+v", tools)
+			}
+		})
+	}
+
+	if _, ok := readToolSchemaCache(filepath.Join(t.TempDir(), "missing.json"), "http://localhost:4000", fetched); ok {
+		t.Error("missing cache file should not be fresh")
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// searchSorts are the orders of --sort on search.
+var searchSorts = []string{"downloads", "updated", "name"}
+
+// searchPage narrows the components of a search result to those in
+// namespace, if one is given, and then to the page-th page of limit of
+// them, counting from 1. The server is asked for the namespace too, but
+// may not filter by it.
+func searchPage(result map[string]any, namespace string, page, limit int) {
+	items, _ := result["components"].([]any)
+	if namespace != "" {
+		items = slices.DeleteFunc(slices.Clone(items), func(item any) bool {
+			c, _ := item.(map[string]any)
+			r, ok := searchResultRef(c)
+			return !ok || r.Namespace != namespace
+		})
+	}
+	skip := (page - 1) * limit
+	items = items[min(skip, len(items)):]
+	result["components"] = items
+	result["count"] = len(items)
+}
+
+// searchSummary groups the versions in a search result by component, with
+// the latest release of each and its description, in the order sortBy
+// says or, without it, the order the server found them in.
+func searchSummary(result map[string]any, sortBy string) []any {
+	type summary struct {
+		row       map[string]any
+		versions  []string
+		yanked    []string
+		items     map[string]map[string]any // by version
+		downloads float64
+		updated   string
+	}
+	var order []string
+	byName := map[string]*summary{}
+	items, _ := result["components"].([]any)
+	for _, item := range items {
+		c, _ := item.(map[string]any)
+		r, ok := searchResultRef(c)
+		if !ok {
+			continue
+		}
+		key := r.Type + ":" + r.Namespace + "." + r.Name
+		s := byName[key]
+		if s == nil {
+			s = &summary{row: map[string]any{"name": r.Namespace + "." + r.Name, "type": r.Type}, items: map[string]map[string]any{}}
+			byName[key] = s
+			order = append(order, key)
+		}
+		if y, _ := c["yanked"].(bool); y {
+			s.yanked = append(s.yanked, r.Version)
+		} else {
+			s.versions = append(s.versions, r.Version)
+		}
+		s.items[r.Version] = c
+		if n, ok := c["downloads"].(float64); ok {
+			s.downloads += n
+		}
+		for _, field := range []string{"updated_at", "published_at"} {
+			if t, _ := c[field].(string); t > s.updated {
+				s.updated = t
+			}
+		}
+	}
+
+	summaries := make([]*summary, len(order))
+	for i, key := range order {
+		s := byName[key]
+		latest, ok := ref.Latest(s.versions)
+		if !ok {
+			// Only prereleases, or only yanked versions: the highest.
+			all := append(slices.Clone(s.versions), s.yanked...)
+			ref.Sort(all)
+			latest = all[len(all)-1]
+		}
+		s.row["latest"] = latest
+		if d, ok := s.items[latest]["description"]; ok {
+			s.row["description"] = d
+		}
+		if s.downloads > 0 {
+			s.row["downloads"] = s.downloads
+		}
+		if s.updated != "" {
+			s.row["updated_at"] = s.updated
+		}
+		summaries[i] = s
+	}
+	switch sortBy {
+	case "name":
+		slices.SortStableFunc(summaries, func(a, b *summary) int { return cmp.Compare(a.row["name"].(string), b.row["name"].(string)) })
+	case "downloads":
+		slices.SortStableFunc(summaries, func(a, b *summary) int { return cmp.Compare(b.downloads, a.downloads) })
+	case "updated":
+		slices.SortStableFunc(summaries, func(a, b *summary) int { return cmp.Compare(b.updated, a.updated) })
+	}
+	rows := make([]any, len(summaries))
+	for i, s := range summaries {
+		rows[i] = s.row
+	}
+	return rows
+}
+
+// warnMorePages tells the user on stderr when there are results after the
+// page shown.
+func warnMorePages(result map[string]any, page int) {
+	if next, _ := result["next_cursor"].(string); next != "" {
+		fmt.Fprintf(os.Stderr, "More results are available; use --page %d for the next page.\n", page+1)
+	}
+}
+
+// validSearchSort checks the value of --sort.
+func validSearchSort(sortBy string) error {
+	if sortBy != "" && !slices.Contains(searchSorts, sortBy) {
+		return output.NewError(output.CodeInvalidArgument, "Invalid --sort %q (use downloads, updated, or name)", sortBy)
+	}
+	return nil
+}
+
+// searchRegistry returns the OCI registry search looks in: the one named
+// registry, or else the one namespace is routed to. It is nil for the
+// server's registry.
+func searchRegistry(registry, namespace string) (*config.Registry, error) {
+	cfg := loadConfigOrDefault()
+	if registry == "" {
+		_, reg := cfg.RegistryFor(namespace)
+		return reg, nil
+	}
+	reg := cfg.Registries[registry]
+	if reg == nil {
+		return nil, output.NewError(output.CodeNotFound, "Registry '%s' not found. Add it with 'cyfr registry add'.", registry)
+	}
+	return reg, nil
+}
+
+// searchOCI lists the versions of the component named query in an OCI
+// registry, as a component search result. Without a namespace, that of
+// the first namespace routed to the registry is used.
+func searchOCI(ctx context.Context, reg *config.Registry, namespace, query, typ string) (map[string]any, error) {
+	if namespace == "" && len(reg.Namespaces) > 0 {
+		namespace = reg.Namespaces[0]
+	}
+	if namespace == "" {
+		return nil, output.NewError(output.CodeInvalidArgument, "Searching %s needs --namespace", reg.URL)
+	}
+	if strings.ContainsAny(query, " /:") {
+		return nil, output.NewError(output.CodeInvalidArgument, "OCI registries can't be searched by keyword; give a component name, not %q", query)
+	}
+	r := ref.ComponentRef{Registry: reg.Host(), Namespace: namespace, Name: query}
+	if prefix := reg.Prefix(); prefix != "" {
+		r.Namespace = prefix + "/" + namespace
+	}
+	tags, err := ociTags(ctx, r)
+	if err != nil {
+		return nil, output.Errorf("Search failed: %s: %v", reg.URL, err)
+	}
+	components := []any{}
+	for _, tag := range tags {
+		if _, err := ref.ParseVersion(tag); err != nil {
+			continue
+		}
+		c := map[string]any{"name": query, "publisher": namespace, "version": tag}
+		if typ != "" {
+			c["component_type"] = typ
+		}
+		components = append(components, c)
+	}
+	return map[string]any{"components": components, "count": len(components)}, nil
+}
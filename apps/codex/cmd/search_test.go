@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func searchResult() map[string]any {
+	return map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.0.0", "component_type": "catalyst", "description": "Old", "downloads": 10.0, "updated_at": "2026-01-01T00:00:00Z"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.2.0", "component_type": "catalyst", "description": "Scores text", "downloads": 5.0, "updated_at": "2026-03-01T00:00:00Z"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.3.0", "component_type": "catalyst", "yanked": true},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "2.0.0-rc.1", "component_type": "catalyst"},
+		map[string]any{"name": "analyzer", "publisher": "other", "version": "0.1.0", "component_type": "reagent", "description": "Analyzes", "downloads": 100.0, "updated_at": "2026-02-01T00:00:00Z"},
+		map[string]any{"name": "beta", "publisher": "other", "version": "0.1.0-beta.1", "component_type": "reagent"},
+	}}
+}
+
+func TestSearchSummary(t *testing.T) {
+	rows := searchSummary(searchResult(), "")
+	if len(rows) != 3 {
+		t.Fatalf("rows = %v", rows)
+	}
+	first := rows[0].(map[string]any)
+	want := map[string]any{"name": "acme.sentiment", "type": "catalyst", "latest": "1.2.0", "description": "Scores text", "downloads": 15.0, "updated_at": "2026-03-01T00:00:00Z"}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("summary = %v, want %v", first, want)
+	}
+	// A component with only a prerelease shows it.
+	if latest := rows[2].(map[string]any)["latest"]; latest != "0.1.0-beta.1" {
+		t.Errorf("prerelease-only latest = %v", latest)
+	}
+
+	names := func(sortBy string) string {
+		var got []string
+		for _, row := range searchSummary(searchResult(), sortBy) {
+			got = append(got, row.(map[string]any)["name"].(string))
+		}
+		return strings.Join(got, ",")
+	}
+	for sortBy, want := range map[string]string{
+		"name":      "acme.sentiment,other.analyzer,other.beta",
+		"downloads": "other.analyzer,acme.sentiment,other.beta",
+		"updated":   "acme.sentiment,other.analyzer,other.beta",
+	} {
+		if got := names(sortBy); got != want {
+			t.Errorf("--sort %s: %s, want %s", sortBy, got, want)
+		}
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	result := searchResult()
+	searchPage(result, "other", 1, 20)
+	if result["count"] != 2 {
+		t.Errorf("namespace filter left %v", result["components"])
+	}
+
+	result = searchResult()
+	searchPage(result, "", 2, 4)
+	items := result["components"].([]any)
+	if len(items) != 2 || items[0].(map[string]any)["name"] != "analyzer" {
+		t.Errorf("page 2 = %v", items)
+	}
+	searchPage(result, "", 3, 4)
+	if result["count"] != 0 {
+		t.Errorf("page past the end = %v", result["components"])
+	}
+}
+
+func TestSearch_Mock(t *testing.T) {
+	if out := runCLI(t, "search", "hello", "--type", "r", "--sort", "name", "-q"); out != "local.hello\n" {
+		t.Errorf("search -q printed %q", out)
+	}
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +21,17 @@ func init() {
 	secretCmd.AddCommand(secretListCmd)
 	secretCmd.AddCommand(secretGrantCmd)
 	secretCmd.AddCommand(secretRevokeCmd)
+	secretCmd.AddCommand(secretHistoryCmd)
+	secretCmd.AddCommand(secretRollbackCmd)
+	addColumnsFlag(secretListCmd)
+	addColumnsFlag(secretHistoryCmd)
+
+	secretGetCmd.Flags().Int("version", 0, "Show this version instead of the current one")
+	secretRollbackCmd.Flags().Int("to", 0, "The version to restore (required)")
+	_ = secretRollbackCmd.MarkFlagRequired("to")
+
+	secretSetCmd.Flags().Bool("from-stdin", false, "Read the value from stdin")
+	secretSetCmd.Flags().String("from-file", "", "Read the value from a file")
 }
 
 var secretCmd = &cobra.Command{
@@ -26,55 +42,209 @@ var secretCmd = &cobra.Command{
 }
 
 var secretSetCmd = &cobra.Command{
-	Use:     "set <name>=<value>",
-	Short:   "Store a secret",
-	Long:    "Create or update an encrypted secret. The value is encrypted server-side before storage.",
-	Example: `  cyfr secret set DATABASE_URL=postgres://localhost/mydb
-  cyfr secret set API_KEY=sk-abc123`,
+	Use:   "set <name>",
+	Short: "Store a secret",
+	Long: `Create or update an encrypted secret. The value is encrypted server-side before storage.
+
+The value is asked for with hidden input, or read with --from-stdin or --from-file; one trailing newline is dropped. NAME=VALUE still works, but leaves the value in shell history and process listings.`,
+	Example: `  cyfr secret set DATABASE_URL
+  printf %s "$DB_URL" | cyfr secret set DATABASE_URL --from-stdin
+  cyfr secret set TLS_KEY --from-file ./tls.key`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		parts := strings.SplitN(args[0], "=", 2)
-		if len(parts) != 2 {
-			output.Error("Usage: cyfr secret set NAME=VALUE")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, value, err := secretValue(cmd, args[0])
+		if err != nil {
+			return err
 		}
 
-		client := newClient()
-		result, err := client.CallTool("secret", map[string]any{
-			"action": "set",
-			"name":   parts[0],
-			"value":  parts[1],
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action: typed.SecretActionSet,
+			Name:   name,
+			Value:  value,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return toolError(err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
-			fmt.Printf("Secret '%s' stored.\n", parts[0])
+			fmt.Printf("Secret '%s' stored.\n", name)
 		}
+		return nil
 	},
 }
 
+// secretValue returns the name and value secret set stores: from a
+// NAME=VALUE argument, with a warning, or else from --from-stdin,
+// --from-file or a hidden prompt.
+func secretValue(cmd *cobra.Command, arg string) (name, value string, err error) {
+	fromStdin, _ := cmd.Flags().GetBool("from-stdin")
+	fromFile, _ := cmd.Flags().GetString("from-file")
+	name, value, inline := strings.Cut(arg, "=")
+	if name == "" {
+		return "", "", output.NewError(output.CodeInvalidArgument, "Usage: cyfr secret set NAME")
+	}
+	switch {
+	case inline && (fromStdin || fromFile != ""):
+		return "", "", output.NewError(output.CodeInvalidArgument, "Give the value of %s once: as NAME=VALUE, --from-stdin or --from-file", name)
+	case fromStdin && fromFile != "":
+		return "", "", output.NewError(output.CodeInvalidArgument, "Give --from-stdin or --from-file, not both")
+	case inline:
+		fmt.Fprintf(os.Stderr, "Warning: a value on the command line is kept in shell history and shown in process listings. Run 'cyfr secret set %s' to type it hidden, or use --from-stdin.\n", name)
+	case fromStdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", "", output.Errorf("Cannot read the value of %s from stdin: %v", name, err)
+		}
+		value = trimNewline(string(data))
+	case fromFile != "":
+		data, err := os.ReadFile(fromFile)
+		if err != nil {
+			return "", "", output.Errorf("Cannot read the value of %s: %v", name, err)
+		}
+		value = trimNewline(string(data))
+	default:
+		if !output.IsTerminal(os.Stdin) {
+			return "", "", output.NewError(output.CodeInvalidArgument, "No value for %s: pipe it in with --from-stdin or give --from-file", name)
+		}
+		if value, err = readHidden(cmd.Context(), fmt.Sprintf("Value for %s: ", name)); err != nil {
+			return "", "", err
+		}
+	}
+	if value == "" {
+		return "", "", output.NewError(output.CodeInvalidArgument, "The value of %s is empty", name)
+	}
+	return name, value, nil
+}
+
+// trimNewline drops one trailing newline from s, as files and echo end
+// with one.
+func trimNewline(s string) string {
+	if t, ok := strings.CutSuffix(s, "\n"); ok {
+		return strings.TrimSuffix(t, "\r")
+	}
+	return s
+}
+
+// readHidden asks for a line on stderr and reads it from the terminal on
+// stdin without echoing it.
+func readHidden(ctx context.Context, prompt string) (string, error) {
+	if err := setEcho(os.Stdin, false); err != nil {
+		return "", output.Errorf("Cannot hide input: %v. Use --from-stdin instead.", err)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	defer func() {
+		setEcho(os.Stdin, true)
+		fmt.Fprintln(os.Stderr)
+	}()
+	line := make(chan string, 1)
+	go func() {
+		s, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		line <- s
+	}()
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case s := <-line:
+		return trimNewline(s), nil
+	}
+}
+
 var secretGetCmd = &cobra.Command{
-	Use:     "get <name>",
-	Short:   "Retrieve a secret (masked)",
-	Long:    "Fetch a secret's metadata and masked value from the server.",
-	Example: "  cyfr secret get DATABASE_URL",
+	Use:   "get <name>",
+	Short: "Retrieve a secret (masked)",
+	Long:  "Fetch a secret's metadata and masked value from the server. --version shows an earlier version from 'cyfr secret history'.",
+	Example: `  cyfr secret get DATABASE_URL
+  cyfr secret get DATABASE_URL --version 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := typed.SecretArgs{
+			Action: typed.SecretActionGet,
+			Name:   args[0],
+		}
+		if cmd.Flags().Changed("version") {
+			version, _ := cmd.Flags().GetInt("version")
+			if version < 1 {
+				return output.NewError(output.CodeInvalidArgument, "Invalid --version %d: versions start at 1", version)
+			}
+			params.Version = version
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), params)
+		if err != nil {
+			return toolError(err)
+		}
+		if flagJSON {
+			output.JSON(result)
+		} else {
+			output.KeyValue(result)
+		}
+		return nil
+	},
+}
+
+var secretHistoryCmd = &cobra.Command{
+	Use:     "history <name>",
+	Short:   "List the versions of a secret",
+	Long:    "List the versions a secret has had, newest first, with when and by whom each was set. Values aren't shown; 'cyfr secret get --version' shows one masked, and 'cyfr secret rollback' restores it.",
+	Example: "  cyfr secret history OPENAI_API_KEY",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("secret", map[string]any{
-			"action": "get",
-			"name":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action: typed.SecretActionHistory,
+			Name:   args[0],
+		})
+		if err != nil {
+			return output.Errorf("Cannot list versions of secret '%s': %v", args[0], err)
+		}
+		return printList(cmd, result, "versions")
+	},
+}
+
+var secretRollbackCmd = &cobra.Command{
+	Use:     "rollback <name>",
+	Short:   "Restore an earlier version of a secret",
+	Long:    "Make an earlier version's value a secret's current value. The rollback is stored as a new version, so the value it replaces stays in the history and can be restored in turn.",
+	Example: "  cyfr secret rollback OPENAI_API_KEY --to 3",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetInt("to")
+		if to < 1 {
+			return output.NewError(output.CodeInvalidArgument, "Invalid --to %d: versions start at 1", to)
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action:  typed.SecretActionRollback,
+			Name:    args[0],
+			Version: to,
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Cannot roll back secret '%s': %v", args[0], err)
 		}
 		if flagJSON {
 			output.JSON(result)
+			return nil
+		}
+		if version, ok := result["version"]; ok {
+			fmt.Printf("Secret '%s' rolled back to version %d, as version %v.\n", args[0], to, version)
 		} else {
-			output.KeyValue(result)
+			fmt.Printf("Secret '%s' rolled back to version %d.\n", args[0], to)
 		}
+		return nil
 	},
 }
 
@@ -84,20 +254,24 @@ var secretDeleteCmd = &cobra.Command{
 	Long:    "Permanently remove a secret and revoke all component grants.",
 	Example: "  cyfr secret delete DATABASE_URL",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("secret", map[string]any{
-			"action": "delete",
-			"name":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action: typed.SecretActionDelete,
+			Name:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return toolError(err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Printf("Secret '%s' deleted.\n", args[0])
 		}
+		return nil
 	},
 }
 
@@ -106,19 +280,18 @@ var secretListCmd = &cobra.Command{
 	Short:   "List all secrets",
 	Long:    "List all stored secret names and their metadata without revealing values.",
 	Example: "  cyfr secret list",
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("secret", map[string]any{
-			"action": "list",
-		})
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action: typed.SecretActionList,
+		})
+		if err != nil {
+			return toolError(err)
 		}
+		return printList(cmd, result, "secrets")
 	},
 }
 
@@ -129,23 +302,30 @@ var secretGrantCmd = &cobra.Command{
 	Example: `  cyfr secret grant c:local.claude:0.1.0 ANTHROPIC_API_KEY
   cyfr secret grant c local.claude:0.1.0 ANTHROPIC_API_KEY`,
 	Args: cobra.RangeArgs(2, 3),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		component := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("secret", map[string]any{
-			"action":        "grant",
-			"component_ref": component,
-			"name":          args[1],
+		component, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action:       typed.SecretActionGrant,
+			ComponentRef: component,
+			Name:         args[1],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return toolError(err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Printf("Granted '%s' access to secret '%s'.\n", component, args[1])
 		}
+		return nil
 	},
 }
 
@@ -156,23 +336,29 @@ var secretRevokeCmd = &cobra.Command{
 	Example: `  cyfr secret revoke c:local.claude:0.1.0 ANTHROPIC_API_KEY
   cyfr secret revoke c local.claude:0.1.0 ANTHROPIC_API_KEY`,
 	Args: cobra.RangeArgs(2, 3),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		args = joinTypeShorthand(args)
-		component := normalizeComponentRef(args[0])
-		client := newClient()
-		result, err := client.CallTool("secret", map[string]any{
-			"action":        "revoke",
-			"component_ref": component,
-			"name":          args[1],
+		component, err := normalizeComponentRef(args[0])
+		if err != nil {
+			return err
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action:       typed.SecretActionRevoke,
+			ComponentRef: component,
+			Name:         args[1],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return toolError(err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			fmt.Printf("Revoked '%s' access to secret '%s'.\n", component, args[1])
 		}
+		return nil
 	},
 }
-
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestSecretSet_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	os.WriteFile(path, []byte("sk-test\n"), 0600)
+	if out := runCLI(t, "secret", "set", "API_KEY", "--from-file", path); !strings.Contains(out, "Secret 'API_KEY' stored.") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestSecretValue(t *testing.T) {
+	resetFlags(secretSetCmd)
+	stdin := func(data string) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteString(data)
+		w.Close()
+		old := os.Stdin
+		os.Stdin = r
+		t.Cleanup(func() { os.Stdin = old; r.Close() })
+	}
+	set := func(flag, value string) {
+		secretSetCmd.Flags().Set(flag, value)
+		t.Cleanup(func() { resetFlags(secretSetCmd) })
+	}
+
+	name, value, err := secretValue(secretSetCmd, "DB_URL=postgres://db")
+	if err != nil || name != "DB_URL" || value != "postgres://db" {
+		t.Errorf("inline: %q, %q, %v", name, value, err)
+	}
+
+	stdin("line one\nline two\r\n")
+	set("from-stdin", "true")
+	if _, value, err := secretValue(secretSetCmd, "CERT"); err != nil || value != "line one\nline two" {
+		t.Errorf("stdin: %q, %v", value, err)
+	}
+	if _, _, err := secretValue(secretSetCmd, "CERT=x"); output.Code(err) != output.CodeInvalidArgument {
+		t.Errorf("value twice: err = %v", err)
+	}
+
+	stdin("\n")
+	if _, _, err := secretValue(secretSetCmd, "CERT"); output.Code(err) != output.CodeInvalidArgument {
+		t.Errorf("empty: err = %v", err)
+	}
+}
+
+func TestSecretValue_NoTerminal(t *testing.T) {
+	resetFlags(secretSetCmd)
+	r, w, _ := os.Pipe()
+	defer r.Close()
+	defer w.Close()
+	old := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = old }()
+	if _, _, err := secretValue(secretSetCmd, "API_KEY"); output.Code(err) != output.CodeInvalidArgument || !strings.Contains(err.Error(), "--from-stdin") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSecretHistory_Mock(t *testing.T) {
+	out := runCLI(t, "secret", "history", "OPENAI_API_KEY")
+	if !strings.Contains(out, "VERSION") || !strings.Contains(out, "2026-02-01T12:00:00Z") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "secret", "get", "OPENAI_API_KEY", "--version", "1"); !strings.Contains(out, "sk-m****") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "secret", "rollback", "OPENAI_API_KEY", "--to", "1"); !strings.Contains(out, "rolled back to version 1, as version 3") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	calls := mockServer.Calls()
+	last := calls[len(calls)-1]
+	if last.Args["action"] != "rollback" || last.Args["version"] != float64(1) {
+		t.Errorf("call = %+v", last)
+	}
+}
+
+func TestSecretSet_ErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-33301,"message":"session required"}}`)
+	}))
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.EnvConfigPath, "")
+	t.Setenv(envURL, srv.URL)
+	resetFlags(secretSetCmd)
+	path := filepath.Join(t.TempDir(), "key")
+	os.WriteFile(path, []byte("sk-test\n"), 0600)
+	secretSetCmd.Flags().Set("from-file", path)
+	t.Cleanup(func() { resetFlags(secretSetCmd) })
+
+	err := secretSetCmd.RunE(secretSetCmd, []string{"API_KEY"})
+	if output.Code(err) != output.CodeNotLoggedIn || !strings.Contains(err.Error(), "cyfr login") {
+		t.Errorf("err = %v (code %s), want %s", err, output.Code(err), output.CodeNotLoggedIn)
+	}
+}
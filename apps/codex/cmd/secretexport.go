@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/secretfile"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, c := range []*cobra.Command{secretExportCmd, secretImportEncryptedCmd} {
+		c.Flags().String("file", "", "The encrypted secrets file (required)")
+		c.Flags().Bool("passphrase-prompt", false, "Ask for the passphrase with hidden input")
+		c.Flags().String("passphrase-file", "", "Read the passphrase from a file, for scripts")
+		c.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+		_ = c.MarkFlagRequired("file")
+	}
+	secretExportCmd.Flags().Bool("force", false, "Overwrite the file if it exists")
+	secretImportEncryptedCmd.Flags().Bool("overwrite", false, "Replace secrets the server already has")
+	secretCmd.AddCommand(secretExportCmd, secretImportEncryptedCmd)
+}
+
+var secretExportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export secrets to a passphrase-encrypted file",
+	Long: `Export the values of the server's secrets, or of the named ones, to a file encrypted with a passphrase, to import them into another server with 'cyfr secret import-encrypted'. It needs the admin permission, and asks for confirmation unless --yes is given.
+
+The file is sealed with AES-256-GCM under a key derived from the passphrase (PBKDF2-HMAC-SHA256), and written readable only by you. Component grants aren't exported: components may have other references on the target server, so grant them there again.`,
+	Example: `  cyfr secret export --file secrets.enc --passphrase-prompt
+  cyfr secret export OPENAI_API_KEY ANTHROPIC_API_KEY --file llm.enc --passphrase-prompt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		if err := checkPassphraseFlags(cmd); err != nil {
+			return err
+		}
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			if _, err := os.Stat(file); err == nil {
+				return output.NewError(output.CodeInvalidArgument, "%s exists; use --force to overwrite it", file)
+			}
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		if err := requireAdmin(cmd.Context(), client.Client, "export secrets"); err != nil {
+			return err
+		}
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			what := "every secret"
+			if len(args) > 0 {
+				what = fmt.Sprintf("%d secret(s)", len(args))
+			}
+			if !confirm(fmt.Sprintf("Export the values of %s on %s to %s?", what, client.BaseURL, file)) {
+				fmt.Println("Aborted.")
+				return nil
+			}
+		}
+		passphrase, err := secretsPassphrase(cmd, true)
+		if err != nil {
+			return err
+		}
+
+		result, err := client.Secret(cmd.Context(), typed.SecretArgs{
+			Action: typed.SecretActionExport,
+			Names:  args,
+		})
+		if err != nil {
+			return output.Errorf("Cannot export secrets: %v", err)
+		}
+		secrets := exportedSecrets(result)
+		for _, name := range args {
+			if !slices.ContainsFunc(secrets, func(s secretfile.Secret) bool { return s.Name == name }) {
+				return output.NewError(output.CodeNotFound, "Secret '%s' not found.", name)
+			}
+		}
+		data, err := secretfile.Seal(secrets, passphrase)
+		if err != nil {
+			return output.Errorf("Cannot encrypt the secrets: %v", err)
+		}
+		if err := os.WriteFile(file, data, 0600); err != nil {
+			return output.NewError(output.CodeError, "Cannot write %s: %v", file, err)
+		}
+		names := make([]string, len(secrets))
+		for i, s := range secrets {
+			names[i] = s.Name
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"file": file, "secrets": nonNil(names), "count": len(names)})
+			return nil
+		}
+		fmt.Printf("Exported %d secret(s) to %s. Import them with 'cyfr secret import-encrypted --file %s --passphrase-prompt'.\n", len(names), file, file)
+		return nil
+	},
+}
+
+var secretImportEncryptedCmd = &cobra.Command{
+	Use:   "import-encrypted",
+	Short: "Import secrets from a file written by secret export",
+	Long:  "Decrypt a file written by 'cyfr secret export' with its passphrase and store its secrets on the current context's server. Secrets the server already has are kept unless --overwrite is given. It needs the admin permission, and asks for confirmation unless --yes is given.",
+	Example: `  cyfr secret import-encrypted --file secrets.enc --passphrase-prompt
+  cyfr --context staging secret import-encrypted --file secrets.enc --passphrase-prompt --overwrite`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Cannot read %s: %v", file, err)
+		}
+		passphrase, err := secretsPassphrase(cmd, false)
+		if err != nil {
+			return err
+		}
+		secrets, err := secretfile.Open(data, passphrase)
+		if errors.Is(err, secretfile.ErrPassphrase) {
+			return output.NewError(output.CodeInvalidArgument, "Cannot decrypt %s: %v", file, err)
+		}
+		if err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Cannot read %s: %v", file, err)
+		}
+
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		if err := requireAdmin(cmd.Context(), client.Client, "import secrets"); err != nil {
+			return err
+		}
+		existing, err := secretNames(cmd.Context(), client.Client)
+		if err != nil {
+			return err
+		}
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes && !confirm(fmt.Sprintf("Import %d secret(s) into %s?", len(secrets), client.BaseURL)) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+
+		var added, replaced, skipped []string
+		for _, s := range secrets {
+			if existing[s.Name] && !overwrite {
+				skipped = append(skipped, s.Name)
+				continue
+			}
+			if _, err := client.Secret(cmd.Context(), typed.SecretArgs{
+				Action: typed.SecretActionSet,
+				Name:   s.Name,
+				Value:  s.Value,
+			}); err != nil {
+				return output.Errorf("Cannot store secret '%s': %v (%d imported before it)", s.Name, err, len(added)+len(replaced))
+			}
+			if existing[s.Name] {
+				replaced = append(replaced, s.Name)
+			} else {
+				added = append(added, s.Name)
+			}
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"added": nonNil(added), "replaced": nonNil(replaced), "skipped": nonNil(skipped)})
+			return nil
+		}
+		fmt.Printf("Imported %d secret(s): %d added, %d replaced, %d skipped\n", len(added)+len(replaced), len(added), len(replaced), len(skipped))
+		if len(skipped) > 0 {
+			fmt.Println("Skipped secrets the server already has; use --overwrite to replace them.")
+		}
+		if len(added)+len(replaced) > 0 {
+			fmt.Println("Grant components access to them with 'cyfr secret grant'.")
+		}
+		return nil
+	},
+}
+
+// secretsPassphrase reads the passphrase of a secrets file from
+// --passphrase-file or, with --passphrase-prompt, the terminal, asking
+// twice for a new file so that a typo doesn't lock the secrets away.
+func secretsPassphrase(cmd *cobra.Command, isNew bool) ([]byte, error) {
+	if err := checkPassphraseFlags(cmd); err != nil {
+		return nil, err
+	}
+	if path, _ := cmd.Flags().GetString("passphrase-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, output.NewError(output.CodeInvalidArgument, "Cannot read the passphrase: %v", err)
+		}
+		if passphrase := trimNewline(string(data)); passphrase != "" {
+			return []byte(passphrase), nil
+		}
+		return nil, output.NewError(output.CodeInvalidArgument, "%s is empty", path)
+	}
+	passphrase, err := readHidden(cmd.Context(), "Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, output.NewError(output.CodeInvalidArgument, "The passphrase is empty")
+	}
+	if isNew {
+		again, err := readHidden(cmd.Context(), "Passphrase again: ")
+		if err != nil {
+			return nil, err
+		}
+		if again != passphrase {
+			return nil, output.NewError(output.CodeInvalidArgument, "The passphrases don't match")
+		}
+	}
+	return []byte(passphrase), nil
+}
+
+// checkPassphraseFlags fails unless the passphrase of a secrets file can
+// be read as the flags say, so that a command fails before it does
+// anything else.
+func checkPassphraseFlags(cmd *cobra.Command) error {
+	prompt, _ := cmd.Flags().GetBool("passphrase-prompt")
+	path, _ := cmd.Flags().GetString("passphrase-file")
+	switch {
+	case prompt && path != "":
+		return output.NewError(output.CodeInvalidArgument, "Give --passphrase-prompt or --passphrase-file, not both")
+	case path != "":
+		return nil
+	case !prompt:
+		return output.NewError(output.CodeInvalidArgument, "Give --passphrase-prompt, or --passphrase-file for scripts")
+	case !output.IsTerminal(os.Stdin):
+		return output.NewError(output.CodeInvalidArgument, "--passphrase-prompt needs a terminal; use --passphrase-file")
+	}
+	return nil
+}
+
+// requireAdmin fails if the server says the user lacks the admin
+// permission needed to do what. If it can't tell, the server has the
+// final say when the command goes on.
+func requireAdmin(ctx context.Context, client *mcp.Client, what string) error {
+	who, err := typed.New(client).Session(ctx, typed.SessionArgs{Action: typed.SessionActionWhoami})
+	if err != nil {
+		return nil
+	}
+	if scope, _ := who["scope"].(string); scope == "admin" {
+		return nil
+	}
+	permissions, ok := who["permissions"].([]any)
+	if !ok || slices.Contains(permissions, any("admin")) {
+		return nil
+	}
+	return output.NewError(output.CodePermissionDenied, "Cannot %s: it needs the admin permission. Log in with an admin key, or ask an admin to run it.", what)
+}
+
+// exportedSecrets reads the secrets of a secret export result, sorted by
+// name.
+func exportedSecrets(result map[string]any) []secretfile.Secret {
+	items, _ := result["secrets"].([]any)
+	var secrets []secretfile.Secret
+	for _, item := range items {
+		entry, _ := item.(map[string]any)
+		name, _ := entry["name"].(string)
+		value, _ := entry["value"].(string)
+		if name != "" {
+			secrets = append(secrets, secretfile.Secret{Name: name, Value: value})
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	return secrets
+}
+
+// secretNames returns the names of the server's secrets. The list holds
+// names, or objects with a name.
+func secretNames(ctx context.Context, client *mcp.Client) (map[string]bool, error) {
+	result, err := typed.New(client).Secret(ctx, typed.SecretArgs{Action: typed.SecretActionList})
+	if err != nil {
+		return nil, output.Errorf("Cannot list secrets: %v", err)
+	}
+	names := map[string]bool{}
+	items, _ := result["secrets"].([]any)
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			names[v] = true
+		case map[string]any:
+			if name, _ := v["name"].(string); name != "" {
+				names[name] = true
+			}
+		}
+	}
+	return names, nil
+}
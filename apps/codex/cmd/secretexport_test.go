@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/secretfile"
+)
+
+// adminFixtures writes the default fixtures with the user an admin, for
+// --mock=<file>. The process's mock server is started again from them, and
+// from the default fixtures after the test.
+func adminFixtures(t *testing.T) string {
+	t.Helper()
+	mockOnce, mockServer, mockErr = sync.Once{}, nil, nil
+	t.Cleanup(func() { mockOnce, mockServer, mockErr = sync.Once{}, nil, nil })
+	f := mockserver.DefaultFixtures()
+	f.Responses["session.whoami"] = map[string]any{"user_id": "user_mock", "permissions": []any{"read", "write", "admin"}}
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	os.WriteFile(path, data, 0644)
+	return path
+}
+
+func TestSecretExportImport_Mock(t *testing.T) {
+	iterations, minIterations := secretfile.Iterations, secretfile.MinIterations
+	secretfile.Iterations, secretfile.MinIterations = 1000, 1000
+	t.Cleanup(func() { secretfile.Iterations, secretfile.MinIterations = iterations, minIterations })
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "secrets.enc")
+	passphrase := filepath.Join(dir, "passphrase")
+	os.WriteFile(passphrase, []byte("correct horse\n"), 0600)
+	mock := "--mock=" + adminFixtures(t)
+
+	out := runCLI(t, mock, "secret", "export", "--file", file, "--passphrase-file", passphrase, "--yes")
+	if !strings.Contains(out, "Exported 1 secret(s)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(file)
+	secrets, err := secretfile.Open(data, []byte("correct horse"))
+	if err != nil || len(secrets) != 1 || secrets[0].Name != "OPENAI_API_KEY" || secrets[0].Value != "sk-mock" {
+		t.Errorf("exported %+v, %v", secrets, err)
+	}
+
+	// The mock server has OPENAI_API_KEY already.
+	out = runCLI(t, mock, "secret", "import-encrypted", "--file", file, "--passphrase-file", passphrase, "--yes")
+	if !strings.Contains(out, "0 added, 0 replaced, 1 skipped") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	out = runCLI(t, mock, "secret", "import-encrypted", "--file", file, "--passphrase-file", passphrase, "--yes", "--overwrite")
+	if !strings.Contains(out, "0 added, 1 replaced, 0 skipped") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	f := mockserver.DefaultFixtures()
+	ts := httptest.NewServer(mockserver.New(f))
+	defer ts.Close()
+	client := mcp.NewClient(ts.URL)
+
+	if err := requireAdmin(context.Background(), client, "export secrets"); output.Code(err) != output.CodePermissionDenied {
+		t.Errorf("no admin: err = %v, want %s", err, output.CodePermissionDenied)
+	}
+	f.Responses["session.whoami"] = map[string]any{"scope": "admin"}
+	if err := requireAdmin(context.Background(), client, "export secrets"); err != nil {
+		t.Errorf("admin scope: %v", err)
+	}
+	// A server that doesn't report permissions decides for itself.
+	f.Responses["session.whoami"] = map[string]any{"user_id": "user_mock"}
+	if err := requireAdmin(context.Background(), client, "export secrets"); err != nil {
+		t.Errorf("no permissions: %v", err)
+	}
+}
+
+func TestExportedSecrets(t *testing.T) {
+	secrets := exportedSecrets(map[string]any{"secrets": []any{
+		map[string]any{"name": "B", "value": "2"},
+		map[string]any{"name": "A", "value": "1"},
+		map[string]any{"value": "nameless"},
+	}})
+	if len(secrets) != 2 || secrets[0].Name != "A" || secrets[1].Value != "2" {
+		t.Errorf("exportedSecrets = %+v", secrets)
+	}
+}
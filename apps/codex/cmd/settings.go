@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/scaffold"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(settingsCmd)
+	settingsCmd.AddCommand(settingsListCmd)
+	settingsCmd.AddCommand(settingsGetCmd)
+	settingsCmd.AddCommand(settingsSetCmd)
+	settingsCmd.AddCommand(settingsUnsetCmd)
+}
+
+// cliSetting is a CLI preference managed with 'cyfr settings'.
+type cliSetting struct {
+	Key         string
+	Description string
+	// Values are the allowed values; nil allows any.
+	Values []string
+	// Default returns the value used when the setting isn't set.
+	Default func() string
+}
+
+// cliSettings are the known settings, in the order they are listed.
+var cliSettings = []cliSetting{
+	{
+		Key:         "output",
+		Description: "Default output format when --json isn't given",
+		Values:      []string{"text", "json"},
+		Default:     func() string { return "text" },
+	},
+	{
+		Key:         "color",
+		Description: "Colored output: auto (on a terminal, unless NO_COLOR is set), always, or never; --no-color turns it off",
+		Values:      []string{"auto", "always", "never"},
+		Default:     func() string { return "auto" },
+	},
+	{
+		Key:         "telemetry",
+		Description: "Whether anonymous usage statistics may be sent; the CLI currently sends none",
+		Values:      []string{"off", "on"},
+		Default:     func() string { return "off" },
+	},
+	{
+		Key:         "editor",
+		Description: "Editor for commands that open one",
+		Default:     func() string { return firstEnv("vi", "VISUAL", "EDITOR") },
+	},
+	{
+		Key:         "scaffold_url",
+		Description: "Release mirror to download scaffold files, templates and server binaries from, laid out like GitHub releases (https:// or file://); a project's cyfr.yaml can set its own, but checksums.txt is always fetched from this one",
+		Default:     func() string { return scaffold.DefaultReleaseURL },
+	},
+	{
+		Key:         "pager",
+		Description: "Pager for long output",
+		Default:     func() string { return firstEnv("less", "PAGER") },
+	},
+}
+
+// firstEnv returns the first of the environment variables that is set, or
+// fallback.
+func firstEnv(fallback string, names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return fallback
+}
+
+// lookupSetting returns the known setting named key, or an error listing
+// the known ones if there is none.
+func lookupSetting(key string) (cliSetting, error) {
+	keys := make([]string, len(cliSettings))
+	for i, s := range cliSettings {
+		if s.Key == key {
+			return s, nil
+		}
+		keys[i] = s.Key
+	}
+	return cliSetting{}, output.Errorf("Unknown setting %q (known: %s)", key, strings.Join(keys, ", "))
+}
+
+// settingValue returns a setting's value from cfg, or its default.
+func settingValue(cfg *config.Config, key string) string {
+	if v := cfg.Settings[key]; v != "" {
+		return v
+	}
+	s, _ := lookupSetting(key)
+	return s.Default()
+}
+
+var settingsCmd = &cobra.Command{
+	Use:     "settings",
+	Short:   "Manage CLI preferences",
+	GroupID: "advanced",
+	Long: `Get and set preferences for how the CLI itself behaves: default output
+format, color, telemetry, editor and pager. They are stored in the CLI's
+config file and apply to every context. For a component's runtime
+configuration on the server, use 'cyfr config'.
+
+Flags, a project's .cyfr/config.json and a context's defaults take
+precedence over these settings.`,
+}
+
+var settingsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show all settings",
+	Long:  "Show every setting with its current value. Settings at their default are marked (default).",
+	Example: `  cyfr settings list
+  cyfr settings list --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := loadConfigOrDefault()
+		if flagJSON {
+			values := make(map[string]any, len(cliSettings))
+			for _, s := range cliSettings {
+				values[s.Key] = settingValue(cfg, s.Key)
+			}
+			output.JSON(values)
+			return nil
+		}
+		rows := make([]map[string]string, len(cliSettings))
+		for i, s := range cliSettings {
+			value := settingValue(cfg, s.Key)
+			if cfg.Settings[s.Key] == "" {
+				value += " (default)"
+			}
+			rows[i] = map[string]string{"SETTING": s.Key, "VALUE": value, "DESCRIPTION": s.Description}
+		}
+		output.Table([]string{"SETTING", "VALUE", "DESCRIPTION"}, rows)
+		return nil
+	},
+}
+
+var settingsGetCmd = &cobra.Command{
+	Use:     "get <key>",
+	Short:   "Show a setting",
+	Long:    "Print the value of a setting, or its default if it isn't set.",
+	Example: "  cyfr settings get output",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
+		value := settingValue(loadConfigOrDefault(), s.Key)
+		if flagJSON {
+			output.JSON(map[string]any{args[0]: value})
+			return nil
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var settingsSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Long:  "Set a CLI preference. Run 'cyfr settings list' to see the settings and their values.",
+	Example: `  cyfr settings set output json
+  cyfr settings set color never
+  cyfr settings set pager "less -R"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
+		value := args[1]
+		if s.Values != nil && !slices.Contains(s.Values, value) {
+			return output.NewError(output.CodeInvalidArgument, "Invalid value %q for %s (use %s)", value, s.Key, strings.Join(s.Values, ", "))
+		}
+		_, err = config.Update(func(cfg *config.Config) error {
+			if cfg.Settings == nil {
+				cfg.Settings = make(map[string]string)
+			}
+			cfg.Settings[s.Key] = value
+			return nil
+		})
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+		fmt.Printf("Set %s to %s\n", s.Key, value)
+		return nil
+	},
+}
+
+var settingsUnsetCmd = &cobra.Command{
+	Use:     "unset <key>",
+	Short:   "Reset a setting to its default",
+	Long:    "Remove a setting, so its default applies again.",
+	Example: "  cyfr settings unset color",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := lookupSetting(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = config.Update(func(cfg *config.Config) error {
+			delete(cfg.Settings, s.Key)
+			return nil
+		})
+		if err != nil {
+			return output.NewError(output.CodeConfig, "Failed to save config: %v", err)
+		}
+		fmt.Printf("Reset %s to its default (%s)\n", s.Key, s.Default())
+		return nil
+	},
+}
+
+// applySettings applies the CLI settings that take effect on every
+// command: the default output format, unless --json was given, and color,
+// unless --no-color was given.
+func applySettings(cmd *cobra.Command) {
+	cfg := loadConfigOrDefault()
+	if !cmd.Flags().Changed("json") {
+		switch cfg.Settings["output"] {
+		case "json":
+			flagJSON = true
+		case "text":
+			flagJSON = false
+		}
+	}
+	scaffold.ReleaseURL = scaffoldURL(cfg)
+	scaffold.ChecksumURL = settingValue(cfg, "scaffold_url")
+	color := cfg.Settings["color"]
+	if flagNoColor {
+		color = "never"
+	}
+	switch color {
+	case "always":
+		output.Color, output.ColorStderr = true, true
+	case "never":
+		output.Color, output.ColorStderr = false, false
+	default:
+		output.Color = colorTerminal(os.Stdout)
+		output.ColorStderr = colorTerminal(os.Stderr)
+	}
+}
+
+// scaffoldURL returns where release assets are downloaded from: the
+// scaffold_url of the project's cyfr.yaml, or else of the settings. Their
+// checksums are only ever fetched from the settings' scaffold_url.
+func scaffoldURL(cfg *config.Config) string {
+	if url := cyfrYAMLValue("scaffold_url"); url != "" {
+		return url
+	}
+	return settingValue(cfg, "scaffold_url")
+}
+
+// colorTerminal reports whether colors are shown on f with the color
+// setting at auto: f is a terminal and NO_COLOR isn't set.
+func colorTerminal(f *os.File) bool {
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && output.IsTerminal(f)
+}
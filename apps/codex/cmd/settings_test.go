@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestSettings_OutputAndColor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	projectCache.dir = ""
+	resetFlags(rootCmd)
+	t.Cleanup(func() {
+		resetFlags(rootCmd)
+		output.Color, output.ColorStderr = false, false
+	})
+
+	if err := settingsSetCmd.RunE(settingsSetCmd, []string{"output", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := settingsSetCmd.RunE(settingsSetCmd, []string{"color", "always"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Settings["output"] != "json" || cfg.Settings["color"] != "always" {
+		t.Fatalf("settings not saved: %v", cfg.Settings)
+	}
+
+	applyDefaults(keyListCmd)
+	if !flagJSON || !output.Color {
+		t.Errorf("settings not applied: json %v, color %v", flagJSON, output.Color)
+	}
+
+	resetFlags(rootCmd)
+	if err := keyListCmd.ParseFlags([]string{"--json=false"}); err != nil {
+		t.Fatal(err)
+	}
+	applyDefaults(keyListCmd)
+	if flagJSON {
+		t.Error("--json=false should win over the output setting")
+	}
+
+	resetFlags(rootCmd)
+	if err := keyListCmd.ParseFlags([]string{"--no-color"}); err != nil {
+		t.Fatal(err)
+	}
+	applyDefaults(keyListCmd)
+	if output.Color || output.ColorStderr {
+		t.Error("--no-color should win over the color setting")
+	}
+
+	if err := settingsUnsetCmd.RunE(settingsUnsetCmd, []string{"output"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := settingValue(loadConfigOrDefault(), "output"); got != "text" {
+		t.Errorf("output after unset = %q, want the default", got)
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyfr/codex/internal/cosign"
+	"github.com/cyfr/codex/internal/oci"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	signCmd.Flags().String("key", "", "PEM private key to sign with, e.g. the cosign.key of 'cosign generate-key-pair'")
+	signCmd.Flags().Bool("keyless", false, "Sign with a short-lived certificate for your OIDC identity, through cosign")
+	rootCmd.AddCommand(signCmd)
+}
+
+// cosignInstall says where to get cosign.
+const cosignInstall = "https://docs.sigstore.dev/cosign/system_config/installation/"
+
+var signCmd = &cobra.Command{
+	Use:     "sign <reference|directory|wasm>",
+	Short:   "Sign a component's artifact",
+	GroupID: "component",
+	Long: `Sign the .wasm file of a local component, given by reference, by its directory or by the file itself, and store the signature bundle beside it as <type>.wasm.bundle. 'cyfr publish' attaches the bundle to what it publishes, and refuses one older than the artifact.
+
+The bundle is in the format of 'cosign sign-blob --bundle'. With --key, an ECDSA key in PEM form signs it directly; cosign's encrypted keys, and --keyless signing with a certificate for your OIDC identity, need cosign installed, which is run to sign (COSIGN_PASSWORD is passed on to it). A signature made with --key can be checked with:
+
+  cosign verify-blob --key cosign.pub --bundle catalyst.wasm.bundle --insecure-ignore-tlog catalyst.wasm`,
+	Example: `  cyfr sign c:local.my-tool:0.1.0 --key cosign.key
+  cyfr sign components/catalysts/local/my-tool/0.1.0/ --key cosign.key
+  cyfr sign c:local.my-tool:0.1.0 --keyless`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyPath, _ := cmd.Flags().GetString("key")
+		keyless, _ := cmd.Flags().GetBool("keyless")
+		if (keyPath == "") == !keyless {
+			return output.NewError(output.CodeInvalidArgument, "Give either --key or --keyless.")
+		}
+		artifact, err := localArtifact(args[0])
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(artifact)
+		if err != nil {
+			return output.Errorf("Cannot read %s: %v", artifact, err)
+		}
+		bundlePath := artifact + cosign.BundleSuffix
+
+		var method string
+		if keyless {
+			method = "keyless"
+			err = cosignSignBlob(artifact, bundlePath)
+		} else {
+			method, err = signWithKey(keyPath, artifact, data, bundlePath)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := map[string]any{"artifact": artifact, "digest": oci.Digest(data), "bundle": bundlePath, "method": method}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Signed %s (%s)\n", artifact, oci.Digest(data))
+		fmt.Printf("Bundle: %s\n", bundlePath)
+		return nil
+	},
+}
+
+// localArtifact returns the .wasm file of the local component arg names:
+// the file itself, the component directory it is in, or a reference to a
+// component under components/.
+func localArtifact(arg string) (string, error) {
+	if strings.HasSuffix(arg, ".wasm") {
+		return arg, nil
+	}
+	dir := arg
+	if !isDir(dir) {
+		normalized, err := normalizeComponentRef(arg)
+		if err != nil {
+			return "", err
+		}
+		if r, ok := registryRef(normalized); ok {
+			dir = filepath.Join("components", r.Type+"s", ociNamespace(r), r.Name, r.Version)
+		} else {
+			r, err := parseComponentRef(normalized)
+			if err != nil {
+				return "", err
+			}
+			if r.Type == "" {
+				return "", output.NewError(output.CodeInvalidArgument, "Cannot sign %s: add a type prefix, e.g. c:%s", r, r)
+			}
+			dir = componentDir(r)
+		}
+	}
+	for _, typ := range componentTypes {
+		path := filepath.Join(dir, typ+".wasm")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", output.NewError(output.CodeNotFound, "No component .wasm file in %s; 'cyfr build' builds it.", dir)
+}
+
+// signWithKey signs data, the contents of artifact, with the PEM key in
+// keyPath, writing the bundle to bundlePath. Keys encrypted by cosign are
+// handed to cosign. It returns "key" or, if cosign signed, "cosign".
+func signWithKey(keyPath, artifact string, data []byte, bundlePath string) (string, error) {
+	pemData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return "", output.Errorf("Cannot read the key: %v", err)
+	}
+	key, err := cosign.ParsePrivateKey(pemData)
+	if errors.Is(err, cosign.ErrEncryptedKey) {
+		return "cosign", cosignSignBlob(artifact, bundlePath, "--key", keyPath)
+	}
+	if err != nil {
+		return "", output.NewError(output.CodeInvalidArgument, "Cannot use %s: %v", keyPath, err)
+	}
+	bundle, err := cosign.Sign(key, data)
+	if err != nil {
+		return "", output.Errorf("Signing failed: %v", err)
+	}
+	encoded, err := json.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(bundlePath, append(encoded, '\n'), 0644); err != nil {
+		return "", output.Errorf("Cannot write the bundle: %v", err)
+	}
+	return "key", nil
+}
+
+// cosignSignBlob runs 'cosign sign-blob' on artifact with extra arguments,
+// writing its bundle to bundlePath. cosign prompts for a password or
+// opens a browser for OIDC as it needs to.
+func cosignSignBlob(artifact, bundlePath string, extra ...string) error {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return output.Errorf("This needs cosign, which isn't installed: %s", cosignInstall)
+	}
+	args := append([]string{"sign-blob", "--yes", "--bundle", bundlePath}, extra...)
+	c := exec.Command("cosign", append(args, artifact)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stderr, os.Stderr
+	if err := c.Run(); err != nil {
+		return output.Errorf("cosign sign-blob failed: %v", err)
+	}
+	return nil
+}
+
+// artifactSignature returns the signature bundle stored beside artifact,
+// or nil if it isn't signed. A bundle older than the artifact was made for
+// an earlier build, and is an error.
+func artifactSignature(artifact string) ([]byte, error) {
+	bundlePath := artifact + cosign.BundleSuffix
+	bundleInfo, err := os.Stat(bundlePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(artifact); err == nil && info.ModTime().After(bundleInfo.ModTime()) {
+		return nil, fmt.Errorf("%s is older than %s; sign it again with 'cyfr sign'", bundlePath, artifact)
+	}
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cosign.ParseBundle(data); err != nil {
+		return nil, fmt.Errorf("%s: %v", bundlePath, err)
+	}
+	return data, nil
+}
+
+// warnUnsigned warns on stderr that artifact is published without a
+// signature, if it is.
+func warnUnsigned(artifact string, signature []byte) {
+	if signature == nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s isn't signed; hosts that require signatures won't run it. 'cyfr sign' signs it.\n", artifact)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/cosign"
+)
+
+func TestSignWithKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	dir := filepath.Join("components", "catalysts", "local", "feeds", "0.1.0")
+	os.MkdirAll(dir, 0755)
+	wasmData := []byte("\x00asm\x0d\x00\x01\x00")
+	os.WriteFile(filepath.Join(dir, "catalyst.wasm"), wasmData, 0644)
+
+	for _, arg := range []string{"c:local.feeds:0.1.0", dir, filepath.Join(dir, "catalyst.wasm")} {
+		if got, err := localArtifact(arg); err != nil || got != filepath.Join(dir, "catalyst.wasm") {
+			t.Errorf("localArtifact(%q) = %q, %v", arg, got, err)
+		}
+	}
+	if _, err := localArtifact("c:local.missing:0.1.0"); err == nil {
+		t.Error("localArtifact of a missing component succeeded")
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalECPrivateKey(key)
+	os.WriteFile("cosign.key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+	artifact := filepath.Join(dir, "catalyst.wasm")
+	if sig, err := artifactSignature(artifact); sig != nil || err != nil {
+		t.Fatalf("unsigned artifact: signature = %q, %v", sig, err)
+	}
+	method, err := signWithKey("cosign.key", artifact, wasmData, artifact+cosign.BundleSuffix)
+	if err != nil || method != "key" {
+		t.Fatalf("signWithKey = %q, %v", method, err)
+	}
+
+	sig, err := artifactSignature(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := cosign.ParseBundle(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cosign.Verify(&key.PublicKey, wasmData, bundle); err != nil {
+		t.Errorf("the bundle doesn't verify: %v", err)
+	}
+
+	// Rebuilding the artifact makes the signature stale.
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(artifact, later, later)
+	if _, err := artifactSignature(artifact); err == nil || !strings.Contains(err.Error(), "sign it again") {
+		t.Errorf("stale signature: err = %v", err)
+	}
+}
+
+func TestSignWithEncryptedKeyNeedsCosign(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	dir := t.TempDir()
+	key := filepath.Join(dir, "cosign.key")
+	os.WriteFile(key, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("{}")}), 0600)
+	artifact := filepath.Join(dir, "reagent.wasm")
+	_, err := signWithKey(key, artifact, nil, artifact+cosign.BundleSuffix)
+	if err == nil || !strings.Contains(err.Error(), "cosign") {
+		t.Errorf("err = %v, want cosign to be needed", err)
+	}
+}
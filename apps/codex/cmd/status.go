@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	statusCmd.Flags().Bool("project", false, "Show the local project instead: its server, scaffold version, components and those not registered yet")
 	statusCmd.Flags().String("scope", "all", "Check specific service: opus, sanctum, emissary, arca, compendium, locus")
+	addMultiContextFlags(statusCmd)
+	addWatchFlag(statusCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(notifyCmd)
 }
@@ -15,26 +23,61 @@ var statusCmd = &cobra.Command{
 	Use:     "status",
 	Short:   "Check system health",
 	GroupID: "start",
-	Long:    "Query the health of each CYFR service. Use --scope to check a single service instead of all of them.",
+	Long:    "Query the health of each CYFR service. Use --scope to check a single service instead of all of them. Use --contexts or --all-contexts to compare several servers, and --watch to refresh the report until interrupted. With --json, the protocol version and capabilities negotiated at login are included under \"server\".\n\nWith --project, an overview of the project in the current directory is shown instead: whether its server is running and healthy, the version of its scaffold files against the CLI's, how many local components it has of each type, and which of them the server doesn't have registered yet.",
 	Example: `  cyfr status
   cyfr status --scope sanctum
-  cyfr status --json`,
-	Run: func(cmd *cobra.Command, args []string) {
+  cyfr status --json
+  cyfr status --all-contexts
+  cyfr status --watch=10s
+  cyfr status --project`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if project, _ := cmd.Flags().GetBool("project"); project {
+			if cmd.Flags().Changed("scope") || cmd.Flags().Changed("contexts") || cmd.Flags().Changed("all-contexts") {
+				return output.NewError(output.CodeInvalidArgument, "--project doesn't work with --scope, --contexts or --all-contexts")
+			}
+			return runProjectStatus(cmd)
+		}
 		scope, _ := cmd.Flags().GetString("scope")
+		toolArgs := typed.SystemArgs{
+			Action: typed.SystemActionStatus,
+			Scope:  scope,
+		}
 
-		client := newClient()
-		result, err := client.CallTool("system", map[string]any{
-			"action": "status",
-			"scope":  scope,
-		})
+		names, err := selectedContexts(cmd)
 		if err != nil {
-			output.Errorf("Failed to connect: %v", err)
+			return err
 		}
-		if flagJSON {
-			output.JSON(result)
-		} else {
-			output.KeyValue(result)
+		if names != nil {
+			return watch(cmd, func() error {
+				ctx, cancel := statusContext(cmd)
+				defer cancel()
+				results := callAcrossContexts(ctx, names, "system", toolArgs.Map())
+				printContextResults(results)
+				return contextErrors(results)
+			})
 		}
+
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		return watch(cmd, func() error {
+			ctx, cancel := statusContext(cmd)
+			defer cancel()
+			result, err := client.System(ctx, toolArgs)
+			if err != nil {
+				return output.Errorf("Failed to connect: %v", err)
+			}
+			if flagJSON {
+				if server := serverSummary(client.Client); server != nil {
+					result["server"] = server
+				}
+				output.JSON(result)
+			} else {
+				output.KeyValue(result)
+			}
+			return nil
+		})
 	},
 }
 
@@ -46,20 +89,35 @@ var notifyCmd = &cobra.Command{
 	Example: `  cyfr notify deployment.complete https://hooks.slack.com/T0/B0/xxx
   cyfr notify audit.export https://example.com/webhook`,
 	Args: cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("system", map[string]any{
-			"action": "notify",
-			"event":  args[0],
-			"target": args[1],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.System(cmd.Context(), typed.SystemArgs{
+			Action: typed.SystemActionNotify,
+			Event:  args[0],
+			Target: args[1],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
+
+// statusTimeout is how long a health check waits for an answer unless
+// --timeout says otherwise; a hung server should be reported, not waited on.
+const statusTimeout = 15 * time.Second
+
+func statusContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if flagTimeout > 0 {
+		return context.WithCancel(cmd.Context())
+	}
+	return mcp.WithTimeout(cmd.Context(), statusTimeout)
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"strings"
 
+	"github.com/cyfr/codex/internal/mcp/typed"
 	"github.com/cyfr/codex/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -32,20 +34,24 @@ var storageListCmd = &cobra.Command{
 	Long:    "List files and directories under the given path.",
 	Example: "  cyfr storage list /data/outputs",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("storage", map[string]any{
-			"action": "list",
-			"path":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Storage(cmd.Context(), typed.StorageArgs{
+			Action: typed.StorageActionList,
+			Path:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -55,20 +61,24 @@ var storageReadCmd = &cobra.Command{
 	Long:    "Read and display the contents of a file from storage.",
 	Example: "  cyfr storage read /data/outputs/result.json",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("storage", map[string]any{
-			"action": "read",
-			"path":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Storage(cmd.Context(), typed.StorageArgs{
+			Action: typed.StorageActionRead,
+			Path:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -78,21 +88,26 @@ var storageWriteCmd = &cobra.Command{
 	Long:    "Write data to a file in storage, creating it if it does not exist.",
 	Example: "  cyfr storage write /data/config.txt \"key=value\"",
 	Args:    cobra.MinimumNArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("storage", map[string]any{
-			"action": "write",
-			"path":   args[0],
-			"data":   strings.Join(args[1:], " "),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		data := strings.Join(args[1:], " ")
+		result, err := client.Storage(cmd.Context(), typed.StorageArgs{
+			Action:  typed.StorageActionWrite,
+			Path:    args[0],
+			Content: base64.StdEncoding.EncodeToString([]byte(data)),
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -102,20 +117,24 @@ var storageDeleteCmd = &cobra.Command{
 	Long:    "Permanently remove a file from storage.",
 	Example: "  cyfr storage delete /data/outputs/old-result.json",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
-		result, err := client.CallTool("storage", map[string]any{
-			"action": "delete",
-			"path":   args[0],
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := client.Storage(cmd.Context(), typed.StorageArgs{
+			Action: typed.StorageActionDelete,
+			Path:   args[0],
 		})
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
 
@@ -126,28 +145,36 @@ var storageRetentionCmd = &cobra.Command{
 	Example: `  cyfr storage retention --get
   cyfr storage retention --set
   cyfr storage retention --cleanup`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client := newClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
 
-		action := "retention"
-		toolArgs := map[string]any{"action": action}
+		toolArgs := typed.StorageArgs{Action: typed.StorageActionRetention}
 
 		if get, _ := cmd.Flags().GetBool("get"); get {
-			toolArgs["sub_action"] = "get"
+			toolArgs.RetentionAction = "get"
 		} else if set, _ := cmd.Flags().GetBool("set"); set {
-			toolArgs["sub_action"] = "set"
+			toolArgs.RetentionAction = "set"
 		} else if cleanup, _ := cmd.Flags().GetBool("cleanup"); cleanup {
-			toolArgs["sub_action"] = "cleanup"
+			toolArgs.RetentionAction = "cleanup"
 		}
 
-		result, err := client.CallTool("storage", toolArgs)
+		done := func() {}
+		if toolArgs.RetentionAction == "cleanup" {
+			done = showProgress(client.Client, "Cleaning up")
+		}
+		result, err := client.Storage(cmd.Context(), toolArgs)
+		done()
 		if err != nil {
-			output.Errorf("Failed: %v", err)
+			return output.Errorf("Failed: %v", err)
 		}
 		if flagJSON {
 			output.JSON(result)
 		} else {
 			output.KeyValue(result)
 		}
+		return nil
 	},
 }
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// maxRecentRefs bounds the references remembered from registry searches.
+const maxRecentRefs = 200
+
+// parseComponentRef parses a registry reference, with the position of the
+// problem in the error if it is malformed. With strict_refs set in the
+// config, only the canonical typed format is accepted.
+func parseComponentRef(raw string) (ref.ComponentRef, error) {
+	parse := ref.Parse
+	if loadConfigOrDefault().StrictRefs {
+		parse = ref.ParseStrict
+	}
+	r, err := parse(raw)
+	if err != nil {
+		var syntaxErr *ref.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return r, output.Errorf("%v\n  %s", err, strings.ReplaceAll(syntaxErr.Pointer(), "\n", "\n  "))
+		}
+		return r, output.Errorf("%v", err)
+	}
+	return r, nil
+}
+
+// didYouMean returns a hint naming references close to raw, to append to
+// err when the server couldn't find raw. Candidates come from the local
+// components directory and recent registry searches. It returns "" if err
+// isn't a not-found error or nothing is close.
+func didYouMean(raw string, err error) string {
+	if err == nil || !strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return ""
+	}
+	return nearMatches(raw)
+}
+
+// nearMatches returns a "did you mean" hint listing the known references
+// closest to raw, or "" if none are close.
+func nearMatches(raw string) string {
+	r, err := ref.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	r.Digest = ""
+	candidates := append(localComponentRefs(), recentRefs()...)
+	suggestions := ref.Suggest(r, candidates)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.String()
+	}
+	if len(names) == 1 {
+		return "\nDid you mean " + names[0] + "?"
+	}
+	return "\nDid you mean one of: " + strings.Join(names, ", ") + "?"
+}
+
+// localComponentRefs lists the components in the project's components
+// directory.
+func localComponentRefs() []ref.ComponentRef {
+	paths, _ := filepath.Glob(filepath.Join("components", "*", "*", "*", "*", "*.wasm"))
+	var refs []ref.ComponentRef
+	for _, p := range paths {
+		if r, err := ref.FromPath(p); err == nil {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+// recentRefsPath returns ~/.cyfr/recent-refs.json, where references seen in
+// registry search results are remembered for suggestions.
+func recentRefsPath() (string, error) {
+	dir, err := config.DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "recent-refs.json"), nil
+}
+
+// recentRefs returns the references remembered from registry searches,
+// most recent first.
+func recentRefs() []ref.ComponentRef {
+	path, err := recentRefsPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var saved []string
+	if json.Unmarshal(data, &saved) != nil {
+		return nil
+	}
+	var refs []ref.ComponentRef
+	for _, s := range saved {
+		if r, err := ref.Parse(s); err == nil {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+// rememberSearchResults adds the components in a registry search result
+// to the recent references. Failures are ignored: the list only feeds
+// suggestions.
+func rememberSearchResults(result map[string]any) {
+	components, _ := result["components"].([]any)
+	var found []string
+	for _, item := range components {
+		if c, ok := item.(map[string]any); ok {
+			if r, ok := searchResultRef(c); ok {
+				found = append(found, r.String())
+			}
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+	path, err := recentRefsPath()
+	if err != nil {
+		return
+	}
+	seen := map[string]bool{}
+	var merged []string
+	for _, list := range [][]string{found, refStrings(recentRefs())} {
+		for _, s := range list {
+			if !seen[s] && len(merged) < maxRecentRefs {
+				seen[s] = true
+				merged = append(merged, s)
+			}
+		}
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o700) == nil {
+		_ = os.WriteFile(path, data, 0o600)
+	}
+}
+
+// searchResultRef builds the reference of a component in a search result,
+// from its component_ref if the server included one.
+func searchResultRef(c map[string]any) (ref.ComponentRef, bool) {
+	if s, _ := c["component_ref"].(string); s != "" {
+		r, err := ref.Parse(s)
+		return r, err == nil
+	}
+	name, _ := c["name"].(string)
+	publisher, _ := c["publisher"].(string)
+	version, _ := c["version"].(string)
+	typ, _ := c["component_type"].(string)
+	if typ == "" {
+		typ, _ = c["type"].(string)
+	}
+	if name == "" || version == "" {
+		return ref.ComponentRef{}, false
+	}
+	if publisher == "" {
+		publisher = "local"
+	}
+	s := publisher + "." + name + ":" + version
+	if typ != "" {
+		s = typ + ":" + s
+	}
+	r, err := ref.Parse(s)
+	return r, err == nil
+}
+
+func refStrings(refs []ref.ComponentRef) []string {
+	out := make([]string, len(refs))
+	for i, r := range refs {
+		out[i] = r.String()
+	}
+	return out
+}
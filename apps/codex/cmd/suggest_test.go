@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+// chdirTemp switches to a new temporary directory for the rest of the test.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func TestDidYouMean_LocalComponents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := chdirTemp(t)
+	wasm := filepath.Join(dir, "components", "catalysts", "local", "claude", "0.1.0", "catalyst.wasm")
+	if err := os.MkdirAll(filepath.Dir(wasm), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(wasm, []byte("\x00asm"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	notFound := errors.New("Component not found: c:local.cluade:0.1.0")
+	got := didYouMean("c:local.cluade:0.1.0", notFound)
+	if want := "\nDid you mean catalyst:local.claude:0.1.0?"; got != want {
+		t.Errorf("didYouMean = %q, want %q", got, want)
+	}
+
+	if got := didYouMean("c:local.cluade:0.1.0", errors.New("connection refused")); got != "" {
+		t.Errorf("other errors should get no hint, got %q", got)
+	}
+	if got := didYouMean("c:acme.unrelated:1.0.0", notFound); got != "" {
+		t.Errorf("unrelated ref should get no hint, got %q", got)
+	}
+}
+
+func TestDidYouMean_RecentSearches(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+
+	rememberSearchResults(map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.0.0", "component_type": "catalyst"},
+		map[string]any{"component_ref": "reagent:acme.sentinel:2.0.0"},
+	}})
+	rememberSearchResults(map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.1.0", "type": "catalyst"},
+	}})
+
+	recent := refStrings(recentRefs())
+	want := []string{"catalyst:acme.sentiment:1.1.0", "catalyst:acme.sentiment:1.0.0", "reagent:acme.sentinel:2.0.0"}
+	if strings.Join(recent, " ") != strings.Join(want, " ") {
+		t.Errorf("recent refs = %v, want %v", recent, want)
+	}
+
+	got := didYouMean("c:acme.sentimnet", errors.New("Component not found"))
+	if want := "\nDid you mean one of: catalyst:acme.sentiment:1.0.0, catalyst:acme.sentiment:1.1.0, reagent:acme.sentinel:2.0.0?"; got != want {
+		t.Errorf("didYouMean = %q, want %q", got, want)
+	}
+}
+
+func TestParseComponentRef_ShowsPosition(t *testing.T) {
+	_, err := parseComponentRef("c:local.Claude:0.1.0")
+	if err == nil {
+		t.Fatal("expected an error for an uppercase name")
+	}
+	want := "at column 9: name must be lowercase, found 'C'\n  c:local.Claude:0.1.0\n          ^"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected %q in error, got: %v", want, err)
+	}
+}
+
+func TestParseComponentRef_StrictRefs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultForLocal()
+	cfg.StrictRefs = true
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseComponentRef("c:local.claude:0.1.0"); err != nil {
+		t.Fatalf("canonical reference rejected: %v", err)
+	}
+	_, err := parseComponentRef("local:claude:0.1.0")
+	if err == nil {
+		t.Fatal("expected an error for a legacy reference in strict mode")
+	}
+	want := "legacy colon-separated format is not allowed in strict mode; write catalyst:local.claude:0.1.0 (or reagent:, formula:)"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected %q in error, got: %v", want, err)
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	tagCmd.AddCommand(tagSetCmd, tagListCmd, tagRemoveCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+var tagCmd = &cobra.Command{
+	Use:     "tag",
+	Short:   "Manage version tags of published components",
+	GroupID: "component",
+	Long: `Point tags, such as stable or canary, at published versions of a component in the server's registry. A reference can name a tag in place of a version: catalyst:acme.sentiment:stable resolves to the version stable points at when it's used, so moving a tag moves every formula and command referring to it without editing them.
+
+Tags are lowercase words of letters, digits and hyphens starting with a letter; "latest" and anything that reads as a version or constraint can't be used. Components in OCI registries use the registry's own tags instead.`,
+}
+
+var tagSetCmd = &cobra.Command{
+	Use:   "set <component> <tag> <version>",
+	Short: "Point a tag at a version",
+	Long:  "Point a tag at a published version of a component, creating the tag or moving it from the version it pointed at.",
+	Example: `  cyfr tag set acme.sentiment stable 1.4.2
+  cyfr tag set c:acme.sentiment canary 1.5.0-rc.1`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tag, version := args[1], args[2]
+		if err := ref.ValidateTag(tag); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "%v", err)
+		}
+		if _, err := ref.ParseVersion(version); err != nil {
+			return output.NewError(output.CodeInvalidArgument, "Cannot tag version %q: give an exact version, e.g. 1.0.0", version)
+		}
+		r, err := taggedComponent(args[0], "tag")
+		if err != nil {
+			return err
+		}
+		reference := componentName(r) + ":" + version
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callTag(cmd.Context(), client, typed.ComponentActionTag, reference, tag)
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Tagged %s as %s\n", reference, tag)
+		return nil
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:     "list <component>",
+	Aliases: []string{"ls"},
+	Short:   "Show the tags of a component",
+	Long:    "List the tags of a component and the versions they point at.",
+	Example: "  cyfr tag list acme.sentiment",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := taggedComponent(args[0], "list tags of")
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		tags, err := componentTags(cmd.Context(), client, r)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(tags))
+		for tag := range tags {
+			names = append(names, tag)
+		}
+		sort.Strings(names)
+		if flagJSON {
+			output.JSON(map[string]any{"reference": componentName(r), "tags": tags, "count": len(tags)})
+			return nil
+		}
+		if len(names) == 0 {
+			fmt.Printf("%s has no tags. Add one with 'cyfr tag set'.\n", componentName(r))
+			return nil
+		}
+		rows := make([]map[string]string, len(names))
+		for i, tag := range names {
+			rows[i] = map[string]string{"TAG": tag, "VERSION": tags[tag]}
+		}
+		output.Table([]string{"TAG", "VERSION"}, rows)
+		return nil
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:     "remove <component> <tag>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a tag",
+	Long:    "Remove a tag from a component. References naming the tag no longer resolve; the version it pointed at is kept.",
+	Example: "  cyfr tag remove acme.sentiment canary",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tag := args[1]
+		r, err := taggedComponent(args[0], "untag")
+		if err != nil {
+			return err
+		}
+		client, err := newTypedClient()
+		if err != nil {
+			return err
+		}
+		result, err := callTag(cmd.Context(), client, typed.ComponentActionUntag, componentName(r), tag)
+		if err != nil {
+			return err
+		}
+		if flagJSON {
+			output.JSON(result)
+			return nil
+		}
+		fmt.Printf("Removed tag %s from %s\n", tag, componentName(r))
+		return nil
+	},
+}
+
+// taggedComponent parses the component a tag command names, which must be
+// in the server's registry and name no version.
+func taggedComponent(raw, action string) (ref.ComponentRef, error) {
+	normalized, err := normalizeComponentRef(raw)
+	if err != nil {
+		return ref.ComponentRef{}, err
+	}
+	r, err := parseComponentRef(normalized)
+	if err != nil {
+		return ref.ComponentRef{}, err
+	}
+	if r.Registry != "" {
+		return ref.ComponentRef{}, output.NewError(output.CodeUnsupported, "Cannot %s %s: components in OCI registries use the registry's tags.", action, r)
+	}
+	if r.Version != "latest" || r.Digest != "" {
+		return ref.ComponentRef{}, output.NewError(output.CodeInvalidArgument, "Cannot %s %s: give the component without a version, e.g. %s", action, raw, componentName(r))
+	}
+	return r, nil
+}
+
+// componentName returns the reference r names without its version or
+// digest, e.g. "catalyst:acme.sentiment".
+func componentName(r ref.ComponentRef) string {
+	s := r.Namespace + "." + r.Name
+	if r.Registry != "" {
+		s = r.Registry + "/" + r.Repository()
+	}
+	if r.Type != "" {
+		s = r.Type + ":" + s
+	}
+	return s
+}
+
+// callTag asks the registry to tag or untag reference, as action says.
+func callTag(ctx context.Context, client *typed.Client, action typed.ComponentAction, reference, tag string) (map[string]any, error) {
+	result, err := client.Component(ctx, typed.ComponentArgs{
+		Action:    action,
+		Reference: reference,
+		Tag:       tag,
+	})
+	if err != nil {
+		return nil, output.Errorf("Cannot %s %s: %v%s", action, reference, err, didYouMean(reference, err))
+	}
+	return result, nil
+}
+
+// componentTags returns the tags of the component r names, mapped to the
+// versions they point at. The registry may report them as a map or as a
+// list of {tag, version} entries.
+func componentTags(ctx context.Context, client *mcp.Client, r ref.ComponentRef) (map[string]string, error) {
+	name := componentName(r)
+	result, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionListTags,
+		Reference: name,
+	})
+	if err != nil {
+		return nil, output.Errorf("Cannot list tags of %s: %v%s", name, err, didYouMean(name, err))
+	}
+	tags := map[string]string{}
+	switch v := result["tags"].(type) {
+	case map[string]any:
+		for tag, version := range v {
+			if s, ok := version.(string); ok {
+				tags[tag] = s
+			}
+		}
+	case []any:
+		for _, item := range v {
+			entry, _ := item.(map[string]any)
+			tag, _ := entry["tag"].(string)
+			version, _ := entry["version"].(string)
+			if tag != "" && version != "" {
+				tags[tag] = version
+			}
+		}
+	}
+	return tags, nil
+}
+
+// resolveTag replaces a tag in a reference to the server's registry with
+// the version it points at. The reference is returned unchanged if it
+// doesn't name a tag.
+func resolveTag(ctx context.Context, client *mcp.Client, raw string) (string, error) {
+	r, err := parseComponentRef(raw)
+	if err != nil {
+		return "", err
+	}
+	if r.Registry != "" || r.Digest != "" || !ref.IsTag(r.Version) {
+		return raw, nil
+	}
+	tags, err := componentTags(ctx, client, r)
+	if err != nil {
+		return "", err
+	}
+	tag := r.Version
+	version, ok := tags[tag]
+	if !ok {
+		return "", output.NewError(output.CodeNotFound, "Cannot resolve %s: no such tag; 'cyfr tag list %s' shows its tags", r, componentName(r))
+	}
+	r.Version = version
+	fmt.Fprintf(os.Stderr, "Resolved %s to %s\n", tag, r)
+	return r.String(), nil
+}
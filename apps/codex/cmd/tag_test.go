@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestTag_Mock(t *testing.T) {
+	if out := runCLI(t, "tag", "set", "r:local.hello", "stable", "0.1.0"); !strings.Contains(out, "Tagged reagent:local.hello:0.1.0 as stable") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "tag", "list", "r:local.hello"); !strings.Contains(out, "stable  0.1.0") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "tag", "remove", "r:local.hello", "stable"); !strings.Contains(out, "Removed tag stable") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestTaggedComponent(t *testing.T) {
+	if _, err := taggedComponent("r:local.hello:0.1.0", "tag"); output.Code(err) != output.CodeInvalidArgument {
+		t.Errorf("versioned: err = %v, want %s", err, output.CodeInvalidArgument)
+	}
+	if _, err := taggedComponent("c:ghcr.io/acme/sentiment", "tag"); output.Code(err) != output.CodeUnsupported {
+		t.Errorf("OCI: err = %v, want %s", err, output.CodeUnsupported)
+	}
+	r, err := taggedComponent("r:local.hello", "tag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := componentName(r); got != "reagent:local.hello" {
+		t.Errorf("componentName = %q", got)
+	}
+}
+
+func TestResolveTag(t *testing.T) {
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.list_tags"] = map[string]any{"tags": []any{
+		map[string]any{"tag": "stable", "version": "1.4.2"},
+		map[string]any{"tag": "canary", "version": "1.5.0-rc.1"},
+	}}
+	srv := mockserver.New(f)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := mcp.NewClient(ts.URL)
+
+	got, err := resolveTag(context.Background(), client, "c:acme.sentiment:stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "catalyst:acme.sentiment:1.4.2" {
+		t.Errorf("resolveTag = %q", got)
+	}
+	calls := srv.Calls()
+	if args := calls[len(calls)-1].Args; args["action"] != "list_tags" || args["reference"] != "catalyst:acme.sentiment" {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, err := resolveTag(context.Background(), client, "c:acme.sentiment:beta"); output.Code(err) != output.CodeNotFound {
+		t.Errorf("missing tag: err = %v, want %s", err, output.CodeNotFound)
+	}
+	for _, raw := range []string{"c:acme.sentiment:1.4.2", "c:acme.sentiment:^1.4", "c:acme.sentiment:latest", "c:ghcr.io/acme/sentiment:stable"} {
+		if got, err := resolveTag(context.Background(), client, raw); err != nil || got != raw {
+			t.Errorf("resolveTag(%q) = %q, %v; want it unchanged", raw, got, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// callLog collects the statistics of every request made with --timing.
+var callLog struct {
+	mu    sync.Mutex
+	calls []mcp.CallStats
+}
+
+// recordCall is the OnCall callback used with --timing.
+func recordCall(s mcp.CallStats) {
+	callLog.mu.Lock()
+	defer callLog.mu.Unlock()
+	callLog.calls = append(callLog.calls, s)
+}
+
+// timingCalls returns the requests recorded so far.
+func timingCalls() []mcp.CallStats {
+	callLog.mu.Lock()
+	defer callLog.mu.Unlock()
+	return append([]mcp.CallStats(nil), callLog.calls...)
+}
+
+// timingMeta is the output.Meta hook used with --timing: it puts the
+// recorded requests under "_meta.timing" in --json output.
+func timingMeta() map[string]any {
+	calls := timingCalls()
+	requests := make([]map[string]any, len(calls))
+	var total time.Duration
+	for i, c := range calls {
+		total += c.Duration
+		r := map[string]any{
+			"method":         c.Method,
+			"duration_ms":    c.Duration.Milliseconds(),
+			"request_bytes":  c.RequestBytes,
+			"response_bytes": c.ResponseBytes,
+			"retries":        c.Retries,
+			"status":         c.Status,
+		}
+		if c.Tool != "" {
+			r["tool"] = c.Tool
+		}
+		if c.RateLimitWait > 0 {
+			r["rate_limit_wait_ms"] = c.RateLimitWait.Milliseconds()
+		}
+		if c.Err != nil {
+			r["error"] = c.Err.Error()
+		}
+		requests[i] = r
+	}
+	return map[string]any{"timing": map[string]any{
+		"requests": requests,
+		"count":    len(calls),
+		"total_ms": total.Milliseconds(),
+	}}
+}
+
+// printTimingSummary writes a table of the recorded requests, for --timing
+// without --json.
+func printTimingSummary(w io.Writer, calls []mcp.CallStats) {
+	if len(calls) == 0 {
+		fmt.Fprintln(w, "\nTiming: no requests made")
+		return
+	}
+	var total, waited time.Duration
+	for _, c := range calls {
+		total += c.Duration
+		waited += c.RateLimitWait
+	}
+	summary := fmt.Sprintf("\nTiming: %d request(s), %s total", len(calls), formatLatency(total))
+	if waited > 0 {
+		summary += fmt.Sprintf(", %s waiting on rate limits", formatLatency(waited))
+	}
+	fmt.Fprintln(w, summary)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  REQUEST\tTIME\tSENT\tRECEIVED\tRETRIES\tSTATUS")
+	for _, c := range calls {
+		label := c.Method
+		if c.Tool != "" {
+			label += " " + c.Tool
+		}
+		status := fmt.Sprint(c.Status)
+		switch {
+		case c.Err != nil:
+			status = "failed"
+		case c.Status == 0:
+			status = "-" // not sent over HTTP
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%d\t%s\n", label, formatLatency(c.Duration),
+			output.HumanBytes(c.RequestBytes), output.HumanBytes(c.ResponseBytes), c.Retries, status)
+	}
+	tw.Flush()
+}
+
+// formatLatency rounds a duration for display: milliseconds below a second,
+// hundredths of a second above.
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+func TestTiming_JSONMeta(t *testing.T) {
+	out := runCLI(t, "key", "list", "--json", "--timing")
+	var result struct {
+		Count float64 `json:"count"`
+		Meta  struct {
+			Timing struct {
+				Count    int              `json:"count"`
+				Requests []map[string]any `json:"requests"`
+			} `json:"timing"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.Count != 1 {
+		t.Errorf("result lost: count = %v", result.Count)
+	}
+	timing := result.Meta.Timing
+	if timing.Count == 0 || len(timing.Requests) != timing.Count {
+		t.Fatalf("unexpected timing: %+v", timing)
+	}
+	last := timing.Requests[len(timing.Requests)-1]
+	if last["method"] != "tools/call" || last["tool"] != "key" {
+		t.Errorf("last request = %v, want tools/call key", last)
+	}
+	if n, _ := last["response_bytes"].(float64); n == 0 {
+		t.Errorf("response_bytes not recorded: %v", last)
+	}
+}
+
+func TestTiming_NoMetaWithoutFlag(t *testing.T) {
+	out := runCLI(t, "key", "list", "--json")
+	if strings.Contains(out, "_meta") {
+		t.Errorf("unexpected _meta without --timing:\n%s", out)
+	}
+}
+
+func TestPrintTimingSummary(t *testing.T) {
+	var buf bytes.Buffer
+	printTimingSummary(&buf, []mcp.CallStats{
+		{Method: "initialize", Duration: 120 * time.Millisecond, RequestBytes: 200, ResponseBytes: 1536, Status: 200},
+		{Method: "tools/call", Tool: "execution", Duration: 1234 * time.Millisecond, RateLimitWait: 500 * time.Millisecond, Retries: 1, Err: errors.New("boom")},
+	})
+	out := buf.String()
+	for _, want := range []string{
+		"2 request(s), 1.35s total, 500ms waiting on rate limits",
+		"initialize",
+		"1.5 KiB",
+		"tools/call execution",
+		"1.23s",
+		"failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(toolsCmd)
+	toolsCmd.AddCommand(toolsGenerateClientCmd)
+
+	toolsGenerateClientCmd.Flags().String("schema-file", "", "Read tool definitions from a tools/list JSON file instead of the server")
+	toolsGenerateClientCmd.Flags().String("save-schema", "", "Also write the fetched tool definitions to this file")
+	toolsGenerateClientCmd.Flags().String("file", "", "Write the generated code to this file instead of stdout")
+	toolsGenerateClientCmd.Flags().String("package", "typed", "Go package name for the generated code")
+}
+
+var toolsCmd = &cobra.Command{
+	Use:     "tools",
+	Short:   "Work with the server's MCP tool definitions",
+	GroupID: "advanced",
+	Long:    "Inspect the MCP tools a CYFR server exposes and generate code from their input schemas.",
+}
+
+var toolsGenerateClientCmd = &cobra.Command{
+	Use:   "generate-client",
+	Short: "Generate typed Go bindings from tool schemas",
+	Long: `Read the inputSchema of every tool from tools/list and generate Go code
+with an args struct, action constants, and a typed call method per tool.
+
+The CLI's own bindings live in internal/mcp/typed and are regenerated from
+the tools.json snapshot there with 'go generate'. Refresh the snapshot from a
+running server with --save-schema.`,
+	Example: `  cyfr tools generate-client --file tools_gen.go
+  cyfr tools generate-client --save-schema internal/mcp/typed/tools.json --file internal/mcp/typed/tools_gen.go
+  cyfr tools generate-client --schema-file tools.json --package cyfrtools`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schemaFile, _ := cmd.Flags().GetString("schema-file")
+		saveSchema, _ := cmd.Flags().GetString("save-schema")
+		outPath, _ := cmd.Flags().GetString("file")
+		pkg, _ := cmd.Flags().GetString("package")
+
+		var tools []mcp.Tool
+		if schemaFile != "" {
+			data, err := os.ReadFile(schemaFile)
+			if err != nil {
+				return output.Errorf("Failed to read %s: %v", schemaFile, err)
+			}
+			var list mcp.ToolsListResult
+			if err := json.Unmarshal(data, &list); err != nil {
+				return output.Errorf("Failed to parse %s: %v", schemaFile, err)
+			}
+			tools = list.Tools
+		} else {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			tools, err = client.ListToolsCtx(cmd.Context())
+			if err != nil {
+				return toolError(err)
+			}
+		}
+		if len(tools) == 0 {
+			return output.Error("No tools to generate bindings for.")
+		}
+
+		if saveSchema != "" {
+			data, err := json.MarshalIndent(mcp.ToolsListResult{Tools: tools}, "", "  ")
+			if err != nil {
+				return output.Errorf("Failed to encode tool definitions: %v", err)
+			}
+			if err := os.WriteFile(saveSchema, append(data, '\n'), 0644); err != nil {
+				return output.Errorf("Failed to write %s: %v", saveSchema, err)
+			}
+		}
+
+		src, err := typed.Generate(pkg, tools)
+		if err != nil {
+			return output.Errorf("Failed to generate client: %v", err)
+		}
+
+		if outPath == "" {
+			os.Stdout.Write(src)
+			return nil
+		}
+		if err := os.WriteFile(outPath, src, 0644); err != nil {
+			return output.Errorf("Failed to write %s: %v", outPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "Generated bindings for %d tools in %s\n", len(tools), outPath)
+		return nil
+	},
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/cyfr/codex/internal/output"
@@ -15,29 +16,37 @@ import (
 
 func init() {
 	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().Bool("dry-run", false, "Show what would be upgraded and how the project's scaffold files would change, without changing anything")
 }
 
 var upgradeCmd = &cobra.Command{
 	Use:     "upgrade",
 	Short:   "Upgrade cyfr to the latest version",
 	GroupID: "start",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Upgrade cyfr to the latest release, pull the latest server image, and in a project directory update the scaffold files cyfr maintains: the guides and the WIT definitions under wit/. New example components are added; existing files other than those are left alone.
+
+Each scaffold file that changes is shown as a diff first, and the version being replaced is kept under .cyfr/backup/<version>/. With --dry-run nothing is changed.`,
+	Example: `  cyfr upgrade --dry-run
+  cyfr upgrade`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
 		// 1. Fetch latest release tag from GitHub
 		resp, err := http.Get("https://api.github.com/repos/cyfrworks/cyfr/releases/latest")
 		if err != nil {
-			output.Errorf("Failed to check for updates: %v", err)
+			return output.Errorf("Failed to check for updates: %v", err)
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			output.Errorf("GitHub API returned status %d", resp.StatusCode)
+			return output.Errorf("GitHub API returned status %d", resp.StatusCode)
 		}
 
 		var release struct {
 			TagName string `json:"tag_name"`
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-			output.Errorf("Failed to parse release info: %v", err)
+			return output.Errorf("Failed to parse release info: %v", err)
 		}
 
 		latest := strings.TrimPrefix(release.TagName, "v")
@@ -46,7 +55,18 @@ var upgradeCmd = &cobra.Command{
 		current := strings.TrimPrefix(Version, "v")
 		if current == latest {
 			fmt.Printf("Already up to date (v%s)\n", current)
-			return
+			return nil
+		}
+
+		if dryRun {
+			fmt.Printf("Would upgrade cyfr from v%s to v%s.\n", current, latest)
+			if _, err := os.Stat("cyfr.yaml"); err == nil {
+				if _, _, err := previewScaffold(latest); err != nil {
+					return output.Errorf("Failed to preview scaffold files: %v", scaffoldError(err))
+				}
+			}
+			fmt.Println("Dry run: nothing was changed.")
+			return nil
 		}
 
 		fmt.Printf("Upgrading cyfr from v%s to v%s...\n", current, latest)
@@ -69,14 +89,14 @@ var upgradeCmd = &cobra.Command{
 			update.Stdout = os.Stdout
 			update.Stderr = os.Stderr
 			if err := update.Run(); err != nil {
-				output.Errorf("brew update failed: %v", err)
+				return output.Errorf("brew update failed: %v", err)
 			}
 
 			upgrade := exec.Command("brew", "upgrade", "--cask", "cyfr")
 			upgrade.Stdout = os.Stdout
 			upgrade.Stderr = os.Stderr
 			if err := upgrade.Run(); err != nil {
-				output.Errorf("brew upgrade failed: %v", err)
+				return output.Errorf("brew upgrade failed: %v", err)
 			}
 
 			fmt.Printf("Successfully upgraded cyfr to v%s\n", latest)
@@ -86,31 +106,123 @@ var upgradeCmd = &cobra.Command{
 			fmt.Printf("Download the latest release from: https://github.com/cyfrworks/cyfr/releases/tag/v%s\n", latest)
 		}
 
-		// 5. Pull latest Docker image (non-fatal)
-		if _, err := exec.LookPath("docker"); err == nil {
-			fmt.Println("Pulling latest Docker image...")
-			pull := exec.Command("docker", "pull", "ghcr.io/cyfrworks/cyfr:latest")
+		// 5. Pull latest server image (non-fatal)
+		if runtime, err := findRuntime(configuredRuntime()); err == nil {
+			fmt.Println("Pulling latest server image...")
+			pull := runtime.command(cmd.Context(), "pull", serverImage)
 			pull.Stdout = os.Stdout
 			pull.Stderr = os.Stderr
 			if err := pull.Run(); err != nil {
-				fmt.Printf("Warning: failed to pull Docker image: %v\n", err)
+				fmt.Printf("Warning: failed to pull server image: %v\n", err)
 			} else {
-				fmt.Println("Docker image updated.")
+				fmt.Println("Server image updated.")
 			}
 		} else {
-			fmt.Println("Docker not found on PATH, skipping image pull.")
+			fmt.Printf("Skipping image pull: %v.\n", err)
 		}
 
 		// 6. Update scaffold files if in a project directory (non-fatal)
 		if _, err := os.Stat("cyfr.yaml"); err == nil {
 			fmt.Println("Updating scaffold files...")
-			if err := scaffold.Update(latest); err != nil {
-				fmt.Printf("Warning: failed to update scaffold files: %v\n", err)
+			if err := updateScaffold(current, latest); err != nil {
+				fmt.Printf("Warning: failed to update scaffold files: %v\n", scaffoldError(err))
 			} else {
 				fmt.Println("Scaffold files updated.")
 			}
 		} else {
 			fmt.Println("Not in a cyfr project directory (no cyfr.yaml found), skipping scaffold update.")
 		}
+		return nil
 	},
 }
+
+// previewScaffold downloads the scaffold of version and prints how it would
+// change the project's files: a list, and a diff of each replaced file. It
+// returns the update and its changes.
+func previewScaffold(version string) (*scaffold.PendingUpdate, []scaffold.FileChange, error) {
+	spinner := output.NewSpinner("Downloading scaffold files")
+	update, err := scaffold.PrepareUpdate(version)
+	spinner.Stop()
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := update.Changes()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(changes) == 0 {
+		fmt.Println("Scaffold files are up to date.")
+		return update, nil, nil
+	}
+	fmt.Printf("Scaffold changes for v%s:\n", version)
+	for _, c := range changes {
+		if c.Created {
+			fmt.Printf("  new       %s\n", c.Path)
+		} else {
+			fmt.Printf("  modified  %s\n", c.Path)
+		}
+	}
+	for _, c := range changes {
+		if !c.Created {
+			fmt.Println("")
+			output.Diff("a/"+c.Path, "b/"+c.Path, c.Old, c.New)
+		}
+	}
+	fmt.Println("")
+	return update, changes, nil
+}
+
+// updateScaffold updates the project's scaffold files from version current
+// to latest, showing the changes and backing up the files it replaces to
+// .cyfr/backup/<current>/ first.
+func updateScaffold(current, latest string) error {
+	update, changes, err := previewScaffold(latest)
+	if err != nil {
+		return err
+	}
+	replaced := 0
+	for _, c := range changes {
+		if !c.Created {
+			replaced++
+		}
+	}
+	if replaced > 0 {
+		backup := filepath.Join(".cyfr", "backup", current)
+		if err := scaffold.Backup(changes, backup); err != nil {
+			return err
+		}
+		fmt.Printf("Backed up %d replaced file(s) to %s\n", replaced, backup)
+	}
+	if err := update.Apply(); err != nil {
+		return err
+	}
+	return recordScaffoldVersion(latest)
+}
+
+// scaffoldVersionFile records the release the project's scaffold files
+// were last downloaded from.
+var scaffoldVersionFile = filepath.Join(".cyfr", "scaffold-version")
+
+// recordScaffoldVersion records version as the project's scaffold version.
+// Development builds download the latest release, which isn't known, so
+// they record nothing.
+func recordScaffoldVersion(version string) error {
+	version = strings.TrimPrefix(version, "v")
+	if version == "dev" || version == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(scaffoldVersionFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(scaffoldVersionFile, []byte(version+"\n"), 0644)
+}
+
+// projectScaffoldVersion returns the project's recorded scaffold version,
+// or "" if it has none.
+func projectScaffoldVersion() string {
+	data, err := os.ReadFile(scaffoldVersionFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
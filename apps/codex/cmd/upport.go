@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+// composePortPattern matches the entry of a docker-compose.yml ports list
+// that publishes the server's port, e.g. - "4000:4000" or
+// - 127.0.0.1:4000:4000, capturing the host port.
+var composePortPattern = regexp.MustCompile(`(?m)^(\s*-\s*"?(?:[\d.]+:)?)(\d+)(:` + strconv.Itoa(serverPort) + `(?:/tcp)?"?\s*)$`)
+
+// composePort returns the host port a docker-compose.yml publishes the
+// server on.
+func composePort(compose string) (int, bool) {
+	m := composePortPattern.FindStringSubmatch(compose)
+	if m == nil {
+		return 0, false
+	}
+	port, err := strconv.Atoi(m[2])
+	return port, err == nil
+}
+
+// setComposePort returns compose with the server published on port.
+func setComposePort(compose string, port int) string {
+	return composePortPattern.ReplaceAllString(compose, "${1}"+strconv.Itoa(port)+"${3}")
+}
+
+// cyfrYAMLPortPattern matches the port line of cyfr.yaml.
+var cyfrYAMLPortPattern = regexp.MustCompile(`(?m)^port:\s*\d+\s*$`)
+
+// projectPort returns the port cyfr.yaml publishes the server on, or the
+// server's own if it doesn't say.
+func projectPort() int {
+	data, err := os.ReadFile("cyfr.yaml")
+	if err != nil {
+		return serverPort
+	}
+	line := cyfrYAMLPortPattern.Find(data)
+	port, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(string(line), "port:")))
+	if err != nil {
+		return serverPort
+	}
+	return port
+}
+
+// portInUse reports whether something is listening on port on this host.
+func portInUse(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return true
+	}
+	l.Close()
+	return false
+}
+
+// freePort returns the first port after port that is free, or 0 if none of
+// the next hundred is.
+func freePort(port int) int {
+	for p := port + 1; p <= port+100 && p <= 65535; p++ {
+		if !portInUse(p) {
+			return p
+		}
+	}
+	return 0
+}
+
+// composeRunning reports whether the project's containers are already
+// running, in which case they are what holds its port.
+func composeRunning() bool {
+	runtime, err := projectRuntime()
+	return err == nil && runtime.running()
+}
+
+// running reports whether the containers of the runtime's compose project
+// are running.
+func (r containerRuntime) running() bool {
+	out, err := r.compose(context.Background(), "ps", "-q", "--status", "running").Output()
+	return err == nil && strings.TrimSpace(string(out)) != ""
+}
+
+// moveProjectPort publishes the project's server on port instead of old:
+// it rewrites docker-compose.yml and cyfr.yaml, and points contexts at
+// http://localhost:old to the new port. The server's port inside its
+// container, CYFR_PORT in .env, stays as it is.
+func moveProjectPort(compose string, old, port int) error {
+	if err := os.WriteFile("docker-compose.yml", []byte(setComposePort(compose, port)), 0644); err != nil {
+		return fmt.Errorf("write docker-compose.yml: %w", err)
+	}
+	if data, err := os.ReadFile("cyfr.yaml"); err == nil {
+		updated := cyfrYAMLPortPattern.ReplaceAll(data, []byte(fmt.Sprintf("port: %d", port)))
+		if err := os.WriteFile("cyfr.yaml", updated, 0644); err != nil {
+			return fmt.Errorf("write cyfr.yaml: %w", err)
+		}
+	}
+
+	oldURL := fmt.Sprintf("http://localhost:%d", old)
+	_, err := config.Update(func(cfg *config.Config) error {
+		for _, ctx := range cfg.Contexts {
+			if strings.TrimSuffix(ctx.URL, "/") == oldURL {
+				ctx.URL = fmt.Sprintf("http://localhost:%d", port)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update contexts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+func TestComposePort(t *testing.T) {
+	tests := []struct {
+		compose string
+		want    int
+		ok      bool
+	}{
+		{defaultInitOptions().compose(), 4000, true},
+		{"    ports:\n      - 4100:4000\n", 4100, true},
+		{"    ports:\n      - \"127.0.0.1:4200:4000/tcp\"\n", 4200, true},
+		{"    ports:\n      - \"9090:9090\"\n", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := composePort(tt.compose)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("composePort(%q) = %d, %v, want %d, %v", tt.compose, got, ok, tt.want, tt.ok)
+		}
+		if !tt.ok {
+			continue
+		}
+		moved := setComposePort(tt.compose, 5000)
+		if got, _ := composePort(moved); got != 5000 {
+			t.Errorf("setComposePort didn't move the port:\n%s", moved)
+		}
+		if strings.Count(moved, ":4000") != 1 {
+			t.Errorf("setComposePort changed the container port:\n%s", moved)
+		}
+	}
+}
+
+func TestMoveProjectPort(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	cfg := &config.Config{CurrentContext: "local", Contexts: map[string]*config.Context{
+		"local": {URL: "http://localhost:4000"},
+		"cloud": {URL: "https://cyfr.example.com"},
+	}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	opts := defaultInitOptions()
+	if err := os.WriteFile("cyfr.yaml", []byte(opts.cyfrYAML()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveProjectPort(opts.compose(), 4000, 4100); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile("docker-compose.yml"); !strings.Contains(string(data), `"4100:4000"`) {
+		t.Errorf("docker-compose.yml =\n%s", data)
+	}
+	if data, _ := os.ReadFile("cyfr.yaml"); !strings.Contains(string(data), "port: 4100\n") {
+		t.Errorf("cyfr.yaml =\n%s", data)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Contexts["local"].URL; got != "http://localhost:4100" {
+		t.Errorf("local context URL = %s", got)
+	}
+	if got := cfg.Contexts["cloud"].URL; got != "https://cyfr.example.com" {
+		t.Errorf("cloud context URL changed to %s", got)
+	}
+}
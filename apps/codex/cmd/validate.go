@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+	"github.com/cyfr/codex/internal/wasm"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+var validateCmd = &cobra.Command{
+	Use:     "validate [directory]...",
+	Short:   "Check components before registering them",
+	GroupID: "component",
+	Long: `Check component directories offline, without a server: that each is in the components/<type>s/<namespace>/<name>/<version>/ layout, that its cyfr-manifest.json is well-formed and agrees with the directory, that its <type>.wasm is a component-model binary exporting the interfaces of the WIT world for its type and importing no CYFR interfaces the world doesn't declare, and that it has a README.md. Without a directory, the component in the current directory is checked.
+
+The world comes from the project's wit/<type>/world.wit, or the one built into the CLI. Missing READMEs and version differences are warnings; everything else is an error, and makes validate fail.
+
+'cyfr register' and 'cyfr publish' run the same checks on local components first; --skip-validation turns them off.`,
+	Example: `  cyfr validate
+  cyfr validate components/catalysts/local/feeds/0.1.0
+  cyfr validate components/*/local/*/* --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dirs := args
+		if len(dirs) == 0 {
+			dirs = []string{"."}
+		}
+		var results []map[string]any
+		failed := 0
+		for _, dir := range dirs {
+			if strings.HasSuffix(dir, ".wasm") || filepath.Base(dir) == componentManifestFile {
+				dir = filepath.Dir(dir)
+			}
+			problems := validateComponent(dir)
+			if countErrors(problems) > 0 {
+				failed++
+			}
+			if flagJSON {
+				results = append(results, map[string]any{"path": dir, "valid": countErrors(problems) == 0, "problems": problems})
+				continue
+			}
+			printProblems(dir, problems)
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"components": results})
+		}
+		if failed > 0 {
+			return output.NewError(output.CodeInvalidArgument, "%d of %d component(s) failed validation", failed, len(dirs))
+		}
+		return nil
+	},
+}
+
+// A validationProblem is something wrong with a component directory.
+type validationProblem struct {
+	File    string `json:"file"` // in the component's directory, or "" for the directory
+	Message string `json:"message"`
+	Warning bool   `json:"warning,omitempty"`
+}
+
+// countErrors returns how many of problems aren't warnings.
+func countErrors(problems []validationProblem) int {
+	n := 0
+	for _, p := range problems {
+		if !p.Warning {
+			n++
+		}
+	}
+	return n
+}
+
+// printProblems prints the problems of the component in dir, one per line
+// as compilers do, and a summary.
+func printProblems(dir string, problems []validationProblem) {
+	for _, p := range problems {
+		level := "error"
+		if p.Warning {
+			level = "warning"
+		}
+		fmt.Printf("%s: %s: %s\n", filepath.Join(dir, p.File), level, p.Message)
+	}
+	if n := countErrors(problems); n > 0 {
+		fmt.Printf("%s: %d error(s), %d warning(s)\n", dir, n, len(problems)-n)
+	} else {
+		fmt.Printf("%s: valid\n", dir)
+	}
+}
+
+// validateComponent checks the component in dir as 'cyfr validate'
+// describes.
+func validateComponent(dir string) []validationProblem {
+	var problems []validationProblem
+	fail := func(file, format string, args ...any) {
+		problems = append(problems, validationProblem{File: file, Message: fmt.Sprintf(format, args...)})
+	}
+	warn := func(file, format string, args ...any) {
+		problems = append(problems, validationProblem{File: file, Message: fmt.Sprintf(format, args...), Warning: true})
+	}
+
+	if !isDir(dir) {
+		fail("", "not a directory")
+		return problems
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		fail("", "%v", err)
+		return problems
+	}
+	r, err := ref.FromPath(abs)
+	if err != nil {
+		fail("", "not in the components/<type>s/<namespace>/<name>/<version>/ layout: %v", err)
+	}
+
+	// The manifest, checked against the directory if it is in the layout.
+	typ := r.Type
+	data, err := os.ReadFile(filepath.Join(dir, componentManifestFile))
+	switch {
+	case os.IsNotExist(err):
+		fail(componentManifestFile, "missing; every component needs one")
+	case err != nil:
+		fail(componentManifestFile, "%v", err)
+	default:
+		var m struct {
+			ID          string          `json:"id"`
+			Type        string          `json:"type"`
+			Version     string          `json:"version"`
+			Description string          `json:"description"`
+			WASI        json.RawMessage `json:"wasi"`
+			Secrets     json.RawMessage `json:"secrets"`
+			// Dependencies are the references the component invokes.
+			Dependencies json.RawMessage `json:"dependencies"`
+		}
+		if err := json.Unmarshal(data, &m); err != nil {
+			fail(componentManifestFile, "not valid JSON: %v", err)
+			break
+		}
+		for _, f := range []struct{ name, value string }{{"id", m.ID}, {"type", m.Type}, {"version", m.Version}, {"description", m.Description}} {
+			if f.value == "" {
+				fail(componentManifestFile, "%s is missing", f.name)
+			}
+		}
+		if typ == "" {
+			typ = m.Type
+		}
+		if m.Type != "" && m.Type != typ {
+			fail(componentManifestFile, "type is %q, but the component is in the %ss directory", m.Type, typ)
+		}
+		if r.Name != "" {
+			if want := fmt.Sprintf("%s:%s.%s", r.Type, r.Namespace, r.Name); m.ID != "" && m.ID != want {
+				fail(componentManifestFile, "id is %q, but the directory makes it %q", m.ID, want)
+			}
+			if m.Version != "" && m.Version != r.Version {
+				fail(componentManifestFile, "version is %s, but the directory is %s", m.Version, r.Version)
+			}
+		}
+		if typ == "catalyst" && m.WASI == nil {
+			fail(componentManifestFile, "wasi is missing; catalysts declare the capabilities they use")
+		}
+		if typ != "catalyst" && typ != "" && (m.WASI != nil || m.Secrets != nil) {
+			warn(componentManifestFile, "wasi and secrets have no effect on a %s", typ)
+		}
+		if m.Dependencies != nil {
+			var deps []string
+			if err := json.Unmarshal(m.Dependencies, &deps); err != nil {
+				fail(componentManifestFile, "dependencies must be a list of references")
+			}
+			for _, dep := range deps {
+				if d, err := ref.Parse(dep); err != nil {
+					fail(componentManifestFile, "dependency %q: %v", dep, err)
+				} else if d.Type == "" {
+					fail(componentManifestFile, "dependency %q has no type; add a prefix, e.g. c:%s", dep, dep)
+				}
+			}
+			if len(deps) > 0 && typ != "formula" && typ != "" {
+				fail(componentManifestFile, "dependencies are only for formulas; a %s can't invoke other components", typ)
+			}
+		}
+	}
+
+	// The binary, against the WIT world of the type.
+	if typ != "" {
+		wasmFile := typ + ".wasm"
+		if data, err := os.ReadFile(filepath.Join(dir, wasmFile)); os.IsNotExist(err) {
+			fail(wasmFile, "missing; 'cyfr build' builds it")
+		} else if err != nil {
+			fail(wasmFile, "%v", err)
+		} else if c, err := wasm.Parse(data); errors.Is(err, wasm.ErrCoreModule) {
+			fail(wasmFile, "is %v; build it for the component model, e.g. with 'cyfr build'", err)
+		} else if err != nil {
+			fail(wasmFile, "not a valid component: %v", err)
+		} else if world, err := componentWorld(abs, typ); err != nil {
+			fail(wasmFile, "can't read the %s world: %v", typ, err)
+		} else {
+			for _, p := range world.check(c) {
+				p.File = wasmFile
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+		warn("README.md", "missing; describe the component's input and output for its users")
+	}
+	return problems
+}
+
+// builtinWorlds are the WIT worlds of the component types, for projects
+// without wit/.
+var builtinWorlds = map[string]string{
+	"catalyst": `package cyfr:catalyst@0.1.0;
+world catalyst {
+    export run;
+    import cyfr:http/fetch@0.1.0;
+    import cyfr:http/streaming@0.1.0;
+    import cyfr:secrets/read@0.1.0;
+}`,
+	"reagent": `package cyfr:reagent@0.1.0;
+world reagent {
+    export compute;
+}`,
+	"formula": `package cyfr:formula@0.1.0;
+world formula {
+    export run;
+    import invoke;
+    import cyfr:mcp/tools@0.1.0;
+}`,
+}
+
+// A witWorld lists the fully qualified interfaces a world exports and
+// imports, e.g. "cyfr:catalyst/run@0.1.0".
+type witWorld struct {
+	Exports []string
+	Imports []string
+}
+
+// witLinePattern matches an export or import of a world.
+var witLinePattern = regexp.MustCompile(`^(export|import)\s+([^;\s]+)\s*;`)
+
+// parseWorld reads the world called name from WIT source. Interfaces of
+// the package itself are qualified with it.
+func parseWorld(src, name string) (*witWorld, error) {
+	m := witPackagePattern.FindStringSubmatch(src)
+	if m == nil {
+		return nil, errors.New("no package declaration")
+	}
+	pkg, version, _ := strings.Cut(m[1], "@")
+	world := &witWorld{}
+	in, found := false, false
+	for _, line := range strings.Split(src, "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "world ") {
+			in = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "world "), "{")) == name
+			found = found || in
+			continue
+		}
+		if !in {
+			continue
+		}
+		if line == "}" {
+			in = false
+			continue
+		}
+		if m := witLinePattern.FindStringSubmatch(line); m != nil {
+			iface := m[2]
+			if !strings.Contains(iface, ":") {
+				iface = pkg + "/" + iface
+				if version != "" {
+					iface += "@" + version
+				}
+			}
+			if m[1] == "export" {
+				world.Exports = append(world.Exports, iface)
+			} else {
+				world.Imports = append(world.Imports, iface)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no world %s", name)
+	}
+	return world, nil
+}
+
+// componentWorld returns the world of the component type typ for the
+// component in dir: the project's wit/<type>/world.wit in a directory
+// above it, or the built-in one.
+func componentWorld(dir, typ string) (*witWorld, error) {
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "wit", typ, "world.wit"))
+		if err == nil {
+			return parseWorld(string(data), typ)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return parseWorld(builtinWorlds[typ], typ)
+}
+
+// check compares a component with the world: it has to export each of the
+// world's interfaces and may only import CYFR interfaces the world
+// declares. Versions that differ are only warned about.
+func (w *witWorld) check(c *wasm.Component) []validationProblem {
+	var problems []validationProblem
+	unversioned := func(names []string) map[string]string {
+		m := map[string]string{}
+		for _, n := range names {
+			base, _, _ := strings.Cut(n, "@")
+			m[base] = n
+		}
+		return m
+	}
+	exports, declared := unversioned(c.Exports), unversioned(w.Imports)
+	for _, want := range w.Exports {
+		base, _, _ := strings.Cut(want, "@")
+		got, ok := exports[base]
+		switch {
+		case !ok:
+			problems = append(problems, validationProblem{Message: fmt.Sprintf("doesn't export %s (exports: %s)", want, listOrNone(c.Exports))})
+		case got != want:
+			problems = append(problems, validationProblem{Message: fmt.Sprintf("exports %s, but the world declares %s", got, want), Warning: true})
+		}
+	}
+	for _, imp := range c.Imports {
+		base, _, _ := strings.Cut(imp, "@")
+		if strings.HasPrefix(imp, "cyfr:") && declared[base] == "" {
+			problems = append(problems, validationProblem{Message: fmt.Sprintf("imports %s, which the world doesn't offer", imp)})
+		}
+	}
+	return problems
+}
+
+// listOrNone joins names, or returns "none" if there are none.
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkBeforeRegister validates the component in dir for register and
+// publish, unless cmd's --skip-validation is set. Warnings are printed to
+// stderr; errors fail.
+func checkBeforeRegister(cmd *cobra.Command, dir string) error {
+	if skip, _ := cmd.Flags().GetBool("skip-validation"); skip {
+		return nil
+	}
+	problems := validateComponent(dir)
+	var errs []string
+	for _, p := range problems {
+		msg := fmt.Sprintf("%s: %s", filepath.Join(dir, p.File), p.Message)
+		if p.Warning {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		} else {
+			errs = append(errs, "  "+msg)
+		}
+	}
+	if len(errs) > 0 {
+		return output.NewError(output.CodeInvalidArgument, "%s failed validation:\n%s\nFix it, or use --skip-validation.", dir, strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// componentBinary encodes a component with nothing but the given imports
+// of instances and exports of instance 0.
+func componentBinary(imports, exports []string) []byte {
+	leb := func(b []byte, n int) []byte {
+		for ; n >= 0x80; n >>= 7 {
+			b = append(b, byte(n)|0x80)
+		}
+		return append(b, byte(n))
+	}
+	section := func(id byte, names []string, tail ...byte) []byte {
+		body := leb(nil, len(names))
+		for _, name := range names {
+			body = append(leb(append(body, 0x00), len(name)), name...)
+			body = append(body, tail...)
+		}
+		return append(leb([]byte{id}, len(body)), body...)
+	}
+	data := []byte{0x00, 'a', 's', 'm', 0x0d, 0x00, 0x01, 0x00}
+	data = append(data, section(10, imports, 0x05, 0x00)...)
+	return append(data, section(11, exports, 0x05, 0x00, 0x00)...)
+}
+
+// problemMessages returns the messages of problems, prefixed with their
+// file and level.
+func problemMessages(problems []validationProblem) string {
+	var lines []string
+	for _, p := range problems {
+		level := "error"
+		if p.Warning {
+			level = "warning"
+		}
+		lines = append(lines, p.File+": "+level+": "+p.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestValidateComponent(t *testing.T) {
+	wasm := componentBinary([]string{"cyfr:http/fetch@0.1.0", "wasi:io/error@0.2.3"}, []string{"cyfr:catalyst/run@0.1.0"})
+	chdirTemp(t)
+	manifest := `{"id": "catalyst:local.feeds", "type": "catalyst", "version": "0.1.0", "description": "Feeds", "wasi": {"http": true}}`
+	dir := writeComponent(t, "catalyst", "feeds", manifest, map[string]string{"catalyst.wasm": string(wasm), "README.md": "# feeds\n"})
+	if problems := validateComponent(dir); len(problems) != 0 {
+		t.Errorf("problems with a valid component:\n%s", problemMessages(problems))
+	}
+
+	// The same binary as a reagent, with a manifest that disagrees with
+	// its directory and no README.
+	manifest = `{"id": "reagent:local.other", "type": "catalyst", "version": "0.2.0", "dependencies": ["c:local.fetch:0.1.0", "local.calc:1.0.0"]}`
+	dir = writeComponent(t, "reagent", "calc", manifest, map[string]string{"reagent.wasm": string(wasm)})
+	got := problemMessages(validateComponent(dir))
+	for _, want := range []string{
+		"cyfr-manifest.json: error: description is missing",
+		`cyfr-manifest.json: error: type is "catalyst", but the component is in the reagents directory`,
+		`cyfr-manifest.json: error: id is "reagent:local.other", but the directory makes it "reagent:local.calc"`,
+		"cyfr-manifest.json: error: version is 0.2.0, but the directory is 0.1.0",
+		"reagent.wasm: error: doesn't export cyfr:reagent/compute@0.1.0 (exports: cyfr:catalyst/run@0.1.0)",
+		"reagent.wasm: error: imports cyfr:http/fetch@0.1.0, which the world doesn't offer",
+		"README.md: warning: missing",
+		`cyfr-manifest.json: error: dependency "local.calc:1.0.0" has no type`,
+		"cyfr-manifest.json: error: dependencies are only for formulas",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("problems don't include %q:\n%s", want, got)
+		}
+	}
+
+	// A core module, and a project world with another version.
+	os.MkdirAll("wit/formula", 0755)
+	os.WriteFile("wit/formula/world.wit", []byte("package cyfr:formula@0.2.0;\nworld formula {\n    export run; // the entry point\n}\n"), 0644)
+	manifest = `{"id": "formula:local.f", "type": "formula", "version": "0.1.0", "description": "F"}`
+	dir = writeComponent(t, "formula", "f", manifest, map[string]string{"formula.wasm": "\x00asm\x01\x00\x00\x00", "README.md": "# f\n"})
+	if got := problemMessages(validateComponent(dir)); !strings.Contains(got, "formula.wasm: error: is a core WebAssembly module") {
+		t.Errorf("core module problems:\n%s", got)
+	}
+	os.WriteFile(filepath.Join(dir, "formula.wasm"), wasm, 0644)
+	world, err := componentWorld(dir, "formula")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(world.Exports) != 1 || world.Exports[0] != "cyfr:formula/run@0.2.0" {
+		t.Errorf("project world exports = %v", world.Exports)
+	}
+}
+
+func TestCheckBeforeRegister(t *testing.T) {
+	chdirTemp(t)
+	dir := writeComponent(t, "reagent", "r", `{"type": "reagent"}`, map[string]string{})
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("skip-validation", false, "")
+	err := checkBeforeRegister(cmd, dir)
+	if err == nil || !strings.Contains(err.Error(), "reagent.wasm: missing") || !strings.Contains(err.Error(), "--skip-validation") {
+		t.Errorf("error = %v", err)
+	}
+	cmd.Flags().Set("skip-validation", "true")
+	if err := checkBeforeRegister(cmd, dir); err != nil {
+		t.Errorf("with --skip-validation: %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+
+	"github.com/cyfr/codex/internal/cosign"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	verifyCmd.Flags().StringArray("key", nil, "PEM public key to trust besides the context's trusted_keys (repeatable)")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// The modes of a context's verify_signatures setting.
+const (
+	verifyEnforce = "enforce"
+	verifyWarn    = "warn"
+	verifyOff     = "off"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:     "verify [type] <reference|path>...",
+	Short:   "Verify component digests and signatures",
+	GroupID: "component",
+	Long: `Check that components are what they claim to be: the artifact is downloaded and hashed against the digest the registry records for it (and a @sha256: pin, if given), and its signature bundle, made by 'cyfr sign', is checked against the trusted keys. A local .wasm file or component directory is checked with the bundle beside it.
+
+The trusted keys are the context's trusted_keys plus any given with --key. Keyless signatures, which carry a certificate instead, can't be checked against keys; use 'cosign verify-blob' for those.
+
+With a context's verify_signatures set to "enforce", pull and run do the same checks first and refuse components that fail them; "warn" only warns. Set both with 'cyfr context add --verify-signatures enforce --trusted-key cosign.pub', or in the context in config.json.`,
+	Example: `  cyfr verify c:acme.sentiment:1.0.0
+  cyfr verify c:ghcr.io/acme/sentiment:1.0.0 --key acme.pub
+  cyfr verify components/catalysts/local/my-tool/0.1.0/ --key cosign.pub`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extra, _ := cmd.Flags().GetStringArray("key")
+		keys, err := trustedKeys(extra)
+		if err != nil {
+			return err
+		}
+		// Local paths are checked as they are; the rest are references.
+		var refs, names []string
+		for _, arg := range args {
+			if isComponentPath(arg) {
+				refs = append(refs, arg)
+			} else {
+				names = append(names, arg)
+			}
+		}
+		if len(names) > 0 {
+			named, err := componentRefArgs(names)
+			if err != nil {
+				return err
+			}
+			refs = append(refs, named...)
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		results := make([]*verification, len(refs))
+		failed := 0
+		for i, raw := range refs {
+			if results[i], err = verifyComponent(cmd.Context(), client, raw, keys); err != nil {
+				return err
+			}
+			if !results[i].Verified {
+				failed++
+			}
+		}
+		if flagJSON {
+			output.JSON(map[string]any{"components": results})
+		} else {
+			for _, v := range results {
+				v.print()
+			}
+		}
+		if failed > 0 {
+			return output.NewError(output.CodeUnverified, "%d of %d component(s) failed verification", failed, len(refs))
+		}
+		return nil
+	},
+}
+
+// A trustedKey is a public key signatures are checked against.
+type trustedKey struct {
+	Path string
+	Key  *ecdsa.PublicKey
+}
+
+// trustedKeys reads the active context's trusted keys and the key files
+// in extra.
+func trustedKeys(extra []string) ([]trustedKey, error) {
+	var paths []string
+	if c := activeContext(); c != nil {
+		paths = append(paths, c.TrustedKeys...)
+	}
+	var keys []trustedKey
+	for _, path := range append(paths, extra...) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, output.NewError(output.CodeConfig, "Cannot read trusted key: %v", err)
+		}
+		key, err := cosign.ParsePublicKey(data)
+		if err != nil {
+			return nil, output.NewError(output.CodeConfig, "Cannot use trusted key %s: %v", path, err)
+		}
+		keys = append(keys, trustedKey{Path: path, Key: key})
+	}
+	return keys, nil
+}
+
+// A verification is the outcome of verifyComponent.
+type verification struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest,omitempty"`
+	Signed    bool   `json:"signed"`
+	// Verified is set if the digest matched and a trusted key verified
+	// the signature; Key is that key.
+	Verified bool   `json:"verified"`
+	Key      string `json:"key,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+}
+
+// verifyComponent downloads the artifact of raw, a reference or a local
+// path, and checks its digest and its signature against keys. A failed
+// check is reported in the verification; the error is for what kept the
+// checks from being made.
+func verifyComponent(ctx context.Context, client *mcp.Client, raw string, keys []trustedKey) (*verification, error) {
+	v := &verification{Reference: raw}
+	var data, signature []byte
+	switch {
+	case isComponentPath(raw):
+		artifact, err := localArtifact(raw)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = os.ReadFile(artifact); err != nil {
+			return nil, output.Errorf("Cannot verify %s: %v", raw, err)
+		}
+		v.Digest = artifactDigest(data)
+		signature, _ = os.ReadFile(artifact + cosign.BundleSuffix)
+
+	default:
+		resolved, err := resolveConstraint(ctx, client, raw)
+		if err != nil {
+			return nil, err
+		}
+		if resolved, err = resolveLatest(ctx, client, resolved); err != nil {
+			return nil, err
+		}
+		v.Reference = resolved
+		if r, ok := registryRef(resolved); ok {
+			artifact, err := newOCIClient(r.Registry).Pull(ctx, r.Registry, r.Repository(), r.Version)
+			if err != nil {
+				return nil, output.Errorf("Cannot verify %s: %v", r.Unpinned(), err)
+			}
+			data, signature, v.Digest = artifact.Data, artifact.Signature, artifact.Digest
+			if r.Digest != "" && !sameDigest(r.Digest, v.Digest) {
+				v.Problem = fmt.Sprintf("digest is %s, but the reference is pinned to %s", v.Digest, r.Digest)
+				return v, nil
+			}
+			break
+		}
+		normalized, pinned, err := unpinReference(resolved)
+		if err != nil {
+			return nil, err
+		}
+		if data, signature, v.Digest, err = registryArtifact(ctx, client, normalized); err != nil {
+			return nil, err
+		}
+		if got := artifactDigest(data); !sameDigest(got, v.Digest) {
+			v.Problem = fmt.Sprintf("the artifact hashes to %s, but the registry records %s", got, withAlgorithm(v.Digest))
+			return v, nil
+		}
+		if pinned != "" && !sameDigest(pinned, v.Digest) {
+			v.Problem = fmt.Sprintf("digest is %s, but the reference is pinned to %s", withAlgorithm(v.Digest), pinned)
+			return v, nil
+		}
+	}
+
+	v.Signed = signature != nil
+	if !v.Signed {
+		v.Problem = "not signed"
+		return v, nil
+	}
+	bundle, err := cosign.ParseBundle(signature)
+	if err != nil {
+		v.Problem = err.Error()
+		return v, nil
+	}
+	if len(keys) == 0 {
+		v.Problem = "no trusted keys to check the signature against"
+		return v, nil
+	}
+	for _, k := range keys {
+		if cosign.Verify(k.Key, data, bundle) == nil {
+			v.Verified, v.Key = true, k.Path
+			return v, nil
+		}
+	}
+	v.Problem = "the signature isn't by a trusted key"
+	if bundle.Cert != "" {
+		v.Problem = "keyless signatures can't be checked against trusted keys; use 'cosign verify-blob'"
+	}
+	return v, nil
+}
+
+// registryArtifact downloads the artifact of reference from the server's
+// registry, returning it with the signature bundle and digest the
+// registry records for it.
+func registryArtifact(ctx context.Context, client *mcp.Client, reference string) (data, signature []byte, digest string, err error) {
+	info, err := typed.New(client).Component(ctx, typed.ComponentArgs{
+		Action:    typed.ComponentActionInspect,
+		Reference: reference,
+	})
+	if err != nil {
+		return nil, nil, "", output.Errorf("Cannot verify %s: %v%s", reference, err, didYouMean(reference, err))
+	}
+	digest, _ = info["digest"].(string)
+	if digest == "" {
+		return nil, nil, "", output.Errorf("Cannot verify %s: the server did not report a digest for it.", reference)
+	}
+	if s, ok := info["signature"].(string); ok && s != "" {
+		signature = []byte(s)
+	}
+	if data, err = downloadBlob(ctx, client, withAlgorithm(digest)); err != nil {
+		return nil, nil, "", output.Errorf("Cannot verify %s: %v", reference, err)
+	}
+	return data, signature, digest, nil
+}
+
+// print prints the verification for people.
+func (v *verification) print() {
+	if v.Verified {
+		fmt.Printf("%s: verified (%s, signed by %s)\n", v.Reference, withAlgorithm(v.Digest), v.Key)
+	} else {
+		fmt.Printf("%s: not verified: %s\n", v.Reference, v.Problem)
+	}
+}
+
+// signatureMode returns the active context's verify_signatures setting.
+func signatureMode() (string, error) {
+	c := activeContext()
+	if c == nil || c.VerifySignatures == "" {
+		return verifyOff, nil
+	}
+	switch c.VerifySignatures {
+	case verifyEnforce, verifyWarn, verifyOff:
+		return c.VerifySignatures, nil
+	}
+	return "", output.NewError(output.CodeConfig, "Invalid verify_signatures %q in the context (use enforce, warn or off)", c.VerifySignatures)
+}
+
+// checkBeforeUse verifies raw before pull or run fetches or executes it,
+// as the active context's verify_signatures says: in enforce mode a
+// component that fails is refused, in warn mode it is warned about.
+//
+// It returns the digest of the artifact that was checked, if raw is a
+// reference and a check was made, so the caller can pin the pull or run
+// to that artifact and not whatever the registry serves next.
+func checkBeforeUse(ctx context.Context, client *mcp.Client, raw string) (string, error) {
+	mode, err := signatureMode()
+	if err != nil || mode == verifyOff {
+		return "", err
+	}
+	keys, err := trustedKeys(nil)
+	if err != nil {
+		return "", err
+	}
+	v, err := verifyComponent(ctx, client, raw, keys)
+	if err != nil {
+		if mode == verifyWarn {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+			return "", nil
+		}
+		return "", err
+	}
+	var digest string
+	if v.Digest != "" && !isComponentPath(raw) {
+		digest = withAlgorithm(v.Digest)
+	}
+	if v.Verified {
+		return digest, nil
+	}
+	if mode == verifyWarn {
+		fmt.Fprintf(os.Stderr, "Warning: %s isn't verified: %s\n", v.Reference, v.Problem)
+		return digest, nil
+	}
+	return "", output.NewError(output.CodeUnverified, "Refusing %s: %s. The context's verify_signatures is enforce; see 'cyfr verify --help'.", v.Reference, v.Problem)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/config"
+	"github.com/cyfr/codex/internal/cosign"
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+)
+
+// newTrustedKey returns a new signing key and the trusted key of its
+// public half, saved as a PEM file in dir.
+func newTrustedKey(t *testing.T, dir string) (*ecdsa.PrivateKey, trustedKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := cosign.MarshalPublicKey(&key.PublicKey)
+	path := filepath.Join(dir, "cosign.pub")
+	if err := os.WriteFile(path, pub, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return key, trustedKey{Path: path, Key: &key.PublicKey}
+}
+
+func bundleJSON(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+	b, err := cosign.Sign(key, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ := json.Marshal(b)
+	return encoded
+}
+
+func TestVerifyLocal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	dir := filepath.Join("components", "reagents", "local", "r", "0.1.0")
+	os.MkdirAll(dir, 0755)
+	artifact := filepath.Join(dir, "reagent.wasm")
+	data := []byte("\x00asm\x0d\x00\x01\x00")
+	os.WriteFile(artifact, data, 0644)
+	key, trusted := newTrustedKey(t, t.TempDir())
+	_, other := newTrustedKey(t, t.TempDir())
+
+	v, err := verifyComponent(context.Background(), nil, dir, []trustedKey{trusted})
+	if err != nil || v.Verified || v.Problem != "not signed" {
+		t.Errorf("unsigned: %+v, %v", v, err)
+	}
+
+	os.WriteFile(artifact+cosign.BundleSuffix, bundleJSON(t, key, data), 0644)
+	v, err = verifyComponent(context.Background(), nil, dir, []trustedKey{other, trusted})
+	if err != nil || !v.Verified || v.Key != trusted.Path {
+		t.Errorf("signed: %+v, %v", v, err)
+	}
+	v, err = verifyComponent(context.Background(), nil, artifact, []trustedKey{other})
+	if err != nil || v.Verified || !strings.Contains(v.Problem, "trusted key") {
+		t.Errorf("signed by an untrusted key: %+v, %v", v, err)
+	}
+}
+
+func TestVerifyRegistry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	data := []byte("\x00asm\x01\x00\x00\x00")
+	key, trusted := newTrustedKey(t, t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.get_blob"] = map[string]any{"bytes": base64.StdEncoding.EncodeToString(data)}
+	f.Responses["component.inspect"] = map[string]any{"digest": artifactDigest(data), "signature": string(bundleJSON(t, key, data))}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	v, err := verifyComponent(context.Background(), client, "r:local.hello:0.1.0", []trustedKey{trusted})
+	if err != nil || !v.Verified {
+		t.Errorf("verification = %+v, %v", v, err)
+	}
+
+	// The registry records another digest than its artifact has.
+	f.Responses["component.inspect"] = map[string]any{"digest": artifactDigest([]byte("other")), "signature": string(bundleJSON(t, key, data))}
+	v, err = verifyComponent(context.Background(), client, "r:local.hello:0.1.0", []trustedKey{trusted})
+	if err != nil || v.Verified || !strings.Contains(v.Problem, "hashes to") {
+		t.Errorf("digest mismatch: %+v, %v", v, err)
+	}
+}
+
+func TestCheckBeforeUse(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	os.WriteFile("reagent.wasm", []byte("\x00asm"), 0644)
+	_, trusted := newTrustedKey(t, t.TempDir())
+
+	cfg := config.DefaultForLocal()
+	cfg.Contexts[cfg.CurrentContext].TrustedKeys = []string{trusted.Path}
+	for _, tt := range []struct {
+		mode    string
+		refused bool
+	}{{"", false}, {"off", false}, {"warn", false}, {"enforce", true}} {
+		cfg.Contexts[cfg.CurrentContext].VerifySignatures = tt.mode
+		if err := cfg.Save(); err != nil {
+			t.Fatal(err)
+		}
+		digest, err := checkBeforeUse(context.Background(), nil, "./reagent.wasm")
+		if digest != "" {
+			t.Errorf("mode %q: digest = %q for a local path", tt.mode, digest)
+		}
+		if refused := err != nil; refused != tt.refused {
+			t.Errorf("mode %q: err = %v, refused = %v, want %v", tt.mode, err, refused, tt.refused)
+		}
+		if err != nil && output.Code(err) != output.CodeUnverified {
+			t.Errorf("mode %q: code = %s, want %s", tt.mode, output.Code(err), output.CodeUnverified)
+		}
+	}
+}
+
+func TestPullPinsCheckedDigest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chdirTemp(t)
+	data := []byte("\x00asm\x01\x00\x00\x00")
+	key, trusted := newTrustedKey(t, t.TempDir())
+	cfg := config.DefaultForLocal()
+	cfg.Contexts[cfg.CurrentContext].TrustedKeys = []string{trusted.Path}
+	cfg.Contexts[cfg.CurrentContext].VerifySignatures = verifyEnforce
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.get_blob"] = map[string]any{"bytes": base64.StdEncoding.EncodeToString(data)}
+	f.Responses["component.inspect"] = map[string]any{"digest": artifactDigest(data), "signature": string(bundleJSON(t, key, data))}
+	// The registry serves another artifact by the time it is pulled.
+	f.Responses["component.pull"] = map[string]any{"digest": artifactDigest([]byte("other"))}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	digest, err := checkBeforeUse(context.Background(), client, "r:local.hello:0.1.0")
+	if err != nil || digest != withAlgorithm(artifactDigest(data)) {
+		t.Fatalf("checkBeforeUse = %q, %v", digest, err)
+	}
+	_, err = pullComponent(context.Background(), client, "r:local.hello:0.1.0")
+	if err == nil || !strings.Contains(err.Error(), "Digest mismatch") {
+		t.Errorf("pull of a swapped artifact: err = %v, want a digest mismatch", err)
+	}
+}
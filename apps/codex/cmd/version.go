@@ -15,7 +15,6 @@ var (
 )
 
 func init() {
-	versionCmd.Flags().Bool("json", false, "Output as JSON")
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -23,16 +22,16 @@ var versionCmd = &cobra.Command{
 	Use:     "version",
 	Short:   "Print the cyfr CLI version",
 	GroupID: "start",
-	Run: func(cmd *cobra.Command, args []string) {
-		jsonFlag, _ := cmd.Flags().GetBool("json")
-		if jsonFlag || flagJSON {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagJSON {
 			output.JSON(map[string]any{
 				"version": Version,
 				"commit":  Commit,
 				"date":    Date,
 			})
-			return
+			return nil
 		}
 		fmt.Printf("cyfr version %s (commit: %s, built: %s)\n", Version, Commit, Date)
+		return nil
 	},
 }
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+// versionSearchLimit bounds the registry search used to list a
+// component's published versions.
+const versionSearchLimit = 500
+
+// resolveConstraint checks a registry reference and replaces a version
+// constraint in it (e.g. "c:acme.sentiment:^1.2") with the highest
+// published version that satisfies it. A tag (e.g. "stable") is replaced
+// with the version it points at. Malformed references are an error;
+// references with an exact version or "latest" are returned unchanged.
+func resolveConstraint(ctx context.Context, client *mcp.Client, raw string) (string, error) {
+	raw, err := resolveTag(ctx, client, raw)
+	if err != nil {
+		return "", err
+	}
+	r, err := parseComponentRef(raw)
+	if err != nil {
+		return "", err
+	}
+	if !r.HasConstraint() {
+		return raw, nil
+	}
+	versions, err := availableVersions(ctx, client, r)
+	if err != nil {
+		return "", toolError(err)
+	}
+	version, err := ref.ResolveVersion(r.Version, versions)
+	if err != nil {
+		hint := ""
+		if len(versions) == 0 && r.Registry == "" {
+			hint = nearMatches(raw)
+		}
+		return "", output.Errorf("Cannot resolve %s: %v%s", r, err, hint)
+	}
+	constraint := r.Version
+	r.Version = version
+	fmt.Fprintf(os.Stderr, "Resolved %s to %s\n", constraint, r)
+	return r.String(), nil
+}
+
+// resolveLatest replaces "latest" in a registry reference with the highest
+// published release, picked by semver precedence rather than left to the
+// registry. The reference is returned unchanged if it names a version, is
+// pinned to a digest, or no release can be listed.
+func resolveLatest(ctx context.Context, client *mcp.Client, raw string) (string, error) {
+	r, err := parseComponentRef(raw)
+	if err != nil {
+		return "", err
+	}
+	if r.Version != "latest" || r.Digest != "" {
+		return raw, nil
+	}
+	versions, err := availableVersions(ctx, client, r)
+	if err != nil {
+		if isInterrupted(err) {
+			return "", errInterrupted()
+		}
+		return raw, nil
+	}
+	latest, ok := ref.Latest(versions)
+	if !ok {
+		return raw, nil
+	}
+	r.Version = latest
+	fmt.Fprintf(os.Stderr, "Resolved latest to %s\n", r)
+	return r.String(), nil
+}
+
+// availableVersions lists the versions of r's component that constraints
+// and "latest" resolve to: the tags of its repository for a reference into
+// an OCI registry, or the versions found in the server's registry that
+// aren't yanked.
+func availableVersions(ctx context.Context, client *mcp.Client, r ref.ComponentRef) ([]string, error) {
+	if r.Registry != "" {
+		return ociTags(ctx, r)
+	}
+	versions, _, err := publishedVersions(ctx, client, r)
+	return versions, err
+}
+
+// A versionEntry is a published version in the history --versions lists.
+type versionEntry struct {
+	Version     string `json:"version"`
+	PublishedAt string `json:"published_at,omitempty"`
+	Digest      string `json:"digest,omitempty"`
+	Yanked      bool   `json:"yanked,omitempty"`
+}
+
+// listVersions prints the version history of a component, highest first,
+// marking the one "latest" resolves to and those that are yanked. Versions
+// in the server's registry have their publish date and digest; the tags
+// of an OCI repository are only listed.
+func listVersions(ctx context.Context, client *mcp.Client, raw string) error {
+	r, err := parseComponentRef(raw)
+	if err != nil {
+		return err
+	}
+	var history []versionEntry
+	if r.Registry != "" {
+		var tags []string
+		tags, err = ociTags(ctx, r)
+		for _, tag := range tags {
+			history = append(history, versionEntry{Version: tag})
+		}
+	} else {
+		history, err = versionHistory(ctx, client, r)
+	}
+	if err != nil {
+		return toolError(err)
+	}
+	slices.SortStableFunc(history, func(a, b versionEntry) int { return ref.Compare(b.Version, a.Version) })
+	var versions, yanked, released []string
+	for _, e := range history {
+		versions = append(versions, e.Version)
+		if e.Yanked {
+			yanked = append(yanked, e.Version)
+		} else {
+			released = append(released, e.Version)
+		}
+	}
+	latest, _ := ref.Latest(released)
+
+	r.Version, r.Digest = "", ""
+	name := strings.TrimSuffix(r.String(), ":")
+	if flagJSON {
+		result := map[string]any{"reference": name, "versions": versions, "count": len(versions)}
+		if latest != "" {
+			result["latest"] = latest
+		}
+		if len(yanked) > 0 {
+			result["yanked"] = yanked
+		}
+		if r.Registry == "" {
+			result["history"] = history
+		}
+		output.JSON(result)
+		return nil
+	}
+	if len(versions) == 0 {
+		fmt.Printf("No published versions of %s.\n", name)
+		return nil
+	}
+	status := func(v string) string {
+		switch {
+		case v == latest:
+			return "latest"
+		case slices.Contains(yanked, v):
+			return "yanked"
+		}
+		return ""
+	}
+	if r.Registry != "" {
+		for _, v := range versions {
+			if s := status(v); s != "" {
+				fmt.Printf("%s  (%s)\n", v, s)
+			} else {
+				fmt.Println(v)
+			}
+		}
+		return nil
+	}
+	rows := make([]map[string]string, len(history))
+	for i, e := range history {
+		digest := ""
+		if e.Digest != "" {
+			digest = withAlgorithm(e.Digest)
+		}
+		rows[i] = map[string]string{"VERSION": e.Version, "PUBLISHED": e.PublishedAt, "DIGEST": digest, "STATUS": status(e.Version)}
+	}
+	output.Table([]string{"VERSION", "PUBLISHED", "DIGEST", "STATUS"}, rows)
+	return nil
+}
+
+// searchComponent searches the registry for r's component. The result
+// may also contain other components.
+func searchComponent(ctx context.Context, client *mcp.Client, r ref.ComponentRef) (map[string]any, error) {
+	args := typed.ComponentArgs{
+		Action: typed.ComponentActionSearch,
+		Query:  r.Name,
+		Type:   r.Type,
+	}
+	result, err := client.CallToolPagedCtx(ctx, "component", args.Map(), "components", versionSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	rememberSearchResults(result)
+	return result, nil
+}
+
+// publishedVersions lists the versions of r's component found in the
+// registry, with those that are yanked apart.
+func publishedVersions(ctx context.Context, client *mcp.Client, r ref.ComponentRef) (versions, yanked []string, err error) {
+	result, err := searchComponent(ctx, client, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	versions, yanked = matchingVersions(result, r)
+	return versions, yanked, nil
+}
+
+// versionHistory lists the versions of r's component found in the
+// registry with when they were published and their digests.
+func versionHistory(ctx context.Context, client *mcp.Client, r ref.ComponentRef) ([]versionEntry, error) {
+	result, err := searchComponent(ctx, client, r)
+	if err != nil {
+		return nil, err
+	}
+	var history []versionEntry
+	for _, c := range matchingComponents(result, r) {
+		found, _ := searchResultRef(c)
+		e := versionEntry{Version: found.Version}
+		e.PublishedAt, _ = c["published_at"].(string)
+		if e.PublishedAt == "" {
+			e.PublishedAt, _ = c["inserted_at"].(string)
+		}
+		e.Digest, _ = c["digest"].(string)
+		e.Yanked, _ = c["yanked"].(bool)
+		history = append(history, e)
+	}
+	return history, nil
+}
+
+// matchingVersions picks the versions of r's component out of a component
+// search result, which may also contain other components. Versions marked
+// yanked are returned apart.
+func matchingVersions(result map[string]any, r ref.ComponentRef) (versions, yanked []string) {
+	for _, c := range matchingComponents(result, r) {
+		found, _ := searchResultRef(c)
+		if y, _ := c["yanked"].(bool); y {
+			yanked = append(yanked, found.Version)
+		} else {
+			versions = append(versions, found.Version)
+		}
+	}
+	return versions, yanked
+}
+
+// matchingComponents picks the versions of r's component out of a
+// component search result, as the result lists them.
+func matchingComponents(result map[string]any, r ref.ComponentRef) []map[string]any {
+	components, _ := result["components"].([]any)
+	var matching []map[string]any
+	for _, item := range components {
+		c, _ := item.(map[string]any)
+		found, ok := searchResultRef(c)
+		if !ok || found.Name != r.Name || found.Namespace != r.Namespace {
+			continue
+		}
+		if r.Type != "" && found.Type != "" && found.Type != r.Type {
+			continue
+		}
+		matching = append(matching, c)
+	}
+	return matching
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/cyfr/codex/internal/ref"
+)
+
+func TestMatchingVersions(t *testing.T) {
+	result := map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.0.0", "component_type": "catalyst"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.2.0", "type": "catalyst"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.3.0", "component_type": "reagent"},
+		map[string]any{"name": "sentiment", "publisher": "other", "version": "1.4.0", "component_type": "catalyst"},
+		map[string]any{"name": "sentiment-pro", "publisher": "acme", "version": "1.5.0", "component_type": "catalyst"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "component_type": "catalyst"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.2.1", "component_type": "catalyst", "yanked": true},
+	}}
+
+	r := ref.ComponentRef{Type: "catalyst", Namespace: "acme", Name: "sentiment", Version: "^1.0"}
+	got, yanked := matchingVersions(result, r)
+	if want := []string{"1.0.0", "1.2.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("typed: got %v, want %v", got, want)
+	}
+	if want := []string{"1.2.1"}; !reflect.DeepEqual(yanked, want) {
+		t.Errorf("yanked: got %v, want %v", yanked, want)
+	}
+
+	r.Type = ""
+	got, _ = matchingVersions(result, r)
+	if want := []string{"1.0.0", "1.2.0", "1.3.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("untyped: got %v, want %v", got, want)
+	}
+}
+
+func TestListVersions_History(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f := mockserver.DefaultFixtures()
+	f.Responses["component.search"] = map[string]any{"components": []any{
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.0.0", "component_type": "catalyst", "digest": "aaa111", "inserted_at": "2026-01-01T00:00:00Z"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.2.0", "component_type": "catalyst", "digest": "sha256:bbb222", "published_at": "2026-03-01T00:00:00Z", "inserted_at": "2026-02-01T00:00:00Z"},
+		map[string]any{"name": "sentiment", "publisher": "acme", "version": "1.3.0", "component_type": "catalyst", "yanked": true},
+	}}
+	srv := httptest.NewServer(mockserver.New(f))
+	defer srv.Close()
+	client := mcp.NewClient(srv.URL)
+
+	r := ref.ComponentRef{Type: "catalyst", Namespace: "acme", Name: "sentiment"}
+	history, err := versionHistory(context.Background(), client, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []versionEntry{
+		{Version: "1.0.0", PublishedAt: "2026-01-01T00:00:00Z", Digest: "aaa111"},
+		{Version: "1.2.0", PublishedAt: "2026-03-01T00:00:00Z", Digest: "sha256:bbb222"},
+		{Version: "1.3.0", Yanked: true},
+	}
+	if !reflect.DeepEqual(history, want) {
+		t.Errorf("history = %+v, want %+v", history, want)
+	}
+
+	flagJSON, output.TableFormat = false, ""
+	out, err := captureStdout(func() { err = listVersions(context.Background(), client, "c:acme.sentiment") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "VERSION") {
+		t.Fatalf("unexpected table:\n%s", out)
+	}
+	for i, want := range [][]string{
+		{"1.3.0", "yanked"},
+		{"1.2.0", "2026-03-01T00:00:00Z", "sha256:bbb222", "latest"},
+		{"1.0.0", "2026-01-01T00:00:00Z", "sha256:aaa111"},
+	} {
+		if got := strings.Fields(lines[i+2]); !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestResolveConstraint_Mock(t *testing.T) {
+	// The built-in fixtures publish r:local.hello:0.1.0.
+	out := runCLI(t, "inspect", "r:local.hello@^0.1")
+	if !strings.Contains(out, "hello") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	client, _ := newClient()
+	if got, _ := resolveConstraint(context.Background(), client, "r:local.hello:~0.1.0"); got != "reagent:local.hello:0.1.0" {
+		t.Errorf("resolveConstraint = %q", got)
+	}
+	if got, _ := resolveConstraint(context.Background(), client, "r:local.hello:0.1.0"); got != "r:local.hello:0.1.0" {
+		t.Errorf("exact version should pass through, got %q", got)
+	}
+}
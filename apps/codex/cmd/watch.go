@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is the refresh interval of --watch without a value.
+const defaultWatchInterval = 2 * time.Second
+
+// watching is set while a command refreshes its output with --watch, so
+// helpers know that a failure is shown in the output rather than ending
+// the command.
+var watching bool
+
+// addWatchFlag registers --watch on a command whose output can be
+// refreshed. The interval is optional, so it must be joined to the flag:
+// in "--watch 5s", 5s is an argument of the command.
+func addWatchFlag(cmd *cobra.Command) {
+	cmd.Flags().DurationP("watch", "w", 0, "Refresh the output every interval (default 2s) until interrupted; give the interval with =, e.g. --watch=5s or -w=5s, not --watch 5s")
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+}
+
+// watch runs render once and returns its error, or with --watch runs it
+// every interval until the command is interrupted. A failed refresh is
+// shown in place of the output, as the next one may succeed. On a terminal
+// each refresh clears the screen and, with colors on, highlights the lines
+// that weren't there before.
+func watch(cmd *cobra.Command, render func() error) error {
+	interval, _ := cmd.Flags().GetDuration("watch")
+	if interval <= 0 {
+		return render()
+	}
+	watching = true
+	defer func() { watching = false }()
+
+	terminal := output.IsTerminal(os.Stdout) && !flagJSON
+	var previous map[string]bool
+	for {
+		var renderErr error
+		frame, err := captureStdout(func() { renderErr = render() })
+		if err != nil {
+			return output.Errorf("Failed to refresh: %v", err)
+		}
+		if renderErr != nil && renderErr.Error() != "" {
+			frame += "Error: " + renderErr.Error() + "\n"
+		}
+		if terminal {
+			fmt.Print("\x1b[H\x1b[2J")
+			fmt.Printf("Every %s: %s    %s\n\n", interval, cmd.CommandPath(), time.Now().Format(time.TimeOnly))
+		}
+		lines := strings.Split(strings.TrimSuffix(frame, "\n"), "\n")
+		current := make(map[string]bool, len(lines))
+		for _, line := range lines {
+			current[line] = true
+			if terminal && previous != nil && !previous[line] {
+				line = output.Highlight(line)
+			}
+			fmt.Println(line)
+		}
+		previous = current
+
+		select {
+		case <-cmd.Context().Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(fn func()) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		r.Close()
+		done <- buf.String()
+	}()
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	return <-done, nil
+}
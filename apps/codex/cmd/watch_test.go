@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWatch(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		addWatchFlag(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	// Without --watch, render runs once and its error is returned.
+	runs := 0
+	err := watch(newCmd(), func() error {
+		runs++
+		return fmt.Errorf("boom")
+	})
+	if runs != 1 {
+		t.Errorf("render ran %d times without --watch, want 1", runs)
+	}
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("watch without --watch returned %v, want boom", err)
+	}
+
+	// With --watch, render runs every interval until the context is done,
+	// and errors are printed as part of the output.
+	cmd := newCmd("--watch=1ms")
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd.SetContext(ctx)
+	runs = 0
+	out, _ := captureStdout(func() {
+		err = watch(cmd, func() error {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+			return fmt.Errorf("refresh %d failed", runs)
+		})
+	})
+	if err != nil {
+		t.Errorf("watch returned %v after being interrupted", err)
+	}
+	if runs != 3 {
+		t.Errorf("render ran %d times, want 3", runs)
+	}
+	if want := "Error: refresh 1 failed\nError: refresh 2 failed\nError: refresh 3 failed\n"; out != want {
+		t.Errorf("watch printed %q, want %q", out, want)
+	}
+	if watching {
+		t.Error("watching still set after watch returned")
+	}
+
+	if d, _ := newCmd("-w").Flags().GetDuration("watch"); d != defaultWatchInterval {
+		t.Errorf("-w without a value = %v, want %v", d, defaultWatchInterval)
+	}
+}
+
+func TestWatchFlag(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want time.Duration
+		rest int
+	}{
+		{[]string{"--watch"}, defaultWatchInterval, 0},
+		{[]string{"--watch=5s"}, 5 * time.Second, 0},
+		{[]string{"-w=5s"}, 5 * time.Second, 0},
+		// The interval must be joined to the flag, as the help says.
+		{[]string{"--watch", "5s"}, defaultWatchInterval, 1},
+	} {
+		cmd := &cobra.Command{Use: "x"}
+		addWatchFlag(cmd)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		if got, _ := cmd.Flags().GetDuration("watch"); got != tt.want || len(cmd.Flags().Args()) != tt.rest {
+			t.Errorf("%v: watch = %s, args %v; want %s and %d args", tt.args, got, cmd.Flags().Args(), tt.want, tt.rest)
+		}
+	}
+}
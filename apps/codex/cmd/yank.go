@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	componentYankCmd.Flags().String("reason", "", "Why the version is withdrawn, recorded with it in the registry")
+	componentCmd.AddCommand(componentYankCmd)
+	componentCmd.AddCommand(componentUnyankCmd)
+}
+
+var componentYankCmd = &cobra.Command{
+	Use:   "yank [type] <reference>",
+	Short: "Withdraw a published version",
+	Long: `Mark a published version of a component as withdrawn in the server's registry. Yanked versions are skipped when a version constraint or "latest" is resolved, and 'cyfr publish --bump' doesn't reuse them, but a reference to the exact version still works, so nothing that already depends on it breaks. 'cyfr inspect --versions' lists them as yanked.
+
+The reference needs an exact version. 'cyfr component unyank' undoes it. Components in OCI registries can't be yanked; delete the tag there instead.`,
+	Example: `  cyfr component yank c:acme.sentiment:1.2.0
+  cyfr component yank c:acme.sentiment:1.2.0 --reason "leaks the API key into logs"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reason, _ := cmd.Flags().GetString("reason")
+		return setYanked(cmd.Context(), joinTypeShorthand(args)[0], true, reason)
+	},
+}
+
+var componentUnyankCmd = &cobra.Command{
+	Use:     "unyank [type] <reference>",
+	Short:   "Restore a yanked version",
+	Long:    `Restore a version withdrawn with 'cyfr component yank', so that version constraints and "latest" resolve to it again.`,
+	Example: `  cyfr component unyank c:acme.sentiment:1.2.0`,
+	Args:    cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setYanked(cmd.Context(), joinTypeShorthand(args)[0], false, "")
+	},
+}
+
+// setYanked yanks the published version raw names, or unyanks it.
+func setYanked(ctx context.Context, raw string, yank bool, reason string) error {
+	action := typed.ComponentActionYank
+	if !yank {
+		action = typed.ComponentActionUnyank
+	}
+	normalized, err := normalizeComponentRef(raw)
+	if err != nil {
+		return err
+	}
+	r, err := parseComponentRef(normalized)
+	if err != nil {
+		return err
+	}
+	if r.Registry != "" {
+		return output.NewError(output.CodeUnsupported, "Cannot %s %s: components in OCI registries can't be yanked.", action, r)
+	}
+	if r.Version == "" || r.Version == "latest" || r.HasConstraint() || r.Digest != "" {
+		return output.NewError(output.CodeInvalidArgument, "Cannot %s %s: give the exact version, e.g. %s:1.0.0", action, r, r)
+	}
+	client, err := newTypedClient()
+	if err != nil {
+		return err
+	}
+	result, err := yankVersion(ctx, client, normalized, action, reason)
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		output.JSON(result)
+		return nil
+	}
+	if yank {
+		fmt.Printf("Yanked %s; constraints and \"latest\" no longer resolve to it.\n", r)
+	} else {
+		fmt.Printf("Unyanked %s.\n", r)
+	}
+	return nil
+}
+
+// yankVersion asks the registry to yank or unyank reference, as action
+// says.
+func yankVersion(ctx context.Context, client *typed.Client, reference string, action typed.ComponentAction, reason string) (map[string]any, error) {
+	result, err := client.Component(ctx, typed.ComponentArgs{
+		Action:    action,
+		Reference: reference,
+		Reason:    reason,
+	})
+	if err != nil {
+		return nil, output.Errorf("Cannot %s %s: %v%s", action, reference, err, didYouMean(reference, err))
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+	"github.com/cyfr/codex/internal/mcp/mockserver"
+	"github.com/cyfr/codex/internal/mcp/typed"
+	"github.com/cyfr/codex/internal/output"
+)
+
+func TestYankVersion(t *testing.T) {
+	srv := mockserver.New(mockserver.DefaultFixtures())
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	if _, err := yankVersion(context.Background(), typed.New(mcp.NewClient(ts.URL)), "reagent:local.hello:0.1.0", typed.ComponentActionYank, "broken"); err != nil {
+		t.Fatal(err)
+	}
+	calls := srv.Calls()
+	args := calls[len(calls)-1].Args
+	if args["action"] != "yank" || args["reference"] != "reagent:local.hello:0.1.0" || args["reason"] != "broken" {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestYank_Mock(t *testing.T) {
+	if out := runCLI(t, "component", "yank", "r:local.hello:0.1.0", "--reason", "broken"); !strings.Contains(out, "Yanked reagent:local.hello:0.1.0") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if out := runCLI(t, "component", "unyank", "r:local.hello:0.1.0"); !strings.Contains(out, "Unyanked") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestSetYanked_NeedsExactVersion(t *testing.T) {
+	for _, raw := range []string{"r:local.hello", "r:local.hello:^0.1", "r:local.hello:latest"} {
+		if err := setYanked(context.Background(), raw, true, ""); output.Code(err) != output.CodeInvalidArgument {
+			t.Errorf("%s: err = %v, want %s", raw, err, output.CodeInvalidArgument)
+		}
+	}
+	if err := setYanked(context.Background(), "c:ghcr.io/acme/sentiment:1.0.0", true, ""); output.Code(err) != output.CodeUnsupported {
+		t.Errorf("OCI: err = %v, want %s", err, output.CodeUnsupported)
+	}
+}
@@ -0,0 +1,272 @@
+// Package cache keeps the component artifacts cyfr pull fetches on the
+// local machine, so they are at hand offline and can be inspected and
+// garbage-collected with cyfr cache.
+//
+// The cache lives at ~/.cyfr/cache/artifacts. Each artifact is stored
+// once, named by its sha256 digest, under blobs/; index.json records the
+// references it was pulled as, its size, when it was first pulled and when
+// it was last used.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+const digestPrefix = "sha256:"
+
+// Entry is an artifact in the cache.
+type Entry struct {
+	Digest     string    `json:"digest"`
+	References []string  `json:"references"`
+	Size       int64     `json:"size"`
+	PulledAt   time.Time `json:"pulled_at"`
+	LastUsed   time.Time `json:"last_used"`
+}
+
+// Cache is an artifact cache directory.
+type Cache struct {
+	Dir string
+	// Now returns the current time; time.Now if nil.
+	Now func() time.Time
+}
+
+// DefaultDir returns ~/.cyfr/cache/artifacts.
+func DefaultDir() (string, error) {
+	dir, err := config.DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "artifacts"), nil
+}
+
+// Open returns the cache in the default directory.
+func Open() (*Cache, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Dir: dir}, nil
+}
+
+func (c *Cache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// blobPath returns the file of the artifact with the given digest.
+func (c *Cache) blobPath(digest string) string {
+	return filepath.Join(c.Dir, "blobs", strings.TrimPrefix(digest, digestPrefix)+".wasm")
+}
+
+func (c *Cache) indexPath() string {
+	return filepath.Join(c.Dir, "index.json")
+}
+
+// Put stores data as an artifact pulled as reference, and marks it used.
+func (c *Cache) Put(reference string, data []byte) (Entry, error) {
+	sum := sha256.Sum256(data)
+	digest := digestPrefix + hex.EncodeToString(sum[:])
+	path := c.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Entry{}, fmt.Errorf("create cache: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0644); err != nil {
+			return Entry{}, fmt.Errorf("write cache: %w", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return Entry{}, fmt.Errorf("write cache: %w", err)
+		}
+	}
+
+	index, err := c.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	now := c.now()
+	e, ok := index[digest]
+	if !ok {
+		e = Entry{Digest: digest, Size: int64(len(data)), PulledAt: now}
+	}
+	if reference != "" && !slices.Contains(e.References, reference) {
+		e.References = append(e.References, reference)
+	}
+	e.LastUsed = now
+	index[digest] = e
+	return e, c.save(index)
+}
+
+// Get returns the cached artifact with the given digest, with or without
+// the "sha256:" prefix, and marks it used. ok is false if it isn't cached
+// or its file no longer matches the digest.
+func (c *Cache) Get(digest string) (data []byte, ok bool) {
+	if !strings.HasPrefix(digest, digestPrefix) {
+		digest = digestPrefix + digest
+	}
+	digest = strings.ToLower(digest)
+	data, err := os.ReadFile(c.blobPath(digest))
+	if err != nil {
+		return nil, false
+	}
+	sum := sha256.Sum256(data)
+	if digestPrefix+hex.EncodeToString(sum[:]) != digest {
+		return nil, false
+	}
+	if index, err := c.load(); err == nil {
+		if e, ok := index[digest]; ok {
+			e.LastUsed = c.now()
+			index[digest] = e
+			_ = c.save(index)
+		}
+	}
+	return data, true
+}
+
+// Entries returns the cached artifacts, most recently used first.
+// Artifacts whose files are gone are left out.
+func (c *Cache) Entries() ([]Entry, error) {
+	index, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(index))
+	for _, e := range index {
+		if _, err := os.Stat(c.blobPath(e.Digest)); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].LastUsed.Equal(entries[j].LastUsed) {
+			return entries[i].LastUsed.After(entries[j].LastUsed)
+		}
+		return entries[i].Digest < entries[j].Digest
+	})
+	return entries, nil
+}
+
+// Lookup returns the most recently used artifact pulled as reference.
+func (c *Cache) Lookup(reference string) (Entry, bool) {
+	entries, err := c.Entries()
+	if err != nil {
+		return Entry{}, false
+	}
+	for _, e := range entries {
+		if slices.Contains(e.References, reference) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Path returns the file of a cached artifact.
+func (c *Cache) Path(digest string) string {
+	return c.blobPath(digest)
+}
+
+// PruneCandidates returns the artifacts to prune: those not used
+// for olderThan, if it is positive, and then, while the rest take more
+// than maxSize bytes, if it is positive, the least recently used.
+func (c *Cache) PruneCandidates(olderThan time.Duration, maxSize int64) ([]Entry, error) {
+	entries, err := c.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var prune, keep []Entry
+	cutoff := c.now().Add(-olderThan)
+	for _, e := range entries {
+		if olderThan > 0 && e.LastUsed.Before(cutoff) {
+			prune = append(prune, e)
+		} else {
+			keep = append(keep, e)
+		}
+	}
+	if maxSize > 0 {
+		var total int64
+		for _, e := range keep {
+			total += e.Size
+		}
+		// keep is most recently used first.
+		for len(keep) > 0 && total > maxSize {
+			e := keep[len(keep)-1]
+			keep = keep[:len(keep)-1]
+			total -= e.Size
+			prune = append(prune, e)
+		}
+	}
+	return prune, nil
+}
+
+// Remove deletes the artifacts with the given digests.
+func (c *Cache) Remove(digests []string) error {
+	index, err := c.load()
+	if err != nil {
+		return err
+	}
+	for _, digest := range digests {
+		if err := os.Remove(c.blobPath(digest)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", digest, err)
+		}
+		delete(index, digest)
+	}
+	return c.save(index)
+}
+
+// load reads the index. A missing index is an empty cache.
+func (c *Cache) load() (map[string]Entry, error) {
+	data, err := os.ReadFile(c.indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]Entry{}, nil
+		}
+		return nil, fmt.Errorf("read cache index: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("read cache index: %w", err)
+	}
+	index := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		index[e.Digest] = e
+	}
+	return index, nil
+}
+
+// save writes the index, ordered by digest so that it diffs well.
+func (c *Cache) save(index map[string]Entry) error {
+	entries := make([]Entry, 0, len(index))
+	for _, e := range index {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Digest < entries[j].Digest })
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("write cache index: %w", err)
+	}
+	tmp := c.indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write cache index: %w", err)
+	}
+	if err := os.Rename(tmp, c.indexPath()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write cache index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func testCache(t *testing.T) (*Cache, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	return &Cache{Dir: t.TempDir(), Now: func() time.Time { return now }}, &now
+}
+
+func TestPutGet(t *testing.T) {
+	c, now := testCache(t)
+	e, err := c.Put("reagent:local.hello:0.1.0", []byte("\x00asm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Size != 4 || len(e.References) != 1 || !e.PulledAt.Equal(*now) {
+		t.Errorf("entry = %+v", e)
+	}
+
+	*now = now.Add(time.Hour)
+	if _, err := c.Put("reagent:local.hello:latest", []byte("\x00asm")); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || len(entries[0].References) != 2 || !entries[0].LastUsed.Equal(*now) || entries[0].PulledAt.Equal(*now) {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	data, ok := c.Get(e.Digest[len("sha256:"):])
+	if !ok || string(data) != "\x00asm" {
+		t.Errorf("Get = %q, %v", data, ok)
+	}
+	if _, ok := c.Get("sha256:0000"); ok {
+		t.Error("Get of a digest that isn't cached succeeded")
+	}
+
+	// A blob that no longer matches its digest isn't served.
+	os.WriteFile(c.blobPath(e.Digest), []byte("tampered"), 0644)
+	if _, ok := c.Get(e.Digest); ok {
+		t.Error("Get of a tampered blob succeeded")
+	}
+}
+
+func TestPruneCandidates(t *testing.T) {
+	c, now := testCache(t)
+	start := *now
+	for i, data := range []string{"old", "middle", "newest"} {
+		*now = start.Add(time.Duration(i) * 24 * time.Hour)
+		if _, err := c.Put("c:local.tool:0.1."+string(rune('0'+i)), []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	*now = start.Add(10 * 24 * time.Hour)
+
+	names := func(entries []Entry) string {
+		s := ""
+		for _, e := range entries {
+			s += e.References[0] + " "
+		}
+		return s
+	}
+	prune, _ := c.PruneCandidates(9*24*time.Hour, 0)
+	if got := names(prune); got != "c:local.tool:0.1.0 " {
+		t.Errorf("older than 9d: %s", got)
+	}
+	// Only the newest, at 6 bytes, fits in 8; the others go, least recently used first.
+	prune, _ = c.PruneCandidates(0, 8)
+	if got := names(prune); got != "c:local.tool:0.1.0 c:local.tool:0.1.1 " {
+		t.Errorf("max size 8: %s", got)
+	}
+	prune, _ = c.PruneCandidates(0, 0)
+	if len(prune) != 0 {
+		t.Errorf("no limits: %s", names(prune))
+	}
+
+	prune, _ = c.PruneCandidates(9*24*time.Hour, 0)
+	if err := c.Remove([]string{prune[0].Digest}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := c.Entries(); len(entries) != 2 {
+		t.Errorf("after Remove: %d entries", len(entries))
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cyfr/codex/internal/keyring"
+)
+
+// Credential stores, for Config.CredentialStore.
+const (
+	// CredentialStoreFile keeps credentials in config.json, readable only
+	// by the user. It is the default.
+	CredentialStoreFile = "file"
+	// CredentialStoreKeyring keeps credentials in the OS keyring, leaving
+	// only the rest of each context in config.json.
+	CredentialStoreKeyring = "keyring"
+)
+
+// keyringService is the keyring service credentials are stored under, one
+// secret per context.
+const keyringService = "cyfr"
+
+// Keyring is the keyring used with the "keyring" credential store. Tests
+// replace it with a keyring.Memory.
+var Keyring keyring.Store = keyring.System()
+
+// credentials are the secrets of a context, as stored in the keyring.
+type credentials struct {
+	SessionID string      `json:"session_id,omitempty"`
+	APIKey    string      `json:"api_key,omitempty"`
+	OAuth     *OAuthToken `json:"oauth,omitempty"`
+}
+
+func (c *Context) credentials() credentials {
+	return credentials{SessionID: c.SessionID, APIKey: c.APIKey, OAuth: c.OAuth}
+}
+
+func (c credentials) empty() bool {
+	return c.SessionID == "" && c.APIKey == "" && c.OAuth == nil
+}
+
+// credentialStore returns the configured credential store, validated.
+func (c *Config) credentialStore() (string, error) {
+	switch c.CredentialStore {
+	case "", CredentialStoreFile:
+		return CredentialStoreFile, nil
+	case CredentialStoreKeyring:
+		return CredentialStoreKeyring, nil
+	}
+	return "", fmt.Errorf("invalid credential_store %q: use %q or %q", c.CredentialStore, CredentialStoreKeyring, CredentialStoreFile)
+}
+
+// loadCredentials reads the credentials of contexts kept in the keyring.
+// It reports whether any context's credentials are in the wrong store for
+// the configured one and need migrating.
+func (c *Config) loadCredentials() (bool, error) {
+	store, err := c.credentialStore()
+	if err != nil {
+		return false, err
+	}
+	migrate := false
+	for name, ctx := range c.Contexts {
+		if ctx.Credentials != CredentialStoreKeyring {
+			migrate = migrate || (store == CredentialStoreKeyring && !ctx.credentials().empty())
+			continue
+		}
+		secret, err := Keyring.Get(keyringService, name)
+		if errors.Is(err, keyring.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("read credentials for context %q from keyring: %w", name, err)
+		}
+		var creds credentials
+		if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+			return false, fmt.Errorf("read credentials for context %q from keyring: %w", name, err)
+		}
+		ctx.SessionID, ctx.APIKey, ctx.OAuth = creds.SessionID, creds.APIKey, creds.OAuth
+		ctx.stored, ctx.storedAs = secret, name
+		migrate = migrate || store == CredentialStoreFile
+	}
+	return migrate, nil
+}
+
+// storeCredentials returns the contexts as they are written to
+// config.json: with the keyring store, credentials are saved to the
+// keyring and left out; with the file store, any left in the keyring from
+// before are removed from it. Keyring entries of deleted contexts are
+// removed too.
+func (c *Config) storeCredentials() (map[string]*Context, error) {
+	store, err := c.credentialStore()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*Context, len(c.Contexts))
+	for name, ctx := range c.Contexts {
+		written := *ctx
+		creds := ctx.credentials()
+		switch {
+		case store == CredentialStoreKeyring && !creds.empty():
+			data, err := json.Marshal(creds)
+			if err != nil {
+				return nil, err
+			}
+			if string(data) != ctx.stored || ctx.storedAs != name {
+				if err := Keyring.Set(keyringService, name, string(data)); err != nil {
+					return nil, fmt.Errorf("save credentials for context %q to keyring: %w", name, err)
+				}
+				ctx.stored, ctx.storedAs = string(data), name
+			}
+			ctx.Credentials = CredentialStoreKeyring
+			written.SessionID, written.APIKey, written.OAuth = "", "", nil
+		case ctx.Credentials == CredentialStoreKeyring:
+			if err := deleteCredentials(name); err != nil {
+				return nil, err
+			}
+			ctx.Credentials, ctx.stored, ctx.storedAs = "", "", ""
+		}
+		written.Credentials = ctx.Credentials
+		out[name] = &written
+	}
+	for name := range c.keyringContexts {
+		if _, ok := c.Contexts[name]; !ok {
+			if err := deleteCredentials(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	c.keyringContexts = make(map[string]bool)
+	for name, ctx := range c.Contexts {
+		if ctx.Credentials == CredentialStoreKeyring {
+			c.keyringContexts[name] = true
+		}
+	}
+	return out, nil
+}
+
+// deleteCredentials removes a context's credentials from the keyring.
+func deleteCredentials(name string) error {
+	err := Keyring.Delete(keyringService, name)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("remove credentials for context %q from keyring: %w", name, err)
+	}
+	return nil
+}
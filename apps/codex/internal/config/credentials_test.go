@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/keyring"
+)
+
+// useMemoryKeyring replaces the OS keyring for the duration of a test.
+func useMemoryKeyring(t *testing.T) *keyring.Memory {
+	t.Helper()
+	mem := keyring.NewMemory()
+	prev := Keyring
+	Keyring = mem
+	t.Cleanup(func() { Keyring = prev })
+	return mem
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestKeyringStore_RoundTrip(t *testing.T) {
+	mem := useMemoryKeyring(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := &Config{
+		CurrentContext:  "local",
+		CredentialStore: CredentialStoreKeyring,
+		Contexts: map[string]*Context{
+			"local": {URL: "http://localhost:4000", SessionID: "sess_secret", OAuth: &OAuthToken{AccessToken: "tok_secret"}},
+			"prod":  {URL: "https://prod.example.com"},
+		},
+	}
+	if err := cfg.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	if data := readFile(t, path); strings.Contains(data, "secret") {
+		t.Errorf("credentials written to the config file:\n%s", data)
+	}
+	if mem.Len() != 1 {
+		t.Errorf("expected 1 keyring entry, got %d", mem.Len())
+	}
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	local := loaded.Contexts["local"]
+	if local.SessionID != "sess_secret" || local.OAuth == nil || local.OAuth.AccessToken != "tok_secret" {
+		t.Errorf("credentials not read back from keyring: %+v", local)
+	}
+
+	delete(loaded.Contexts, "local")
+	if err := loaded.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	if mem.Len() != 0 {
+		t.Errorf("keyring entry of deleted context kept")
+	}
+}
+
+func TestKeyringStore_Migration(t *testing.T) {
+	mem := useMemoryKeyring(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	plain := `{"current_context": "local", "credential_store": "keyring",
+		"contexts": {"local": {"url": "http://localhost:4000", "api_key": "cyfr_sk_secret"}}}`
+	if err := os.WriteFile(path, []byte(plain), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if cfg.Current().APIKey != "cyfr_sk_secret" {
+		t.Errorf("API key lost in migration: %+v", cfg.Current())
+	}
+	if data := readFile(t, path); strings.Contains(data, "cyfr_sk_secret") || !strings.Contains(data, `"credentials": "keyring"`) {
+		t.Errorf("credentials not migrated to the keyring:\n%s", data)
+	}
+
+	// Switching back to the file store moves them back.
+	cfg.CredentialStore = CredentialStoreFile
+	if err := cfg.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+	if data := readFile(t, path); !strings.Contains(data, "cyfr_sk_secret") || strings.Contains(data, `"credentials"`) {
+		t.Errorf("credentials not moved back to the file:\n%s", data)
+	}
+	if mem.Len() != 0 {
+		t.Errorf("keyring entry kept after moving to the file store")
+	}
+}
+
+func TestKeyringStore_InvalidSetting(t *testing.T) {
+	useMemoryKeyring(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"credential_store": "vault", "contexts": {}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); err == nil || !strings.Contains(err.Error(), "credential_store") {
+		t.Errorf("expected invalid credential_store error, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the config format this CLI reads and writes. Bump it
+// together with a migration whenever the shape of the file changes.
+const CurrentVersion = 1
+
+// migration upgrades a config, decoded as generic JSON, from version from
+// to from+1.
+type migration struct {
+	from  int
+	apply func(raw map[string]any) error
+}
+
+// migrations are applied in order to files older than CurrentVersion.
+var migrations = []migration{
+	// Files from before versioning have the version 1 shape.
+	{from: 0, apply: func(map[string]any) error { return nil }},
+}
+
+// migrateConfig upgrades config file data to CurrentVersion, returning the
+// upgraded data and the version it was upgraded from. Files written by a
+// newer CLI are refused rather than read with their unknown fields dropped.
+func migrateConfig(data []byte) ([]byte, int, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("parse config: %w", err)
+	}
+	version := 0
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	from := version
+	if version > CurrentVersion {
+		return nil, from, fmt.Errorf("config is version %d, newer than this cyfr supports (%d); upgrade cyfr", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, from, nil
+	}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(raw); err != nil {
+			return nil, from, fmt.Errorf("migrate config from version %d: %w", m.from, err)
+		}
+		version = m.from + 1
+	}
+	if version != CurrentVersion {
+		return nil, from, fmt.Errorf("no migration from config version %d", version)
+	}
+	raw["version"] = CurrentVersion
+	out, err := json.Marshal(raw)
+	if err != nil {
+		return nil, from, fmt.Errorf("migrate config: %w", err)
+	}
+	return out, from, nil
+}
+
+// backupConfig keeps a copy of a config file before it is migrated from
+// version, as path.v<version>.bak.
+func backupConfig(path string, data []byte, version int) error {
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return fmt.Errorf("back up config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectFile is where a project keeps its config, relative to the
+// project's root directory.
+const ProjectFile = ".cyfr/config.json"
+
+// Project is a project's .cyfr/config.json: settings that apply to
+// commands run anywhere inside the project, over those in
+// ~/.cyfr/config.json. Empty fields leave the global settings alone.
+type Project struct {
+	// Context is the context to use instead of the current one.
+	Context string `json:"context,omitempty"`
+	// URL is the server URL, overriding the context's.
+	URL string `json:"url,omitempty"`
+	// DefaultType is the component type for references without one.
+	DefaultType string `json:"default_type,omitempty"`
+	// Output is the default output format: "json" or "text".
+	Output string `json:"output,omitempty"`
+
+	// Path is the file the config was read from.
+	Path string `json:"-"`
+}
+
+// FindProject looks for a project config in dir and each of its parents,
+// returning the nearest one, or nil if there is none. The global config
+// in ~/.cyfr is never mistaken for a project's.
+func FindProject(dir string) (*Project, error) {
+	global, _ := DefaultConfigPath()
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve project dir: %w", err)
+	}
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if path != global {
+			p, err := LoadProject(path)
+			if err == nil || !os.IsNotExist(err) {
+				return p, err
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProject reads a project config from path. A missing file is
+// reported with an error satisfying os.IsNotExist.
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("read project config: %w", err)
+	}
+	var p Project
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse project config %s: %w", path, err)
+	}
+	p.Path = path
+	return &p, nil
+}
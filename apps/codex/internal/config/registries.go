@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cyfr/codex/internal/keyring"
+)
+
+// registryKeyringService is the keyring service registry passwords are
+// stored under, one per registry.
+const registryKeyringService = "cyfr-registry"
+
+// Registry is an OCI registry, such as an organization's private one,
+// that components are pulled from and published to.
+type Registry struct {
+	// URL is the registry's host, optionally followed by a path that
+	// repositories are under: "ghcr.io/acme" keeps acme.sentiment in
+	// ghcr.io/acme/acme/sentiment.
+	URL string `json:"url"`
+
+	// Namespaces are the component namespaces routed to the registry:
+	// references in them are pulled from it, published to it and searched
+	// in it rather than in the server's registry.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Priority orders registries routing the same namespace; the highest
+	// wins.
+	Priority int `json:"priority,omitempty"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Credentials is "keyring" when Password is kept in the OS keyring
+	// rather than in this file. The store is the one configured when the
+	// password was set.
+	Credentials string `json:"credentials,omitempty"`
+}
+
+// Host returns the host of the registry's URL.
+func (r *Registry) Host() string {
+	host, _, _ := strings.Cut(r.URL, "/")
+	return host
+}
+
+// Prefix returns the path of the registry's URL that repositories are
+// under, or "".
+func (r *Registry) Prefix() string {
+	_, prefix, _ := strings.Cut(r.URL, "/")
+	return prefix
+}
+
+// RegistryFor returns the registry namespace is routed to, with its name,
+// or nil if it is in none.
+func (c *Config) RegistryFor(namespace string) (string, *Registry) {
+	names := make([]string, 0, len(c.Registries))
+	for name, r := range c.Registries {
+		if slices.Contains(r.Namespaces, namespace) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := c.Registries[names[i]], c.Registries[names[j]]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return names[i] < names[j]
+	})
+	return names[0], c.Registries[names[0]]
+}
+
+// RegistryForHost returns the first registry, by name, with host, or nil.
+func (c *Config) RegistryForHost(host string) (string, *Registry) {
+	names := make([]string, 0, len(c.Registries))
+	for name, r := range c.Registries {
+		if r.Host() == host {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	return names[0], c.Registries[names[0]]
+}
+
+// SetRegistryCredentials sets the username and password of the registry
+// name, keeping the password in the configured credential store.
+func (c *Config) SetRegistryCredentials(name, username, password string) error {
+	r := c.Registries[name]
+	if r == nil {
+		return fmt.Errorf("registry %q not found", name)
+	}
+	store, err := c.credentialStore()
+	if err != nil {
+		return err
+	}
+	r.Username = username
+	if store == CredentialStoreKeyring {
+		if err := Keyring.Set(registryKeyringService, name, password); err != nil {
+			return fmt.Errorf("save password for registry %q to keyring: %w", name, err)
+		}
+		r.Password, r.Credentials = "", CredentialStoreKeyring
+		return nil
+	}
+	if r.Credentials == CredentialStoreKeyring {
+		if err := deleteRegistryPassword(name); err != nil {
+			return err
+		}
+	}
+	r.Password, r.Credentials = password, ""
+	return nil
+}
+
+// RegistryPassword returns the password of the registry name, from the
+// keyring if it is kept there.
+func (c *Config) RegistryPassword(name string) (string, error) {
+	r := c.Registries[name]
+	if r == nil {
+		return "", fmt.Errorf("registry %q not found", name)
+	}
+	if r.Credentials != CredentialStoreKeyring {
+		return r.Password, nil
+	}
+	password, err := Keyring.Get(registryKeyringService, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read password for registry %q from keyring: %w", name, err)
+	}
+	return password, nil
+}
+
+// RemoveRegistry removes the registry name and any password of it in the
+// keyring.
+func (c *Config) RemoveRegistry(name string) error {
+	r := c.Registries[name]
+	if r == nil {
+		return fmt.Errorf("registry %q not found", name)
+	}
+	if r.Credentials == CredentialStoreKeyring {
+		if err := deleteRegistryPassword(name); err != nil {
+			return err
+		}
+	}
+	delete(c.Registries, name)
+	return nil
+}
+
+// deleteRegistryPassword removes a registry's password from the keyring.
+func deleteRegistryPassword(name string) error {
+	err := Keyring.Delete(registryKeyringService, name)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("remove password for registry %q from keyring: %w", name, err)
+	}
+	return nil
+}
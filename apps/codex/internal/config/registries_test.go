@@ -0,0 +1,54 @@
+package config
+
+import "testing"
+
+func TestRegistryFor(t *testing.T) {
+	cfg := &Config{Registries: map[string]*Registry{
+		"acme":   {URL: "ghcr.io/acme", Namespaces: []string{"acme", "shared"}},
+		"mirror": {URL: "registry.internal:5000", Namespaces: []string{"shared"}, Priority: 10},
+		"other":  {URL: "quay.io", Namespaces: []string{"shared"}, Priority: 10},
+	}}
+	for namespace, want := range map[string]string{"acme": "acme", "shared": "mirror", "cyfr": ""} {
+		if name, _ := cfg.RegistryFor(namespace); name != want {
+			t.Errorf("RegistryFor(%q) = %q, want %q", namespace, name, want)
+		}
+	}
+	if r := cfg.Registries["acme"]; r.Host() != "ghcr.io" || r.Prefix() != "acme" {
+		t.Errorf("host %q, prefix %q", r.Host(), r.Prefix())
+	}
+	if name, _ := cfg.RegistryForHost("registry.internal:5000"); name != "mirror" {
+		t.Errorf("RegistryForHost = %q", name)
+	}
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	mem := useMemoryKeyring(t)
+	cfg := &Config{
+		CredentialStore: CredentialStoreKeyring,
+		Registries:      map[string]*Registry{"acme": {URL: "ghcr.io/acme"}},
+	}
+	if err := cfg.SetRegistryCredentials("acme", "bot", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if r := cfg.Registries["acme"]; r.Password != "" || r.Credentials != CredentialStoreKeyring || mem.Len() != 1 {
+		t.Errorf("with the keyring store: %+v, %d in the keyring", r, mem.Len())
+	}
+	if pw, err := cfg.RegistryPassword("acme"); err != nil || pw != "s3cret" {
+		t.Errorf("RegistryPassword = %q, %v", pw, err)
+	}
+
+	// Switching to the file store moves the password out of the keyring.
+	cfg.CredentialStore = CredentialStoreFile
+	if err := cfg.SetRegistryCredentials("acme", "bot", "n3w"); err != nil {
+		t.Fatal(err)
+	}
+	if r := cfg.Registries["acme"]; r.Password != "n3w" || r.Credentials != "" || mem.Len() != 0 {
+		t.Errorf("with the file store: %+v, %d in the keyring", r, mem.Len())
+	}
+
+	cfg.CredentialStore = CredentialStoreKeyring
+	cfg.SetRegistryCredentials("acme", "bot", "s3cret")
+	if err := cfg.RemoveRegistry("acme"); err != nil || mem.Len() != 0 || cfg.Registries["acme"] != nil {
+		t.Errorf("RemoveRegistry: %v, %d left in the keyring", err, mem.Len())
+	}
+}
@@ -7,29 +7,181 @@ import (
 	"path/filepath"
 )
 
-// Config is the top-level ~/.cyfr/config.json structure.
+// Config is the top-level config.json structure, kept in ~/.cyfr or the
+// XDG config directory (see DefaultConfigDir).
 type Config struct {
+	// Version is the format of the file; see CurrentVersion.
+	Version int `json:"version"`
+
 	CurrentContext string              `json:"current_context"`
 	Contexts       map[string]*Context `json:"contexts"`
+
+	// Aliases map short names to component references, e.g. "claude" to
+	// "catalyst:local.claude:0.1.0", for use wherever a reference is
+	// accepted.
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// StrictRefs rejects the legacy and abbreviated component reference
+	// formats, accepting only type:namespace.name[:version].
+	StrictRefs bool `json:"strict_refs,omitempty"`
+
+	// Settings are CLI preferences such as the default output format,
+	// managed with 'cyfr settings'. Unlike component config they never
+	// leave this machine.
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Registries are the OCI registries components are pulled from,
+	// published to and searched in besides the server's, by name. See
+	// Registry.
+	Registries map[string]*Registry `json:"registries,omitempty"`
+
+	// CredentialStore is where session IDs, API keys and OAuth tokens are
+	// kept: "file" (the default) in this file, or "keyring" in the OS
+	// keyring. Credentials move to the configured store the next time the
+	// config is loaded.
+	CredentialStore string `json:"credential_store,omitempty"`
+
+	// keyringContexts are the contexts with credentials in the keyring as
+	// of the last load or save, so those of deleted contexts are removed.
+	keyringContexts map[string]bool
 }
 
 // Context is a named server connection.
 type Context struct {
 	URL       string `json:"url"`
 	SessionID string `json:"session_id,omitempty"`
+	APIKey    string `json:"api_key,omitempty"`
+
+	// OAuth authenticates with an access/refresh token pair instead of a
+	// session.
+	OAuth *OAuthToken `json:"oauth,omitempty"`
+
+	// HTTP tuning. Durations use Go syntax ("10s", "15m"); empty or zero
+	// values fall back to the client defaults.
+	ConnectTimeout string `json:"connect_timeout,omitempty"`
+	RequestTimeout string `json:"request_timeout,omitempty"`
+	MaxIdleConns   int    `json:"max_idle_conns,omitempty"`
+
+	// TLS settings for servers behind an internal CA or mutual TLS. Paths
+	// are PEM files.
+	CACert             string `json:"ca_cert,omitempty"`
+	ClientCert         string `json:"client_cert,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+
+	// Transport is how requests reach the server: "http" (the default) or
+	// "websocket" for a persistent connection.
+	Transport string `json:"transport,omitempty"`
+
+	// VerifySignatures is whether components are verified before they
+	// are pulled or run: "enforce" refuses those without a valid
+	// signature by one of TrustedKeys, "warn" only warns, and "off" (or
+	// empty) doesn't check. TrustedKeys are paths of PEM public keys.
+	VerifySignatures string   `json:"verify_signatures,omitempty"`
+	TrustedKeys      []string `json:"trusted_keys,omitempty"`
+
+	// Defaults are flag values used with this context when the flag isn't
+	// given, keyed by flag name ({"json": true, "timeout": "60s"}), plus
+	// "default_type", the component type for references without one.
+	Defaults map[string]any `json:"defaults,omitempty"`
+
+	// Server is what the server reported when the session was negotiated
+	// at login, so later commands know its capabilities without asking.
+	Server *ServerState `json:"server,omitempty"`
+
+	// Credentials is "keyring" when SessionID, APIKey and OAuth are kept in
+	// the OS keyring rather than in this file.
+	Credentials string `json:"credentials,omitempty"`
+
+	// stored is the context's keyring secret as last read or written, and
+	// storedAs the context name it was stored under.
+	stored   string
+	storedAs string
 }
 
-// DefaultConfigDir returns ~/.cyfr.
+// OAuthToken is a saved OAuth access token and what's needed to refresh it.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Expiry is RFC 3339; empty if the token doesn't expire.
+	Expiry        string `json:"expiry,omitempty"`
+	TokenEndpoint string `json:"token_endpoint,omitempty"`
+	ClientID      string `json:"client_id,omitempty"`
+}
+
+// ServerState is a saved MCP initialize result.
+type ServerState struct {
+	ProtocolVersion string         `json:"protocol_version"`
+	Capabilities    map[string]any `json:"capabilities,omitempty"`
+	Name            string         `json:"name,omitempty"`
+	Version         string         `json:"version,omitempty"`
+}
+
+// Environment variables locating the config.
+const (
+	// EnvConfigPath overrides the path of the config file.
+	EnvConfigPath = "CYFR_CONFIG_PATH"
+	envXDGConfig  = "XDG_CONFIG_HOME"
+)
+
+// DefaultConfigDir returns the directory holding the config and the CLI's
+// other state: the directory of $CYFR_CONFIG_PATH if set, else
+// $XDG_CONFIG_HOME/cyfr if XDG_CONFIG_HOME is set or ~/.config/cyfr
+// exists, else ~/.cyfr. With XDG_CONFIG_HOME set, an existing ~/.cyfr is
+// moved to $XDG_CONFIG_HOME/cyfr the first time; if it can't be, ~/.cyfr
+// stays in use.
 func DefaultConfigDir() (string, error) {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return filepath.Dir(path), nil
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("get home dir: %w", err)
 	}
-	return filepath.Join(home, ".cyfr"), nil
+	legacy := filepath.Join(home, ".cyfr")
+
+	// The XDG spec says relative paths are to be ignored.
+	xdg := os.Getenv(envXDGConfig)
+	if xdg == "" || !filepath.IsAbs(xdg) {
+		dir := filepath.Join(home, ".config", "cyfr")
+		if isDir(dir) {
+			return dir, nil
+		}
+		return legacy, nil
+	}
+	dir := filepath.Join(xdg, "cyfr")
+	if err := migrateDir(legacy, dir); err != nil {
+		return legacy, nil
+	}
+	return dir, nil
+}
+
+// migrateDir moves the legacy config directory to dir, unless dir already
+// exists or there is nothing to move.
+func migrateDir(legacy, dir string) error {
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		return nil
+	}
+	if !isDir(legacy) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+		return err
+	}
+	return os.Rename(legacy, dir)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
-// DefaultConfigPath returns ~/.cyfr/config.json.
+// DefaultConfigPath returns $CYFR_CONFIG_PATH if set, else config.json in
+// DefaultConfigDir.
 func DefaultConfigPath() (string, error) {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path, nil
+	}
 	dir, err := DefaultConfigDir()
 	if err != nil {
 		return "", err
@@ -46,8 +198,17 @@ func Load() (*Config, error) {
 	return LoadFrom(path)
 }
 
-// LoadFrom reads the config from a specific path.
+// LoadFrom reads the config from a specific path, with credentials kept in
+// the keyring filled in. A file in an older format is upgraded, keeping a
+// backup of the original, and credentials not in the configured store are
+// moved there; either way the file is rewritten.
 func LoadFrom(path string) (*Config, error) {
+	return load(path, false)
+}
+
+// load reads the config from path; locked says whether the caller holds
+// the config lock, for rewriting the file after a credentials migration.
+func load(path string, locked bool) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -56,6 +217,18 @@ func LoadFrom(path string) (*Config, error) {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
 
+	original := data
+	data, from, err := migrateConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	upgraded := from != CurrentVersion
+	if upgraded {
+		if err := backupConfig(path, original, from); err != nil {
+			return nil, err
+		}
+	}
+
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
@@ -63,6 +236,25 @@ func LoadFrom(path string) (*Config, error) {
 	if cfg.Contexts == nil {
 		cfg.Contexts = make(map[string]*Context)
 	}
+	cfg.keyringContexts = make(map[string]bool)
+	for name, ctx := range cfg.Contexts {
+		if ctx.Credentials == CredentialStoreKeyring {
+			cfg.keyringContexts[name] = true
+		}
+	}
+	migrate, err := cfg.loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if migrate || upgraded {
+		save := cfg.SaveTo
+		if locked {
+			save = cfg.saveLocked
+		}
+		if err := save(path); err != nil {
+			return nil, fmt.Errorf("save migrated config: %w", err)
+		}
+	}
 	return &cfg, nil
 }
 
@@ -75,23 +267,71 @@ func (c *Config) Save() error {
 	return c.SaveTo(path)
 }
 
-// SaveTo writes the config to a specific path.
+// SaveTo writes the config to a specific path. The file is replaced
+// atomically while holding the config lock, so concurrent cyfr processes
+// never see or leave a partial file. To change a single setting without
+// dropping other processes' changes, use Update instead.
 func (c *Config) SaveTo(path string) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
-		return fmt.Errorf("create config dir: %w", err)
+	unlock, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.saveLocked(path)
+}
+
+// saveLocked is SaveTo for a caller holding the config lock.
+func (c *Config) saveLocked(path string) error {
+	written := *c
+	written.Version = CurrentVersion
+	contexts, err := c.storeCredentials()
+	if err != nil {
+		return err
 	}
+	written.Contexts = contexts
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(&written, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := writeFileAtomic(path, data, 0600); err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
 	return nil
 }
 
+// Update applies fn to the config as currently on disk and saves the
+// result, holding the config lock throughout, so changes other cyfr
+// processes saved in the meantime are kept.
+func Update(fn func(*Config) error) (*Config, error) {
+	path, err := DefaultConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return UpdateFile(path, fn)
+}
+
+// UpdateFile is Update for the config at path.
+func UpdateFile(path string, fn func(*Config) error) (*Config, error) {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	cfg, err := load(path, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.saveLocked(path); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 // Current returns the active context, or nil if none is set.
 func (c *Config) Current() *Context {
 	if c.CurrentContext == "" {
@@ -109,14 +349,22 @@ func (c *Config) CurrentURL() string {
 	return ctx.URL
 }
 
-// SetSessionID updates the session ID for the active context and saves.
-func (c *Config) SetSessionID(sessionID string) error {
-	ctx := c.Current()
+// SetSessionID updates the session ID for the context name and saves it
+// into the config as re-read from disk, keeping other processes' changes.
+func (c *Config) SetSessionID(name, sessionID string) error {
+	ctx := c.Contexts[name]
 	if ctx == nil {
-		return fmt.Errorf("no active context")
+		return fmt.Errorf("context %q not found", name)
 	}
 	ctx.SessionID = sessionID
-	return c.Save()
+	_, err := Update(func(fresh *Config) error {
+		if fresh.Contexts[name] == nil {
+			return fmt.Errorf("context %q no longer exists", name)
+		}
+		fresh.Contexts[name].SessionID = sessionID
+		return nil
+	})
+	return err
 }
 
 // DefaultForLocal returns a config with the default local context.
@@ -126,6 +374,7 @@ func DefaultForLocal() *Config {
 
 func defaultConfig() *Config {
 	return &Config{
+		Version:        CurrentVersion,
 		CurrentContext: "local",
 		Contexts: map[string]*Context{
 			"local": {
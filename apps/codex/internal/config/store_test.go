@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -161,3 +163,196 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFindProject_NearestParent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	path := filepath.Join(root, ProjectFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"context":"staging","default_type":"catalyst"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := FindProject(sub)
+	if err != nil {
+		t.Fatalf("FindProject failed: %v", err)
+	}
+	if p == nil || p.Context != "staging" || p.DefaultType != "catalyst" || p.Path != path {
+		t.Errorf("unexpected project config: %+v", p)
+	}
+}
+
+func TestFindProject_SkipsGlobalConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := &Config{CurrentContext: "local", Contexts: map[string]*Context{"local": {URL: "http://localhost:4000"}}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(home, "src")
+	if err := os.MkdirAll(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := FindProject(sub)
+	if err != nil {
+		t.Fatalf("FindProject failed: %v", err)
+	}
+	if p != nil {
+		t.Errorf("global config taken for a project config: %+v", p)
+	}
+}
+
+func TestUpdateFile_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := &Config{CurrentContext: "local", Contexts: map[string]*Context{}}
+	if err := cfg.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			_, err := UpdateFile(path, func(c *Config) error {
+				c.Contexts[fmt.Sprintf("ctx%d", i)] = &Context{URL: "http://localhost:4000"}
+				return nil
+			})
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("UpdateFile failed: %v", err)
+		}
+	}
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Contexts) != n {
+		t.Errorf("expected %d contexts after concurrent updates, got %d", n, len(loaded.Contexts))
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temporary file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestSetSessionID_KeepsOtherChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := DefaultForLocal()
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process adds a context after cfg was loaded.
+	other, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Contexts["staging"] = &Context{URL: "https://staging.example.com"}
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.SetSessionID(cfg.CurrentContext, "sess_1"); err != nil {
+		t.Fatalf("SetSessionID failed: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Contexts["staging"] == nil || loaded.Current().SessionID != "sess_1" {
+		t.Errorf("expected both changes kept, got %+v", loaded.Contexts)
+	}
+}
+
+func TestDefaultConfigPath_XDGMigration(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvConfigPath, "")
+	t.Setenv(envXDGConfig, "")
+	cfg := DefaultForLocal()
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".cyfr", "config.json")); err != nil {
+		t.Fatalf("expected config in ~/.cyfr without XDG_CONFIG_HOME: %v", err)
+	}
+
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv(envXDGConfig, xdg)
+	path, err := DefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(xdg, "cyfr", "config.json"); path != want {
+		t.Errorf("config path = %q, want %q", path, want)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("config not migrated: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".cyfr")); !os.IsNotExist(err) {
+		t.Errorf("~/.cyfr left behind after migration")
+	}
+}
+
+func TestDefaultConfigPath_Override(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team", "cyfr.json")
+	t.Setenv(EnvConfigPath, path)
+	got, err := DefaultConfigPath()
+	if err != nil || got != path {
+		t.Errorf("DefaultConfigPath() = %q, %v; want %q", got, err, path)
+	}
+	if dir, _ := DefaultConfigDir(); dir != filepath.Dir(path) {
+		t.Errorf("DefaultConfigDir() = %q, want %q", dir, filepath.Dir(path))
+	}
+}
+
+func TestLoadFrom_MigratesUnversioned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	old := `{"current_context": "local", "contexts": {"local": {"url": "http://localhost:4000"}}}`
+	if err := os.WriteFile(path, []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if cfg.Version != CurrentVersion || cfg.CurrentURL() != "http://localhost:4000" {
+		t.Errorf("unexpected migrated config: %+v", cfg)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf(`"version": %d`, CurrentVersion)) {
+		t.Errorf("migrated config not saved:\n%s", data)
+	}
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != old {
+		t.Errorf("expected the original kept as a backup, got %q, %v", backup, err)
+	}
+}
+
+func TestLoadFrom_RefusesNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := fmt.Sprintf(`{"version": %d, "contexts": {}}`, CurrentVersion+1)
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); err == nil || !strings.Contains(err.Error(), "upgrade cyfr") {
+		t.Errorf("expected a newer-version error, got %v", err)
+	}
+}
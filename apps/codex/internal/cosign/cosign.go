@@ -0,0 +1,129 @@
+// Package cosign signs component artifacts in a form cosign can verify:
+// an ECDSA signature over the artifact, written as the JSON bundle of
+// 'cosign sign-blob --bundle', so that
+//
+//	cosign verify-blob --key cosign.pub --bundle catalyst.wasm.bundle --insecure-ignore-tlog catalyst.wasm
+//
+// checks it. Keys are the PEM files cosign and openssl write; cosign's own
+// encrypted keys need cosign itself to decrypt them.
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// BundleSuffix is appended to an artifact's file name for its bundle,
+// e.g. catalyst.wasm.bundle.
+const BundleSuffix = ".bundle"
+
+// ErrEncryptedKey is returned by ParsePrivateKey for a key encrypted by
+// 'cosign generate-key-pair', which only cosign can decrypt.
+var ErrEncryptedKey = errors.New("the key is encrypted by cosign")
+
+// A Bundle is a signature in the format of 'cosign sign-blob --bundle'.
+// Cert is set for keyless signatures and RekorBundle when the signature
+// was recorded in a transparency log; neither is for signatures made with
+// a key by this package.
+type Bundle struct {
+	Base64Signature string          `json:"base64Signature"`
+	Cert            string          `json:"cert,omitempty"`
+	RekorBundle     json.RawMessage `json:"rekorBundle,omitempty"`
+}
+
+// ParseBundle reads a bundle, checking that it has a signature.
+func ParseBundle(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("not a signature bundle: %w", err)
+	}
+	if b.Base64Signature == "" {
+		return nil, errors.New("not a signature bundle: no base64Signature")
+	}
+	return &b, nil
+}
+
+// ParsePrivateKey reads an ECDSA private key from PEM data, in SEC 1
+// ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY") form. It returns
+// ErrEncryptedKey for cosign's encrypted keys.
+func ParsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM-encoded key found")
+	}
+	switch block.Type {
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY":
+		return nil, ErrEncryptedKey
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if ec, ok := key.(*ecdsa.PrivateKey); ok {
+			return ec, nil
+		}
+		return nil, fmt.Errorf("unsupported key type %T; cosign keys are ECDSA", key)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// ParsePublicKey reads an ECDSA public key from PEM data, such as the
+// cosign.pub of a key pair.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM-encoded key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ec, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T; cosign keys are ECDSA", key)
+	}
+	return ec, nil
+}
+
+// MarshalPublicKey returns the PEM form of key, as in cosign.pub.
+func MarshalPublicKey(key *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Sign signs data with key as cosign sign-blob does: an ASN.1 ECDSA
+// signature over its SHA-256 digest.
+func Sign(key *ecdsa.PrivateKey, data []byte) (*Bundle, error) {
+	digest := sha256.Sum256(data)
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	return &Bundle{Base64Signature: base64.StdEncoding.EncodeToString(sig)}, nil
+}
+
+// Verify checks that b is a signature of data by key.
+func Verify(key *ecdsa.PublicKey, data []byte, b *Bundle) error {
+	sig, err := base64.StdEncoding.DecodeString(b.Base64Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(key, digest[:], sig) {
+		return errors.New("signature doesn't match the artifact")
+	}
+	return nil
+}
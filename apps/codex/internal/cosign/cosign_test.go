@@ -0,0 +1,90 @@
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSignVerify(t *testing.T) {
+	key := newKey(t)
+	data := []byte("\x00asm\x0d\x00\x01\x00")
+	b, err := Sign(key, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(&key.PublicKey, data, b); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := Verify(&key.PublicKey, append(data, 0), b); err == nil {
+		t.Error("Verify succeeded for changed data")
+	}
+	if err := Verify(&newKey(t).PublicKey, data, b); err == nil {
+		t.Error("Verify succeeded with another key")
+	}
+
+	// The bundle has cosign's field names, and nothing it doesn't have.
+	encoded, _ := json.Marshal(b)
+	if !strings.HasPrefix(string(encoded), `{"base64Signature":"`) || strings.Contains(string(encoded), "cert") {
+		t.Errorf("bundle = %s", encoded)
+	}
+	parsed, err := ParseBundle(encoded)
+	if err != nil || parsed.Base64Signature != b.Base64Signature {
+		t.Errorf("ParseBundle = %+v, %v", parsed, err)
+	}
+	if _, err := ParseBundle([]byte(`{"cert": "x"}`)); err == nil {
+		t.Error("ParseBundle accepted a bundle without a signature")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := newKey(t)
+	sec1, _ := x509.MarshalECPrivateKey(key)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+	for _, block := range []*pem.Block{{Type: "EC PRIVATE KEY", Bytes: sec1}, {Type: "PRIVATE KEY", Bytes: pkcs8}} {
+		parsed, err := ParsePrivateKey(pem.EncodeToMemory(block))
+		if err != nil {
+			t.Errorf("%s: %v", block.Type, err)
+		} else if !parsed.Equal(key) {
+			t.Errorf("%s: parsed a different key", block.Type)
+		}
+	}
+
+	encrypted := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("{}")})
+	if _, err := ParsePrivateKey(encrypted); !errors.Is(err, ErrEncryptedKey) {
+		t.Errorf("encrypted key: err = %v, want ErrEncryptedKey", err)
+	}
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("ParsePrivateKey accepted garbage")
+	}
+}
+
+func TestPublicKeyRoundTrip(t *testing.T) {
+	key := newKey(t)
+	data, err := MarshalPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParsePublicKey(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(&key.PublicKey) {
+		t.Error("parsed a different key")
+	}
+}
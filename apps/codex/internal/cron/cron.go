@@ -0,0 +1,200 @@
+// Package cron parses standard five-field cron expressions and computes
+// upcoming run times.
+//
+// The CLI validates expressions and renders next-run times locally; the
+// schedule itself is stored and triggered server-side.
+//
+// Supported syntax per field: "*", single values, ranges ("1-5"), lists
+// ("1,15,30"), and steps ("*/15", "0-30/5"). Month and weekday fields also
+// accept three-letter names ("jan", "mon"). Weekday 7 is treated as Sunday.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record whether the day fields were "*". Per cron
+	// semantics, when both are restricted a day matches if either matches.
+	domStar, dowStar bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Parse parses a five-field cron expression.
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	// Fold Sunday-as-7 into 0.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+	return s, nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		b, err := parsePart(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parsePart(part string, f field) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		lo, hi = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		a, b, _ := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = parseValue(a, f); err != nil {
+			return 0, err
+		}
+		if hi, err = parseValue(b, f); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q in %s field", rangePart, f.name)
+		}
+	default:
+		v, err := parseValue(rangePart, f)
+		if err != nil {
+			return 0, err
+		}
+		lo, hi = v, v
+		if hasStep {
+			hi = f.max
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseValue(s string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+// Next returns the first time strictly after t that matches the schedule,
+// evaluated in t's location. It returns the zero time if no match exists
+// within five years (e.g. "0 0 30 2 *").
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// NextN returns the next n run times after t.
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for i := 0; i < n; i++ {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
@@ -0,0 +1,90 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"* * * *", "5 fields"},
+		{"60 * * * *", "out of range"},
+		{"* 24 * * *", "out of range"},
+		{"*/0 * * * *", "invalid step"},
+		{"5-1 * * * *", "invalid range"},
+		{"* * * foo *", "invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Thursday, 2026-01-01 10:30 UTC
+	base := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 1, 10, 31, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 feb *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"30 10 1 1 *", time.Date(2027, 1, 1, 10, 30, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match.
+		{"0 0 15 * fri", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := s.Next(base); !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext_Impossible(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("expected zero time, got %v", got)
+	}
+}
+
+func TestNextN_RespectsLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	s, _ := Parse("0 9 * * *")
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, loc)
+	times := s.NextN(base, 2)
+	if len(times) != 2 {
+		t.Fatalf("expected 2 times, got %d", len(times))
+	}
+	if times[0].Hour() != 9 || times[0].Day() != 2 || times[0].Location() != loc {
+		t.Errorf("unexpected first run: %v", times[0])
+	}
+}
@@ -0,0 +1,152 @@
+// Package history records cyfr run invocations in a local journal so that
+// previous executions can be listed, inspected, and re-run exactly.
+//
+// The journal lives at ~/.cyfr/history.db and is stored as JSON lines: one
+// Entry per line, appended in order. Entry IDs are assigned sequentially and
+// never reused, so "cyfr history rerun 12" keeps meaning the same run.
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cyfr/codex/internal/config"
+)
+
+// Entry is a single recorded run.
+type Entry struct {
+	ID          int            `json:"id"`
+	Time        time.Time      `json:"time"`
+	URL         string         `json:"url,omitempty"`
+	Reference   map[string]any `json:"reference"`
+	Input       map[string]any `json:"input,omitempty"`
+	InputHash   string         `json:"input_hash"`
+	ExecutionID string         `json:"execution_id,omitempty"`
+	Status      string         `json:"status"`
+	DurationMS  int64          `json:"duration_ms"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// DefaultPath returns ~/.cyfr/history.db.
+func DefaultPath() (string, error) {
+	dir, err := config.DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.db"), nil
+}
+
+// HashInput returns a short, stable hash of an execution input. Map keys are
+// sorted by encoding/json, so equal inputs always hash the same.
+func HashInput(input map[string]any) string {
+	if input == nil {
+		input = map[string]any{}
+	}
+	data, _ := json.Marshal(input)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Load reads every entry from the journal at path, oldest first. A missing
+// journal is not an error and yields no entries.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			// Skip a torn or corrupt line rather than losing the whole journal.
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Append assigns the next ID to e and appends it to the journal at path.
+// The journal is locked from reading the last ID to writing e, so
+// concurrent runs get distinct IDs.
+func Append(path string, e *Entry) error {
+	unlock, err := config.LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := Load(path)
+	if err != nil {
+		return err
+	}
+	e.ID = 1
+	if n := len(entries); n > 0 {
+		e.ID = entries[n-1].ID + 1
+	}
+	if e.InputHash == "" {
+		e.InputHash = HashInput(e.Input)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal history entry: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
+
+// Get returns the entry with the given ID.
+func Get(path string, id int) (*Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("history entry %d not found", id)
+}
+
+// Last returns the most recently recorded entry.
+func Last(path string) (*Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("history is empty")
+	}
+	return &entries[len(entries)-1], nil
+}
@@ -0,0 +1,151 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestLoad_NonexistentReturnsEmpty(t *testing.T) {
+	entries, err := Load(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestAppend_AssignsSequentialIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	for i := 0; i < 3; i++ {
+		e := &Entry{
+			Reference: map[string]any{"registry": "c:local.claude:0.1.0"},
+			Input:     map[string]any{"n": i},
+			Status:    "completed",
+		}
+		if err := Append(path, e); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		if e.ID != i+1 {
+			t.Errorf("expected ID %d, got %d", i+1, e.ID)
+		}
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[2].Input["n"] != float64(2) {
+		t.Errorf("expected input n=2, got %v", entries[2].Input["n"])
+	}
+}
+
+func TestAppend_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	const n = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- Append(path, &Entry{Input: map[string]any{"n": i}, Status: "completed"})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	seen := map[int]bool{}
+	for _, e := range entries {
+		if seen[e.ID] {
+			t.Errorf("ID %d assigned twice", e.ID)
+		}
+		seen[e.ID] = true
+	}
+	if len(entries) != n {
+		t.Errorf("expected %d entries, got %d", n, len(entries))
+	}
+}
+
+func TestGetAndLast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	if _, err := Last(path); err == nil {
+		t.Error("expected error for empty history")
+	}
+
+	_ = Append(path, &Entry{ExecutionID: "exec_1"})
+	_ = Append(path, &Entry{ExecutionID: "exec_2"})
+
+	e, err := Get(path, 1)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if e.ExecutionID != "exec_1" {
+		t.Errorf("expected exec_1, got %q", e.ExecutionID)
+	}
+
+	last, err := Last(path)
+	if err != nil {
+		t.Fatalf("Last failed: %v", err)
+	}
+	if last.ExecutionID != "exec_2" {
+		t.Errorf("expected exec_2, got %q", last.ExecutionID)
+	}
+
+	if _, err := Get(path, 99); err == nil {
+		t.Error("expected error for missing entry")
+	}
+}
+
+func TestLoad_SkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	_ = Append(path, &Entry{ExecutionID: "exec_1"})
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{torn line\n")
+	f.Close()
+
+	_ = Append(path, &Entry{ExecutionID: "exec_2"})
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].ID != 2 {
+		t.Errorf("expected ID 2, got %d", entries[1].ID)
+	}
+}
+
+func TestHashInput_Stable(t *testing.T) {
+	a := HashInput(map[string]any{"a": 1, "b": "x"})
+	b := HashInput(map[string]any{"b": "x", "a": 1})
+	if a != b {
+		t.Errorf("expected equal hashes, got %q and %q", a, b)
+	}
+	if HashInput(nil) != HashInput(map[string]any{}) {
+		t.Error("expected nil and empty input to hash the same")
+	}
+}
@@ -0,0 +1,72 @@
+// Package keyring keeps secrets in the operating system's credential
+// store: the macOS Keychain, the Secret Service (GNOME Keyring, KWallet)
+// on Linux and other Unix systems, or the Windows Credential Manager.
+package keyring
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotFound is returned when the keyring holds no secret for an account.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// ErrUnsupported is returned when the system has no keyring the package
+// can reach, e.g. a Linux host without secret-tool.
+var ErrUnsupported = errors.New("no keyring available on this system")
+
+// Store is a credential store holding one secret per service and account.
+type Store interface {
+	// Get returns the secret for account, or ErrNotFound.
+	Get(service, account string) (string, error)
+	// Set stores the secret for account, replacing any previous one.
+	Set(service, account, secret string) error
+	// Delete removes the secret for account, or returns ErrNotFound.
+	Delete(service, account string) error
+}
+
+// Memory is a Store that keeps secrets in memory, for tests.
+type Memory struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// NewMemory returns an empty in-memory Store.
+func NewMemory() *Memory {
+	return &Memory{secrets: make(map[string]string)}
+}
+
+func (m *Memory) Get(service, account string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.secrets[service+"\x00"+account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (m *Memory) Set(service, account, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[service+"\x00"+account] = secret
+	return nil
+}
+
+func (m *Memory) Delete(service, account string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := service + "\x00" + account
+	if _, ok := m.secrets[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.secrets, key)
+	return nil
+}
+
+// Len returns the number of secrets stored.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.secrets)
+}
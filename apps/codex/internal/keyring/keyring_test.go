@@ -0,0 +1,39 @@
+package keyring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	if _, err := m.Get("cyfr", "local"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on empty store = %v, want ErrNotFound", err)
+	}
+
+	if err := m.Set("cyfr", "local", "sess_1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set("cyfr", "local", "sess_2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set("other", "local", "key"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.Get("cyfr", "local"); err != nil || got != "sess_2" {
+		t.Errorf("Get = %q, %v; want the replaced secret sess_2", got, err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len = %d, want 2", m.Len())
+	}
+
+	if err := m.Delete("cyfr", "local"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete("cyfr", "local"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	if got, err := m.Get("other", "local"); err != nil || got != "key" {
+		t.Errorf("Get other service = %q, %v; want key", got, err)
+	}
+}
@@ -0,0 +1,126 @@
+//go:build !windows
+
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// System returns the operating system's keyring: the Keychain on macOS,
+// reached through security(1), and the Secret Service elsewhere, reached
+// through secret-tool(1).
+func System() Store {
+	if runtime.GOOS == "darwin" {
+		return keychain{}
+	}
+	return secretService{}
+}
+
+// run runs a keyring tool with stdin as its input, returning its trimmed
+// output and exit code. A missing tool is ErrUnsupported.
+func run(stdin string, name string, args ...string) (string, int, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %s not found", ErrUnsupported, name)
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", exitErr.ExitCode(), fmt.Errorf("%s: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), 0, nil
+}
+
+// keychain is the macOS Keychain, holding secrets as generic passwords.
+type keychain struct{}
+
+// errKeychainNotFound is the exit code of security(1) for a missing item.
+const errKeychainNotFound = 44
+
+func (keychain) Get(service, account string) (string, error) {
+	out, code, err := run("", "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	if code == errKeychainNotFound {
+		return "", ErrNotFound
+	}
+	return out, err
+}
+
+// Set sends the add command to security -i on stdin, so the secret never
+// appears in the process list as it would as an argument.
+func (keychain) Set(service, account, secret string) error {
+	command, err := keychainSetCommand(service, account, secret)
+	if err != nil {
+		return err
+	}
+	_, _, err = run(command, "security", "-i")
+	return err
+}
+
+// maxKeychainCommand is the longest line security -i reads.
+const maxKeychainCommand = 4096
+
+// keychainSetCommand returns the security -i command line storing secret.
+func keychainSetCommand(service, account, secret string) (string, error) {
+	if strings.ContainsAny(secret, "\r\n") {
+		return "", errors.New("keychain: secret contains a line break")
+	}
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(service), quote(account), quote(secret))
+	if len(command) > maxKeychainCommand {
+		return "", fmt.Errorf("keychain: secret is too long (%d bytes)", len(secret))
+	}
+	return command, nil
+}
+
+// quote quotes s as one word for security -i, which splits its input like
+// a shell.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+func (keychain) Delete(service, account string) error {
+	_, code, err := run("", "security", "delete-generic-password", "-s", service, "-a", account)
+	if code == errKeychainNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// secretService is the freedesktop.org Secret Service, holding secrets
+// with service and account attributes.
+type secretService struct{}
+
+func (secretService) Get(service, account string) (string, error) {
+	out, code, err := run("", "secret-tool", "lookup", "service", service, "account", account)
+	// secret-tool exits 1 without a message when nothing matches.
+	if code == 1 || (err == nil && out == "") {
+		return "", ErrNotFound
+	}
+	return out, err
+}
+
+func (secretService) Set(service, account, secret string) error {
+	label := service + " (" + account + ")"
+	_, _, err := run(secret, "secret-tool", "store", "--label", label, "service", service, "account", account)
+	return err
+}
+
+func (s secretService) Delete(service, account string) error {
+	if _, err := s.Get(service, account); err != nil {
+		return err
+	}
+	_, _, err := run("", "secret-tool", "clear", "service", service, "account", account)
+	return err
+}
@@ -0,0 +1,65 @@
+//go:build !windows
+
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeychainSetCommand(t *testing.T) {
+	got, err := keychainSetCommand("cyfr", "local", "it's a secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `add-generic-password -U -s 'cyfr' -a 'local' -w 'it'"'"'s a secret'` + "\n"
+	if got != want {
+		t.Errorf("command = %q, want %q", got, want)
+	}
+
+	if _, err := keychainSetCommand("cyfr", "local", "line\nbreak"); err == nil {
+		t.Error("expected an error for a secret with a line break")
+	}
+	if _, err := keychainSetCommand("cyfr", "local", strings.Repeat("x", maxKeychainCommand)); err == nil {
+		t.Error("expected an error for a secret longer than security -i reads")
+	}
+}
+
+// fakeTool installs a script named name on PATH that records its arguments
+// and stdin in dir.
+func fakeTool(t *testing.T, name string) (dir string) {
+	dir = t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" > " + dir + "/args\ncat > " + dir + "/stdin\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestSetKeepsSecretOffCommandLine(t *testing.T) {
+	for _, tt := range []struct {
+		tool  string
+		store Store
+	}{
+		{"security", keychain{}},
+		{"secret-tool", secretService{}},
+	} {
+		t.Run(tt.tool, func(t *testing.T) {
+			dir := fakeTool(t, tt.tool)
+			if err := tt.store.Set("cyfr", "local", "sess_secret"); err != nil {
+				t.Fatal(err)
+			}
+			args, _ := os.ReadFile(filepath.Join(dir, "args"))
+			stdin, _ := os.ReadFile(filepath.Join(dir, "stdin"))
+			if strings.Contains(string(args), "sess_secret") {
+				t.Errorf("secret passed as an argument: %s", args)
+			}
+			if !strings.Contains(string(stdin), "sess_secret") {
+				t.Errorf("secret not sent on stdin: %q", stdin)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential is the Win32 CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// System returns the Windows Credential Manager, holding secrets as
+// generic credentials named "service:account".
+func System() Store {
+	return credentialManager{}
+}
+
+type credentialManager struct{}
+
+func target(service, account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + account)
+}
+
+func (credentialManager) Get(service, account string) (string, error) {
+	name, err := target(service, account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, callErr := procCredReadW.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", credError("read", callErr)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func (credentialManager) Set(service, account, secret string) error {
+	name, err := target(service, account)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		UserName:           user,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	r, _, callErr := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if r == 0 {
+		return credError("write", callErr)
+	}
+	return nil
+}
+
+func (credentialManager) Delete(service, account string) error {
+	name, err := target(service, account)
+	if err != nil {
+		return err
+	}
+	r, _, callErr := procCredDelete.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0)
+	if r == 0 {
+		return credError("delete", callErr)
+	}
+	return nil
+}
+
+func credError(op string, err error) error {
+	if errors.Is(err, errorNotFound) {
+		return ErrNotFound
+	}
+	return fmt.Errorf("credential manager %s: %w", op, err)
+}
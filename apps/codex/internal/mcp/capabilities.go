@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gatedFeatures are the method groups a server must declare in its
+// capabilities before a client may use them. Tools are always assumed.
+var gatedFeatures = map[string]bool{
+	"resources": true,
+	"prompts":   true,
+}
+
+// UnsupportedError reports a feature the server doesn't offer: either its
+// initialize result doesn't declare the capability, or it answered
+// "method not found".
+type UnsupportedError struct {
+	Feature string
+	Server  *InitializeResult // may be nil
+	Err     error             // the server's error, if it rejected the call
+}
+
+func (e *UnsupportedError) Error() string {
+	msg := "server does not support " + e.Feature
+	if e.Server != nil && e.Server.ServerInfo != nil {
+		msg += fmt.Sprintf(" (%s %s)", e.Server.ServerInfo.Name, e.Server.ServerInfo.Version)
+	}
+	return msg
+}
+
+func (e *UnsupportedError) Unwrap() error { return e.Err }
+
+// Supports reports whether the server declared feature ("resources",
+// "prompts", "logging", ...) in its capabilities. Without an initialize
+// result to go on, every feature is assumed to be supported.
+func (c *Client) Supports(feature string) bool {
+	if c.Server == nil || c.Server.Capabilities == nil {
+		return true
+	}
+	_, ok := c.Server.Capabilities[feature]
+	return ok
+}
+
+// methodFeature returns the feature a method belongs to, e.g. "resources"
+// for "resources/read".
+func methodFeature(method string) string {
+	feature, _, _ := strings.Cut(method, "/")
+	return feature
+}
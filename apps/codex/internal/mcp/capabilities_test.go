@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInitialize_RecordsServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Result: map[string]any{
+				"protocolVersion": "2025-06-18",
+				"capabilities":    map[string]any{"tools": map[string]any{}, "resources": map[string]any{"subscribe": false}},
+				"serverInfo":      map[string]any{"name": "CYFR", "version": "0.1.0"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	if err := c.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if c.Server == nil {
+		t.Fatal("Server not recorded")
+	}
+	if c.Server.ProtocolVersion != "2025-06-18" {
+		t.Errorf("ProtocolVersion = %q", c.Server.ProtocolVersion)
+	}
+	if c.Server.ServerInfo == nil || c.Server.ServerInfo.Name != "CYFR" {
+		t.Errorf("ServerInfo = %+v", c.Server.ServerInfo)
+	}
+	if !c.Supports("resources") || c.Supports("prompts") {
+		t.Errorf("Supports: resources=%v prompts=%v, want true false", c.Supports("resources"), c.Supports("prompts"))
+	}
+}
+
+func TestSupports_UnknownServer(t *testing.T) {
+	c := NewClient("http://example.com")
+	if !c.Supports("prompts") {
+		t.Error("without an initialize result every feature should be assumed supported")
+	}
+}
+
+func TestUndeclaredFeatureNotCalled(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Server = &InitializeResult{
+		Capabilities: map[string]any{"tools": map[string]any{}},
+		ServerInfo:   &ServerInfo{Name: "CYFR", Version: "0.1.0"},
+	}
+	_, err := c.ListPrompts()
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected *UnsupportedError, got %v", err)
+	}
+	if unsupported.Feature != "prompts" {
+		t.Errorf("Feature = %q, want prompts", unsupported.Feature)
+	}
+	if got, want := unsupported.Error(), "server does not support prompts (CYFR 0.1.0)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if called {
+		t.Error("undeclared feature should not reach the server")
+	}
+}
+
+func TestMethodNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Error:   &JSONRPCError{Code: CodeMethodNotFound, Message: "Method not found: resources/list"},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	_, err := c.ListResources()
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected *UnsupportedError, got %v", err)
+	}
+	if unsupported.Feature != "resources" {
+		t.Errorf("Feature = %q, want resources", unsupported.Feature)
+	}
+	var rpcErr *JSONRPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeMethodNotFound {
+		t.Errorf("expected the server's JSON-RPC error to be wrapped, got %v", err)
+	}
+}
@@ -2,14 +2,19 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
-const protocolVersion = "2025-11-25"
+// ProtocolVersion is the MCP protocol version this client speaks.
+const ProtocolVersion = "2025-11-25"
 
 // ErrSessionExpired is returned when the server reports that the session has expired.
 var ErrSessionExpired = fmt.Errorf("session expired")
@@ -17,32 +22,106 @@ var ErrSessionExpired = fmt.Errorf("session expired")
 // ErrSessionRequired is returned when the server requires a session but none was provided.
 var ErrSessionRequired = fmt.Errorf("session required")
 
+// ErrStreamingUnsupported is returned by Subscribe when the server does not
+// offer a server-to-client notification stream.
+var ErrStreamingUnsupported = fmt.Errorf("server does not support notification streams")
+
 // Client is a JSON-RPC 2.0 MCP client over HTTP.
 type Client struct {
 	BaseURL   string
 	SessionID string
 
+	// Server is what the server reported at initialize: its protocol
+	// version, capabilities and identity. It's set by Initialize, or by the
+	// caller from a saved result; nil means unknown, and every feature is
+	// then assumed to be supported.
+	Server *InitializeResult
+
+	// APIKey, if set, authenticates every request as a Bearer token. API keys
+	// are stateless, so no session is needed.
+	APIKey string
+
+	// OAuth, if set and APIKey isn't, authenticates every request with its
+	// access token, which is refreshed when it expires or the server
+	// rejects it. OnTokenRefresh, if set, receives each new token so it can
+	// be saved.
+	OAuth          *OAuthToken
+	OnTokenRefresh func(OAuthToken)
+
+	// OnNotification, if set, receives server notifications (progress, log
+	// messages) that arrive on a streamed response while a request is in flight.
+	OnNotification func(Notification)
+
+	// OnProgress, if set, asks the server for progress updates on tool calls
+	// and receives them. Progress notifications then go here instead of to
+	// OnNotification.
+	OnProgress func(Progress)
+
+	// InputSchema, if set, returns the inputSchema of a tool (or nil if
+	// unknown). CallTool validates arguments against it before sending and
+	// returns an *ArgumentError instead of making the call.
+	InputSchema func(ctx context.Context, tool string) any
+
+	// OnCall, if set, receives timing and size statistics for every request
+	// once it's done, whether it succeeded or not.
+	OnCall func(CallStats)
+
+	// Retry controls retrying of transient failures. See RetryPolicy.
+	Retry RetryPolicy
+
+	// RateLimit paces requests once the server signals a rate limit. It's
+	// shared by all requests of the client; nil disables pacing.
+	RateLimit *RateLimiter
+
+	// OnRateLimit, if set, is called whenever a request is held back by the
+	// server's rate limit, with how long it will wait.
+	OnRateLimit func(wait time.Duration)
+
+	// Debug, if set, receives one line per request and response: method,
+	// tool name, redacted arguments, HTTP status, and latency.
+	Debug io.Writer
+
+	// Timeout bounds each request, including retries. Zero means no limit.
+	// See HTTPOptions.RequestTimeout.
+	Timeout time.Duration
+
 	httpClient *http.Client
+	tokenMu    sync.Mutex // guards OAuth once requests are in flight
+	httpOpts   HTTPOptions
+	ws         *wsTransport // nil unless UseWebSocket was called
 	nextID     atomic.Int64
+	sleep      func(time.Duration) // test hook; nil waits on a timer
 }
 
 // NewClient creates a new MCP client for the given base URL.
 func NewClient(baseURL string) *Client {
-	return &Client{
-		BaseURL:    baseURL,
-		httpClient: &http.Client{},
+	c := &Client{
+		BaseURL:   baseURL,
+		Retry:     DefaultRetryPolicy(),
+		RateLimit: &RateLimiter{},
 	}
+	c.SetHTTPOptions(DefaultHTTPOptions())
+	return c
 }
 
-// Initialize sends the MCP initialize request and captures the session ID.
+// Initialize sends the MCP initialize request, captures the session ID, and
+// records the negotiated protocol version and capabilities in Server.
 func (c *Client) Initialize() error {
+	return c.InitializeCtx(context.Background())
+}
+
+// InitializeCtx is Initialize with a context that cancels the request.
+func (c *Client) InitializeCtx(ctx context.Context) error {
 	c.SessionID = "" // Clear stale session ID; initialize creates a new one
+	if c.ws != nil {
+		c.ws.reset() // and a new connection, handshaking without it
+	}
 	req := JSONRPCRequest{
 		JSONRPC: "2.0",
 		ID:      int(c.nextID.Add(1)),
 		Method:  "initialize",
 		Params: map[string]any{
-			"protocolVersion": protocolVersion,
+			"protocolVersion": ProtocolVersion,
 			"capabilities":    map[string]any{},
 			"clientInfo": map[string]any{
 				"name":    "cyfr",
@@ -51,7 +130,7 @@ func (c *Client) Initialize() error {
 		},
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequest(ctx, req)
 	if err != nil {
 		return fmt.Errorf("initialize: %w", err)
 	}
@@ -60,28 +139,53 @@ func (c *Client) Initialize() error {
 		return fmt.Errorf("initialize error: %s", resp.Error.Message)
 	}
 
+	resultBytes, err := json.Marshal(resp.Result)
+	if err != nil {
+		return fmt.Errorf("marshal result: %w", err)
+	}
+	var result InitializeResult
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		return fmt.Errorf("initialize: unmarshal result: %w", err)
+	}
+	c.Server = &result
+
 	return nil
 }
 
 // CallTool invokes an MCP tool and returns the raw result.
 func (c *Client) CallTool(name string, args map[string]any) (map[string]any, error) {
+	return c.CallToolCtx(context.Background(), name, args)
+}
+
+// CallToolCtx is CallTool with a context. Cancelling ctx aborts the request,
+// including any retry wait, and returns an error wrapping ctx.Err().
+func (c *Client) CallToolCtx(ctx context.Context, name string, args map[string]any) (map[string]any, error) {
+	if c.InputSchema != nil {
+		if schema := c.InputSchema(ctx, name); schema != nil {
+			if err := ValidateArguments(name, schema, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	req := JSONRPCRequest{
 		JSONRPC: "2.0",
 		ID:      int(c.nextID.Add(1)),
 		Method:  "tools/call",
-		Params: ToolCallParams{
-			Name:      name,
-			Arguments: args,
-		},
 	}
+	params := ToolCallParams{Name: name, Arguments: args}
+	if c.OnProgress != nil {
+		params.Meta = map[string]any{"progressToken": req.ID}
+	}
+	req.Params = params
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequest(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("call tool %s: %w", name, err)
 	}
 
 	if resp.Error != nil {
-		return nil, fmt.Errorf("%s", resp.Error.Message)
+		return nil, resp.Error
 	}
 
 	// Parse the result - it contains content blocks
@@ -101,109 +205,294 @@ func (c *Client) CallTool(name string, args map[string]any) (map[string]any, err
 	}
 
 	if toolResult.IsError {
-		if len(toolResult.Content) > 0 {
-			return nil, fmt.Errorf("%s", toolResult.Content[0].Text)
+		if text := toolResult.text(); text != "" {
+			return nil, fmt.Errorf("%s", text)
 		}
 		return nil, fmt.Errorf("tool returned error")
 	}
 
-	// Parse the text content as JSON
-	if len(toolResult.Content) > 0 && toolResult.Content[0].Type == "text" {
-		var result map[string]any
-		if err := json.Unmarshal([]byte(toolResult.Content[0].Text), &result); err != nil {
-			return map[string]any{"text": toolResult.Content[0].Text}, nil
-		}
-		return result, nil
-	}
-
-	return map[string]any{}, nil
+	return toolResult.resultMap(), nil
 }
 
 // ListTools returns the list of available MCP tools.
 func (c *Client) ListTools() ([]Tool, error) {
+	return c.ListToolsCtx(context.Background())
+}
+
+// ListToolsCtx is ListTools with a context that cancels the request. It
+// follows pagination cursors until every tool has been listed.
+func (c *Client) ListToolsCtx(ctx context.Context) ([]Tool, error) {
+	tools, err := listAll[Tool](ctx, c, "tools/list", "tools")
+	if err != nil {
+		return nil, fmt.Errorf("list tools: %w", err)
+	}
+	return tools, nil
+}
+
+// call sends a JSON-RPC request and decodes its result into out.
+func (c *Client) call(ctx context.Context, method string, params any, out any) error {
+	feature := methodFeature(method)
+	if gatedFeatures[feature] && !c.Supports(feature) {
+		return &UnsupportedError{Feature: feature, Server: c.Server}
+	}
+
 	req := JSONRPCRequest{
 		JSONRPC: "2.0",
 		ID:      int(c.nextID.Add(1)),
-		Method:  "tools/list",
+		Method:  method,
+		Params:  params,
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequest(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("list tools: %w", err)
+		return err
 	}
-
 	if resp.Error != nil {
-		return nil, fmt.Errorf("list tools error: %s", resp.Error.Message)
+		if resp.Error.Code == CodeMethodNotFound {
+			return &UnsupportedError{Feature: feature, Server: c.Server, Err: resp.Error}
+		}
+		return resp.Error
 	}
 
 	resultBytes, err := json.Marshal(resp.Result)
 	if err != nil {
-		return nil, fmt.Errorf("marshal result: %w", err)
+		return fmt.Errorf("marshal result: %w", err)
+	}
+	if err := json.Unmarshal(resultBytes, out); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
 	}
+	return nil
+}
 
-	var toolsResult ToolsListResult
-	if err := json.Unmarshal(resultBytes, &toolsResult); err != nil {
-		return nil, fmt.Errorf("unmarshal tools: %w", err)
+// post sends a single JSON-RPC POST to the MCP endpoint.
+func (c *Client) post(ctx context.Context, body []byte) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/mcp", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	return toolsResult.Tools, nil
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	c.setSessionHeaders(httpReq)
+
+	return c.httpClient.Do(httpReq)
 }
 
-func (c *Client) doRequest(req JSONRPCRequest) (*JSONRPCResponse, error) {
+// setSessionHeaders adds the protocol version and credentials to a request.
+func (c *Client) setSessionHeaders(httpReq *http.Request) {
+	httpReq.Header.Set("MCP-Protocol-Version", ProtocolVersion)
+	if c.SessionID != "" {
+		httpReq.Header.Set("MCP-Session-Id", c.SessionID)
+	}
+	if c.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
+	} else if token := c.currentToken(); token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// currentToken returns the OAuth access token as it is, without refreshing.
+func (c *Client) currentToken() string {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.OAuth == nil {
+		return ""
+	}
+	return c.OAuth.AccessToken
+}
+
+func (c *Client) doRequest(ctx context.Context, req JSONRPCRequest) (*JSONRPCResponse, error) {
+	var stats CallStats
+	start := time.Now()
+
+	// An OAuth token is refreshed ahead of its expiry, and once more if the
+	// server rejects it anyway (e.g. it was revoked, or clocks disagree).
+	var resp *JSONRPCResponse
+	token, err := c.accessToken(ctx)
+	if err == nil {
+		resp, err = c.send(ctx, req, &stats)
+		if isUnauthorized(err) && token != "" && c.APIKey == "" && c.refreshAfterRejection(ctx, token) {
+			resp, err = c.send(ctx, req, &stats)
+		}
+	}
+	c.recordCall(req, &stats, start, err)
+	return resp, err
+}
+
+// send makes a request, retrying transient failures, and fills in stats
+// as it goes.
+func (c *Client) send(ctx context.Context, req JSONRPCRequest, stats *CallStats) (*JSONRPCResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
+	stats.RequestBytes = int64(len(body))
 
-	httpReq, err := http.NewRequest("POST", c.BaseURL+"/mcp", bytes.NewReader(body))
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	c.debugf("--> #%d %s", req.ID, describeRequest(req))
+	start := time.Now()
+
+	waited, err := c.pace(ctx, req.ID)
+	stats.RateLimitWait += waited
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("MCP-Protocol-Version", protocolVersion)
-	if c.SessionID != "" {
-		httpReq.Header.Set("MCP-Session-Id", c.SessionID)
+	if c.ws != nil {
+		resp, err := c.ws.roundTrip(ctx, req, body, stats)
+		c.debugResponse(req, 0, start, err)
+		return resp, err
 	}
 
-	httpResp, err := c.httpClient.Do(httpReq)
+	var httpResp *http.Response
+	for attempt := 1; ; attempt++ {
+		httpResp, err = c.post(ctx, body)
+		if httpResp != nil && c.RateLimit != nil {
+			c.RateLimit.observe(httpResp)
+		}
+		delay, retry := c.Retry.retryDelay(attempt, httpResp, err, req.Method != "tools/call")
+		if !retry {
+			break
+		}
+		if httpResp != nil && httpResp.StatusCode == http.StatusTooManyRequests && c.OnRateLimit != nil {
+			c.OnRateLimit(delay)
+		}
+		if httpResp != nil {
+			c.debugf("    #%d HTTP %d, retrying in %s", req.ID, httpResp.StatusCode, delay.Round(time.Millisecond))
+		} else {
+			c.debugf("    #%d %v, retrying in %s", req.ID, err, delay.Round(time.Millisecond))
+		}
+		if httpResp != nil {
+			io.Copy(io.Discard, httpResp.Body)
+			httpResp.Body.Close()
+		}
+		stats.Retries++
+		if httpResp != nil && httpResp.StatusCode == http.StatusTooManyRequests {
+			stats.RateLimitWait += delay
+		}
+		if c.wait(ctx, delay) != nil {
+			return nil, c.contextErr(ctx)
+		}
+	}
 	if err != nil {
+		c.debugResponse(req, 0, start, err)
+		if ctx.Err() != nil {
+			return nil, c.contextErr(ctx)
+		}
 		return nil, fmt.Errorf("http request: %w", err)
 	}
+	httpResp.Body = &countingBody{ReadCloser: httpResp.Body, n: &stats.ResponseBytes}
 	defer httpResp.Body.Close()
+	stats.Status = httpResp.StatusCode
+	c.debugResponse(req, httpResp.StatusCode, start, nil)
 
 	// Capture session ID from response headers
 	if sid := httpResp.Header.Get("Mcp-Session-Id"); sid != "" {
 		c.SessionID = sid
 	}
 
+	// Streamable HTTP: the server may answer with an SSE stream carrying
+	// notifications followed by the response.
+	if httpResp.StatusCode == http.StatusOK && strings.HasPrefix(httpResp.Header.Get("Content-Type"), "text/event-stream") {
+		resp, err := c.readStreamedResponse(httpResp.Body, req.ID)
+		if err != nil && ctx.Err() != nil {
+			return nil, c.contextErr(ctx)
+		}
+		return resp, err
+	}
+
 	respBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, c.contextErr(ctx)
+		}
 		return nil, fmt.Errorf("read response: %w", err)
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
-		// Detect session expiry: server returns 404 with error code -33302
-		if httpResp.StatusCode == http.StatusNotFound {
-			var errResp JSONRPCResponse
-			if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != nil && errResp.Error.Code == -33302 {
-				return nil, ErrSessionExpired
-			}
+		if err := sessionError(httpResp.StatusCode, respBody); err != nil {
+			return nil, err
 		}
-		// Detect session required: server returns 400 with error code -33301
-		if httpResp.StatusCode == http.StatusBadRequest {
-			var errResp JSONRPCResponse
-			if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != nil && errResp.Error.Code == -33301 {
-				return nil, ErrSessionRequired
-			}
-		}
-		return nil, fmt.Errorf("HTTP %d: %s", httpResp.StatusCode, string(respBody))
+		return nil, &statusError{Status: httpResp.StatusCode, Body: string(respBody)}
 	}
 
 	var resp JSONRPCResponse
 	if err := json.Unmarshal(respBody, &resp); err != nil {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
+	if resp.Error != nil {
+		c.debugf("    #%d JSON-RPC error %d: %s", req.ID, resp.Error.Code, resp.Error.Message)
+	}
 
 	return &resp, nil
 }
+
+// pace holds a request back for its slot under the server's rate limit and
+// returns how long it waited. Retries of the request aren't paced again;
+// their own delay covers it.
+func (c *Client) pace(ctx context.Context, id int) (time.Duration, error) {
+	if c.RateLimit == nil {
+		return 0, nil
+	}
+	d := c.RateLimit.reserve()
+	if d <= 0 {
+		return 0, nil
+	}
+	c.debugf("    #%d rate limited, waiting %s", id, d.Round(time.Millisecond))
+	if c.OnRateLimit != nil {
+		c.OnRateLimit(d)
+	}
+	if c.wait(ctx, d) != nil {
+		return d, c.contextErr(ctx)
+	}
+	return d, nil
+}
+
+// statusError is an HTTP error response from the server.
+type statusError struct {
+	Status int
+	Body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.Status, e.Body)
+}
+
+// sessionError recognizes the server's answers for a missing or expired
+// session, returning nil for any other response.
+func sessionError(status int, body []byte) error {
+	var errResp JSONRPCResponse
+	if json.Unmarshal(body, &errResp) != nil || errResp.Error == nil {
+		return nil
+	}
+	switch {
+	// Detect session expiry: server returns 404 with error code -33302
+	case status == http.StatusNotFound && errResp.Error.Code == -33302:
+		return ErrSessionExpired
+	// Detect session required: server returns 400 with error code -33301
+	case status == http.StatusBadRequest && errResp.Error.Code == -33301:
+		return ErrSessionRequired
+	}
+	return nil
+}
+
+// wait pauses for d between retries, returning early if ctx is done.
+func (c *Client) wait(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		c.sleep(d)
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
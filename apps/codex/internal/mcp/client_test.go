@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -256,3 +258,337 @@ func TestRequestHeaders(t *testing.T) {
 	c.SessionID = "my-session"
 	_, _ = c.ListTools()
 }
+
+func TestCallTool_StreamedResponseWithNotifications(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); !strings.Contains(accept, "text/event-stream") {
+			t.Errorf("expected Accept to include text/event-stream, got %q", accept)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req JSONRPCRequest
+		json.Unmarshal(body, &req)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, ": keep-alive\n\n")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1,"total":2}}`+"\n\n")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"working"}}`+"\n\n")
+		resp, _ := json.Marshal(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result: map[string]any{
+				"content": []map[string]any{{"type": "text", "text": `{"status":"ok"}`}},
+			},
+		})
+		io.WriteString(w, "event: message\ndata: "+string(resp)+"\n\n")
+	}))
+	defer srv.Close()
+
+	var got []Notification
+	c := NewClient(srv.URL)
+	c.OnNotification = func(n Notification) { got = append(got, n) }
+
+	result, err := c.CallTool("test-tool", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result["status"] != "ok" {
+		t.Errorf("expected status 'ok', got %v", result["status"])
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(got))
+	}
+	if got[0].Method != "notifications/progress" || got[0].Params["progress"] != float64(1) {
+		t.Errorf("unexpected first notification: %+v", got[0])
+	}
+	if got[1].Method != "notifications/message" {
+		t.Errorf("unexpected second notification: %+v", got[1])
+	}
+}
+
+func TestCallTool_StreamEndsWithoutResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`+"\n\n")
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	_, err := c.CallTool("test-tool", nil)
+	if err == nil || !strings.Contains(err.Error(), "without a response") {
+		t.Errorf("expected 'without a response' error, got %v", err)
+	}
+}
+
+func TestSubscribe_DeliversNotifications(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/message","params":{"data":"one"}}`+"\n\n")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/message","params":{"data":"two"}}`+"\n\n")
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	sub, err := c.Subscribe()
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer sub.Close()
+
+	var data []any
+	for n := range sub.C {
+		data = append(data, n.Params["data"])
+	}
+	if len(data) != 2 || data[0] != "one" || data[1] != "two" {
+		t.Errorf("unexpected notifications: %v", data)
+	}
+	if sub.Err() != nil {
+		t.Errorf("unexpected stream error: %v", sub.Err())
+	}
+}
+
+func TestSubscribe_Unsupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	_, err := c.Subscribe()
+	if !errors.Is(err, ErrStreamingUnsupported) {
+		t.Errorf("expected ErrStreamingUnsupported, got %v", err)
+	}
+}
+
+func TestCallToolCtx_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	c := NewClient(srv.URL)
+	_, err := c.CallToolCtx(ctx, "test-tool", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCallToolCtx_CancelledDuringRetryWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	c := NewClient(srv.URL)
+	start := time.Now()
+	_, err := c.CallToolCtx(ctx, "test-tool", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("retry wait was not interrupted")
+	}
+}
+
+func TestCallTool_RequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClient(srv.URL)
+	c.SetHTTPOptions(HTTPOptions{ConnectTimeout: time.Second, RequestTimeout: 50 * time.Millisecond})
+
+	_, err := c.CallTool("test-tool", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("expected timeout in message, got %q", err)
+	}
+}
+
+func TestCallTool_CallerTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClient(srv.URL)
+	c.SetHTTPOptions(HTTPOptions{ConnectTimeout: time.Second, RequestTimeout: time.Minute})
+
+	// The caller's shorter timeout expires, not the client's.
+	ctx, cancel := WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.CallToolCtx(ctx, "test-tool", nil)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("caller timeout: got %v", err)
+	}
+
+	// A deadline not set with WithTimeout isn't reported as the client's.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.CallToolCtx(ctx, "test-tool", nil)
+	if !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "1m0s") {
+		t.Errorf("plain deadline: got %v", err)
+	}
+}
+
+func TestRequestHeaders_APIKey(t *testing.T) {
+	var auth, session string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		session = r.Header.Get("MCP-Session-Id")
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: 1, Result: map[string]any{}})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.APIKey = "cyfr_sk_test"
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if auth != "Bearer cyfr_sk_test" {
+		t.Errorf("expected Bearer authorization, got %q", auth)
+	}
+	if session != "" {
+		t.Errorf("expected no session header, got %q", session)
+	}
+}
+
+func TestResources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result any
+		switch req.Method {
+		case "resources/list":
+			result = map[string]any{"resources": []map[string]any{
+				{"uri": "opus://executions/{id}", "name": "Execution", "mimeType": "application/json"},
+			}}
+		case "resources/read":
+			params := req.Params.(map[string]any)
+			result = map[string]any{"contents": []map[string]any{
+				{"uri": params["uri"], "mimeType": "application/json", "text": `{"status":"completed"}`},
+			}}
+		}
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	resources, err := c.ListResources()
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+	if len(resources) != 1 || resources[0].URI != "opus://executions/{id}" {
+		t.Errorf("unexpected resources: %+v", resources)
+	}
+
+	contents, err := c.ReadResource("opus://executions/exec_1")
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if len(contents) != 1 || contents[0].URI != "opus://executions/exec_1" || contents[0].Text != `{"status":"completed"}` {
+		t.Errorf("unexpected contents: %+v", contents)
+	}
+}
+
+func TestPrompts(t *testing.T) {
+	var gotParams map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result any
+		switch req.Method {
+		case "prompts/list":
+			result = map[string]any{"prompts": []map[string]any{
+				{"name": "build-catalyst", "arguments": []map[string]any{{"name": "language", "required": true}}},
+			}}
+		case "prompts/get":
+			gotParams = req.Params.(map[string]any)
+			result = map[string]any{"messages": []map[string]any{
+				{"role": "user", "content": map[string]any{"type": "text", "text": "Build a go catalyst"}},
+			}}
+		}
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	prompts, err := c.ListPrompts()
+	if err != nil {
+		t.Fatalf("ListPrompts failed: %v", err)
+	}
+	if len(prompts) != 1 || !prompts[0].Arguments[0].Required {
+		t.Errorf("unexpected prompts: %+v", prompts)
+	}
+
+	result, err := c.GetPrompt("build-catalyst", map[string]string{"language": "go"})
+	if err != nil {
+		t.Fatalf("GetPrompt failed: %v", err)
+	}
+	if gotParams["name"] != "build-catalyst" || gotParams["arguments"].(map[string]any)["language"] != "go" {
+		t.Errorf("unexpected params: %v", gotParams)
+	}
+	if len(result.Messages) != 1 || result.Messages[0].Content.Text != "Build a go catalyst" {
+		t.Errorf("unexpected messages: %+v", result.Messages)
+	}
+}
+
+func TestCallTool_ProgressToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int `json:"id"`
+			Params struct {
+				Meta map[string]any `json:"_meta"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		token, _ := json.Marshal(req.Params.Meta["progressToken"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":`+string(token)+`,"progress":5,"total":10,"message":"half"}}`+"\n\n")
+		io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"x"}}`+"\n\n")
+		resp, _ := json.Marshal(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: map[string]any{}})
+		io.WriteString(w, "data: "+string(resp)+"\n\n")
+	}))
+	defer srv.Close()
+
+	var progress []Progress
+	var other []Notification
+	c := NewClient(srv.URL)
+	c.OnProgress = func(p Progress) { progress = append(progress, p) }
+	c.OnNotification = func(n Notification) { other = append(other, n) }
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("expected 1 progress update, got %d", len(progress))
+	}
+	p := progress[0]
+	if p.Token != float64(1) || p.Progress != 5 || p.Total != 10 || p.Message != "half" || p.Params["message"] != "half" {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+	if len(other) != 1 || other[0].Method != "notifications/message" {
+		t.Errorf("expected the log message on OnNotification, got %+v", other)
+	}
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ExtraContentKey is the key under which CallTool returns the content blocks
+// of a tool result that aren't its JSON result: images, audio, resource
+// links, embedded resources, and any text after the first block. The value
+// is a []ContentBlock; use ExtraContent to read it.
+const ExtraContentKey = "_content"
+
+// ExtraContent returns the extra content blocks of a CallTool result and
+// removes them from it.
+func ExtraContent(result map[string]any) []ContentBlock {
+	blocks, _ := result[ExtraContentKey].([]ContentBlock)
+	delete(result, ExtraContentKey)
+	return blocks
+}
+
+// text joins the text blocks of a result.
+func (r *ToolCallResult) text() string {
+	var parts []string
+	for _, b := range r.Content {
+		if b.Type == "text" && b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// resultMap converts a tool result to the map CallTool returns. A leading
+// text block holding a JSON object is the result; other leading text is
+// returned under "text". Every other block is kept under ExtraContentKey.
+func (r *ToolCallResult) resultMap() map[string]any {
+	blocks := r.Content
+	result := map[string]any{}
+	if len(blocks) > 0 && blocks[0].Type == "text" {
+		var parsed map[string]any
+		if err := json.Unmarshal([]byte(blocks[0].Text), &parsed); err == nil && parsed != nil {
+			result = parsed
+		} else {
+			result["text"] = blocks[0].Text
+		}
+		blocks = blocks[1:]
+	}
+	if len(blocks) > 0 {
+		result[ExtraContentKey] = blocks
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToolCallResult_ResultMap(t *testing.T) {
+	image := ContentBlock{Type: "image", Data: "iVBORw0K", MimeType: "image/png"}
+	link := ContentBlock{Type: "resource_link", URI: "arca://artifacts/out.csv", Name: "out.csv"}
+
+	tests := []struct {
+		name    string
+		content []ContentBlock
+		want    map[string]any
+	}{
+		{
+			name:    "json text",
+			content: []ContentBlock{{Type: "text", Text: `{"status":"ok"}`}},
+			want:    map[string]any{"status": "ok"},
+		},
+		{
+			name:    "plain text",
+			content: []ContentBlock{{Type: "text", Text: "hello"}},
+			want:    map[string]any{"text": "hello"},
+		},
+		{
+			name:    "empty",
+			content: nil,
+			want:    map[string]any{},
+		},
+		{
+			name:    "json with image and link",
+			content: []ContentBlock{{Type: "text", Text: `{"status":"ok"}`}, image, link},
+			want:    map[string]any{"status": "ok", ExtraContentKey: []ContentBlock{image, link}},
+		},
+		{
+			name:    "image only",
+			content: []ContentBlock{image},
+			want:    map[string]any{ExtraContentKey: []ContentBlock{image}},
+		},
+		{
+			name:    "second text block kept",
+			content: []ContentBlock{{Type: "text", Text: "first"}, {Type: "text", Text: "second"}},
+			want:    map[string]any{"text": "first", ExtraContentKey: []ContentBlock{{Type: "text", Text: "second"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ToolCallResult{Content: tt.content}
+			if got := r.resultMap(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultMap() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraContent(t *testing.T) {
+	image := ContentBlock{Type: "image", Data: "AAAA", MimeType: "image/png"}
+	result := map[string]any{"status": "ok", ExtraContentKey: []ContentBlock{image}}
+
+	blocks := ExtraContent(result)
+	if len(blocks) != 1 || blocks[0] != image {
+		t.Errorf("ExtraContent = %+v", blocks)
+	}
+	if _, ok := result[ExtraContentKey]; ok {
+		t.Error("ExtraContent should remove the blocks from the result")
+	}
+	if ExtraContent(map[string]any{}) != nil {
+		t.Error("expected nil for a result without extra content")
+	}
+}
+
+func TestToolCallResult_ErrorText(t *testing.T) {
+	r := &ToolCallResult{IsError: true, Content: []ContentBlock{
+		{Type: "text", Text: "denied"},
+		{Type: "image", Data: "AAAA"},
+		{Type: "text", Text: "by policy"},
+	}}
+	if got := r.text(); got != "denied\nby policy" {
+		t.Errorf("text() = %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sensitiveKeys are argument names whose values are never logged. The list
+// mirrors the server's request log sanitizer.
+var sensitiveKeys = map[string]bool{
+	"password": true, "secret": true, "token": true, "api_key": true,
+	"apikey": true, "access_token": true, "refresh_token": true,
+	"private_key": true, "secret_key": true, "auth": true, "bearer": true,
+	"credential": true, "credentials": true, "passwd": true, "pwd": true,
+	"api-key": true, "x-api-key": true, "authorization": true,
+}
+
+const redacted = "[REDACTED]"
+
+// redactArgs returns a copy of tool arguments with sensitive values
+// replaced, recursing into nested maps and lists. For the secret tool the
+// "value" argument is the secret itself and is redacted too.
+func redactArgs(tool string, args map[string]any) map[string]any {
+	out := redactMap(args)
+	if tool == "secret" {
+		if _, ok := out["value"]; ok {
+			out["value"] = redacted
+		}
+	}
+	return out
+}
+
+func redactMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if sensitiveKeys[strings.ToLower(k)] {
+			out[k] = redacted
+			continue
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return redactMap(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = redactValue(e)
+		}
+		return out
+	}
+	return v
+}
+
+// describeRequest returns the log label for a request: the method, plus the
+// tool name and redacted arguments for tool calls.
+func describeRequest(req JSONRPCRequest) string {
+	params, ok := req.Params.(ToolCallParams)
+	if !ok {
+		return req.Method
+	}
+	args, _ := json.Marshal(redactArgs(params.Name, params.Arguments))
+	return fmt.Sprintf("%s %s %s", req.Method, params.Name, args)
+}
+
+// debugf writes a wire-level log line when Debug is set.
+func (c *Client) debugf(format string, args ...any) {
+	if c.Debug == nil {
+		return
+	}
+	fmt.Fprintf(c.Debug, "[debug] "+format+"\n", args...)
+}
+
+// debugResponse logs the outcome of a request.
+func (c *Client) debugResponse(req JSONRPCRequest, status int, start time.Time, err error) {
+	if c.Debug == nil {
+		return
+	}
+	label := req.Method
+	if params, ok := req.Params.(ToolCallParams); ok {
+		label += " " + params.Name
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case err != nil:
+		c.debugf("<-- #%d %s error after %s: %v", req.ID, label, elapsed, err)
+	case status == 0:
+		c.debugf("<-- #%d %s (%s)", req.ID, label, elapsed)
+	default:
+		c.debugf("<-- #%d %s HTTP %d (%s)", req.ID, label, status, elapsed)
+	}
+}
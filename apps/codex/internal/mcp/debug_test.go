@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	args := map[string]any{
+		"action": "set",
+		"name":   "OPENAI_KEY",
+		"value":  "sk-live-123",
+		"nested": map[string]any{"Password": "hunter2", "user": "bob"},
+		"list":   []any{map[string]any{"token": "abc"}},
+	}
+
+	got := redactArgs("secret", args)
+	if got["value"] != redacted {
+		t.Errorf("expected secret value redacted, got %v", got["value"])
+	}
+	if got["name"] != "OPENAI_KEY" {
+		t.Errorf("expected name kept, got %v", got["name"])
+	}
+	nested := got["nested"].(map[string]any)
+	if nested["Password"] != redacted || nested["user"] != "bob" {
+		t.Errorf("unexpected nested redaction: %v", nested)
+	}
+	if got["list"].([]any)[0].(map[string]any)["token"] != redacted {
+		t.Errorf("expected token in list redacted, got %v", got["list"])
+	}
+	if args["value"] != "sk-live-123" {
+		t.Error("redactArgs modified its input")
+	}
+
+	if got := redactArgs("config", map[string]any{"value": "plain"}); got["value"] != "plain" {
+		t.Errorf("expected config value kept, got %v", got["value"])
+	}
+}
+
+func TestDebugLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: 1, Result: map[string]any{}})
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := NewClient(srv.URL)
+	c.Debug = &buf
+	if _, err := c.CallTool("secret", map[string]any{"action": "set", "name": "K", "value": "s3cret"}); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	log := buf.String()
+	for _, want := range []string{"--> #1 tools/call secret", `"action":"set"`, "<-- #1 tools/call secret HTTP 200"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("expected log to contain %q, got:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "s3cret") {
+		t.Errorf("secret value leaked into debug log:\n%s", log)
+	}
+}
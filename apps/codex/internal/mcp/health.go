@@ -0,0 +1,27 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Health checks the server's /api/health endpoint, which answers without
+// a session, over the client's HTTP transport.
+func (c *Client) Health(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/api/health", nil)
+	if err != nil {
+		return fmt.Errorf("create health request: %w", err)
+	}
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+	defer httpResp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(httpResp.Body, 1<<16))
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check: HTTP %d", httpResp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+// Package mockserver implements a fake CYFR MCP endpoint that answers tool
+// calls with canned responses from fixtures. It backs `cyfr --mock` and lets
+// the cmd layer be tested end to end without a running server.
+package mockserver
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+//go:embed fixtures/default.json
+var defaultFixtures []byte
+
+// SessionID is the session ID the mock server hands out at initialize.
+const SessionID = "mock-session"
+
+// Fixtures are the canned answers of a mock server.
+type Fixtures struct {
+	Server mcp.ServerInfo `json:"server"`
+
+	// Tools are returned by tools/list. If empty, one tool with an open
+	// schema is listed for each tool named in Responses and Errors.
+	Tools []mcp.Tool `json:"tools,omitempty"`
+
+	// Responses are tool results keyed by "tool.action" or, to answer every
+	// action, by "tool". Each is returned as the JSON text of the result.
+	Responses map[string]any `json:"responses"`
+
+	// Errors are tool error messages, keyed like Responses and checked first.
+	Errors map[string]string `json:"errors,omitempty"`
+
+	// Resources are listed by resources/list and served by resources/read.
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// Resource is a fixture resource and its contents.
+type Resource struct {
+	URI      string `json:"uri"`
+	Name     string `json:"name"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// DefaultFixtures returns the built-in fixtures, which cover the common CLI
+// commands with plausible responses.
+func DefaultFixtures() *Fixtures {
+	f, err := parseFixtures(defaultFixtures)
+	if err != nil {
+		panic("mockserver: invalid built-in fixtures: " + err.Error())
+	}
+	return f
+}
+
+// LoadFixtures reads fixtures from a JSON file.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseFixtures(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse fixtures %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func parseFixtures(data []byte) (*Fixtures, error) {
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Call is a tool call received by the server.
+type Call struct {
+	Tool string
+	Args map[string]any
+}
+
+// Server is a mock MCP endpoint. It serves POST /mcp and is safe for
+// concurrent use.
+type Server struct {
+	fixtures *Fixtures
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// New returns a server answering from f.
+func New(f *Fixtures) *Server {
+	return &Server{fixtures: f}
+}
+
+// Calls returns the tool calls received so far, in order.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// Transport returns an http.RoundTripper that serves requests in process,
+// without opening a listener. Use it with mcp.Client.WrapTransport.
+func (s *Server) Transport() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/api/health" {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		// No server-to-client stream; clients treat 405 as "unsupported".
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ID     int             `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, mcp.JSONRPCResponse{
+			JSONRPC: "2.0",
+			Error:   &mcp.JSONRPCError{Code: -32700, Message: "Parse error"},
+		})
+		return
+	}
+
+	result, rpcErr := s.dispatch(w, req.Method, req.Params)
+	writeJSON(w, http.StatusOK, mcp.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+		Error:   rpcErr,
+	})
+}
+
+func (s *Server) dispatch(w http.ResponseWriter, method string, params json.RawMessage) (any, *mcp.JSONRPCError) {
+	switch method {
+	case "initialize":
+		w.Header().Set("Mcp-Session-Id", SessionID)
+		return mcp.InitializeResult{
+			ProtocolVersion: mcp.ProtocolVersion,
+			Capabilities: map[string]any{
+				"tools":     map[string]any{"listChanged": false},
+				"resources": map[string]any{"subscribe": false, "listChanged": false},
+			},
+			ServerInfo: &s.fixtures.Server,
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return mcp.ToolsListResult{Tools: s.tools()}, nil
+	case "tools/call":
+		var p mcp.ToolCallParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &mcp.JSONRPCError{Code: -32602, Message: "Invalid params"}
+		}
+		return s.callTool(p.Name, p.Arguments), nil
+	case "resources/list":
+		list := make([]mcp.Resource, len(s.fixtures.Resources))
+		for i, r := range s.fixtures.Resources {
+			list[i] = mcp.Resource{URI: r.URI, Name: r.Name, MimeType: r.MimeType}
+		}
+		return mcp.ResourcesListResult{Resources: list}, nil
+	case "resources/read":
+		var p struct {
+			URI string `json:"uri"`
+		}
+		_ = json.Unmarshal(params, &p)
+		for _, r := range s.fixtures.Resources {
+			if r.URI == p.URI {
+				return mcp.ReadResourceResult{Contents: []mcp.ResourceContents{
+					{URI: r.URI, MimeType: r.MimeType, Text: r.Text},
+				}}, nil
+			}
+		}
+		return nil, &mcp.JSONRPCError{Code: -32002, Message: "Resource not found: " + p.URI}
+	}
+	return nil, &mcp.JSONRPCError{Code: mcp.CodeMethodNotFound, Message: "Method not found: " + method}
+}
+
+func (s *Server) callTool(name string, args map[string]any) mcp.ToolCallResult {
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Tool: name, Args: args})
+	s.mu.Unlock()
+
+	keys := []string{name}
+	if action, ok := args["action"].(string); ok {
+		keys = []string{name + "." + action, name}
+	}
+	for _, k := range keys {
+		if msg, ok := s.fixtures.Errors[k]; ok {
+			return errorResult(msg)
+		}
+	}
+	for _, k := range keys {
+		if resp, ok := s.fixtures.Responses[k]; ok {
+			text, err := json.Marshal(resp)
+			if err != nil {
+				return errorResult(fmt.Sprintf("mock: encode fixture %s: %v", k, err))
+			}
+			return mcp.ToolCallResult{Content: []mcp.ContentBlock{{Type: "text", Text: string(text)}}}
+		}
+	}
+	return errorResult(fmt.Sprintf("mock: no fixture for %s", keys[0]))
+}
+
+// tools lists the fixture tools, deriving them from the response keys when
+// none are given.
+func (s *Server) tools() []mcp.Tool {
+	if len(s.fixtures.Tools) > 0 {
+		return s.fixtures.Tools
+	}
+	names := map[string]bool{}
+	for k := range s.fixtures.Responses {
+		name, _, _ := strings.Cut(k, ".")
+		names[name] = true
+	}
+	for k := range s.fixtures.Errors {
+		name, _, _ := strings.Cut(k, ".")
+		names[name] = true
+	}
+	tools := make([]mcp.Tool, 0, len(names))
+	for name := range names {
+		tools = append(tools, mcp.Tool{
+			Name:        name,
+			Description: "Mock " + name + " tool",
+			InputSchema: map[string]any{"type": "object"},
+		})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func errorResult(msg string) mcp.ToolCallResult {
+	return mcp.ToolCallResult{IsError: true, Content: []mcp.ContentBlock{{Type: "text", Text: msg}}}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,107 @@
+package mockserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+func TestServer_CallTool(t *testing.T) {
+	srv := New(&Fixtures{
+		Responses: map[string]any{
+			"widget.get": map[string]any{"name": "gear"},
+			"widget":     map[string]any{"fallback": true},
+		},
+		Errors: map[string]string{"widget.delete": "permission denied"},
+	})
+	c := mcp.NewClient("http://mock.invalid")
+	c.WrapTransport(func(http.RoundTripper) http.RoundTripper { return srv.Transport() })
+
+	tests := []struct {
+		action  string
+		want    map[string]any
+		wantErr string
+	}{
+		{action: "get", want: map[string]any{"name": "gear"}},
+		{action: "list", want: map[string]any{"fallback": true}},
+		{action: "delete", wantErr: "permission denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			result, err := c.CallTool("widget", map[string]any{"action": tt.action})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.want {
+				if result[k] != v {
+					t.Errorf("%s = %v, want %v", k, result[k], v)
+				}
+			}
+		})
+	}
+
+	if _, err := c.CallTool("gadget", nil); err == nil || !strings.Contains(err.Error(), "no fixture for gadget") {
+		t.Errorf("expected missing fixture error, got %v", err)
+	}
+	if calls := srv.Calls(); len(calls) != 4 || calls[0].Tool != "widget" || calls[0].Args["action"] != "get" {
+		t.Errorf("Calls() = %+v", calls)
+	}
+}
+
+func TestServer_InitializeAndListTools(t *testing.T) {
+	ts := httptest.NewServer(New(DefaultFixtures()))
+	defer ts.Close()
+
+	c := mcp.NewClient(ts.URL)
+	if err := c.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if c.SessionID != SessionID {
+		t.Errorf("SessionID = %q, want %q", c.SessionID, SessionID)
+	}
+	if !c.Supports("resources") || c.Supports("prompts") {
+		t.Error("mock should declare resources but not prompts")
+	}
+
+	tools, err := c.ListTools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, tool := range tools {
+		names[tool.Name] = true
+	}
+	for _, want := range []string{"system", "execution", "key"} {
+		if !names[want] {
+			t.Errorf("tools/list missing %q", want)
+		}
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	os.WriteFile(path, []byte(`{"responses":{"system.status":{"status":"degraded"}}}`), 0600)
+	f, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.Responses["system.status"]; !ok {
+		t.Errorf("fixtures not loaded: %+v", f)
+	}
+
+	os.WriteFile(path, []byte(`{`), 0600)
+	if _, err := LoadFixtures(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenExpiryLeeway is how long before its expiry a token is refreshed, so
+// it doesn't expire while a request is on its way.
+const tokenExpiryLeeway = 30 * time.Second
+
+// ErrTokenExpired is returned when the OAuth access token has expired and
+// can't be refreshed, either because there is no refresh token or because
+// the token endpoint rejected it.
+var ErrTokenExpired = fmt.Errorf("access token expired")
+
+// OAuthToken is an OAuth 2.0 access token and what's needed to refresh it.
+type OAuthToken struct {
+	AccessToken  string
+	RefreshToken string
+	// Expiry is when AccessToken expires; zero means it doesn't, or that
+	// the server didn't say.
+	Expiry time.Time
+	// TokenEndpoint is where refresh_token grants are sent.
+	TokenEndpoint string
+	// ClientID identifies the CLI to the token endpoint, if it needs to.
+	ClientID string
+}
+
+// canRefresh reports whether the token can be refreshed.
+func (t *OAuthToken) canRefresh() bool {
+	return t.RefreshToken != "" && t.TokenEndpoint != ""
+}
+
+// expiresSoon reports whether the token is expired or about to be.
+func (t *OAuthToken) expiresSoon(now time.Time) bool {
+	return !t.Expiry.IsZero() && now.Add(tokenExpiryLeeway).After(t.Expiry)
+}
+
+// TokenResponse is the JSON answer of an OAuth token endpoint (RFC 6749
+// section 5.1).
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Error        string `json:"error,omitempty"`
+	ErrorDesc    string `json:"error_description,omitempty"`
+}
+
+// Token converts a token response received at now into an OAuthToken,
+// keeping the previous refresh token if the server didn't rotate it.
+func (r TokenResponse) Token(prev OAuthToken, now time.Time) OAuthToken {
+	t := prev
+	t.AccessToken = r.AccessToken
+	if r.RefreshToken != "" {
+		t.RefreshToken = r.RefreshToken
+	}
+	t.Expiry = time.Time{}
+	if r.ExpiresIn > 0 {
+		t.Expiry = now.Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return t
+}
+
+// accessToken returns the current OAuth access token, refreshing it first
+// if it has expired or is about to. It returns "" when the client doesn't
+// use OAuth.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.OAuth == nil {
+		return "", nil
+	}
+	if c.OAuth.expiresSoon(time.Now()) {
+		if err := c.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.OAuth.AccessToken, nil
+}
+
+// RefreshToken exchanges the refresh token for a new access token at the
+// token endpoint and reports the new token to OnTokenRefresh. Requests
+// refresh automatically; call this only to force it.
+func (c *Client) RefreshToken(ctx context.Context) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.OAuth == nil {
+		return fmt.Errorf("client has no OAuth token")
+	}
+	return c.refreshLocked(ctx)
+}
+
+// refreshAfterRejection refreshes a token the server rejected with HTTP 401,
+// unless another request already replaced it in the meantime. It reports
+// whether the request is worth retrying.
+func (c *Client) refreshAfterRejection(ctx context.Context, rejected string) bool {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.OAuth == nil || !c.OAuth.canRefresh() {
+		return false
+	}
+	if c.OAuth.AccessToken != rejected {
+		return true
+	}
+	return c.refreshLocked(ctx) == nil
+}
+
+func (c *Client) refreshLocked(ctx context.Context) error {
+	if !c.OAuth.canRefresh() {
+		return ErrTokenExpired
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {c.OAuth.RefreshToken},
+	}
+	if c.OAuth.ClientID != "" {
+		form.Set("client_id", c.OAuth.ClientID)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.OAuth.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create refresh request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	c.debugf("--> POST %s (token refresh)", c.OAuth.TokenEndpoint)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+
+	var tr TokenResponse
+	if json.Unmarshal(body, &tr) != nil && httpResp.StatusCode == http.StatusOK {
+		return fmt.Errorf("refresh token: invalid response from token endpoint")
+	}
+	if httpResp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		// invalid_grant and friends mean the refresh token itself is no
+		// good; the user has to log in again.
+		if tr.Error != "" {
+			return fmt.Errorf("%w: token endpoint refused refresh: %s", ErrTokenExpired, tr.Error)
+		}
+		return fmt.Errorf("refresh token: HTTP %d from token endpoint", httpResp.StatusCode)
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return fmt.Errorf("refresh token: unsupported token type %q", tr.TokenType)
+	}
+
+	t := tr.Token(*c.OAuth, time.Now())
+	c.OAuth = &t
+	c.debugf("    token refreshed, expires %s", expiryLabel(t.Expiry))
+	if c.OnTokenRefresh != nil {
+		c.OnTokenRefresh(t)
+	}
+	return nil
+}
+
+func expiryLabel(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// isUnauthorized reports whether err is an HTTP 401 from the server.
+func isUnauthorized(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.Status == http.StatusUnauthorized
+}
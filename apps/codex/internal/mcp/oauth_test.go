@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// oauthServers starts an MCP server that accepts only the bearer token
+// *valid, and a token endpoint that issues "fresh" for refresh token "r1".
+func oauthServers(t *testing.T, valid *string) (mcpURL, tokenURL string, refreshes *int) {
+	t.Helper()
+	n := 0
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "r1" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{"error": "invalid_grant"})
+			return
+		}
+		*valid = "fresh"
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "fresh", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	t.Cleanup(tokenSrv.Close)
+
+	mcpSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+*valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: 1, Result: map[string]any{
+			"content": []map[string]any{{"type": "text", "text": `{"status":"ok"}`}},
+		}})
+	}))
+	t.Cleanup(mcpSrv.Close)
+	return mcpSrv.URL, tokenSrv.URL, &n
+}
+
+func TestOAuth_RefreshesExpiredToken(t *testing.T) {
+	valid := "stale-but-valid"
+	mcpURL, tokenURL, refreshes := oauthServers(t, &valid)
+
+	c := NewClient(mcpURL)
+	c.OAuth = &OAuthToken{AccessToken: "old", RefreshToken: "r1", TokenEndpoint: tokenURL, Expiry: time.Now().Add(-time.Minute)}
+	var saved []OAuthToken
+	c.OnTokenRefresh = func(t OAuthToken) { saved = append(saved, t) }
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if *refreshes != 1 || len(saved) != 1 {
+		t.Fatalf("expected one refresh, got %d (%d saved)", *refreshes, len(saved))
+	}
+	if saved[0].AccessToken != "fresh" || saved[0].RefreshToken != "r1" || saved[0].Expiry.Before(time.Now()) {
+		t.Errorf("unexpected refreshed token: %+v", saved[0])
+	}
+}
+
+func TestOAuth_RefreshesRejectedToken(t *testing.T) {
+	valid := "other"
+	mcpURL, tokenURL, refreshes := oauthServers(t, &valid)
+
+	c := NewClient(mcpURL)
+	c.OAuth = &OAuthToken{AccessToken: "revoked", RefreshToken: "r1", TokenEndpoint: tokenURL}
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if *refreshes != 1 || c.OAuth.AccessToken != "fresh" {
+		t.Errorf("expected one refresh to 'fresh', got %d, token %q", *refreshes, c.OAuth.AccessToken)
+	}
+}
+
+func TestOAuth_ExpiredWithoutRefreshToken(t *testing.T) {
+	valid := "x"
+	mcpURL, tokenURL, refreshes := oauthServers(t, &valid)
+
+	c := NewClient(mcpURL)
+	c.OAuth = &OAuthToken{AccessToken: "old", TokenEndpoint: tokenURL, Expiry: time.Now().Add(-time.Minute)}
+
+	if _, err := c.CallTool("test-tool", nil); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+	if *refreshes != 0 {
+		t.Errorf("expected no refresh attempt, got %d", *refreshes)
+	}
+}
+
+func TestOAuth_RefreshRejected(t *testing.T) {
+	valid := "x"
+	mcpURL, tokenURL, _ := oauthServers(t, &valid)
+
+	c := NewClient(mcpURL)
+	c.OAuth = &OAuthToken{AccessToken: "old", RefreshToken: "revoked", TokenEndpoint: tokenURL, Expiry: time.Now().Add(-time.Minute)}
+
+	if _, err := c.CallTool("test-tool", nil); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestOAuth_APIKeyTakesPrecedence(t *testing.T) {
+	valid := "cyfr_sk_test"
+	mcpURL, tokenURL, refreshes := oauthServers(t, &valid)
+
+	c := NewClient(mcpURL)
+	c.APIKey = "cyfr_sk_test"
+	c.OAuth = &OAuthToken{AccessToken: "old", RefreshToken: "r1", TokenEndpoint: tokenURL}
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if *refreshes != 0 {
+		t.Errorf("expected no refresh, got %d", *refreshes)
+	}
+}
+
+func TestTokenResponse_Token(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	prev := OAuthToken{AccessToken: "a", RefreshToken: "r", TokenEndpoint: "https://idp/token", ClientID: "cyfr"}
+
+	got := TokenResponse{AccessToken: "b", ExpiresIn: 60}.Token(prev, now)
+	if got.AccessToken != "b" || got.RefreshToken != "r" || got.TokenEndpoint != prev.TokenEndpoint || !got.Expiry.Equal(now.Add(time.Minute)) {
+		t.Errorf("unexpected token: %+v", got)
+	}
+	if got := (TokenResponse{AccessToken: "c", RefreshToken: "r2"}).Token(prev, now); got.RefreshToken != "r2" || !got.Expiry.IsZero() {
+		t.Errorf("unexpected rotated token: %+v", got)
+	}
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// maxListPages bounds how many pages a list call follows, guarding against
+// a server that never stops returning a cursor.
+const maxListPages = 1000
+
+// listAll calls a cursor-paginated MCP list method (tools/list,
+// resources/list, prompts/list) and follows nextCursor until the last page,
+// returning the items found under itemsKey on every page.
+func listAll[T any](ctx context.Context, c *Client, method, itemsKey string) ([]T, error) {
+	var all []T
+	cursor := ""
+	for page := 0; page < maxListPages; page++ {
+		var params any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+
+		var raw map[string]json.RawMessage
+		if err := c.call(ctx, method, params, &raw); err != nil {
+			return nil, err
+		}
+		if data, ok := raw[itemsKey]; ok {
+			var items []T
+			if err := json.Unmarshal(data, &items); err != nil {
+				return nil, fmt.Errorf("unmarshal %s: %w", itemsKey, err)
+			}
+			all = append(all, items...)
+		}
+
+		var next string
+		if data, ok := raw["nextCursor"]; ok {
+			_ = json.Unmarshal(data, &next)
+		}
+		if next == "" || next == cursor {
+			return all, nil
+		}
+		cursor = next
+	}
+	return nil, fmt.Errorf("more than %d pages", maxListPages)
+}
+
+// CallToolPagedCtx calls a tool's list-style action and follows the
+// "next_cursor" it returns, sending it back as "cursor", until the last page
+// or until limit items have been collected (limit <= 0 means all). Each
+// request asks for at most the remaining number of items via "limit".
+//
+// The returned result is the last page with itemsKey replaced by every item
+// collected and "count" updated. A non-empty "next_cursor" in it means more
+// items were available than limit allowed.
+func (c *Client) CallToolPagedCtx(ctx context.Context, name string, args map[string]any, itemsKey string, limit int) (map[string]any, error) {
+	var items []any
+	var result map[string]any
+	cursor := ""
+	for page := 0; page < maxListPages; page++ {
+		pageArgs := make(map[string]any, len(args)+2)
+		for k, v := range args {
+			pageArgs[k] = v
+		}
+		if cursor != "" {
+			pageArgs["cursor"] = cursor
+		}
+		if limit > 0 {
+			pageArgs["limit"] = limit - len(items)
+		}
+
+		var err error
+		result, err = c.CallToolCtx(ctx, name, pageArgs)
+		if err != nil {
+			return nil, err
+		}
+		pageItems, _ := result[itemsKey].([]any)
+		items = append(items, pageItems...)
+
+		next, _ := result["next_cursor"].(string)
+		if next == "" || next == cursor || len(pageItems) == 0 || (limit > 0 && len(items) >= limit) {
+			break
+		}
+		cursor = next
+	}
+
+	if limit > 0 && len(items) > limit {
+		// The server ignored the limit; trim, and make sure the caller
+		// still learns there was more.
+		items = items[:limit]
+		if next, _ := result["next_cursor"].(string); next == "" {
+			result["next_cursor"] = "truncated"
+		}
+	}
+	if items == nil {
+		items = []any{}
+	}
+	result[itemsKey] = items
+	result["count"] = len(items)
+	return result, nil
+}
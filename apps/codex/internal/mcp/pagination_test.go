@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pagedToolServer serves a tools/call list action over n items, pageSize at
+// a time, honoring "cursor" and "limit" arguments.
+func pagedToolServer(t *testing.T, n, pageSize int) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			ID     int            `json:"id"`
+			Params ToolCallParams `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		start := 0
+		if c, ok := req.Params.Arguments["cursor"].(string); ok {
+			fmt.Sscanf(c, "%d", &start)
+		}
+		size := pageSize
+		if l, ok := req.Params.Arguments["limit"].(float64); ok && int(l) < size {
+			size = int(l)
+		}
+		end := start + size
+		if end > n {
+			end = n
+		}
+		items := []any{}
+		for i := start; i < end; i++ {
+			items = append(items, i)
+		}
+		page := map[string]any{"keys": items}
+		if end < n {
+			page["next_cursor"] = fmt.Sprint(end)
+		}
+		text, _ := json.Marshal(page)
+		json.NewEncoder(w).Encode(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"content": []map[string]any{{"type": "text", "text": string(text)}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestCallToolPaged(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantCount int
+		wantCalls int
+		wantMore  bool
+	}{
+		{"all", 0, 25, 3, false},
+		{"limit within first page", 5, 5, 1, true},
+		{"limit across pages", 15, 15, 2, true},
+		{"limit above total", 100, 25, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := pagedToolServer(t, 25, 10)
+			c := NewClient(srv.URL)
+
+			result, err := c.CallToolPagedCtx(context.Background(), "key", map[string]any{"action": "list"}, "keys", tt.limit)
+			if err != nil {
+				t.Fatalf("CallToolPagedCtx failed: %v", err)
+			}
+			items := result["keys"].([]any)
+			if len(items) != tt.wantCount || result["count"] != tt.wantCount {
+				t.Errorf("got %d items (count %v), want %d", len(items), result["count"], tt.wantCount)
+			}
+			if items[len(items)-1] != float64(tt.wantCount-1) {
+				t.Errorf("items out of order: %v", items)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", *calls, tt.wantCalls)
+			}
+			if more := result["next_cursor"] != nil && result["next_cursor"] != ""; more != tt.wantMore {
+				t.Errorf("next_cursor = %v, want more=%v", result["next_cursor"], tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestListTools_FollowsCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int            `json:"id"`
+			Params map[string]any `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		result := map[string]any{"tools": []map[string]any{{"name": "a"}}, "nextCursor": "p2"}
+		if req.Params["cursor"] == "p2" {
+			result = map[string]any{"tools": []map[string]any{{"name": "b"}}}
+		}
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+	}))
+	defer srv.Close()
+
+	tools, err := NewClient(srv.URL).ListTools()
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "a" || tools[1].Name != "b" {
+		t.Errorf("unexpected tools: %+v", tools)
+	}
+}
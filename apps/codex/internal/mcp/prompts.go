@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Prompt describes a reusable prompt template exposed by the server.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument is a named argument accepted by a prompt template.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptMessage is one message of a rendered prompt.
+type PromptMessage struct {
+	Role    string       `json:"role"`
+	Content ContentBlock `json:"content"`
+}
+
+// PromptsListResult is the result of prompts/list.
+type PromptsListResult struct {
+	Prompts    []Prompt `json:"prompts"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+// GetPromptResult is the result of prompts/get.
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// ListPrompts returns the prompt templates the server exposes.
+func (c *Client) ListPrompts() ([]Prompt, error) {
+	return c.ListPromptsCtx(context.Background())
+}
+
+// ListPromptsCtx is ListPrompts with a context that cancels the request.
+// It follows pagination cursors until every prompt is listed.
+func (c *Client) ListPromptsCtx(ctx context.Context) ([]Prompt, error) {
+	prompts, err := listAll[Prompt](ctx, c, "prompts/list", "prompts")
+	if err != nil {
+		return nil, fmt.Errorf("list prompts: %w", err)
+	}
+	return prompts, nil
+}
+
+// GetPrompt renders a prompt template with the given arguments.
+func (c *Client) GetPrompt(name string, args map[string]string) (*GetPromptResult, error) {
+	return c.GetPromptCtx(context.Background(), name, args)
+}
+
+// GetPromptCtx is GetPrompt with a context that cancels the request.
+func (c *Client) GetPromptCtx(ctx context.Context, name string, args map[string]string) (*GetPromptResult, error) {
+	params := map[string]any{"name": name}
+	if len(args) > 0 {
+		params["arguments"] = args
+	}
+	var result GetPromptResult
+	if err := c.call(ctx, "prompts/get", params, &result); err != nil {
+		return nil, fmt.Errorf("get prompt %s: %w", name, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// minRateInterval is the spacing adopted after a 429 that didn't say
+	// how fast to go.
+	minRateInterval = 250 * time.Millisecond
+	// maxRateInterval caps the spacing between paced requests.
+	maxRateInterval = 10 * time.Second
+	// epochThreshold separates a reset given in seconds from one given as a
+	// Unix timestamp.
+	epochThreshold = 1_000_000_000
+)
+
+// RateLimiter paces requests once the server signals a rate limit, so a
+// command making many calls slows down instead of failing. It does nothing
+// until the server answers 429 or sends rate-limit headers:
+//
+//   - RateLimit-Remaining / RateLimit-Reset (or their X-RateLimit- forms):
+//     the remaining budget is spread evenly until the reset, and requests
+//     wait for the reset once it is used up.
+//   - 429 Too Many Requests: requests are spaced out, doubling the spacing
+//     on every further 429, and none is sent before Retry-After. The
+//     spacing eases off again as requests get through.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // minimum spacing between requests; 0 = unpaced
+	next     time.Time     // earliest start of the next request
+	now      func() time.Time
+}
+
+// reserve claims the next request slot and returns how long to wait for it.
+func (r *RateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.clock()
+	start := now
+	if r.next.After(now) {
+		start = r.next
+	}
+	if r.interval > 0 {
+		r.next = start.Add(r.interval)
+	}
+	return start.Sub(now)
+}
+
+// observe updates the pacing from a response.
+func (r *RateLimiter) observe(resp *http.Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.clock()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		r.interval = min(max(r.interval*2, minRateInterval), maxRateInterval)
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			r.pushNext(now.Add(d))
+		} else {
+			r.pushNext(now.Add(r.interval))
+		}
+	}
+
+	remaining, okRemaining := headerInt(resp.Header, "RateLimit-Remaining")
+	reset, okReset := headerInt(resp.Header, "RateLimit-Reset")
+	if !okRemaining || !okReset {
+		if resp.StatusCode != http.StatusTooManyRequests {
+			r.relax()
+		}
+		return
+	}
+	until := time.Duration(reset) * time.Second
+	if reset > epochThreshold {
+		until = time.Unix(int64(reset), 0).Sub(now)
+	}
+	if until < 0 {
+		until = 0
+	}
+	if remaining <= 0 {
+		r.pushNext(now.Add(until))
+		return
+	}
+	if until > 0 {
+		r.interval = min(until/time.Duration(remaining), maxRateInterval)
+	}
+}
+
+// relax speeds pacing back up a little after a request got through, so a
+// brief burst of 429s doesn't slow the rest of a long command.
+func (r *RateLimiter) relax() {
+	r.interval -= r.interval / 10
+	if r.interval < minRateInterval/2 {
+		r.interval = 0
+	}
+}
+
+func (r *RateLimiter) pushNext(t time.Time) {
+	if t.After(r.next) {
+		r.next = t
+	}
+}
+
+func (r *RateLimiter) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// headerInt reads an integer rate-limit header, accepting the standard name
+// and its X- prefixed form.
+func headerInt(h http.Header, name string) (int, bool) {
+	v := h.Get(name)
+	if v == "" {
+		v = h.Get("X-" + name)
+	}
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for RateLimiter.now.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter() (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	return &RateLimiter{now: clock.now}, clock
+}
+
+func response(status int, header map[string]string) *http.Response {
+	h := http.Header{}
+	for k, v := range header {
+		h.Set(k, v)
+	}
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestRateLimiter_IdleUntilSignalled(t *testing.T) {
+	r, _ := newTestLimiter()
+	for i := 0; i < 5; i++ {
+		r.observe(response(http.StatusOK, nil))
+		if d := r.reserve(); d != 0 {
+			t.Fatalf("reserve() = %v before any rate-limit signal, want 0", d)
+		}
+	}
+}
+
+func TestRateLimiter_Headers(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   []time.Duration // waits for successive reserves
+	}{
+		{
+			name:   "budget spread until reset",
+			header: map[string]string{"RateLimit-Remaining": "4", "RateLimit-Reset": "2"},
+			want:   []time.Duration{0, 500 * time.Millisecond, time.Second},
+		},
+		{
+			name:   "x-prefixed headers",
+			header: map[string]string{"X-RateLimit-Remaining": "2", "X-RateLimit-Reset": "1"},
+			want:   []time.Duration{0, 500 * time.Millisecond},
+		},
+		{
+			name:   "exhausted waits for reset",
+			header: map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "3"},
+			want:   []time.Duration{3 * time.Second, 3 * time.Second},
+		},
+		{
+			name:   "reset as unix time",
+			header: map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "1767268805"},
+			want:   []time.Duration{5 * time.Second},
+		},
+		{
+			name:   "malformed ignored",
+			header: map[string]string{"RateLimit-Remaining": "lots", "RateLimit-Reset": "1"},
+			want:   []time.Duration{0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestLimiter()
+			r.observe(response(http.StatusOK, tt.header))
+			for i, want := range tt.want {
+				if got := r.reserve(); got != want {
+					t.Errorf("reserve #%d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiter_TooManyRequests(t *testing.T) {
+	r, clock := newTestLimiter()
+
+	r.observe(response(http.StatusTooManyRequests, map[string]string{"Retry-After": "2"}))
+	if d := r.reserve(); d != 2*time.Second {
+		t.Errorf("first reserve after 429 = %v, want Retry-After of 2s", d)
+	}
+	if d := r.reserve(); d != 2*time.Second+minRateInterval {
+		t.Errorf("second reserve = %v, want 2s + %v", d, minRateInterval)
+	}
+
+	// A further 429 without Retry-After doubles the spacing.
+	clock.advance(time.Minute)
+	r.observe(response(http.StatusTooManyRequests, nil))
+	if d := r.reserve(); d != 2*minRateInterval {
+		t.Errorf("reserve after second 429 = %v, want %v", d, 2*minRateInterval)
+	}
+
+	// Requests getting through ease the pacing off until it stops.
+	clock.advance(time.Minute)
+	for i := 0; i < 20; i++ {
+		r.observe(response(http.StatusOK, nil))
+	}
+	if r.interval != 0 {
+		t.Errorf("interval = %v after many successes, want 0", r.interval)
+	}
+}
+
+func TestDoRequest_RateLimitRetriesBeyondMaxRetries(t *testing.T) {
+	srv, calls := retryServer(t, http.StatusTooManyRequests, 4, http.Header{"Retry-After": {"1"}})
+
+	var notified int
+	c := NewClient(srv.URL)
+	c.Retry.MaxRetries = 1
+	c.sleep = func(time.Duration) {}
+	c.OnRateLimit = func(time.Duration) { notified++ }
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if *calls != 5 {
+		t.Errorf("expected 5 calls, got %d", *calls)
+	}
+	if notified == 0 {
+		t.Error("expected OnRateLimit to be called")
+	}
+}
+
+func TestDoRequest_PacesFollowingRequests(t *testing.T) {
+	srv, _ := retryServer(t, http.StatusOK, 0, nil)
+
+	var slept []time.Duration
+	c := NewClient(srv.URL)
+	c.sleep = func(d time.Duration) { slept = append(slept, d) }
+	clock := &fakeClock{t: time.Now()}
+	c.RateLimit = &RateLimiter{now: clock.now}
+	c.RateLimit.observe(response(http.StatusOK, map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "4"}))
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(slept) != 1 || slept[0] != 4*time.Second {
+		t.Errorf("expected a single 4s wait, got %v", slept)
+	}
+}
@@ -0,0 +1,299 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// recordingVersion is the format version written to recording files.
+const recordingVersion = 1
+
+// recordedHeaders are the response headers kept in a recording; everything
+// else is noise for replay purposes.
+var recordedHeaders = []string{"Content-Type", "Mcp-Session-Id", "Retry-After"}
+
+// Recording is a captured sequence of HTTP exchanges with an MCP server,
+// written by a Recorder and served back by a Replayer.
+type Recording struct {
+	Version   int        `json:"version"`
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// Exchange is one request and the server's answer. Requests are stored with
+// sensitive tool arguments redacted; they are kept for reading, while replay
+// matches on RPCMethod and Tool only.
+type Exchange struct {
+	HTTPMethod string            `json:"http_method"`
+	RPCMethod  string            `json:"rpc_method,omitempty"`
+	Tool       string            `json:"tool,omitempty"`
+	Request    json.RawMessage   `json:"request,omitempty"`
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// key identifies the kind of request an exchange answers.
+func (e *Exchange) key() string {
+	if e.HTTPMethod == http.MethodGet {
+		return "GET"
+	}
+	return e.RPCMethod + " " + e.Tool
+}
+
+// WrapTransport wraps the client's HTTP transport, e.g. with a Recorder or
+// Replayer. Call it after SetHTTPOptions, which replaces the transport.
+func (c *Client) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	c.httpClient.Transport = wrap(c.httpClient.Transport)
+}
+
+// Recorder captures every exchange that passes through the transports it
+// wraps and rewrites its file after each one, so the recording survives a
+// command that exits early.
+type Recorder struct {
+	path string
+	mu   sync.Mutex
+	rec  Recording
+}
+
+// NewRecorder returns a Recorder writing to path.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path, rec: Recording{Version: recordingVersion}}
+}
+
+// Wrap returns a transport that records exchanges made through next.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		ex := Exchange{HTTPMethod: req.Method}
+		if req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			ex.RPCMethod, ex.Tool, _ = describeBody(body)
+			ex.Request = redactBody(body)
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		ex.Status = resp.StatusCode
+		for _, h := range recordedHeaders {
+			if v := resp.Header.Get(h); v != "" {
+				if ex.Headers == nil {
+					ex.Headers = map[string]string{}
+				}
+				ex.Headers[h] = v
+			}
+		}
+		resp.Body = &recordingBody{ReadCloser: resp.Body, done: func(b []byte) {
+			ex.Body = string(b)
+			r.add(ex)
+		}}
+		return resp, nil
+	})
+}
+
+func (r *Recorder) add(ex Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rec.Exchanges = append(r.rec.Exchanges, ex)
+	data, err := json.MarshalIndent(r.rec, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(r.path, append(data, '\n'), 0600)
+}
+
+// recordingBody copies a response body as it is read and reports the copy
+// once, when the body is closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func([]byte)
+	once sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+	return err
+}
+
+// Replayer answers requests from a Recording instead of the network. Each
+// request is served by the first unused exchange of the same kind (JSON-RPC
+// method and tool name), with response IDs rewritten to match the request.
+type Replayer struct {
+	mu   sync.Mutex
+	rec  Recording
+	used []bool
+}
+
+// LoadReplayer reads a recording written by a Recorder.
+func LoadReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parse recording %s: %w", path, err)
+	}
+	if rec.Version != recordingVersion {
+		return nil, fmt.Errorf("recording %s has unsupported version %d", path, rec.Version)
+	}
+	return &Replayer{rec: rec, used: make([]bool, len(rec.Exchanges))}, nil
+}
+
+// Wrap returns a transport that serves requests from the recording. The
+// wrapped transport is never used.
+func (p *Replayer) Wrap(http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(p.roundTrip)
+}
+
+func (p *Replayer) roundTrip(req *http.Request) (*http.Response, error) {
+	want := Exchange{HTTPMethod: req.Method}
+	id := -1
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		want.RPCMethod, want.Tool, id = describeBody(body)
+	}
+
+	ex, err := p.next(want.key())
+	if err != nil {
+		return nil, err
+	}
+
+	body := ex.Body
+	if id >= 0 {
+		body = rewriteResponseID(body, ex.Request, id)
+	}
+	resp := &http.Response{
+		StatusCode: ex.Status,
+		Status:     fmt.Sprintf("%d %s", ex.Status, http.StatusText(ex.Status)),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+	for k, v := range ex.Headers {
+		resp.Header.Set(k, v)
+	}
+	return resp, nil
+}
+
+func (p *Replayer) next(key string) (*Exchange, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.rec.Exchanges {
+		if !p.used[i] && p.rec.Exchanges[i].key() == key {
+			p.used[i] = true
+			return &p.rec.Exchanges[i], nil
+		}
+	}
+	return nil, fmt.Errorf("replay: no recorded response left for %s", strings.TrimSpace(key))
+}
+
+// describeBody extracts the JSON-RPC method, tool name and ID of a request
+// body. The ID is -1 if absent.
+func describeBody(body []byte) (method, tool string, id int) {
+	var req struct {
+		ID     *int   `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return "", "", -1
+	}
+	id = -1
+	if req.ID != nil {
+		id = *req.ID
+	}
+	if req.Method == "tools/call" {
+		tool = req.Params.Name
+	}
+	return req.Method, tool, id
+}
+
+// redactBody returns a request body with sensitive tool arguments
+// redacted, as in debug logs.
+func redactBody(body []byte) json.RawMessage {
+	var req map[string]any
+	if json.Unmarshal(body, &req) != nil {
+		return nil
+	}
+	if params, ok := req["params"].(map[string]any); ok {
+		if args, ok := params["arguments"].(map[string]any); ok {
+			name, _ := params["name"].(string)
+			params["arguments"] = redactArgs(name, args)
+		}
+	}
+	out, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// rewriteResponseID replaces the recorded request's ID with id in a
+// response body, which is either a JSON object or an SSE stream of them.
+func rewriteResponseID(body string, recordedReq json.RawMessage, id int) string {
+	_, _, oldID := describeBody(recordedReq)
+	if oldID < 0 || oldID == id {
+		return body
+	}
+	if !strings.HasPrefix(strings.TrimSpace(body), "{") {
+		var out strings.Builder
+		sc := bufio.NewScanner(strings.NewReader(body))
+		sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for sc.Scan() {
+			line := sc.Text()
+			if data, ok := strings.CutPrefix(line, "data:"); ok {
+				line = "data: " + rewriteMessageID(strings.TrimSpace(data), oldID, id)
+			}
+			out.WriteString(line + "\n")
+		}
+		return out.String()
+	}
+	return rewriteMessageID(body, oldID, id)
+}
+
+func rewriteMessageID(msg string, oldID, id int) string {
+	var m map[string]any
+	if json.Unmarshal([]byte(msg), &m) != nil {
+		return msg
+	}
+	if v, ok := m["id"].(float64); !ok || int(v) != oldID {
+		return msg
+	}
+	m["id"] = id
+	out, err := json.Marshal(m)
+	if err != nil {
+		return msg
+	}
+	return string(out)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		body, _ := io.ReadAll(r.Body)
+		var req JSONRPCRequest
+		json.Unmarshal(body, &req)
+		resp, _ := json.Marshal(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"content": []map[string]any{{"type": "text", "text": `{"status":"ok"}`}}},
+		})
+		if strings.Contains(string(body), `"secret"`) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, `data: {"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1}}`+"\n\n")
+			io.WriteString(w, "data: "+string(resp)+"\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Mcp-Session-Id", "sess-1")
+		w.Write(resp)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	rec := NewRecorder(path)
+	c := NewClient(srv.URL)
+	c.WrapTransport(rec.Wrap)
+	if _, err := c.CallTool("system", map[string]any{"action": "status"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CallTool("secret", map[string]any{"action": "set", "name": "k", "value": "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Error("recording contains a secret value")
+	}
+
+	// Replay in a different order with fresh IDs; the server must not be hit.
+	hits = 0
+	p, err := LoadReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewClient("http://replay.invalid")
+	r.WrapTransport(p.Wrap)
+	r.nextID.Store(40)
+	var notes []Notification
+	r.OnNotification = func(n Notification) { notes = append(notes, n) }
+
+	for _, tool := range []string{"secret", "system"} {
+		result, err := r.CallTool(tool, map[string]any{"action": "x"})
+		if err != nil {
+			t.Fatalf("replay %s: %v", tool, err)
+		}
+		if result["status"] != "ok" {
+			t.Errorf("replay %s: result = %v", tool, result)
+		}
+	}
+	if hits != 0 {
+		t.Errorf("server hit %d times during replay", hits)
+	}
+	if len(notes) != 1 {
+		t.Errorf("expected the recorded notification to be replayed, got %d", len(notes))
+	}
+	if r.SessionID != "sess-1" {
+		t.Errorf("SessionID = %q, want recorded sess-1", r.SessionID)
+	}
+
+	if _, err := r.CallTool("system", nil); err == nil || !strings.Contains(err.Error(), "no recorded response left for tools/call system") {
+		t.Errorf("expected exhausted recording error, got %v", err)
+	}
+}
+
+func TestLoadReplayer_BadVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.json")
+	os.WriteFile(path, []byte(`{"version":99,"exchanges":[]}`), 0600)
+	if _, err := LoadReplayer(path); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Resource describes a server-exposed resource, such as a guide or an
+// execution record.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is one item of a resources/read result. Exactly one of
+// Text or Blob (base64) is set.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// ResourcesListResult is the result of resources/list.
+type ResourcesListResult struct {
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// ReadResourceResult is the result of resources/read.
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// ListResources returns the resources the server exposes.
+func (c *Client) ListResources() ([]Resource, error) {
+	return c.ListResourcesCtx(context.Background())
+}
+
+// ListResourcesCtx is ListResources with a context that cancels the
+// request. It follows pagination cursors until every resource is listed.
+func (c *Client) ListResourcesCtx(ctx context.Context) ([]Resource, error) {
+	resources, err := listAll[Resource](ctx, c, "resources/list", "resources")
+	if err != nil {
+		return nil, fmt.Errorf("list resources: %w", err)
+	}
+	return resources, nil
+}
+
+// ReadResource fetches the contents of a resource by URI.
+func (c *Client) ReadResource(uri string) ([]ResourceContents, error) {
+	return c.ReadResourceCtx(context.Background(), uri)
+}
+
+// ReadResourceCtx is ReadResource with a context that cancels the request.
+func (c *Client) ReadResourceCtx(ctx context.Context, uri string) ([]ResourceContents, error) {
+	var result ReadResourceResult
+	if err := c.call(ctx, "resources/read", map[string]any{"uri": uri}, &result); err != nil {
+		return nil, fmt.Errorf("read resource %s: %w", uri, err)
+	}
+	return result.Contents, nil
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried.
+//
+// Retried failures are: connection refused and other failed dials, HTTP
+// 502/503/504, and HTTP 429. For 429 (and 503) a Retry-After header, in
+// seconds or as an HTTP date, takes precedence over the computed backoff.
+//
+// A request that isn't idempotent, such as tools/call, is only retried if
+// it can't have reached the server (a failed dial) or the server turned it
+// away (HTTP 429, 503, or a Retry-After header): a gateway error after the
+// server accepted a run would otherwise run it twice.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero
+	// disables retrying.
+	MaxRetries int
+	// RateLimitRetries are extra retries allowed for HTTP 429 on top of
+	// MaxRetries. Being rate limited says nothing about the server's health,
+	// so it's worth waiting out rather than failing the command.
+	RateLimitRetries int
+	// BaseDelay is the delay before the first retry; it doubles each retry.
+	BaseDelay time.Duration
+	// MaxDelay caps both the backoff and any server-requested Retry-After.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0–1) of each delay that is randomized to avoid
+	// synchronized retries from parallel clients.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the policy used by NewClient.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:       2,
+		RateLimitRetries: 5,
+		BaseDelay:        500 * time.Millisecond,
+		MaxDelay:         30 * time.Second,
+		Jitter:           0.2,
+	}
+}
+
+// retryDelay decides whether an attempt should be retried and how long to
+// wait first. attempt is 1 for the first retry; idempotent says whether
+// the request may safely reach the server twice.
+func (p RetryPolicy) retryDelay(attempt int, resp *http.Response, err error, idempotent bool) (time.Duration, bool) {
+	limit := p.MaxRetries
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		limit += p.RateLimitRetries
+	}
+	if attempt > limit {
+		return 0, false
+	}
+
+	if err != nil {
+		var opErr *net.OpError
+		if !errors.Is(err, syscall.ECONNREFUSED) && !(errors.As(err, &opErr) && opErr.Op == "dial") {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if hasRetryAfter && p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+		retryAfter = p.MaxDelay
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if hasRetryAfter {
+			return retryAfter, true
+		}
+		return p.backoff(attempt), true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		switch {
+		case idempotent:
+			return p.backoff(attempt), true
+		case hasRetryAfter:
+			return retryAfter, true
+		}
+	}
+	return 0, false
+}
+
+// backoff returns the exponential delay for the given retry attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d <= 0) {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		spread := float64(d) * p.Jitter
+		d = time.Duration(float64(d) - spread + rand.Float64()*2*spread)
+	}
+	return d
+}
+
+// parseRetryAfter parses a Retry-After header given as delta-seconds or an
+// HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in     string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Thu, 01 Jan 2026 12:00:10 GMT", 10 * time.Second, true},
+		{"Thu, 01 Jan 2026 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.in, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestBackoff_Exponential(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// retryServer fails with the given status for the first n requests, then succeeds.
+func retryServer(t *testing.T, status, n int, header http.Header) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= n {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Result: map[string]any{
+				"content": []map[string]any{{"type": "text", "text": `{"status":"ok"}`}},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestDoRequest_RetriesTransientStatus(t *testing.T) {
+	for _, status := range []int{502, 503, 504} {
+		srv, calls := retryServer(t, status, 2, nil)
+
+		var slept []time.Duration
+		c := NewClient(srv.URL)
+		c.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+		if _, err := c.ListTools(); err != nil {
+			t.Fatalf("HTTP %d: ListTools failed: %v", status, err)
+		}
+		if *calls != 3 {
+			t.Errorf("HTTP %d: expected 3 calls, got %d", status, *calls)
+		}
+		if len(slept) != 2 {
+			t.Errorf("HTTP %d: expected 2 sleeps, got %d", status, len(slept))
+		}
+	}
+}
+
+func TestDoRequest_ToolCallRetries(t *testing.T) {
+	tests := []struct {
+		status int
+		header http.Header
+		calls  int
+	}{
+		// The server turned the call away: it never ran.
+		{503, nil, 3},
+		{502, http.Header{"Retry-After": {"1"}}, 3},
+		// A gateway gave up on the call, which may have run.
+		{502, nil, 1},
+		{504, nil, 1},
+	}
+	for _, tt := range tests {
+		srv, calls := retryServer(t, tt.status, 2, tt.header)
+		c := NewClient(srv.URL)
+		c.sleep = func(time.Duration) {}
+
+		result, err := c.CallTool("test-tool", nil)
+		if *calls != tt.calls {
+			t.Errorf("HTTP %d %v: expected %d calls, got %d", tt.status, tt.header, tt.calls, *calls)
+		}
+		if tt.calls == 3 && (err != nil || result["status"] != "ok") {
+			t.Errorf("HTTP %d %v: CallTool = %v, %v", tt.status, tt.header, result, err)
+		}
+		if tt.calls == 1 && err == nil {
+			t.Errorf("HTTP %d: expected an error", tt.status)
+		}
+	}
+}
+
+func TestDoRequest_HonorsRetryAfter(t *testing.T) {
+	srv, _ := retryServer(t, http.StatusTooManyRequests, 1, http.Header{"Retry-After": {"7"}})
+
+	var slept []time.Duration
+	c := NewClient(srv.URL)
+	c.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(slept) != 1 || slept[0] != 7*time.Second {
+		t.Errorf("expected a single 7s sleep, got %v", slept)
+	}
+}
+
+func TestDoRequest_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, calls := retryServer(t, http.StatusServiceUnavailable, 10, nil)
+
+	c := NewClient(srv.URL)
+	c.Retry.MaxRetries = 1
+	c.sleep = func(time.Duration) {}
+
+	_, err := c.CallTool("test-tool", nil)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 503") {
+		t.Errorf("expected HTTP 503 error, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestDoRequest_DoesNotRetryServerError(t *testing.T) {
+	srv, calls := retryServer(t, http.StatusInternalServerError, 1, nil)
+
+	c := NewClient(srv.URL)
+	c.sleep = func(time.Duration) { t.Error("unexpected sleep") }
+
+	if _, err := c.CallTool("test-tool", nil); err == nil {
+		t.Fatal("expected error for HTTP 500")
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 call, got %d", *calls)
+	}
+}
+
+func TestDoRequest_RetriesConnectionRefused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	sleeps := 0
+	c := NewClient(url)
+	c.sleep = func(time.Duration) { sleeps++ }
+
+	if _, err := c.CallTool("test-tool", nil); err == nil {
+		t.Fatal("expected connection error")
+	}
+	if sleeps != c.Retry.MaxRetries {
+		t.Errorf("expected %d retries, got %d", c.Retry.MaxRetries, sleeps)
+	}
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ArgumentError reports tool arguments that don't match the tool's
+// inputSchema, caught before the call is sent.
+type ArgumentError struct {
+	Tool     string
+	Problems []string
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("invalid arguments for %s: %s", e.Tool, strings.Join(e.Problems, "; "))
+}
+
+// ValidateArguments checks args against a tool's inputSchema and returns an
+// *ArgumentError listing every problem found, or nil if they match.
+//
+// It understands the subset of JSON Schema that tool definitions use:
+// type, required, enum, properties, items and oneOf. Properties the schema
+// doesn't declare are allowed, since servers routinely accept more than
+// they advertise.
+func ValidateArguments(tool string, schema any, args map[string]any) error {
+	def, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var problems []string
+	validateObject(def, args, "", &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ArgumentError{Tool: tool, Problems: problems}
+}
+
+func validateObject(def map[string]any, obj map[string]any, prefix string, problems *[]string) {
+	if required, ok := def["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; name != "" && !present {
+				*problems = append(*problems, fmt.Sprintf("missing required field '%s'", prefix+name))
+			}
+		}
+	}
+
+	props, _ := def["properties"].(map[string]any)
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propDef, ok := props[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		validateValue(propDef, obj[name], prefix+name, problems)
+	}
+}
+
+func validateValue(def map[string]any, v any, path string, problems *[]string) {
+	if v == nil {
+		return
+	}
+
+	if variants, ok := def["oneOf"].([]any); ok && len(variants) > 0 && def["type"] == nil {
+		for _, variant := range variants {
+			vdef, _ := variant.(map[string]any)
+			var sub []string
+			validateValue(vdef, v, path, &sub)
+			if len(sub) == 0 {
+				return
+			}
+		}
+		*problems = append(*problems, fmt.Sprintf("field '%s' does not match any allowed form", path))
+		return
+	}
+
+	if typ, ok := def["type"].(string); ok && !hasType(v, typ) {
+		*problems = append(*problems, fmt.Sprintf("field '%s' must be %s, got %s", path, article(typ), article(jsonType(v))))
+		return
+	}
+
+	if enum, ok := def["enum"].([]any); ok && len(enum) > 0 {
+		found := false
+		allowed := make([]string, len(enum))
+		for i, e := range enum {
+			allowed[i] = fmt.Sprint(e)
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+			}
+		}
+		if !found {
+			*problems = append(*problems, fmt.Sprintf("field '%s' must be one of: %s (got %v)", path, strings.Join(allowed, ", "), v))
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		if _, ok := def["properties"]; ok {
+			validateObject(def, val, path+".", problems)
+		}
+	case []any:
+		if items, ok := def["items"].(map[string]any); ok {
+			for i, item := range val {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case []string:
+		if items, ok := def["items"].(map[string]any); ok {
+			for i, item := range val {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+// hasType reports whether v, as built by Go code or decoded from JSON,
+// is of JSON Schema type typ.
+func hasType(v any, typ string) bool {
+	switch typ {
+	case "integer":
+		switch n := v.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return n == math.Trunc(n)
+		}
+		return false
+	case "number":
+		switch v.(type) {
+		case int, int32, int64, float32, float64:
+			return true
+		}
+		return false
+	}
+	return jsonType(v) == typ
+}
+
+// jsonType names the JSON type v encodes to.
+func jsonType(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int32, int64, float32, float64:
+		return "number"
+	case map[string]any, map[string]string:
+		return "object"
+	case []any, []string, []map[string]any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func article(typ string) string {
+	switch typ {
+	case "object", "array", "integer":
+		return "an " + typ
+	}
+	return "a " + typ
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var executionSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"action": map[string]any{"type": "string", "enum": []any{"run", "list", "logs", "cancel"}},
+		"type":   map[string]any{"type": "string", "enum": []any{"catalyst", "reagent", "formula"}},
+		"limit":  map[string]any{"type": "integer"},
+		"input":  map[string]any{"type": "object"},
+		"verify": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"identity": map[string]any{"type": "string"},
+			},
+		},
+		"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		"path": map[string]any{"oneOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		}},
+	},
+	"required": []any{"action"},
+}
+
+func TestValidateArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string // substrings of the error; nil means valid
+	}{
+		{"valid", map[string]any{"action": "run", "type": "reagent", "input": map[string]any{}}, nil},
+		{"undeclared fields allowed", map[string]any{"action": "list", "cursor": "abc"}, nil},
+		{"integral float is integer", map[string]any{"action": "list", "limit": float64(20)}, nil},
+		{"oneOf string", map[string]any{"action": "list", "path": "a/b"}, nil},
+		{"oneOf array", map[string]any{"action": "list", "path": []string{"a", "b"}}, nil},
+		{"missing required", map[string]any{"type": "reagent"}, []string{"missing required field 'action'"}},
+		{"enum", map[string]any{"action": "run", "type": "potion"}, []string{"field 'type' must be one of: catalyst, reagent, formula (got potion)"}},
+		{"wrong type", map[string]any{"action": "list", "limit": "ten"}, []string{"field 'limit' must be an integer, got a string"}},
+		{"fractional integer", map[string]any{"action": "list", "limit": 1.5}, []string{"field 'limit' must be an integer"}},
+		{"nested", map[string]any{"action": "run", "verify": map[string]any{"identity": 42}}, []string{"field 'verify.identity' must be a string"}},
+		{"array items", map[string]any{"action": "run", "tags": []any{"ok", 3}}, []string{"field 'tags[1]' must be a string"}},
+		{"oneOf mismatch", map[string]any{"action": "list", "path": 7}, []string{"field 'path' does not match any allowed form"}},
+		{"several problems", map[string]any{"type": "potion"}, []string{"missing required field 'action'", "field 'type' must be one of"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateArguments("execution", executionSchema, tt.args)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var argErr *ArgumentError
+			if !errors.As(err, &argErr) {
+				t.Fatalf("expected *ArgumentError, got %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q missing %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateArguments_NoSchema(t *testing.T) {
+	if err := ValidateArguments("x", nil, map[string]any{"action": 1}); err != nil {
+		t.Errorf("expected nil without a schema, got %v", err)
+	}
+}
+
+func TestCallTool_InputSchemaBlocksInvalidCall(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.InputSchema = func(ctx context.Context, tool string) any {
+		if tool == "execution" {
+			return executionSchema
+		}
+		return nil
+	}
+
+	_, err := c.CallTool("execution", map[string]any{"action": "explode"})
+	var argErr *ArgumentError
+	if !errors.As(err, &argErr) {
+		t.Fatalf("expected *ArgumentError, got %v", err)
+	}
+	if argErr.Tool != "execution" {
+		t.Errorf("Tool = %q, want execution", argErr.Tool)
+	}
+	if called {
+		t.Error("invalid call should not reach the server")
+	}
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Notification is a server-initiated JSON-RPC notification, such as
+// "notifications/progress" or "notifications/message".
+type Notification struct {
+	Method string         `json:"method"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// sseEvent is a single Server-Sent Event.
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// readSSE reads Server-Sent Events from r and calls fn for each complete
+// event. It stops at EOF or when fn returns false.
+func readSSE(r io.Reader, fn func(sseEvent) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+
+	var ev sseEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				ev.Data = strings.Join(data, "\n")
+				if !fn(ev) {
+					return nil
+				}
+			}
+			ev = sseEvent{}
+			data = nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment / keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event":
+			ev.Event = value
+		case "id":
+			ev.ID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// Dispatch a trailing event that wasn't followed by a blank line.
+	if len(data) > 0 {
+		ev.Data = strings.Join(data, "\n")
+		fn(ev)
+	}
+	return nil
+}
+
+// readStreamedResponse reads an SSE response body to a POST request,
+// dispatching any notifications to the client's handler, and returns the
+// JSON-RPC response whose ID matches id.
+func (c *Client) readStreamedResponse(body io.Reader, id int) (*JSONRPCResponse, error) {
+	var resp *JSONRPCResponse
+	var decodeErr error
+	err := readSSE(body, func(ev sseEvent) bool {
+		var msg JSONRPCMessage
+		if err := json.Unmarshal([]byte(ev.Data), &msg); err != nil {
+			decodeErr = fmt.Errorf("unmarshal stream message: %w", err)
+			return true
+		}
+		if msg.ID == nil {
+			if msg.Method != "" {
+				c.notify(Notification{Method: msg.Method, Params: msg.Params})
+			}
+			return true
+		}
+		if *msg.ID != id {
+			return true
+		}
+		resp = &JSONRPCResponse{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Result:  msg.Result,
+			Error:   msg.Error,
+		}
+		return false
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read stream: %w", err)
+	}
+	if resp == nil {
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		return nil, fmt.Errorf("stream ended without a response")
+	}
+	return resp, nil
+}
+
+// Progress is a notifications/progress update for a request that asked for
+// progress. Total is zero when the server doesn't know it. Params holds the
+// notification's raw params, including any server-specific fields.
+type Progress struct {
+	Token    any
+	Progress float64
+	Total    float64
+	Message  string
+	Params   map[string]any
+}
+
+// progressFromParams decodes notifications/progress params.
+func progressFromParams(params map[string]any) Progress {
+	p := Progress{Token: params["progressToken"], Params: params}
+	p.Progress, _ = params["progress"].(float64)
+	p.Total, _ = params["total"].(float64)
+	p.Message, _ = params["message"].(string)
+	return p
+}
+
+func (c *Client) notify(n Notification) {
+	if n.Method == "notifications/progress" && c.OnProgress != nil {
+		c.OnProgress(progressFromParams(n.Params))
+		return
+	}
+	if c.OnNotification != nil {
+		c.OnNotification(n)
+	}
+}
+
+// Subscription is an open server-to-client notification stream.
+type Subscription struct {
+	// C receives notifications until the stream ends or Close is called.
+	C <-chan Notification
+
+	body io.ReadCloser
+	stop chan struct{}
+	done chan struct{}
+	err  error
+}
+
+// Close stops the subscription and releases the connection.
+func (s *Subscription) Close() error {
+	close(s.stop)
+	err := s.body.Close()
+	<-s.done
+	return err
+}
+
+// Err returns the error that ended the stream, if any. It is only valid
+// after C has been closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Subscribe opens the streamable-HTTP GET stream on /mcp and delivers
+// server-initiated notifications on the returned subscription's channel.
+// Servers that do not offer a standalone stream answer with HTTP 405.
+func (c *Client) Subscribe() (*Subscription, error) {
+	return c.SubscribeCtx(context.Background())
+}
+
+// SubscribeCtx is Subscribe with a context; cancelling ctx ends the stream
+// and closes C.
+func (c *Client) SubscribeCtx(ctx context.Context) (*Subscription, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/mcp", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	c.setSessionHeaders(httpReq)
+	c.debugf("--> GET /mcp notification stream")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		httpResp.Body.Close()
+		if httpResp.StatusCode == http.StatusMethodNotAllowed {
+			return nil, ErrStreamingUnsupported
+		}
+		return nil, fmt.Errorf("HTTP %d", httpResp.StatusCode)
+	}
+
+	ch := make(chan Notification, 16)
+	sub := &Subscription{C: ch, body: httpResp.Body, stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(sub.done)
+		defer close(ch)
+		err := readSSE(httpResp.Body, func(ev sseEvent) bool {
+			var msg JSONRPCMessage
+			if json.Unmarshal([]byte(ev.Data), &msg) != nil || msg.ID != nil || msg.Method == "" {
+				return true
+			}
+			select {
+			case ch <- Notification{Method: msg.Method, Params: msg.Params}:
+				return true
+			case <-sub.stop:
+				return false
+			}
+		})
+		select {
+		case <-sub.stop:
+			// Closed by the caller; the read error is expected.
+		case <-ctx.Done():
+			sub.err = ctx.Err()
+		default:
+			sub.err = err
+		}
+	}()
+	return sub, nil
+}
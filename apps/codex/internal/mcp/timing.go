@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"io"
+	"time"
+)
+
+// CallStats describes one request made by a Client, for diagnosing whether
+// time goes to the network, the server, or waiting out rate limits.
+type CallStats struct {
+	Method string
+	Tool   string // for tools/call
+
+	// Duration is the time from sending the request to reading the last of
+	// the response, including retries and rate-limit waits.
+	Duration time.Duration
+	// RateLimitWait is the part of Duration spent held back by pacing.
+	RateLimitWait time.Duration
+
+	RequestBytes  int64
+	ResponseBytes int64 // of the final attempt
+	Retries       int
+	Status        int // HTTP status of the final attempt; 0 if none
+	Err           error
+}
+
+// recordCall reports a finished request to OnCall.
+func (c *Client) recordCall(req JSONRPCRequest, stats *CallStats, start time.Time, err error) {
+	if c.OnCall == nil {
+		return
+	}
+	stats.Method = req.Method
+	if params, ok := req.Params.(ToolCallParams); ok {
+		stats.Tool = params.Name
+	}
+	stats.Duration = time.Since(start)
+	stats.Err = err
+	c.OnCall(*stats)
+}
+
+// countingBody adds the bytes read from a response body to *n.
+type countingBody struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	*b.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOnCall_ReportsStats(t *testing.T) {
+	srv, _ := retryServer(t, http.StatusServiceUnavailable, 1, nil)
+
+	var stats []CallStats
+	c := NewClient(srv.URL)
+	c.sleep = func(time.Duration) {}
+	c.OnCall = func(s CallStats) { stats = append(stats, s) }
+
+	if _, err := c.CallTool("test-tool", map[string]any{"a": 1}); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 call reported, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.Method != "tools/call" || s.Tool != "test-tool" {
+		t.Errorf("method/tool = %q/%q", s.Method, s.Tool)
+	}
+	if s.Retries != 1 || s.Status != http.StatusOK || s.Err != nil {
+		t.Errorf("retries=%d status=%d err=%v", s.Retries, s.Status, s.Err)
+	}
+	if s.RequestBytes == 0 || s.ResponseBytes == 0 {
+		t.Errorf("sizes not recorded: %+v", s)
+	}
+}
+
+func TestOnCall_ReportsFailure(t *testing.T) {
+	srv, _ := retryServer(t, http.StatusInternalServerError, 1, nil)
+
+	var stats []CallStats
+	c := NewClient(srv.URL)
+	c.OnCall = func(s CallStats) { stats = append(stats, s) }
+
+	if _, err := c.CallTool("test-tool", nil); err == nil {
+		t.Fatal("expected error for HTTP 500")
+	}
+	if len(stats) != 1 || stats[0].Err == nil || stats[0].Status != http.StatusInternalServerError {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// HTTPOptions configures the client's HTTP transport and timeouts.
+//
+// Proxies are always taken from the environment (HTTPS_PROXY, HTTP_PROXY,
+// NO_PROXY), as with any Go HTTP client.
+type HTTPOptions struct {
+	// ConnectTimeout bounds dialing and the TLS handshake. Zero means no limit.
+	ConnectTimeout time.Duration
+	// RequestTimeout bounds a whole tool call, including retries and reading
+	// a streamed response. Zero means no limit. It does not apply to
+	// Subscribe, whose stream is expected to stay open.
+	RequestTimeout time.Duration
+	// MaxIdleConns is the number of keep-alive connections kept open to the
+	// server between requests.
+	MaxIdleConns int
+	// TLS, if set, is used for HTTPS connections. See LoadTLSConfig.
+	TLS *tls.Config
+}
+
+// DefaultHTTPOptions returns the options used by NewClient. The request
+// timeout is generous because a tool call may wait for a long execution.
+func DefaultHTTPOptions() HTTPOptions {
+	return HTTPOptions{
+		ConnectTimeout: 10 * time.Second,
+		RequestTimeout: 10 * time.Minute,
+		MaxIdleConns:   4,
+	}
+}
+
+// SetHTTPOptions replaces the client's transport and request timeout.
+func (c *Client) SetHTTPOptions(o HTTPOptions) {
+	c.Timeout = o.RequestTimeout
+	c.httpOpts = o
+	c.httpClient = &http.Client{Transport: newTransport(o)}
+}
+
+func newTransport(o HTTPOptions) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   o.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: o.ConnectTimeout,
+		TLSClientConfig:     o.TLS,
+		MaxIdleConns:        o.MaxIdleConns,
+		MaxIdleConnsPerHost: o.MaxIdleConns,
+		IdleConnTimeout:     90 * time.Second,
+		ForceAttemptHTTP2:   true,
+	}
+}
+
+// LoadTLSConfig builds a TLS configuration for servers behind an internal CA
+// or requiring mutual TLS. caCert is a PEM bundle trusted in addition to the
+// system roots; clientCert and clientKey are a PEM key pair presented to the
+// server and must be given together. It returns nil when no option is set,
+// so the transport keeps Go's defaults.
+func LoadTLSConfig(caCert, clientCert, clientKey string, insecureSkipVerify bool) (*tls.Config, error) {
+	if caCert == "" && clientCert == "" && clientKey == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caCert != "" {
+		pem, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("read CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caCert)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (clientCert == "") != (clientKey == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if clientCert != "" {
+		pair, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+
+	return cfg, nil
+}
+
+// contextErr converts a cancelled request context into the error returned
+// to callers, naming the timeout that expired when it was set with
+// WithTimeout, as the client's own Timeout is.
+func (c *Client) contextErr(ctx context.Context) error {
+	err := ctx.Err()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if d, ok := expiredTimeout(ctx); ok {
+		return fmt.Errorf("request timed out after %s: %w", d, err)
+	}
+	return fmt.Errorf("request timed out: %w", err)
+}
+
+// timeoutKey is the context key of the timeouts WithTimeout sets.
+type timeoutKey struct{}
+
+// A timeout is one set with WithTimeout, linked to the one set before it
+// on the same context.
+type timeout struct {
+	d        time.Duration
+	deadline time.Time
+	outer    *timeout
+}
+
+// WithTimeout is context.WithTimeout, but a request that runs out of time
+// under the returned context reports d as the timeout that expired.
+func WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, d)
+	deadline, _ := ctx.Deadline()
+	if first, ok := parent.Deadline(); ok && !deadline.Before(first) {
+		// The parent runs out first: d never expires.
+		return ctx, cancel
+	}
+	outer, _ := parent.Value(timeoutKey{}).(*timeout)
+	return context.WithValue(ctx, timeoutKey{}, &timeout{d: d, deadline: deadline, outer: outer}), cancel
+}
+
+// expiredTimeout returns the timeout set with WithTimeout whose deadline is
+// ctx's, which an earlier one further out may have set.
+func expiredTimeout(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	t, _ := ctx.Value(timeoutKey{}).(*timeout)
+	for ; t != nil; t = t.outer {
+		if t.deadline.Equal(deadline) {
+			return t.d, true
+		}
+	}
+	return 0, false
+}
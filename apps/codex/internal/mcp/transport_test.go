@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTLSConfig_NoneSet(t *testing.T) {
+	cfg, err := LoadTLSConfig("", "", "", false)
+	if err != nil || cfg != nil {
+		t.Errorf("expected nil config and no error, got %v, %v", cfg, err)
+	}
+}
+
+func TestLoadTLSConfig_CertWithoutKey(t *testing.T) {
+	_, err := LoadTLSConfig("", "client.pem", "", false)
+	if err == nil || !strings.Contains(err.Error(), "set together") {
+		t.Errorf("expected pairing error, got %v", err)
+	}
+}
+
+func TestLoadTLSConfig_CAWithoutCertificates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(path, []byte("not a certificate"), 0600)
+
+	_, err := LoadTLSConfig(path, "", "", false)
+	if err == nil || !strings.Contains(err.Error(), "no certificates") {
+		t.Errorf("expected no certificates error, got %v", err)
+	}
+}
+
+func TestCallTool_CustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Result: map[string]any{
+				"content": []map[string]any{{"type": "text", "text": `{"status":"ok"}`}},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	// Without the server's CA the handshake must fail.
+	c := NewClient(srv.URL)
+	c.Retry.MaxRetries = 0
+	if _, err := c.CallTool("test-tool", nil); err == nil {
+		t.Fatal("expected certificate verification error")
+	}
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig, err := LoadTLSConfig(caPath, "", "", false)
+	if err != nil {
+		t.Fatalf("LoadTLSConfig failed: %v", err)
+	}
+	opts := DefaultHTTPOptions()
+	opts.TLS = tlsConfig
+	c.SetHTTPOptions(opts)
+
+	result, err := c.CallTool("test-tool", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result["status"] != "ok" {
+		t.Errorf("expected status ok, got %v", result["status"])
+	}
+}
@@ -0,0 +1,266 @@
+package typed
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+// Generate returns Go source for package pkg declaring typed bindings for
+// tools: an args struct per tool, a string type and constants for its
+// actions, a Map method building the tools/call arguments, and a Client
+// method calling it.
+//
+// Schema types map to Go as string, int, float64, bool, []string, []any and
+// map[string]any; properties with no single type (oneOf, missing) become
+// any. Optional properties are omitted from the call when left at their
+// zero value.
+func Generate(pkg string, tools []mcp.Tool) ([]byte, error) {
+	sorted := append([]mcp.Tool(nil), tools...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by \"cyfr tools generate-client\"; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if len(sorted) > 0 {
+		fmt.Fprintln(&b, `import "context"`)
+		fmt.Fprintln(&b)
+	}
+
+	seen := map[string]string{}
+	for _, tool := range sorted {
+		typeName := exportedName(tool.Name)
+		if typeName == "" {
+			return nil, fmt.Errorf("tool %q: no usable Go name", tool.Name)
+		}
+		if other, ok := seen[typeName]; ok {
+			return nil, fmt.Errorf("tools %q and %q both map to %s", other, tool.Name, typeName)
+		}
+		seen[typeName] = tool.Name
+		if err := generateTool(&b, typeName, tool); err != nil {
+			return nil, fmt.Errorf("tool %q: %w", tool.Name, err)
+		}
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// property is one input schema property resolved to a Go field.
+type property struct {
+	name     string
+	field    string
+	goType   string
+	doc      string
+	required bool
+	enum     []string
+}
+
+func generateTool(b *bytes.Buffer, typeName string, tool mcp.Tool) error {
+	schema, _ := tool.InputSchema.(map[string]any)
+	props, err := schemaProperties(schema)
+	if err != nil {
+		return err
+	}
+
+	argsType := typeName + "Args"
+	var action *property
+	for i := range props {
+		if props[i].name == "action" && props[i].goType == "string" {
+			action = &props[i]
+			action.goType = typeName + "Action"
+		}
+	}
+
+	if action != nil {
+		fmt.Fprintf(b, "// %s is an action of the %q tool.\n", action.goType, tool.Name)
+		fmt.Fprintf(b, "type %s string\n\n", action.goType)
+		if len(action.enum) > 0 {
+			fmt.Fprintf(b, "// Actions of the %q tool.\n", tool.Name)
+			fmt.Fprintln(b, "const (")
+			for _, v := range action.enum {
+				fmt.Fprintf(b, "\t%s%s %s = %q\n", action.goType, exportedName(v), action.goType, v)
+			}
+			fmt.Fprintln(b, ")")
+			fmt.Fprintln(b)
+		}
+	}
+
+	desc := strings.TrimSpace(tool.Description)
+	fmt.Fprintf(b, "// %s are the arguments to the %q tool.", argsType, tool.Name)
+	if desc != "" {
+		fmt.Fprintf(b, "\n//\n// %s", commentText(desc))
+	}
+	fmt.Fprintf(b, "\ntype %s struct {\n", argsType)
+	for _, p := range props {
+		if p.doc != "" {
+			fmt.Fprintf(b, "\t// %s\n", commentText(p.doc))
+		}
+		fmt.Fprintf(b, "\t%s %s\n", p.field, p.goType)
+	}
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b)
+
+	fmt.Fprintf(b, "// Map returns the arguments as sent in tools/call.\n")
+	fmt.Fprintf(b, "func (a %s) Map() map[string]any {\n", argsType)
+	fmt.Fprintln(b, "\tm := map[string]any{}")
+	for _, p := range props {
+		value := "a." + p.field
+		if action != nil && p.name == action.name {
+			value = "string(a." + p.field + ")"
+		}
+		if cond := zeroCheck("a."+p.field, p.goType); cond != "" && !p.required {
+			fmt.Fprintf(b, "\tif %s {\n\t\tm[%q] = %s\n\t}\n", cond, p.name, value)
+		} else {
+			fmt.Fprintf(b, "\tm[%q] = %s\n", p.name, value)
+		}
+	}
+	fmt.Fprintln(b, "\treturn m")
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b)
+
+	fmt.Fprintf(b, "// %s calls the %q tool.\n", typeName, tool.Name)
+	fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context, args %s) (map[string]any, error) {\n", typeName, argsType)
+	fmt.Fprintf(b, "\treturn c.CallToolCtx(ctx, %q, args.Map())\n", tool.Name)
+	fmt.Fprintln(b, "}")
+	fmt.Fprintln(b)
+	return nil
+}
+
+// schemaProperties resolves the properties of an object schema, required
+// ones first in the order listed and the rest sorted by name.
+func schemaProperties(schema map[string]any) ([]property, error) {
+	raw, _ := schema["properties"].(map[string]any)
+	required := map[string]int{}
+	if list, ok := schema["required"].([]any); ok {
+		for i, v := range list {
+			if s, ok := v.(string); ok {
+				required[s] = i
+			}
+		}
+	}
+
+	props := make([]property, 0, len(raw))
+	fields := map[string]string{}
+	for name, v := range raw {
+		def, _ := v.(map[string]any)
+		field := exportedName(name)
+		if field == "" {
+			return nil, fmt.Errorf("property %q: no usable Go name", name)
+		}
+		if other, ok := fields[field]; ok {
+			return nil, fmt.Errorf("properties %q and %q both map to %s", other, name, field)
+		}
+		fields[field] = name
+
+		_, req := required[name]
+		p := property{
+			name:     name,
+			field:    field,
+			goType:   goType(def),
+			required: req,
+		}
+		p.doc, _ = def["description"].(string)
+		if list, ok := def["enum"].([]any); ok {
+			for _, e := range list {
+				if s, ok := e.(string); ok {
+					p.enum = append(p.enum, s)
+				}
+			}
+		}
+		props = append(props, p)
+	}
+
+	sort.Slice(props, func(i, j int) bool {
+		ri, iReq := required[props[i].name]
+		rj, jReq := required[props[j].name]
+		if iReq != jReq {
+			return iReq
+		}
+		if iReq {
+			return ri < rj
+		}
+		return props[i].name < props[j].name
+	})
+	return props, nil
+}
+
+// goType maps a JSON Schema property to a Go type.
+func goType(def map[string]any) string {
+	switch def["type"] {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "object":
+		return "map[string]any"
+	case "array":
+		items, _ := def["items"].(map[string]any)
+		if items["type"] == "string" {
+			return "[]string"
+		}
+		return "[]any"
+	}
+	return "any"
+}
+
+// zeroCheck returns a condition that is true when expr of type typ is set,
+// or "" if the type has no meaningful zero value to skip.
+func zeroCheck(expr, typ string) string {
+	switch {
+	case typ == "int" || typ == "float64":
+		return expr + " != 0"
+	case typ == "bool":
+		return expr
+	case strings.HasPrefix(typ, "[]"):
+		return "len(" + expr + ") > 0"
+	case strings.HasPrefix(typ, "map[") || typ == "any":
+		return expr + " != nil"
+	case typ == "string" || strings.HasSuffix(typ, "Action"):
+		return expr + ` != ""`
+	}
+	return ""
+}
+
+// initialisms are name parts written in all caps, following Go convention.
+var initialisms = map[string]bool{
+	"api": true, "id": true, "ip": true, "json": true, "oci": true,
+	"uri": true, "url": true,
+}
+
+// exportedName converts a snake_case or kebab-case name to an exported Go
+// identifier, e.g. "execution_id" to "ExecutionID".
+func exportedName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		if initialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	name := b.String()
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "X" + name
+	}
+	return name
+}
+
+// commentText flattens a description onto one comment line.
+func commentText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,146 @@
+package typed
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cyfr/codex/internal/mcp"
+)
+
+func TestExportedName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"key", "Key"},
+		{"execution_id", "ExecutionID"},
+		{"ip_allowlist", "IPAllowlist"},
+		{"source_url", "SourceURL"},
+		{"device-init", "DeviceInit"},
+		{"resolve_granted", "ResolveGranted"},
+		{"2fa", "X2fa"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := exportedName(tt.in); got != tt.want {
+				t.Errorf("exportedName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tools := []mcp.Tool{{
+		Name:        "widget",
+		Description: "Manage widgets",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action":  map[string]any{"type": "string", "enum": []any{"list", "get_one"}},
+				"name":    map[string]any{"type": "string", "description": "Widget name"},
+				"limit":   map[string]any{"type": "integer"},
+				"tags":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"filters": map[string]any{"type": "object"},
+				"path":    map[string]any{"oneOf": []any{map[string]any{"type": "string"}}},
+				"dry_run": map[string]any{"type": "boolean"},
+			},
+			"required": []any{"action"},
+		},
+	}}
+
+	src, err := Generate("widgets", tools)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, want := range []string{
+		"package widgets",
+		"type WidgetAction string",
+		`WidgetActionGetOne WidgetAction = "get_one"`,
+		"// Widget name Name string",
+		"Limit int",
+		"Tags []string",
+		"Filters map[string]any",
+		"Path any",
+		"DryRun bool",
+		`m["action"] = string(a.Action)`,
+		"if a.DryRun {",
+		"func (c *Client) Widget(ctx context.Context, args WidgetArgs) (map[string]any, error)",
+	} {
+		if !strings.Contains(collapseSpace(string(src)), want) {
+			t.Errorf("generated code missing %q:\n%s", want, src)
+		}
+	}
+}
+
+// collapseSpace undoes gofmt's column alignment so tests can match fields.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestGenerate_NameCollision(t *testing.T) {
+	tools := []mcp.Tool{{Name: "api_key"}, {Name: "api-key"}}
+	if _, err := Generate("typed", tools); err == nil {
+		t.Fatal("expected error for tools mapping to the same Go name")
+	}
+}
+
+// TestGeneratedUpToDate fails when tools_gen.go was edited by hand or not
+// regenerated after tools.json changed; run 'go generate' to fix it.
+func TestGeneratedUpToDate(t *testing.T) {
+	data, err := os.ReadFile("tools.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list mcp.ToolsListResult
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatal(err)
+	}
+	want, err := Generate("typed", list.Tools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("tools_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("tools_gen.go is out of date with tools.json; run 'go generate ./internal/mcp/typed'")
+	}
+}
+
+func TestArgsMap(t *testing.T) {
+	tests := []struct {
+		name string
+		args KeyArgs
+		want map[string]any
+	}{
+		{
+			name: "zero values omitted",
+			args: KeyArgs{Action: KeyActionGet, Name: "ci"},
+			want: map[string]any{"action": "get", "name": "ci"},
+		},
+		{
+			name: "all set",
+			args: KeyArgs{Action: KeyActionCreate, Name: "ci", Type: "secret", Scope: []string{"read"}, RateLimit: "100/1m", IPAllowlist: []string{"10.0.0.0/8"}},
+			want: map[string]any{
+				"action":       "create",
+				"name":         "ci",
+				"type":         "secret",
+				"scope":        []string{"read"},
+				"rate_limit":   "100/1m",
+				"ip_allowlist": []string{"10.0.0.0/8"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.args.Map(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
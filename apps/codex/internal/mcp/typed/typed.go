@@ -0,0 +1,23 @@
+// Package typed provides strongly-typed wrappers for the CYFR MCP tools.
+//
+// Each tool gets an args struct built from its inputSchema, constants for
+// its actions, and a method on Client that calls it. The bindings in
+// tools_gen.go are generated from the tools.json snapshot; regenerate them
+// after the server's tool schemas change:
+//
+//	cyfr tools generate-client --file internal/mcp/typed/tools_gen.go
+package typed
+
+//go:generate go run github.com/cyfr/codex tools generate-client --schema-file tools.json --file tools_gen.go
+
+import "github.com/cyfr/codex/internal/mcp"
+
+// Client calls CYFR tools with typed arguments.
+type Client struct {
+	*mcp.Client
+}
+
+// New wraps an MCP client.
+func New(c *mcp.Client) *Client {
+	return &Client{Client: c}
+}
@@ -16,6 +16,17 @@ type JSONRPCResponse struct {
 	Error   *JSONRPCError `json:"error,omitempty"`
 }
 
+// JSONRPCMessage is any JSON-RPC 2.0 message received on a stream: a
+// response (ID set) or a notification (ID nil, Method set).
+type JSONRPCMessage struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      *int           `json:"id,omitempty"`
+	Method  string         `json:"method,omitempty"`
+	Params  map[string]any `json:"params,omitempty"`
+	Result  any            `json:"result,omitempty"`
+	Error   *JSONRPCError  `json:"error,omitempty"`
+}
+
 // JSONRPCError is a JSON-RPC 2.0 error object.
 type JSONRPCError struct {
 	Code    int    `json:"code"`
@@ -23,11 +34,21 @@ type JSONRPCError struct {
 	Data    any    `json:"data,omitempty"`
 }
 
+// CodeMethodNotFound is the JSON-RPC error code for an unknown method.
+const CodeMethodNotFound = -32601
+
+// CodeInvalidParams is the JSON-RPC error code for invalid method
+// parameters.
+const CodeInvalidParams = -32602
+
+func (e *JSONRPCError) Error() string { return e.Message }
+
 // InitializeResult is the result of the initialize method.
 type InitializeResult struct {
-	ProtocolVersion string       `json:"protocolVersion"`
-	Capabilities    any          `json:"capabilities"`
-	ServerInfo      *ServerInfo  `json:"serverInfo,omitempty"`
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      *ServerInfo    `json:"serverInfo,omitempty"`
+	Instructions    string         `json:"instructions,omitempty"`
 }
 
 // ServerInfo describes the MCP server.
@@ -40,6 +61,7 @@ type ServerInfo struct {
 type ToolCallParams struct {
 	Name      string         `json:"name"`
 	Arguments map[string]any `json:"arguments,omitempty"`
+	Meta      map[string]any `json:"_meta,omitempty"`
 }
 
 // ToolCallResult is the result of tools/call.
@@ -48,10 +70,22 @@ type ToolCallResult struct {
 	IsError bool           `json:"isError,omitempty"`
 }
 
-// ContentBlock is a content block in a tool result.
+// ContentBlock is a content block in a tool result or prompt message. Type
+// says which fields are set:
+//
+//   - "text": Text
+//   - "image", "audio": Data (base64) and MimeType
+//   - "resource_link": URI, plus optional Name, Description and MimeType
+//   - "resource": Resource, an embedded resource
 type ContentBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text,omitempty"`
+	Type        string            `json:"type"`
+	Text        string            `json:"text,omitempty"`
+	Data        string            `json:"data,omitempty"`
+	MimeType    string            `json:"mimeType,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Resource    *ResourceContents `json:"resource,omitempty"`
 }
 
 // Tool describes an MCP tool.
@@ -0,0 +1,332 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A minimal RFC 6455 WebSocket client: enough to carry JSON-RPC messages as
+// text frames over one long-lived connection.
+
+const (
+	wsGUID        = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsSubprotocol = "mcp"
+
+	// wsMaxMessage bounds a reassembled message, as for SSE events.
+	wsMaxMessage = 16 << 20
+)
+
+// WebSocket opcodes.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// wsCloseError is returned when the peer closes the connection.
+type wsCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *wsCloseError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("websocket closed (%d): %s", e.Code, e.Reason)
+	}
+	return fmt.Sprintf("websocket closed (%d)", e.Code)
+}
+
+// wsConn is a WebSocket connection. Reads must come from one goroutine;
+// writes may come from any.
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	mask bool // client frames are masked, server frames are not
+
+	wmu sync.Mutex
+}
+
+// websocketURL maps an http(s) base URL to the ws(s) URL of path.
+func websocketURL(baseURL, path string) (*url.URL, error) {
+	u, err := url.Parse(baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return nil, fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	return u, nil
+}
+
+// dialWebSocket opens a WebSocket connection to u, sending header with the
+// handshake. On a failed handshake the server's response is returned with
+// its body read into memory, so callers can inspect it.
+func dialWebSocket(ctx context.Context, u *url.URL, header http.Header, o HTTPOptions) (*wsConn, *http.Response, error) {
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "wss" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	if o.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.ConnectTimeout)
+		defer cancel()
+	}
+	dialer := &net.Dialer{KeepAlive: 30 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.Scheme == "wss" {
+		cfg := &tls.Config{}
+		if o.TLS != nil {
+			cfg = o.TLS.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		conn = tlsConn
+	}
+
+	// Bound the handshake by ctx; the connection has no deadline after.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Unix(1, 0)) })
+	ws, resp, err := wsHandshake(conn, u, header)
+	if !stop() || err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, resp, ctx.Err()
+		}
+		return nil, resp, err
+	}
+	return ws, resp, nil
+}
+
+func wsHandshake(conn net.Conn, u *url.URL, header http.Header) (*wsConn, *http.Response, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header.Clone(),
+		Host:       u.Host,
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Protocol", wsSubprotocol)
+	if err := req.Write(conn); err != nil {
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		resp.Body = io.NopCloser(strings.NewReader(string(body)))
+		return nil, resp, fmt.Errorf("websocket handshake: %w", &statusError{Status: resp.StatusCode, Body: string(body)})
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		return nil, resp, errors.New("websocket handshake: invalid upgrade response")
+	}
+	return &wsConn{conn: conn, br: br, mask: true}, resp, nil
+}
+
+// wsAcceptKey computes the Sec-WebSocket-Accept value for a key.
+func wsAcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// ReadMessage returns the next text or binary message, answering pings and
+// reassembling fragments along the way. A close frame from the peer is
+// acknowledged and returned as a *wsCloseError.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			ce := &wsCloseError{Code: 1005}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			_ = c.writeFrame(wsClose, payload[:min(len(payload), 2)])
+			return nil, ce
+		case wsText, wsBinary:
+			if started {
+				return nil, errors.New("websocket: new message before previous one ended")
+			}
+			started = true
+		case wsContinuation:
+			if !started {
+				return nil, errors.New("websocket: continuation without a message")
+			}
+		default:
+			return nil, fmt.Errorf("websocket: unknown opcode %d", op)
+		}
+		if len(msg)+len(payload) > wsMaxMessage {
+			return nil, errors.New("websocket: message too large")
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(c.br, h[:]); err != nil {
+		return
+	}
+	fin = h[0]&0x80 != 0
+	op = h[0] & 0x0F
+	masked := h[1]&0x80 != 0
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > wsMaxMessage {
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, key[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		maskBytes(key, payload)
+	}
+	return fin, op, payload, nil
+}
+
+// WriteText sends a text message in a single frame.
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsText, data)
+}
+
+// Ping sends a ping frame; the peer's pong is discarded by ReadMessage.
+func (c *wsConn) Ping() error {
+	return c.writeFrame(wsPing, nil)
+}
+
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|op)
+	var maskBit byte
+	if c.mask {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if c.mask {
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		buf = append(buf, key[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		maskBytes(key, buf[start:])
+	} else {
+		buf = append(buf, payload...)
+	}
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// Close sends a normal closure frame and closes the connection.
+func (c *wsConn) Close() error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_ = c.writeFrame(wsClose, binary.BigEndian.AppendUint16(nil, 1000))
+	return c.conn.Close()
+}
+
+func maskBytes(key [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= key[i%4]
+	}
+}
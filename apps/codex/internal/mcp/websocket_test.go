@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebsocketURL(t *testing.T) {
+	tests := []struct {
+		base, want string
+	}{
+		{"http://localhost:4000", "ws://localhost:4000/mcp/ws"},
+		{"https://cyfr.example.com", "wss://cyfr.example.com/mcp/ws"},
+		{"https://cyfr.example.com/prefix", "wss://cyfr.example.com/prefix/mcp/ws"},
+	}
+	for _, tt := range tests {
+		u, err := websocketURL(tt.base, WebSocketPath)
+		if err != nil || u.String() != tt.want {
+			t.Errorf("websocketURL(%q) = %v, %v; want %s", tt.base, u, err, tt.want)
+		}
+	}
+	if _, err := websocketURL("ftp://example.com", WebSocketPath); err == nil {
+		t.Error("expected an error for an ftp URL")
+	}
+}
+
+func TestWSConn_Messages(t *testing.T) {
+	a, b := net.Pipe()
+	client := &wsConn{conn: a, br: bufio.NewReader(a), mask: true}
+	server := &wsConn{conn: b, br: bufio.NewReader(b)}
+
+	for _, size := range []int{0, 10, 125, 126, 300, 70000} {
+		msg := bytes.Repeat([]byte("x"), size)
+		go client.WriteText(msg)
+		got, err := server.ReadMessage()
+		if err != nil || !bytes.Equal(got, msg) {
+			t.Fatalf("size %d: got %d bytes, err %v", size, len(got), err)
+		}
+	}
+
+	// A fragmented message with a ping in the middle is reassembled, and
+	// the ping is answered.
+	go func() {
+		b.Write([]byte{wsText, 3})
+		b.Write([]byte("abc"))
+		b.Write([]byte{0x80 | wsPing, 0})
+		b.Write([]byte{0x80 | wsContinuation, 3})
+		b.Write([]byte("def"))
+	}()
+	done := make(chan []byte)
+	go func() {
+		got, _ := client.ReadMessage()
+		done <- got
+	}()
+	_, op, _, err := server.readFrame()
+	if err != nil || op != wsPong {
+		t.Fatalf("expected a pong, got opcode %d, err %v", op, err)
+	}
+	if got := <-done; string(got) != "abcdef" {
+		t.Errorf("reassembled %q, want abcdef", got)
+	}
+
+	go b.Write([]byte{0x80 | wsClose, 2, 0x03, 0xE8})
+	go server.readFrame() // the close acknowledgement
+	_, err = client.ReadMessage()
+	var ce *wsCloseError
+	if !errors.As(err, &ce) || ce.Code != 1000 {
+		t.Errorf("expected close 1000, got %v", err)
+	}
+}
+
+// wsTestServer is a WebSocket MCP endpoint. It hands out session
+// "ws-session", records the session ID presented at each handshake, and
+// passes every message to handle along with the server end of the
+// connection.
+type wsTestServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	handshakes []string
+}
+
+func newWSTestServer(t *testing.T, handle func(s *wsConn, msg JSONRPCMessage)) *wsTestServer {
+	t.Helper()
+	ts := &wsTestServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != WebSocketPath || r.Header.Get("Upgrade") != "websocket" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ws := &wsConn{conn: conn, br: brw.Reader}
+		ts.mu.Lock()
+		ts.handshakes = append(ts.handshakes, r.Header.Get("Mcp-Session-Id"))
+		ts.mu.Unlock()
+
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n" +
+			"Mcp-Session-Id: ws-session\r\n\r\n")
+		brw.Flush()
+		for {
+			data, err := ws.ReadMessage()
+			if err != nil {
+				conn.Close()
+				return
+			}
+			var msg JSONRPCMessage
+			json.Unmarshal(data, &msg)
+			handle(ws, msg)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *wsTestServer) sessions() []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]string(nil), ts.handshakes...)
+}
+
+func wsSend(ws *wsConn, v any) {
+	data, _ := json.Marshal(v)
+	ws.WriteText(data)
+}
+
+// wsToolResult answers a tools/call with {"status":"ok"}.
+func wsToolResult(ws *wsConn, id int) {
+	wsSend(ws, JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: map[string]any{
+		"content": []map[string]any{{"type": "text", "text": `{"status":"ok"}`}},
+	}})
+}
+
+func TestWebSocket_CallTool(t *testing.T) {
+	pingReplies := make(chan JSONRPCMessage, 1)
+	srv := newWSTestServer(t, func(ws *wsConn, msg JSONRPCMessage) {
+		if msg.Method == "" {
+			pingReplies <- msg // the client's answer to our ping
+			return
+		}
+		wsSend(ws, map[string]any{"jsonrpc": "2.0", "id": 99, "method": "ping"})
+		wsSend(ws, map[string]any{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]any{"data": "hi"}})
+		wsToolResult(ws, *msg.ID)
+	})
+
+	c := NewClient(srv.URL)
+	c.UseWebSocket()
+	defer c.Close()
+	var notes []Notification
+	c.OnNotification = func(n Notification) { notes = append(notes, n) }
+
+	result, err := c.CallTool("test-tool", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result["status"] != "ok" {
+		t.Errorf("unexpected result: %v", result)
+	}
+	if len(notes) != 1 || notes[0].Method != "notifications/message" {
+		t.Errorf("unexpected notifications: %v", notes)
+	}
+	if c.SessionID != "ws-session" {
+		t.Errorf("SessionID = %q, want ws-session", c.SessionID)
+	}
+
+	// The second call reuses the connection.
+	if _, err := c.CallTool("test-tool", nil); err != nil {
+		t.Fatalf("second CallTool failed: %v", err)
+	}
+	if n := len(srv.sessions()); n != 1 {
+		t.Errorf("expected 1 handshake, got %d", n)
+	}
+	select {
+	case reply := <-pingReplies:
+		if reply.ID == nil || *reply.ID != 99 || reply.Error != nil {
+			t.Errorf("unexpected ping reply: %+v", reply)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("server ping not answered")
+	}
+}
+
+func TestWebSocket_ReconnectResumesSession(t *testing.T) {
+	srv := newWSTestServer(t, func(ws *wsConn, msg JSONRPCMessage) {
+		wsToolResult(ws, *msg.ID)
+		ws.conn.Close() // drop the connection after every answer
+	})
+
+	c := NewClient(srv.URL)
+	c.sleep = func(time.Duration) {}
+	c.UseWebSocket()
+	defer c.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.CallTool("test-tool", nil); err != nil {
+			t.Fatalf("call %d failed: %v", i+1, err)
+		}
+	}
+	sessions := srv.sessions()
+	if len(sessions) < 2 || sessions[0] != "" || sessions[1] != "ws-session" {
+		t.Errorf("handshake sessions = %q, want a fresh one then ws-session", sessions)
+	}
+}
+
+func TestWebSocket_LostConnectionFailsInFlightRequest(t *testing.T) {
+	srv := newWSTestServer(t, func(ws *wsConn, msg JSONRPCMessage) {
+		ws.conn.Close()
+	})
+
+	c := NewClient(srv.URL)
+	c.sleep = func(time.Duration) {}
+	c.UseWebSocket()
+	defer c.Close()
+
+	_, err := c.CallTool("test-tool", nil)
+	if err == nil || !strings.Contains(err.Error(), "connection lost") {
+		t.Errorf("expected a connection lost error, got %v", err)
+	}
+}
+
+func TestWebSocket_SessionExpiredAtHandshake(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(JSONRPCResponse{JSONRPC: "2.0", Error: &JSONRPCError{Code: -33302, Message: "Session expired"}})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.SessionID = "stale"
+	c.UseWebSocket()
+	defer c.Close()
+
+	if _, err := c.CallTool("test-tool", nil); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+}
+
+func TestWebSocket_Closed(t *testing.T) {
+	c := NewClient("http://127.0.0.1:1")
+	c.UseWebSocket()
+	c.Close()
+	if _, err := c.CallToolCtx(context.Background(), "test-tool", nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+}
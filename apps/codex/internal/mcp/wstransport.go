@@ -0,0 +1,325 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// WebSocketPath is where the server accepts MCP over WebSocket.
+	WebSocketPath = "/mcp/ws"
+
+	// wsKeepAlive is how often the connection is pinged, so idle sessions
+	// aren't dropped by the server or proxies in between.
+	wsKeepAlive = 30 * time.Second
+)
+
+// ErrClientClosed is returned for requests made after Close.
+var ErrClientClosed = fmt.Errorf("client closed")
+
+// UseWebSocket switches the client from HTTP POSTs to a persistent
+// WebSocket connection to BaseURL + WebSocketPath, for interactive sessions
+// where per-request latency matters.
+//
+// The connection is opened on the first request and carries every request
+// after it. Server-initiated notifications arrive on OnNotification and
+// OnProgress at any time, not only during a request, so those callbacks
+// must be safe to call from another goroutine; server pings are answered.
+// If the connection drops, the client reconnects in the background,
+// presenting its session ID so the server can resume the session. Requests
+// that were in flight fail rather than being resent, since a tool call may
+// already have run. TLS settings from SetHTTPOptions apply; HTTP proxies
+// are not used.
+//
+// Call Close when done with the client.
+func (c *Client) UseWebSocket() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ws = &wsTransport{c: c, ctx: ctx, cancel: cancel, pending: map[int]chan wsResponse{}}
+}
+
+// Close releases the client's WebSocket connection, if any. The client
+// can't be used afterwards.
+func (c *Client) Close() error {
+	if c.ws == nil {
+		return nil
+	}
+	return c.ws.close()
+}
+
+// wsTransport multiplexes requests over one WebSocket connection, matching
+// responses to requests by ID.
+type wsTransport struct {
+	c      *Client
+	ctx    context.Context // cancelled by close; ends reconnecting
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	conn    *wsConn
+	done    chan struct{} // closed when conn's reader exits
+	pending map[int]chan wsResponse
+	closed  bool
+}
+
+// wsResponse is a response to a request, with its size on the wire.
+type wsResponse struct {
+	msg  JSONRPCMessage
+	size int
+}
+
+// roundTrip sends a request and waits for its response. Failures to reach
+// the server are retried as for HTTP; a connection lost after the request
+// was sent is not.
+func (t *wsTransport) roundTrip(ctx context.Context, req JSONRPCRequest, body []byte, stats *CallStats) (*JSONRPCResponse, error) {
+	c := t.c
+	for attempt := 1; ; attempt++ {
+		conn, done, resp, err := t.connect(ctx)
+		if err == nil {
+			ch := t.register(req.ID)
+			if err = conn.WriteText(body); err == nil {
+				return t.await(ctx, req.ID, ch, done, stats)
+			}
+			t.unregister(req.ID)
+			t.drop(conn)
+		}
+		if ctx.Err() != nil {
+			return nil, c.contextErr(ctx)
+		}
+		if err == ErrSessionExpired || err == ErrSessionRequired || err == ErrClientClosed {
+			return nil, err
+		}
+
+		var delay time.Duration
+		retry := false
+		if resp != nil {
+			delay, retry = c.Retry.retryDelay(attempt, resp, nil, true)
+		} else if attempt <= c.Retry.MaxRetries {
+			delay, retry = c.Retry.backoff(attempt), true
+		}
+		if !retry {
+			return nil, fmt.Errorf("websocket: %w", err)
+		}
+		c.debugf("    #%d %v, retrying in %s", req.ID, err, delay.Round(time.Millisecond))
+		stats.Retries++
+		if c.wait(ctx, delay) != nil {
+			return nil, c.contextErr(ctx)
+		}
+	}
+}
+
+func (t *wsTransport) await(ctx context.Context, id int, ch chan wsResponse, done chan struct{}, stats *CallStats) (*JSONRPCResponse, error) {
+	select {
+	case r := <-ch:
+		return t.response(r, stats), nil
+	case <-done:
+		// The reader may have delivered the response just before exiting.
+		select {
+		case r := <-ch:
+			return t.response(r, stats), nil
+		default:
+		}
+		t.unregister(id)
+		return nil, fmt.Errorf("websocket connection lost before the response arrived")
+	case <-ctx.Done():
+		t.unregister(id)
+		return nil, t.c.contextErr(ctx)
+	}
+}
+
+func (t *wsTransport) response(r wsResponse, stats *CallStats) *JSONRPCResponse {
+	stats.ResponseBytes = int64(r.size)
+	if r.msg.Error != nil {
+		t.c.debugf("    #%d JSON-RPC error %d: %s", *r.msg.ID, r.msg.Error.Code, r.msg.Error.Message)
+	}
+	return &JSONRPCResponse{JSONRPC: r.msg.JSONRPC, ID: *r.msg.ID, Result: r.msg.Result, Error: r.msg.Error}
+}
+
+// connect returns the open connection, dialing one if needed. On a refused
+// handshake it also returns the server's response.
+func (t *wsTransport) connect(ctx context.Context) (*wsConn, chan struct{}, *http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, nil, nil, ErrClientClosed
+	}
+	if t.conn != nil {
+		return t.conn, t.done, nil, nil
+	}
+
+	c := t.c
+	u, err := websocketURL(c.BaseURL, WebSocketPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	hreq := &http.Request{Header: http.Header{}}
+	c.setSessionHeaders(hreq)
+	c.debugf("--> GET %s (websocket)", WebSocketPath)
+
+	conn, resp, err := dialWebSocket(ctx, u, hreq.Header, c.httpOpts)
+	if err != nil {
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			if serr := sessionError(resp.StatusCode, body); serr != nil {
+				return nil, nil, resp, serr
+			}
+		}
+		return nil, nil, resp, err
+	}
+	if sid := resp.Header.Get("Mcp-Session-Id"); sid != "" {
+		c.SessionID = sid
+	}
+
+	t.conn = conn
+	t.done = make(chan struct{})
+	go t.read(conn, t.done)
+	go t.keepAlive(conn, t.done)
+	return conn, t.done, nil, nil
+}
+
+// read dispatches incoming messages until the connection fails, then
+// starts reconnecting unless the connection was dropped on purpose.
+func (t *wsTransport) read(conn *wsConn, done chan struct{}) {
+	var err error
+	for {
+		var data []byte
+		data, err = conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		t.dispatch(conn, data)
+	}
+
+	t.mu.Lock()
+	lost := t.conn == conn
+	if lost {
+		t.conn = nil
+	}
+	t.mu.Unlock()
+	conn.conn.Close()
+	close(done)
+
+	if lost {
+		t.c.debugf("    websocket connection lost: %v", err)
+		go t.reconnect()
+	}
+}
+
+func (t *wsTransport) dispatch(conn *wsConn, data []byte) {
+	var msg JSONRPCMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.c.debugf("    websocket: ignoring malformed message: %v", err)
+		return
+	}
+	switch {
+	case msg.ID != nil && msg.Method == "":
+		t.mu.Lock()
+		ch, ok := t.pending[*msg.ID]
+		delete(t.pending, *msg.ID)
+		t.mu.Unlock()
+		if ok {
+			ch <- wsResponse{msg: msg, size: len(data)}
+		}
+	case msg.ID != nil:
+		// A request from the server. Only ping is part of what a client
+		// must answer; refuse anything else.
+		reply := JSONRPCResponse{JSONRPC: "2.0", ID: *msg.ID}
+		if msg.Method == "ping" {
+			reply.Result = map[string]any{}
+		} else {
+			reply.Error = &JSONRPCError{Code: CodeMethodNotFound, Message: "Method not found: " + msg.Method}
+		}
+		if out, err := json.Marshal(reply); err == nil {
+			_ = conn.WriteText(out)
+		}
+	case msg.Method != "":
+		t.c.notify(Notification{Method: msg.Method, Params: msg.Params})
+	}
+}
+
+// keepAlive pings the connection while it's open.
+func (t *wsTransport) keepAlive(conn *wsConn, done chan struct{}) {
+	ticker := time.NewTicker(wsKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = conn.Ping()
+		case <-done:
+			return
+		}
+	}
+}
+
+// reconnect redials after a lost connection, backing off between
+// attempts, until it succeeds, the session is gone, or the client closes.
+func (t *wsTransport) reconnect() {
+	for attempt := 1; ; attempt++ {
+		if t.c.wait(t.ctx, t.c.Retry.backoff(attempt)) != nil {
+			return
+		}
+		_, _, resp, err := t.connect(t.ctx)
+		if err == nil {
+			t.c.debugf("    websocket reconnected")
+			return
+		}
+		if err == ErrSessionExpired || err == ErrSessionRequired || err == ErrClientClosed {
+			return
+		}
+		if resp != nil {
+			if _, retry := t.c.Retry.retryDelay(1, resp, nil, true); !retry {
+				return
+			}
+		}
+	}
+}
+
+func (t *wsTransport) register(id int) chan wsResponse {
+	ch := make(chan wsResponse, 1)
+	t.mu.Lock()
+	t.pending[id] = ch
+	t.mu.Unlock()
+	return ch
+}
+
+func (t *wsTransport) unregister(id int) {
+	t.mu.Lock()
+	delete(t.pending, id)
+	t.mu.Unlock()
+}
+
+// drop closes conn without reconnecting.
+func (t *wsTransport) drop(conn *wsConn) {
+	t.mu.Lock()
+	if t.conn == conn {
+		t.conn = nil
+	}
+	t.mu.Unlock()
+	conn.Close()
+}
+
+// reset drops the current connection, so the next request starts afresh.
+func (t *wsTransport) reset() {
+	t.mu.Lock()
+	conn := t.conn
+	t.mu.Unlock()
+	if conn != nil {
+		t.drop(conn)
+	}
+}
+
+func (t *wsTransport) close() error {
+	t.mu.Lock()
+	t.closed = true
+	conn := t.conn
+	t.conn = nil
+	t.mu.Unlock()
+	t.cancel()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
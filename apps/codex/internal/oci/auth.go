@@ -0,0 +1,201 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Error is an error response from a registry.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if e.Code != "" {
+		msg = e.Code + ": " + msg
+	}
+	return fmt.Sprintf("registry returned HTTP %d: %s", e.Status, msg)
+}
+
+// Unwrap makes 404s match ErrNotFound.
+func (e *Error) Unwrap() error {
+	if e.Status == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// do sends the request built by newReq, authenticating with a bearer
+// token for scope or basic auth when the registry asks for it, and returns
+// the response if it succeeded. newReq is called again for the retry, so
+// request bodies can be resent.
+func (c *Client) do(ctx context.Context, host, scope string, newReq func() (*http.Request, error)) (*http.Response, error) {
+	req, err := newReq()
+	if err != nil {
+		return nil, err
+	}
+	c.authorize(req, host, scope)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := c.authenticate(ctx, host, scope, challenge); err != nil {
+			return nil, err
+		}
+		if req, err = newReq(); err != nil {
+			return nil, err
+		}
+		c.authorize(req, host, scope)
+		if resp, err = c.HTTPClient.Do(req); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode >= 300 {
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// authorize adds the credentials obtained for host and scope, if any.
+func (c *Client) authorize(req *http.Request, host, scope string) {
+	c.mu.Lock()
+	token := c.tokens[host+" "+scope]
+	basic := c.tokens[host+" basic"]
+	c.mu.Unlock()
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case basic != "" && c.Username != "":
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+}
+
+// authenticate answers a WWW-Authenticate challenge: for Bearer it fetches
+// a token from the realm, for Basic it notes that the registry wants the
+// configured credentials.
+func (c *Client) authenticate(ctx context.Context, host, scope, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if c.Username == "" {
+			return &Error{Status: http.StatusUnauthorized, Message: "registry requires credentials (set CYFR_REGISTRY_USERNAME and CYFR_REGISTRY_PASSWORD)"}
+		}
+		c.mu.Lock()
+		c.tokens[host+" basic"] = "yes"
+		c.mu.Unlock()
+		return nil
+	case "bearer":
+	default:
+		return &Error{Status: http.StatusUnauthorized, Message: "unsupported authentication challenge " + challenge}
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("registry sent an invalid token realm %q", params["realm"])
+	}
+	q := realm.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	q.Set("scope", scope)
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get registry token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := responseError(resp)
+		return fmt.Errorf("get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	var tr struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return fmt.Errorf("get registry token: %w", err)
+	}
+	token := tr.Token
+	if token == "" {
+		token = tr.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("get registry token: no token in response")
+	}
+	c.mu.Lock()
+	c.tokens[host+" "+scope] = token
+	c.mu.Unlock()
+	return nil
+}
+
+// parseChallenge splits a WWW-Authenticate header into its lowercased
+// scheme and parameters.
+func parseChallenge(h string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(h), " ")
+	params := map[string]string{}
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		key, after, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(after, `"`) {
+			end := strings.IndexByte(after[1:], '"')
+			if end < 0 {
+				value, rest = after[1:], ""
+			} else {
+				value, rest = after[1:end+1], after[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(after, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return strings.ToLower(scheme), params
+}
+
+// responseError builds an *Error from a failed response, using the
+// registry's error body when it has one.
+func responseError(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	e := &Error{Status: resp.StatusCode}
+	var parsed struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && len(parsed.Errors) > 0 {
+		e.Code, e.Message = parsed.Errors[0].Code, parsed.Errors[0].Message
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		e.Message += " (not found)"
+	}
+	return e
+}
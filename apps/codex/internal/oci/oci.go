@@ -0,0 +1,379 @@
+// Package oci is a minimal client for OCI distribution registries
+// (ghcr.io, Harbor, a registry:2 mirror, ...), enough to pull, push and
+// list the tags of CYFR components stored there as OCI artifacts.
+//
+// A component is stored as an image manifest with the empty config and an
+// application/wasm layer holding the WASM binary, followed by a layer with
+// its signature bundle if it is signed. The component type is recorded in
+// the manifest annotations.
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Media types and annotations of CYFR component artifacts.
+const (
+	ManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	ArtifactType         = "application/vnd.cyfr.component.v1"
+	LayerMediaType       = "application/wasm"
+	EmptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+	// SignatureMediaType is the layer of a cosign signature bundle of the
+	// WASM binary.
+	SignatureMediaType = "application/vnd.cyfr.component.signature.v1+json"
+
+	// TypeAnnotation records the component type (catalyst, reagent,
+	// formula) on the manifest.
+	TypeAnnotation = "dev.cyfr.component.type"
+	// VersionAnnotation records the component version, so artifacts
+	// pulled by a moving tag such as "latest" can be placed correctly.
+	VersionAnnotation = "org.opencontainers.image.version"
+	titleAnnotation   = "org.opencontainers.image.title"
+)
+
+// maxArtifactSize bounds how much of a blob is read, as a guard against a
+// misbehaving registry.
+const maxArtifactSize = 512 << 20
+
+// emptyConfig is the content of the empty config blob.
+var emptyConfig = []byte("{}")
+
+// ErrNotFound is returned when the registry doesn't have the requested
+// repository, tag or blob.
+var ErrNotFound = errors.New("not found")
+
+// Descriptor points at a blob.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Artifact is a pulled component.
+type Artifact struct {
+	Manifest Manifest
+	// ManifestDigest is the digest of the manifest as served.
+	ManifestDigest string
+	// Digest is the digest of Data, the WASM binary.
+	Digest string
+	Data   []byte
+	// Signature is the cosign signature bundle of Data, or nil if the
+	// artifact isn't signed.
+	Signature []byte
+}
+
+// Type returns the component type recorded on the artifact, or "".
+func (a *Artifact) Type() string {
+	return a.Manifest.Annotations[TypeAnnotation]
+}
+
+// Version returns the component version recorded on the artifact, or "".
+func (a *Artifact) Version() string {
+	return a.Manifest.Annotations[VersionAnnotation]
+}
+
+// Client talks to OCI registries. The zero value is not usable; create one
+// with NewClient.
+type Client struct {
+	HTTPClient *http.Client
+	// Username and Password, if set, are sent to registries that ask for
+	// basic auth and to token services when requesting bearer tokens.
+	Username, Password string
+
+	mu     sync.Mutex
+	tokens map[string]string // bearer token per host and scope
+}
+
+// NewClient returns a client using http.DefaultClient.
+func NewClient() *Client {
+	return &Client{HTTPClient: http.DefaultClient, tokens: map[string]string{}}
+}
+
+// Digest returns the sha256 digest of data in "sha256:<hex>" form.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Pull fetches the component tagged (or with manifest digest) reference
+// from repo on host, checking the WASM binary against its digest.
+func (c *Client) Pull(ctx context.Context, host, repo, reference string) (*Artifact, error) {
+	scope := "repository:" + repo + ":pull"
+	resp, err := c.do(ctx, host, scope, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", baseURL(host)+"/v2/"+repo+"/manifests/"+reference, nil)
+		if err == nil {
+			req.Header.Set("Accept", ManifestMediaType)
+		}
+		return req, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch manifest: %w", err)
+	}
+	body, err := readAll(resp)
+	if err != nil {
+		return nil, fmt.Errorf("fetch manifest: %w", err)
+	}
+	a := &Artifact{ManifestDigest: Digest(body)}
+	if strings.HasPrefix(reference, "sha256:") && a.ManifestDigest != reference {
+		return nil, fmt.Errorf("manifest digest mismatch: got %s, want %s", a.ManifestDigest, reference)
+	}
+	if err := json.Unmarshal(body, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	layer, err := wasmLayer(a.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	if a.Data, err = c.fetchBlob(ctx, host, repo, layer.Digest); err != nil {
+		return nil, fmt.Errorf("fetch artifact: %w", err)
+	}
+	a.Digest = Digest(a.Data)
+	if a.Digest != layer.Digest {
+		return nil, fmt.Errorf("artifact digest mismatch: got %s, manifest says %s", a.Digest, layer.Digest)
+	}
+	for _, l := range a.Manifest.Layers {
+		if l.MediaType != SignatureMediaType {
+			continue
+		}
+		if a.Signature, err = c.fetchBlob(ctx, host, repo, l.Digest); err != nil {
+			return nil, fmt.Errorf("fetch signature: %w", err)
+		}
+		if Digest(a.Signature) != l.Digest {
+			return nil, fmt.Errorf("signature digest mismatch: manifest says %s", l.Digest)
+		}
+	}
+	return a, nil
+}
+
+// fetchBlob downloads the blob digest from repo on host.
+func (c *Client) fetchBlob(ctx context.Context, host, repo, digest string) ([]byte, error) {
+	resp, err := c.do(ctx, host, "repository:"+repo+":pull", func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", baseURL(host)+"/v2/"+repo+"/blobs/"+digest, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return readAll(resp)
+}
+
+// wasmLayer finds the WASM binary among a manifest's layers.
+func wasmLayer(m Manifest) (Descriptor, error) {
+	for _, l := range m.Layers {
+		if l.MediaType == LayerMediaType {
+			return l, nil
+		}
+	}
+	if len(m.Layers) == 1 {
+		return m.Layers[0], nil
+	}
+	return Descriptor{}, fmt.Errorf("manifest has no %s layer; not a CYFR component", LayerMediaType)
+}
+
+// Push uploads a WASM binary as a component artifact to repo on host and
+// tags it, with its signature bundle unless signature is nil. It returns
+// the manifest digest.
+func (c *Client) Push(ctx context.Context, host, repo, tag string, data []byte, componentType string, signature []byte) (string, error) {
+	config := Descriptor{MediaType: EmptyConfigMediaType, Digest: Digest(emptyConfig), Size: int64(len(emptyConfig))}
+	layer := Descriptor{
+		MediaType:   LayerMediaType,
+		Digest:      Digest(data),
+		Size:        int64(len(data)),
+		Annotations: map[string]string{titleAnnotation: componentType + ".wasm"},
+	}
+	type blob struct {
+		d    Descriptor
+		data []byte
+	}
+	blobs := []blob{{config, emptyConfig}, {layer, data}}
+	layers := []Descriptor{layer}
+	if signature != nil {
+		sig := Descriptor{
+			MediaType:   SignatureMediaType,
+			Digest:      Digest(signature),
+			Size:        int64(len(signature)),
+			Annotations: map[string]string{titleAnnotation: componentType + ".wasm.bundle"},
+		}
+		blobs = append(blobs, blob{sig, signature})
+		layers = append(layers, sig)
+	}
+	for _, blob := range blobs {
+		if err := c.pushBlob(ctx, host, repo, blob.d.Digest, blob.data); err != nil {
+			return "", err
+		}
+	}
+
+	annotations := map[string]string{TypeAnnotation: componentType}
+	if tag != "latest" {
+		annotations[VersionAnnotation] = tag
+	}
+	manifest, err := json.Marshal(Manifest{
+		SchemaVersion: 2,
+		MediaType:     ManifestMediaType,
+		ArtifactType:  ArtifactType,
+		Config:        config,
+		Layers:        layers,
+		Annotations:   annotations,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(ctx, host, pushScope(repo), func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "PUT", baseURL(host)+"/v2/"+repo+"/manifests/"+tag, bytes.NewReader(manifest))
+		if err == nil {
+			req.Header.Set("Content-Type", ManifestMediaType)
+		}
+		return req, err
+	})
+	if err != nil {
+		return "", fmt.Errorf("push manifest: %w", err)
+	}
+	resp.Body.Close()
+	return Digest(manifest), nil
+}
+
+// pushBlob uploads a blob unless the registry already has it.
+func (c *Client) pushBlob(ctx context.Context, host, repo, digest string, data []byte) error {
+	scope := pushScope(repo)
+	resp, err := c.do(ctx, host, scope, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "HEAD", baseURL(host)+"/v2/"+repo+"/blobs/"+digest, nil)
+	})
+	if err == nil {
+		resp.Body.Close()
+		return nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("check blob: %w", err)
+	}
+
+	resp, err = c.do(ctx, host, scope, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "POST", baseURL(host)+"/v2/"+repo+"/blobs/uploads/", nil)
+	})
+	if err != nil {
+		return fmt.Errorf("start upload: %w", err)
+	}
+	resp.Body.Close()
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("start upload: registry gave no upload location")
+	}
+	q := location.Query()
+	q.Set("digest", digest)
+	location.RawQuery = q.Encode()
+
+	resp, err = c.do(ctx, host, scope, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "PUT", location.String(), bytes.NewReader(data))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/octet-stream")
+		}
+		return req, err
+	})
+	if err != nil {
+		return fmt.Errorf("upload blob: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Tags lists the tags of repo on host.
+func (c *Client) Tags(ctx context.Context, host, repo string) ([]string, error) {
+	var tags []string
+	next := baseURL(host) + "/v2/" + repo + "/tags/list"
+	for next != "" {
+		u := next
+		resp, err := c.do(ctx, host, "repository:"+repo+":pull", func() (*http.Request, error) {
+			return http.NewRequestWithContext(ctx, "GET", u, nil)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list tags: %w", err)
+		}
+		body, err := readAll(resp)
+		if err != nil {
+			return nil, fmt.Errorf("list tags: %w", err)
+		}
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("list tags: %w", err)
+		}
+		tags = append(tags, page.Tags...)
+		next = nextLink(resp, u)
+	}
+	return tags, nil
+}
+
+// nextLink returns the URL of the next page from a Link header, resolved
+// against the current URL, or "".
+func nextLink(resp *http.Response, current string) string {
+	link := resp.Header.Get("Link")
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	start, end := strings.IndexByte(link, '<'), strings.IndexByte(link, '>')
+	if start < 0 || end < start {
+		return ""
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	u, err := base.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+func pushScope(repo string) string {
+	return "repository:" + repo + ":pull,push"
+}
+
+// baseURL returns the registry's URL. Registries on the local machine are
+// reached over plain HTTP, as a development registry:2 usually is; all
+// others over HTTPS.
+func baseURL(host string) string {
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	if name == "localhost" || name == "127.0.0.1" || name == "::1" {
+		return "http://" + host
+	}
+	return "https://" + host
+}
+
+func readAll(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArtifactSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArtifactSize {
+		return nil, fmt.Errorf("response larger than %d MiB", maxArtifactSize>>20)
+	}
+	return data, nil
+}
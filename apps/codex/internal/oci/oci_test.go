@@ -0,0 +1,241 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry is an in-memory OCI registry. If token is set it requires
+// that bearer token, issued by its /token endpoint.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte // by "repo:tag" and "repo@digest"
+	tags      map[string][]string
+	token     string
+	scopes    []string
+}
+
+func newFakeRegistry(t *testing.T, token string) (*fakeRegistry, string) {
+	t.Helper()
+	reg := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}, tags: map[string][]string{}, token: token}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+		if r.URL.Path == "/token" {
+			reg.scopes = append(reg.scopes, r.URL.Query().Get("scope"))
+			fmt.Fprintf(w, `{"token":%q}`, reg.token)
+			return
+		}
+		if reg.token != "" && r.Header.Get("Authorization") != "Bearer "+reg.token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.serve(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return reg, strings.TrimPrefix(srv.URL, "http://")
+}
+
+func (reg *fakeRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`)
+	}
+	switch {
+	case strings.HasSuffix(path, "/tags/list"):
+		repo := strings.TrimSuffix(path, "/tags/list")
+		fmt.Fprintf(w, `{"name":%q,"tags":["%s"]}`, repo, strings.Join(reg.tags[repo], `","`))
+	case strings.HasSuffix(path, "/blobs/uploads/") && r.Method == "POST":
+		w.Header().Set("Location", "/upload/1")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/"):
+		digest := path[strings.LastIndex(path, "/")+1:]
+		data, ok := reg.blobs[digest]
+		if !ok {
+			notFound()
+			return
+		}
+		w.Write(data)
+	case strings.Contains(path, "/manifests/"):
+		repo, reference, _ := strings.Cut(path, "/manifests/")
+		if r.Method == "PUT" {
+			data, _ := io.ReadAll(r.Body)
+			reg.manifests[repo+":"+reference] = data
+			reg.manifests[repo+"@"+Digest(data)] = data
+			reg.tags[repo] = append(reg.tags[repo], reference)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		data, ok := reg.manifests[repo+":"+reference]
+		if !ok {
+			data, ok = reg.manifests[repo+"@"+reference]
+		}
+		if !ok {
+			notFound()
+			return
+		}
+		w.Header().Set("Content-Type", ManifestMediaType)
+		w.Write(data)
+	case r.URL.Path == "/upload/1" && r.Method == "PUT":
+		data, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if Digest(data) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reg.blobs[digest] = data
+		w.WriteHeader(http.StatusCreated)
+	default:
+		notFound()
+	}
+}
+
+var wasm = []byte("\x00asm\x01\x00\x00\x00")
+
+func TestPushPull(t *testing.T) {
+	_, host := newFakeRegistry(t, "")
+	c := NewClient()
+	ctx := context.Background()
+
+	manifestDigest, err := c.Push(ctx, host, "acme/sentiment", "1.0.0", wasm, "catalyst", nil)
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	a, err := c.Pull(ctx, host, "acme/sentiment", "1.0.0")
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if string(a.Data) != string(wasm) {
+		t.Errorf("Data = %q, want %q", a.Data, wasm)
+	}
+	if a.Digest != Digest(wasm) {
+		t.Errorf("Digest = %s, want %s", a.Digest, Digest(wasm))
+	}
+	if a.ManifestDigest != manifestDigest {
+		t.Errorf("ManifestDigest = %s, want %s", a.ManifestDigest, manifestDigest)
+	}
+	if a.Type() != "catalyst" || a.Version() != "1.0.0" {
+		t.Errorf("Type, Version = %q, %q; want catalyst, 1.0.0", a.Type(), a.Version())
+	}
+	if a.Manifest.ArtifactType != ArtifactType {
+		t.Errorf("ArtifactType = %q, want %q", a.Manifest.ArtifactType, ArtifactType)
+	}
+
+	byDigest, err := c.Pull(ctx, host, "acme/sentiment", manifestDigest)
+	if err != nil {
+		t.Fatalf("Pull by digest: %v", err)
+	}
+	if byDigest.Digest != a.Digest {
+		t.Errorf("Pull by digest: Digest = %s, want %s", byDigest.Digest, a.Digest)
+	}
+
+	tags, err := c.Tags(ctx, host, "acme/sentiment")
+	if err != nil {
+		t.Fatalf("Tags: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"1.0.0"}) {
+		t.Errorf("Tags = %v, want [1.0.0]", tags)
+	}
+}
+
+func TestPushPullSigned(t *testing.T) {
+	_, host := newFakeRegistry(t, "")
+	c := NewClient()
+	ctx := context.Background()
+	bundle := []byte(`{"base64Signature":"c2ln"}`)
+
+	if _, err := c.Push(ctx, host, "acme/sentiment", "1.0.0", wasm, "catalyst", bundle); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	a, err := c.Pull(ctx, host, "acme/sentiment", "1.0.0")
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if string(a.Data) != string(wasm) || string(a.Signature) != string(bundle) {
+		t.Errorf("Data, Signature = %q, %q; want %q, %q", a.Data, a.Signature, wasm, bundle)
+	}
+	if len(a.Manifest.Layers) != 2 || a.Manifest.Layers[1].MediaType != SignatureMediaType {
+		t.Errorf("layers = %+v, want the WASM binary and the signature", a.Manifest.Layers)
+	}
+}
+
+func TestPull_NotFound(t *testing.T) {
+	_, host := newFakeRegistry(t, "")
+	_, err := NewClient().Pull(context.Background(), host, "acme/missing", "1.0.0")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "not found") || !strings.Contains(err.Error(), "manifest unknown") {
+		t.Errorf("err = %q, want the registry's message and \"not found\"", err)
+	}
+}
+
+func TestPull_DigestMismatch(t *testing.T) {
+	reg, host := newFakeRegistry(t, "")
+	c := NewClient()
+	ctx := context.Background()
+	if _, err := c.Push(ctx, host, "acme/sentiment", "1.0.0", wasm, "catalyst", nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	reg.blobs[Digest(wasm)] = []byte("tampered")
+
+	_, err := c.Pull(ctx, host, "acme/sentiment", "1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Fatalf("err = %v, want a digest mismatch", err)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	reg, host := newFakeRegistry(t, "s3cret")
+	c := NewClient()
+	ctx := context.Background()
+
+	if _, err := c.Push(ctx, host, "acme/sentiment", "1.0.0", wasm, "reagent", nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if _, err := c.Pull(ctx, host, "acme/sentiment", "1.0.0"); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	want := []string{"repository:acme/sentiment:pull,push", "repository:acme/sentiment:pull"}
+	if !reflect.DeepEqual(reg.scopes, want) {
+		t.Errorf("token scopes = %v, want %v (one token per scope)", reg.scopes, want)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:acme/x:pull"`)
+	if scheme != "bearer" {
+		t.Errorf("scheme = %q, want bearer", scheme)
+	}
+	want := map[string]string{"realm": "https://ghcr.io/token", "service": "ghcr.io", "scope": "repository:acme/x:pull"}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want %v", params, want)
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io":        "https://ghcr.io",
+		"registry.corp":  "https://registry.corp",
+		"localhost:5000": "http://localhost:5000",
+		"127.0.0.1:5000": "http://127.0.0.1:5000",
+	}
+	for host, want := range tests {
+		if got := baseURL(host); got != want {
+			t.Errorf("baseURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"slices"
+	"strings"
+)
+
+// ANSI styles. Every code has two digits, so cells styled differently
+// carry the same number of escape bytes and tables stay aligned.
+const (
+	stylePlain = "00"
+	styleBold  = "01"
+	styleDim   = "02"
+	styleRed   = "31"
+	styleGreen = "32"
+	styleAmber = "33"
+	styleCyan  = "36"
+)
+
+// style wraps s in an ANSI style if Color is on.
+func style(code, s string) string {
+	if !Color {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// Highlight marks a line, e.g. one that changed since the last refresh,
+// in reverse video if Color is on. Styles within the line are kept.
+func Highlight(line string) string {
+	if !Color {
+		return line
+	}
+	return "\x1b[7m" + strings.ReplaceAll(line, "\x1b[0m", "\x1b[0;7m") + "\x1b[0m"
+}
+
+// Status words by the color they are shown in.
+var (
+	goodStatuses = []string{"ok", "completed", "complete", "success", "succeeded", "healthy", "ready", "active", "yes", "true", "valid"}
+	warnStatuses = []string{"pending", "running", "queued", "starting", "cancelled", "canceled", "degraded", "warning", "skipped", "unknown"}
+	badStatuses  = []string{"failed", "failure", "error", "down", "no", "false", "timeout", "timed_out", "killed", "unreachable", "invalid", "revoked", "expired"}
+)
+
+// statusStyle returns the color for a status value: green when all is
+// well, amber for in-between states and red for failures.
+func statusStyle(s string) string {
+	word := strings.ToLower(strings.TrimSpace(s))
+	if word, _, ok := strings.Cut(word, " "); ok {
+		// e.g. "no (connection refused)"
+		return statusStyle(word)
+	}
+	switch {
+	case slices.Contains(goodStatuses, word):
+		return styleGreen
+	case slices.Contains(warnStatuses, word):
+		return styleAmber
+	case slices.Contains(badStatuses, word):
+		return styleRed
+	}
+	return stylePlain
+}
+
+// statusColumn reports whether a table column holds statuses.
+func statusColumn(header string) bool {
+	switch header {
+	case "STATUS", "STATE", "HEALTHY", "REACHABLE", "RESULT", "VALID":
+		return true
+	}
+	return false
+}
+
+// secondaryColumn reports whether a table column holds detail that is
+// shown dimmed, such as timestamps and durations.
+func secondaryColumn(header string) bool {
+	for _, s := range []string{"_AT", "STARTED", "CREATED", "UPDATED", "TIMESTAMP", "DURATION", "LATENCY"} {
+		if strings.Contains(header, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// cellStyle returns the style of a table cell in the column header.
+func cellStyle(header, value string) string {
+	switch {
+	case statusColumn(header):
+		return statusStyle(value)
+	case secondaryColumn(header):
+		return styleDim
+	}
+	return stylePlain
+}
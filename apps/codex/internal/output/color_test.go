@@ -0,0 +1,78 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatusStyle(t *testing.T) {
+	tests := map[string]string{
+		"completed":               styleGreen,
+		"OK":                      styleGreen,
+		"running":                 styleAmber,
+		"failed":                  styleRed,
+		"no (connection refused)": styleRed,
+		"exec_abc123":             stylePlain,
+		"":                        stylePlain,
+	}
+	for in, want := range tests {
+		if got := statusStyle(in); got != want {
+			t.Errorf("statusStyle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTable_ColorKeepsAlignment(t *testing.T) {
+	defer func() { Color = false }()
+	rows := []map[string]string{
+		{"ID": "exec_1", "STATUS": "completed", "STARTED": "2026-01-01"},
+		{"ID": "exec_long_id", "STATUS": "failed", "STARTED": "2026-01-02"},
+	}
+	headers := []string{"ID", "STATUS", "STARTED"}
+	plain := captureStdout(t, func() { Table(headers, rows) })
+	Color = true
+	colored := captureStdout(t, func() { Table(headers, rows) })
+
+	if !strings.Contains(colored, "\x1b[32mcompleted\x1b[0m") || !strings.Contains(colored, "\x1b[31mfailed\x1b[0m") {
+		t.Errorf("statuses not colored:\n%q", colored)
+	}
+	if !strings.Contains(colored, "\x1b[01mSTATUS\x1b[0m") {
+		t.Errorf("headers not bold:\n%q", colored)
+	}
+	if stripped := stripANSI(colored); stripped != plain {
+		t.Errorf("colors changed the layout:\n%s\nwant:\n%s", stripped, plain)
+	}
+}
+
+func TestKeyValue_ColorsStatus(t *testing.T) {
+	defer func() { Color = false }()
+	Color = true
+	out := captureStdout(t, func() { KeyValue(map[string]any{"status": "failed", "name": "x"}) })
+	if !strings.Contains(out, "\x1b[31mfailed\x1b[0m") || strings.Contains(out, "\x1b[00mx") {
+		t.Errorf("unexpected output:\n%q", out)
+	}
+}
+
+// stripANSI removes ANSI style sequences from s.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			i += strings.IndexByte(s[i:], 'm')
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func TestHighlight(t *testing.T) {
+	defer func() { Color = false }()
+	if got := Highlight("new line"); got != "new line" {
+		t.Errorf("Highlight without color = %q", got)
+	}
+	Color = true
+	if got, want := Highlight("a "+style(styleGreen, "ok")+" b"), "\x1b[7ma \x1b[32mok\x1b[0;7m b\x1b[0m"; got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tsvReplacer replaces the characters TSV can't hold in a value.
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// delimitedTable prints a table as CSV or, for format "tsv", tab-separated
+// values: a header line, then one line per row, without styling. CSV
+// quotes values as needed; TSV has no quoting, so tabs and line breaks in
+// values become spaces.
+func delimitedTable(headers []string, rows []map[string]string, format string) {
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, headers)
+	for _, row := range rows {
+		record := make([]string, len(headers))
+		for i, h := range headers {
+			record[i] = row[h]
+		}
+		records = append(records, record)
+	}
+
+	if format == "tsv" {
+		for _, record := range records {
+			for i, v := range record {
+				record[i] = tsvReplacer.Replace(v)
+			}
+			fmt.Println(strings.Join(record, "\t"))
+		}
+		return
+	}
+	w := csv.NewWriter(os.Stdout)
+	w.WriteAll(records)
+}
@@ -0,0 +1,24 @@
+package output
+
+import "testing"
+
+func TestTable_Delimited(t *testing.T) {
+	defer func() { TableFormat = "" }()
+	headers := []string{"NAME", "DESCRIPTION"}
+	rows := []map[string]string{
+		{"NAME": "hello", "DESCRIPTION": `Says "hello", twice`},
+		{"NAME": "tabs", "DESCRIPTION": "a\tb\nc"},
+	}
+
+	TableFormat = "csv"
+	want := "NAME,DESCRIPTION\nhello,\"Says \"\"hello\"\", twice\"\ntabs,\"a\tb\nc\"\n"
+	if got := captureStdout(t, func() { Table(headers, rows) }); got != want {
+		t.Errorf("csv:\n%q\nwant:\n%q", got, want)
+	}
+
+	TableFormat = "tsv"
+	want = "NAME\tDESCRIPTION\nhello\tSays \"hello\", twice\ntabs\ta b c\n"
+	if got := captureStdout(t, func() { Table(headers, rows) }); got != want {
+		t.Errorf("tsv:\n%q\nwant:\n%q", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change.
+const diffContext = 3
+
+// Diff prints a unified diff from oldText to newText, labelled oldName and
+// newName, with removed lines in red and added lines in green if Color is
+// on. It prints nothing and returns false if the texts are the same.
+func Diff(oldName, newName, oldText, newText string) bool {
+	lines := unifiedDiff(oldName, newName, oldText, newText)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return len(lines) > 0
+}
+
+// diffOp is a line of a diff: kept (' '), removed ('-') or added ('+').
+// The line keeps its newline, if it has one.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the lines of a unified diff from oldText to newText,
+// styled, or none if they are the same.
+func unifiedDiff(oldName, newName, oldText, newText string) []string {
+	if oldText == newText {
+		return nil
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	// oldLine[i] and newLine[i] count the lines of each side before ops[i].
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.kind != '+' {
+			oldLine[i+1]++
+		}
+		if op.kind != '-' {
+			newLine[i+1]++
+		}
+	}
+
+	out := []string{style(styleBold, "--- "+oldName), style(styleBold, "+++ "+newName)}
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// A hunk runs until more unchanged lines than the context on both
+		// sides of them would fill.
+		start, end := max(i-diffContext, 0), i+1
+		for j := end; j < len(ops) && j-end <= 2*diffContext; j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			}
+		}
+		stop := min(end+diffContext, len(ops))
+
+		header := fmt.Sprintf("@@ -%s +%s @@",
+			hunkRange(oldLine[start], oldLine[stop]-oldLine[start]),
+			hunkRange(newLine[start], newLine[stop]-newLine[start]))
+		out = append(out, style(styleCyan, header))
+		for _, op := range ops[start:stop] {
+			line := string(op.kind) + strings.TrimSuffix(op.line, "\n")
+			switch op.kind {
+			case '-':
+				line = style(styleRed, line)
+			case '+':
+				line = style(styleGreen, line)
+			}
+			out = append(out, line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out = append(out, `\ No newline at end of file`)
+			}
+		}
+		i = stop
+	}
+	return out
+}
+
+// hunkRange formats the range of a hunk header from the number of lines
+// before it and its length, as diff does: the start is 1-based, or the
+// line before an empty range, and a length of 1 is left out.
+func hunkRange(before, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, n)
+}
+
+// splitLines splits s after each newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit from a to b, with removals before
+// additions where they replace lines. The common prefix and suffix are
+// kept as they are, so only the changed middle is compared line by line.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of am[i:]
+	// and bm[j:].
+	lcs := make([][]int, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			ops = append(ops, diffOp{' ', am[i]})
+			i++
+			j++
+		case i < len(am) && (j == len(bm) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', am[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', bm[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
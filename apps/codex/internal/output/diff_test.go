@@ -0,0 +1,74 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "same",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "replaced line",
+			old:  "a\nb\nc\n",
+			new:  "a\nB\nc\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve",
+		},
+		{
+			name: "close changes share a hunk",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\neight\n",
+			want: "--- old\n+++ new\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight",
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  "a\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1 @@\n+a",
+		},
+		{
+			name: "missing newline",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(unifiedDiff("old", "new", tt.old, tt.new), "\n")
+			if got != tt.want {
+				t.Errorf("diff =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Color(t *testing.T) {
+	defer func() { Color = false }()
+	Color = true
+	out := captureStdout(t, func() {
+		if !Diff("old", "new", "a\nb\n", "a\nc\n") {
+			t.Error("Diff reported no change")
+		}
+	})
+	for _, want := range []string{"\x1b[01m--- old\x1b[0m", "\x1b[36m@@ -1,2 +1,2 @@\x1b[0m", "\x1b[31m-b\x1b[0m", "\x1b[32m+c\x1b[0m", "\n a\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%q", want, out)
+		}
+	}
+}
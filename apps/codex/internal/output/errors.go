@@ -0,0 +1,143 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error codes of JSON error output. They are part of the CLI's interface:
+// scripts may rely on them, so existing codes must not change meaning.
+const (
+	CodeError            = "ERROR"             // anything without a more specific code
+	CodeInvalidArgument  = "INVALID_ARGUMENT"  // bad flags, arguments or input
+	CodeNotFound         = "NOT_FOUND"         // a context, component or other object doesn't exist
+	CodeNotLoggedIn      = "NOT_LOGGED_IN"     // the server needs a session and there is none
+	CodeSessionExpired   = "SESSION_EXPIRED"   // the session has expired; log in again
+	CodeTokenExpired     = "TOKEN_EXPIRED"     // the OAuth token expired and couldn't be refreshed
+	CodeUnsupported      = "UNSUPPORTED"       // the server doesn't offer the tool or feature
+	CodeUnreachable      = "UNREACHABLE"       // the server couldn't be reached
+	CodeTimeout          = "TIMEOUT"           // the request took longer than --timeout
+	CodeServerError      = "SERVER_ERROR"      // the server failed to handle the request
+	CodeConfig           = "CONFIG_ERROR"      // the CLI config couldn't be read or written
+	CodeInterrupted      = "INTERRUPTED"       // the command was interrupted
+	CodeUnverified       = "UNVERIFIED"        // a component's digest or signature couldn't be verified
+	CodePermissionDenied = "PERMISSION_DENIED" // the user lacks a permission the command needs
+)
+
+// JSONErrors makes errors print as a JSON object on stdout instead of a
+// line on stderr, for --json.
+var JSONErrors bool
+
+// ErrorCode, if set, returns the code of an error that doesn't carry one,
+// such as an error from the MCP client, or "" if it doesn't know.
+var ErrorCode func(error) string
+
+// CodedError is an error with a machine-readable code for JSON error
+// output.
+type CodedError struct {
+	Code    string
+	Message string
+	// Err is the underlying error, if any.
+	Err error
+	// Status is the exit status; 0 means 1.
+	Status int
+}
+
+func (e *CodedError) Error() string { return e.Message }
+
+func (e *CodedError) Unwrap() error { return e.Err }
+
+// NewError returns an error with code and a message formatted from format
+// and args. The first error among args is kept as the underlying error.
+func NewError(code, format string, args ...any) *CodedError {
+	return &CodedError{Code: code, Message: fmt.Sprintf(format, args...), Err: firstError(args)}
+}
+
+func firstError(args []any) error {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Code returns the code of err: its own if it is a CodedError, else what
+// ErrorCode says, else CodeError.
+func Code(err error) string {
+	var coded *CodedError
+	if errors.As(err, &coded) && coded.Code != "" {
+		return coded.Code
+	}
+	if ErrorCode != nil {
+		if code := ErrorCode(err); code != "" {
+			return code
+		}
+	}
+	return CodeError
+}
+
+// Fail reports err and exits: with JSONErrors as
+// {"error": {"code": ..., "message": ...}} on stdout, otherwise as an
+// "Error:" line on stderr, after the pager is closed so it stays visible.
+// An interruption is reported without the prefix, as it isn't the
+// command's fault, and an error without a message only sets the exit
+// status. Commands return their errors rather than calling it; Execute
+// does, once.
+func Fail(err error) {
+	status := 1
+	var coded *CodedError
+	if errors.As(err, &coded) && coded.Status != 0 {
+		status = coded.Status
+	}
+	switch {
+	case err.Error() == "":
+	case JSONErrors:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		enc.Encode(map[string]any{
+			"error": map[string]any{"code": Code(err), "message": err.Error()},
+		})
+	case Code(err) == CodeInterrupted:
+		StopPager()
+		fmt.Fprintln(os.Stderr, err.Error())
+	default:
+		StopPager()
+		fmt.Fprintln(os.Stderr, errorPrefix()+err.Error())
+	}
+	Exit(status)
+}
+
+// Error returns an error with message msg and no code of its own; see
+// Code.
+func Error(msg string) error {
+	return &CodedError{Message: msg}
+}
+
+// Errorf returns an error with a message formatted from format and args.
+// An error among args decides the code, and is kept as the underlying
+// error.
+func Errorf(format string, args ...any) error {
+	return &CodedError{Message: fmt.Sprintf(format, args...), Err: firstError(args)}
+}
+
+// ExitError returns an error that only sets the exit status, for commands
+// that have already shown what went wrong, e.g. in their output.
+func ExitError(status int) error {
+	return &CodedError{Status: status}
+}
+
+// ColorStderr turns on ANSI colors on stderr, e.g. for the "Error:" prefix
+// of error messages.
+var ColorStderr bool
+
+// errorPrefix returns the prefix of error messages.
+func errorPrefix() string {
+	if ColorStderr {
+		return "\x1b[31mError:\x1b[0m "
+	}
+	return "Error: "
+}
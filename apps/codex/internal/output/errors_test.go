@@ -0,0 +1,35 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	defer func() { ErrorCode = nil }()
+	ErrorCode = func(err error) string {
+		if errors.Is(err, errSentinel) {
+			return CodeTimeout
+		}
+		return ""
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NewError(CodeNotFound, "Context '%s' not found.", "x"), CodeNotFound},
+		{fmt.Errorf("wrapped: %w", NewError(CodeConfig, "bad config")), CodeConfig},
+		{&CodedError{Message: "Failed: slow", Err: errSentinel}, CodeTimeout},
+		{NewError("", "Failed: %v", errSentinel), CodeTimeout},
+		{errors.New("other"), CodeError},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+var errSentinel = errors.New("sentinel")
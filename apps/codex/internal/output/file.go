@@ -0,0 +1,64 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// outputFile is the file stdout is written to since StartOutputFile, and
+// the stdout it replaced.
+var outputFile struct {
+	tmp    *os.File
+	path   string
+	stdout *os.File
+}
+
+// StartOutputFile sends stdout to path until FinishOutputFile. The output
+// goes to a temporary file next to path, which only replaces path once the
+// command has succeeded, so path never holds partial output.
+func StartOutputFile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	outputFile.tmp, outputFile.path, outputFile.stdout = tmp, path, os.Stdout
+	os.Stdout = tmp
+	return nil
+}
+
+// FinishOutputFile restores stdout and, if keep is set, renames the output
+// into place and returns its size. Otherwise the output is discarded and
+// path left as it was. It does nothing without StartOutputFile.
+func FinishOutputFile(keep bool) (int64, error) {
+	tmp := outputFile.tmp
+	if tmp == nil {
+		return 0, nil
+	}
+	os.Stdout = outputFile.stdout
+	outputFile.tmp = nil
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if !keep {
+		tmp.Close()
+		return 0, nil
+	}
+	info, err := tmp.Stat()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), outputFile.path); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
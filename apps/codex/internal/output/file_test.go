@@ -0,0 +1,51 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+
+	// A failed command leaves the file as it was.
+	if err := StartOutputFile(path); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("partial")
+	if _, err := FinishOutputFile(false); err != nil {
+		t.Fatal(err)
+	}
+	if os.Stdout != stdout {
+		t.Fatal("stdout not restored")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old\n" {
+		t.Errorf("discarded output replaced the file: %q", data)
+	}
+
+	if err := StartOutputFile(path); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("new output")
+	size, err := FinishOutputFile(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new output\n" || size != int64(len(data)) {
+		t.Errorf("file = %q, size %d", data, size)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+
+	if _, err := FinishOutputFile(true); err != nil {
+		t.Errorf("FinishOutputFile without StartOutputFile: %v", err)
+	}
+}
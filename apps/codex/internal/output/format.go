@@ -9,8 +9,26 @@ import (
 	"text/tabwriter"
 )
 
-// JSON prints a value as formatted JSON.
+// Meta, if set, returns data that JSON adds under the "_meta" key of object
+// output, such as request timings. Keys already under "_meta" are kept.
+var Meta func() map[string]any
+
+// Color turns on ANSI colors on stdout: bold table headers, colored
+// statuses and dimmed secondary fields.
+var Color bool
+
+// JSON prints a value as formatted JSON, or reduced by Query and the
+// template set with SetTemplate if either is set.
 func JSON(v any) {
+	if m, ok := v.(map[string]any); ok && Meta != nil {
+		v = withMeta(m, Meta())
+	}
+	if filtering() {
+		if err := printFiltered(v); err != nil {
+			exitOnFilterError(err)
+		}
+		return
+	}
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
@@ -19,16 +37,48 @@ func JSON(v any) {
 	fmt.Println(string(data))
 }
 
+// withMeta returns a copy of m with meta merged into its "_meta" object.
+func withMeta(m, meta map[string]any) map[string]any {
+	out := make(map[string]any, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	merged := map[string]any{}
+	if existing, ok := m["_meta"].(map[string]any); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range meta {
+		merged[k] = v
+	}
+	out["_meta"] = merged
+	return out
+}
+
+// TableFormat, if "csv" or "tsv", makes Table print comma- or
+// tab-separated values instead of aligned columns, for spreadsheets and
+// data pipelines.
+var TableFormat string
+
 // Table prints a list of maps as a formatted table.
 func Table(headers []string, rows []map[string]string) {
+	if TableFormat != "" {
+		delimitedTable(headers, rows, TableFormat)
+		return
+	}
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, strings.Join(headers, "\t"))
-	fmt.Fprintln(w, strings.Repeat("-\t", len(headers)))
+	styled := make([]string, len(headers))
+	for i, h := range headers {
+		styled[i] = style(styleBold, h)
+	}
+	fmt.Fprintln(w, strings.Join(styled, "\t"))
+	fmt.Fprintln(w, strings.Repeat(style(styleDim, "-")+"\t", len(headers)))
 
 	for _, row := range rows {
 		vals := make([]string, len(headers))
 		for i, h := range headers {
-			vals[i] = row[h]
+			vals[i] = style(cellStyle(h, row[h]), row[h])
 		}
 		fmt.Fprintln(w, strings.Join(vals, "\t"))
 	}
@@ -48,7 +98,12 @@ func KeyValue(data map[string]any) {
 		switch val := v.(type) {
 		case map[string]any, []any:
 			jsonBytes, _ := json.MarshalIndent(val, "                     ", "  ")
-			fmt.Printf("%-20s %s\n", k+":", string(jsonBytes))
+			fmt.Printf("%-20s %s\n", k+":", style(styleDim, string(jsonBytes)))
+		case string:
+			if k == "status" {
+				val = style(statusStyle(val), val)
+			}
+			fmt.Printf("%-20s %s\n", k+":", val)
 		default:
 			fmt.Printf("%-20s %v\n", k+":", val)
 		}
@@ -60,14 +115,16 @@ func Success(msg string) {
 	fmt.Println(msg)
 }
 
-// Error prints an error message to stderr and exits.
-func Error(msg string) {
-	fmt.Fprintln(os.Stderr, "Error: "+msg)
-	os.Exit(1)
-}
-
-// Errorf prints a formatted error message to stderr and exits.
-func Errorf(format string, args ...any) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
-	os.Exit(1)
+// HumanBytes formats a byte count using binary units, e.g. 1536 → "1.5 KiB".
+func HumanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
@@ -111,3 +111,33 @@ func TestSuccess_Output(t *testing.T) {
 		t.Errorf("expected 'operation complete', got %q", trimmed)
 	}
 }
+
+func TestHumanBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := HumanBytes(tt.in); got != tt.want {
+			t.Errorf("HumanBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWithMeta(t *testing.T) {
+	in := map[string]any{"a": 1, "_meta": map[string]any{"server": "x"}}
+	out := withMeta(in, map[string]any{"timing": "t"})
+	meta, _ := out["_meta"].(map[string]any)
+	if out["a"] != 1 || meta["server"] != "x" || meta["timing"] != "t" {
+		t.Errorf("withMeta = %v", out)
+	}
+	if _, ok := in["_meta"].(map[string]any)["timing"]; ok {
+		t.Error("withMeta modified its input")
+	}
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListTable prints the items of a list result as a table with a column per
+// field. A column may name a nested field, e.g. "reference.registry".
+// Items that aren't objects, such as plain names, fill the first column.
+func ListTable(items []any, columns []string) {
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = strings.ToUpper(c)
+	}
+	rows := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		row := make(map[string]string, len(columns))
+		if _, ok := item.(map[string]any); !ok {
+			if len(headers) > 0 {
+				row[headers[0]] = Cell(item)
+			}
+			rows = append(rows, row)
+			continue
+		}
+		for i, c := range columns {
+			v, _ := Select(item, "."+c)
+			row[headers[i]] = Cell(v)
+		}
+		rows = append(rows, row)
+	}
+	Table(headers, rows)
+}
+
+// ListColumns returns the fields worth a column for items: those that are
+// present with a value that fits a cell in at least one item, sorted.
+func ListColumns(items []any) []string {
+	seen := make(map[string]bool)
+	for _, item := range items {
+		m, _ := item.(map[string]any)
+		for k, v := range m {
+			if fitsCell(v) {
+				seen[k] = true
+			}
+		}
+	}
+	columns := make([]string, 0, len(seen))
+	for k := range seen {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// fitsCell reports whether v reads well in a table cell: a scalar, a list
+// of scalars, or an object with a single field.
+func fitsCell(v any) bool {
+	switch val := v.(type) {
+	case map[string]any:
+		return len(val) == 1
+	case []any:
+		for _, item := range val {
+			switch item.(type) {
+			case map[string]any, []any:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Cell formats a field value for a table cell: lists of scalars comma
+// separated, objects with a single field, such as component references,
+// as that field's value, other objects as compact JSON, and null as "-".
+func Cell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return val
+	case float64:
+		return fmt.Sprint(val)
+	case map[string]any:
+		if len(val) == 1 {
+			for _, inner := range val {
+				return Cell(inner)
+			}
+		}
+	case []any:
+		if fitsCell(val) {
+			parts := make([]string, len(val))
+			for i, item := range val {
+				parts[i] = Cell(item)
+			}
+			return strings.Join(parts, ",")
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
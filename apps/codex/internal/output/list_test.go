@@ -0,0 +1,60 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCell(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{nil, "-"},
+		{"x", "x"},
+		{12.0, "12"},
+		{true, "true"},
+		{[]any{"a", "b"}, "a,b"},
+		{map[string]any{"registry": "local.hello:0.1.0"}, "local.hello:0.1.0"},
+		{map[string]any{"a": 1.0, "b": 2.0}, `{"a":1,"b":2}`},
+		{[]any{map[string]any{"a": 1.0}}, `[{"a":1}]`},
+	}
+	for _, tt := range tests {
+		if got := Cell(tt.in); got != tt.want {
+			t.Errorf("Cell(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestListColumns(t *testing.T) {
+	items := []any{
+		map[string]any{"name": "a", "meta": map[string]any{"x": 1.0, "y": 2.0}},
+		map[string]any{"name": "b", "scope": []any{"read"}},
+		"plain",
+	}
+	if got, want := ListColumns(items), []string{"name", "scope"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListColumns = %v, want %v", got, want)
+	}
+}
+
+func TestListTable(t *testing.T) {
+	items := []any{
+		map[string]any{"name": "ci", "ref": map[string]any{"registry": "r:a:1"}},
+		map[string]any{"name": "deploy"},
+		"bare",
+	}
+	out := captureStdout(t, func() { ListTable(items, []string{"name", "ref.registry"}) })
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header, separator and 3 rows, got:\n%s", out)
+	}
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"NAME", "REF.REGISTRY"}) {
+		t.Errorf("headers = %v", fields)
+	}
+	for i, want := range [][]string{{"ci", "r:a:1"}, {"deploy", "-"}, {"bare"}} {
+		if fields := strings.Fields(lines[i+2]); !reflect.DeepEqual(fields, want) {
+			t.Errorf("row %d = %v, want %v", i, fields, want)
+		}
+	}
+}
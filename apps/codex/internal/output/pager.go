@@ -0,0 +1,65 @@
+package output
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pager is the running pager, if any, and the stdout it replaced.
+var pager struct {
+	cmd    *exec.Cmd
+	stdout *os.File
+}
+
+// StartPager sends stdout through a pager command line, e.g. "less -R",
+// until StopPager. Like git, it sets LESS=FRX unless LESS is set, so less
+// exits right away when the output fits on one screen. It does nothing if
+// stdout isn't a terminal, the command is empty or "cat", or the pager
+// can't be started.
+func StartPager(command string) {
+	fields := strings.Fields(command)
+	if pager.cmd != nil || len(fields) == 0 || fields[0] == "cat" || !IsTerminal(os.Stdout) {
+		return
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	c := exec.Command(fields[0], fields[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = r, os.Stdout, os.Stderr
+	c.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		c.Env = append(c.Env, "LESS=FRX")
+	}
+	if os.Getenv("LV") == "" {
+		c.Env = append(c.Env, "LV=-c")
+	}
+	if err := c.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return
+	}
+	r.Close()
+	pager.cmd, pager.stdout = c, os.Stdout
+	os.Stdout = w
+}
+
+// StopPager ends the output sent to the pager and waits for the user to
+// quit it, restoring stdout.
+func StopPager() {
+	if pager.cmd == nil {
+		return
+	}
+	os.Stdout.Close()
+	os.Stdout = pager.stdout
+	pager.cmd.Wait()
+	pager.cmd = nil
+}
+
+// Exit stops the pager, if any, and exits with status. Commands exit
+// through it rather than os.Exit, which would leave the pager behind.
+func Exit(status int) {
+	StopPager()
+	os.Exit(status)
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStartPager_NotATerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		w.Close()
+		os.Stdout = stdout
+	}()
+
+	StartPager("less")
+	if pager.cmd != nil || os.Stdout != w {
+		StopPager()
+		t.Fatal("the pager should only start when stdout is a terminal")
+	}
+	StopPager() // no-op without a pager
+	if os.Stdout != w {
+		t.Error("StopPager without a pager changed stdout")
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progressBarWidth is the number of cells in a determinate progress bar.
+const progressBarWidth = 30
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Progress draws a single-line progress bar (when the total is known) or
+// spinner (when it isn't) on stderr. It draws nothing when stderr is not a
+// terminal, so redirected output and CI logs stay clean, or when Silent is
+// set.
+type Progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	label   string
+	enabled bool
+	frame   int
+	drawn   bool
+}
+
+// NewProgress returns a progress indicator with the given label.
+func NewProgress(label string) *Progress {
+	return &Progress{w: os.Stderr, label: label, enabled: !Silent && IsTerminal(os.Stderr)}
+}
+
+// Update redraws the indicator. total <= 0 means the total is unknown.
+func (p *Progress) Update(current, total float64, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	p.frame++
+	fmt.Fprintf(p.w, "\r%s\x1b[K", renderProgress(p.label, current, total, message, p.frame))
+	p.drawn = true
+}
+
+// Done clears the indicator line.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.drawn {
+		fmt.Fprint(p.w, "\r\x1b[K")
+		p.drawn = false
+	}
+}
+
+// renderProgress formats one frame of a progress indicator.
+func renderProgress(label string, current, total float64, message string, frame int) string {
+	var b strings.Builder
+	b.WriteString(label)
+	if total > 0 {
+		frac := current / total
+		if frac < 0 {
+			frac = 0
+		}
+		if frac > 1 {
+			frac = 1
+		}
+		filled := int(frac * progressBarWidth)
+		b.WriteString(" [")
+		b.WriteString(strings.Repeat("=", filled))
+		if filled < progressBarWidth {
+			b.WriteString(">")
+			b.WriteString(strings.Repeat(" ", progressBarWidth-filled-1))
+		}
+		fmt.Fprintf(&b, "] %3d%%", int(frac*100))
+	} else {
+		fmt.Fprintf(&b, " %s %v", spinnerFrames[frame%len(spinnerFrames)], current)
+	}
+	if message != "" {
+		b.WriteString(" ")
+		b.WriteString(message)
+	}
+	return b.String()
+}
+
+// IsTerminal reports whether f is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
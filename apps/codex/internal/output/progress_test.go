@@ -0,0 +1,48 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderProgress(t *testing.T) {
+	tests := []struct {
+		name           string
+		current, total float64
+		message        string
+		want           string
+	}{
+		{"half", 50, 100, "", "Pulling [===============>              ]  50%"},
+		{"complete", 100, 100, "done", "Pulling [==============================] 100% done"},
+		{"over total", 150, 100, "", "Pulling [==============================] 100%"},
+		{"unknown total", 3, 0, "chunks", "Pulling / 3 chunks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderProgress("Pulling", tt.current, tt.total, tt.message, 1); got != tt.want {
+				t.Errorf("renderProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgress_DisabledDrawsNothing(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Progress{w: &buf, label: "x"}
+	p.Update(1, 2, "")
+	p.Done()
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestProgress_DoneClearsLine(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Progress{w: &buf, label: "x", enabled: true}
+	p.Update(1, 2, "")
+	p.Done()
+	if !strings.HasSuffix(buf.String(), "\r\x1b[K") {
+		t.Errorf("expected line to be cleared, got %q", buf.String())
+	}
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Query, if set, is a path expression JSON applies to its value before
+// printing, e.g. ".execution_id" or "$.keys[*].name"; see Select.
+var Query string
+
+// tmpl, if set, formats JSON output instead of printing it as JSON.
+var tmpl *template.Template
+
+// SetTemplate makes JSON output formatted with a Go template, e.g.
+// "{{.status}}", executed on the value as decoded from JSON. An empty
+// text turns templating off.
+func SetTemplate(text string) error {
+	if text == "" {
+		tmpl = nil
+		return nil
+	}
+	t, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"join": func(sep string, v []any) string {
+			parts := make([]string, len(v))
+			for i, item := range v {
+				parts[i] = fmt.Sprint(item)
+			}
+			return strings.Join(parts, sep)
+		},
+	}).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	tmpl = t
+	return nil
+}
+
+// filtering reports whether JSON output goes through Query or a template.
+func filtering() bool {
+	return Query != "" || tmpl != nil
+}
+
+// printFiltered prints v reduced by Query and formatted by the template.
+// Strings are printed bare and lists one item per line, so a single field
+// can be used in scripts as is.
+func printFiltered(v any) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	if Query != "" {
+		generic, err = Select(generic, Query)
+		if err != nil {
+			return err
+		}
+	}
+	if tmpl != nil {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, generic); err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+		out := buf.String()
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		fmt.Print(out)
+		return nil
+	}
+	if list, ok := generic.([]any); ok {
+		for _, item := range list {
+			fmt.Println(bare(item, false))
+		}
+		return nil
+	}
+	fmt.Println(bare(generic, true))
+	return nil
+}
+
+// bare formats a query result: strings without quotes, anything else as
+// JSON, indented if indent is set.
+func bare(v any, indent bool) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	var data []byte
+	if indent {
+		data, _ = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, _ = json.Marshal(v)
+	}
+	return string(data)
+}
+
+// toGeneric converts v to the maps, slices and scalars encoding/json
+// decodes into, so structs can be queried like maps.
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Select evaluates a path expression on a decoded JSON value. The
+// expression is a subset shared by jq and JSONPath: an optional leading
+// "$" or ".", then .field, ["field"], [index] (negative counts from the
+// end), and [] or [*] to apply the rest of the path to every element of a
+// list or object, collecting the results in a list. Missing fields give
+// null.
+func Select(v any, expr string) (any, error) {
+	steps, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return selectSteps(v, steps), nil
+}
+
+// pathStep is one step of a path: a field, an index, or an iteration
+// (all set to their zero value but iterate).
+type pathStep struct {
+	field   string
+	index   int
+	isIndex bool
+	iterate bool
+}
+
+func parsePath(expr string) ([]pathStep, error) {
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+	if s == "." {
+		return nil, nil
+	}
+	var steps []pathStep
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, "["):
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid query %q: missing ]", expr)
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case inner == "" || inner == "*":
+				steps = append(steps, pathStep{iterate: true})
+			case strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, "'"):
+				field, err := unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query %q: %v", expr, err)
+				}
+				steps = append(steps, pathStep{field: field})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query %q: bad index %q", expr, inner)
+				}
+				steps = append(steps, pathStep{index: n, isIndex: true})
+			}
+		case strings.HasPrefix(s, "."):
+			s = s[1:]
+			if strings.HasPrefix(s, "[") {
+				continue // jq's .["field"] and .[0]
+			}
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid query %q: empty field name", expr)
+			}
+			steps = append(steps, pathStep{field: s[:end]})
+			s = s[end:]
+		default:
+			return nil, fmt.Errorf("invalid query %q: expected . or [ at %q", expr, s)
+		}
+	}
+	return steps, nil
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+func selectSteps(v any, steps []pathStep) any {
+	for i, step := range steps {
+		switch {
+		case step.iterate:
+			var items []any
+			switch c := v.(type) {
+			case []any:
+				items = c
+			case map[string]any:
+				for _, k := range sortedKeys(c) {
+					items = append(items, c[k])
+				}
+			}
+			out := make([]any, 0, len(items))
+			for _, item := range items {
+				out = append(out, selectSteps(item, steps[i+1:]))
+			}
+			return out
+		case step.isIndex:
+			list, _ := v.([]any)
+			n := step.index
+			if n < 0 {
+				n += len(list)
+			}
+			if n < 0 || n >= len(list) {
+				return nil
+			}
+			v = list[n]
+		default:
+			m, _ := v.(map[string]any)
+			v = m[step.field]
+		}
+	}
+	return v
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exitOnFilterError reports a failed query or template and exits.
+func exitOnFilterError(err error) {
+	Fail(&CodedError{Code: CodeInvalidArgument, Message: err.Error(), Err: err})
+}
@@ -0,0 +1,109 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	var doc any
+	json.Unmarshal([]byte(`{
+		"status": "ok",
+		"meta": {"a-b": 1},
+		"items": [{"name": "x", "n": 1}, {"name": "y", "n": 2}],
+		"services": {"b": "down", "a": "ok"}
+	}`), &doc)
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{".status", "ok"},
+		{"$.status", "ok"},
+		{"status", nil},
+		{".", doc},
+		{".missing", nil},
+		{".missing.deeper", nil},
+		{`.meta["a-b"]`, 1.0},
+		{`$.meta['a-b']`, 1.0},
+		{".items[0].name", "x"},
+		{".items[-1].name", "y"},
+		{".items[5]", nil},
+		{".items[].name", []any{"x", "y"}},
+		{"$.items[*].n", []any{1.0, 2.0}},
+		{".services[]", []any{"ok", "down"}},
+	}
+	for _, tt := range tests {
+		got, err := Select(doc, tt.expr)
+		if tt.expr == "status" {
+			if err == nil {
+				t.Errorf("Select(%q) should fail", tt.expr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Select(%q): %v", tt.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Select(%q) = %#v, want %#v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSelect_Invalid(t *testing.T) {
+	for _, expr := range []string{".items[", ".items[x]", "..status", `.["unterminated]`} {
+		if _, err := Select(nil, expr); err == nil {
+			t.Errorf("Select(%q) should fail", expr)
+		}
+	}
+}
+
+func TestJSON_Query(t *testing.T) {
+	defer func() { Query = "" }()
+	data := map[string]any{"id": "exec_1", "tags": []string{"a", "b"}, "n": 3}
+
+	tests := map[string]string{
+		".id":      "exec_1\n",
+		".tags":    "a\nb\n",
+		".n":       "3\n",
+		".nope":    "null\n",
+		".tags[0]": "a\n",
+	}
+	for query, want := range tests {
+		Query = query
+		if got := captureStdout(t, func() { JSON(data) }); got != want {
+			t.Errorf("JSON with query %q printed %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestJSON_Template(t *testing.T) {
+	defer SetTemplate("")
+	type result struct {
+		Status string `json:"status"`
+		Count  int    `json:"count"`
+	}
+
+	if err := SetTemplate("{{.status}} ({{.count}})"); err != nil {
+		t.Fatal(err)
+	}
+	if got := captureStdout(t, func() { JSON(result{"ok", 2}) }); got != "ok (2)\n" {
+		t.Errorf("got %q", got)
+	}
+
+	if err := SetTemplate(`{{join "," .tags}} {{json .meta}}`); err != nil {
+		t.Fatal(err)
+	}
+	got := captureStdout(t, func() {
+		JSON(map[string]any{"tags": []string{"a", "b"}, "meta": map[string]int{"x": 1}})
+	})
+	if got != "a,b {\"x\":1}\n" {
+		t.Errorf("got %q", got)
+	}
+
+	if err := SetTemplate("{{.status"); err == nil {
+		t.Error("expected an error for an unterminated template")
+	}
+}
@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Silent turns off spinners and progress bars, for --json and --quiet.
+var Silent bool
+
+// spinnerInterval is how often a Spinner redraws.
+const spinnerInterval = 100 * time.Millisecond
+
+// Spinner draws an animated spinner on stderr with the elapsed time and
+// the current step, for work that reports no progress of its own. Like
+// Progress, it draws nothing when stderr is not a terminal or Silent is
+// set. Stop it before printing anything else.
+type Spinner struct {
+	mu      sync.Mutex
+	w       io.Writer
+	label   string
+	step    string
+	start   time.Time
+	frame   int
+	drawn   bool
+	stopped bool
+	done    chan struct{}
+}
+
+// NewSpinner starts a spinner with the given label.
+func NewSpinner(label string) *Spinner {
+	s := &Spinner{w: os.Stderr, label: label, start: time.Now(), done: make(chan struct{})}
+	if Silent || !IsTerminal(os.Stderr) {
+		s.stopped = true
+		return s
+	}
+	go s.run()
+	return s
+}
+
+func (s *Spinner) run() {
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for {
+		s.draw()
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Spinner) draw() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.frame++
+	fmt.Fprintf(s.w, "\r%s\x1b[K", renderSpinner(s.label, s.step, time.Since(s.start), s.frame))
+	s.drawn = true
+}
+
+// Step sets the step shown after the elapsed time, e.g. what is being
+// waited for.
+func (s *Spinner) Step(step string) {
+	s.mu.Lock()
+	s.step = step
+	s.mu.Unlock()
+}
+
+// Stop clears the spinner line. It is safe to call more than once.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.drawn {
+		fmt.Fprint(s.w, "\r\x1b[K")
+		s.drawn = false
+	}
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+}
+
+// renderSpinner formats one frame of a spinner.
+func renderSpinner(label, step string, elapsed time.Duration, frame int) string {
+	line := fmt.Sprintf("%s %s %s", spinnerFrames[frame%len(spinnerFrames)], label, formatElapsed(elapsed))
+	if step != "" {
+		line += " " + step
+	}
+	return line
+}
+
+// formatElapsed formats a duration as whole seconds, or minutes and
+// seconds from a minute on: "4s", "1m05s".
+func formatElapsed(d time.Duration) string {
+	secs := int(d / time.Second)
+	if secs < 60 {
+		return fmt.Sprintf("%ds", secs)
+	}
+	return fmt.Sprintf("%dm%02ds", secs/60, secs%60)
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderSpinner(t *testing.T) {
+	if got := renderSpinner("Pulling", "", 4*time.Second, 1); got != "/ Pulling 4s" {
+		t.Errorf("got %q", got)
+	}
+	if got := renderSpinner("Waiting for server", "(HTTP 503)", 65*time.Second, 0); got != "| Waiting for server 1m05s (HTTP 503)" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSpinner_StepAndStop(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Spinner{w: &buf, label: "Pulling", start: time.Now(), done: make(chan struct{})}
+	s.Step("r:local.hello:0.1.0")
+	s.draw()
+	s.Stop()
+	s.Stop()
+	s.draw()
+
+	out := buf.String()
+	if !strings.Contains(out, "Pulling 0s r:local.hello:0.1.0") {
+		t.Errorf("step not drawn: %q", out)
+	}
+	if !strings.HasSuffix(out, "\r\x1b[K") || strings.Count(out, "\r\x1b[K") != 1 {
+		t.Errorf("expected the line cleared once and nothing drawn after Stop, got %q", out)
+	}
+}
+
+func TestSpinner_SilentDrawsNothing(t *testing.T) {
+	defer func() { Silent = false }()
+	Silent = true
+	s := NewSpinner("x")
+	s.Step("y")
+	s.Stop()
+	if !s.stopped || s.drawn {
+		t.Error("a silent spinner should never draw")
+	}
+}
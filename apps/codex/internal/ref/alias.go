@@ -0,0 +1,50 @@
+package ref
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateAlias checks that name can be used as an alias: a non-empty
+// word of lowercase letters, digits and hyphens that isn't a type prefix,
+// so it can't be mistaken for part of a reference.
+func ValidateAlias(name string) error {
+	if IsTypePrefix(name) {
+		return fmt.Errorf("alias %q is a component type", name)
+	}
+	if err := checkSegment(name, "alias", name, 0); err != nil {
+		return fmt.Errorf("invalid alias %q: %s", name, err.(*SyntaxError).Msg)
+	}
+	return nil
+}
+
+// ExpandAlias replaces an alias with the reference it stands for. An alias
+// may be followed by "@" and a version, constraint or digest, which
+// replaces the aliased version or pins it: with claude aliased to
+// catalyst:local.claude:0.1.0, "claude@0.2.0" is catalyst:local.claude:0.2.0.
+// Strings that aren't aliases are returned unchanged, with false.
+func ExpandAlias(s string, aliases map[string]string) (string, bool) {
+	name, suffix, hasSuffix := strings.Cut(strings.TrimSpace(s), "@")
+	target, ok := aliases[name]
+	if !ok {
+		return s, false
+	}
+	if !hasSuffix {
+		return target, true
+	}
+	r, err := Parse(target)
+	if err != nil {
+		// Let the caller report the bad alias target when it parses it.
+		return target + "@" + suffix, true
+	}
+	if version, digest := SplitDigest("@" + suffix); digest != "" {
+		r.Digest = digest
+		if version != "" {
+			r.Version = strings.TrimPrefix(version, "@")
+		}
+	} else {
+		r.Version = suffix
+		r.Digest = ""
+	}
+	return r.String(), true
+}
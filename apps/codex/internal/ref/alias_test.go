@@ -0,0 +1,48 @@
+package ref
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	aliases := map[string]string{
+		"claude": "catalyst:local.claude:0.1.0",
+		"broken": "catalyst:local.Broken:0.1.0",
+	}
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"claude", "catalyst:local.claude:0.1.0", true},
+		{" claude ", "catalyst:local.claude:0.1.0", true},
+		{"claude@0.2.0", "catalyst:local.claude:0.2.0", true},
+		{"claude@^0.1", "catalyst:local.claude:^0.1", true},
+		{"claude@" + digest, "catalyst:local.claude:0.1.0@" + digest, true},
+		{"claude@0.2.0@" + digest, "catalyst:local.claude:0.2.0@" + digest, true},
+		{"broken@1.0.0", "catalyst:local.Broken:0.1.0@1.0.0", true},
+		{"gemini", "gemini", false},
+		{"c:local.claude:0.1.0", "c:local.claude:0.1.0", false},
+	}
+	for _, tt := range tests {
+		got, ok := ExpandAlias(tt.input, aliases)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ExpandAlias(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	for _, good := range []string{"claude", "my-tool", "gpt4"} {
+		if err := ValidateAlias(good); err != nil {
+			t.Errorf("ValidateAlias(%q): %v", good, err)
+		}
+	}
+	for _, bad := range []string{"", "c", "catalyst", "Claude", "local.claude", "a:b", "-x", "x@1"} {
+		if err := ValidateAlias(bad); err == nil {
+			t.Errorf("ValidateAlias(%q) succeeded, want error", bad)
+		}
+	}
+}
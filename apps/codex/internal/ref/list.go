@@ -0,0 +1,92 @@
+package ref
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SplitList splits a comma-separated list of references. Commas inside a
+// version constraint (c:acme.sentiment:>=1.0.0, <2.0.0) don't split: an
+// item starting with a comparison operator, or one that only parses when
+// joined to the item before it, continues that item. Empty items are
+// dropped.
+func SplitList(s string) []string {
+	var items []string
+	for _, piece := range strings.Split(s, ",") {
+		item := strings.TrimSpace(piece)
+		if item == "" {
+			continue
+		}
+		if n := len(items); n > 0 && continuesConstraint(items[n-1], item) {
+			items[n-1] += ", " + item
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// continuesConstraint reports whether item is the rest of a version
+// constraint begun in prev.
+func continuesConstraint(prev, item string) bool {
+	if strings.ContainsAny(item[:1], "<>=^~") {
+		return true
+	}
+	if _, err := Parse(item); err == nil {
+		return false
+	}
+	_, err := Parse(prev + ", " + item)
+	return err == nil
+}
+
+// ParseList parses a comma-separated list of references, such as
+// "c:acme.sentiment:1.0,r:acme.parser:2.0", and returns them deduplicated
+// and sorted (see Dedupe). The error names the first invalid item.
+func ParseList(s string) ([]ComponentRef, error) {
+	items := SplitList(s)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("component ref list cannot be empty")
+	}
+	refs := make([]ComponentRef, 0, len(items))
+	for _, item := range items {
+		r, err := Parse(item)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, r)
+	}
+	return Dedupe(refs), nil
+}
+
+// Dedupe returns refs sorted by type, registry, namespace, name and
+// version (in the order of Compare), with references that are the same in
+// canonical form kept once.
+func Dedupe(refs []ComponentRef) []ComponentRef {
+	out := append([]ComponentRef(nil), refs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Type != b.Type:
+			return a.Type < b.Type
+		case a.Registry != b.Registry:
+			return a.Registry < b.Registry
+		case a.Namespace != b.Namespace:
+			return a.Namespace < b.Namespace
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		case a.Version != b.Version:
+			return Compare(a.Version, b.Version) < 0
+		}
+		return a.Digest < b.Digest
+	})
+	seen := map[string]bool{}
+	deduped := out[:0]
+	for _, r := range out {
+		if key := r.String(); !seen[key] {
+			seen[key] = true
+			deduped = append(deduped, r)
+		}
+	}
+	return deduped
+}
@@ -0,0 +1,46 @@
+package ref
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"c:a.b:1.0.0,r:x.y:2.0.0", []string{"c:a.b:1.0.0", "r:x.y:2.0.0"}},
+		{" c:a.b:1.0.0 , , r:x.y ,", []string{"c:a.b:1.0.0", "r:x.y"}},
+		{"c:a.b:>=1.0.0, <2.0.0,r:x.y", []string{"c:a.b:>=1.0.0, <2.0.0", "r:x.y"}},
+		{"c:a.b:1.0.0 || 2.0.0,c:a.b:3.0.0", []string{"c:a.b:1.0.0 || 2.0.0", "c:a.b:3.0.0"}},
+	}
+	for _, tt := range tests {
+		if got := SplitList(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitList(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	got, err := ParseList("r:x.y:2.0.0, c:a.b:1.10.0,catalyst:a.b:1.10.0,c:a.b:1.9.0,local:tool:1.0.0,local.tool:1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var strs []string
+	for _, r := range got {
+		strs = append(strs, r.String())
+	}
+	want := []string{"local.tool:1.0.0", "catalyst:a.b:1.9.0", "catalyst:a.b:1.10.0", "reagent:x.y:2.0.0"}
+	if !reflect.DeepEqual(strs, want) {
+		t.Errorf("ParseList = %q, want %q", strs, want)
+	}
+
+	if _, err := ParseList("c:a.b:1.0.0,c:A.b"); err == nil || !strings.Contains(err.Error(), `"c:A.b"`) {
+		t.Errorf("ParseList with an invalid item = %v, want an error naming it", err)
+	}
+	if _, err := ParseList(" , "); err == nil {
+		t.Error("ParseList of an empty list succeeded")
+	}
+}
@@ -0,0 +1,71 @@
+package ref
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MustParse is like Parse but panics if s isn't a valid reference. It is
+// meant for references known to be valid, such as constants and defaults.
+func MustParse(s string) ComponentRef {
+	r, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// IsZero reports whether r is the zero reference.
+func (r ComponentRef) IsZero() bool {
+	return r == ComponentRef{}
+}
+
+// MarshalText encodes r as its canonical string, so references are written
+// as strings in JSON, YAML and other text formats. The zero reference
+// encodes as "".
+func (r ComponentRef) MarshalText() ([]byte, error) {
+	if r.IsZero() {
+		return []byte{}, nil
+	}
+	if r.Name == "" {
+		return nil, fmt.Errorf("cannot marshal component ref without a name")
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText parses a reference written by MarshalText, or any other
+// form Parse accepts. An empty string decodes as the zero reference.
+func (r *ComponentRef) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*r = ComponentRef{}
+		return nil
+	}
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// MarshalJSON encodes r as a JSON string in canonical form.
+func (r ComponentRef) MarshalJSON() ([]byte, error) {
+	text, err := r.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes a reference from a JSON string. null leaves r
+// unchanged, as for other types.
+func (r *ComponentRef) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("component ref must be a string: %w", err)
+	}
+	return r.UnmarshalText([]byte(s))
+}
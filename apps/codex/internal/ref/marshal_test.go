@@ -0,0 +1,72 @@
+package ref
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestComponentRef_JSON(t *testing.T) {
+	type lockfile struct {
+		Component ComponentRef            `json:"component"`
+		Optional  ComponentRef            `json:"optional"`
+		ByRef     map[ComponentRef]string `json:"by_ref"`
+	}
+	in := lockfile{
+		Component: MustParse("c:acme.sentiment:1.2.0"),
+		ByRef:     map[ComponentRef]string{MustParse("ghcr.io/acme/parser:2.0.0"): "mirrored"},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"component":"catalyst:acme.sentiment:1.2.0","optional":"","by_ref":{"ghcr.io/acme/parser:2.0.0":"mirrored"}}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	var out lockfile
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Component != in.Component || !out.Optional.IsZero() || len(out.ByRef) != 1 {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+	for k := range out.ByRef {
+		if k != MustParse("ghcr.io/acme/parser:2.0.0") {
+			t.Errorf("map key = %+v", k)
+		}
+	}
+}
+
+func TestComponentRef_UnmarshalJSON_Invalid(t *testing.T) {
+	var r ComponentRef
+	if err := json.Unmarshal([]byte(`"c:Acme.sentiment"`), &r); err == nil || !strings.Contains(err.Error(), "column") {
+		t.Errorf("err = %v, want a syntax error with its column", err)
+	}
+	if err := json.Unmarshal([]byte(`42`), &r); err == nil {
+		t.Error("non-string accepted")
+	}
+	if _, err := (ComponentRef{Namespace: "acme"}).MarshalText(); err == nil {
+		t.Error("ref without a name marshalled")
+	}
+}
+
+func TestComponentRef_UnmarshalText_Forms(t *testing.T) {
+	var r ComponentRef
+	if err := r.UnmarshalText([]byte("local:my-tool:1.0.0")); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := r.MarshalText(); string(text) != "local.my-tool:1.0.0" {
+		t.Errorf("legacy form re-encoded as %q, want canonical local.my-tool:1.0.0", text)
+	}
+}
+
+func TestMustParse_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse of an invalid ref didn't panic")
+		}
+	}()
+	MustParse("c:")
+}
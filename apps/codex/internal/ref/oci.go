@@ -0,0 +1,117 @@
+package ref
+
+import "strings"
+
+// typeDirs maps the plural directory names used for component types, as
+// in components/catalysts/..., to the type.
+var typeDirs = map[string]string{
+	"catalysts": "catalyst",
+	"reagents":  "reagent",
+	"formulas":  "formula",
+}
+
+// isRegistryHost reports whether the first path segment of a reference is
+// a registry host rather than part of a name, following Docker's rule: it
+// contains a dot or a port, or is localhost.
+func isRegistryHost(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
+
+// parseOCI parses a reference to a component in an OCI registry:
+//
+//	[type:]host[:port]/path/to/name[:version]
+//
+// When no type prefix is given and a path segment is a type directory
+// (registry.corp/catalysts/foo), the type is taken from it.
+func parseOCI(full, s string, offset int) (ComponentRef, error) {
+	host, path, _ := strings.Cut(s, "/")
+	if err := checkHost(full, host, offset); err != nil {
+		return ComponentRef{}, err
+	}
+	pathAt := offset + len(host) + 1
+
+	r := ComponentRef{Registry: host, Version: "latest"}
+	versionAt := -1
+	if i := strings.LastIndexByte(path, ':'); i >= 0 {
+		r.Version = path[i+1:]
+		versionAt = pathAt + i + 1
+		path = path[:i]
+	}
+
+	segAt := pathAt
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if err := checkRepoComponent(full, seg, segAt); err != nil {
+			return ComponentRef{}, err
+		}
+		if t, ok := typeDirs[seg]; ok && i < len(segments)-1 && r.Type == "" {
+			r.Type = t
+		}
+		segAt += len(seg) + 1
+	}
+	r.Name = segments[len(segments)-1]
+	r.Namespace = strings.Join(segments[:len(segments)-1], "/")
+
+	if versionAt >= 0 {
+		if err := checkVersion(full, r.Version, versionAt); err != nil {
+			return ComponentRef{}, err
+		}
+		r.Version = strings.Join(strings.Fields(r.Version), " ")
+	}
+	return r, nil
+}
+
+// checkHost validates a registry host with an optional port.
+func checkHost(ref, host string, offset int) error {
+	name, port, hasPort := strings.Cut(host, ":")
+	if name == "" {
+		return syntaxError(ref, offset, "missing registry host")
+	}
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-') {
+			return syntaxError(ref, offset+i, "invalid character %q in registry host", c)
+		}
+	}
+	if hasPort {
+		portAt := offset + len(name) + 1
+		if port == "" {
+			return syntaxError(ref, portAt, "missing registry port")
+		}
+		for i, c := range port {
+			if c < '0' || c > '9' {
+				return syntaxError(ref, portAt+i, "invalid character %q in registry port", c)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRepoComponent validates one segment of an OCI repository path:
+// lowercase letters and digits, separated by single dots, underscores
+// (one or two) or hyphens.
+func checkRepoComponent(ref, seg string, offset int) error {
+	if seg == "" {
+		return syntaxError(ref, offset, "missing repository path segment")
+	}
+	for i, c := range seg {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == '-':
+			if i == 0 || i == len(seg)-1 {
+				return syntaxError(ref, offset+i, "repository path segment cannot start or end with %q", c)
+			}
+		case c >= 'A' && c <= 'Z':
+			return syntaxError(ref, offset+i, "repository path must be lowercase, found %q", c)
+		default:
+			return syntaxError(ref, offset+i, "invalid character %q in repository path", c)
+		}
+	}
+	return nil
+}
+
+// IsTypeDir reports whether s is the plural directory name of a component
+// type, such as "catalysts".
+func IsTypeDir(s string) bool {
+	_, ok := typeDirs[s]
+	return ok
+}
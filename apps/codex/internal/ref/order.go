@@ -0,0 +1,73 @@
+package ref
+
+import (
+	"sort"
+	"strings"
+)
+
+// Compare orders two version strings the way published versions are
+// listed: semantic versions by precedence, with build metadata only
+// breaking ties between otherwise equal versions; after them, strings that
+// aren't semantic versions, alphabetically; and "latest" above everything,
+// as it stands for the newest release. It returns -1, 0 or 1.
+func Compare(a, b string) int {
+	if a == b {
+		return 0
+	}
+	switch {
+	case a == "latest":
+		return 1
+	case b == "latest":
+		return -1
+	}
+	av, aErr := ParseVersion(a)
+	bv, bErr := ParseVersion(b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return strings.Compare(a, b)
+	case aErr != nil:
+		return 1
+	case bErr != nil:
+		return -1
+	}
+	if d := av.Compare(bv); d != 0 {
+		return d
+	}
+	return compareBuild(av.Build, bv.Build)
+}
+
+// compareBuild orders build metadata for a stable sort: none first, then
+// identifier by identifier like prereleases.
+func compareBuild(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+	return comparePre(a, b)
+}
+
+// Sort sorts versions in place from lowest to highest, in the order of
+// Compare.
+func Sort(vs []string) {
+	sort.SliceStable(vs, func(i, j int) bool { return Compare(vs[i], vs[j]) < 0 })
+}
+
+// Latest returns the version "latest" resolves to among vs: the highest
+// release. Prereleases and strings that aren't semantic versions are
+// never picked; ok is false if vs has no release.
+func Latest(vs []string) (latest string, ok bool) {
+	for _, s := range vs {
+		v, err := ParseVersion(s)
+		if err != nil || v.Pre != "" {
+			continue
+		}
+		if !ok || Compare(s, latest) > 0 {
+			latest, ok = s, true
+		}
+	}
+	return latest, ok
+}
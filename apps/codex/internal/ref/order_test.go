@@ -0,0 +1,52 @@
+package ref
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	// Each version is lower than the next.
+	ordered := []string{
+		"0.9.0", "1.0.0-rc.1", "1.0.0", "1.0.0+build.2", "1.0.0+build.11",
+		"1.2.0-beta", "1.2.0", "10.0.0", "dev", "nightly", "latest",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, b := ordered[i], ordered[i+1]
+		if Compare(a, b) != -1 || Compare(b, a) != 1 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+	}
+	if Compare("1.0.0", "1.0.0") != 0 {
+		t.Error("Compare of equal versions should be 0")
+	}
+}
+
+func TestSort(t *testing.T) {
+	vs := []string{"latest", "1.10.0", "1.2.0", "1.2.0-rc.1", "dev", "1.9.0"}
+	Sort(vs)
+	want := []string{"1.2.0-rc.1", "1.2.0", "1.9.0", "1.10.0", "dev", "latest"}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("Sort = %v, want %v", vs, want)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		versions []string
+		want     string
+		ok       bool
+	}{
+		{[]string{"1.2.0", "1.10.0", "1.9.0"}, "1.10.0", true},
+		{[]string{"1.0.0", "2.0.0-rc.1", "latest"}, "1.0.0", true},
+		{[]string{"1.0.0+build.1", "1.0.0+build.2"}, "1.0.0+build.2", true},
+		{[]string{"2.0.0-rc.1", "dev"}, "", false},
+		{nil, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Latest(tt.versions)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Latest(%v) = %q, %v; want %q, %v", tt.versions, got, ok, tt.want, tt.ok)
+		}
+	}
+}
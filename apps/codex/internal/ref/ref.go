@@ -1,13 +1,28 @@
-// Package ref provides component type prefix detection and expansion.
+// Package ref parses component references and resolves version constraints.
 //
-// Component types in CYFR: catalyst, reagent, formula.
-// Shorthand prefixes: c, r, f.
+// Component references follow the canonical format type:namespace.name:version,
+// e.g. catalyst:local.claude:0.1.0. Component types in CYFR: catalyst,
+// reagent, formula. Shorthand prefixes: c, r, f.
 //
-// Parsing and validation of full component references is handled server-side
-// by Sanctum.ComponentRef (Elixir). The CLI only needs type prefix awareness
-// for input normalization (e.g., joining "c local.claude" → "c:local.claude").
+// Parse mirrors the formats and validation rules of Sanctum.ComponentRef
+// (Elixir), so mistakes are reported before anything is sent, with the
+// position of the offending character. In addition, the version may be a
+// constraint such as ^1.2 or ">=1.0.0 <2.0.0"; the server only accepts exact
+// versions, so the CLI resolves constraints against the published versions
+// with ResolveVersion before sending a reference. A reference may also be
+// pinned to an artifact digest, e.g. catalyst:acme.sentiment@sha256:abcd...,
+// which the CLI verifies against what the registry serves.
 package ref
 
+import (
+	"fmt"
+	"strings"
+)
+
+// DigestAlgorithm prefixes artifact digests, the only algorithm the
+// registry uses.
+const DigestAlgorithm = "sha256:"
+
 // validTypes is the set of recognized component types.
 var validTypes = map[string]bool{
 	"catalyst": true,
@@ -30,3 +45,236 @@ func IsTypePrefix(s string) bool {
 	_, ok := typeShorthands[s]
 	return ok
 }
+
+// ExpandTypePrefix expands a leading type shorthand in a reference string
+// to the full type name, e.g. "c:local.claude:0.1.0" → "catalyst:local.claude:0.1.0".
+// Strings without a recognized shorthand prefix are returned unchanged.
+func ExpandTypePrefix(s string) string {
+	prefix, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return s
+	}
+	if full, ok := typeShorthands[prefix]; ok {
+		return full + ":" + rest
+	}
+	return s
+}
+
+// ComponentRef is a parsed component reference.
+type ComponentRef struct {
+	// Type is catalyst, reagent or formula; empty if the reference had no
+	// type prefix.
+	Type      string
+	Namespace string
+	Name      string
+	// Version is an exact version, "latest", or a constraint (see
+	// IsConstraint).
+	Version string
+	// Digest pins the artifact, e.g. "sha256:abcd..."; empty if the
+	// reference isn't pinned.
+	Digest string
+	// Registry is the host of the OCI registry the component lives in,
+	// e.g. "ghcr.io", for references like ghcr.io/acme/sentiment:1.0.0;
+	// empty for components in the CYFR server's own registry. Namespace is
+	// then the repository path before the name, and may contain slashes or
+	// be empty.
+	Registry string
+}
+
+// String returns the reference in canonical form, with the type prefix if
+// it has one. A pinned reference ends in "@" and the digest, and leaves out
+// the version if it is "latest".
+func (r ComponentRef) String() string {
+	s := r.Namespace + "." + r.Name
+	if r.Registry != "" {
+		s = r.Registry + "/" + r.Repository()
+	}
+	if r.Digest == "" || r.Version != "latest" {
+		s += ":" + r.Version
+	}
+	if r.Type != "" {
+		s = r.Type + ":" + s
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Repository returns the OCI repository path of a registry-host
+// reference: its namespace and name joined by "/".
+func (r ComponentRef) Repository() string {
+	if r.Namespace == "" {
+		return r.Name
+	}
+	return r.Namespace + "/" + r.Name
+}
+
+// Unpinned returns the reference without its digest, in canonical form:
+// the reference to send to the server, which doesn't understand digests.
+func (r ComponentRef) Unpinned() string {
+	r.Digest = ""
+	return r.String()
+}
+
+// SplitDigest splits a trailing "@sha256:..." digest off a reference
+// string. It returns s unchanged and an empty digest if there is none. The
+// digest isn't validated.
+func SplitDigest(s string) (string, string) {
+	i := strings.LastIndex(s, "@"+DigestAlgorithm)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i+1:]
+}
+
+// ValidDigest reports whether d is a sha256 digest: "sha256:" followed by
+// 64 lowercase hex digits.
+func ValidDigest(d string) bool {
+	hex, ok := strings.CutPrefix(d, DigestAlgorithm)
+	if !ok || len(hex) != 64 {
+		return false
+	}
+	for _, c := range hex {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse parses a component reference. Like Sanctum.ComponentRef.parse it
+// accepts typed and untyped forms:
+//
+//	catalyst:local.my-tool:1.0.0   typed canonical
+//	c:local.my-tool:1.0.0          shorthand type
+//	local.my-tool:1.0.0            canonical (no type)
+//	my-tool:1.0.0                  namespace defaults to "local"
+//	my-tool                        version defaults to "latest"
+//	local:my-tool:1.0.0            legacy colon-separated
+//	ghcr.io/acme/my-tool:1.0.0     in an OCI registry (see parseOCI)
+//
+// The version may also be a constraint such as ^1.2, ~1.4.0 or
+// ">=1.0.0 <2.0.0" (see ParseConstraint), and any form may be pinned to an
+// artifact digest with a "@sha256:..." suffix.
+//
+// The namespace and name are checked with the rules of
+// Sanctum.ComponentRef.validate: 1-64 lowercase letters, digits and
+// hyphens, not starting or ending with a hyphen. Malformed references
+// return a *SyntaxError pointing at the offending character.
+func Parse(s string) (ComponentRef, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ComponentRef{}, fmt.Errorf("component ref cannot be empty")
+	}
+	if base, digest := SplitDigest(s); digest != "" {
+		if err := checkDigest(s, digest, len(base)+1); err != nil {
+			return ComponentRef{}, err
+		}
+		r, err := parse(s, base, 0)
+		if err != nil {
+			return ComponentRef{}, err
+		}
+		r.Digest = digest
+		return r, nil
+	}
+	return parse(s, s, 0)
+}
+
+// parse parses s, which starts at byte offset in the full reference, so
+// that errors can point into full.
+func parse(full, s string, offset int) (ComponentRef, error) {
+	if s == "" {
+		return ComponentRef{}, syntaxError(full, offset, "missing component name")
+	}
+	if prefix, rest, ok := strings.Cut(s, ":"); ok && !strings.Contains(prefix, ".") && IsTypePrefix(prefix) {
+		restOffset := offset + len(prefix) + 1
+		if p, _, ok := strings.Cut(rest, ":"); ok && !strings.Contains(p, ".") && IsTypePrefix(p) {
+			return ComponentRef{}, syntaxError(full, restOffset, "duplicate type prefix %q", p)
+		}
+		inner, err := parse(full, rest, restOffset)
+		if err != nil {
+			return ComponentRef{}, err
+		}
+		inner.Type = ExpandType(prefix)
+		return inner, nil
+	}
+
+	if host, _, ok := strings.Cut(s, "/"); ok && isRegistryHost(host) {
+		return parseOCI(full, s, offset)
+	}
+
+	var r ComponentRef
+	var nsAt, nameAt, versionAt int
+	before, version, hasVersion := strings.Cut(s, ":")
+	switch {
+	case hasVersion && strings.Count(s, ":") == 2 && !strings.Contains(before, "."):
+		// Legacy "local:name:version".
+		r.Namespace = before
+		r.Name, r.Version, _ = strings.Cut(version, ":")
+		if strings.Contains(r.Name, ".") {
+			// "catalist:local.tool:1.0.0" is a misspelled type, not a
+			// legacy reference.
+			return ComponentRef{}, syntaxError(full, offset, "unknown component type %q. Valid types: catalyst (c), reagent (r), formula (f)", before)
+		}
+		nsAt, nameAt = offset, offset+len(before)+1
+		versionAt = nameAt + len(r.Name) + 1
+	case strings.Contains(before, "."):
+		r.Namespace, r.Name, _ = strings.Cut(before, ".")
+		r.Version = version
+		nsAt, nameAt = offset, offset+len(r.Namespace)+1
+		versionAt = offset + len(before) + 1
+	case hasVersion:
+		r.Namespace, r.Name, r.Version = "local", before, version
+		nameAt, versionAt = offset, offset+len(before)+1
+	default:
+		r.Namespace, r.Name = "local", s
+		nameAt = offset
+	}
+
+	if nsAt != nameAt {
+		if err := checkSegment(full, "namespace", r.Namespace, nsAt); err != nil {
+			return ComponentRef{}, err
+		}
+	}
+	if err := checkSegment(full, "name", r.Name, nameAt); err != nil {
+		return ComponentRef{}, err
+	}
+	if !hasVersion {
+		r.Version = "latest"
+		return r, nil
+	}
+	if err := checkVersion(full, r.Version, versionAt); err != nil {
+		return ComponentRef{}, err
+	}
+	r.Version = strings.Join(strings.Fields(r.Version), " ")
+	return r, nil
+}
+
+// Normalize parses a reference and returns it in canonical form. Like
+// Sanctum.ComponentRef.normalize, it requires a type prefix.
+func Normalize(s string) (string, error) {
+	r, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if r.Type == "" {
+		return "", fmt.Errorf("component ref must include a type prefix (e.g., catalyst:%s). Valid types: catalyst (c), reagent (r), formula (f)", strings.TrimSpace(s))
+	}
+	return r.String(), nil
+}
+
+// HasConstraint reports whether the reference's version is a constraint
+// that must be resolved to an exact version.
+func (r ComponentRef) HasConstraint() bool {
+	return IsConstraint(r.Version)
+}
+
+// ExpandType returns the full type name for a type or shorthand, e.g. "c" →
+// "catalyst". Other strings are returned unchanged.
+func ExpandType(s string) string {
+	if full, ok := typeShorthands[s]; ok {
+		return full
+	}
+	return s
+}
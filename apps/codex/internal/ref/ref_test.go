@@ -1,6 +1,7 @@
 package ref
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -33,3 +34,198 @@ func TestIsTypePrefix(t *testing.T) {
 		t.Error("expected my-tool NOT to be a type prefix")
 	}
 }
+
+func TestExpandTypePrefix(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"c:local.claude:0.1.0", "catalyst:local.claude:0.1.0"},
+		{"r:local.parser", "reagent:local.parser"},
+		{"f:acme.flow:1.0.0", "formula:acme.flow:1.0.0"},
+		{"catalyst:local.claude:0.1.0", "catalyst:local.claude:0.1.0"},
+		{"local.claude:0.1.0", "local.claude:0.1.0"},
+		{"local.claude", "local.claude"},
+	}
+	for _, tt := range tests {
+		if got := ExpandTypePrefix(tt.input); got != tt.want {
+			t.Errorf("ExpandTypePrefix(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ComponentRef
+	}{
+		{"catalyst:local.my-tool:1.0.0", ComponentRef{"catalyst", "local", "my-tool", "1.0.0", "", ""}},
+		{"c:local.my-tool:1.0.0", ComponentRef{"catalyst", "local", "my-tool", "1.0.0", "", ""}},
+		{"r:acme.parser", ComponentRef{"reagent", "acme", "parser", "latest", "", ""}},
+		{"local.my-tool:1.0.0", ComponentRef{"", "local", "my-tool", "1.0.0", "", ""}},
+		{"my-tool:1.0.0", ComponentRef{"", "local", "my-tool", "1.0.0", "", ""}},
+		{"my-tool", ComponentRef{"", "local", "my-tool", "latest", "", ""}},
+		{"local:my-tool:1.0.0", ComponentRef{"", "local", "my-tool", "1.0.0", "", ""}},
+		{"  f:acme.flow:2.0.0-beta.1 ", ComponentRef{"formula", "acme", "flow", "2.0.0-beta.1", "", ""}},
+		// Version constraints
+		{"c:acme.sentiment:^1.2", ComponentRef{"catalyst", "acme", "sentiment", "^1.2", "", ""}},
+		{"catalyst:acme.sentiment:~1.4.0", ComponentRef{"catalyst", "acme", "sentiment", "~1.4.0", "", ""}},
+		{"c:acme.sentiment:>=1.0.0   <2.0.0", ComponentRef{"catalyst", "acme", "sentiment", ">=1.0.0 <2.0.0", "", ""}},
+		{"sentiment:^1", ComponentRef{"", "local", "sentiment", "^1", "", ""}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "   ", "c:", "local.:1.0.0", ".tool:1.0.0", "local.tool:", "local.tool:1.0.0:extra", "c:r:local.tool:1.0.0", "local.tool:1.0.banana"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"catalyst:local.my-tool:1.0.0", "catalyst:local.my-tool:1.0.0"},
+		{"c:local.my-tool:1.0.0", "catalyst:local.my-tool:1.0.0"},
+		{"r:local.parser", "reagent:local.parser:latest"},
+		{"f:my-flow:1.0.0", "formula:local.my-flow:1.0.0"},
+		{"c:acme.sentiment:^1.2", "catalyst:acme.sentiment:^1.2"},
+		{"c:acme.sentiment: >=1.0.0  <2.0.0", "catalyst:acme.sentiment:>=1.0.0 <2.0.0"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.input)
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	_, err := Normalize("local.my-tool:1.0.0")
+	want := "component ref must include a type prefix (e.g., catalyst:local.my-tool:1.0.0). Valid types: catalyst (c), reagent (r), formula (f)"
+	if err == nil || err.Error() != want {
+		t.Errorf("Normalize untyped: err = %v, want %q", err, want)
+	}
+}
+
+func TestComponentRef_HasConstraint(t *testing.T) {
+	for input, want := range map[string]bool{
+		"c:local.tool:1.0.0":      false,
+		"c:local.tool":            false,
+		"c:local.tool:^1.0":       true,
+		"c:local.tool:>=1.0 <2.0": true,
+	} {
+		r, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		if got := r.HasConstraint(); got != want {
+			t.Errorf("HasConstraint(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestParse_Digest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		input string
+		want  ComponentRef
+		str   string
+	}{
+		{"catalyst:acme.sentiment@" + digest, ComponentRef{"catalyst", "acme", "sentiment", "latest", digest, ""}, "catalyst:acme.sentiment@" + digest},
+		{"c:acme.sentiment:1.2.0@" + digest, ComponentRef{"catalyst", "acme", "sentiment", "1.2.0", digest, ""}, "catalyst:acme.sentiment:1.2.0@" + digest},
+		{"acme.sentiment@" + digest, ComponentRef{"", "acme", "sentiment", "latest", digest, ""}, "acme.sentiment@" + digest},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("String() = %q, want %q", got.String(), tt.str)
+		}
+	}
+
+	r, _ := Parse("c:acme.sentiment:1.2.0@" + digest)
+	if got := r.Unpinned(); got != "catalyst:acme.sentiment:1.2.0" {
+		t.Errorf("Unpinned() = %q", got)
+	}
+
+	for _, bad := range []string{
+		"c:acme.sentiment@sha256:abcd",
+		"c:acme.sentiment@sha256:" + strings.Repeat("AB", 32),
+		"c:acme.sentiment@" + digest + "@" + digest,
+		"@" + digest,
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestSplitDigest(t *testing.T) {
+	base, digest := SplitDigest("c:acme.x:1.0.0@sha256:abc")
+	if base != "c:acme.x:1.0.0" || digest != "sha256:abc" {
+		t.Errorf("got %q, %q", base, digest)
+	}
+	base, digest = SplitDigest("c:acme.x@1.0.0")
+	if base != "c:acme.x@1.0.0" || digest != "" {
+		t.Errorf("got %q, %q", base, digest)
+	}
+}
+
+func TestParse_OCI(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		input string
+		want  ComponentRef
+		str   string
+	}{
+		{"ghcr.io/acme/sentiment:1.0.0", ComponentRef{"", "acme", "sentiment", "1.0.0", "", "ghcr.io"}, "ghcr.io/acme/sentiment:1.0.0"},
+		{"registry.corp/catalysts/foo:2.1.0", ComponentRef{"catalyst", "catalysts", "foo", "2.1.0", "", "registry.corp"}, "catalyst:registry.corp/catalysts/foo:2.1.0"},
+		{"r:localhost:5000/parser", ComponentRef{"reagent", "", "parser", "latest", "", "localhost:5000"}, "reagent:localhost:5000/parser:latest"},
+		{"ghcr.io/acme/tools/sentiment:^1.2", ComponentRef{"", "acme/tools", "sentiment", "^1.2", "", "ghcr.io"}, "ghcr.io/acme/tools/sentiment:^1.2"},
+		{"ghcr.io/acme/sentiment@" + digest, ComponentRef{"", "acme", "sentiment", "latest", digest, "ghcr.io"}, "ghcr.io/acme/sentiment@" + digest},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("String() = %q, want %q", got.String(), tt.str)
+		}
+	}
+
+	for _, bad := range []string{
+		"ghcr.io/Acme/sentiment:1.0.0",
+		"ghcr.io//sentiment",
+		"localhost:port/acme/x",
+		"ghcr.io/acme/-x:1.0.0",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package ref
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNoMatchingVersion is returned by ResolveVersion when no available
+// version satisfies the constraint.
+var ErrNoMatchingVersion = errors.New("no matching version")
+
+// Version is a parsed semantic version.
+type Version struct {
+	Major, Minor, Patch int
+	// Pre is the prerelease, e.g. "beta.1"; empty for a release.
+	Pre string
+	// Build is the build metadata, e.g. "build.5". It is kept for display
+	// but ignored by Compare, as it doesn't affect precedence.
+	Build string
+}
+
+// ParseVersion parses a full semantic version such as "1.2.3",
+// "1.0.0-beta.1" or "1.0.0+build.5".
+func ParseVersion(s string) (Version, error) {
+	v, n, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if n < 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than w
+// in semver precedence.
+func (v Version) Compare(w Version) int {
+	for _, d := range [][2]int{{v.Major, w.Major}, {v.Minor, w.Minor}, {v.Patch, w.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePre(v.Pre, w.Pre)
+}
+
+// comparePre compares prerelease strings: a release outranks any
+// prerelease, and identifiers compare numerically when both are numbers.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case aErr == nil:
+			return -1 // numeric identifiers sort before alphanumeric ones
+		case bErr == nil:
+			return 1
+		case as[i] < bs[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// parsePartial parses a possibly incomplete version such as "1", "1.2",
+// "1.2.x" or "1.2.3-beta", returning how many of major, minor and patch
+// were given. Wildcards (x, X, *) end the given parts.
+func parsePartial(s string) (Version, int, error) {
+	var v Version
+	if s == "" {
+		return v, 0, fmt.Errorf("empty version")
+	}
+	core := s
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		v.Build = core[i+1:]
+		if !validIdentifiers(v.Build) {
+			return v, 0, fmt.Errorf("invalid build metadata in version %q", s)
+		}
+		core = core[:i]
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		v.Pre = core[i+1:]
+		if !validIdentifiers(v.Pre) {
+			return v, 0, fmt.Errorf("invalid prerelease in version %q", s)
+		}
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return v, 0, fmt.Errorf("invalid version %q: too many components", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	n := 0
+	for i, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			// Everything after a wildcard must be a wildcard too.
+			for _, rest := range parts[i+1:] {
+				if rest != "x" && rest != "X" && rest != "*" {
+					return v, 0, fmt.Errorf("invalid version %q", s)
+				}
+			}
+			break
+		}
+		num, err := strconv.Atoi(p)
+		if err != nil || num < 0 || (len(p) > 1 && p[0] == '0') || p[0] == '+' {
+			return v, 0, fmt.Errorf("invalid version %q: %q is not a number", s, p)
+		}
+		*nums[i] = num
+		n++
+	}
+	if v.Pre != "" && n < 3 {
+		return v, 0, fmt.Errorf("invalid version %q: a prerelease needs MAJOR.MINOR.PATCH", s)
+	}
+	return v, n, nil
+}
+
+// validIdentifiers reports whether s is a dot-separated list of non-empty
+// alphanumeric-and-hyphen identifiers, as in semver prereleases.
+func validIdentifiers(s string) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// comparator is a single bound such as ">=1.2.0".
+type comparator struct {
+	op string // one of = > >= < <=
+	v  Version
+}
+
+func (c comparator) matches(v Version) bool {
+	d := v.Compare(c.v)
+	switch c.op {
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	case "<":
+		return d < 0
+	case "<=":
+		return d <= 0
+	}
+	return d == 0
+}
+
+// Constraint is a parsed version constraint: alternatives separated by
+// "||", each a set of comparators that must all match.
+type Constraint struct {
+	sets [][]comparator
+}
+
+// ParseConstraint parses a version constraint. Supported forms:
+//
+//	1.2.3            exactly 1.2.3
+//	1.2, 1.2.x       any 1.2 patch release (>=1.2.0 <1.3.0)
+//	^1.2             compatible with 1.2 (>=1.2.0 <2.0.0; below 1.0.0 the
+//	                 first non-zero part is kept: ^0.2 is >=0.2.0 <0.3.0)
+//	~1.4.0           patch releases of 1.4 (>=1.4.0 <1.5.0)
+//	>=1.0.0 <2.0.0   comparators, all of which must match
+//	^1.0 || ^2.0     alternatives
+//	*, latest        any release
+//
+// Commas may separate comparators as well as spaces.
+func ParseConstraint(s string) (*Constraint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	c := &Constraint{}
+	for _, alt := range strings.Split(s, "||") {
+		var set []comparator
+		tokens := strings.Fields(strings.ReplaceAll(alt, ",", " "))
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty alternative", s)
+		}
+		for i := 0; i < len(tokens); i++ {
+			tok := tokens[i]
+			// Allow a space between an operator and its version: ">= 1.0".
+			if isOperator(tok) && i+1 < len(tokens) {
+				tok += tokens[i+1]
+				i++
+			}
+			cs, err := parseComparator(tok)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			set = append(set, cs...)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+func isOperator(s string) bool {
+	switch s {
+	case "=", ">", ">=", "<", "<=", "^", "~":
+		return true
+	}
+	return false
+}
+
+// parseComparator expands one constraint token into the comparators it
+// stands for.
+func parseComparator(tok string) ([]comparator, error) {
+	if tok == "latest" {
+		return nil, nil
+	}
+	op := ""
+	for _, o := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(tok, o) {
+			op = o
+			break
+		}
+	}
+	v, n, err := parsePartial(strings.TrimPrefix(tok, op))
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil // a wildcard matches anything
+	}
+	// next is the first version above everything tok's given parts cover,
+	// e.g. 1.3.0 for "1.2".
+	next := bump(v, n)
+
+	switch op {
+	case "^":
+		var upper Version
+		switch {
+		case v.Major > 0 || n == 1:
+			upper = Version{Major: v.Major + 1}
+		case v.Minor > 0 || n == 2:
+			upper = Version{Minor: v.Minor + 1}
+		default:
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "~":
+		if n == 1 {
+			return []comparator{{">=", v}, {"<", bump(v, 1)}}, nil
+		}
+		return []comparator{{">=", v}, {"<", bump(v, 2)}}, nil
+	case ">":
+		if n < 3 {
+			return []comparator{{">=", next}}, nil
+		}
+	case "<=":
+		if n < 3 {
+			return []comparator{{"<", next}}, nil
+		}
+	case "", "=":
+		if n < 3 {
+			return []comparator{{">=", v}, {"<", next}}, nil
+		}
+		op = "="
+	}
+	return []comparator{{op, v}}, nil
+}
+
+// bump returns the lowest version above every version whose first n parts
+// equal v's.
+func bump(v Version, n int) Version {
+	switch n {
+	case 1:
+		return Version{Major: v.Major + 1}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// BumpVersion returns the version after s when the part "major", "minor"
+// or "patch" is incremented, e.g. 1.3.0 for "minor" of 1.2.5. The lower
+// parts are reset, and a prerelease or build is dropped.
+func BumpVersion(s, part string) (string, error) {
+	v, err := ParseVersion(s)
+	if err != nil {
+		return "", err
+	}
+	n := map[string]int{"major": 1, "minor": 2, "patch": 3}[part]
+	if n == 0 {
+		return "", fmt.Errorf("invalid part %q: expected major, minor or patch", part)
+	}
+	return bump(v, n).String(), nil
+}
+
+// Check reports whether v satisfies the constraint. Prereleases only
+// match an alternative that names a prerelease of the same
+// MAJOR.MINOR.PATCH, so "^1.0" never picks "1.5.0-beta".
+func (c *Constraint) Check(v Version) bool {
+	for _, set := range c.sets {
+		if matchesAll(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(set []comparator, v Version) bool {
+	prereleaseAllowed := v.Pre == ""
+	for _, cmp := range set {
+		if !cmp.matches(v) {
+			return false
+		}
+		if cmp.v.Pre != "" && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch {
+			prereleaseAllowed = true
+		}
+	}
+	return prereleaseAllowed
+}
+
+// IsConstraint reports whether version is a range rather than an exact
+// version or "latest", i.e. whether it must be resolved against the
+// published versions before use.
+func IsConstraint(version string) bool {
+	if version == "" || version == "latest" {
+		return false
+	}
+	if _, err := ParseVersion(version); err == nil {
+		return false
+	}
+	_, err := ParseConstraint(version)
+	return err == nil
+}
+
+// ResolveVersion returns the highest of the available versions that
+// satisfies constraint. Available versions that aren't valid semver are
+// skipped. "latest" (or an empty constraint) picks the highest release.
+func ResolveVersion(constraint string, available []string) (string, error) {
+	if constraint == "" {
+		constraint = "latest"
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	var best string
+	for _, s := range available {
+		v, err := ParseVersion(s)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if best == "" || Compare(s, best) > 0 {
+			best = s
+		}
+	}
+	if best == "" {
+		if len(available) == 0 {
+			return "", fmt.Errorf("%w for %s: no published versions", ErrNoMatchingVersion, constraint)
+		}
+		return "", fmt.Errorf("%w for %s (available: %s)", ErrNoMatchingVersion, constraint, strings.Join(SortVersions(available), ", "))
+	}
+	return best, nil
+}
+
+// SortVersions returns a sorted copy of vs, in the order of Sort.
+func SortVersions(vs []string) []string {
+	out := append([]string(nil), vs...)
+	Sort(out)
+	return out
+}
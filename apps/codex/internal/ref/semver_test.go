@@ -0,0 +1,204 @@
+package ref
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{"1.2.3", Version{1, 2, 3, "", ""}},
+		{"0.0.0", Version{0, 0, 0, "", ""}},
+		{"1.0.0-beta.1", Version{1, 0, 0, "beta.1", ""}},
+		{"1.0.0-rc-1+build.5", Version{1, 0, 0, "rc-1", "build.5"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.input)
+		if err != nil {
+			t.Errorf("ParseVersion(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.x.3", "a.b.c", "1.2.3-", "1.2.3-beta..1", "1.2.3+"} {
+		if _, err := ParseVersion(bad); err == nil {
+			t.Errorf("ParseVersion(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// Each version is lower than the next.
+	ordered := []string{
+		"0.9.9", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+	a, _ := ParseVersion("1.0.0+build.1")
+	b, _ := ParseVersion("1.0.0+build.2")
+	if a.Compare(b) != 0 {
+		t.Error("build metadata should not affect precedence")
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4", "1.2.3-beta"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.2"}},
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.9"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.9", "2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.8.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^0", []string{"0.0.1", "0.9.0"}, []string{"1.0.0"}},
+		{"~1.4.0", []string{"1.4.0", "1.4.7"}, []string{"1.5.0", "1.3.9"}},
+		{"~1.4", []string{"1.4.0", "1.4.7"}, []string{"1.5.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.0.0 <2.0.0", []string{"1.0.0", "1.9.9"}, []string{"0.9.9", "2.0.0", "2.0.0-beta"}},
+		{">=1.0.0, <2.0.0", []string{"1.5.0"}, []string{"2.0.0"}},
+		{">= 1.0 < 2", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{">1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"<1.2", []string{"1.1.9"}, []string{"1.2.0"}},
+		{"^1.0 || ^3.0", []string{"1.5.0", "3.1.0"}, []string{"2.0.0"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-beta"}},
+		{"latest", []string{"1.0.0"}, []string{"1.0.0-beta"}},
+		{"^1.0.0-beta", []string{"1.0.0-beta", "1.0.0-beta.2", "1.0.0", "1.5.0"}, []string{"1.5.0-beta", "1.0.0-alpha"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", tt.constraint, err)
+			continue
+		}
+		for _, s := range tt.matches {
+			v, _ := ParseVersion(s)
+			if !c.Check(v) {
+				t.Errorf("%q should match %s", tt.constraint, s)
+			}
+		}
+		for _, s := range tt.rejects {
+			v, _ := ParseVersion(s)
+			if c.Check(v) {
+				t.Errorf("%q should not match %s", tt.constraint, s)
+			}
+		}
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	for _, bad := range []string{"", "^", ">=", "^1.x.2", "1.2.3.4", "^1.0 ||", ">=abc", "~>1.0", "1.2-beta"} {
+		if _, err := ParseConstraint(bad); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestIsConstraint(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"1.2.3", false},
+		{"1.0.0-beta.1", false},
+		{"latest", false},
+		{"", false},
+		{"^1.2", true},
+		{"~1.4.0", true},
+		{">=1.0.0 <2.0.0", true},
+		{"1.2", true},
+		{"*", true},
+		{"not-a-version", false},
+	}
+	for _, tt := range tests {
+		if got := IsConstraint(tt.input); got != tt.want {
+			t.Errorf("IsConstraint(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	available := []string{"0.9.0", "1.0.0", "1.2.0", "1.4.1", "1.4.3", "1.10.0", "2.0.0-beta.1", "2.0.0", "garbage"}
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"^1.2", "1.10.0"},
+		{"~1.4.0", "1.4.3"},
+		{">=1.0.0 <2.0.0", "1.10.0"},
+		{"^0.9", "0.9.0"},
+		{"1.2.0", "1.2.0"},
+		{"latest", "2.0.0"},
+		{"", "2.0.0"},
+		{">=2.0.0-beta", "2.0.0"},
+		{"2.0.0-beta.1", "2.0.0-beta.1"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveVersion(tt.constraint, available)
+		if err != nil {
+			t.Errorf("ResolveVersion(%q): %v", tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveVersion(%q) = %q, want %q", tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestResolveVersion_NoMatch(t *testing.T) {
+	_, err := ResolveVersion("^3.0", []string{"2.0.0", "1.0.0"})
+	if !errors.Is(err, ErrNoMatchingVersion) {
+		t.Fatalf("err = %v, want ErrNoMatchingVersion", err)
+	}
+	if want := "no matching version for ^3.0 (available: 1.0.0, 2.0.0)"; err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+
+	_, err = ResolveVersion("^1.0", nil)
+	if !errors.Is(err, ErrNoMatchingVersion) {
+		t.Errorf("err = %v, want ErrNoMatchingVersion", err)
+	}
+
+	if _, err := ResolveVersion("^x.y", []string{"1.0.0"}); err == nil || errors.Is(err, ErrNoMatchingVersion) {
+		t.Errorf("invalid constraint: err = %v", err)
+	}
+}
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct{ version, part, want string }{
+		{"1.2.5", "patch", "1.2.6"},
+		{"1.2.5", "minor", "1.3.0"},
+		{"1.2.5", "major", "2.0.0"},
+		{"0.1.0-beta.2+build.7", "patch", "0.1.1"},
+	}
+	for _, tt := range tests {
+		got, err := BumpVersion(tt.version, tt.part)
+		if err != nil || got != tt.want {
+			t.Errorf("BumpVersion(%q, %q) = %q, %v, want %q", tt.version, tt.part, got, err, tt.want)
+		}
+	}
+	for _, bad := range [][2]string{{"1.2", "patch"}, {"1.2.3", "build"}} {
+		if _, err := BumpVersion(bad[0], bad[1]); err == nil {
+			t.Errorf("BumpVersion(%q, %q) succeeded, want error", bad[0], bad[1])
+		}
+	}
+}
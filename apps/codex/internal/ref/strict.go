@@ -0,0 +1,45 @@
+package ref
+
+import "strings"
+
+// ParseStrict is like Parse but accepts only the canonical typed form,
+// type:namespace.name[:version] (or a typed OCI registry reference). The
+// legacy colon-separated form, bare names without a namespace and
+// references without a type, which Parse accepts for compatibility, are
+// rejected with a *SyntaxError suggesting the canonical spelling.
+func ParseStrict(s string) (ComponentRef, error) {
+	r, err := Parse(s)
+	if err != nil {
+		return ComponentRef{}, err
+	}
+	full := strings.TrimSpace(s)
+	body, _ := SplitDigest(full)
+	offset := 0
+	if prefix, rest, ok := strings.Cut(body, ":"); ok && IsTypePrefix(prefix) {
+		body, offset = rest, len(prefix)+1
+	}
+
+	if host, _, ok := strings.Cut(body, "/"); !ok || !isRegistryHost(host) {
+		before, _, _ := strings.Cut(body, ":")
+		switch {
+		case strings.Count(body, ":") == 2 && !strings.Contains(before, "."):
+			return ComponentRef{}, syntaxError(full, offset, "legacy colon-separated format is not allowed in strict mode; write %s", canonicalHint(r))
+		case !strings.Contains(before, "."):
+			return ComponentRef{}, syntaxError(full, offset, "namespace is required in strict mode; write %s", canonicalHint(r))
+		}
+	}
+	if r.Type == "" {
+		return ComponentRef{}, syntaxError(full, 0, "type prefix is required in strict mode; write %s", canonicalHint(r))
+	}
+	return r, nil
+}
+
+// canonicalHint spells r in the form ParseStrict accepts, for error
+// messages.
+func canonicalHint(r ComponentRef) string {
+	if r.Type != "" {
+		return r.String()
+	}
+	r.Type = "catalyst"
+	return r.String() + " (or reagent:, formula:)"
+}
@@ -0,0 +1,50 @@
+package ref
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStrict(t *testing.T) {
+	for _, ok := range []string{
+		"catalyst:local.my-tool:1.0.0",
+		"c:acme.sentiment:^1.2",
+		"r:acme.parser",
+		"c:ghcr.io/acme/sentiment:1.0.0",
+		"f:acme.flow@sha256:" + strings.Repeat("ab", 32),
+	} {
+		if _, err := ParseStrict(ok); err != nil {
+			t.Errorf("ParseStrict(%q): %v", ok, err)
+		}
+	}
+
+	tests := []struct {
+		input  string
+		column int
+		hint   string
+	}{
+		{"c:local:my-tool:1.0.0", 3, "legacy colon-separated format is not allowed in strict mode; write catalyst:local.my-tool:1.0.0"},
+		{"local:my-tool:1.0.0", 1, "write catalyst:local.my-tool:1.0.0 (or reagent:, formula:)"},
+		{"r:my-tool:1.0.0", 3, "namespace is required in strict mode; write reagent:local.my-tool:1.0.0"},
+		{"my-tool", 1, "namespace is required"},
+		{"acme.sentiment:1.0.0", 1, "type prefix is required in strict mode; write catalyst:acme.sentiment:1.0.0 (or reagent:, formula:)"},
+		{"ghcr.io/acme/sentiment:1.0.0", 1, "type prefix is required"},
+	}
+	for _, tt := range tests {
+		_, err := ParseStrict(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseStrict(%q) = %v, want a *SyntaxError", tt.input, err)
+			continue
+		}
+		if syntaxErr.Column() != tt.column || !strings.Contains(syntaxErr.Msg, tt.hint) {
+			t.Errorf("ParseStrict(%q) = %v, want column %d and %q", tt.input, err, tt.column, tt.hint)
+		}
+	}
+
+	// Malformed references fail as in Parse.
+	if _, err := ParseStrict("c:local.My-tool"); err == nil || !strings.Contains(err.Error(), "lowercase") {
+		t.Errorf("ParseStrict of a malformed ref = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package ref
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is how many near matches Suggest returns at most.
+const maxSuggestions = 3
+
+// FromPath derives a reference from a component's path in the canonical
+// layout, components/{type}s/{namespace}/{name}/{version}/{type}.wasm, like
+// Sanctum.ComponentRef.from_path. The path may also be the component's
+// version directory, without the file name. The derived reference is
+// validated like a parsed one.
+func FromPath(path string) (ComponentRef, error) {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	if strings.HasSuffix(parts[len(parts)-1], ".wasm") {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) >= 4 {
+		parts = parts[len(parts)-4:]
+		if typ, ok := typeDirs[parts[0]]; ok {
+			r := ComponentRef{Type: typ, Namespace: parts[1], Name: parts[2], Version: parts[3]}
+			if _, err := Parse(r.String()); err != nil {
+				return ComponentRef{}, fmt.Errorf("cannot derive component ref from path %s: %w", path, err)
+			}
+			if IsTag(r.Version) {
+				// Version directories hold versions; tags only name them.
+				return ComponentRef{}, fmt.Errorf("cannot derive component ref from path %s: %q is not a version", path, r.Version)
+			}
+			return r, nil
+		}
+	}
+	return ComponentRef{}, fmt.Errorf("cannot derive component ref from path: %s (expected components/{catalysts,reagents,formulas}/{namespace}/{name}/{version}/[{type}.wasm])", path)
+}
+
+// Suggest returns up to three of the candidates that are close to r, best
+// first, for "did you mean" hints when r can't be found. Closeness is the
+// edit distance between namespace.name pairs, plus one for a different
+// type or, when r names an exact version, a different version. Candidates
+// identical to r are skipped, as r was already tried.
+func Suggest(r ComponentRef, candidates []ComponentRef) []ComponentRef {
+	target := r.Namespace + "." + r.Name
+	limit := max(2, len(target)/4)
+	exact := r.Version != "latest" && !IsConstraint(r.Version)
+
+	type scored struct {
+		ref   ComponentRef
+		score int
+	}
+	var matches []scored
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		key := c.String()
+		if seen[key] || key == r.String() {
+			continue
+		}
+		seen[key] = true
+
+		score := editDistance(target, c.Namespace+"."+c.Name)
+		if r.Type != "" && c.Type != "" && r.Type != c.Type {
+			score++
+		}
+		if exact && c.Version != r.Version {
+			score++
+		}
+		if score > limit {
+			continue
+		}
+		matches = append(matches, scored{c, score})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].ref.String() < matches[j].ref.String()
+	})
+
+	var out []ComponentRef
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		out = append(out, m.ref)
+	}
+	return out
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
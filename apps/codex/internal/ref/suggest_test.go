@@ -0,0 +1,94 @@
+package ref
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromPath(t *testing.T) {
+	got, err := FromPath("components/catalysts/local/claude/0.1.0/catalyst.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ComponentRef{Type: "catalyst", Namespace: "local", Name: "claude", Version: "0.1.0"}
+	if got != want {
+		t.Errorf("FromPath = %+v, want %+v", got, want)
+	}
+
+	for _, path := range []string{"components/catalysts/local/claude/0.1.0", "./components/catalysts/local/claude/0.1.0/", "/src/project/components/catalysts/local/claude/0.1.0/catalyst.wasm"} {
+		if got, err := FromPath(path); err != nil || got != want {
+			t.Errorf("FromPath(%q) = %+v, %v; want %+v", path, got, err, want)
+		}
+	}
+
+	for _, bad := range []string{
+		"catalyst.wasm",
+		"components/widgets/local/claude/0.1.0/catalyst.wasm",
+		"local/claude/0.1.0/catalyst.wasm",
+		"components/catalysts/local/Claude/0.1.0/catalyst.wasm",
+		"components/catalysts/local/claude/v1/catalyst.wasm",
+	} {
+		if _, err := FromPath(bad); err == nil {
+			t.Errorf("FromPath(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	mustParse := func(s string) ComponentRef {
+		t.Helper()
+		r, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	candidates := []ComponentRef{
+		mustParse("catalyst:local.claude:0.1.0"),
+		mustParse("catalyst:local.gemini:0.1.0"),
+		mustParse("catalyst:local.openai:0.1.0"),
+		mustParse("formula:local.list-models:0.1.0"),
+		mustParse("catalyst:local.claude:0.1.0"), // duplicate
+	}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"catalyst:local.cluade:0.1.0", []string{"catalyst:local.claude:0.1.0"}},
+		{"c:local.claude", []string{"catalyst:local.claude:0.1.0"}},
+		{"catalyst:local.claude:0.1.0", nil}, // already tried
+		{"c:local.claude:0.2.0", []string{"catalyst:local.claude:0.1.0"}},
+		{"r:local.claude:0.1.0", []string{"catalyst:local.claude:0.1.0"}},
+		{"f:local.list-model", []string{"formula:local.list-models:0.1.0"}},
+		{"c:acme.sentiment", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range Suggest(mustParse(tt.input), candidates) {
+			got = append(got, r.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"claude", "claude", 0},
+		{"claude", "cluade", 2},
+		{"claude", "claud", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
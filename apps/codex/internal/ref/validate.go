@@ -0,0 +1,140 @@
+package ref
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSegmentLen is the longest namespace or name Sanctum accepts.
+const maxSegmentLen = 64
+
+// SyntaxError describes a malformed component reference and where in it
+// the problem is.
+type SyntaxError struct {
+	// Ref is the reference, with surrounding whitespace removed.
+	Ref string
+	// Offset is the byte offset of the problem in Ref.
+	Offset int
+	Msg    string
+}
+
+// Column returns the 1-based column of the problem in Ref.
+func (e *SyntaxError) Column() int {
+	return utf8.RuneCountInString(e.Ref[:e.Offset]) + 1
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid component ref %q at column %d: %s", e.Ref, e.Column(), e.Msg)
+}
+
+// Pointer returns the reference with a caret under the problem, for
+// display below the error message:
+//
+//	c:local.Claude:0.1.0
+//	        ^
+func (e *SyntaxError) Pointer() string {
+	return e.Ref + "\n" + strings.Repeat(" ", e.Column()-1) + "^"
+}
+
+func syntaxError(ref string, offset int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Ref: ref, Offset: min(offset, len(ref)), Msg: fmt.Sprintf(format, args...)}
+}
+
+// checkSegment validates a namespace or name found at offset in ref.
+func checkSegment(ref, what, seg string, offset int) error {
+	if seg == "" {
+		return syntaxError(ref, offset, "missing %s", what)
+	}
+	for i, c := range seg {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-':
+		case c >= 'A' && c <= 'Z':
+			return syntaxError(ref, offset+i, "%s must be lowercase, found %q", what, c)
+		default:
+			return syntaxError(ref, offset+i, "invalid character %q in %s (use lowercase letters, digits and hyphens)", c, what)
+		}
+	}
+	if seg[0] == '-' {
+		return syntaxError(ref, offset, "%s cannot start with a hyphen", what)
+	}
+	if seg[len(seg)-1] == '-' {
+		return syntaxError(ref, offset+len(seg)-1, "%s cannot end with a hyphen", what)
+	}
+	if len(seg) > maxSegmentLen {
+		return syntaxError(ref, offset+maxSegmentLen, "%s must be at most %d characters", what, maxSegmentLen)
+	}
+	return nil
+}
+
+// checkVersion validates a version, "latest" or version constraint found
+// at offset in ref.
+func checkVersion(ref, version string, offset int) error {
+	if strings.TrimSpace(version) == "" {
+		return syntaxError(ref, offset, "missing version")
+	}
+	for i, c := range version {
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case strings.ContainsRune(".+-^~<>=*|, ", c):
+		default:
+			return syntaxError(ref, offset+i, "invalid character %q in version", c)
+		}
+	}
+	if strings.TrimSpace(version) == "latest" || IsTag(version) {
+		return nil
+	}
+	if _, err := ParseConstraint(version); err != nil {
+		return syntaxError(ref, offset+len(version)-len(strings.TrimLeft(version, " ")),
+			"version must be valid semver (e.g., 1.0.0), a constraint (e.g., ^1.0), a tag (e.g., stable), or 'latest'")
+	}
+	return nil
+}
+
+// IsTag reports whether version names a tag, such as "stable", that the
+// registry maps to a version: a word of lowercase letters, digits and
+// hyphens starting with a letter, other than "latest" and the wildcards.
+func IsTag(version string) bool {
+	return ValidateTag(version) == nil
+}
+
+// ValidateTag checks that name can be used as a tag.
+func ValidateTag(name string) error {
+	if err := checkSegment(name, "tag", name, 0); err != nil {
+		return fmt.Errorf("invalid tag %q: %s", name, err.(*SyntaxError).Msg)
+	}
+	if name[0] < 'a' || name[0] > 'z' {
+		return fmt.Errorf("invalid tag %q: it must start with a letter", name)
+	}
+	if name == "latest" || IsConstraint(name) {
+		return fmt.Errorf("invalid tag %q: it reads as a version", name)
+	}
+	return nil
+}
+
+// ValidateNamespace checks that name can be claimed as a registry
+// namespace. "local" is reserved for components that were never published.
+func ValidateNamespace(name string) error {
+	if err := checkSegment(name, "namespace", name, 0); err != nil {
+		return fmt.Errorf("invalid namespace %q: %s", name, err.(*SyntaxError).Msg)
+	}
+	if name == "local" {
+		return fmt.Errorf("invalid namespace %q: it is reserved for unpublished components", name)
+	}
+	return nil
+}
+
+// checkDigest validates a digest pin found at offset in ref.
+func checkDigest(ref, digest string, offset int) error {
+	hex := strings.TrimPrefix(digest, DigestAlgorithm)
+	hexAt := offset + len(DigestAlgorithm)
+	for i, c := range hex {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return syntaxError(ref, hexAt+i, "invalid character %q in digest (use lowercase hex digits)", c)
+		}
+	}
+	if len(hex) != 64 {
+		return syntaxError(ref, hexAt, "sha256 digest must be 64 hex digits, got %d", len(hex))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package ref
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		input  string
+		column int
+		msg    string
+	}{
+		{"c:local.Claude:0.1.0", 9, `name must be lowercase, found 'C'`},
+		{"c:Local.claude:0.1.0", 3, `namespace must be lowercase, found 'L'`},
+		{"c:local.my_tool:0.1.0", 11, `invalid character '_' in name`},
+		{"c:local.-tool:0.1.0", 9, "name cannot start with a hyphen"},
+		{"c:local.tool-:0.1.0", 13, "name cannot end with a hyphen"},
+		{"c:loc al.tool:0.1.0", 6, `invalid character ' ' in namespace`},
+		{"c:local.tool:0.1.0:extra", 19, `invalid character ':' in version`},
+		{"c:local.tool:1.0.0#2", 19, `invalid character '#' in version`},
+		{"c:local.tool:1.0.banana", 14, "version must be valid semver"},
+		{"c:local.tool:", 14, "missing version"},
+		{"c:.tool:0.1.0", 3, "missing namespace"},
+		{"c:local.:0.1.0", 9, "missing name"},
+		{"c:", 3, "missing component name"},
+		{"c:r:local.tool", 3, `duplicate type prefix "r"`},
+		{"catalist:local.tool:0.1.0", 1, `unknown component type "catalist"`},
+		{"c:local." + strings.Repeat("a", 70) + ":1.0.0", 73, "name must be at most 64 characters"},
+		{"c:local.tool@sha256:abcd", 21, "sha256 digest must be 64 hex digits, got 4"},
+		{"c:local.tool@sha256:" + strings.Repeat("aB", 32), 22, `invalid character 'B' in digest`},
+		{"c:local.Tool@" + digest, 9, "name must be lowercase"},
+		{"c:café.tool:1.0.0", 6, `invalid character 'é' in namespace`},
+		{"c:local.tool:1.0.é", 18, `invalid character 'é' in version`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q): err = %v, want *SyntaxError", tt.input, err)
+			continue
+		}
+		if se.Column() != tt.column {
+			t.Errorf("Parse(%q): column %d, want %d (%v)", tt.input, se.Column(), tt.column, err)
+		}
+		if !strings.Contains(se.Msg, tt.msg) {
+			t.Errorf("Parse(%q): msg %q, want it to contain %q", tt.input, se.Msg, tt.msg)
+		}
+	}
+}
+
+func TestSyntaxError_Pointer(t *testing.T) {
+	_, err := Parse("  c:local.Claude:0.1.0 ")
+	var se *SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf("err = %v", err)
+	}
+	want := "c:local.Claude:0.1.0\n        ^"
+	if got := se.Pointer(); got != want {
+		t.Errorf("Pointer() =\n%s\nwant\n%s", got, want)
+	}
+	wantErr := `invalid component ref "c:local.Claude:0.1.0" at column 9: name must be lowercase, found 'C'`
+	if err.Error() != wantErr {
+		t.Errorf("Error() = %q, want %q", err, wantErr)
+	}
+}
+
+func TestParse_ValidSegments(t *testing.T) {
+	for _, s := range []string{
+		"c:a.b:1.0.0",
+		"c:local.list-models:0.1.0",
+		"c:cyfr.json-transform2:1.0.0-beta.1+build.7",
+		"c:local." + strings.Repeat("a", 64) + ":1.0.0",
+	} {
+		if _, err := Parse(s); err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+		}
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"stable", "beta", "lts-2", "canary"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q): %v", tag, err)
+		}
+		if _, err := Parse("c:local.tool:" + tag); err != nil {
+			t.Errorf("Parse with tag %q: %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "latest", "1.0.0", "2", "x", "Stable", "-beta", "beta-", "rc.1", "2-beta"} {
+		if err := ValidateTag(tag); err == nil {
+			t.Errorf("ValidateTag(%q) = nil, want an error", tag)
+		}
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	for _, ns := range []string{"acme", "acme-labs", "team2"} {
+		if err := ValidateNamespace(ns); err != nil {
+			t.Errorf("ValidateNamespace(%q): %v", ns, err)
+		}
+	}
+	for _, ns := range []string{"", "local", "Acme", "-acme", "acme-", "acme.labs", strings.Repeat("a", 65)} {
+		if err := ValidateNamespace(ns); err == nil {
+			t.Errorf("ValidateNamespace(%q) = nil, want an error", ns)
+		}
+	}
+}
@@ -2,27 +2,78 @@ package scaffold
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
 
 const (
-	urlTemplate    = "https://github.com/cyfrworks/cyfr/releases/download/v%s/cyfr-scaffold.tar.gz"
 	maxFileSize    = 10 << 20 // 10 MB per file
 	requestTimeout = 60 * time.Second
 )
 
+// DefaultReleaseURL is where release assets are downloaded from unless a
+// mirror is configured.
+const DefaultReleaseURL = "https://github.com/cyfrworks/cyfr/releases"
+
+// ReleaseURL is where release assets are downloaded from: DefaultReleaseURL
+// or a mirror laid out the same way, with assets at
+// download/v<version>/<asset> and latest/download/<asset> under it. A
+// file:// URL serves a mirror from a directory.
+var ReleaseURL = DefaultReleaseURL
+
+// ChecksumURL is the release URL checksums.txt is fetched from to verify
+// downloads. It is kept apart from ReleaseURL so that a mirror named by a
+// project can't vouch for its own files: only DefaultReleaseURL or a
+// mirror the user configured is trusted for checksums.
+var ChecksumURL = DefaultReleaseURL
+
+// scaffoldAsset is the release asset with the scaffold.
+const scaffoldAsset = "cyfr-scaffold.tar.gz"
+
+// httpClient returns a client for release assets, which also reads file://
+// URLs.
+func httpClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// Languages are the languages with a component template.
+var Languages = []string{"rust", "go", "python", "js"}
+
+// assetURL returns the URL of a release asset for the given version, or of
+// the latest release for version "dev" or "".
+func assetURL(version, asset string) string {
+	return releaseAssetURL(ReleaseURL, version, asset)
+}
+
+// releaseAssetURL is assetURL under the release URL base.
+func releaseAssetURL(base, version, asset string) string {
+	if version == "dev" || version == "" {
+		return strings.TrimSuffix(base, "/") + "/latest/download/" + asset
+	}
+	return fmt.Sprintf("%s/download/v%s/%s", strings.TrimSuffix(base, "/"), version, asset)
+}
+
 // Download fetches the scaffold tarball for the given version and extracts it
 // into the current working directory. Files that already exist on disk are
 // skipped (idempotent). Version "dev" or "" is a no-op.
 func Download(version string) error {
-	return extract(version, false)
+	return extract(version, false, true)
+}
+
+// DownloadWithoutExamples is Download without the example components, for
+// projects that start from their own.
+func DownloadWithoutExamples(version string) error {
+	return extract(version, false, false)
 }
 
 // Update fetches the scaffold tarball for the given version and extracts it
@@ -30,7 +81,7 @@ func Download(version string) error {
 // are overwritten with the latest content. Component files that already exist
 // are skipped; new components are created. Version "dev" or "" is a no-op.
 func Update(version string) error {
-	return extract(version, true)
+	return extract(version, true, true)
 }
 
 // isManaged returns true for files that are maintained by cyfr and should be
@@ -47,95 +98,183 @@ func isManaged(path string) bool {
 	return false
 }
 
+// userOwned returns true for the project files cyfr init generates once and
+// leaves to the user, which nothing in a tarball may create or replace.
+func userOwned(path string) bool {
+	switch path {
+	case "docker-compose.yml", "docker-compose.override.yml", ".env":
+		return true
+	}
+	return false
+}
+
+// Template fetches the component template for lang — starter code, WIT
+// bindings and a build script — for the given version and extracts it into
+// dir, filling in the component's name and type where the template has
+// {{name}} and {{type}}. Files that already exist are skipped. Version
+// "dev" or "" uses the latest release.
+func Template(version, lang, dir, name, compType string) error {
+	if !slices.Contains(Languages, lang) {
+		return fmt.Errorf("no template for %q (available: %s)", lang, strings.Join(Languages, ", "))
+	}
+	data, err := fetchVerified(version, "cyfr-template-"+lang+".tar.gz")
+	if err != nil {
+		return err
+	}
+	fill := strings.NewReplacer("{{name}}", name, "{{type}}", compType)
+	return extractFrom(data, dir, nil, nil, fill)
+}
+
+// isExample returns true for the example components in the scaffold.
+func isExample(path string) bool {
+	return strings.HasPrefix(path, "components/")
+}
+
 // extract fetches the scaffold tarball and extracts it. When overwriteManaged
 // is true, managed files are replaced with the tarball contents; other files
-// retain the existing skip-if-exists behavior.
-func extract(version string, overwriteManaged bool) error {
+// retain the existing skip-if-exists behavior. Without examples, the example
+// components are left out.
+func extract(version string, overwriteManaged, examples bool) error {
 	if version == "dev" || version == "" {
 		return nil
 	}
-
-	url := fmt.Sprintf(urlTemplate, version)
-
-	client := &http.Client{Timeout: requestTimeout}
-	resp, err := client.Get(url)
+	data, err := fetchVerified(version, scaffoldAsset)
 	if err != nil {
-		return fmt.Errorf("download scaffold: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
+	return extractScaffold(data, overwriteManaged, examples)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download scaffold: HTTP %d from %s", resp.StatusCode, url)
+// ExtractFile extracts the scaffold tarball at path into the current
+// working directory as Download does, for machines that can't reach the
+// releases. It is verified against the checksums.txt next to it.
+func ExtractFile(path string, examples bool) error {
+	data, err := readVerified(path)
+	if err != nil {
+		return err
 	}
+	return extractScaffold(data, false, examples)
+}
 
-	gr, err := gzip.NewReader(resp.Body)
-	if err != nil {
-		return fmt.Errorf("decompress scaffold: %w", err)
+// extractScaffold extracts the scaffold tarball data into the current
+// working directory, as extract describes.
+func extractScaffold(data []byte, overwriteManaged, examples bool) error {
+	var managed, include func(string) bool
+	if overwriteManaged {
+		managed = isManaged
+	}
+	if !examples {
+		include = func(path string) bool { return !isExample(path) }
+	}
+	if err := extractFrom(data, ".", include, managed, nil); err != nil {
+		return err
 	}
-	defer gr.Close()
 
-	tr := tar.NewReader(gr)
-	for {
-		hdr, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("read scaffold tar: %w", err)
-		}
+	// Ensure component subdirs exist even when tarball has no reagent examples yet.
+	_ = os.MkdirAll("components/reagents/local", 0755)
 
-		name := filepath.Clean(hdr.Name)
+	return nil
+}
 
-		// Path traversal protection: reject absolute paths and ".." components.
-		if filepath.IsAbs(name) || strings.HasPrefix(name, "..") || strings.Contains(name, string(filepath.Separator)+"..") {
-			continue
-		}
+// extractFrom extracts the verified tarball data into dir: the entries
+// include returns true for, or all if it is nil. Files for which
+// managed returns true are replaced; other files that already exist are
+// skipped. If fill is set, it is applied to the contents of every file.
+func extractFrom(data []byte, dir string, include, managed func(string) bool, fill *strings.Replacer) error {
+	return walkTar(data, include, func(name string, hdr *tar.Header, r io.Reader) error {
+		path := filepath.Join(dir, name)
 
 		switch hdr.Typeflag {
 		case tar.TypeDir:
-			if err := os.MkdirAll(name, 0755); err != nil {
-				return fmt.Errorf("mkdir %s: %w", name, err)
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return fmt.Errorf("mkdir %s: %w", path, err)
 			}
 
 		case tar.TypeReg:
-			managed := overwriteManaged && isManaged(name)
+			replace := managed != nil && managed(filepath.ToSlash(name))
 
 			// Skip non-managed files that already exist (idempotent).
-			if !managed {
-				if _, err := os.Stat(name); err == nil {
-					continue
+			if !replace {
+				if _, err := os.Stat(path); err == nil {
+					return nil
 				}
 			}
 
-			if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
-				return fmt.Errorf("mkdir parent %s: %w", name, err)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fmt.Errorf("mkdir parent %s: %w", path, err)
 			}
 
 			var flags int
-			if managed {
+			if replace {
 				flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
 			} else {
 				flags = os.O_CREATE | os.O_WRONLY | os.O_EXCL
 			}
 
-			f, err := os.OpenFile(name, flags, os.FileMode(hdr.Mode)&0755|0644)
+			f, err := os.OpenFile(path, flags, os.FileMode(hdr.Mode)&0755|0644)
 			if err != nil {
 				if os.IsExist(err) {
-					continue // race: created between Stat and OpenFile
+					return nil // race: created between Stat and OpenFile
 				}
-				return fmt.Errorf("create %s: %w", name, err)
+				return fmt.Errorf("create %s: %w", path, err)
 			}
 
-			if _, err := io.Copy(f, io.LimitReader(tr, maxFileSize)); err != nil {
+			if err := writeFile(f, io.LimitReader(r, maxFileSize), fill); err != nil {
 				f.Close()
-				return fmt.Errorf("write %s: %w", name, err)
+				return fmt.Errorf("write %s: %w", path, err)
 			}
 			f.Close()
 		}
+		return nil
+	})
+}
+
+// walkTar calls fn with each entry of the tarball data that include returns
+// true for, or every entry if it is nil, and its contents. Entries with
+// absolute paths or paths leaving the tarball are skipped.
+func walkTar(data []byte, include func(string) bool, fn func(name string, hdr *tar.Header, r io.Reader) error) error {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decompress scaffold: %w", err)
 	}
+	defer gr.Close()
 
-	// Ensure component subdirs exist even when tarball has no reagent examples yet.
-	_ = os.MkdirAll("components/reagents/local", 0755)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read scaffold tar: %w", err)
+		}
 
-	return nil
+		name := filepath.Clean(hdr.Name)
+
+		// Path traversal protection: reject absolute paths and ".." components.
+		if filepath.IsAbs(name) || strings.HasPrefix(name, "..") || strings.Contains(name, string(filepath.Separator)+"..") {
+			continue
+		}
+
+		if userOwned(filepath.ToSlash(name)) || include != nil && !include(filepath.ToSlash(name)) {
+			continue
+		}
+		if err := fn(name, hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// writeFile copies r to w, applying fill to the contents if it is set.
+func writeFile(w io.Writer, r io.Reader, fill *strings.Replacer) error {
+	if fill == nil {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = fill.WriteString(w, string(data))
+	return err
 }
@@ -0,0 +1,330 @@
+package scaffold
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// tarball returns a gzipped tar of files.
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+// fakeRelease serves assets as every release, with a checksums.txt listing
+// them, and returns the path of the last asset requested.
+func fakeRelease(t *testing.T, assets map[string][]byte) *string {
+	t.Helper()
+	requested := new(string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(r.URL.Path)
+		if name == checksumsAsset {
+			for name, data := range assets {
+				fmt.Fprintf(w, "%x  %s\n", sha256.Sum256(data), name)
+			}
+			return
+		}
+		*requested = r.URL.Path
+		data, ok := assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	setReleaseURL(t, srv.URL)
+	return requested
+}
+
+// setReleaseURL points ReleaseURL and ChecksumURL at url for the test.
+func setReleaseURL(t *testing.T, url string) {
+	t.Helper()
+	release, checksums := ReleaseURL, ChecksumURL
+	ReleaseURL, ChecksumURL = url, url
+	t.Cleanup(func() { ReleaseURL, ChecksumURL = release, checksums })
+}
+
+func TestTemplate(t *testing.T) {
+	data := tarball(t, map[string]string{
+		"go.mod":       "module {{name}}\n",
+		"build.sh":     "tinygo build -o {{type}}.wasm\n",
+		"../escape.go": "package escape\n",
+	})
+	requested := fakeRelease(t, map[string][]byte{"cyfr-template-go.tar.gz": data})
+
+	dir := filepath.Join(t.TempDir(), "sentiment", "0.1.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "build.sh"), []byte("custom\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Template("1.2.0", "go", dir, "sentiment", "catalyst"); err != nil {
+		t.Fatal(err)
+	}
+	if *requested != "/download/v1.2.0/cyfr-template-go.tar.gz" {
+		t.Errorf("requested %s", *requested)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "go.mod")); string(got) != "module sentiment\n" {
+		t.Errorf("go.mod = %q, want the name filled in", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "build.sh")); string(got) != "custom\n" {
+		t.Errorf("existing build.sh replaced: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "escape.go")); err == nil {
+		t.Error("template wrote outside its directory")
+	}
+
+	if err := Template("dev", "go", t.TempDir(), "x", "reagent"); err != nil {
+		t.Fatal(err)
+	}
+	if *requested != "/latest/download/cyfr-template-go.tar.gz" {
+		t.Errorf("dev build requested %s, want the latest release", *requested)
+	}
+
+	if err := Template("1.2.0", "cobol", dir, "x", "catalyst"); err == nil {
+		t.Error("expected an error for a language without a template")
+	}
+}
+
+func TestDownloadServer(t *testing.T) {
+	assets := map[string][]byte{ServerAsset(): []byte("#!/bin/sh\n")}
+	requested := fakeRelease(t, assets)
+
+	bin := filepath.Join(t.TempDir(), "bin", "cyfr-server")
+	if err := DownloadServer("1.2.0", bin); err != nil {
+		t.Fatal(err)
+	}
+	if *requested != "/download/v1.2.0/"+ServerAsset() {
+		t.Errorf("requested %s", *requested)
+	}
+	info, err := os.Stat(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("server binary mode %v isn't executable", info.Mode())
+	}
+
+	delete(assets, ServerAsset())
+	if err := DownloadServer("1.2.0", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a release without a server binary")
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(bin)); len(entries) != 1 {
+		t.Errorf("download left %d files behind", len(entries))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := tarball(t, map[string]string{"cyfr.yaml": "name: x\n"})
+	good := sha256.Sum256(data)
+	fakeRelease(t, map[string][]byte{"cyfr-scaffold.tar.gz": data})
+
+	if err := verify("1.2.0", "cyfr-scaffold.tar.gz", good[:]); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+	tampered := sha256.Sum256(append(data, 0))
+	if err := verify("1.2.0", "cyfr-scaffold.tar.gz", tampered[:]); !errors.Is(err, ErrUnverified) {
+		t.Errorf("verify of a tampered download: %v", err)
+	}
+	if err := verify("1.2.0", "cyfr-scaffold-other.tar.gz", good[:]); !errors.Is(err, ErrUnverified) {
+		t.Errorf("verify of an asset without a checksum: %v", err)
+	}
+
+	// A tampered tarball isn't extracted at all, unless Insecure is set.
+	tamperedData := tarball(t, map[string]string{"go.mod": "module evil\n"})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == checksumsAsset {
+			fmt.Fprintf(w, "%x  cyfr-template-go.tar.gz\n", good)
+			return
+		}
+		w.Write(tamperedData)
+	}))
+	defer srv.Close()
+	setReleaseURL(t, srv.URL)
+	dir := t.TempDir()
+	if err := Template("1.2.0", "go", dir, "x", "catalyst"); !errors.Is(err, ErrUnverified) {
+		t.Errorf("Template of a tampered tarball: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("extracted %d files from a tampered tarball", len(entries))
+	}
+	Insecure = true
+	defer func() { Insecure = false }()
+	if err := Template("1.2.0", "go", dir, "x", "catalyst"); err != nil {
+		t.Errorf("Template with Insecure: %v", err)
+	}
+}
+
+func TestExtractFile(t *testing.T) {
+	data := tarball(t, map[string]string{"cyfr.yaml": "name: x\n", "components/catalysts/local/demo/0.1.0/README.md": "demo\n"})
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cyfr-scaffold.tar.gz")
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	if err := ExtractFile(file, false); !errors.Is(err, ErrUnverified) {
+		t.Errorf("ExtractFile without checksums.txt: %v", err)
+	}
+	sums := fmt.Sprintf("%x  cyfr-scaffold.tar.gz\n", sha256.Sum256(data))
+	if err := os.WriteFile(filepath.Join(dir, checksumsAsset), []byte(sums), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExtractFile(file, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("cyfr.yaml"); err != nil {
+		t.Error("cyfr.yaml wasn't extracted")
+	}
+	if _, err := os.Stat("components/catalysts/local/demo"); err == nil {
+		t.Error("examples were extracted without examples")
+	}
+}
+
+func TestFileMirror(t *testing.T) {
+	data := tarball(t, map[string]string{"go.mod": "module {{name}}\n"})
+	mirror := t.TempDir()
+	release := filepath.Join(mirror, "download", "v1.2.0")
+	if err := os.MkdirAll(release, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(release, "cyfr-template-go.tar.gz"), data, 0644)
+	os.WriteFile(filepath.Join(release, checksumsAsset), []byte(fmt.Sprintf("%x  cyfr-template-go.tar.gz\n", sha256.Sum256(data))), 0644)
+	setReleaseURL(t, "file://"+filepath.ToSlash(mirror)+"/")
+
+	dir := t.TempDir()
+	if err := Template("1.2.0", "go", dir, "mirrored", "catalyst"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "go.mod")); string(got) != "module mirrored\n" {
+		t.Errorf("go.mod = %q", got)
+	}
+}
+
+func TestPendingUpdate(t *testing.T) {
+	data := tarball(t, map[string]string{
+		"wit/cyfr.wit":                "interface v2\n",
+		"component-guide.md":          "guide\n",
+		"cyfr.yaml":                   "name: scaffold\n",
+		"components/new/a.txt":        "new\n",
+		"docker-compose.override.yml": "services: {}\n",
+	})
+	fakeRelease(t, map[string][]byte{scaffoldAsset: data})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	os.MkdirAll("wit", 0755)
+	os.WriteFile("wit/cyfr.wit", []byte("interface v1\n"), 0644)
+	os.WriteFile("component-guide.md", []byte("guide\n"), 0644)
+	os.WriteFile("cyfr.yaml", []byte("name: mine\n"), 0644)
+
+	update, err := PrepareUpdate("1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := update.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]FileChange{}
+	for _, c := range changes {
+		got[c.Path] = c
+	}
+	if len(got) != 2 {
+		t.Errorf("changes = %+v, want wit/cyfr.wit and components/new/a.txt", changes)
+	}
+	if c := got["wit/cyfr.wit"]; c.Created || c.Old != "interface v1\n" || c.New != "interface v2\n" {
+		t.Errorf("wit/cyfr.wit change = %+v", c)
+	}
+	if c := got["components/new/a.txt"]; !c.Created {
+		t.Errorf("components/new/a.txt change = %+v", c)
+	}
+
+	if err := Backup(changes, filepath.Join(".cyfr", "backup", "1.1.0")); err != nil {
+		t.Fatal(err)
+	}
+	if old, _ := os.ReadFile(".cyfr/backup/1.1.0/wit/cyfr.wit"); string(old) != "interface v1\n" {
+		t.Errorf("backup = %q", old)
+	}
+	if err := update.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("docker-compose.override.yml"); err == nil {
+		t.Error("the scaffold created docker-compose.override.yml")
+	}
+	if _, err := os.Stat(".cyfr/backup/1.1.0/components"); err == nil {
+		t.Error("new files were backed up")
+	}
+	if err := update.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if now, _ := os.ReadFile("wit/cyfr.wit"); string(now) != "interface v2\n" {
+		t.Errorf("wit/cyfr.wit = %q after Apply", now)
+	}
+	if now, _ := os.ReadFile("cyfr.yaml"); string(now) != "name: mine\n" {
+		t.Errorf("cyfr.yaml replaced: %q", now)
+	}
+}
+
+func TestChecksumURL(t *testing.T) {
+	data := tarball(t, map[string]string{"go.mod": "module {{name}}\n"})
+	fakeRelease(t, map[string][]byte{"cyfr-template-go.tar.gz": data})
+
+	// A mirror serving another tarball, with a checksums.txt to match it,
+	// is only trusted for the tarball.
+	evil := tarball(t, map[string]string{"go.mod": "module evil\n"})
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == checksumsAsset {
+			fmt.Fprintf(w, "%x  cyfr-template-go.tar.gz\n", sha256.Sum256(evil))
+			return
+		}
+		w.Write(evil)
+	}))
+	defer mirror.Close()
+	ReleaseURL = mirror.URL
+
+	dir := t.TempDir()
+	if err := Template("1.2.0", "go", dir, "x", "catalyst"); !errors.Is(err, ErrUnverified) {
+		t.Errorf("Template from a mirror with its own checksums: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("extracted %d files from the mirror's tarball", len(entries))
+	}
+}
@@ -0,0 +1,71 @@
+package scaffold
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// serverTimeout bounds downloading the server binary, which is much larger
+// than the scaffold.
+const serverTimeout = 10 * time.Minute
+
+// ServerAsset returns the name of the release asset with the server binary
+// for this platform, e.g. cyfr-server-linux-amd64.
+func ServerAsset() string {
+	name := fmt.Sprintf("cyfr-server-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// DownloadServer downloads the server binary for this platform of the given
+// version, or of the latest release for version "dev" or "", to path. The
+// file only appears at path once it is complete and matches the release's
+// checksum.
+func DownloadServer(version, path string) error {
+	asset := ServerAsset()
+	url := assetURL(version, asset)
+	client := httpClient(serverTimeout)
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("download server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("download server: no server binary for %s/%s at %s", runtime.GOOS, runtime.GOARCH, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download server: HTTP %d from %s", resp.StatusCode, url)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(path), err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("download server: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := verify(version, asset, h.Sum(nil)); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// A FileChange is a file that updating the scaffold creates or replaces.
+type FileChange struct {
+	Path    string // relative to the project, with slashes
+	Old     string // the current contents, or "" for a new file
+	New     string
+	Created bool
+}
+
+// A PendingUpdate is the scaffold of a version, downloaded and verified, to
+// be previewed before it is applied to the current working directory.
+type PendingUpdate struct {
+	data []byte // nil for a dev build, which updates nothing
+}
+
+// PrepareUpdate fetches the scaffold tarball for the given version for
+// Update, without changing anything yet.
+func PrepareUpdate(version string) (*PendingUpdate, error) {
+	if version == "dev" || version == "" {
+		return &PendingUpdate{}, nil
+	}
+	data, err := fetchVerified(version, scaffoldAsset)
+	if err != nil {
+		return nil, err
+	}
+	return &PendingUpdate{data: data}, nil
+}
+
+// Changes returns the files Apply would create or replace: managed files
+// whose contents differ and files that don't exist yet, in the order of the
+// tarball.
+func (u *PendingUpdate) Changes() ([]FileChange, error) {
+	if u.data == nil {
+		return nil, nil
+	}
+	var changes []FileChange
+	err := walkTar(u.data, nil, func(name string, hdr *tar.Header, r io.Reader) error {
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+		content, err := io.ReadAll(io.LimitReader(r, maxFileSize))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		old, err := os.ReadFile(name)
+		switch {
+		case os.IsNotExist(err):
+			changes = append(changes, FileChange{Path: filepath.ToSlash(name), New: string(content), Created: true})
+		case err != nil:
+			return err
+		case isManaged(filepath.ToSlash(name)) && string(old) != string(content):
+			changes = append(changes, FileChange{Path: filepath.ToSlash(name), Old: string(old), New: string(content)})
+		}
+		return nil
+	})
+	return changes, err
+}
+
+// Apply updates the scaffold in the current working directory, as Update
+// does.
+func (u *PendingUpdate) Apply() error {
+	if u.data == nil {
+		return nil
+	}
+	return extractScaffold(u.data, true, true)
+}
+
+// Backup copies the current contents of the files changes replace into dir,
+// under the same paths.
+func Backup(changes []FileChange, dir string) error {
+	for _, c := range changes {
+		if c.Created {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(c.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("back up %s: %w", c.Path, err)
+		}
+		if err := os.WriteFile(path, []byte(c.Old), 0644); err != nil {
+			return fmt.Errorf("back up %s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package scaffold
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumsAsset is the release asset listing the SHA-256 of every other
+// asset, one "<hex digest>  <asset>" line each, as sha256sum prints them.
+const checksumsAsset = "checksums.txt"
+
+// maxArchiveSize bounds a scaffold or template tarball, which is read into
+// memory to be verified before anything is extracted from it.
+const maxArchiveSize = 100 << 20 // 100 MB
+
+// Insecure turns off verifying downloads against the release's checksums.
+var Insecure bool
+
+// ErrUnverified is wrapped by the errors of downloads that couldn't be
+// verified: their checksum is missing or doesn't match.
+var ErrUnverified = errors.New("download not verified")
+
+// releaseChecksum returns the SHA-256 the release of version lists for
+// asset, as hex. The list comes from ChecksumURL, not ReleaseURL.
+func releaseChecksum(version, asset string) (string, error) {
+	url := releaseAssetURL(ChecksumURL, version, checksumsAsset)
+	resp, err := httpClient(requestTimeout).Get(url)
+	if err != nil {
+		return "", fmt.Errorf("download checksums: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: the release has no %s", ErrUnverified, checksumsAsset)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download checksums: HTTP %d from %s", resp.StatusCode, url)
+	}
+	return findChecksum(resp.Body, asset)
+}
+
+// findChecksum returns the SHA-256 a checksums file lists for asset, as
+// hex.
+func findChecksum(r io.Reader, asset string) (string, error) {
+	scanner := bufio.NewScanner(io.LimitReader(r, 1<<20))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sha256sum marks files hashed in binary mode with a *.
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read checksums: %w", err)
+	}
+	return "", fmt.Errorf("%w: %s has no checksum for %s", ErrUnverified, checksumsAsset, asset)
+}
+
+// verify checks that sum, a SHA-256, is the one the release of version
+// lists for asset. It passes everything if Insecure is set.
+func verify(version, asset string, sum []byte) error {
+	if Insecure {
+		return nil
+	}
+	want, err := releaseChecksum(version, asset)
+	if err != nil {
+		return err
+	}
+	return matchChecksum(asset, sum, want)
+}
+
+// matchChecksum returns an error unless sum is want, the hex SHA-256 listed
+// for asset.
+func matchChecksum(asset string, sum []byte, want string) error {
+	if got := hex.EncodeToString(sum); got != want {
+		return fmt.Errorf("%w: %s has SHA-256 %s, but the release lists %s", ErrUnverified, asset, got, want)
+	}
+	return nil
+}
+
+// fetchVerified downloads asset of the given version and returns it once
+// it is verified.
+func fetchVerified(version, asset string) ([]byte, error) {
+	url := assetURL(version, asset)
+	resp, err := httpClient(requestTimeout).Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("download scaffold: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download scaffold: HTTP %d from %s", resp.StatusCode, url)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download scaffold: %w", err)
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("download scaffold: %s is larger than %d MB", asset, maxArchiveSize>>20)
+	}
+	sum := sha256.Sum256(data)
+	if err := verify(version, asset, sum[:]); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readVerified reads the tarball at path and returns it once it matches the
+// checksum the checksums.txt next to it lists for it.
+func readVerified(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxArchiveSize {
+		return nil, fmt.Errorf("%s is larger than %d MB", path, maxArchiveSize>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if Insecure {
+		return data, nil
+	}
+	sums, err := os.Open(filepath.Join(filepath.Dir(path), checksumsAsset))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: there is no %s next to %s", ErrUnverified, checksumsAsset, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer sums.Close()
+	asset := filepath.Base(path)
+	want, err := findChecksum(sums, asset)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if err := matchChecksum(asset, sum[:], want); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,160 @@
+// Package secretfile reads and writes the passphrase-encrypted files
+// cyfr secret export writes, for moving secrets from one server to
+// another.
+//
+// A file is a JSON envelope holding the secrets, as JSON, sealed with
+// AES-256-GCM under a key derived from the passphrase with
+// PBKDF2-HMAC-SHA256. The envelope's format, version and key derivation
+// parameters are authenticated along with the secrets.
+package secretfile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Format identifies secret export files.
+const Format = "cyfr-secrets"
+
+const (
+	version = 1
+	kdf     = "pbkdf2-sha256"
+	keyLen  = 32
+	saltLen = 16
+)
+
+// Iterations is the PBKDF2 iteration count of new files.
+var Iterations = 600000
+
+// MinIterations is the fewest PBKDF2 iterations Open accepts: fewer make
+// guessing the passphrase cheap.
+var MinIterations = 600000
+
+// maxIterations is the most PBKDF2 iterations Open accepts, so a tampered
+// file can't keep it deriving a key for hours.
+const maxIterations = 10000000
+
+// ErrPassphrase is returned by Open when the passphrase is wrong or the
+// file was altered; AES-GCM can't tell the two apart.
+var ErrPassphrase = errors.New("wrong passphrase, or the file is corrupted")
+
+// A Secret is a secret's name and value.
+type Secret struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type envelope struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce,omitempty"`
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+}
+
+// additionalData returns what the ciphertext authenticates besides the
+// secrets: the envelope without the nonce and ciphertext.
+func (e envelope) additionalData() []byte {
+	e.Nonce, e.Ciphertext = nil, nil
+	b, _ := json.Marshal(e)
+	return b
+}
+
+// Seal encrypts secrets with passphrase into the contents of an export
+// file.
+func Seal(secrets []Secret, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	e := envelope{Format: Format, Version: version, KDF: kdf, Iterations: Iterations, Salt: make([]byte, saltLen)}
+	if _, err := rand.Read(e.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, e.Salt, e.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	e.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, err
+	}
+	if secrets == nil {
+		secrets = []Secret{}
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	e.Ciphertext = aead.Seal(nil, e.Nonce, plaintext, e.additionalData())
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// Open decrypts the contents of an export file with passphrase.
+func Open(data, passphrase []byte) ([]Secret, error) {
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil || e.Format != Format {
+		return nil, errors.New("not a cyfr secret export file")
+	}
+	if e.Version != version || e.KDF != kdf {
+		return nil, fmt.Errorf("unsupported secret export file: version %d, %s; upgrade cyfr", e.Version, e.KDF)
+	}
+	if e.Iterations < MinIterations || e.Iterations > maxIterations || len(e.Salt) == 0 {
+		return nil, errors.New("invalid secret export file: bad key derivation parameters")
+	}
+	aead, err := newAEAD(passphrase, e.Salt, e.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid secret export file: bad nonce")
+	}
+	plaintext, err := aead.Open(nil, e.Nonce, e.Ciphertext, e.additionalData())
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	var secrets []Secret
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid secret export file: %w", err)
+	}
+	return secrets, nil
+}
+
+func newAEAD(passphrase, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2SHA256(passphrase, salt, iterations, keyLen))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives a key of keyLen bytes from password and salt with
+// PBKDF2 (RFC 8018) using HMAC-SHA256.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for n := 1; n < iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
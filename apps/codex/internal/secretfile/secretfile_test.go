@@ -0,0 +1,77 @@
+package secretfile
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func init() {
+	// Keep the tests fast; the iteration count is in the file.
+	Iterations, MinIterations = 1000, 1000
+}
+
+func TestPBKDF2(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 test vectors for P = "password", S = "salt".
+	for _, tt := range []struct {
+		iterations int
+		want       string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	} {
+		if got := hex.EncodeToString(pbkdf2SHA256([]byte("password"), []byte("salt"), tt.iterations, 32)); got != tt.want {
+			t.Errorf("%d iterations: got %s, want %s", tt.iterations, got, tt.want)
+		}
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	secrets := []Secret{{Name: "OPENAI_API_KEY", Value: "sk-test"}, {Name: "TLS_KEY", Value: "line one\nline two"}}
+	data, err := Seal(secrets, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("sk-test")) || bytes.Contains(data, []byte("OPENAI_API_KEY")) {
+		t.Error("the file holds a secret in the clear")
+	}
+	got, err := Open(data, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != secrets[0] || got[1] != secrets[1] {
+		t.Errorf("Open = %+v", got)
+	}
+
+	if _, err := Open(data, []byte("wrong horse")); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("wrong passphrase: err = %v", err)
+	}
+
+	// Changing the iteration count breaks the authentication; counts out
+	// of bounds, to make guessing cheaper or Open hang, are refused before
+	// a key is derived.
+	var e map[string]any
+	json.Unmarshal(data, &e)
+	e["iterations"] = 2000
+	tampered, _ := json.Marshal(e)
+	if _, err := Open(tampered, []byte("correct horse")); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("tampered: err = %v", err)
+	}
+	for _, n := range []int{1, 999, maxIterations + 1, 1 << 40} {
+		e["iterations"] = n
+		tampered, _ := json.Marshal(e)
+		if _, err := Open(tampered, []byte("correct horse")); err == nil || errors.Is(err, ErrPassphrase) {
+			t.Errorf("%d iterations: err = %v", n, err)
+		}
+	}
+
+	if _, err := Open([]byte(`{"contexts": []}`), []byte("x")); err == nil {
+		t.Error("Open accepted another file")
+	}
+	if _, err := Seal(secrets, nil); err == nil {
+		t.Error("Seal accepted an empty passphrase")
+	}
+}
@@ -0,0 +1,326 @@
+// Package wasm reads what the CLI needs to know about WebAssembly
+// component-model binaries: whether a file is one, the names it imports
+// and exports, the WIT worlds it was built for, and what its sections take.
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// magic starts every WebAssembly binary, core module or component.
+var magic = []byte{0x00, 'a', 's', 'm'}
+
+// ErrCoreModule is returned for a core WebAssembly module, which has to be
+// wrapped in a component before the component model can run it.
+var ErrCoreModule = errors.New("a core WebAssembly module, not a component")
+
+// The component sections Parse reads; the others are only measured.
+const (
+	sectionCustom     = 0
+	sectionCoreModule = 1
+	sectionImport     = 10
+	sectionExport     = 11
+)
+
+// sectionKinds names the component section ids.
+var sectionKinds = []string{
+	"custom", "core module", "core instance", "core type", "component", "instance",
+	"alias", "type", "canon", "start", "import", "export", "value",
+}
+
+// A Component is what Parse reads from a component binary.
+type Component struct {
+	Imports []string // e.g. "cyfr:http/fetch@0.1.0", in binary order
+	Exports []string // e.g. "cyfr:catalyst/run@0.1.0", in binary order
+	// Worlds are the WIT worlds the component's core modules were built
+	// for, e.g. "cyfr:catalyst/catalyst@0.1.0", from the component-type
+	// custom sections the bindings generator embeds.
+	Worlds []string
+	// Sections are the top-level sections, in binary order.
+	Sections []Section
+	// CustomSections are the custom sections, top-level and in core
+	// modules, in binary order.
+	CustomSections []Section
+}
+
+// A Section is a section of a binary and its size in bytes, including its
+// id and size header.
+type Section struct {
+	Kind string `json:"kind"`           // e.g. "core module", or "custom"
+	Name string `json:"name,omitempty"` // the name of a custom section
+	Size int    `json:"size"`
+}
+
+// sectionKind returns the name of a component section id.
+func sectionKind(id byte) string {
+	if int(id) < len(sectionKinds) {
+		return sectionKinds[id]
+	}
+	return fmt.Sprintf("unknown (%d)", id)
+}
+
+// Parse reads the top-level imports and exports of the component binary
+// data. It returns ErrCoreModule for a core module and an error for
+// anything else that isn't a well-formed component.
+func Parse(data []byte) (*Component, error) {
+	if len(data) < 8 || !bytes.Equal(data[:4], magic) {
+		return nil, errors.New("not a WebAssembly binary")
+	}
+	layer := binary.LittleEndian.Uint16(data[6:8])
+	switch layer {
+	case 0:
+		return nil, ErrCoreModule
+	case 1:
+	default:
+		return nil, fmt.Errorf("unknown WebAssembly layer %d", layer)
+	}
+
+	c := &Component{}
+	r := &reader{data: data, pos: 8}
+	for !r.done() {
+		start := r.pos
+		id := r.byte()
+		size := r.u32()
+		if r.err != nil {
+			break
+		}
+		end := r.pos + int(size)
+		if end > len(r.data) || end < r.pos {
+			return nil, fmt.Errorf("section %d at offset %d runs past the end of the file", id, r.pos)
+		}
+		s := Section{Kind: sectionKind(id), Size: end - start}
+		section := &reader{data: r.data[:end], pos: r.pos}
+		switch id {
+		case sectionCustom:
+			s.Name = section.name()
+			c.addCustom(s)
+		case sectionCoreModule:
+			for _, custom := range section.coreCustomSections() {
+				c.addCustom(custom)
+			}
+		case sectionImport:
+			c.Imports = append(c.Imports, section.imports()...)
+		case sectionExport:
+			c.Exports = append(c.Exports, section.exports()...)
+		}
+		c.Sections = append(c.Sections, s)
+		if section.err != nil {
+			return nil, fmt.Errorf("section %d: %w", id, section.err)
+		}
+		r.pos = end
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return c, nil
+}
+
+// addCustom records a custom section, and the world it names if it is a
+// component-type section.
+func (c *Component) addCustom(s Section) {
+	c.CustomSections = append(c.CustomSections, s)
+	if world, ok := worldName(s.Name); ok {
+		for _, w := range c.Worlds {
+			if w == world {
+				return
+			}
+		}
+		c.Worlds = append(c.Worlds, world)
+	}
+}
+
+// worldName returns the WIT world a component-type custom section names,
+// such as "component-type:wit-bindgen:0.41.0:cyfr:catalyst@0.1.0:catalyst:encoded world",
+// as "cyfr:catalyst/catalyst@0.1.0".
+func worldName(section string) (string, bool) {
+	rest, ok := strings.CutPrefix(section, "component-type:")
+	if !ok {
+		return "", false
+	}
+	rest = strings.TrimSuffix(rest, ":encoded world")
+	if tool, after, ok := strings.Cut(rest, ":"); ok && tool == "wit-bindgen" {
+		// Skip the generator and its version.
+		if _, after, ok := strings.Cut(after, ":"); ok {
+			rest = after
+		}
+	}
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return rest, rest != ""
+	}
+	pkg, world := rest[:i], rest[i+1:]
+	version := ""
+	if at := strings.Index(pkg, "@"); at >= 0 {
+		pkg, version = pkg[:at], pkg[at:]
+	}
+	return pkg + "/" + world + version, true
+}
+
+// reader decodes the binary format, remembering the first error.
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) done() bool { return r.err != nil || r.pos >= len(r.data) }
+
+func (r *reader) fail(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("offset %d: "+format, append([]any{r.pos}, args...)...)
+	}
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.data) {
+		r.fail("unexpected end")
+		return 0
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+// leb reads an LEB128 number of at most maxBytes bytes, ignoring its sign:
+// only whether it is well-formed matters to Parse.
+func (r *reader) leb(maxBytes int) uint64 {
+	var n uint64
+	for i := 0; i < maxBytes; i++ {
+		b := r.byte()
+		n |= uint64(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return n
+		}
+	}
+	r.fail("LEB128 number too long")
+	return 0
+}
+
+func (r *reader) u32() uint32 { return uint32(r.leb(5)) }
+
+func (r *reader) name() string {
+	n := int(r.u32())
+	if r.err != nil {
+		return ""
+	}
+	if n > len(r.data)-r.pos {
+		r.fail("name runs past the end")
+		return ""
+	}
+	s := string(r.data[r.pos : r.pos+n])
+	r.pos += n
+	return s
+}
+
+// externName reads an importname' or exportname': a name, and for the
+// 0x01 form a version suffix after it, which isn't part of the name.
+func (r *reader) externName() string {
+	switch form := r.byte(); form {
+	case 0x00:
+		return r.name()
+	case 0x01:
+		name := r.name()
+		r.name()
+		return name
+	default:
+		r.fail("unknown name form 0x%02x", form)
+		return ""
+	}
+}
+
+// externDesc skips an externdesc.
+func (r *reader) externDesc() {
+	switch kind := r.byte(); kind {
+	case 0x00: // core module
+		if b := r.byte(); b != 0x11 {
+			r.fail("unknown core extern 0x%02x", b)
+		}
+		r.u32()
+	case 0x01, 0x04, 0x05: // func, component, instance
+		r.u32()
+	case 0x02: // value
+		switch b := r.byte(); b {
+		case 0x00:
+			r.u32()
+		case 0x01:
+			r.leb(5) // a valtype: a primitive or a type index, as an s33
+		default:
+			r.fail("unknown value bound 0x%02x", b)
+		}
+	case 0x03: // type
+		switch b := r.byte(); b {
+		case 0x00:
+			r.u32()
+		case 0x01:
+		default:
+			r.fail("unknown type bound 0x%02x", b)
+		}
+	default:
+		r.fail("unknown extern kind 0x%02x", kind)
+	}
+}
+
+// sortIdx skips a sortidx.
+func (r *reader) sortIdx() {
+	if r.byte() == 0x00 {
+		r.byte() // the core sort
+	}
+	r.u32()
+}
+
+func (r *reader) imports() []string {
+	n := r.u32()
+	var names []string
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		names = append(names, r.externName())
+		r.externDesc()
+	}
+	return names
+}
+
+func (r *reader) exports() []string {
+	n := r.u32()
+	var names []string
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		names = append(names, r.externName())
+		r.sortIdx()
+		if r.byte() == 0x01 {
+			r.externDesc()
+		}
+	}
+	return names
+}
+
+// coreCustomSections reads the custom sections of a core module, skipping
+// the rest of it. Core modules are otherwise left to the runtime to check,
+// so reading stops quietly at anything malformed.
+func (r *reader) coreCustomSections() []Section {
+	if len(r.data)-r.pos < 8 || !bytes.Equal(r.data[r.pos:r.pos+4], magic) {
+		return nil
+	}
+	r.pos += 8
+	var sections []Section
+	for !r.done() {
+		start := r.pos
+		id := r.byte()
+		size := r.u32()
+		end := r.pos + int(size)
+		if r.err != nil || end > len(r.data) || end < r.pos {
+			r.err = nil
+			return sections
+		}
+		if id == sectionCustom {
+			name := (&reader{data: r.data[:end], pos: r.pos}).name()
+			sections = append(sections, Section{Kind: "custom", Name: name, Size: end - start})
+		}
+		r.pos = end
+	}
+	return sections
+}
@@ -0,0 +1,120 @@
+package wasm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// section encodes a section with the given id and contents.
+func section(id byte, contents ...byte) []byte {
+	return append([]byte{id, byte(len(contents))}, contents...)
+}
+
+// name encodes a short name.
+func name(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+var componentHeader = []byte{0x00, 'a', 's', 'm', 0x0d, 0x00, 0x01, 0x00}
+
+func TestParse(t *testing.T) {
+	imports := concat([]byte{2},
+		[]byte{0x00}, name("cyfr:http/fetch@0.1.0"), []byte{0x05, 0x00}, // instance type 0
+		[]byte{0x01}, name("wasi:io/error"), name("@0.2.3"), []byte{0x03, 0x01}, // versioned, sub resource
+	)
+	exports := concat([]byte{1},
+		[]byte{0x00}, name("cyfr:catalyst/run@0.1.0"), []byte{0x05, 0x02}, // instance 2
+		[]byte{0x01, 0x05, 0x03}, // with an instance type
+	)
+	data := concat(componentHeader,
+		section(0, concat(name("custom"), []byte{1, 2, 3})...),
+		section(sectionImport, imports...),
+		section(1, 0x00, 0x61, 0x73, 0x6d), // a nested core module, skipped
+		section(sectionExport, exports...),
+	)
+
+	c, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cyfr:http/fetch@0.1.0", "wasi:io/error"}; !reflect.DeepEqual(c.Imports, want) {
+		t.Errorf("imports = %q, want %q", c.Imports, want)
+	}
+	if want := []string{"cyfr:catalyst/run@0.1.0"}; !reflect.DeepEqual(c.Exports, want) {
+		t.Errorf("exports = %q, want %q", c.Exports, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	core := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+	if _, err := Parse(core); !errors.Is(err, ErrCoreModule) {
+		t.Errorf("core module: %v", err)
+	}
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"not wasm":    []byte("#!/bin/sh\necho hi\n"),
+		"truncated":   concat(componentHeader, []byte{sectionExport, 10, 1}),
+		"bad export":  concat(componentHeader, section(sectionExport, 1, 0x07)),
+		"bad section": concat(componentHeader, section(sectionExport, 1, 0x00, 5, 'a')),
+	} {
+		if _, err := Parse(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParse_SectionsAndWorlds(t *testing.T) {
+	world := "component-type:wit-bindgen:0.41.0:cyfr:catalyst@0.1.0:catalyst:encoded world"
+	core := concat([]byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00},
+		section(1, 0x00), // a core type section
+		section(0, concat(name(world), []byte{9, 9})...),
+	)
+	data := concat(componentHeader,
+		section(0, name("producers")...),
+		section(sectionCoreModule, core...),
+		section(sectionExport, 0),
+	)
+
+	c, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cyfr:catalyst/catalyst@0.1.0"}; !reflect.DeepEqual(c.Worlds, want) {
+		t.Errorf("worlds = %q, want %q", c.Worlds, want)
+	}
+	wantSections := []Section{
+		{Kind: "custom", Name: "producers", Size: 12},
+		{Kind: "core module", Size: 2 + len(core)},
+		{Kind: "export", Size: 3},
+	}
+	if !reflect.DeepEqual(c.Sections, wantSections) {
+		t.Errorf("sections = %+v, want %+v", c.Sections, wantSections)
+	}
+	if len(c.CustomSections) != 2 || c.CustomSections[1].Name != world {
+		t.Errorf("custom sections = %+v", c.CustomSections)
+	}
+}
+
+func TestWorldName(t *testing.T) {
+	for section, want := range map[string]string{
+		"component-type:wit-bindgen:0.41.0:cyfr:catalyst@0.1.0:catalyst:encoded world": "cyfr:catalyst/catalyst@0.1.0",
+		"component-type:cyfr:reagent:reagent":                                          "cyfr:reagent/reagent",
+		"component-type:catalyst":                                                      "catalyst",
+	} {
+		if got, ok := worldName(section); !ok || got != want {
+			t.Errorf("worldName(%q) = %q, %v; want %q", section, got, ok, want)
+		}
+	}
+	if _, ok := worldName("producers"); ok {
+		t.Error("producers named a world")
+	}
+}
@@ -1,10 +1,6 @@
 package main
 
-import (
-	"os"
-
-	"github.com/cyfr/codex/cmd"
-)
+import "github.com/cyfr/codex/cmd"
 
 // Build-time variables set via ldflags.
 var (
@@ -18,7 +14,5 @@ func main() {
 	cmd.Commit = commit
 	cmd.Date = date
 
-	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	cmd.Execute()
 }